@@ -0,0 +1,115 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// CheckSpec 定义一次独立的验证：对已有资源执行期望检查。
+// 适用于部署后验证，无需编写多步骤 IntegrationTest。
+type CheckSpec struct {
+	// Target 被检查的资源（只读选择器）。
+	// 匹配多个资源时，每个资源都必须满足条件。
+	Target ResourceSelector `json:"target"`
+	// Condition 检查条件：在超时时间内持续检查，直到通过或超时。
+	Condition ReadyCondition `json:"condition"`
+	// IntervalSeconds 周期检查间隔（秒）。
+	// 为 0 时只检查一次；大于 0 时每轮结束后按间隔重新检查。
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	IntervalSeconds int32 `json:"intervalSeconds,omitempty"`
+	// Exporters 结果导出器，与控制器级导出器一起接收本 Check 的事件。
+	// +optional
+	Exporters []ExporterSpec `json:"exporters,omitempty"`
+}
+
+// CheckPhase 定义 Check 的阶段。
+// +kubebuilder:validation:Enum=Running;Passed;Failed
+type CheckPhase string
+
+const (
+	CheckPhaseRunning CheckPhase = "Running"
+	CheckPhasePassed  CheckPhase = "Passed"
+	CheckPhaseFailed  CheckPhase = "Failed"
+)
+
+// CheckStatus 定义 Check 的观测状态。
+type CheckStatus struct {
+	// Phase 当前阶段（周期模式下为最近一轮的结果）。
+	Phase CheckPhase `json:"phase,omitempty"`
+	// Reason 阶段原因。
+	Reason string `json:"reason,omitempty"`
+	// Message 详细消息。
+	Message string `json:"message,omitempty"`
+	// StartedAt 当前轮次开始时间。
+	StartedAt *metav1.Time `json:"startedAt,omitempty"`
+	// Deadline 当前轮次截止时间。
+	Deadline *metav1.Time `json:"deadline,omitempty"`
+	// LastCheckTime 最近一次检查时间。
+	LastCheckTime *metav1.Time `json:"lastCheckTime,omitempty"`
+	// LastCompletionTime 最近一轮完成时间。
+	LastCompletionTime *metav1.Time `json:"lastCompletionTime,omitempty"`
+	// RunCount 已完成轮次。
+	RunCount int32 `json:"runCount,omitempty"`
+	// PassCount 通过轮次。
+	PassCount int32 `json:"passCount,omitempty"`
+	// FailCount 失败轮次。
+	FailCount int32 `json:"failCount,omitempty"`
+	// MatchedResources 当前匹配到的资源（apiVersion/kind/name）。
+	MatchedResources []string `json:"matchedResources,omitempty"`
+	// Results 最近一次检查的期望结果。
+	Results []ExpectationResult `json:"results,omitempty"`
+	// SelectorDiagnostics 选择器未匹配到资源时的诊断信息。
+	SelectorDiagnostics *SelectorDiagnostics `json:"selectorDiagnostics,omitempty"`
+	// ObservedGeneration 已观察的 Generation。
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// Conditions 条件列表。
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
+// +kubebuilder:printcolumn:name="Runs",type=integer,JSONPath=`.status.runCount`,priority=1
+// +kubebuilder:printcolumn:name="Pass",type=integer,JSONPath=`.status.passCount`,priority=1
+// +kubebuilder:printcolumn:name="Fail",type=integer,JSONPath=`.status.failCount`,priority=1
+// +kubebuilder:printcolumn:name="Reason",type=string,JSONPath=`.status.reason`,priority=1
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+// +kubebuilder:resource:shortName=chk
+
+// Check 表示一次独立的资源验证。
+type Check struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   CheckSpec   `json:"spec,omitempty"`
+	Status CheckStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// CheckList 包含多个 Check。
+type CheckList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Check `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&Check{}, &CheckList{})
+}
@@ -0,0 +1,25 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+// v1alpha1 是 IntegrationTest 与 LoadTest 的存储版本和转换 Hub，其他版本与其互相转换。
+
+// Hub 标记 IntegrationTest 为转换 Hub。
+func (*IntegrationTest) Hub() {}
+
+// Hub 标记 LoadTest 为转换 Hub。
+func (*LoadTest) Hub() {}
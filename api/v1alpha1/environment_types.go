@@ -0,0 +1,83 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// EnvironmentSpec 定义被测环境的共享连接信息，由 IntegrationTest/LoadTest 通过 spec.environmentRef 引用。
+// 变量与 BaseURLs 在调和时以 ${env.<name>}、${env.baseURLs.<name>}、${env.namespace} 展开到测试 spec 中。
+type EnvironmentSpec struct {
+	// KubeconfigSecretRef 被测集群 kubeconfig 所在的 Secret（与 Environment 同命名空间）。
+	// 控制器本身不使用该凭证，只在 Webhook 请求中传递引用，由外部检查服务自行读取。
+	// +optional
+	KubeconfigSecretRef *SecretKeyReference `json:"kubeconfigSecretRef,omitempty"`
+	// BaseURLs 服务地址（如 prometheus、api），以 ${env.baseURLs.<name>} 引用。
+	// 名为 prometheus 的地址作为未设置 url 的 Prometheus 查询的默认地址。
+	// +optional
+	BaseURLs map[string]string `json:"baseURLs,omitempty"`
+	// DefaultNamespace 选择器未指定命名空间时使用的命名空间，默认为测试所在命名空间。
+	// 测试创建的资源仍位于测试所在命名空间。
+	// +optional
+	DefaultNamespace string `json:"defaultNamespace,omitempty"`
+	// Variables 环境变量，以 ${env.<name>} 引用。
+	// +optional
+	Variables map[string]string `json:"variables,omitempty"`
+}
+
+// SecretKeyReference 引用同命名空间 Secret 中的一个键。
+type SecretKeyReference struct {
+	// Name Secret 名称。
+	Name string `json:"name"`
+	// Key 键名，默认 kubeconfig。
+	// +kubebuilder:default=kubeconfig
+	// +optional
+	Key string `json:"key,omitempty"`
+}
+
+// EnvironmentReference 引用同命名空间的 Environment。
+type EnvironmentReference struct {
+	// Name Environment 名称。
+	Name string `json:"name"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:printcolumn:name="Namespace",type=string,JSONPath=`.spec.defaultNamespace`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+// +kubebuilder:resource:shortName=env
+
+// Environment 表示测试共享的被测环境上下文。
+type Environment struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec EnvironmentSpec `json:"spec,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// EnvironmentList 包含多个 Environment。
+type EnvironmentList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Environment `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&Environment{}, &EnvironmentList{})
+}
@@ -17,14 +17,34 @@ limitations under the License.
 package v1alpha1
 
 import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 )
 
+// AnnotationInjectExpectation 故障注入注解（调试用），设置在 IntegrationTest/LoadTest 上。
+// 按期望函数名强制结果，用于在不破坏真实目标的情况下验证告警、阈值、事件等失败路径。
+// 格式：<function>=<action>[,...]，action 取值：
+//   - fail：强制失败
+//   - pass：强制通过
+//   - delay:<duration>：延迟后正常执行（上限 30s），如 delay:5s
+const AnnotationInjectExpectation = "infra.testplane.io/inject-expectation"
+
+// AnnotationRecord 录制注解（调试用），值为 "true" 时录制每次期望检查的状态快照。
+// 需要控制器通过 --record-dir 配置归档目录，之后可用 `testplane replay` 本地重放。
+const AnnotationRecord = "infra.testplane.io/record"
+
 // Expectation 定义一个业务期望。
 // 支持两种模式：
 // 1. 内置函数：Function + Params（可选）
 // 2. Webhook：Function + Webhook + Params（可选）
 type Expectation struct {
+	// Name 期望的可读名称（可选），如 "primary VIP reachable"。
+	// 设置后结果、事件与报告中使用该名称代替函数名标识期望。
+	// +optional
+	Name string `json:"name,omitempty"`
+	// Description 期望的说明（可选），随结果记录，便于排查失败时理解断言意图。
+	// +optional
+	Description string `json:"description,omitempty"`
 	// Function 函数名（必填）。
 	// - 无 Webhook 时：调用内置函数
 	// - 有 Webhook 时：传给 Webhook 表示执行哪个检查
@@ -34,9 +54,90 @@ type Expectation struct {
 	Webhook string `json:"webhook,omitempty"`
 	// Params 函数参数（可选）。
 	Params runtime.RawExtension `json:"params,omitempty"`
+	// Resource 断言的目标资源（可选）：步骤别名（TestStep.As）或 apiVersion/kind/name 状态键。
+	// 为空时使用当前步骤（或 LoadTest Target）的资源。
+	// +optional
+	Resource string `json:"resource,omitempty"`
+	// RelatedResources 关联资源（可选）。
+	// 状态收集时自动获取并合并到被断言资源的 _related.<name> 下，便于跨资源断言。
+	// +optional
+	RelatedResources []RelatedResource `json:"relatedResources,omitempty"`
+	// Subresource 断言资源的子资源视图（可选），目前支持 scale：
+	// 函数接收 autoscaling/v1 Scale（spec.replicas、status.replicas、status.selector），
+	// 可对任何可伸缩资源（含 CRD）断言副本数而无需了解其 schema。
+	// +kubebuilder:validation:Enum=scale
+	// +optional
+	Subresource string `json:"subresource,omitempty"`
+	// Fields 字段投影（可选），如 ["status", "metadata.labels"]。
+	// 设置后函数只接收这些字段（以及 apiVersion、kind、metadata.name/namespace），
+	// 用于大对象减少传递与录制的数据量。
+	// +optional
+	Fields []string `json:"fields,omitempty"`
+	// OnError 函数执行出错（区别于断言未通过）时的处理方式（可选，默认 Retry）。
+	// - Retry：暂时性错误（Webhook 网络错误、5xx、429）记为错误结果，本次检查不通过，下次检查重试
+	// - Fail：任何错误都立即中止检查并判定失败
+	// 未知函数、参数无效等永久性错误始终立即失败。
+	// +kubebuilder:validation:Enum=Retry;Fail
+	// +optional
+	OnError ExpectationErrorPolicy `json:"onError,omitempty"`
+	// HoldSeconds 条件需持续成立的秒数（可选）：跨多次检查连续通过满 N 秒才记为通过，
+	// 避免目标短暂就绪一次随即崩溃被误判为完成。用于 IntegrationTest 步骤与 LoadTest readyCondition 的完成判定。
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	HoldSeconds int32 `json:"holdSeconds,omitempty"`
+	// Severity 未通过时的影响（可选，默认 Blocker）。
+	// Warning 用于提示性检查（如仍在使用已弃用的 API）：未通过时结果标记 severity，设置 ExpectationWarnings Condition
+	// 并计入 testplane_expectation_warnings_total 指标，但不会使步骤失败；anyOf 中只有 Blocker 参与判定。
+	// +kubebuilder:validation:Enum=Blocker;Warning
+	// +optional
+	Severity ExpectationSeverity `json:"severity,omitempty"`
+	// Tombstone 断言 Delete 步骤资源删除前的最后状态（墓碑快照），而非删除后的空对象（可选，仅 IntegrationTest）。
+	// resource 为空时使用当前步骤的资源；快照保存在控制器内存中，控制器重启后丢失。
+	// +optional
+	Tombstone bool `json:"tombstone,omitempty"`
+}
+
+// ExpectationErrorPolicy 期望函数执行出错时的处理方式。
+type ExpectationErrorPolicy string
+
+const (
+	// ExpectationErrorRetry 暂时性错误视为可重试（默认）。
+	ExpectationErrorRetry ExpectationErrorPolicy = "Retry"
+	// ExpectationErrorFail 任何错误都立即失败。
+	ExpectationErrorFail ExpectationErrorPolicy = "Fail"
+)
+
+// ExpectationSeverity 期望未通过时的影响。
+type ExpectationSeverity string
+
+const (
+	// ExpectationSeverityBlocker 未通过时步骤/检查不通过（默认）。
+	ExpectationSeverityBlocker ExpectationSeverity = "Blocker"
+	// ExpectationSeverityWarning 仅提示：未通过时记录到结果、Condition 与指标，不影响步骤/检查结论。
+	ExpectationSeverityWarning ExpectationSeverity = "Warning"
+)
+
+// RelatedResource 声明期望需要的关联资源（如 Service 的 Endpoints、Deployment 的 Pods）。
+// ResourceName、LabelSelector、LabelSelectorFrom 均为空时，按主资源的名称获取同名资源。
+type RelatedResource struct {
+	// Name 关联资源在 _related 下的子键名。
+	Name string `json:"name"`
+	// APIVersion 关联资源的 API 版本。
+	APIVersion string `json:"apiVersion"`
+	// Kind 关联资源的类型。
+	Kind string `json:"kind"`
+	// ResourceName 按名称获取关联资源。
+	// +optional
+	ResourceName string `json:"resourceName,omitempty"`
+	// LabelSelector 按标签选择关联资源。
+	// +optional
+	LabelSelector map[string]string `json:"labelSelector,omitempty"`
+	// LabelSelectorFrom 从主资源读取标签选择器的字段路径（如 spec.selector.matchLabels）。
+	// +optional
+	LabelSelectorFrom string `json:"labelSelectorFrom,omitempty"`
 }
 
-// Extractor 定义值提取器（用于 EnvInjection）。
+// Extractor 定义值提取器（用于 EnvInjection 与步骤输出）。
 type Extractor struct {
 	// Function 提取函数名。
 	Function string `json:"function"`
@@ -46,6 +147,12 @@ type Extractor struct {
 
 // ExpectationResult 记录单个期望的执行结果。
 type ExpectationResult struct {
+	// Name 期望的可读名称（来自 Expectation.Name）。
+	// +optional
+	Name string `json:"name,omitempty"`
+	// Description 期望的说明（来自 Expectation.Description）。
+	// +optional
+	Description string `json:"description,omitempty"`
 	// Expect 期望函数名称。
 	Expect string `json:"expect"`
 	// Params 期望函数的参数。
@@ -56,11 +163,24 @@ type ExpectationResult struct {
 	Actual string `json:"actual,omitempty"`
 	// Message 结果消息。
 	Message string `json:"message,omitempty"`
+	// Error 是否为执行错误（而非断言未通过），错误结果的 Passed 为 false。
+	Error bool `json:"error,omitempty"`
+	// Severity 期望的严重级别（来自 Expectation.Severity），Warning 结果未通过不影响结论。
+	// +optional
+	Severity ExpectationSeverity `json:"severity,omitempty"`
+	// RetryAfterSeconds 未通过时期望函数建议的下次检查间隔（秒），如平台给出的预计剩余时间。
+	// +optional
+	RetryAfterSeconds int32 `json:"retryAfterSeconds,omitempty"`
+	// HeldSince 设置 holdSeconds 时，条件最近一次开始连续成立的时间。
+	// +optional
+	HeldSince *metav1.Time `json:"heldSince,omitempty"`
 }
 
 // ExpectationResultSummary 期望结果摘要（不含完整参数，用于状态存储优化）。
 // 用于在状态中存储历史检查结果，减少状态大小。
 type ExpectationResultSummary struct {
+	// Name 期望的可读名称。
+	Name string `json:"name,omitempty"`
 	// Expect 期望函数名称。
 	Expect string `json:"expect"`
 	// Passed 是否通过。
@@ -69,4 +189,10 @@ type ExpectationResultSummary struct {
 	Actual string `json:"actual,omitempty"`
 	// Message 结果消息（截断至 256 字符）。
 	Message string `json:"message,omitempty"`
+	// Error 是否为执行错误（而非断言未通过）。
+	Error bool `json:"error,omitempty"`
+	// Severity 期望的严重级别，Warning 结果未通过不影响结论。
+	Severity ExpectationSeverity `json:"severity,omitempty"`
+	// HeldSince 设置 holdSeconds 时，条件最近一次开始连续成立的时间。
+	HeldSince *metav1.Time `json:"heldSince,omitempty"`
 }
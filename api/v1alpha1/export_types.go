@@ -0,0 +1,79 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+// ExporterType 结果导出器类型。
+// +kubebuilder:validation:Enum=HTTP;S3;Kafka
+type ExporterType string
+
+const (
+	// ExporterTypeHTTP 以 POST 发送 JSON 到 URL。
+	ExporterTypeHTTP ExporterType = "HTTP"
+	// ExporterTypeS3 每个事件写入一个 S3 对象（兼容 S3 协议的存储均可）。
+	ExporterTypeS3 ExporterType = "S3"
+	// ExporterTypeKafka 通过 Kafka REST Proxy 写入 Topic。
+	ExporterTypeKafka ExporterType = "Kafka"
+)
+
+// ExportEventType 导出的事件类型。
+// +kubebuilder:validation:Enum=PhaseChanged;CheckResult
+type ExportEventType string
+
+const (
+	// ExportEventPhaseChanged 测试阶段转换。
+	ExportEventPhaseChanged ExportEventType = "PhaseChanged"
+	// ExportEventCheckResult Check 的一轮检查结果。
+	ExportEventCheckResult ExportEventType = "CheckResult"
+)
+
+// ExporterSpec 结果导出器配置：阶段转换与检查结果推送到外部系统。
+type ExporterSpec struct {
+	// Type 导出器类型。
+	Type ExporterType `json:"type"`
+	// URL 目标地址：HTTP 为接收端点；S3 为服务端点（默认 https://s3.<region>.amazonaws.com）；
+	// Kafka 为 REST Proxy 地址。
+	// +optional
+	URL string `json:"url,omitempty"`
+	// Headers 附加的请求头（HTTP、Kafka）。
+	// +optional
+	Headers map[string]string `json:"headers,omitempty"`
+	// Bucket S3 存储桶。
+	// +optional
+	Bucket string `json:"bucket,omitempty"`
+	// Region S3 区域，默认 us-east-1。
+	// +optional
+	Region string `json:"region,omitempty"`
+	// Prefix S3 对象键前缀。
+	// +optional
+	Prefix string `json:"prefix,omitempty"`
+	// Topic Kafka Topic。
+	// +optional
+	Topic string `json:"topic,omitempty"`
+	// CredentialsSecretRef 凭证所在的 Secret（同命名空间）：
+	// S3 使用 accessKeyID/secretAccessKey 键，HTTP 与 Kafka 使用 token 键（作为 Bearer Token）。
+	// +optional
+	CredentialsSecretRef *LocalSecretReference `json:"credentialsSecretRef,omitempty"`
+	// Events 只导出这些事件类型，为空时导出全部。
+	// +optional
+	Events []ExportEventType `json:"events,omitempty"`
+}
+
+// LocalSecretReference 引用同命名空间的 Secret。
+type LocalSecretReference struct {
+	// Name Secret 名称。
+	Name string `json:"name"`
+}
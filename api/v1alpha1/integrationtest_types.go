@@ -17,6 +17,7 @@ limitations under the License.
 package v1alpha1
 
 import (
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -31,12 +32,26 @@ const (
 	IntegrationTestModeParallel IntegrationTestMode = "Parallel"
 )
 
+// CleanupPolicy 定义测试执行期间自动删除步骤资源的时机。
+// +kubebuilder:validation:Enum=Keep;DeleteOnSuccess;DeleteAlways
+type CleanupPolicy string
+
+const (
+	// CleanupPolicyKeep 保留资源，只在测试删除时清理（默认）。
+	CleanupPolicyKeep CleanupPolicy = "Keep"
+	// CleanupPolicyDeleteOnSuccess 成功时删除资源，失败时保留现场便于调试。
+	CleanupPolicyDeleteOnSuccess CleanupPolicy = "DeleteOnSuccess"
+	// CleanupPolicyDeleteAlways 结束后（成功、失败或中止）总是删除资源。
+	CleanupPolicyDeleteAlways CleanupPolicy = "DeleteAlways"
+)
+
 // RepeatConfig 重复执行配置。
 // 停止条件（满足任意一个即停止）：
 // - 达到 Count 轮数（Count > 0 时生效）
 // - 达到 MaxDurationSeconds 时间（MaxDurationSeconds > 0 时生效）
 // - UntilFailure=true 且发生任何失败（断言失败、资源操作失败、超时等）
 // - 如果以上都未设置/未触发，则永远执行直到 IntegrationTest 被删除
+// 设置 Schedule 时新一轮只在时间窗口内开始，窗口外测试保持 Running（reason 为 WaitingForWindow）。
 type RepeatConfig struct {
 	// Count 重复轮数，0 表示不限轮数。
 	Count int `json:"count,omitempty"`
@@ -49,6 +64,80 @@ type RepeatConfig struct {
 
 	// DelayBetweenRounds 每轮之间的延迟（秒）。
 	DelayBetweenRounds int `json:"delayBetweenRounds,omitempty"`
+
+	// Schedule 轮次时间窗口，不设置则随时开始新一轮。
+	// +optional
+	Schedule *RepeatSchedule `json:"schedule,omitempty"`
+
+	// Gate 轮次闸门，每轮开始前查询外部信号，未放行时等待；不设置则不等待。
+	// +optional
+	Gate *RoundGate `json:"gate,omitempty"`
+
+	// RatePerMinute 每分钟开始的轮数：第 n 个时间槽为 startTime + n × (60s / ratePerMinute)，
+	// 轮次按槽开始而与轮次耗时无关，用作控制面压测的稳定变更源。设置后 DelayBetweenRounds 不生效。
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=600
+	// +optional
+	RatePerMinute int32 `json:"ratePerMinute,omitempty"`
+
+	// OverrunPolicy 上一轮超过时间槽时的处理：Skip（默认）跳过已错过的槽，等待下一个槽；
+	// Queue 立即开始新一轮，直到追上计划。
+	// +optional
+	OverrunPolicy RoundOverrunPolicy `json:"overrunPolicy,omitempty"`
+}
+
+// RoundOverrunPolicy 轮次超过 ratePerMinute 时间槽时的处理方式。
+// +kubebuilder:validation:Enum=Skip;Queue
+type RoundOverrunPolicy string
+
+const (
+	// RoundOverrunSkip 跳过已错过的时间槽（记入 status.skippedRounds）。
+	RoundOverrunSkip RoundOverrunPolicy = "Skip"
+	// RoundOverrunQueue 错过的时间槽排队，新一轮立即开始。
+	RoundOverrunQueue RoundOverrunPolicy = "Queue"
+)
+
+// RoundGate 轮次闸门：外部编排系统（如数据管道有新输入时）通过 Webhook 或 ConfigMap 键控制新一轮何时开始，无需修改测试。
+// Webhook 与 ConfigMapKey 二选一；已开始的轮次不受影响。
+type RoundGate struct {
+	// Webhook GET 该地址返回 HTTP 200 时放行，其他状态码或请求失败时等待。
+	// +optional
+	Webhook string `json:"webhook,omitempty"`
+
+	// ConfigMapKey 同命名空间 ConfigMap 中的键，值为 "true" 时放行；ConfigMap 或键不存在时等待。
+	// +optional
+	ConfigMapKey *ConfigMapKeyReference `json:"configMapKey,omitempty"`
+
+	// PollSeconds 未放行时重新查询的间隔（秒），默认 30。
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	PollSeconds int32 `json:"pollSeconds,omitempty"`
+}
+
+// ConfigMapKeyReference 引用同命名空间 ConfigMap 中的键。
+type ConfigMapKeyReference struct {
+	// Name ConfigMap 名称。
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+	// Key 键名。
+	// +kubebuilder:validation:MinLength=1
+	Key string `json:"key"`
+}
+
+// RepeatSchedule 轮次时间窗口：窗口在 Cron 触发时打开，持续 WindowSeconds。
+// 已开始的轮次在窗口关闭后继续执行直到结束。
+type RepeatSchedule struct {
+	// Cron 窗口开始时间，标准 5 字段 cron 表达式（如 "0 1 * * *" 表示每天 01:00）。
+	// +kubebuilder:validation:MinLength=1
+	Cron string `json:"cron"`
+
+	// WindowSeconds 窗口时长（秒）。
+	// +kubebuilder:validation:Minimum=1
+	WindowSeconds int32 `json:"windowSeconds"`
+
+	// TimeZone 解析 Cron 使用的 IANA 时区（如 Asia/Shanghai），默认 UTC。
+	// +optional
+	TimeZone string `json:"timeZone,omitempty"`
 }
 
 // StepCondition 步骤条件（用于 readyCondition 和 expectations）。
@@ -73,6 +162,33 @@ type TestStep struct {
 	// Resource 步骤资源（单资源）。
 	// +optional
 	Resource *ResourceRef `json:"resource,omitempty"`
+	// PreStep 步骤资源 apply 之前依次执行（apply 或 delete）的准备清单，如步骤依赖的 Secret、测试数据，
+	// 不污染主步骤列表。只支持 manifest，不等待收敛；失败时步骤按 apply 失败处理，postStep 仍然执行。
+	// +optional
+	PreStep []ResourceRef `json:"preStep,omitempty"`
+	// PostStep 步骤结束后（成功、失败或中止）依次执行（apply 或 delete）的清理清单，类似 Argo Workflows 的 exit handler，
+	// 保证在步骤失败时也会执行；断点暂停期间推迟到恢复或测试结束。执行为尽力而为，
+	// 结果记录在 status.steps[].postStep 中，不改变步骤与测试的结果。
+	// +optional
+	PostStep []ResourceRef `json:"postStep,omitempty"`
+	// CleanupPolicy 步骤结束后删除该步骤 apply 的资源（preStep、步骤资源与 postStep）的时机：
+	// DeleteOnSuccess 在步骤成功后删除，DeleteAlways 在步骤结束后删除，Keep 保留到测试删除。
+	// 删除按依赖顺序进行（同测试删除时的清理），完成后才推进下一步骤或下一轮；周期步骤在测试结束时按步骤结果删除。
+	// 设置后覆盖 spec.cleanupPolicy；未设置时按测试级策略处理。
+	// +optional
+	CleanupPolicy CleanupPolicy `json:"cleanupPolicy,omitempty"`
+	// As 步骤资源的别名，期望通过 resource: <alias> 引用该资源（包括后续步骤的期望）。
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	// +optional
+	As string `json:"as,omitempty"`
+	// Outputs 步骤期望满足后用提取函数（同 LoadTest 的 envInjection，如 FieldPath）从步骤资源提取的输出，
+	// 与内置输出（kind、name、namespace）一起记录在 status.steps[].outputs 中，
+	// 后续步骤的清单（resource、preStep、postStep）与期望参数以 ${steps.<name>.outputs.<key>} 引用。
+	// 提取结果为空或提取失败时步骤继续等待，超时后失败。
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	Outputs []StepOutput `json:"outputs,omitempty"`
 	// ReadyCondition 创建/更新资源后的就绪条件（步骤级）。
 	// +optional
 	ReadyCondition *StepCondition `json:"readyCondition,omitempty"`
@@ -81,18 +197,216 @@ type TestStep struct {
 	Expectations *StepCondition `json:"expectations,omitempty"`
 	// TimeoutSeconds 步骤超时时间（秒），控制整个步骤的超时。
 	TimeoutSeconds int32 `json:"timeoutSeconds,omitempty"`
+	// ConfirmFailureSeconds 超时判定失败前的复核间隔（秒）。步骤超时时 expectations 未满足先记录一次观察，
+	// 间隔该时长后再检查一次：仍未满足才判定失败，满足则步骤成功，两次观察记录在 status.steps[].failureChecks 中。
+	// 用于避免目标状态恰好在截止时刻短暂抖动导致误判；为 0（默认）时超时立即失败。
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	ConfirmFailureSeconds int32 `json:"confirmFailureSeconds,omitempty"`
+	// Retries 步骤失败后重新执行的次数。apply 持续冲突、期望检查出错或超时等暂时性失败时，
+	// 按退避等待后重新执行整个步骤（apply、就绪条件与期望检查），每次重试重新计算步骤超时；
+	// 权限拒绝（Forbidden）、清单无效（Invalid）、Webhook 拒绝、资源类型已移除与预算超出等确定性失败不重试。
+	// 每次尝试的结果记录在 status.steps[].attempts 中。周期步骤的迭代失败不重试。
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	Retries int32 `json:"retries,omitempty"`
+	// RetryBackoffSeconds 第一次重试前的等待时间（秒，默认 10），之后每次重试翻倍，最长 5 分钟。
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	RetryBackoffSeconds int32 `json:"retryBackoffSeconds,omitempty"`
+	// PeriodicSeconds 周期执行间隔（秒）。
+	// 步骤首次成功后，在本轮其余步骤执行期间每隔该间隔重新执行一次（重新应用资源并检查期望），
+	// 如定期轮换凭据；任一迭代失败则测试失败。每次迭代的超时沿用 TimeoutSeconds。
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	PeriodicSeconds int32 `json:"periodicSeconds,omitempty"`
+	// Barrier 并行屏障（仅 Parallel 模式生效）。
+	// 屏障步骤在之前声明的所有步骤成功后才开始，之后声明的步骤在屏障步骤成功后才开始。
+	// +optional
+	Barrier bool `json:"barrier,omitempty"`
+	// KeepCompleted 步骤资源创建的已完成子对象（如 Job 的 Pod、CronJob 的 Job）的保留数量。
+	// 设置后控制器在轮次之间删除超出数量的旧对象，避免长时间重复执行时命名空间膨胀。
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	KeepCompleted *int32 `json:"keepCompleted,omitempty"`
+	// Race 并发竞争模式（仅 Apply 清单有效）：以不同的字段管理者并发 SSA apply 同一对象 N 次，
+	// 验证冲突处理（如自研 Operator 的 SSA 行为），每次尝试的结果记录在 status.steps[].raceAttempts 中。
+	// +optional
+	Race *RaceSpec `json:"race,omitempty"`
+	// Canary 金丝雀比较：从基线与金丝雀两个选择器匹配的资源中提取相同指标，断言偏差在范围内，
+	// 用于验证平台 Operator 自身的金丝雀发布。各指标的比较结果与 expectations 一起判定，未满足时重试直到步骤超时。
+	// +optional
+	Canary *CanarySpec `json:"canary,omitempty"`
+}
+
+// CanarySpec 金丝雀比较配置。两个选择器只读取，各自按名称排序后取第一个匹配的资源。
+type CanarySpec struct {
+	// Baseline 基线资源选择器。
+	Baseline ResourceSelector `json:"baseline"`
+	// Canary 金丝雀资源选择器。
+	Canary ResourceSelector `json:"canary"`
+	// Metrics 比较的指标。
+	// +kubebuilder:validation:MinItems=1
+	Metrics []CanaryMetric `json:"metrics"`
+}
+
+// CanaryMetric 金丝雀比较的单个指标。
+type CanaryMetric struct {
+	// Name 指标名称（用于结果展示）。
+	Name string `json:"name"`
+	// Extract 从基线与金丝雀资源提取数值的提取器（如 FieldPath）。
+	Extract Extractor `json:"extract"`
+	// MaxDeviationPercent 金丝雀相对基线允许的最大偏差百分比（默认 10）；基线为 0 时要求金丝雀也为 0。
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MaxDeviationPercent *int32 `json:"maxDeviationPercent,omitempty"`
+}
+
+// RaceSpec 步骤并发竞争配置。
+// 清单中的 ${RACE_ATTEMPT} 在每次尝试中替换为尝试序号（从 1 开始），使各尝试写入不同的字段值以产生冲突；
+// 替换结果为字符串，对象的 apiVersion/kind/name 不应引用该占位符。
+type RaceSpec struct {
+	// Attempts 并发 apply 的次数，第 i 次使用字段管理者 integrationtest-controller-race-<i>。
+	// +kubebuilder:validation:Minimum=2
+	// +kubebuilder:validation:Maximum=20
+	Attempts int32 `json:"attempts"`
+	// Force 是否强制获取冲突字段的所有权。默认 false：冲突的 apply 被 API Server 拒绝并记录为 Conflict。
+	// +optional
+	Force bool `json:"force,omitempty"`
+}
+
+// TestVariable 测试变量，在期望参数中以 ${vars.<name>} 引用。
+type TestVariable struct {
+	// Name 变量名称。
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+	// Value 变量值。
+	Value string `json:"value,omitempty"`
+	// ValueFrom 从其他测试的状态中取值，设置时忽略 value。
+	// 取值在执行期望时解析，引用的测试尚未产生该值时期望按可重试错误处理，直到步骤超时。
+	// +optional
+	ValueFrom *VariableSource `json:"valueFrom,omitempty"`
+}
+
+// VariableSource 测试变量的取值来源。
+type VariableSource struct {
+	// TestRef 引用同命名空间测试的状态字段。
+	TestRef *TestFieldReference `json:"testRef"`
+}
+
+// TestFieldReference 引用测试对象中的字段。
+type TestFieldReference struct {
+	// Kind 测试类型：IntegrationTest 或 LoadTest。
+	// +kubebuilder:validation:Enum=IntegrationTest;LoadTest
+	// +kubebuilder:default=IntegrationTest
+	// +optional
+	Kind string `json:"kind,omitempty"`
+	// Name 测试名称（同命名空间）。
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+	// JSONPath 字段路径（kubectl JSONPath 语法），如 {.status.steps[0].outputs.name}，
+	// 花括号可省略。结果为多个值时以空格连接。
+	// +kubebuilder:validation:MinLength=1
+	JSONPath string `json:"jsonPath"`
 }
 
 // IntegrationTestSpec 定义测试用例的规格。
 type IntegrationTestSpec struct {
 	// Mode 测试执行模式：Sequential（顺序）或 Parallel（并行）。
 	// - Sequential：按 steps 顺序依次执行
-	// - Parallel：所有 steps 并行执行，全部完成后验证期望
+	// - Parallel：所有 steps 并行执行，全部完成后验证期望；barrier 步骤将步骤划分为依次执行的批次
 	Mode IntegrationTestMode `json:"mode,omitempty"`
 	// Steps 测试步骤列表。
 	Steps []TestStep `json:"steps,omitempty"`
 	// Repeat 重复执行配置，不设置则只执行一轮。
 	Repeat *RepeatConfig `json:"repeat,omitempty"`
+	// DependsOn 依赖的 IntegrationTest 名称（同命名空间）。
+	// 所有依赖 Succeeded 后才开始执行；任一依赖 Failed/Aborted 则本测试以 DependencyFailed 中止。
+	// 依赖不存在时设置 DependencyNotFound Condition 等待，超过 repeat.maxDurationSeconds（未设置时 10 分钟）仍不存在则以 DependencyNotFound 失败。
+	// +optional
+	DependsOn []string `json:"dependsOn,omitempty"`
+	// EnvironmentRef 引用的 Environment（同命名空间），其变量与服务地址在调和时展开到 spec 中。
+	// +optional
+	EnvironmentRef *EnvironmentReference `json:"environmentRef,omitempty"`
+	// Triage 失败分诊配置，测试以 Failed 结束时调用。
+	// +optional
+	Triage *TriageSpec `json:"triage,omitempty"`
+	// Debug 调试配置（如步骤失败时在断点处暂停）。
+	// +optional
+	Debug *DebugSpec `json:"debug,omitempty"`
+	// Variables 测试变量，期望参数中的 ${vars.<name>} 在调用期望函数前替换为变量值。
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	Variables []TestVariable `json:"variables,omitempty"`
+	// Exporters 结果导出器，与控制器级导出器一起接收本测试的事件。
+	// +optional
+	Exporters []ExporterSpec `json:"exporters,omitempty"`
+	// Workspace 测试专属的临时存储，每次运行开始时创建，spec 中的 ${WORKSPACE} 替换为其名称，
+	// 供步骤（如 Job）之间交换文件或数据。
+	// +optional
+	Workspace *WorkspaceSpec `json:"workspace,omitempty"`
+	// StatsExpectations 所有轮次结束、判定成功前执行一次的期望，断言对象为测试自身（如 status.stats.maxRoundSeconds）。
+	// 不满足时测试以 StatsExpectationsFailed 失败，使耗时回归成为失败而不只是记录下来的数字。
+	// +optional
+	StatsExpectations *StepCondition `json:"statsExpectations,omitempty"`
+	// StrictSchema 预检时以服务端 dry-run（fieldValidation=Strict）校验所有步骤清单，
+	// 清单含未知字段（如拼写错误）或不符合 schema 时测试以 PreflightFailed 失败，而不是静默创建不完整的对象。
+	// +optional
+	StrictSchema bool `json:"strictSchema,omitempty"`
+	// ScopedRBAC 以最小权限的身份执行资源写入：预检时根据步骤清单与选择器引用的类型生成 Role，
+	// 绑定到测试专属的 ServiceAccount，apply/删除以该身份执行，取代控制器的通配权限。
+	// 清单只能写入测试所在命名空间的命名空间级资源，否则预检失败。
+	// +optional
+	ScopedRBAC bool `json:"scopedRBAC,omitempty"`
+	// Suspend 暂停执行（类似 CronJob 的 suspend）：设置为 true 后不再推进步骤与轮次，
+	// 恢复为 false 时从暂停处继续，暂停期间的时长不计入步骤与 readyCondition 的超时。
+	// +optional
+	Suspend bool `json:"suspend,omitempty"`
+	// PropagateAnnotations 复制到步骤创建的每个资源上的测试注解键（如工单号、流水线地址），
+	// 便于从资源追溯到创建它的测试与 CI 运行。以 * 结尾的键按前缀匹配（如 ci.example.com/*）。
+	// +optional
+	PropagateAnnotations []string `json:"propagateAnnotations,omitempty"`
+	// Cleanup 测试删除时按依赖顺序清理步骤创建的资源，未设置时使用默认等待时长。
+	// +optional
+	Cleanup *CleanupSpec `json:"cleanup,omitempty"`
+	// CleanupPolicy 测试结束后删除步骤资源的时机（未设置 cleanupPolicy 的步骤）：
+	// DeleteOnSuccess 在测试成功后删除、失败或中止时保留现场，DeleteAlways 在测试结束后总是删除，
+	// Keep（默认）保留到测试删除。删除按依赖顺序进行，等待时长沿用 cleanup 配置。
+	// +optional
+	CleanupPolicy CleanupPolicy `json:"cleanupPolicy,omitempty"`
+}
+
+// DebugSpec 调试配置。
+type DebugSpec struct {
+	// BreakOnFailure 步骤失败时在断点处暂停：测试保持 Running（原因 Breakpoint）并保留所有资源，
+	// 通过 infra.testplane.io/resume 注解重试失败的步骤，或通过 infra.testplane.io/abort 注解中止。
+	// +optional
+	BreakOnFailure bool `json:"breakOnFailure,omitempty"`
+}
+
+// WorkspaceKind 工作区的资源类型。
+// +kubebuilder:validation:Enum=ConfigMap;Secret;PersistentVolumeClaim
+type WorkspaceKind string
+
+const (
+	WorkspaceKindConfigMap             WorkspaceKind = "ConfigMap"
+	WorkspaceKindSecret                WorkspaceKind = "Secret"
+	WorkspaceKindPersistentVolumeClaim WorkspaceKind = "PersistentVolumeClaim"
+)
+
+// WorkspaceSpec 定义测试工作区。
+type WorkspaceSpec struct {
+	// Kind 工作区资源类型，默认 ConfigMap。
+	// +kubebuilder:default=ConfigMap
+	// +optional
+	Kind WorkspaceKind `json:"kind,omitempty"`
+	// Size PersistentVolumeClaim 的容量，默认 1Gi。
+	// +optional
+	Size *resource.Quantity `json:"size,omitempty"`
+	// StorageClassName PersistentVolumeClaim 的存储类，为空时使用集群默认存储类。
+	// +optional
+	StorageClassName *string `json:"storageClassName,omitempty"`
 }
 
 // IntegrationTestPhase 定义测试用例的阶段。
@@ -107,13 +421,54 @@ const (
 	IntegrationTestPhaseAborted   IntegrationTestPhase = "Aborted"
 )
 
+// AnnotationAbort 中止注解：设置后运行中（或等待中）的 IntegrationTest 进入 Aborted 阶段，
+// 运行中的 LoadTest 停止负载并以相同原因进入 Failed 阶段。
+// 值为中止原因：Preempted 表示被抢占，其他值（如 "true"）均视为 ManualAbort。
+const AnnotationAbort = "infra.testplane.io/abort"
+
+// AnnotationRerun 重跑注解：值为任意令牌（如时间戳），与 status.rerunToken 不同时
+// 控制器清空状态并从头重新执行测试（IntegrationTest 与 LoadTest 均支持）。
+// 重跑不会移除中止注解，需要同时删除 infra.testplane.io/abort（testplane bulk rerun 会一并处理）。
+const AnnotationRerun = "infra.testplane.io/rerun"
+
+// AnnotationResume 断点恢复注解：值为任意令牌，测试停在断点（spec.debug.breakOnFailure）且令牌与 status.resumeToken 不同时，
+// 控制器重置失败的步骤并从该步骤继续执行。
+const AnnotationResume = "infra.testplane.io/resume"
+
+// AnnotationManifestHash 步骤 apply 的对象上记录的清单哈希，与 status.applyLedger 一起用于
+// 控制器在 apply 之后、写入 status 之前崩溃时判断对象是否已 apply。
+const AnnotationManifestHash = "infra.testplane.io/manifest-hash"
+
+// 资源预算注解：限制 IntegrationTest（累计所有轮次）经步骤 apply 的对象数、这些对象将创建的 Pod 数与 CPU 请求总量，
+// 超出时不再 apply 并以 BudgetExceeded 失败，防止失控的重复测试耗尽共享环境。未设置的注解不限制。
+const (
+	// AnnotationBudgetMaxObjects 最多 apply 的对象次数（整数）。
+	AnnotationBudgetMaxObjects = "infra.testplane.io/budget-max-objects"
+	// AnnotationBudgetMaxPods 最多创建的 Pod 数（整数，按 Pod、工作负载副本数与 Job completions 估算）。
+	AnnotationBudgetMaxPods = "infra.testplane.io/budget-max-pods"
+	// AnnotationBudgetMaxCPU 创建对象的 CPU 请求总量上限（Quantity，如 "8" 或 "500m"）。
+	AnnotationBudgetMaxCPU = "infra.testplane.io/budget-max-cpu"
+)
+
+// Aborted 阶段的原因常量（写入 status.reason）。
+const (
+	// AbortReasonManual 用户通过注解手动中止。
+	AbortReasonManual = "ManualAbort"
+	// AbortReasonDependencyFailed 依赖的 IntegrationTest 失败或被中止。
+	AbortReasonDependencyFailed = "DependencyFailed"
+	// AbortReasonPreempted 被调度方抢占（如为更高优先级的测试让出资源）。
+	AbortReasonPreempted = "Preempted"
+	// AbortReasonNamespaceTerminating 测试所在命名空间进入删除流程，后续 apply 只会被拒绝。
+	AbortReasonNamespaceTerminating = "NamespaceTerminating"
+)
+
 // StepStatus 记录步骤的执行状态。
 type StepStatus struct {
 	// Name 步骤名称。
 	Name string `json:"name"`
 	// Index 步骤序号（从 0 开始）。
 	Index int `json:"index,omitempty"`
-	// State 步骤状态：Succeeded, Failed, Running。
+	// State 步骤状态：Succeeded, Failed, Running, Aborted。
 	State string `json:"state,omitempty"`
 	// Reason 步骤失败原因。
 	Reason string `json:"reason,omitempty"`
@@ -128,15 +483,208 @@ type StepStatus struct {
 	FinishedAt *metav1.Time `json:"finishedAt,omitempty"`
 	// ExpectationResults 期望结果摘要。
 	ExpectationResults []ExpectationResultSummary `json:"expectationResults,omitempty"`
+	// ErrorCount 期望检查开始出现可重试错误的次数（连续出错计一次，不计入断言失败）。
+	ErrorCount int32 `json:"errorCount,omitempty"`
 	// ReadyConditionStatus 就绪条件检查状态。
 	ReadyConditionStatus *ReadyConditionStatus `json:"readyConditionStatus,omitempty"`
+	// SelectorDiagnostics 选择器未匹配到资源时的诊断信息。
+	SelectorDiagnostics *SelectorDiagnostics `json:"selectorDiagnostics,omitempty"`
+	// IterationCount 周期步骤已开始的迭代次数（首次执行不计入）。
+	IterationCount int32 `json:"iterationCount,omitempty"`
+	// Iterations 周期步骤最近的迭代记录（最多保留 10 条）。
+	// +optional
+	Iterations []StepIteration `json:"iterations,omitempty"`
+	// Outputs 步骤成功后记录的输出：资源的 kind、name、namespace 与 spec.steps[].outputs 提取的值，
+	// 后续步骤的清单与期望参数以 ${steps.<name>.outputs.<key>} 引用。
+	// +optional
+	Outputs map[string]string `json:"outputs,omitempty"`
+	// Progress 等待期间目标资源的进度快照，按心跳间隔或目标阶段变化时更新，
+	// 用于区分长时间步骤（如集群创建）是仍在推进还是已卡住。
+	// +optional
+	Progress *StepProgress `json:"progress,omitempty"`
+	// RaceAttempts 并发竞争步骤每次尝试的结果（按尝试序号排列）。
+	// +optional
+	RaceAttempts []RaceAttempt `json:"raceAttempts,omitempty"`
+	// FailureChecks 超时复核（confirmFailureSeconds）的观察：第一次为超时时的检查，第二次为复核。
+	// +optional
+	FailureChecks []FailureCheck `json:"failureChecks,omitempty"`
+	// Attempts 设置 retries 的步骤发生重试后每次尝试的结果（按尝试序号排列），未重试时为空。
+	// +optional
+	Attempts []StepAttempt `json:"attempts,omitempty"`
+	// PreStep preStep 清单的执行结果，未设置 preStep 或尚未执行时为空。
+	// +optional
+	PreStep *StepHookStatus `json:"preStep,omitempty"`
+	// PostStep postStep 清单的执行结果，未设置 postStep 或尚未执行时为空。
+	// +optional
+	PostStep *StepHookStatus `json:"postStep,omitempty"`
+	// Cleanup 按步骤 cleanupPolicy 自动删除资源的进度，未触发删除时为空。
+	// +optional
+	Cleanup *CleanupStatus `json:"cleanup,omitempty"`
+}
+
+// StepHookStatus 步骤 preStep/postStep 清单的执行结果。
+type StepHookStatus struct {
+	// State 执行结果：Succeeded 或 Failed。
+	State string `json:"state"`
+	// Message 失败信息。
+	// +optional
+	Message string `json:"message,omitempty"`
+	// FinishedAt 执行完成时间。
+	// +optional
+	FinishedAt *metav1.Time `json:"finishedAt,omitempty"`
+}
+
+// StepOutput 定义一个步骤输出。
+type StepOutput struct {
+	// Name 输出名称，引用为 ${steps.<step>.outputs.<name>}；与内置输出同名时覆盖内置输出。
+	// +kubebuilder:validation:Pattern=`^[A-Za-z0-9_-]+$`
+	Name string `json:"name"`
+	// Extract 从步骤资源提取值的提取器，参数中可引用 ${vars.<name>} 与之前步骤的输出。
+	Extract Extractor `json:"extract"`
+}
+
+// CleanupStatus 按 cleanupPolicy 自动删除资源的进度。
+type CleanupStatus struct {
+	// State 删除状态：Running（按依赖顺序删除中）、Succeeded 或 Failed。
+	State string `json:"state"`
+	// Message 失败信息。
+	// +optional
+	Message string `json:"message,omitempty"`
+	// FinishedAt 删除完成时间。
+	// +optional
+	FinishedAt *metav1.Time `json:"finishedAt,omitempty"`
+}
+
+// StepAttempt 步骤一次尝试的结果。
+type StepAttempt struct {
+	// Attempt 尝试序号（从 1 开始）。
+	Attempt int32 `json:"attempt"`
+	// State 尝试结果：Succeeded 或 Failed。
+	State string `json:"state"`
+	// Reason 失败原因（如 Failed、Timeout、Conflict）。
+	// +optional
+	Reason string `json:"reason,omitempty"`
+	// Message 失败信息。
+	// +optional
+	Message string `json:"message,omitempty"`
+	// StartedAt 尝试开始时间。
+	// +optional
+	StartedAt *metav1.Time `json:"startedAt,omitempty"`
+	// FinishedAt 尝试结束时间。
+	// +optional
+	FinishedAt *metav1.Time `json:"finishedAt,omitempty"`
+}
+
+// FailureCheck 超时复核的一次观察。
+type FailureCheck struct {
+	// At 观察时间。
+	At metav1.Time `json:"at"`
+	// Passed 期望是否满足。
+	Passed bool `json:"passed"`
+	// Failed 未满足的期望。
+	// +optional
+	Failed string `json:"failed,omitempty"`
+}
+
+// BreakpointStatus 断点信息。
+type BreakpointStatus struct {
+	// Round 断点所在轮次。
+	Round int `json:"round"`
+	// Step 失败的步骤名称。
+	Step string `json:"step"`
+	// Reason 步骤失败原因（如 Failed、Timeout）。
+	Reason string `json:"reason,omitempty"`
+	// Message 步骤失败信息。
+	Message string `json:"message,omitempty"`
+	// At 进入断点的时间。
+	At *metav1.Time `json:"at,omitempty"`
+}
+
+// RaceAttempt 并发竞争步骤单次 apply 尝试的结果。
+type RaceAttempt struct {
+	// Attempt 尝试序号（从 1 开始）。
+	Attempt int32 `json:"attempt"`
+	// FieldOwner 本次尝试使用的字段管理者。
+	FieldOwner string `json:"fieldOwner"`
+	// Outcome 结果：Applied（成功）、Conflict（字段所有权冲突被拒绝）、Error（其他错误）。
+	Outcome string `json:"outcome"`
+	// Message 未成功时的错误信息。
+	Message string `json:"message,omitempty"`
+}
+
+// StepProgress 步骤等待期间的进度快照。
+type StepProgress struct {
+	// LastHeartbeatTime 最近一次记录快照的时间。
+	LastHeartbeatTime *metav1.Time `json:"lastHeartbeatTime,omitempty"`
+	// ObservedPhase 最近观察到的目标资源阶段：status.phase，没有时为 Ready 条件（如 Ready=False）。
+	ObservedPhase string `json:"observedPhase,omitempty"`
+	// ObservedMessage 目标资源的状态消息：status.message，没有时为 Ready 条件的消息。
+	ObservedMessage string `json:"observedMessage,omitempty"`
+	// LastTransitionTime ObservedPhase 最近一次变化的时间。
+	LastTransitionTime *metav1.Time `json:"lastTransitionTime,omitempty"`
+}
+
+// StepIteration 周期步骤的单次迭代结果。
+type StepIteration struct {
+	// Iteration 迭代序号（从 1 开始）。
+	Iteration int32 `json:"iteration"`
+	// State 迭代状态：Running, Succeeded, Failed。
+	State string `json:"state,omitempty"`
+	// Message 迭代失败原因。
+	Message string `json:"message,omitempty"`
+	// StartedAt 迭代开始时间。
+	StartedAt *metav1.Time `json:"startedAt,omitempty"`
+	// FinishedAt 迭代结束时间。
+	FinishedAt *metav1.Time `json:"finishedAt,omitempty"`
+}
+
+// RoundStats 已完成轮次的汇总统计。
+// 轮次耗时为该轮最早开始的步骤到最晚结束的步骤之间的时长；步骤失败会直接结束测试，因此统计的轮次均已成功。
+type RoundStats struct {
+	// Rounds 已统计的轮次数。
+	Rounds int `json:"rounds"`
+	// LastRoundSeconds 最近一轮的耗时（秒）。
+	LastRoundSeconds int64 `json:"lastRoundSeconds"`
+	// MinRoundSeconds 最短轮次耗时（秒）。
+	MinRoundSeconds int64 `json:"minRoundSeconds"`
+	// MaxRoundSeconds 最长轮次耗时（秒）。
+	MaxRoundSeconds int64 `json:"maxRoundSeconds"`
+	// AvgRoundSeconds 平均轮次耗时（秒，向下取整）。
+	AvgRoundSeconds int64 `json:"avgRoundSeconds"`
+	// TotalRoundSeconds 各轮耗时之和（秒）。
+	TotalRoundSeconds int64 `json:"totalRoundSeconds"`
+	// MaxStepSeconds 单个步骤的最长耗时（秒）。
+	MaxStepSeconds int64 `json:"maxStepSeconds"`
+	// SlowestStep 耗时最长的步骤名称。
+	// +optional
+	SlowestStep string `json:"slowestStep,omitempty"`
+}
+
+// ApplyLedgerEntry apply 台账的一项：步骤资源 apply 之前写入的意图。
+type ApplyLedgerEntry struct {
+	// Step 步骤名称。
+	Step string `json:"step"`
+	// Round 轮次。
+	Round int `json:"round"`
+	// Hash 展开后清单的哈希。
+	Hash string `json:"hash"`
+}
+
+// BudgetUsage 资源预算的累计用量。
+type BudgetUsage struct {
+	// Objects 已 apply 的对象次数。
+	Objects int64 `json:"objects"`
+	// Pods 估算已创建的 Pod 数。
+	Pods int64 `json:"pods"`
+	// CPUMillis 已创建对象的 CPU 请求总量（毫核）。
+	CPUMillis int64 `json:"cpuMillis"`
 }
 
 // IntegrationTestStatus 记录测试用例的状态和报告。
 type IntegrationTestStatus struct {
 	// Phase 测试阶段。
 	Phase IntegrationTestPhase `json:"phase,omitempty"`
-	// Reason 阶段原因（如 StepFailed、InitialConditionNotMet、Timeout）。
+	// Reason 阶段原因（如 StepFailed、Timeout、BudgetExceeded、APIRemoved、StatsExpectationsFailed；Aborted 阶段为 ManualAbort、DependencyFailed、Preempted、NamespaceTerminating）。
 	Reason string `json:"reason,omitempty"`
 	// Message 阶段消息。
 	Message string `json:"message,omitempty"`
@@ -154,6 +702,56 @@ type IntegrationTestStatus struct {
 	CompletedRounds int `json:"completedRounds,omitempty"`
 	// Steps 步骤状态详情（当前轮次）。
 	Steps []StepStatus `json:"steps,omitempty"`
+	// Triage 失败分诊结果。
+	Triage *TriageStatus `json:"triage,omitempty"`
+	// RerunToken 已处理的重跑令牌（infra.testplane.io/rerun 注解的值）。
+	RerunToken string `json:"rerunToken,omitempty"`
+	// ResumeToken 已处理的断点恢复令牌（infra.testplane.io/resume 注解的值）。
+	// +optional
+	ResumeToken string `json:"resumeToken,omitempty"`
+	// Breakpoint 测试停在的断点（spec.debug.breakOnFailure 时步骤失败），恢复后清除，中止后保留以记录停止位置。
+	// +optional
+	Breakpoint *BreakpointStatus `json:"breakpoint,omitempty"`
+	// Workspace 本次运行的工作区资源名称（设置 spec.workspace 时）。
+	// +optional
+	Workspace string `json:"workspace,omitempty"`
+	// RunAs 设置 spec.scopedRBAC 时执行资源写入的身份（system:serviceaccount:<namespace>:<name>）。
+	// +optional
+	RunAs string `json:"runAs,omitempty"`
+	// SkippedRounds 设置 repeat.ratePerMinute 且 overrunPolicy 为 Skip 时，因上一轮超时而跳过的时间槽数。
+	// +optional
+	SkippedRounds int `json:"skippedRounds,omitempty"`
+	// ApplyLedger 本轮步骤资源 apply 之前写入的台账（步骤清单哈希）。控制器在 apply 之后、
+	// 步骤状态写入之前崩溃时，新的 leader 据此确认对象已 apply，不重复 apply，从中断处继续。
+	// +optional
+	ApplyLedger []ApplyLedgerEntry `json:"applyLedger,omitempty"`
+	// BudgetUsage 资源预算的累计用量（设置预算注解时记录）。
+	// +optional
+	BudgetUsage *BudgetUsage `json:"budgetUsage,omitempty"`
+	// Stats 已完成轮次的汇总统计，每轮完成时更新。
+	// +optional
+	Stats *RoundStats `json:"stats,omitempty"`
+	// Cleanup 测试结束后按 spec.cleanupPolicy 自动删除资源的进度，未触发删除时为空。
+	// +optional
+	Cleanup *CleanupStatus `json:"cleanup,omitempty"`
+	// TouchedKinds 测试涉及的资源类型（Kind.group，核心组只有 Kind），初始化时由步骤资源与 preStep/postStep 推导，
+	// 用于测试影响分析；合法的类型同时写入 touches.infra.testplane.io/<Kind.group> 标签。
+	// +optional
+	TouchedKinds []string `json:"touchedKinds,omitempty"`
+	// SpecDigest 开始执行时 spec 各路径（如 timeout、steps[create].resource）的摘要，
+	// 用于比较运行中被忽略的 spec 变更。
+	// +optional
+	SpecDigest map[string]string `json:"specDigest,omitempty"`
+	// IgnoredSpecChanges 运行中被忽略的 spec 变更路径，前缀 ~ 修改、+ 新增、- 删除。
+	// +optional
+	IgnoredSpecChanges []string `json:"ignoredSpecChanges,omitempty"`
+	// SuspendedAt spec.suspend 生效、暂停执行的时间，恢复时据此顺延步骤截止时间。
+	// +optional
+	SuspendedAt *metav1.Time `json:"suspendedAt,omitempty"`
+	// Deadlines 进行中的截止时间，key 为对应的 Condition 类型（StepDeadline、ReadyConditionDeadline、MaxDurationDeadline），
+	// 仪表盘可据此显示倒计时。
+	// +optional
+	Deadlines map[string]metav1.Time `json:"deadlines,omitempty"`
 	// Conditions 条件列表。
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
@@ -165,8 +763,10 @@ type IntegrationTestStatus struct {
 // +kubebuilder:printcolumn:name="Round",type=integer,JSONPath=`.status.currentRound`,priority=1
 // +kubebuilder:printcolumn:name="Completed",type=integer,JSONPath=`.status.completedRounds`,priority=1
 // +kubebuilder:printcolumn:name="Reason",type=string,JSONPath=`.status.reason`,priority=1
+// +kubebuilder:printcolumn:name="Suspend",type=boolean,JSONPath=`.spec.suspend`,priority=1
 // +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
 // +kubebuilder:resource:shortName=it
+// +kubebuilder:storageversion
 
 // IntegrationTest 表示一个集成测试用例。
 type IntegrationTest struct {
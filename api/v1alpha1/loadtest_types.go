@@ -44,10 +44,74 @@ type HealthCheck struct {
 	// FailureThreshold 连续失败阈值。
 	// +kubebuilder:default=3
 	FailureThreshold int32 `json:"failureThreshold,omitempty"`
+	// JitterSeconds 检查时间偏移上限（秒）。每个 LoadTest 按 UID 取 [0, jitterSeconds) 内固定的偏移，
+	// 避免集群中大量 LoadTest 在同一秒检查。
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	JitterSeconds int32 `json:"jitterSeconds,omitempty"`
+	// AlignToMinute 检查对齐到整分钟：间隔小于 1 分钟时在每分钟内按间隔对齐（如 15 秒间隔在 :00/:15/:30/:45），
+	// 否则对齐到整分钟；与 JitterSeconds 同时使用时在对齐点之后再偏移。
+	// +optional
+	AlignToMinute bool `json:"alignToMinute,omitempty"`
 	// AllOf 所有期望都必须满足。
 	AllOf []Expectation `json:"allOf,omitempty"`
 	// AnyOf 任一期望满足即可。
 	AnyOf []Expectation `json:"anyOf,omitempty"`
+	// Trends 数值趋势检查（有状态）：每次健康检查采样一次数值，基于历史样本断言趋势。
+	// 用于发现单点断言无法发现的问题（如内存泄漏、计数器停滞）。
+	// +optional
+	Trends []TrendCheck `json:"trends,omitempty"`
+}
+
+// TrendType 趋势断言类型。
+// +kubebuilder:validation:Enum=NonDecreasing;StableWithin;GrowthRateBelow
+type TrendType string
+
+const (
+	// TrendNonDecreasing 样本单调不减（如请求计数器不应停滞回退）。
+	TrendNonDecreasing TrendType = "NonDecreasing"
+	// TrendStableWithin 样本相对窗口均值的偏差不超过 TolerancePercent。
+	TrendStableWithin TrendType = "StableWithin"
+	// TrendGrowthRateBelow 窗口内每分钟增长量不超过 MaxGrowthPerMinute（如内存不应持续增长）。
+	TrendGrowthRateBelow TrendType = "GrowthRateBelow"
+)
+
+// PromQuery Prometheus 即时查询（取结果的第一个值）。
+type PromQuery struct {
+	// URL Prometheus 地址（如 http://prometheus.monitoring:9090）。
+	// 未设置时使用引用的 Environment 中名为 prometheus 的 baseURL。
+	// +optional
+	URL string `json:"url,omitempty"`
+	// Query PromQL 查询语句。
+	Query string `json:"query"`
+}
+
+// TrendCheck 数值趋势检查定义。
+// 数值来源 Extract 与 PromQuery 二选一。
+type TrendCheck struct {
+	// Name 趋势检查名称（用于状态和结果展示）。
+	Name string `json:"name"`
+	// Extract 从 Target 提取数值的提取器（与 PromQuery 互斥）。
+	// +optional
+	Extract *Extractor `json:"extract,omitempty"`
+	// PromQuery Prometheus 查询（与 Extract 互斥）。
+	// +optional
+	PromQuery *PromQuery `json:"promQuery,omitempty"`
+	// Type 趋势类型。
+	Type TrendType `json:"type"`
+	// TolerancePercent StableWithin 允许的偏差百分比（默认 10）。
+	// +optional
+	TolerancePercent int32 `json:"tolerancePercent,omitempty"`
+	// MaxGrowthPerMinute GrowthRateBelow 允许的每分钟最大增长量（十进制数字字符串，如 "1.5"），
+	// 等于该值仍通过；"0" 表示只允许平稳或下降。
+	// +optional
+	MaxGrowthPerMinute string `json:"maxGrowthPerMinute,omitempty"`
+	// WindowSize 参与判定的最近样本数（默认 10）。
+	// +optional
+	WindowSize int32 `json:"windowSize,omitempty"`
+	// MinSamples 开始判定所需的最少样本数（默认 3），样本不足时视为通过。
+	// +optional
+	MinSamples int32 `json:"minSamples,omitempty"`
 }
 
 // TargetSpec 定义测试目标资源（单资源）。
@@ -57,6 +121,36 @@ type TargetSpec struct {
 	// ReadyCondition 就绪条件（可选）。
 	// 创建/更新 Target 后，等待此条件满足才继续执行后续步骤。
 	ReadyCondition *ReadyCondition `json:"readyCondition,omitempty"`
+	// DriftDetection 运行期漂移检测（可选，仅 Manifest 目标）。
+	// 定期比较 Target 实际状态与模板声明的字段，发现被手动修改或被其他控制器回滚时按策略处理。
+	// +optional
+	DriftDetection *DriftDetection `json:"driftDetection,omitempty"`
+}
+
+// DriftPolicy Target 漂移处理策略。
+// +kubebuilder:validation:Enum=Reapply;Warn;Fail
+type DriftPolicy string
+
+const (
+	// DriftPolicyReapply 重新应用模板，恢复被修改的字段。
+	DriftPolicyReapply DriftPolicy = "Reapply"
+	// DriftPolicyWarn 设置 TargetDrifted Condition 并发送 Warning 事件，测试继续。
+	DriftPolicyWarn DriftPolicy = "Warn"
+	// DriftPolicyFail 测试失败。
+	DriftPolicyFail DriftPolicy = "Fail"
+)
+
+// DriftDetection Target 漂移检测配置。
+type DriftDetection struct {
+	// IntervalSeconds 检测间隔（秒）。
+	// +kubebuilder:default=60
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	IntervalSeconds int32 `json:"intervalSeconds,omitempty"`
+	// DriftPolicy 检测到漂移时的处理策略，默认 Warn。
+	// +kubebuilder:default=Warn
+	// +optional
+	DriftPolicy DriftPolicy `json:"driftPolicy,omitempty"`
 }
 
 // EnvInjection 环境变量注入定义。
@@ -69,14 +163,62 @@ type EnvInjection struct {
 }
 
 // WorkloadSpec 负载资源定义。
+// Resources 与 HTTPLoad 至少设置一个，可同时使用。
+// +kubebuilder:validation:XValidation:rule="(has(self.resources) && size(self.resources) > 0) || has(self.httpLoad)",message="workload requires resources or httpLoad"
 type WorkloadSpec struct {
 	// EnvInjection 环境变量注入列表（函数式）。
 	EnvInjection []EnvInjection `json:"envInjection,omitempty"`
 	// Resources 负载资源（多资源）。
-	Resources []ResourceRef `json:"resources"`
+	// +optional
+	Resources []ResourceRef `json:"resources,omitempty"`
+	// HTTPLoad 内置 HTTP 负载（可选）。
+	// 控制器生成并管理 worker Deployment 直接发起 HTTP 请求，简单的 API 压测无需编写负载 manifest。
+	// +optional
+	HTTPLoad *HTTPLoadSpec `json:"httpLoad,omitempty"`
+}
+
+// HTTPLoadSpec 内置 HTTP 负载定义。
+type HTTPLoadSpec struct {
+	// URL 请求地址，可用 $(NAME) 引用 EnvInjection 注入的值（如 http://$(TARGET_HOST):8080/api）。
+	URL string `json:"url"`
+	// Method HTTP 方法。
+	// +kubebuilder:validation:Enum=GET;POST;PUT;PATCH;DELETE;HEAD
+	// +kubebuilder:default=GET
+	// +optional
+	Method string `json:"method,omitempty"`
+	// Headers 请求头（可选）。
+	// +optional
+	Headers map[string]string `json:"headers,omitempty"`
+	// Body 请求体（可选）。
+	// +optional
+	Body string `json:"body,omitempty"`
+	// RPS 所有 worker 合计的每秒请求数。
+	// +kubebuilder:validation:Minimum=1
+	RPS int32 `json:"rps"`
+	// Concurrency 每个 worker 的最大并发请求数。
+	// +kubebuilder:default=10
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	Concurrency int32 `json:"concurrency,omitempty"`
+	// Replicas worker 副本数，RPS 在副本间平均分配。
+	// +kubebuilder:default=1
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	Replicas int32 `json:"replicas,omitempty"`
+	// DurationSeconds 施压时长（秒），0 表示持续施压直到 LoadTest 删除。
+	// +optional
+	DurationSeconds int32 `json:"durationSeconds,omitempty"`
+	// TimeoutSeconds 单个请求超时（秒）。
+	// +kubebuilder:default=10
+	// +optional
+	TimeoutSeconds int32 `json:"timeoutSeconds,omitempty"`
+	// Image worker 镜像（可选），默认使用控制器的 --loadgen-image。
+	// +optional
+	Image string `json:"image,omitempty"`
 }
 
 // LoadTestSpec 定义负载测试规格。
+// +kubebuilder:validation:XValidation:rule="!has(self.postVerification) || (has(self.durationSeconds) && self.durationSeconds > 0)",message="postVerification requires durationSeconds > 0"
 type LoadTestSpec struct {
 	// Target 被测目标资源。
 	// 使用 Target.ReadyCondition 定义就绪条件，通过后才部署 Workload。
@@ -86,10 +228,184 @@ type LoadTestSpec struct {
 	// HealthCheck 运行期健康检查（周期性执行）。
 	// 使用 IntervalSeconds（检查间隔）和 FailureThreshold（连续失败阈值）。
 	HealthCheck *HealthCheck `json:"healthCheck,omitempty"`
+	// WarmupSeconds 进入 Running 后的预热时长（秒）：预热期内健康检查照常执行并记录结果，
+	// 但不计入通过/失败次数与连续失败，也不采集趋势样本，避免 JIT 预热、缓存填充等启动抖动触发失败阈值或影响最终统计。
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	WarmupSeconds int32 `json:"warmupSeconds,omitempty"`
+	// Monitoring 监控资源生成（可选）：Grafana dashboard 与 PrometheusRule 告警。
+	// +optional
+	Monitoring *MonitoringSpec `json:"monitoring,omitempty"`
+	// LoadControl 闭环负载控制（可选）：调整负载参数使指标保持在目标值附近。
+	// +optional
+	LoadControl *LoadControlSpec `json:"loadControl,omitempty"`
+	// DurationSeconds 运行时长（秒）：Running 持续该时长后停止负载，
+	// 执行 PostVerification（如有）后决定最终阶段。0 表示持续运行直到 LoadTest 删除。
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	DurationSeconds int32 `json:"durationSeconds,omitempty"`
+	// PostVerification 负载结束后的验证步骤（可选，需设置 DurationSeconds，否则负载不会结束、验证不会执行，创建时被拒绝）。
+	// 用于压测后的数据一致性校验，结果记录在同一个 LoadTest 的状态中。
+	// +optional
+	PostVerification *PostVerificationSpec `json:"postVerification,omitempty"`
+	// EnvironmentRef 引用的 Environment（同命名空间），其变量与服务地址在调和时展开到 spec 中。
+	// +optional
+	EnvironmentRef *EnvironmentReference `json:"environmentRef,omitempty"`
+	// Triage 失败分诊配置，测试以 Failed 结束时调用。
+	// +optional
+	Triage *TriageSpec `json:"triage,omitempty"`
+	// Exporters 结果导出器，与控制器级导出器一起接收本测试的事件。
+	// +optional
+	Exporters []ExporterSpec `json:"exporters,omitempty"`
+	// Artifacts 测试结束（成功或失败）时从 workload Pod 中收集的文件，
+	// 经 exec + tar 复制到控制器配置的工件存储（--artifact-dir），避免 Pod 被清理后结果文件丢失。
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	Artifacts []ArtifactSpec `json:"artifacts,omitempty"`
+	// PropagateAnnotations 复制到 workload 资源上的测试注解键（如工单号、流水线地址），以 * 结尾的键按前缀匹配。
+	// +optional
+	PropagateAnnotations []string `json:"propagateAnnotations,omitempty"`
+	// Cleanup 测试删除时按依赖顺序清理监控、workload 与 target 资源，未设置时使用默认等待时长。
+	// +optional
+	Cleanup *CleanupSpec `json:"cleanup,omitempty"`
+}
+
+// ArtifactSpec 定义从 Pod 中收集的一组文件。
+type ArtifactSpec struct {
+	// Name 工件名称，归档文件名为 <name>-<pod>.tar。
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	Name string `json:"name"`
+	// PodSelector 选择 Pod 的标签（与 LoadTest 同命名空间），
+	// 为空时选择内置 HTTP 负载的 worker Pod。
+	// +optional
+	PodSelector map[string]string `json:"podSelector,omitempty"`
+	// Container 容器名称，默认 Pod 的第一个容器。
+	// +optional
+	Container string `json:"container,omitempty"`
+	// Paths 容器内的文件或目录路径。
+	// +kubebuilder:validation:MinItems=1
+	Paths []string `json:"paths"`
+}
+
+// ArtifactStatus 单个 Pod 的工件收集结果。
+type ArtifactStatus struct {
+	// Name 工件名称。
+	Name string `json:"name"`
+	// Pod 来源 Pod，未选中任何 Pod 时为空。
+	// +optional
+	Pod string `json:"pod,omitempty"`
+	// Location 工件在存储中的位置。
+	// +optional
+	Location string `json:"location,omitempty"`
+	// SizeBytes 归档大小。
+	// +optional
+	SizeBytes int64 `json:"sizeBytes,omitempty"`
+	// Error 收集失败的原因。
+	// +optional
+	Error string `json:"error,omitempty"`
+}
+
+// PostVerificationSpec 负载结束后执行的验证步骤（IntegrationTest 风格）。
+// 控制器以 LoadTest 为 owner 创建 IntegrationTest（<name>-verify）执行这些步骤，
+// 其结果决定 LoadTest 的最终阶段。
+type PostVerificationSpec struct {
+	// Mode 步骤执行模式，默认 Sequential。
+	// +optional
+	Mode IntegrationTestMode `json:"mode,omitempty"`
+	// Steps 验证步骤。
+	// +kubebuilder:validation:MinItems=1
+	Steps []TestStep `json:"steps"`
+}
+
+// LoadParameterType 闭环控制调整的负载参数类型。
+// +kubebuilder:validation:Enum=Replicas;Env
+type LoadParameterType string
+
+const (
+	// LoadParameterReplicas 调整 workload 资源的 spec.replicas。
+	LoadParameterReplicas LoadParameterType = "Replicas"
+	// LoadParameterEnv 调整 workload Pod 模板中容器的数值型环境变量（如 RPS）。
+	LoadParameterEnv LoadParameterType = "Env"
+)
+
+// LoadParameter 闭环控制调整的负载参数。
+type LoadParameter struct {
+	// Type 参数类型。
+	Type LoadParameterType `json:"type"`
+	// APIVersion workload 资源的 apiVersion。
+	APIVersion string `json:"apiVersion"`
+	// Kind workload 资源的 kind。
+	Kind string `json:"kind"`
+	// Name workload 资源名称（与 LoadTest 同命名空间）。
+	Name string `json:"name"`
+	// Container 容器名称（Type=Env 时使用，默认第一个容器）。
+	// +optional
+	Container string `json:"container,omitempty"`
+	// EnvName 环境变量名（Type=Env 时必填）。
+	// +optional
+	EnvName string `json:"envName,omitempty"`
+}
+
+// LoadControlSpec 闭环负载控制：按间隔查询指标，与目标值比较后调整负载参数。
+// 假设指标随参数单调递增（如 p99 延迟随 RPS 增大），用于寻找满足指标约束的最大负载。
+// +kubebuilder:validation:XValidation:rule="self.min <= self.max",message="min must not exceed max"
+type LoadControlSpec struct {
+	// Metric 被控指标（Prometheus 即时查询）。
+	Metric PromQuery `json:"metric"`
+	// Setpoint 指标目标值（十进制数字字符串，如 "200"）。
+	Setpoint string `json:"setpoint"`
+	// Parameter 被调整的负载参数。
+	Parameter LoadParameter `json:"parameter"`
+	// Min 参数下限。
+	// +kubebuilder:validation:Minimum=0
+	Min int32 `json:"min"`
+	// Max 参数上限。
+	// +kubebuilder:validation:Minimum=1
+	Max int32 `json:"max"`
+	// MaxStep 单次调整的最大变化量（默认 1）。
+	// +kubebuilder:default=1
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MaxStep int32 `json:"maxStep,omitempty"`
+	// IntervalSeconds 调整间隔（秒），应大于指标的采集与生效延迟。
+	// +kubebuilder:default=30
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	IntervalSeconds int32 `json:"intervalSeconds,omitempty"`
+	// TolerancePercent 指标与目标值的允许偏差百分比（默认 5），偏差内不调整。
+	// +kubebuilder:default=5
+	// +optional
+	TolerancePercent int32 `json:"tolerancePercent,omitempty"`
+	// StableSamples 连续多少次采样落在允许偏差内视为收敛（默认 3）。
+	// +kubebuilder:default=3
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	StableSamples int32 `json:"stableSamples,omitempty"`
+}
+
+// MonitoringSpec 为 LoadTest 生成的监控资源。
+// 生成的资源通过 ownerRef 关联到 LoadTest，随 LoadTest 删除。
+type MonitoringSpec struct {
+	// Dashboard 生成包含 Grafana dashboard JSON 的 ConfigMap（<name>-dashboard），
+	// 面板覆盖健康检查计数、连续失败次数与趋势检查声明的 promQuery。
+	// +optional
+	Dashboard bool `json:"dashboard,omitempty"`
+	// DashboardLabels dashboard ConfigMap 的标签，供 Grafana sidecar 发现。
+	// 为空时使用 grafana_dashboard: "1"。
+	// +optional
+	DashboardLabels map[string]string `json:"dashboardLabels,omitempty"`
+	// Alerts 生成 PrometheusRule（<name>-alerts），健康检查出现失败或连续失败达阈值时告警。
+	// 需要集群已安装 Prometheus Operator。
+	// +optional
+	Alerts bool `json:"alerts,omitempty"`
+	// RuleLabels PrometheusRule 的标签，供 Prometheus ruleSelector 选择（如 release: prometheus）。
+	// +optional
+	RuleLabels map[string]string `json:"ruleLabels,omitempty"`
 }
 
 // LoadTestPhase 负载测试阶段。
-// +kubebuilder:validation:Enum=Pending;Initializing;Running;Succeeded;Failed
+// +kubebuilder:validation:Enum=Pending;Initializing;Running;Verifying;Succeeded;Failed
 type LoadTestPhase string
 
 const (
@@ -99,6 +415,8 @@ const (
 	LoadTestInitializing LoadTestPhase = "Initializing"
 	// LoadTestRunning 运行中。
 	LoadTestRunning LoadTestPhase = "Running"
+	// LoadTestVerifying 负载已停止，执行 PostVerification。
+	LoadTestVerifying LoadTestPhase = "Verifying"
 	// LoadTestSucceeded 成功。
 	LoadTestSucceeded LoadTestPhase = "Succeeded"
 	// LoadTestFailed 失败。
@@ -117,8 +435,102 @@ type HealthCheckStatus struct {
 	FailCount int32 `json:"failCount,omitempty"`
 	// ConsecutiveFailures 连续失败次数。
 	ConsecutiveFailures int32 `json:"consecutiveFailures,omitempty"`
+	// ErrorCount 因可重试错误（如 Webhook 暂时不可用）未能得出结论的检查次数，不计入失败。
+	ErrorCount int32 `json:"errorCount,omitempty"`
+	// WarmupCount 预热期（spec.warmupSeconds）内执行的检查次数，计入 CheckCount，不计入其他次数。
+	WarmupCount int32 `json:"warmupCount,omitempty"`
 	// LastResults 最近一次检查结果摘要。
 	LastResults []ExpectationResultSummary `json:"lastResults,omitempty"`
+	// Trends 趋势检查的样本窗口。
+	Trends []TrendStatus `json:"trends,omitempty"`
+}
+
+// TrendSample 趋势检查的单个样本。
+type TrendSample struct {
+	// Time 采样时间。
+	Time metav1.Time `json:"time"`
+	// Value 样本值（十进制数字字符串）。
+	Value string `json:"value"`
+}
+
+// TrendStatus 单个趋势检查的状态。
+type TrendStatus struct {
+	// Name 趋势检查名称。
+	Name string `json:"name"`
+	// Samples 最近的样本（最多 WindowSize 个）。
+	Samples []TrendSample `json:"samples,omitempty"`
+}
+
+// HTTPLoadStatus 内置 HTTP 负载的统计（汇总所有 worker）。
+type HTTPLoadStatus struct {
+	// LastUpdateTime 上次汇总时间。
+	LastUpdateTime *metav1.Time `json:"lastUpdateTime,omitempty"`
+	// Workers 成功上报统计的 worker 数。
+	Workers int32 `json:"workers,omitempty"`
+	// Completed 所有 worker 是否已完成施压（DurationSeconds 到期）。
+	Completed bool `json:"completed,omitempty"`
+	// Requests 已完成的请求数。
+	Requests int64 `json:"requests,omitempty"`
+	// Errors 失败的请求数（网络错误、超时或非 2xx/3xx 响应）。
+	Errors int64 `json:"errors,omitempty"`
+	// Dropped 并发达到上限而未发出的请求数，不计入 Requests 与延迟分位数；持续增长说明目标已过载。
+	Dropped int64 `json:"dropped,omitempty"`
+	// ErrorRate 错误率（十进制数字字符串，如 "0.012"）。
+	ErrorRate string `json:"errorRate,omitempty"`
+	// LatencyP50Ms 延迟 P50（毫秒，按直方图桶上界估算）。
+	LatencyP50Ms int64 `json:"latencyP50Ms,omitempty"`
+	// LatencyP95Ms 延迟 P95（毫秒）。
+	LatencyP95Ms int64 `json:"latencyP95Ms,omitempty"`
+	// LatencyP99Ms 延迟 P99（毫秒）。
+	LatencyP99Ms int64 `json:"latencyP99Ms,omitempty"`
+}
+
+// PostVerificationStatus 负载结束后验证的状态（镜像子 IntegrationTest 的状态）。
+type PostVerificationStatus struct {
+	// TestName 执行验证的 IntegrationTest 名称。
+	TestName string `json:"testName,omitempty"`
+	// Phase 验证阶段。
+	Phase IntegrationTestPhase `json:"phase,omitempty"`
+	// Message 验证消息（失败原因）。
+	Message string `json:"message,omitempty"`
+	// Steps 验证步骤状态。
+	// +optional
+	Steps []StepStatus `json:"steps,omitempty"`
+}
+
+// LoadControlStatus 闭环负载控制状态。
+type LoadControlStatus struct {
+	// LastSampleTime 最后一次采样时间。
+	LastSampleTime *metav1.Time `json:"lastSampleTime,omitempty"`
+	// LastMetric 最后一次采样的指标值（十进制数字字符串）。
+	LastMetric string `json:"lastMetric,omitempty"`
+	// CurrentValue 参数当前值。
+	CurrentValue int32 `json:"currentValue,omitempty"`
+	// Adjustments 已调整次数。
+	Adjustments int32 `json:"adjustments,omitempty"`
+	// StableCount 连续落在允许偏差内的采样次数。
+	StableCount int32 `json:"stableCount,omitempty"`
+	// Converged 是否已收敛。
+	Converged bool `json:"converged,omitempty"`
+	// ConvergedValue 收敛时的参数值。
+	ConvergedValue int32 `json:"convergedValue,omitempty"`
+	// Message 最近一次采样或调整的说明（如触及上下限、查询失败）。
+	Message string `json:"message,omitempty"`
+}
+
+// DriftStatus Target 漂移检测状态。
+type DriftStatus struct {
+	// LastCheckTime 最后一次检测时间。
+	LastCheckTime *metav1.Time `json:"lastCheckTime,omitempty"`
+	// Drifted 最近一次检测时 Target 是否与模板不一致。
+	Drifted bool `json:"drifted,omitempty"`
+	// Fields 不一致的字段路径（最多 10 个）。
+	// +optional
+	Fields []string `json:"fields,omitempty"`
+	// DetectedCount 检测到漂移的次数（持续漂移只计一次）。
+	DetectedCount int32 `json:"detectedCount,omitempty"`
+	// ReappliedCount 因漂移重新应用模板的次数。
+	ReappliedCount int32 `json:"reappliedCount,omitempty"`
 }
 
 // LoadTestStatus 记录负载测试状态。
@@ -131,16 +543,42 @@ type LoadTestStatus struct {
 	Message string `json:"message,omitempty"`
 	// StartTime 开始时间。
 	StartTime *metav1.Time `json:"startTime,omitempty"`
+	// RunStartTime 进入 Running 的时间，DurationSeconds 从此时开始计算。
+	RunStartTime *metav1.Time `json:"runStartTime,omitempty"`
 	// CompletionTime 完成时间。
 	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
 	// InjectedValues 已注入的值（便于调试）。
 	InjectedValues map[string]string `json:"injectedValues,omitempty"`
+	// TouchedKinds 测试涉及的资源类型（Kind.group），初始化时由 target 与 workload 清单推导，用于测试影响分析。
+	// +optional
+	TouchedKinds []string `json:"touchedKinds,omitempty"`
+	// TargetSpecHash 最近一次 apply 的 target 模板规范化 hash。
+	// +optional
+	TargetSpecHash string `json:"targetSpecHash,omitempty"`
+	// WorkloadSpecHash 最近一次 apply 的 workload（含注入值）规范化 hash，未变化时 spec 变更不重新部署。
+	// +optional
+	WorkloadSpecHash string `json:"workloadSpecHash,omitempty"`
 	// ReadyConditionStatus 就绪条件检查状态。
 	ReadyConditionStatus *ReadyConditionStatus `json:"readyConditionStatus,omitempty"`
 	// HealthCheckStatus 健康检查状态。
 	HealthCheckStatus *HealthCheckStatus `json:"healthCheckStatus,omitempty"`
+	// HTTPLoad 内置 HTTP 负载统计。
+	HTTPLoad *HTTPLoadStatus `json:"httpLoad,omitempty"`
+	// Drift Target 漂移检测状态。
+	Drift *DriftStatus `json:"drift,omitempty"`
+	// LoadControl 闭环负载控制状态。
+	LoadControl *LoadControlStatus `json:"loadControl,omitempty"`
+	// PostVerification 负载结束后验证的状态。
+	PostVerification *PostVerificationStatus `json:"postVerification,omitempty"`
+	// Triage 失败分诊结果。
+	Triage *TriageStatus `json:"triage,omitempty"`
+	// Artifacts 测试结束时收集的工件。
+	// +optional
+	Artifacts []ArtifactStatus `json:"artifacts,omitempty"`
 	// ObservedGeneration 已观察的 Generation。
 	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// RerunToken 已处理的重跑令牌（infra.testplane.io/rerun 注解的值）。
+	RerunToken string `json:"rerunToken,omitempty"`
 	// Conditions 条件列表。
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
@@ -154,6 +592,7 @@ type LoadTestStatus struct {
 // +kubebuilder:printcolumn:name="Reason",type=string,JSONPath=`.status.reason`,priority=1
 // +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
 // +kubebuilder:resource:shortName=lt
+// +kubebuilder:storageversion
 
 // LoadTest 表示一个负载测试。
 type LoadTest struct {
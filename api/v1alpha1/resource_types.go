@@ -20,6 +20,11 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 )
 
+// LabelTouchedKindPrefix 测试涉及的资源类型标签前缀：控制器初始化测试时为每个涉及的资源类型
+// 设置 touches.infra.testplane.io/<Kind.group>: "true"（如 touches.infra.testplane.io/Cluster.infra.example.io），
+// CI 可按标签选择涉及某个 Operator 资源的测试。
+const LabelTouchedKindPrefix = "touches.infra.testplane.io/"
+
 // TemplateAction 定义资源操作类型。
 // +kubebuilder:validation:Enum=Apply;Delete
 type TemplateAction string
@@ -29,6 +34,17 @@ const (
 	TemplateActionDelete TemplateAction = "Delete"
 )
 
+// ConvergenceMode 定义 apply 后资源收敛的判定方式。
+// +kubebuilder:validation:Enum=Generation;None
+type ConvergenceMode string
+
+const (
+	// ConvergenceGeneration 等待控制器处理最新 spec（status.observedGeneration >= metadata.generation）。
+	ConvergenceGeneration ConvergenceMode = "Generation"
+	// ConvergenceNone 不等待控制器处理，资源存在即视为收敛（适用于 ConfigMap、Secret 等无 status 的资源）。
+	ConvergenceNone ConvergenceMode = "None"
+)
+
 // ResourceSelector 资源选择器（只读引用）。
 // 支持三种互斥的选择方式：
 // 1. Name：按名称精确选择单个资源
@@ -50,12 +66,19 @@ type ResourceSelector struct {
 }
 
 // ResourceRef 单资源引用（扁平化）。
-// Manifest 和 Selector 互斥，指定其中一个。
+// Manifest、Base 和 Selector 互斥，指定其中一个。
 type ResourceRef struct {
-	// Manifest K8s 资源清单（与 Selector 互斥）。
+	// Manifest K8s 资源清单（与 Selector、Base 互斥）。
 	// +kubebuilder:pruning:PreserveUnknownFields
 	// +optional
 	Manifest runtime.RawExtension `json:"manifest,omitempty"`
+	// Base 引用的基础清单（与 Manifest、Selector 互斥），读取后按 Patches 修改作为 Manifest 使用，
+	// 多个测试共用大型清单时只需声明差异。
+	// +optional
+	Base *ManifestBase `json:"base,omitempty"`
+	// Patches 依次应用到 Manifest 或 Base 的 JSON Patch（RFC 6902）操作。
+	// +optional
+	Patches []JSONPatchOperation `json:"patches,omitempty"`
 	// Selector 资源选择器（与 Manifest 互斥）。
 	// +optional
 	Selector *ResourceSelector `json:"selector,omitempty"`
@@ -63,4 +86,44 @@ type ResourceRef struct {
 	// +kubebuilder:default=Apply
 	// +optional
 	Action TemplateAction `json:"action,omitempty"`
+	// Convergence 收敛判定方式（仅 Manifest 有效）。
+	// 为空时按 Kind 自动选择：ConfigMap、Secret 等无 status 的内置资源为 None，其他为 Generation。
+	// +optional
+	Convergence ConvergenceMode `json:"convergence,omitempty"`
+}
+
+// ManifestBase 基础清单的来源。
+type ManifestBase struct {
+	// ConfigMapRef 存放基础清单（YAML 或 JSON，单个对象或 List）的 ConfigMap 键，ConfigMap 与测试同命名空间。
+	ConfigMapRef ConfigMapKeyReference `json:"configMapRef"`
+}
+
+// JSONPatchOperation 一个 JSON Patch（RFC 6902）操作。
+type JSONPatchOperation struct {
+	// Op 操作类型。
+	// +kubebuilder:validation:Enum=add;remove;replace;move;copy;test
+	Op string `json:"op"`
+	// Path 目标位置的 JSON Pointer（如 /spec/replicas、/metadata/labels/tier）。
+	Path string `json:"path"`
+	// From move、copy 的来源位置。
+	// +optional
+	From string `json:"from,omitempty"`
+	// Value add、replace、test 使用的值，可以是任意 JSON 类型。
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +optional
+	Value *runtime.RawExtension `json:"value,omitempty"`
+}
+
+// CleanupSpec 测试删除时清理所建资源的配置。
+// 控制器按创建的相反顺序逐个删除（依赖方先于其 owner），等前一个资源删除完成再删下一个，
+// 避免 workload 与 target、CR 与其 Operator 同时删除时 finalizer 互相等待导致命名空间卡在 Terminating。
+type CleanupSpec struct {
+	// GraceSeconds 按 Kind 配置的等待时长（秒）：资源删除超过该时长仍未完成时不再等待，交给 GC 继续删除后续资源。
+	// +optional
+	GraceSeconds map[string]int32 `json:"graceSeconds,omitempty"`
+	// DefaultGraceSeconds 未在 graceSeconds 中列出的 Kind 的等待时长（秒），默认 30。为 0 时只发起删除、不等待。
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	DefaultGraceSeconds *int32 `json:"defaultGraceSeconds,omitempty"`
 }
@@ -0,0 +1,165 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// LabelScheduledTest 标记 ScheduledTest 创建的测试的标签，值为 ScheduledTest 名称。
+const LabelScheduledTest = "infra.testplane.io/scheduled-test"
+
+// AnnotationScheduledTime ScheduledTest 创建的测试对应的计划触发时间（RFC3339）。
+const AnnotationScheduledTime = "infra.testplane.io/scheduled-time"
+
+// ConcurrencyPolicy 上一次运行尚未结束时到达触发时间的处理方式。
+// +kubebuilder:validation:Enum=Allow;Forbid;Replace
+type ConcurrencyPolicy string
+
+const (
+	// ConcurrencyAllow 允许多次运行同时进行。
+	ConcurrencyAllow ConcurrencyPolicy = "Allow"
+	// ConcurrencyForbid 跳过本次触发，运行中的测试结束后（startingDeadlineSeconds 内）补跑。
+	ConcurrencyForbid ConcurrencyPolicy = "Forbid"
+	// ConcurrencyReplace 以 infra.testplane.io/abort 注解中止运行中的测试，并创建新的测试。
+	ConcurrencyReplace ConcurrencyPolicy = "Replace"
+)
+
+// ScheduledTestSpec 按 cron 周期性创建 IntegrationTest/LoadTest。
+type ScheduledTestSpec struct {
+	// Schedule 触发时间，标准 5 字段 cron 表达式（如 "0 2 * * *" 表示每天 02:00）。
+	// +kubebuilder:validation:MinLength=1
+	Schedule string `json:"schedule"`
+	// TimeZone 解析 Schedule 使用的 IANA 时区（如 Asia/Shanghai），默认 UTC。
+	// +optional
+	TimeZone string `json:"timeZone,omitempty"`
+	// StartingDeadlineSeconds 触发时间过后仍可开始运行的时长（秒）：控制器停机或 Forbid 跳过后超过该时长的触发视为错过。
+	// 未设置时不限制（只补跑最近的一次触发）。
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	StartingDeadlineSeconds *int64 `json:"startingDeadlineSeconds,omitempty"`
+	// ConcurrencyPolicy 上一次运行尚未结束时的处理方式，默认 Forbid（不允许重叠运行）。
+	// +kubebuilder:default=Forbid
+	// +optional
+	ConcurrencyPolicy ConcurrencyPolicy `json:"concurrencyPolicy,omitempty"`
+	// Suspend 暂停调度：不再创建新的测试，已创建的测试不受影响。
+	// +optional
+	Suspend bool `json:"suspend,omitempty"`
+	// SuccessfulRunsHistoryLimit 保留的成功测试数量，超出时删除最旧的，默认 3。
+	// +kubebuilder:default=3
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	SuccessfulRunsHistoryLimit *int32 `json:"successfulRunsHistoryLimit,omitempty"`
+	// FailedRunsHistoryLimit 保留的失败测试数量，超出时删除最旧的，默认 1。
+	// +kubebuilder:default=1
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	FailedRunsHistoryLimit *int32 `json:"failedRunsHistoryLimit,omitempty"`
+	// Template 每次触发创建的测试。
+	Template ScheduledTestTemplate `json:"template"`
+}
+
+// ScheduledTestTemplate 每次触发创建的测试：引用同命名空间的模板或内联 spec，二者必须且只能设置一个。
+type ScheduledTestTemplate struct {
+	// Kind 测试类型。
+	Kind TestSuiteItemKind `json:"kind"`
+	// TemplateRef 引用的模板测试（同命名空间，Kind 一致），每次触发时复制其最新的 spec、标签与注解。
+	// 模板通常带 infra.testplane.io/template=true 注解，避免自身被执行。
+	// +optional
+	TemplateRef *TestSuiteTemplateReference `json:"templateRef,omitempty"`
+	// Spec 内联的测试 spec，按 Kind 解析为 IntegrationTestSpec 或 LoadTestSpec。
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +optional
+	Spec *runtime.RawExtension `json:"spec,omitempty"`
+}
+
+// ScheduledTestRunState 运行状态。
+// +kubebuilder:validation:Enum=Active;Succeeded;Failed
+type ScheduledTestRunState string
+
+const (
+	ScheduledTestRunActive    ScheduledTestRunState = "Active"
+	ScheduledTestRunSucceeded ScheduledTestRunState = "Succeeded"
+	ScheduledTestRunFailed    ScheduledTestRunState = "Failed"
+)
+
+// ScheduledTestRun 一次运行（创建的测试）的摘要。
+type ScheduledTestRun struct {
+	// Name 创建的测试名称。
+	Name string `json:"name"`
+	// ScheduledTime 对应的触发时间。
+	ScheduledTime *metav1.Time `json:"scheduledTime,omitempty"`
+	// State 运行状态。
+	State ScheduledTestRunState `json:"state,omitempty"`
+	// Phase 测试最近一次观察到的阶段。
+	Phase string `json:"phase,omitempty"`
+	// Reason 测试结束原因。
+	Reason string `json:"reason,omitempty"`
+}
+
+// ScheduledTestStatus 定义 ScheduledTest 的观测状态。
+type ScheduledTestStatus struct {
+	// Runs 仍保留的运行（受 history limit 约束），按触发时间从新到旧排列。
+	// +optional
+	Runs []ScheduledTestRun `json:"runs,omitempty"`
+	// Active 运行中的测试数量。
+	Active int32 `json:"active,omitempty"`
+	// LastScheduleTime 最近一次处理的触发时间（创建测试或判定为错过）。
+	LastScheduleTime *metav1.Time `json:"lastScheduleTime,omitempty"`
+	// LastSuccessfulTime 最近一次成功运行的结束时间。
+	LastSuccessfulTime *metav1.Time `json:"lastSuccessfulTime,omitempty"`
+	// NextScheduleTime 下一次触发时间（暂停时为空）。
+	NextScheduleTime *metav1.Time `json:"nextScheduleTime,omitempty"`
+	// ObservedGeneration 最近一次调和的 Generation。
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// Conditions 条件列表（Ready 为 False 时 schedule 或 template 无效）。
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Schedule",type=string,JSONPath=`.spec.schedule`
+// +kubebuilder:printcolumn:name="Kind",type=string,JSONPath=`.spec.template.kind`
+// +kubebuilder:printcolumn:name="Suspend",type=boolean,JSONPath=`.spec.suspend`
+// +kubebuilder:printcolumn:name="Active",type=integer,JSONPath=`.status.active`
+// +kubebuilder:printcolumn:name="Last Schedule",type=date,JSONPath=`.status.lastScheduleTime`
+// +kubebuilder:printcolumn:name="Next Schedule",type=string,JSONPath=`.status.nextScheduleTime`,priority=1
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+// +kubebuilder:resource:shortName=stest
+
+// ScheduledTest 按 cron 周期性创建 IntegrationTest/LoadTest（如每晚的回归测试），并保留有限的运行历史。
+type ScheduledTest struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ScheduledTestSpec   `json:"spec,omitempty"`
+	Status ScheduledTestStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ScheduledTestList 包含多个 ScheduledTest。
+type ScheduledTestList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ScheduledTest `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ScheduledTest{}, &ScheduledTestList{})
+}
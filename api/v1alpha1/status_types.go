@@ -62,4 +62,22 @@ type ReadyConditionStatus struct {
 	FinishedAt *metav1.Time `json:"finishedAt,omitempty"`
 	// Results 期望结果。
 	Results []ExpectationResult `json:"results,omitempty"`
+	// SelectorDiagnostics 选择器未匹配到资源时的诊断信息。
+	SelectorDiagnostics *SelectorDiagnostics `json:"selectorDiagnostics,omitempty"`
+}
+
+// SelectorDiagnostics 记录选择器匹配诊断信息，用于排查选择器配置错误。
+type SelectorDiagnostics struct {
+	// Selector 选择器标识（apiVersion/kind[/name]）。
+	Selector string `json:"selector,omitempty"`
+	// Namespaces 已搜索的命名空间。
+	Namespaces []string `json:"namespaces,omitempty"`
+	// CandidateCount 同 Kind 的候选资源数量（不考虑名称/标签/注解）。
+	CandidateCount int `json:"candidateCount,omitempty"`
+	// MatchedCount 满足名称/标签/注解条件的资源数量。
+	MatchedCount int `json:"matchedCount,omitempty"`
+	// NearestMisses 最接近匹配但未命中的资源及原因（最多 5 条）。
+	NearestMisses []string `json:"nearestMisses,omitempty"`
+	// Message 诊断摘要。
+	Message string `json:"message,omitempty"`
 }
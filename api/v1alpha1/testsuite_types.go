@@ -0,0 +1,207 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// AnnotationTemplate 模板注解：值为 "true" 的 IntegrationTest/LoadTest 只作为 TestSuite 的模板，控制器不执行。
+// TestSuite 从模板创建的测试不带该注解。
+const AnnotationTemplate = "infra.testplane.io/template"
+
+// 标记 TestSuite 创建的测试的标签。
+const (
+	// LabelSuite 所属 TestSuite 名称。
+	LabelSuite = "infra.testplane.io/suite"
+	// LabelSuiteItem 对应的 TestSuite 条目名称。
+	LabelSuiteItem = "infra.testplane.io/suite-item"
+)
+
+// TestSuiteMode 条目执行方式。
+// +kubebuilder:validation:Enum=Sequential;Parallel
+type TestSuiteMode string
+
+const (
+	// TestSuiteSequential 按 items 顺序逐个执行。
+	TestSuiteSequential TestSuiteMode = "Sequential"
+	// TestSuiteParallel 并行执行，同时运行的条目数受 maxConcurrency 限制。
+	TestSuiteParallel TestSuiteMode = "Parallel"
+)
+
+// TestSuiteItemKind 条目的测试类型。
+// +kubebuilder:validation:Enum=IntegrationTest;LoadTest
+type TestSuiteItemKind string
+
+const (
+	TestSuiteItemIntegrationTest TestSuiteItemKind = "IntegrationTest"
+	TestSuiteItemLoadTest        TestSuiteItemKind = "LoadTest"
+)
+
+// TestSuiteSpec 定义一组按顺序或并行执行的 IntegrationTest/LoadTest。
+type TestSuiteSpec struct {
+	// Mode 执行方式，默认 Sequential。
+	// +kubebuilder:default=Sequential
+	// +optional
+	Mode TestSuiteMode `json:"mode,omitempty"`
+	// MaxConcurrency Parallel 模式下同时运行的条目数上限，为 0 时不限制。
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MaxConcurrency int32 `json:"maxConcurrency,omitempty"`
+	// Items 套件条目，每个条目创建一个名为 <suite>-<item> 的测试。
+	// +listType=map
+	// +listMapKey=name
+	// +kubebuilder:validation:MinItems=1
+	Items []TestSuiteItem `json:"items"`
+	// TimeoutSeconds 套件整体超时（秒），超时后中止运行中的条目、跳过未开始的条目并以 Timeout 失败。为 0 时不限制。
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	TimeoutSeconds int32 `json:"timeoutSeconds,omitempty"`
+	// FailFast 任一条目失败时中止运行中的条目并跳过未开始的条目。
+	// +optional
+	FailFast bool `json:"failFast,omitempty"`
+}
+
+// TestSuiteItem 套件中的一个测试：引用同命名空间的模板或内联 spec，二者必须且只能设置一个。
+type TestSuiteItem struct {
+	// Name 条目名称（套件内唯一）。
+	Name string `json:"name"`
+	// Kind 测试类型。
+	Kind TestSuiteItemKind `json:"kind"`
+	// TemplateRef 引用的模板测试（同命名空间，Kind 与条目一致），复制其 spec、标签与注解。
+	// 模板通常带 infra.testplane.io/template=true 注解，避免自身被执行。
+	// +optional
+	TemplateRef *TestSuiteTemplateReference `json:"templateRef,omitempty"`
+	// Spec 内联的测试 spec，按 Kind 解析为 IntegrationTestSpec 或 LoadTestSpec。
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +optional
+	Spec *runtime.RawExtension `json:"spec,omitempty"`
+}
+
+// TestSuiteTemplateReference 引用同命名空间的模板测试。
+type TestSuiteTemplateReference struct {
+	// Name 模板名称。
+	Name string `json:"name"`
+}
+
+// TestSuitePhase 定义 TestSuite 的阶段。
+// +kubebuilder:validation:Enum=Running;Succeeded;Failed
+type TestSuitePhase string
+
+const (
+	TestSuitePhaseRunning   TestSuitePhase = "Running"
+	TestSuitePhaseSucceeded TestSuitePhase = "Succeeded"
+	TestSuitePhaseFailed    TestSuitePhase = "Failed"
+)
+
+// TestSuiteItemState 条目状态。
+// +kubebuilder:validation:Enum=Pending;Running;Succeeded;Failed;Skipped
+type TestSuiteItemState string
+
+const (
+	TestSuiteItemPending   TestSuiteItemState = "Pending"
+	TestSuiteItemRunning   TestSuiteItemState = "Running"
+	TestSuiteItemSucceeded TestSuiteItemState = "Succeeded"
+	TestSuiteItemFailed    TestSuiteItemState = "Failed"
+	TestSuiteItemSkipped   TestSuiteItemState = "Skipped"
+)
+
+// TestSuiteItemStatus 条目的执行状态。
+type TestSuiteItemStatus struct {
+	// Name 条目名称。
+	Name string `json:"name"`
+	// Kind 测试类型。
+	Kind TestSuiteItemKind `json:"kind,omitempty"`
+	// TestName 创建的测试名称。
+	TestName string `json:"testName,omitempty"`
+	// State 条目状态。
+	State TestSuiteItemState `json:"state,omitempty"`
+	// Phase 创建的测试最近一次观察到的阶段。
+	Phase string `json:"phase,omitempty"`
+	// Reason 测试结束原因（或跳过原因）。
+	Reason string `json:"reason,omitempty"`
+	// Message 详细消息。
+	Message string `json:"message,omitempty"`
+	// StartedAt 开始时间。
+	StartedAt *metav1.Time `json:"startedAt,omitempty"`
+	// FinishedAt 结束时间。
+	FinishedAt *metav1.Time `json:"finishedAt,omitempty"`
+}
+
+// TestSuiteStatus 定义 TestSuite 的观测状态。
+type TestSuiteStatus struct {
+	// Phase 当前阶段。
+	Phase TestSuitePhase `json:"phase,omitempty"`
+	// Reason 阶段原因。
+	Reason string `json:"reason,omitempty"`
+	// Message 详细消息。
+	Message string `json:"message,omitempty"`
+	// StartedAt 开始时间。
+	StartedAt *metav1.Time `json:"startedAt,omitempty"`
+	// Deadline 套件截止时间（设置 timeoutSeconds 时）。
+	Deadline *metav1.Time `json:"deadline,omitempty"`
+	// CompletionTime 结束时间。
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+	// Items 条目状态，与 spec.items 顺序一致。
+	// +listType=map
+	// +listMapKey=name
+	Items []TestSuiteItemStatus `json:"items,omitempty"`
+	// Succeeded 成功的条目数。
+	Succeeded int32 `json:"succeeded,omitempty"`
+	// Failed 失败的条目数。
+	Failed int32 `json:"failed,omitempty"`
+	// Skipped 跳过的条目数。
+	Skipped int32 `json:"skipped,omitempty"`
+	// ObservedGeneration 开始执行时的 Generation，之后的 spec 变更不生效。
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// Conditions 条件列表。
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
+// +kubebuilder:printcolumn:name="Mode",type=string,JSONPath=`.spec.mode`
+// +kubebuilder:printcolumn:name="Succeeded",type=integer,JSONPath=`.status.succeeded`
+// +kubebuilder:printcolumn:name="Failed",type=integer,JSONPath=`.status.failed`
+// +kubebuilder:printcolumn:name="Skipped",type=integer,JSONPath=`.status.skipped`,priority=1
+// +kubebuilder:printcolumn:name="Reason",type=string,JSONPath=`.status.reason`,priority=1
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+// +kubebuilder:resource:shortName=ts
+
+// TestSuite 编排一组 IntegrationTest/LoadTest 并汇总结果。
+type TestSuite struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   TestSuiteSpec   `json:"spec,omitempty"`
+	Status TestSuiteStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// TestSuiteList 包含多个 TestSuite。
+type TestSuiteList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []TestSuite `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&TestSuite{}, &TestSuiteList{})
+}
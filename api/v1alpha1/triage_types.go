@@ -0,0 +1,64 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// TriageSpec 失败分诊配置：测试以 Failed 结束时调用分诊 Webhook，
+// 将结构化的失败上下文交给外部服务（如缺陷跟踪系统）识别已知问题。
+type TriageSpec struct {
+	// Webhook 分诊服务地址，以 POST 发送失败上下文。
+	Webhook string `json:"webhook"`
+	// EvidenceLinks 随请求传递的证据链接（如 Grafana 面板、日志查询），名称到 URL。
+	// +optional
+	EvidenceLinks map[string]string `json:"evidenceLinks,omitempty"`
+	// MaxAttempts 调用失败时的最大尝试次数，默认 3。
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MaxAttempts int32 `json:"maxAttempts,omitempty"`
+}
+
+// TriageState 分诊状态。
+// +kubebuilder:validation:Enum=Succeeded;Failed
+type TriageState string
+
+const (
+	// TriageStateSucceeded 分诊服务已返回结果。
+	TriageStateSucceeded TriageState = "Succeeded"
+	// TriageStateFailed 分诊调用失败（达到最大尝试次数前会重试）。
+	TriageStateFailed TriageState = "Failed"
+)
+
+// TriageStatus 记录分诊 Webhook 的调用结果。
+type TriageStatus struct {
+	// State 分诊状态。
+	State TriageState `json:"state,omitempty"`
+	// Attempts 已尝试次数。
+	Attempts int32 `json:"attempts,omitempty"`
+	// LastAttemptTime 最近一次调用时间。
+	LastAttemptTime *metav1.Time `json:"lastAttemptTime,omitempty"`
+	// KnownIssue 分诊服务是否判定为已知问题。
+	KnownIssue bool `json:"knownIssue,omitempty"`
+	// Ticket 分诊服务返回的问题单号（如 JIRA-123）。
+	Ticket string `json:"ticket,omitempty"`
+	// URL 分诊服务返回的问题链接。
+	URL string `json:"url,omitempty"`
+	// Message 分诊服务返回的说明或调用失败原因。
+	Message string `json:"message,omitempty"`
+}
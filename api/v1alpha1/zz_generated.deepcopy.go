@@ -26,139 +26,143 @@ import (
 )
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *EnvInjection) DeepCopyInto(out *EnvInjection) {
+func (in *ApplyLedgerEntry) DeepCopyInto(out *ApplyLedgerEntry) {
 	*out = *in
-	in.Extract.DeepCopyInto(&out.Extract)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EnvInjection.
-func (in *EnvInjection) DeepCopy() *EnvInjection {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApplyLedgerEntry.
+func (in *ApplyLedgerEntry) DeepCopy() *ApplyLedgerEntry {
 	if in == nil {
 		return nil
 	}
-	out := new(EnvInjection)
+	out := new(ApplyLedgerEntry)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Expectation) DeepCopyInto(out *Expectation) {
+func (in *ArtifactSpec) DeepCopyInto(out *ArtifactSpec) {
 	*out = *in
-	in.Params.DeepCopyInto(&out.Params)
+	if in.PodSelector != nil {
+		in, out := &in.PodSelector, &out.PodSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Paths != nil {
+		in, out := &in.Paths, &out.Paths
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Expectation.
-func (in *Expectation) DeepCopy() *Expectation {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArtifactSpec.
+func (in *ArtifactSpec) DeepCopy() *ArtifactSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(Expectation)
+	out := new(ArtifactSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ExpectationResult) DeepCopyInto(out *ExpectationResult) {
+func (in *ArtifactStatus) DeepCopyInto(out *ArtifactStatus) {
 	*out = *in
-	in.Params.DeepCopyInto(&out.Params)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExpectationResult.
-func (in *ExpectationResult) DeepCopy() *ExpectationResult {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArtifactStatus.
+func (in *ArtifactStatus) DeepCopy() *ArtifactStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(ExpectationResult)
+	out := new(ArtifactStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ExpectationResultSummary) DeepCopyInto(out *ExpectationResultSummary) {
+func (in *BreakpointStatus) DeepCopyInto(out *BreakpointStatus) {
 	*out = *in
+	if in.At != nil {
+		in, out := &in.At, &out.At
+		*out = (*in).DeepCopy()
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExpectationResultSummary.
-func (in *ExpectationResultSummary) DeepCopy() *ExpectationResultSummary {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BreakpointStatus.
+func (in *BreakpointStatus) DeepCopy() *BreakpointStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(ExpectationResultSummary)
+	out := new(BreakpointStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Extractor) DeepCopyInto(out *Extractor) {
+func (in *BudgetUsage) DeepCopyInto(out *BudgetUsage) {
 	*out = *in
-	in.Params.DeepCopyInto(&out.Params)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Extractor.
-func (in *Extractor) DeepCopy() *Extractor {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BudgetUsage.
+func (in *BudgetUsage) DeepCopy() *BudgetUsage {
 	if in == nil {
 		return nil
 	}
-	out := new(Extractor)
+	out := new(BudgetUsage)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *HealthCheck) DeepCopyInto(out *HealthCheck) {
+func (in *CanaryMetric) DeepCopyInto(out *CanaryMetric) {
 	*out = *in
-	if in.AllOf != nil {
-		in, out := &in.AllOf, &out.AllOf
-		*out = make([]Expectation, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
-	if in.AnyOf != nil {
-		in, out := &in.AnyOf, &out.AnyOf
-		*out = make([]Expectation, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	in.Extract.DeepCopyInto(&out.Extract)
+	if in.MaxDeviationPercent != nil {
+		in, out := &in.MaxDeviationPercent, &out.MaxDeviationPercent
+		*out = new(int32)
+		**out = **in
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HealthCheck.
-func (in *HealthCheck) DeepCopy() *HealthCheck {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CanaryMetric.
+func (in *CanaryMetric) DeepCopy() *CanaryMetric {
 	if in == nil {
 		return nil
 	}
-	out := new(HealthCheck)
+	out := new(CanaryMetric)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *HealthCheckStatus) DeepCopyInto(out *HealthCheckStatus) {
+func (in *CanarySpec) DeepCopyInto(out *CanarySpec) {
 	*out = *in
-	if in.LastCheckTime != nil {
-		in, out := &in.LastCheckTime, &out.LastCheckTime
-		*out = (*in).DeepCopy()
-	}
-	if in.LastResults != nil {
-		in, out := &in.LastResults, &out.LastResults
-		*out = make([]ExpectationResultSummary, len(*in))
-		copy(*out, *in)
+	in.Baseline.DeepCopyInto(&out.Baseline)
+	in.Canary.DeepCopyInto(&out.Canary)
+	if in.Metrics != nil {
+		in, out := &in.Metrics, &out.Metrics
+		*out = make([]CanaryMetric, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HealthCheckStatus.
-func (in *HealthCheckStatus) DeepCopy() *HealthCheckStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CanarySpec.
+func (in *CanarySpec) DeepCopy() *CanarySpec {
 	if in == nil {
 		return nil
 	}
-	out := new(HealthCheckStatus)
+	out := new(CanarySpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *IntegrationTest) DeepCopyInto(out *IntegrationTest) {
+func (in *Check) DeepCopyInto(out *Check) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
@@ -166,18 +170,18 @@ func (in *IntegrationTest) DeepCopyInto(out *IntegrationTest) {
 	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IntegrationTest.
-func (in *IntegrationTest) DeepCopy() *IntegrationTest {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Check.
+func (in *Check) DeepCopy() *Check {
 	if in == nil {
 		return nil
 	}
-	out := new(IntegrationTest)
+	out := new(Check)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *IntegrationTest) DeepCopyObject() runtime.Object {
+func (in *Check) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -185,31 +189,31 @@ func (in *IntegrationTest) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *IntegrationTestList) DeepCopyInto(out *IntegrationTestList) {
+func (in *CheckList) DeepCopyInto(out *CheckList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]IntegrationTest, len(*in))
+		*out = make([]Check, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IntegrationTestList.
-func (in *IntegrationTestList) DeepCopy() *IntegrationTestList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CheckList.
+func (in *CheckList) DeepCopy() *CheckList {
 	if in == nil {
 		return nil
 	}
-	out := new(IntegrationTestList)
+	out := new(CheckList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *IntegrationTestList) DeepCopyObject() runtime.Object {
+func (in *CheckList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -217,55 +221,65 @@ func (in *IntegrationTestList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *IntegrationTestSpec) DeepCopyInto(out *IntegrationTestSpec) {
+func (in *CheckSpec) DeepCopyInto(out *CheckSpec) {
 	*out = *in
-	if in.Steps != nil {
-		in, out := &in.Steps, &out.Steps
-		*out = make([]TestStep, len(*in))
+	in.Target.DeepCopyInto(&out.Target)
+	in.Condition.DeepCopyInto(&out.Condition)
+	if in.Exporters != nil {
+		in, out := &in.Exporters, &out.Exporters
+		*out = make([]ExporterSpec, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
-	if in.Repeat != nil {
-		in, out := &in.Repeat, &out.Repeat
-		*out = new(RepeatConfig)
-		**out = **in
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IntegrationTestSpec.
-func (in *IntegrationTestSpec) DeepCopy() *IntegrationTestSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CheckSpec.
+func (in *CheckSpec) DeepCopy() *CheckSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(IntegrationTestSpec)
+	out := new(CheckSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *IntegrationTestStatus) DeepCopyInto(out *IntegrationTestStatus) {
+func (in *CheckStatus) DeepCopyInto(out *CheckStatus) {
 	*out = *in
-	if in.StartTime != nil {
-		in, out := &in.StartTime, &out.StartTime
+	if in.StartedAt != nil {
+		in, out := &in.StartedAt, &out.StartedAt
 		*out = (*in).DeepCopy()
 	}
-	if in.CompletionTime != nil {
-		in, out := &in.CompletionTime, &out.CompletionTime
+	if in.Deadline != nil {
+		in, out := &in.Deadline, &out.Deadline
 		*out = (*in).DeepCopy()
 	}
-	if in.CurrentStepIndex != nil {
-		in, out := &in.CurrentStepIndex, &out.CurrentStepIndex
-		*out = new(int)
-		**out = **in
+	if in.LastCheckTime != nil {
+		in, out := &in.LastCheckTime, &out.LastCheckTime
+		*out = (*in).DeepCopy()
 	}
-	if in.Steps != nil {
-		in, out := &in.Steps, &out.Steps
-		*out = make([]StepStatus, len(*in))
+	if in.LastCompletionTime != nil {
+		in, out := &in.LastCompletionTime, &out.LastCompletionTime
+		*out = (*in).DeepCopy()
+	}
+	if in.MatchedResources != nil {
+		in, out := &in.MatchedResources, &out.MatchedResources
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Results != nil {
+		in, out := &in.Results, &out.Results
+		*out = make([]ExpectationResult, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.SelectorDiagnostics != nil {
+		in, out := &in.SelectorDiagnostics, &out.SelectorDiagnostics
+		*out = new(SelectorDiagnostics)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
 		*out = make([]v1.Condition, len(*in))
@@ -275,303 +289,2081 @@ func (in *IntegrationTestStatus) DeepCopyInto(out *IntegrationTestStatus) {
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IntegrationTestStatus.
-func (in *IntegrationTestStatus) DeepCopy() *IntegrationTestStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CheckStatus.
+func (in *CheckStatus) DeepCopy() *CheckStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(IntegrationTestStatus)
+	out := new(CheckStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *LoadTest) DeepCopyInto(out *LoadTest) {
+func (in *CleanupSpec) DeepCopyInto(out *CleanupSpec) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	in.Spec.DeepCopyInto(&out.Spec)
-	in.Status.DeepCopyInto(&out.Status)
+	if in.GraceSeconds != nil {
+		in, out := &in.GraceSeconds, &out.GraceSeconds
+		*out = make(map[string]int32, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.DefaultGraceSeconds != nil {
+		in, out := &in.DefaultGraceSeconds, &out.DefaultGraceSeconds
+		*out = new(int32)
+		**out = **in
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LoadTest.
-func (in *LoadTest) DeepCopy() *LoadTest {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CleanupSpec.
+func (in *CleanupSpec) DeepCopy() *CleanupSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(LoadTest)
+	out := new(CleanupSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *LoadTest) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *LoadTestList) DeepCopyInto(out *LoadTestList) {
+func (in *CleanupStatus) DeepCopyInto(out *CleanupStatus) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ListMeta.DeepCopyInto(&out.ListMeta)
-	if in.Items != nil {
-		in, out := &in.Items, &out.Items
-		*out = make([]LoadTest, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	if in.FinishedAt != nil {
+		in, out := &in.FinishedAt, &out.FinishedAt
+		*out = (*in).DeepCopy()
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LoadTestList.
-func (in *LoadTestList) DeepCopy() *LoadTestList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CleanupStatus.
+func (in *CleanupStatus) DeepCopy() *CleanupStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(LoadTestList)
+	out := new(CleanupStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *LoadTestList) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *LoadTestSpec) DeepCopyInto(out *LoadTestSpec) {
+func (in *ConfigMapKeyReference) DeepCopyInto(out *ConfigMapKeyReference) {
 	*out = *in
-	in.Target.DeepCopyInto(&out.Target)
-	in.Workload.DeepCopyInto(&out.Workload)
-	if in.HealthCheck != nil {
-		in, out := &in.HealthCheck, &out.HealthCheck
-		*out = new(HealthCheck)
-		(*in).DeepCopyInto(*out)
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LoadTestSpec.
-func (in *LoadTestSpec) DeepCopy() *LoadTestSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigMapKeyReference.
+func (in *ConfigMapKeyReference) DeepCopy() *ConfigMapKeyReference {
 	if in == nil {
 		return nil
 	}
-	out := new(LoadTestSpec)
+	out := new(ConfigMapKeyReference)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *LoadTestStatus) DeepCopyInto(out *LoadTestStatus) {
+func (in *DebugSpec) DeepCopyInto(out *DebugSpec) {
 	*out = *in
-	if in.StartTime != nil {
-		in, out := &in.StartTime, &out.StartTime
-		*out = (*in).DeepCopy()
-	}
-	if in.CompletionTime != nil {
-		in, out := &in.CompletionTime, &out.CompletionTime
-		*out = (*in).DeepCopy()
-	}
-	if in.InjectedValues != nil {
-		in, out := &in.InjectedValues, &out.InjectedValues
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
-	}
-	if in.ReadyConditionStatus != nil {
-		in, out := &in.ReadyConditionStatus, &out.ReadyConditionStatus
-		*out = new(ReadyConditionStatus)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.HealthCheckStatus != nil {
-		in, out := &in.HealthCheckStatus, &out.HealthCheckStatus
-		*out = new(HealthCheckStatus)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.Conditions != nil {
-		in, out := &in.Conditions, &out.Conditions
-		*out = make([]v1.Condition, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LoadTestStatus.
-func (in *LoadTestStatus) DeepCopy() *LoadTestStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DebugSpec.
+func (in *DebugSpec) DeepCopy() *DebugSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(LoadTestStatus)
+	out := new(DebugSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ReadyCondition) DeepCopyInto(out *ReadyCondition) {
+func (in *DriftDetection) DeepCopyInto(out *DriftDetection) {
 	*out = *in
-	if in.AllOf != nil {
-		in, out := &in.AllOf, &out.AllOf
-		*out = make([]Expectation, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
-	if in.AnyOf != nil {
-		in, out := &in.AnyOf, &out.AnyOf
-		*out = make([]Expectation, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReadyCondition.
-func (in *ReadyCondition) DeepCopy() *ReadyCondition {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DriftDetection.
+func (in *DriftDetection) DeepCopy() *DriftDetection {
 	if in == nil {
 		return nil
 	}
-	out := new(ReadyCondition)
+	out := new(DriftDetection)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ReadyConditionStatus) DeepCopyInto(out *ReadyConditionStatus) {
+func (in *DriftStatus) DeepCopyInto(out *DriftStatus) {
 	*out = *in
-	if in.StartedAt != nil {
-		in, out := &in.StartedAt, &out.StartedAt
-		*out = (*in).DeepCopy()
-	}
-	if in.Deadline != nil {
-		in, out := &in.Deadline, &out.Deadline
-		*out = (*in).DeepCopy()
-	}
-	if in.FinishedAt != nil {
-		in, out := &in.FinishedAt, &out.FinishedAt
+	if in.LastCheckTime != nil {
+		in, out := &in.LastCheckTime, &out.LastCheckTime
 		*out = (*in).DeepCopy()
 	}
-	if in.Results != nil {
-		in, out := &in.Results, &out.Results
-		*out = make([]ExpectationResult, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	if in.Fields != nil {
+		in, out := &in.Fields, &out.Fields
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReadyConditionStatus.
-func (in *ReadyConditionStatus) DeepCopy() *ReadyConditionStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DriftStatus.
+func (in *DriftStatus) DeepCopy() *DriftStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(ReadyConditionStatus)
+	out := new(DriftStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *RepeatConfig) DeepCopyInto(out *RepeatConfig) {
+func (in *EnvInjection) DeepCopyInto(out *EnvInjection) {
+	*out = *in
+	in.Extract.DeepCopyInto(&out.Extract)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EnvInjection.
+func (in *EnvInjection) DeepCopy() *EnvInjection {
+	if in == nil {
+		return nil
+	}
+	out := new(EnvInjection)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Environment) DeepCopyInto(out *Environment) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Environment.
+func (in *Environment) DeepCopy() *Environment {
+	if in == nil {
+		return nil
+	}
+	out := new(Environment)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Environment) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EnvironmentList) DeepCopyInto(out *EnvironmentList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Environment, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EnvironmentList.
+func (in *EnvironmentList) DeepCopy() *EnvironmentList {
+	if in == nil {
+		return nil
+	}
+	out := new(EnvironmentList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *EnvironmentList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EnvironmentReference) DeepCopyInto(out *EnvironmentReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EnvironmentReference.
+func (in *EnvironmentReference) DeepCopy() *EnvironmentReference {
+	if in == nil {
+		return nil
+	}
+	out := new(EnvironmentReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EnvironmentSpec) DeepCopyInto(out *EnvironmentSpec) {
+	*out = *in
+	if in.KubeconfigSecretRef != nil {
+		in, out := &in.KubeconfigSecretRef, &out.KubeconfigSecretRef
+		*out = new(SecretKeyReference)
+		**out = **in
+	}
+	if in.BaseURLs != nil {
+		in, out := &in.BaseURLs, &out.BaseURLs
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Variables != nil {
+		in, out := &in.Variables, &out.Variables
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EnvironmentSpec.
+func (in *EnvironmentSpec) DeepCopy() *EnvironmentSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(EnvironmentSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Expectation) DeepCopyInto(out *Expectation) {
+	*out = *in
+	in.Params.DeepCopyInto(&out.Params)
+	if in.RelatedResources != nil {
+		in, out := &in.RelatedResources, &out.RelatedResources
+		*out = make([]RelatedResource, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Fields != nil {
+		in, out := &in.Fields, &out.Fields
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Expectation.
+func (in *Expectation) DeepCopy() *Expectation {
+	if in == nil {
+		return nil
+	}
+	out := new(Expectation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExpectationResult) DeepCopyInto(out *ExpectationResult) {
+	*out = *in
+	in.Params.DeepCopyInto(&out.Params)
+	if in.HeldSince != nil {
+		in, out := &in.HeldSince, &out.HeldSince
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExpectationResult.
+func (in *ExpectationResult) DeepCopy() *ExpectationResult {
+	if in == nil {
+		return nil
+	}
+	out := new(ExpectationResult)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExpectationResultSummary) DeepCopyInto(out *ExpectationResultSummary) {
+	*out = *in
+	if in.HeldSince != nil {
+		in, out := &in.HeldSince, &out.HeldSince
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExpectationResultSummary.
+func (in *ExpectationResultSummary) DeepCopy() *ExpectationResultSummary {
+	if in == nil {
+		return nil
+	}
+	out := new(ExpectationResultSummary)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExporterSpec) DeepCopyInto(out *ExporterSpec) {
+	*out = *in
+	if in.Headers != nil {
+		in, out := &in.Headers, &out.Headers
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.CredentialsSecretRef != nil {
+		in, out := &in.CredentialsSecretRef, &out.CredentialsSecretRef
+		*out = new(LocalSecretReference)
+		**out = **in
+	}
+	if in.Events != nil {
+		in, out := &in.Events, &out.Events
+		*out = make([]ExportEventType, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExporterSpec.
+func (in *ExporterSpec) DeepCopy() *ExporterSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ExporterSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Extractor) DeepCopyInto(out *Extractor) {
+	*out = *in
+	in.Params.DeepCopyInto(&out.Params)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Extractor.
+func (in *Extractor) DeepCopy() *Extractor {
+	if in == nil {
+		return nil
+	}
+	out := new(Extractor)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FailureCheck) DeepCopyInto(out *FailureCheck) {
+	*out = *in
+	in.At.DeepCopyInto(&out.At)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FailureCheck.
+func (in *FailureCheck) DeepCopy() *FailureCheck {
+	if in == nil {
+		return nil
+	}
+	out := new(FailureCheck)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HTTPLoadSpec) DeepCopyInto(out *HTTPLoadSpec) {
+	*out = *in
+	if in.Headers != nil {
+		in, out := &in.Headers, &out.Headers
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPLoadSpec.
+func (in *HTTPLoadSpec) DeepCopy() *HTTPLoadSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(HTTPLoadSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HTTPLoadStatus) DeepCopyInto(out *HTTPLoadStatus) {
+	*out = *in
+	if in.LastUpdateTime != nil {
+		in, out := &in.LastUpdateTime, &out.LastUpdateTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPLoadStatus.
+func (in *HTTPLoadStatus) DeepCopy() *HTTPLoadStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(HTTPLoadStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HealthCheck) DeepCopyInto(out *HealthCheck) {
+	*out = *in
+	if in.AllOf != nil {
+		in, out := &in.AllOf, &out.AllOf
+		*out = make([]Expectation, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.AnyOf != nil {
+		in, out := &in.AnyOf, &out.AnyOf
+		*out = make([]Expectation, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Trends != nil {
+		in, out := &in.Trends, &out.Trends
+		*out = make([]TrendCheck, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HealthCheck.
+func (in *HealthCheck) DeepCopy() *HealthCheck {
+	if in == nil {
+		return nil
+	}
+	out := new(HealthCheck)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HealthCheckStatus) DeepCopyInto(out *HealthCheckStatus) {
+	*out = *in
+	if in.LastCheckTime != nil {
+		in, out := &in.LastCheckTime, &out.LastCheckTime
+		*out = (*in).DeepCopy()
+	}
+	if in.LastResults != nil {
+		in, out := &in.LastResults, &out.LastResults
+		*out = make([]ExpectationResultSummary, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Trends != nil {
+		in, out := &in.Trends, &out.Trends
+		*out = make([]TrendStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HealthCheckStatus.
+func (in *HealthCheckStatus) DeepCopy() *HealthCheckStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(HealthCheckStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IntegrationTest) DeepCopyInto(out *IntegrationTest) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IntegrationTest.
+func (in *IntegrationTest) DeepCopy() *IntegrationTest {
+	if in == nil {
+		return nil
+	}
+	out := new(IntegrationTest)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *IntegrationTest) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IntegrationTestList) DeepCopyInto(out *IntegrationTestList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]IntegrationTest, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IntegrationTestList.
+func (in *IntegrationTestList) DeepCopy() *IntegrationTestList {
+	if in == nil {
+		return nil
+	}
+	out := new(IntegrationTestList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *IntegrationTestList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IntegrationTestSpec) DeepCopyInto(out *IntegrationTestSpec) {
+	*out = *in
+	if in.Steps != nil {
+		in, out := &in.Steps, &out.Steps
+		*out = make([]TestStep, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Repeat != nil {
+		in, out := &in.Repeat, &out.Repeat
+		*out = new(RepeatConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DependsOn != nil {
+		in, out := &in.DependsOn, &out.DependsOn
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.EnvironmentRef != nil {
+		in, out := &in.EnvironmentRef, &out.EnvironmentRef
+		*out = new(EnvironmentReference)
+		**out = **in
+	}
+	if in.Triage != nil {
+		in, out := &in.Triage, &out.Triage
+		*out = new(TriageSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Debug != nil {
+		in, out := &in.Debug, &out.Debug
+		*out = new(DebugSpec)
+		**out = **in
+	}
+	if in.Variables != nil {
+		in, out := &in.Variables, &out.Variables
+		*out = make([]TestVariable, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Exporters != nil {
+		in, out := &in.Exporters, &out.Exporters
+		*out = make([]ExporterSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Workspace != nil {
+		in, out := &in.Workspace, &out.Workspace
+		*out = new(WorkspaceSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.StatsExpectations != nil {
+		in, out := &in.StatsExpectations, &out.StatsExpectations
+		*out = new(StepCondition)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PropagateAnnotations != nil {
+		in, out := &in.PropagateAnnotations, &out.PropagateAnnotations
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Cleanup != nil {
+		in, out := &in.Cleanup, &out.Cleanup
+		*out = new(CleanupSpec)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IntegrationTestSpec.
+func (in *IntegrationTestSpec) DeepCopy() *IntegrationTestSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(IntegrationTestSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IntegrationTestStatus) DeepCopyInto(out *IntegrationTestStatus) {
+	*out = *in
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CurrentStepIndex != nil {
+		in, out := &in.CurrentStepIndex, &out.CurrentStepIndex
+		*out = new(int)
+		**out = **in
+	}
+	if in.Steps != nil {
+		in, out := &in.Steps, &out.Steps
+		*out = make([]StepStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Triage != nil {
+		in, out := &in.Triage, &out.Triage
+		*out = new(TriageStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Breakpoint != nil {
+		in, out := &in.Breakpoint, &out.Breakpoint
+		*out = new(BreakpointStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ApplyLedger != nil {
+		in, out := &in.ApplyLedger, &out.ApplyLedger
+		*out = make([]ApplyLedgerEntry, len(*in))
+		copy(*out, *in)
+	}
+	if in.BudgetUsage != nil {
+		in, out := &in.BudgetUsage, &out.BudgetUsage
+		*out = new(BudgetUsage)
+		**out = **in
+	}
+	if in.Stats != nil {
+		in, out := &in.Stats, &out.Stats
+		*out = new(RoundStats)
+		**out = **in
+	}
+	if in.Cleanup != nil {
+		in, out := &in.Cleanup, &out.Cleanup
+		*out = new(CleanupStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TouchedKinds != nil {
+		in, out := &in.TouchedKinds, &out.TouchedKinds
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.SpecDigest != nil {
+		in, out := &in.SpecDigest, &out.SpecDigest
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.IgnoredSpecChanges != nil {
+		in, out := &in.IgnoredSpecChanges, &out.IgnoredSpecChanges
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.SuspendedAt != nil {
+		in, out := &in.SuspendedAt, &out.SuspendedAt
+		*out = (*in).DeepCopy()
+	}
+	if in.Deadlines != nil {
+		in, out := &in.Deadlines, &out.Deadlines
+		*out = make(map[string]v1.Time, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IntegrationTestStatus.
+func (in *IntegrationTestStatus) DeepCopy() *IntegrationTestStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(IntegrationTestStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *JSONPatchOperation) DeepCopyInto(out *JSONPatchOperation) {
+	*out = *in
+	if in.Value != nil {
+		in, out := &in.Value, &out.Value
+		*out = new(runtime.RawExtension)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JSONPatchOperation.
+func (in *JSONPatchOperation) DeepCopy() *JSONPatchOperation {
+	if in == nil {
+		return nil
+	}
+	out := new(JSONPatchOperation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LoadControlSpec) DeepCopyInto(out *LoadControlSpec) {
+	*out = *in
+	out.Metric = in.Metric
+	out.Parameter = in.Parameter
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LoadControlSpec.
+func (in *LoadControlSpec) DeepCopy() *LoadControlSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(LoadControlSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LoadControlStatus) DeepCopyInto(out *LoadControlStatus) {
+	*out = *in
+	if in.LastSampleTime != nil {
+		in, out := &in.LastSampleTime, &out.LastSampleTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LoadControlStatus.
+func (in *LoadControlStatus) DeepCopy() *LoadControlStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(LoadControlStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LoadParameter) DeepCopyInto(out *LoadParameter) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LoadParameter.
+func (in *LoadParameter) DeepCopy() *LoadParameter {
+	if in == nil {
+		return nil
+	}
+	out := new(LoadParameter)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LoadTest) DeepCopyInto(out *LoadTest) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LoadTest.
+func (in *LoadTest) DeepCopy() *LoadTest {
+	if in == nil {
+		return nil
+	}
+	out := new(LoadTest)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *LoadTest) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LoadTestList) DeepCopyInto(out *LoadTestList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]LoadTest, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LoadTestList.
+func (in *LoadTestList) DeepCopy() *LoadTestList {
+	if in == nil {
+		return nil
+	}
+	out := new(LoadTestList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *LoadTestList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LoadTestSpec) DeepCopyInto(out *LoadTestSpec) {
+	*out = *in
+	in.Target.DeepCopyInto(&out.Target)
+	in.Workload.DeepCopyInto(&out.Workload)
+	if in.HealthCheck != nil {
+		in, out := &in.HealthCheck, &out.HealthCheck
+		*out = new(HealthCheck)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Monitoring != nil {
+		in, out := &in.Monitoring, &out.Monitoring
+		*out = new(MonitoringSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.LoadControl != nil {
+		in, out := &in.LoadControl, &out.LoadControl
+		*out = new(LoadControlSpec)
+		**out = **in
+	}
+	if in.PostVerification != nil {
+		in, out := &in.PostVerification, &out.PostVerification
+		*out = new(PostVerificationSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.EnvironmentRef != nil {
+		in, out := &in.EnvironmentRef, &out.EnvironmentRef
+		*out = new(EnvironmentReference)
+		**out = **in
+	}
+	if in.Triage != nil {
+		in, out := &in.Triage, &out.Triage
+		*out = new(TriageSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Exporters != nil {
+		in, out := &in.Exporters, &out.Exporters
+		*out = make([]ExporterSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Artifacts != nil {
+		in, out := &in.Artifacts, &out.Artifacts
+		*out = make([]ArtifactSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.PropagateAnnotations != nil {
+		in, out := &in.PropagateAnnotations, &out.PropagateAnnotations
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Cleanup != nil {
+		in, out := &in.Cleanup, &out.Cleanup
+		*out = new(CleanupSpec)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LoadTestSpec.
+func (in *LoadTestSpec) DeepCopy() *LoadTestSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(LoadTestSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LoadTestStatus) DeepCopyInto(out *LoadTestStatus) {
+	*out = *in
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.RunStartTime != nil {
+		in, out := &in.RunStartTime, &out.RunStartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+	if in.InjectedValues != nil {
+		in, out := &in.InjectedValues, &out.InjectedValues
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.TouchedKinds != nil {
+		in, out := &in.TouchedKinds, &out.TouchedKinds
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ReadyConditionStatus != nil {
+		in, out := &in.ReadyConditionStatus, &out.ReadyConditionStatus
+		*out = new(ReadyConditionStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.HealthCheckStatus != nil {
+		in, out := &in.HealthCheckStatus, &out.HealthCheckStatus
+		*out = new(HealthCheckStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.HTTPLoad != nil {
+		in, out := &in.HTTPLoad, &out.HTTPLoad
+		*out = new(HTTPLoadStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Drift != nil {
+		in, out := &in.Drift, &out.Drift
+		*out = new(DriftStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.LoadControl != nil {
+		in, out := &in.LoadControl, &out.LoadControl
+		*out = new(LoadControlStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PostVerification != nil {
+		in, out := &in.PostVerification, &out.PostVerification
+		*out = new(PostVerificationStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Triage != nil {
+		in, out := &in.Triage, &out.Triage
+		*out = new(TriageStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Artifacts != nil {
+		in, out := &in.Artifacts, &out.Artifacts
+		*out = make([]ArtifactStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LoadTestStatus.
+func (in *LoadTestStatus) DeepCopy() *LoadTestStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(LoadTestStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LocalSecretReference) DeepCopyInto(out *LocalSecretReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LocalSecretReference.
+func (in *LocalSecretReference) DeepCopy() *LocalSecretReference {
+	if in == nil {
+		return nil
+	}
+	out := new(LocalSecretReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ManifestBase) DeepCopyInto(out *ManifestBase) {
+	*out = *in
+	out.ConfigMapRef = in.ConfigMapRef
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManifestBase.
+func (in *ManifestBase) DeepCopy() *ManifestBase {
+	if in == nil {
+		return nil
+	}
+	out := new(ManifestBase)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MonitoringSpec) DeepCopyInto(out *MonitoringSpec) {
+	*out = *in
+	if in.DashboardLabels != nil {
+		in, out := &in.DashboardLabels, &out.DashboardLabels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.RuleLabels != nil {
+		in, out := &in.RuleLabels, &out.RuleLabels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MonitoringSpec.
+func (in *MonitoringSpec) DeepCopy() *MonitoringSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(MonitoringSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PostVerificationSpec) DeepCopyInto(out *PostVerificationSpec) {
+	*out = *in
+	if in.Steps != nil {
+		in, out := &in.Steps, &out.Steps
+		*out = make([]TestStep, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PostVerificationSpec.
+func (in *PostVerificationSpec) DeepCopy() *PostVerificationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PostVerificationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PostVerificationStatus) DeepCopyInto(out *PostVerificationStatus) {
+	*out = *in
+	if in.Steps != nil {
+		in, out := &in.Steps, &out.Steps
+		*out = make([]StepStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PostVerificationStatus.
+func (in *PostVerificationStatus) DeepCopy() *PostVerificationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PostVerificationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PromQuery) DeepCopyInto(out *PromQuery) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PromQuery.
+func (in *PromQuery) DeepCopy() *PromQuery {
+	if in == nil {
+		return nil
+	}
+	out := new(PromQuery)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RaceAttempt) DeepCopyInto(out *RaceAttempt) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RaceAttempt.
+func (in *RaceAttempt) DeepCopy() *RaceAttempt {
+	if in == nil {
+		return nil
+	}
+	out := new(RaceAttempt)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RaceSpec) DeepCopyInto(out *RaceSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RaceSpec.
+func (in *RaceSpec) DeepCopy() *RaceSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RaceSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReadyCondition) DeepCopyInto(out *ReadyCondition) {
+	*out = *in
+	if in.AllOf != nil {
+		in, out := &in.AllOf, &out.AllOf
+		*out = make([]Expectation, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.AnyOf != nil {
+		in, out := &in.AnyOf, &out.AnyOf
+		*out = make([]Expectation, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReadyCondition.
+func (in *ReadyCondition) DeepCopy() *ReadyCondition {
+	if in == nil {
+		return nil
+	}
+	out := new(ReadyCondition)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReadyConditionStatus) DeepCopyInto(out *ReadyConditionStatus) {
+	*out = *in
+	if in.StartedAt != nil {
+		in, out := &in.StartedAt, &out.StartedAt
+		*out = (*in).DeepCopy()
+	}
+	if in.Deadline != nil {
+		in, out := &in.Deadline, &out.Deadline
+		*out = (*in).DeepCopy()
+	}
+	if in.FinishedAt != nil {
+		in, out := &in.FinishedAt, &out.FinishedAt
+		*out = (*in).DeepCopy()
+	}
+	if in.Results != nil {
+		in, out := &in.Results, &out.Results
+		*out = make([]ExpectationResult, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.SelectorDiagnostics != nil {
+		in, out := &in.SelectorDiagnostics, &out.SelectorDiagnostics
+		*out = new(SelectorDiagnostics)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReadyConditionStatus.
+func (in *ReadyConditionStatus) DeepCopy() *ReadyConditionStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ReadyConditionStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RelatedResource) DeepCopyInto(out *RelatedResource) {
+	*out = *in
+	if in.LabelSelector != nil {
+		in, out := &in.LabelSelector, &out.LabelSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RelatedResource.
+func (in *RelatedResource) DeepCopy() *RelatedResource {
+	if in == nil {
+		return nil
+	}
+	out := new(RelatedResource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RepeatConfig) DeepCopyInto(out *RepeatConfig) {
+	*out = *in
+	if in.Schedule != nil {
+		in, out := &in.Schedule, &out.Schedule
+		*out = new(RepeatSchedule)
+		**out = **in
+	}
+	if in.Gate != nil {
+		in, out := &in.Gate, &out.Gate
+		*out = new(RoundGate)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RepeatConfig.
+func (in *RepeatConfig) DeepCopy() *RepeatConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(RepeatConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RepeatSchedule) DeepCopyInto(out *RepeatSchedule) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RepeatSchedule.
+func (in *RepeatSchedule) DeepCopy() *RepeatSchedule {
+	if in == nil {
+		return nil
+	}
+	out := new(RepeatSchedule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourceRef) DeepCopyInto(out *ResourceRef) {
+	*out = *in
+	in.Manifest.DeepCopyInto(&out.Manifest)
+	if in.Base != nil {
+		in, out := &in.Base, &out.Base
+		*out = new(ManifestBase)
+		**out = **in
+	}
+	if in.Patches != nil {
+		in, out := &in.Patches, &out.Patches
+		*out = make([]JSONPatchOperation, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Selector != nil {
+		in, out := &in.Selector, &out.Selector
+		*out = new(ResourceSelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceRef.
+func (in *ResourceRef) DeepCopy() *ResourceRef {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourceRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourceSelector) DeepCopyInto(out *ResourceSelector) {
+	*out = *in
+	if in.LabelSelector != nil {
+		in, out := &in.LabelSelector, &out.LabelSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.AnnotationSelector != nil {
+		in, out := &in.AnnotationSelector, &out.AnnotationSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceSelector.
+func (in *ResourceSelector) DeepCopy() *ResourceSelector {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourceSelector)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RoundGate) DeepCopyInto(out *RoundGate) {
+	*out = *in
+	if in.ConfigMapKey != nil {
+		in, out := &in.ConfigMapKey, &out.ConfigMapKey
+		*out = new(ConfigMapKeyReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RoundGate.
+func (in *RoundGate) DeepCopy() *RoundGate {
+	if in == nil {
+		return nil
+	}
+	out := new(RoundGate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RoundStats) DeepCopyInto(out *RoundStats) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RoundStats.
+func (in *RoundStats) DeepCopy() *RoundStats {
+	if in == nil {
+		return nil
+	}
+	out := new(RoundStats)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScheduledTest) DeepCopyInto(out *ScheduledTest) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScheduledTest.
+func (in *ScheduledTest) DeepCopy() *ScheduledTest {
+	if in == nil {
+		return nil
+	}
+	out := new(ScheduledTest)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ScheduledTest) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScheduledTestList) DeepCopyInto(out *ScheduledTestList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ScheduledTest, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScheduledTestList.
+func (in *ScheduledTestList) DeepCopy() *ScheduledTestList {
+	if in == nil {
+		return nil
+	}
+	out := new(ScheduledTestList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ScheduledTestList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScheduledTestRun) DeepCopyInto(out *ScheduledTestRun) {
+	*out = *in
+	if in.ScheduledTime != nil {
+		in, out := &in.ScheduledTime, &out.ScheduledTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScheduledTestRun.
+func (in *ScheduledTestRun) DeepCopy() *ScheduledTestRun {
+	if in == nil {
+		return nil
+	}
+	out := new(ScheduledTestRun)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScheduledTestSpec) DeepCopyInto(out *ScheduledTestSpec) {
+	*out = *in
+	if in.StartingDeadlineSeconds != nil {
+		in, out := &in.StartingDeadlineSeconds, &out.StartingDeadlineSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	if in.SuccessfulRunsHistoryLimit != nil {
+		in, out := &in.SuccessfulRunsHistoryLimit, &out.SuccessfulRunsHistoryLimit
+		*out = new(int32)
+		**out = **in
+	}
+	if in.FailedRunsHistoryLimit != nil {
+		in, out := &in.FailedRunsHistoryLimit, &out.FailedRunsHistoryLimit
+		*out = new(int32)
+		**out = **in
+	}
+	in.Template.DeepCopyInto(&out.Template)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScheduledTestSpec.
+func (in *ScheduledTestSpec) DeepCopy() *ScheduledTestSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ScheduledTestSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScheduledTestStatus) DeepCopyInto(out *ScheduledTestStatus) {
+	*out = *in
+	if in.Runs != nil {
+		in, out := &in.Runs, &out.Runs
+		*out = make([]ScheduledTestRun, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.LastScheduleTime != nil {
+		in, out := &in.LastScheduleTime, &out.LastScheduleTime
+		*out = (*in).DeepCopy()
+	}
+	if in.LastSuccessfulTime != nil {
+		in, out := &in.LastSuccessfulTime, &out.LastSuccessfulTime
+		*out = (*in).DeepCopy()
+	}
+	if in.NextScheduleTime != nil {
+		in, out := &in.NextScheduleTime, &out.NextScheduleTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScheduledTestStatus.
+func (in *ScheduledTestStatus) DeepCopy() *ScheduledTestStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ScheduledTestStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScheduledTestTemplate) DeepCopyInto(out *ScheduledTestTemplate) {
+	*out = *in
+	if in.TemplateRef != nil {
+		in, out := &in.TemplateRef, &out.TemplateRef
+		*out = new(TestSuiteTemplateReference)
+		**out = **in
+	}
+	if in.Spec != nil {
+		in, out := &in.Spec, &out.Spec
+		*out = new(runtime.RawExtension)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScheduledTestTemplate.
+func (in *ScheduledTestTemplate) DeepCopy() *ScheduledTestTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(ScheduledTestTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecretKeyReference) DeepCopyInto(out *SecretKeyReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretKeyReference.
+func (in *SecretKeyReference) DeepCopy() *SecretKeyReference {
+	if in == nil {
+		return nil
+	}
+	out := new(SecretKeyReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SelectorDiagnostics) DeepCopyInto(out *SelectorDiagnostics) {
+	*out = *in
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.NearestMisses != nil {
+		in, out := &in.NearestMisses, &out.NearestMisses
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SelectorDiagnostics.
+func (in *SelectorDiagnostics) DeepCopy() *SelectorDiagnostics {
+	if in == nil {
+		return nil
+	}
+	out := new(SelectorDiagnostics)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StepAttempt) DeepCopyInto(out *StepAttempt) {
+	*out = *in
+	if in.StartedAt != nil {
+		in, out := &in.StartedAt, &out.StartedAt
+		*out = (*in).DeepCopy()
+	}
+	if in.FinishedAt != nil {
+		in, out := &in.FinishedAt, &out.FinishedAt
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StepAttempt.
+func (in *StepAttempt) DeepCopy() *StepAttempt {
+	if in == nil {
+		return nil
+	}
+	out := new(StepAttempt)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StepCondition) DeepCopyInto(out *StepCondition) {
+	*out = *in
+	if in.AllOf != nil {
+		in, out := &in.AllOf, &out.AllOf
+		*out = make([]Expectation, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.AnyOf != nil {
+		in, out := &in.AnyOf, &out.AnyOf
+		*out = make([]Expectation, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StepCondition.
+func (in *StepCondition) DeepCopy() *StepCondition {
+	if in == nil {
+		return nil
+	}
+	out := new(StepCondition)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StepHookStatus) DeepCopyInto(out *StepHookStatus) {
+	*out = *in
+	if in.FinishedAt != nil {
+		in, out := &in.FinishedAt, &out.FinishedAt
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StepHookStatus.
+func (in *StepHookStatus) DeepCopy() *StepHookStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(StepHookStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StepIteration) DeepCopyInto(out *StepIteration) {
+	*out = *in
+	if in.StartedAt != nil {
+		in, out := &in.StartedAt, &out.StartedAt
+		*out = (*in).DeepCopy()
+	}
+	if in.FinishedAt != nil {
+		in, out := &in.FinishedAt, &out.FinishedAt
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StepIteration.
+func (in *StepIteration) DeepCopy() *StepIteration {
+	if in == nil {
+		return nil
+	}
+	out := new(StepIteration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StepOutput) DeepCopyInto(out *StepOutput) {
+	*out = *in
+	in.Extract.DeepCopyInto(&out.Extract)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StepOutput.
+func (in *StepOutput) DeepCopy() *StepOutput {
+	if in == nil {
+		return nil
+	}
+	out := new(StepOutput)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StepProgress) DeepCopyInto(out *StepProgress) {
+	*out = *in
+	if in.LastHeartbeatTime != nil {
+		in, out := &in.LastHeartbeatTime, &out.LastHeartbeatTime
+		*out = (*in).DeepCopy()
+	}
+	if in.LastTransitionTime != nil {
+		in, out := &in.LastTransitionTime, &out.LastTransitionTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StepProgress.
+func (in *StepProgress) DeepCopy() *StepProgress {
+	if in == nil {
+		return nil
+	}
+	out := new(StepProgress)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StepStatus) DeepCopyInto(out *StepStatus) {
+	*out = *in
+	if in.StartedAt != nil {
+		in, out := &in.StartedAt, &out.StartedAt
+		*out = (*in).DeepCopy()
+	}
+	if in.Deadline != nil {
+		in, out := &in.Deadline, &out.Deadline
+		*out = (*in).DeepCopy()
+	}
+	if in.FinishedAt != nil {
+		in, out := &in.FinishedAt, &out.FinishedAt
+		*out = (*in).DeepCopy()
+	}
+	if in.ExpectationResults != nil {
+		in, out := &in.ExpectationResults, &out.ExpectationResults
+		*out = make([]ExpectationResultSummary, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ReadyConditionStatus != nil {
+		in, out := &in.ReadyConditionStatus, &out.ReadyConditionStatus
+		*out = new(ReadyConditionStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SelectorDiagnostics != nil {
+		in, out := &in.SelectorDiagnostics, &out.SelectorDiagnostics
+		*out = new(SelectorDiagnostics)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Iterations != nil {
+		in, out := &in.Iterations, &out.Iterations
+		*out = make([]StepIteration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Outputs != nil {
+		in, out := &in.Outputs, &out.Outputs
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Progress != nil {
+		in, out := &in.Progress, &out.Progress
+		*out = new(StepProgress)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RaceAttempts != nil {
+		in, out := &in.RaceAttempts, &out.RaceAttempts
+		*out = make([]RaceAttempt, len(*in))
+		copy(*out, *in)
+	}
+	if in.FailureChecks != nil {
+		in, out := &in.FailureChecks, &out.FailureChecks
+		*out = make([]FailureCheck, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Attempts != nil {
+		in, out := &in.Attempts, &out.Attempts
+		*out = make([]StepAttempt, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.PreStep != nil {
+		in, out := &in.PreStep, &out.PreStep
+		*out = new(StepHookStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PostStep != nil {
+		in, out := &in.PostStep, &out.PostStep
+		*out = new(StepHookStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Cleanup != nil {
+		in, out := &in.Cleanup, &out.Cleanup
+		*out = new(CleanupStatus)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StepStatus.
+func (in *StepStatus) DeepCopy() *StepStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(StepStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TargetSpec) DeepCopyInto(out *TargetSpec) {
+	*out = *in
+	in.Resource.DeepCopyInto(&out.Resource)
+	if in.ReadyCondition != nil {
+		in, out := &in.ReadyCondition, &out.ReadyCondition
+		*out = new(ReadyCondition)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DriftDetection != nil {
+		in, out := &in.DriftDetection, &out.DriftDetection
+		*out = new(DriftDetection)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TargetSpec.
+func (in *TargetSpec) DeepCopy() *TargetSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(TargetSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TestFieldReference) DeepCopyInto(out *TestFieldReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TestFieldReference.
+func (in *TestFieldReference) DeepCopy() *TestFieldReference {
+	if in == nil {
+		return nil
+	}
+	out := new(TestFieldReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TestStep) DeepCopyInto(out *TestStep) {
+	*out = *in
+	if in.Resource != nil {
+		in, out := &in.Resource, &out.Resource
+		*out = new(ResourceRef)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PreStep != nil {
+		in, out := &in.PreStep, &out.PreStep
+		*out = make([]ResourceRef, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.PostStep != nil {
+		in, out := &in.PostStep, &out.PostStep
+		*out = make([]ResourceRef, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Outputs != nil {
+		in, out := &in.Outputs, &out.Outputs
+		*out = make([]StepOutput, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ReadyCondition != nil {
+		in, out := &in.ReadyCondition, &out.ReadyCondition
+		*out = new(StepCondition)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Expectations != nil {
+		in, out := &in.Expectations, &out.Expectations
+		*out = new(StepCondition)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.KeepCompleted != nil {
+		in, out := &in.KeepCompleted, &out.KeepCompleted
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Race != nil {
+		in, out := &in.Race, &out.Race
+		*out = new(RaceSpec)
+		**out = **in
+	}
+	if in.Canary != nil {
+		in, out := &in.Canary, &out.Canary
+		*out = new(CanarySpec)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TestStep.
+func (in *TestStep) DeepCopy() *TestStep {
+	if in == nil {
+		return nil
+	}
+	out := new(TestStep)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TestSuite) DeepCopyInto(out *TestSuite) {
 	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RepeatConfig.
-func (in *RepeatConfig) DeepCopy() *RepeatConfig {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TestSuite.
+func (in *TestSuite) DeepCopy() *TestSuite {
 	if in == nil {
 		return nil
 	}
-	out := new(RepeatConfig)
+	out := new(TestSuite)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *TestSuite) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ResourceRef) DeepCopyInto(out *ResourceRef) {
+func (in *TestSuiteItem) DeepCopyInto(out *TestSuiteItem) {
 	*out = *in
-	in.Manifest.DeepCopyInto(&out.Manifest)
-	if in.Selector != nil {
-		in, out := &in.Selector, &out.Selector
-		*out = new(ResourceSelector)
+	if in.TemplateRef != nil {
+		in, out := &in.TemplateRef, &out.TemplateRef
+		*out = new(TestSuiteTemplateReference)
+		**out = **in
+	}
+	if in.Spec != nil {
+		in, out := &in.Spec, &out.Spec
+		*out = new(runtime.RawExtension)
 		(*in).DeepCopyInto(*out)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceRef.
-func (in *ResourceRef) DeepCopy() *ResourceRef {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TestSuiteItem.
+func (in *TestSuiteItem) DeepCopy() *TestSuiteItem {
 	if in == nil {
 		return nil
 	}
-	out := new(ResourceRef)
+	out := new(TestSuiteItem)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ResourceSelector) DeepCopyInto(out *ResourceSelector) {
+func (in *TestSuiteItemStatus) DeepCopyInto(out *TestSuiteItemStatus) {
 	*out = *in
-	if in.LabelSelector != nil {
-		in, out := &in.LabelSelector, &out.LabelSelector
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
+	if in.StartedAt != nil {
+		in, out := &in.StartedAt, &out.StartedAt
+		*out = (*in).DeepCopy()
 	}
-	if in.AnnotationSelector != nil {
-		in, out := &in.AnnotationSelector, &out.AnnotationSelector
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
+	if in.FinishedAt != nil {
+		in, out := &in.FinishedAt, &out.FinishedAt
+		*out = (*in).DeepCopy()
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceSelector.
-func (in *ResourceSelector) DeepCopy() *ResourceSelector {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TestSuiteItemStatus.
+func (in *TestSuiteItemStatus) DeepCopy() *TestSuiteItemStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(ResourceSelector)
+	out := new(TestSuiteItemStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *StepCondition) DeepCopyInto(out *StepCondition) {
+func (in *TestSuiteList) DeepCopyInto(out *TestSuiteList) {
 	*out = *in
-	if in.AllOf != nil {
-		in, out := &in.AllOf, &out.AllOf
-		*out = make([]Expectation, len(*in))
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]TestSuite, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
-	if in.AnyOf != nil {
-		in, out := &in.AnyOf, &out.AnyOf
-		*out = make([]Expectation, len(*in))
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TestSuiteList.
+func (in *TestSuiteList) DeepCopy() *TestSuiteList {
+	if in == nil {
+		return nil
+	}
+	out := new(TestSuiteList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *TestSuiteList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TestSuiteSpec) DeepCopyInto(out *TestSuiteSpec) {
+	*out = *in
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]TestSuiteItem, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StepCondition.
-func (in *StepCondition) DeepCopy() *StepCondition {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TestSuiteSpec.
+func (in *TestSuiteSpec) DeepCopy() *TestSuiteSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(StepCondition)
+	out := new(TestSuiteSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *StepStatus) DeepCopyInto(out *StepStatus) {
+func (in *TestSuiteStatus) DeepCopyInto(out *TestSuiteStatus) {
 	*out = *in
 	if in.StartedAt != nil {
 		in, out := &in.StartedAt, &out.StartedAt
@@ -581,79 +2373,191 @@ func (in *StepStatus) DeepCopyInto(out *StepStatus) {
 		in, out := &in.Deadline, &out.Deadline
 		*out = (*in).DeepCopy()
 	}
-	if in.FinishedAt != nil {
-		in, out := &in.FinishedAt, &out.FinishedAt
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
 		*out = (*in).DeepCopy()
 	}
-	if in.ExpectationResults != nil {
-		in, out := &in.ExpectationResults, &out.ExpectationResults
-		*out = make([]ExpectationResultSummary, len(*in))
-		copy(*out, *in)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]TestSuiteItemStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
-	if in.ReadyConditionStatus != nil {
-		in, out := &in.ReadyConditionStatus, &out.ReadyConditionStatus
-		*out = new(ReadyConditionStatus)
-		(*in).DeepCopyInto(*out)
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StepStatus.
-func (in *StepStatus) DeepCopy() *StepStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TestSuiteStatus.
+func (in *TestSuiteStatus) DeepCopy() *TestSuiteStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(StepStatus)
+	out := new(TestSuiteStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *TargetSpec) DeepCopyInto(out *TargetSpec) {
+func (in *TestSuiteTemplateReference) DeepCopyInto(out *TestSuiteTemplateReference) {
 	*out = *in
-	in.Resource.DeepCopyInto(&out.Resource)
-	if in.ReadyCondition != nil {
-		in, out := &in.ReadyCondition, &out.ReadyCondition
-		*out = new(ReadyCondition)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TestSuiteTemplateReference.
+func (in *TestSuiteTemplateReference) DeepCopy() *TestSuiteTemplateReference {
+	if in == nil {
+		return nil
+	}
+	out := new(TestSuiteTemplateReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TestVariable) DeepCopyInto(out *TestVariable) {
+	*out = *in
+	if in.ValueFrom != nil {
+		in, out := &in.ValueFrom, &out.ValueFrom
+		*out = new(VariableSource)
 		(*in).DeepCopyInto(*out)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TargetSpec.
-func (in *TargetSpec) DeepCopy() *TargetSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TestVariable.
+func (in *TestVariable) DeepCopy() *TestVariable {
 	if in == nil {
 		return nil
 	}
-	out := new(TargetSpec)
+	out := new(TestVariable)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *TestStep) DeepCopyInto(out *TestStep) {
+func (in *TrendCheck) DeepCopyInto(out *TrendCheck) {
 	*out = *in
-	if in.Resource != nil {
-		in, out := &in.Resource, &out.Resource
-		*out = new(ResourceRef)
+	if in.Extract != nil {
+		in, out := &in.Extract, &out.Extract
+		*out = new(Extractor)
 		(*in).DeepCopyInto(*out)
 	}
-	if in.ReadyCondition != nil {
-		in, out := &in.ReadyCondition, &out.ReadyCondition
-		*out = new(StepCondition)
-		(*in).DeepCopyInto(*out)
+	if in.PromQuery != nil {
+		in, out := &in.PromQuery, &out.PromQuery
+		*out = new(PromQuery)
+		**out = **in
 	}
-	if in.Expectations != nil {
-		in, out := &in.Expectations, &out.Expectations
-		*out = new(StepCondition)
-		(*in).DeepCopyInto(*out)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TrendCheck.
+func (in *TrendCheck) DeepCopy() *TrendCheck {
+	if in == nil {
+		return nil
 	}
+	out := new(TrendCheck)
+	in.DeepCopyInto(out)
+	return out
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TestStep.
-func (in *TestStep) DeepCopy() *TestStep {
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TrendSample) DeepCopyInto(out *TrendSample) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TrendSample.
+func (in *TrendSample) DeepCopy() *TrendSample {
 	if in == nil {
 		return nil
 	}
-	out := new(TestStep)
+	out := new(TrendSample)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TrendStatus) DeepCopyInto(out *TrendStatus) {
+	*out = *in
+	if in.Samples != nil {
+		in, out := &in.Samples, &out.Samples
+		*out = make([]TrendSample, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TrendStatus.
+func (in *TrendStatus) DeepCopy() *TrendStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(TrendStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TriageSpec) DeepCopyInto(out *TriageSpec) {
+	*out = *in
+	if in.EvidenceLinks != nil {
+		in, out := &in.EvidenceLinks, &out.EvidenceLinks
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TriageSpec.
+func (in *TriageSpec) DeepCopy() *TriageSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(TriageSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TriageStatus) DeepCopyInto(out *TriageStatus) {
+	*out = *in
+	if in.LastAttemptTime != nil {
+		in, out := &in.LastAttemptTime, &out.LastAttemptTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TriageStatus.
+func (in *TriageStatus) DeepCopy() *TriageStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(TriageStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VariableSource) DeepCopyInto(out *VariableSource) {
+	*out = *in
+	if in.TestRef != nil {
+		in, out := &in.TestRef, &out.TestRef
+		*out = new(TestFieldReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VariableSource.
+func (in *VariableSource) DeepCopy() *VariableSource {
+	if in == nil {
+		return nil
+	}
+	out := new(VariableSource)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -675,6 +2579,11 @@ func (in *WorkloadSpec) DeepCopyInto(out *WorkloadSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.HTTPLoad != nil {
+		in, out := &in.HTTPLoad, &out.HTTPLoad
+		*out = new(HTTPLoadSpec)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkloadSpec.
@@ -686,3 +2595,28 @@ func (in *WorkloadSpec) DeepCopy() *WorkloadSpec {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkspaceSpec) DeepCopyInto(out *WorkspaceSpec) {
+	*out = *in
+	if in.Size != nil {
+		in, out := &in.Size, &out.Size
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.StorageClassName != nil {
+		in, out := &in.StorageClassName, &out.StorageClassName
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkspaceSpec.
+func (in *WorkspaceSpec) DeepCopy() *WorkspaceSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkspaceSpec)
+	in.DeepCopyInto(out)
+	return out
+}
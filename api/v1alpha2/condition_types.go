@@ -0,0 +1,61 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha2
+
+import (
+	"github.com/lunz1207/testplane/api/v1alpha1"
+)
+
+// Condition 期望条件：在超时时间内持续检查直到满足。
+// 统一了 v1alpha1 的 StepCondition（步骤 expectations）与 ReadyCondition（就绪条件）。
+type Condition struct {
+	// TimeoutSeconds 超时（秒）。用于步骤 expectations 时为单次检查超时（默认 10），
+	// 用于 ready 时为等待就绪的总超时（默认 300）。
+	// +optional
+	TimeoutSeconds int32 `json:"timeoutSeconds,omitempty"`
+	// AllOf 所有期望都必须满足。
+	AllOf []v1alpha1.Expectation `json:"allOf,omitempty"`
+	// AnyOf 任一期望满足即可。
+	AnyOf []v1alpha1.Expectation `json:"anyOf,omitempty"`
+}
+
+// PeriodicCondition 周期期望：按间隔检查，连续失败达阈值则失败（v1alpha1 的 HealthCheck）。
+type PeriodicCondition struct {
+	// IntervalSeconds 检查间隔（秒）。
+	// +kubebuilder:default=10
+	IntervalSeconds int32 `json:"intervalSeconds,omitempty"`
+	// TimeoutSeconds 单次检查超时（秒）。
+	// +kubebuilder:default=10
+	TimeoutSeconds int32 `json:"timeoutSeconds,omitempty"`
+	// FailureThreshold 连续失败阈值。
+	// +kubebuilder:default=3
+	FailureThreshold int32 `json:"failureThreshold,omitempty"`
+	// JitterSeconds 检查时间偏移上限（秒），按 UID 取 [0, jitterSeconds) 内固定的偏移。
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	JitterSeconds int32 `json:"jitterSeconds,omitempty"`
+	// AlignToMinute 检查对齐到整分钟（间隔小于 1 分钟时在每分钟内按间隔对齐）。
+	// +optional
+	AlignToMinute bool `json:"alignToMinute,omitempty"`
+	// AllOf 所有期望都必须满足。
+	AllOf []v1alpha1.Expectation `json:"allOf,omitempty"`
+	// AnyOf 任一期望满足即可。
+	AnyOf []v1alpha1.Expectation `json:"anyOf,omitempty"`
+	// Trends 数值趋势检查（有状态）。
+	// +optional
+	Trends []v1alpha1.TrendCheck `json:"trends,omitempty"`
+}
@@ -0,0 +1,143 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha2
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+
+	"github.com/lunz1207/testplane/api/v1alpha1"
+)
+
+// 转换策略：v1alpha1 为 Hub（存储版本）。两个版本的 spec 除更名字段外结构相同，
+// 因此按 JSON 转换并重命名差异字段；status 类型相同，直接复制。
+// 任一版本存在另一版本没有的字段时转换报错，避免经 v1alpha2 读写时静默丢失数据。
+
+// fieldRename 两个版本之间更名的 spec 字段。
+type fieldRename struct {
+	// path 字段所在对象相对 spec 的路径，"*" 表示数组中的每个元素。
+	path []string
+	// v1alpha1 字段名。
+	v1alpha1 string
+	// v1alpha2 字段名。
+	v1alpha2 string
+}
+
+// integrationTestRenames IntegrationTest spec 的更名字段。
+var integrationTestRenames = []fieldRename{
+	{path: []string{"steps", "*"}, v1alpha1: "readyCondition", v1alpha2: "ready"},
+}
+
+// loadTestRenames LoadTest spec 的更名字段。
+var loadTestRenames = []fieldRename{
+	{path: []string{"target"}, v1alpha1: "readyCondition", v1alpha2: "ready"},
+	{v1alpha1: "healthCheck", v1alpha2: "expectations"},
+	{path: []string{"postVerification", "steps", "*"}, v1alpha1: "readyCondition", v1alpha2: "ready"},
+}
+
+// ConvertTo 将 v1alpha2 IntegrationTest 转换为 v1alpha1。
+func (src *IntegrationTest) ConvertTo(dstRaw conversion.Hub) error {
+	dst := dstRaw.(*v1alpha1.IntegrationTest)
+	dst.ObjectMeta = src.ObjectMeta
+	dst.Status = src.Status
+	dst.Spec = v1alpha1.IntegrationTestSpec{}
+	return convertSpec(&src.Spec, &dst.Spec, integrationTestRenames, true)
+}
+
+// ConvertFrom 将 v1alpha1 IntegrationTest 转换为 v1alpha2。
+func (dst *IntegrationTest) ConvertFrom(srcRaw conversion.Hub) error {
+	src := srcRaw.(*v1alpha1.IntegrationTest)
+	dst.ObjectMeta = src.ObjectMeta
+	dst.Status = src.Status
+	dst.Spec = IntegrationTestSpec{}
+	return convertSpec(&src.Spec, &dst.Spec, integrationTestRenames, false)
+}
+
+// ConvertTo 将 v1alpha2 LoadTest 转换为 v1alpha1。
+func (src *LoadTest) ConvertTo(dstRaw conversion.Hub) error {
+	dst := dstRaw.(*v1alpha1.LoadTest)
+	dst.ObjectMeta = src.ObjectMeta
+	dst.Status = src.Status
+	dst.Spec = v1alpha1.LoadTestSpec{}
+	return convertSpec(&src.Spec, &dst.Spec, loadTestRenames, true)
+}
+
+// ConvertFrom 将 v1alpha1 LoadTest 转换为 v1alpha2。
+func (dst *LoadTest) ConvertFrom(srcRaw conversion.Hub) error {
+	src := srcRaw.(*v1alpha1.LoadTest)
+	dst.ObjectMeta = src.ObjectMeta
+	dst.Status = src.Status
+	dst.Spec = LoadTestSpec{}
+	return convertSpec(&src.Spec, &dst.Spec, loadTestRenames, false)
+}
+
+// convertSpec 按 JSON 转换 spec 并重命名差异字段，toV1alpha1 表示转换方向。
+func convertSpec(src, dst interface{}, renames []fieldRename, toV1alpha1 bool) error {
+	raw, err := json.Marshal(src)
+	if err != nil {
+		return fmt.Errorf("marshal spec: %w", err)
+	}
+	var spec map[string]interface{}
+	if err := json.Unmarshal(raw, &spec); err != nil {
+		return fmt.Errorf("unmarshal spec: %w", err)
+	}
+	for _, r := range renames {
+		from, to := r.v1alpha1, r.v1alpha2
+		if toV1alpha1 {
+			from, to = to, from
+		}
+		renameField(spec, r.path, from, to)
+	}
+	if raw, err = json.Marshal(spec); err != nil {
+		return fmt.Errorf("marshal spec: %w", err)
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(dst); err != nil {
+		return fmt.Errorf("convert spec: %w", err)
+	}
+	return nil
+}
+
+// renameField 将 path 指向的对象中的字段 from 更名为 to。
+func renameField(obj interface{}, path []string, from, to string) {
+	if len(path) == 0 {
+		m, ok := obj.(map[string]interface{})
+		if !ok {
+			return
+		}
+		if v, ok := m[from]; ok {
+			delete(m, from)
+			m[to] = v
+		}
+		return
+	}
+	if path[0] == "*" {
+		items, _ := obj.([]interface{})
+		for _, item := range items {
+			renameField(item, path[1:], from, to)
+		}
+		return
+	}
+	if m, ok := obj.(map[string]interface{}); ok {
+		renameField(m[path[0]], path[1:], from, to)
+	}
+}
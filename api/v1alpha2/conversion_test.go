@@ -0,0 +1,40 @@
+package v1alpha2
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/lunz1207/testplane/api/v1alpha1"
+)
+
+var _ = Describe("Conversion", func() {
+	Context("When a LoadTest is read through v1alpha2", func() {
+		It("should round-trip health check scheduling fields", func() {
+			src := &v1alpha1.LoadTest{
+				ObjectMeta: metav1.ObjectMeta{Name: "soak", Namespace: "default"},
+				Spec: v1alpha1.LoadTestSpec{
+					HealthCheck: &v1alpha1.HealthCheck{
+						IntervalSeconds:  15,
+						FailureThreshold: 3,
+						JitterSeconds:    10,
+						AlignToMinute:    true,
+						AllOf:            []v1alpha1.Expectation{{Function: "DeploymentReady"}},
+					},
+					WarmupSeconds:   60,
+					DurationSeconds: 600,
+				},
+			}
+
+			var served LoadTest
+			Expect(served.ConvertFrom(src)).To(Succeed())
+			Expect(served.Spec.Expectations).NotTo(BeNil())
+			Expect(served.Spec.Expectations.JitterSeconds).To(Equal(int32(10)))
+			Expect(served.Spec.Expectations.AlignToMinute).To(BeTrue())
+
+			var hub v1alpha1.LoadTest
+			Expect(served.ConvertTo(&hub)).To(Succeed())
+			Expect(hub.Spec).To(Equal(src.Spec))
+		})
+	})
+})
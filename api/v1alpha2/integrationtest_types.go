@@ -0,0 +1,175 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha2
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/lunz1207/testplane/api/v1alpha1"
+)
+
+// TestStep 定义一个测试步骤（单资源）。
+// 与 v1alpha1 相比，就绪条件字段 readyCondition 更名为 ready，与 expectations 使用同一 Condition 类型。
+type TestStep struct {
+	// Name 步骤名称。
+	Name string `json:"name"`
+	// Resource 步骤资源（单资源），Manifest 和 Selector 互斥。
+	// +optional
+	Resource *v1alpha1.ResourceRef `json:"resource,omitempty"`
+	// PreStep 步骤资源 apply 之前依次执行的准备清单。
+	// +optional
+	PreStep []v1alpha1.ResourceRef `json:"preStep,omitempty"`
+	// PostStep 步骤结束后（包括失败）依次执行的清理清单。
+	// +optional
+	PostStep []v1alpha1.ResourceRef `json:"postStep,omitempty"`
+	// CleanupPolicy 步骤结束后删除步骤资源的时机，覆盖 spec.cleanupPolicy。
+	// +optional
+	CleanupPolicy v1alpha1.CleanupPolicy `json:"cleanupPolicy,omitempty"`
+	// As 步骤资源的别名，期望通过 resource: <alias> 引用该资源。
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	// +optional
+	As string `json:"as,omitempty"`
+	// Outputs 步骤期望满足后从步骤资源提取的输出，后续步骤以 ${steps.<name>.outputs.<key>} 引用。
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	Outputs []v1alpha1.StepOutput `json:"outputs,omitempty"`
+	// Ready 创建/更新资源后的就绪条件。
+	// +optional
+	Ready *Condition `json:"ready,omitempty"`
+	// Expectations 步骤执行后的业务预期。
+	// +optional
+	Expectations *Condition `json:"expectations,omitempty"`
+	// TimeoutSeconds 步骤超时时间（秒），控制整个步骤的超时。
+	TimeoutSeconds int32 `json:"timeoutSeconds,omitempty"`
+	// ConfirmFailureSeconds 超时判定失败前的复核间隔（秒），两次检查都未满足才判定失败。
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	ConfirmFailureSeconds int32 `json:"confirmFailureSeconds,omitempty"`
+	// Retries 步骤暂时性失败后重新执行的次数。
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	Retries int32 `json:"retries,omitempty"`
+	// RetryBackoffSeconds 第一次重试前的等待时间（秒，默认 10），之后每次翻倍。
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	RetryBackoffSeconds int32 `json:"retryBackoffSeconds,omitempty"`
+	// PeriodicSeconds 周期执行间隔（秒），步骤首次成功后在本轮其余步骤执行期间周期性重新执行。
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	PeriodicSeconds int32 `json:"periodicSeconds,omitempty"`
+	// Barrier 并行屏障（仅 Parallel 模式生效）。
+	// +optional
+	Barrier bool `json:"barrier,omitempty"`
+	// KeepCompleted 步骤资源创建的已完成子对象的保留数量，超出部分在轮次之间删除。
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	KeepCompleted *int32 `json:"keepCompleted,omitempty"`
+	// Race 并发竞争模式：以不同的字段管理者并发 SSA apply 同一对象 N 次。
+	// +optional
+	Race *v1alpha1.RaceSpec `json:"race,omitempty"`
+	// Canary 金丝雀比较：从基线与金丝雀资源中提取相同指标，断言偏差在范围内。
+	// +optional
+	Canary *v1alpha1.CanarySpec `json:"canary,omitempty"`
+}
+
+// IntegrationTestSpec 定义测试用例的规格。
+type IntegrationTestSpec struct {
+	// Mode 测试执行模式：Sequential（顺序）或 Parallel（并行）。
+	Mode v1alpha1.IntegrationTestMode `json:"mode,omitempty"`
+	// Steps 测试步骤列表。
+	Steps []TestStep `json:"steps,omitempty"`
+	// Repeat 重复执行配置，不设置则只执行一轮。
+	Repeat *v1alpha1.RepeatConfig `json:"repeat,omitempty"`
+	// DependsOn 依赖的 IntegrationTest 名称（同命名空间）。
+	// +optional
+	DependsOn []string `json:"dependsOn,omitempty"`
+	// EnvironmentRef 引用的 Environment（同命名空间）。
+	// +optional
+	EnvironmentRef *v1alpha1.EnvironmentReference `json:"environmentRef,omitempty"`
+	// Triage 失败分诊配置，测试以 Failed 结束时调用。
+	// +optional
+	Triage *v1alpha1.TriageSpec `json:"triage,omitempty"`
+	// Debug 调试配置（如步骤失败时在断点处暂停）。
+	// +optional
+	Debug *v1alpha1.DebugSpec `json:"debug,omitempty"`
+	// Variables 测试变量，期望参数中以 ${vars.<name>} 引用。
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	Variables []v1alpha1.TestVariable `json:"variables,omitempty"`
+	// Exporters 结果导出器。
+	// +optional
+	Exporters []v1alpha1.ExporterSpec `json:"exporters,omitempty"`
+	// Workspace 测试专属的临时存储，spec 中以 ${WORKSPACE} 引用其名称。
+	// +optional
+	Workspace *v1alpha1.WorkspaceSpec `json:"workspace,omitempty"`
+	// StatsExpectations 判定成功前对 status.stats 等测试自身字段执行一次的期望。
+	// +optional
+	StatsExpectations *v1alpha1.StepCondition `json:"statsExpectations,omitempty"`
+	// StrictSchema 预检时以服务端 dry-run（fieldValidation=Strict）校验所有步骤清单。
+	// +optional
+	StrictSchema bool `json:"strictSchema,omitempty"`
+	// ScopedRBAC 以按步骤清单与选择器生成的最小权限身份执行资源写入。
+	// +optional
+	ScopedRBAC bool `json:"scopedRBAC,omitempty"`
+	// Suspend 暂停执行，恢复时暂停期间不计入步骤超时。
+	// +optional
+	Suspend bool `json:"suspend,omitempty"`
+	// PropagateAnnotations 复制到步骤创建的每个资源上的测试注解键，以 * 结尾时按前缀匹配。
+	// +optional
+	PropagateAnnotations []string `json:"propagateAnnotations,omitempty"`
+	// Cleanup 测试删除时按依赖顺序清理资源的配置。
+	// +optional
+	Cleanup *v1alpha1.CleanupSpec `json:"cleanup,omitempty"`
+	// CleanupPolicy 测试结束后删除步骤资源的时机：Keep（默认）、DeleteOnSuccess 或 DeleteAlways。
+	// +optional
+	CleanupPolicy v1alpha1.CleanupPolicy `json:"cleanupPolicy,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
+// +kubebuilder:printcolumn:name="Mode",type=string,JSONPath=`.spec.mode`
+// +kubebuilder:printcolumn:name="Round",type=integer,JSONPath=`.status.currentRound`,priority=1
+// +kubebuilder:printcolumn:name="Completed",type=integer,JSONPath=`.status.completedRounds`,priority=1
+// +kubebuilder:printcolumn:name="Reason",type=string,JSONPath=`.status.reason`,priority=1
+// +kubebuilder:printcolumn:name="Suspend",type=boolean,JSONPath=`.spec.suspend`,priority=1
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+// +kubebuilder:resource:shortName=it
+
+// IntegrationTest 表示一个集成测试用例。状态与 v1alpha1 相同。
+type IntegrationTest struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   IntegrationTestSpec            `json:"spec,omitempty"`
+	Status v1alpha1.IntegrationTestStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// IntegrationTestList 包含多个 IntegrationTest。
+type IntegrationTestList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []IntegrationTest `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&IntegrationTest{}, &IntegrationTestList{})
+}
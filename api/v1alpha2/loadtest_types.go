@@ -0,0 +1,128 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha2
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/lunz1207/testplane/api/v1alpha1"
+)
+
+// TargetSpec 定义测试目标资源（单资源）。
+type TargetSpec struct {
+	// Resource 目标资源（单资源）。
+	Resource v1alpha1.ResourceRef `json:"resource"`
+	// Ready 就绪条件（可选），满足后才部署 Workload。
+	// +optional
+	Ready *Condition `json:"ready,omitempty"`
+	// DriftDetection 运行期漂移检测（可选，仅 Manifest 目标）。
+	// +optional
+	DriftDetection *v1alpha1.DriftDetection `json:"driftDetection,omitempty"`
+}
+
+// PostVerificationSpec 负载结束后执行的验证步骤。
+type PostVerificationSpec struct {
+	// Mode 步骤执行模式，默认 Sequential。
+	// +optional
+	Mode v1alpha1.IntegrationTestMode `json:"mode,omitempty"`
+	// Steps 验证步骤。
+	// +kubebuilder:validation:MinItems=1
+	Steps []TestStep `json:"steps"`
+}
+
+// LoadTestSpec 定义负载测试规格。
+// 与 v1alpha1 相比，target.readyCondition 更名为 target.ready，healthCheck 更名为 expectations，
+// 与 IntegrationTest 步骤的命名保持一致。
+// +kubebuilder:validation:XValidation:rule="!has(self.postVerification) || (has(self.durationSeconds) && self.durationSeconds > 0)",message="postVerification requires durationSeconds > 0"
+type LoadTestSpec struct {
+	// Target 被测目标资源。
+	Target TargetSpec `json:"target"`
+	// Workload 负载资源定义。
+	Workload v1alpha1.WorkloadSpec `json:"workload"`
+	// Expectations 运行期周期性期望。
+	// +optional
+	Expectations *PeriodicCondition `json:"expectations,omitempty"`
+	// WarmupSeconds 进入 Running 后的预热时长（秒），预热期内的健康检查不计入统计。
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	WarmupSeconds int32 `json:"warmupSeconds,omitempty"`
+	// Monitoring 监控资源生成（可选）。
+	// +optional
+	Monitoring *v1alpha1.MonitoringSpec `json:"monitoring,omitempty"`
+	// LoadControl 闭环负载控制（可选）。
+	// +optional
+	LoadControl *v1alpha1.LoadControlSpec `json:"loadControl,omitempty"`
+	// DurationSeconds 运行时长（秒），0 表示持续运行直到 LoadTest 删除。
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	DurationSeconds int32 `json:"durationSeconds,omitempty"`
+	// PostVerification 负载结束后的验证步骤（可选，需设置 DurationSeconds）。
+	// +optional
+	PostVerification *PostVerificationSpec `json:"postVerification,omitempty"`
+	// EnvironmentRef 引用的 Environment（同命名空间）。
+	// +optional
+	EnvironmentRef *v1alpha1.EnvironmentReference `json:"environmentRef,omitempty"`
+	// Triage 失败分诊配置，测试以 Failed 结束时调用。
+	// +optional
+	Triage *v1alpha1.TriageSpec `json:"triage,omitempty"`
+	// Exporters 结果导出器。
+	// +optional
+	Exporters []v1alpha1.ExporterSpec `json:"exporters,omitempty"`
+	// Artifacts 测试结束时从 workload Pod 中收集的文件。
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	Artifacts []v1alpha1.ArtifactSpec `json:"artifacts,omitempty"`
+	// PropagateAnnotations 复制到 workload 资源上的测试注解键，以 * 结尾时按前缀匹配。
+	// +optional
+	PropagateAnnotations []string `json:"propagateAnnotations,omitempty"`
+	// Cleanup 测试删除时按依赖顺序清理资源的配置。
+	// +optional
+	Cleanup *v1alpha1.CleanupSpec `json:"cleanup,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
+// +kubebuilder:printcolumn:name="Checks",type=integer,JSONPath=`.status.healthCheckStatus.checkCount`,priority=1
+// +kubebuilder:printcolumn:name="Pass",type=integer,JSONPath=`.status.healthCheckStatus.passCount`,priority=1
+// +kubebuilder:printcolumn:name="Fail",type=integer,JSONPath=`.status.healthCheckStatus.failCount`,priority=1
+// +kubebuilder:printcolumn:name="Reason",type=string,JSONPath=`.status.reason`,priority=1
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+// +kubebuilder:resource:shortName=lt
+
+// LoadTest 表示一个负载测试。状态与 v1alpha1 相同。
+type LoadTest struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   LoadTestSpec            `json:"spec,omitempty"`
+	Status v1alpha1.LoadTestStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// LoadTestList 包含多个 LoadTest。
+type LoadTestList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []LoadTest `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&LoadTest{}, &LoadTestList{})
+}
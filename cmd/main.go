@@ -17,10 +17,13 @@ limitations under the License.
 package main
 
 import (
+	"context"
 	"crypto/tls"
 	"flag"
 	"os"
 	"path/filepath"
+	"strconv"
+	"time"
 
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
 	// to ensure that exec-entrypoint and run can make use of them.
@@ -30,7 +33,9 @@ import (
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/certwatcher"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 	"sigs.k8s.io/controller-runtime/pkg/metrics/filters"
@@ -38,10 +43,23 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 
 	infrav1alpha1 "github.com/lunz1207/testplane/api/v1alpha1"
+	infrav1alpha2 "github.com/lunz1207/testplane/api/v1alpha2"
 	"github.com/lunz1207/testplane/internal/builtins"
+	checkcontroller "github.com/lunz1207/testplane/internal/controller/check"
 	integrationtestcontroller "github.com/lunz1207/testplane/internal/controller/integrationtest"
 	loadtestcontroller "github.com/lunz1207/testplane/internal/controller/loadtest"
+	scheduledtestcontroller "github.com/lunz1207/testplane/internal/controller/scheduledtest"
+	"github.com/lunz1207/testplane/internal/controller/shared"
+	statusboardcontroller "github.com/lunz1207/testplane/internal/controller/statusboard"
+	testsuitecontroller "github.com/lunz1207/testplane/internal/controller/testsuite"
+	"github.com/lunz1207/testplane/internal/loadgen"
 	"github.com/lunz1207/testplane/internal/plugin"
+	webhookv1alpha1 "github.com/lunz1207/testplane/internal/webhook/v1alpha1"
+	"github.com/lunz1207/testplane/pkg/artifacts"
+	"github.com/lunz1207/testplane/pkg/export"
+	"github.com/lunz1207/testplane/pkg/hooks"
+	"github.com/lunz1207/testplane/pkg/recording"
+	"github.com/lunz1207/testplane/pkg/results"
 	// +kubebuilder:scaffold:imports
 )
 
@@ -54,11 +72,17 @@ func init() {
 	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
 
 	utilruntime.Must(infrav1alpha1.AddToScheme(scheme))
+	utilruntime.Must(infrav1alpha2.AddToScheme(scheme))
 	// +kubebuilder:scaffold:scheme
 }
 
 // nolint:gocyclo
 func main() {
+	// 内置 HTTP 负载 worker 与控制器共用镜像：manager loadgen --url=... --rps=...
+	if len(os.Args) > 1 && os.Args[1] == "loadgen" {
+		os.Exit(loadgen.Main(os.Args[2:]))
+	}
+
 	var metricsAddr string
 	var metricsCertPath, metricsCertName, metricsCertKey string
 	var webhookCertPath, webhookCertName, webhookCertKey string
@@ -66,6 +90,17 @@ func main() {
 	var probeAddr string
 	var secureMetrics bool
 	var enableHTTP2 bool
+	var recordDir string
+	var recordMaxBytes int64
+	var artifactDir string
+	var loadGenImage string
+	var exportersConfig string
+	var reconcileDebounce time.Duration
+	var statusBoardName string
+	var resultsAddr, resultsCertPath, resultsCertName, resultsCertKey, resultsClientCA string
+	var resultsPodName, resultsPodNamespace string
+	var resultsMaxEntries int
+	var itClientOpts, ltClientOpts, checkClientOpts, suiteClientOpts, scheduleClientOpts shared.ClientOptions
 	var tlsOpts []func(*tls.Config)
 	flag.StringVar(&metricsAddr, "metrics-bind-address", "0", "The address the metrics endpoint binds to. "+
 		"Use :8443 for HTTPS or :8080 for HTTP, or leave as 0 to disable the metrics service.")
@@ -82,6 +117,47 @@ func main() {
 		"The directory that contains the metrics server certificate.")
 	flag.StringVar(&metricsCertName, "metrics-cert-name", "tls.crt", "The name of the metrics server certificate file.")
 	flag.StringVar(&metricsCertKey, "metrics-cert-key", "tls.key", "The name of the metrics server key file.")
+	flag.StringVar(&recordDir, "record-dir", "",
+		"Directory for expectation state snapshots of tests annotated with "+
+			infrav1alpha1.AnnotationRecord+"=true. Recording is disabled when empty. "+
+			"Snapshots contain full resource state and may include Secret data.")
+	flag.Int64Var(&recordMaxBytes, "record-max-bytes", recording.DefaultMaxBytes,
+		"Size at which a recording file is rotated to <file>.1, keeping at most two files per test. "+
+			"Zero disables rotation.")
+	flag.StringVar(&artifactDir, "artifact-dir", "",
+		"Directory for artifacts copied out of LoadTest workload pods (spec.artifacts). Collection is disabled when empty.")
+	flag.StringVar(&loadGenImage, "loadgen-image", "",
+		"Default image of LoadTest httpLoad workers. The image must provide /manager (this binary).")
+	flag.StringVar(&exportersConfig, "exporters-config", "",
+		"YAML file listing controller-wide result exporters (HTTP, S3, Kafka). "+
+			"Tests can add their own via spec.exporters.")
+	flag.DurationVar(&reconcileDebounce, "reconcile-debounce", 0,
+		"Coalesce update events of the same test within this window into one reconcile (e.g. 200ms). "+
+			"Create and delete events are never delayed. Disabled when 0.")
+	flag.StringVar(&statusBoardName, "status-board-configmap", "",
+		"Name of a per-namespace ConfigMap summarizing all tests (phase, round, last failure), "+
+			"for dashboards without list access to the CRDs. Disabled when empty.")
+	flag.StringVar(&resultsAddr, "results-api-bind-address", "0",
+		"The address the aggregated test results API ("+results.GroupVersion.String()+") binds to, e.g. :9443. "+
+			"Leave as 0 to disable.")
+	flag.StringVar(&resultsCertPath, "results-api-cert-path", "", "The directory that contains the results API certificate.")
+	flag.StringVar(&resultsCertName, "results-api-cert-name", "tls.crt", "The name of the results API certificate file.")
+	flag.StringVar(&resultsCertKey, "results-api-cert-key", "tls.key", "The name of the results API key file.")
+	flag.StringVar(&resultsClientCA, "results-api-client-ca", "",
+		"CA bundle verifying the aggregation layer's client certificate. "+
+			"Read from kube-system/extension-apiserver-authentication when empty.")
+	flag.IntVar(&resultsMaxEntries, "results-api-max-entries", results.DefaultMaxEntries,
+		"Number of test results kept in memory by the results API; the oldest are evicted first.")
+	flag.StringVar(&resultsPodName, "results-api-pod-name", os.Getenv("POD_NAME"),
+		"Name of the manager's own pod. The leader labels it with "+results.LeaderLabel+
+			" so that the results API Service only routes to the leader. Defaults to $POD_NAME.")
+	flag.StringVar(&resultsPodNamespace, "results-api-pod-namespace", os.Getenv("POD_NAMESPACE"),
+		"Namespace of the manager's own pod. Defaults to $POD_NAMESPACE.")
+	bindClientFlags(flag.CommandLine, "integrationtest", &itClientOpts)
+	bindClientFlags(flag.CommandLine, "loadtest", &ltClientOpts)
+	bindClientFlags(flag.CommandLine, "check", &checkClientOpts)
+	bindClientFlags(flag.CommandLine, "testsuite", &suiteClientOpts)
+	bindClientFlags(flag.CommandLine, "scheduledtest", &scheduleClientOpts)
 	flag.BoolVar(&enableHTTP2, "enable-http2", false,
 		"If set, HTTP/2 will be enabled for the metrics and webhook servers")
 	opts := zap.Options{
@@ -199,6 +275,12 @@ func main() {
 		// if you are doing or is intended to do any operation such as perform cleanups
 		// after the manager stops then its usage might be unsafe.
 		// LeaderElectionReleaseOnCancel: true,
+
+		// 缓存的 typed 与 metadata 对象（测试 CR、Pod、Job 等）不需要 managedFields，缓存前剔除以节省内存。
+		// 期望检查读取的 unstructured 对象不经缓存，由状态收集自行剔除（见 resource.StripManagedFields）。
+		Cache: cache.Options{
+			DefaultTransform: cache.TransformStripManagedFields(),
+		},
 	})
 	if err != nil {
 		setupLog.Error(err, "unable to start manager")
@@ -210,25 +292,184 @@ func main() {
 	pluginRegistry := plugin.NewRegistry()
 	builtins.RegisterAll(pluginRegistry)
 
+	// 状态快照录制（仅对带录制注解的测试生效）
+	var snapshotArchive recording.Archive
+	if recordDir != "" {
+		archive := recording.NewFileArchive(recordDir)
+		archive.MaxBytes = recordMaxBytes
+		snapshotArchive = archive
+		setupLog.Info("expectation state recording enabled", "dir", recordDir, "maxBytes", recordMaxBytes)
+	}
+
+	// 工件收集（LoadTest spec.artifacts）
+	var artifactStore artifacts.Store
+	var podExecutor shared.PodExecutor
+	if artifactDir != "" {
+		artifactStore = artifacts.NewFileStore(artifactDir)
+		if podExecutor, err = shared.NewPodExecutor(mgr.GetConfig()); err != nil {
+			setupLog.Error(err, "unable to create pod executor")
+			os.Exit(1)
+		}
+		setupLog.Info("artifact collection enabled", "dir", artifactDir)
+	}
+
+	// 控制器级结果导出器
+	var globalExporter export.Exporter
+	if exportersConfig != "" {
+		exporters, err := export.LoadConfig(exportersConfig)
+		if err != nil {
+			setupLog.Error(err, "unable to load exporters config")
+			os.Exit(1)
+		}
+		globalExporter = exporters
+		setupLog.Info("result exporters enabled", "config", exportersConfig, "count", len(exporters))
+	}
+
+	// 聚合 API 结果视图：结果与 CR status 双写到内存存储
+	var resultsServer *results.Server
+	if resultsAddr != "0" {
+		if resultsCertPath == "" {
+			setupLog.Error(nil, "results API requires --results-api-cert-path")
+			os.Exit(1)
+		}
+		store := results.NewStore(resultsMaxEntries)
+		if globalExporter != nil {
+			globalExporter = export.Multi{globalExporter, store}
+		} else {
+			globalExporter = store
+		}
+		resultsServer = &results.Server{
+			Store:        store,
+			BindAddress:  resultsAddr,
+			CertPath:     resultsCertPath,
+			CertName:     resultsCertName,
+			KeyName:      resultsCertKey,
+			ClientCAFile: resultsClientCA,
+			Reader:       mgr.GetAPIReader(),
+			Authorizer:   mgr.GetClient(),
+		}
+		if resultsPodName != "" {
+			// 清除容器重启前作为 leader 留下的标签，重新当选后再打上
+			pod := client.ObjectKey{Namespace: resultsPodNamespace, Name: resultsPodName}
+			if err := clearResultsLeaderLabel(mgr, pod); err != nil {
+				setupLog.Error(err, "unable to clear results API leader label", "pod", pod)
+				os.Exit(1)
+			}
+			resultsServer.Pod = pod
+			resultsServer.PodWriter = mgr.GetClient()
+		} else {
+			setupLog.Info("results API pod name is not set; the Service must select a single replica")
+		}
+	}
+
+	itClient, itReader, err := shared.NewControllerClients(mgr, "integrationtest", itClientOpts)
+	if err != nil {
+		setupLog.Error(err, "unable to create client", "controller", "IntegrationTest")
+		os.Exit(1)
+	}
 	if err := (&integrationtestcontroller.IntegrationTestReconciler{
-		Client:         mgr.GetClient(),
-		Scheme:         mgr.GetScheme(),
-		PluginRegistry: pluginRegistry,
-		APIReader:      mgr.GetAPIReader(),
-		Recorder:       mgr.GetEventRecorderFor("integrationtest"),
+		Client:          itClient,
+		Scheme:          mgr.GetScheme(),
+		PluginRegistry:  pluginRegistry,
+		APIReader:       itReader,
+		Recorder:        shared.NewManagerEventRecorder(mgr, "integrationtest"),
+		SnapshotArchive: snapshotArchive,
+		Exporter:        &shared.ResultExporter{Global: globalExporter, Reader: itReader},
+		Debounce:        reconcileDebounce,
+		Hooks:           hooks.Default,
+		RESTConfig:      shared.ControllerRESTConfig(mgr.GetConfig(), "integrationtest", itClientOpts),
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "IntegrationTest")
 		os.Exit(1)
 	}
+	ltClient, ltReader, err := shared.NewControllerClients(mgr, "loadtest", ltClientOpts)
+	if err != nil {
+		setupLog.Error(err, "unable to create client", "controller", "LoadTest")
+		os.Exit(1)
+	}
 	if err := (&loadtestcontroller.LoadTestReconciler{
-		Client:         mgr.GetClient(),
-		Scheme:         mgr.GetScheme(),
-		PluginRegistry: pluginRegistry,
-		APIReader:      mgr.GetAPIReader(),
+		Client:          ltClient,
+		Scheme:          mgr.GetScheme(),
+		PluginRegistry:  pluginRegistry,
+		APIReader:       ltReader,
+		Recorder:        shared.NewManagerEventRecorder(mgr, "loadtest"),
+		SnapshotArchive: snapshotArchive,
+		Exporter:        &shared.ResultExporter{Global: globalExporter, Reader: ltReader},
+		Debounce:        reconcileDebounce,
+		LoadGenImage:    loadGenImage,
+		ArtifactStore:   artifactStore,
+		PodExecutor:     podExecutor,
+		Hooks:           hooks.Default,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "LoadTest")
 		os.Exit(1)
 	}
+	checkClient, checkReader, err := shared.NewControllerClients(mgr, "check", checkClientOpts)
+	if err != nil {
+		setupLog.Error(err, "unable to create client", "controller", "Check")
+		os.Exit(1)
+	}
+	if err := (&checkcontroller.CheckReconciler{
+		Client:          checkClient,
+		Scheme:          mgr.GetScheme(),
+		PluginRegistry:  pluginRegistry,
+		APIReader:       checkReader,
+		Recorder:        shared.NewManagerEventRecorder(mgr, "check"),
+		SnapshotArchive: snapshotArchive,
+		Exporter:        &shared.ResultExporter{Global: globalExporter, Reader: checkReader},
+		Debounce:        reconcileDebounce,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "Check")
+		os.Exit(1)
+	}
+	suiteClient, suiteReader, err := shared.NewControllerClients(mgr, "testsuite", suiteClientOpts)
+	if err != nil {
+		setupLog.Error(err, "unable to create client", "controller", "TestSuite")
+		os.Exit(1)
+	}
+	if err := (&testsuitecontroller.TestSuiteReconciler{
+		Client:    suiteClient,
+		Scheme:    mgr.GetScheme(),
+		APIReader: suiteReader,
+		Recorder:  shared.NewManagerEventRecorder(mgr, "testsuite"),
+		Debounce:  reconcileDebounce,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "TestSuite")
+		os.Exit(1)
+	}
+	scheduleClient, _, err := shared.NewControllerClients(mgr, "scheduledtest", scheduleClientOpts)
+	if err != nil {
+		setupLog.Error(err, "unable to create client", "controller", "ScheduledTest")
+		os.Exit(1)
+	}
+	if err := (&scheduledtestcontroller.ScheduledTestReconciler{
+		Client:   scheduleClient,
+		Scheme:   mgr.GetScheme(),
+		Recorder: shared.NewManagerEventRecorder(mgr, "scheduledtest"),
+		Debounce: reconcileDebounce,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "ScheduledTest")
+		os.Exit(1)
+	}
+	if statusBoardName != "" {
+		if err := (&statusboardcontroller.StatusBoardReconciler{
+			Client:        mgr.GetClient(),
+			APIReader:     mgr.GetAPIReader(),
+			ConfigMapName: statusBoardName,
+			Debounce:      reconcileDebounce,
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "StatusBoard")
+			os.Exit(1)
+		}
+		setupLog.Info("status board enabled", "configMap", statusBoardName)
+	}
+	// nolint:goconst
+	if os.Getenv("ENABLE_WEBHOOKS") != "false" {
+		if err := webhookv1alpha1.SetupConversionWebhooksWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create conversion webhooks")
+			os.Exit(1)
+		}
+	}
 	// +kubebuilder:scaffold:builder
 
 	if metricsCertWatcher != nil {
@@ -247,6 +488,14 @@ func main() {
 		}
 	}
 
+	if resultsServer != nil {
+		if err := mgr.Add(resultsServer); err != nil {
+			setupLog.Error(err, "unable to add results API server to manager")
+			os.Exit(1)
+		}
+		setupLog.Info("results API enabled", "address", resultsAddr, "apiVersion", results.GroupVersion.String())
+	}
+
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
 		setupLog.Error(err, "unable to set up health check")
 		os.Exit(1)
@@ -262,3 +511,32 @@ func main() {
 		os.Exit(1)
 	}
 }
+
+// clearResultsLeaderLabel 在 manager 启动前移除 Pod 上的结果 API leader 标签。
+// manager 的缓存尚未启动，使用直连 API Server 的客户端。
+func clearResultsLeaderLabel(mgr ctrl.Manager, pod client.ObjectKey) error {
+	c, err := client.New(mgr.GetConfig(), client.Options{Scheme: mgr.GetScheme()})
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	return results.ClearLeaderLabel(ctx, c, pod)
+}
+
+// bindClientFlags 注册控制器客户端的 QPS/Burst 参数。
+// 未设置时沿用 manager 的 rest.Config。
+func bindClientFlags(fs *flag.FlagSet, controller string, opts *shared.ClientOptions) {
+	fs.Func(controller+"-kube-api-qps",
+		"QPS limit of the "+controller+" controller's API server client. Defaults to the manager's setting.",
+		func(v string) error {
+			qps, err := strconv.ParseFloat(v, 32)
+			if err != nil {
+				return err
+			}
+			opts.QPS = float32(qps)
+			return nil
+		})
+	fs.IntVar(&opts.Burst, controller+"-kube-api-burst", 0,
+		"Burst limit of the "+controller+" controller's API server client. Defaults to the manager's setting.")
+}
@@ -0,0 +1,221 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/flowcontrol"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/config"
+
+	infrav1alpha1 "github.com/lunz1207/testplane/api/v1alpha1"
+)
+
+// bulkTarget 批量操作的单个测试。
+type bulkTarget struct {
+	kind     string
+	obj      client.Object
+	phase    string
+	terminal bool
+}
+
+// runBulk 按标签选择器批量重跑或中止测试：为匹配的测试设置 rerun/abort 注解，
+// 由控制器完成实际操作。patch 按 --qps 限速，避免环境重置后同时重启数百个测试。
+//
+//	testplane bulk rerun -n soak -l suite=nightly --phase Failed,Aborted
+//	testplane bulk abort -A -l team=storage --reason Preempted --dry-run
+func runBulk(args []string) int {
+	if len(args) == 0 || (args[0] != "rerun" && args[0] != "abort") {
+		fmt.Fprintln(os.Stderr, "Usage: testplane bulk <rerun|abort> -l <selector> [flags]")
+		return 2
+	}
+	action := args[0]
+
+	fs := flag.NewFlagSet("bulk "+action, flag.ContinueOnError)
+	var namespace, selector string
+	fs.StringVar(&namespace, "namespace", "default", "命名空间")
+	fs.StringVar(&namespace, "n", "default", "命名空间（--namespace 的简写）")
+	fs.StringVar(&selector, "selector", "", "标签选择器（必填），如 suite=nightly,env!=prod")
+	fs.StringVar(&selector, "l", "", "标签选择器（--selector 的简写）")
+	allNamespaces := fs.Bool("all-namespaces", false, "所有命名空间")
+	fs.BoolVar(allNamespaces, "A", false, "所有命名空间（--all-namespaces 的简写）")
+	kind := fs.String("kind", "all", "测试类型：integrationtest、loadtest 或 all")
+	phases := fs.String("phase", "", "只处理这些阶段的测试（逗号分隔），为空时不过滤")
+	reason := fs.String("reason", "true", "中止原因（abort）：Preempted 或其他值（ManualAbort）")
+	qps := fs.Float64("qps", 5, "每秒最多 patch 的测试数")
+	dryRun := fs.Bool("dry-run", false, "只列出将要处理的测试，不修改")
+	if err := fs.Parse(args[1:]); err != nil {
+		return 2
+	}
+	if selector == "" {
+		fmt.Fprintln(os.Stderr, "--selector is required")
+		return 2
+	}
+	sel, err := labels.Parse(selector)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "invalid --selector: %v\n", err)
+		return 2
+	}
+	if *kind != "all" && *kind != "integrationtest" && *kind != "loadtest" {
+		fmt.Fprintf(os.Stderr, "invalid --kind %q\n", *kind)
+		return 2
+	}
+	if *qps <= 0 {
+		fmt.Fprintln(os.Stderr, "--qps must be positive")
+		return 2
+	}
+
+	c, err := newClusterClient()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+	opts := []client.ListOption{client.MatchingLabelsSelector{Selector: sel}}
+	if !*allNamespaces {
+		opts = append(opts, client.InNamespace(namespace))
+	}
+
+	ctx := context.Background()
+	targets, err := listBulkTargets(ctx, c, *kind, opts)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+
+	patch, err := bulkPatch(action, *reason)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+	phaseFilter := parsePhases(*phases)
+	limiter := flowcontrol.NewTokenBucketRateLimiter(float32(*qps), 1)
+
+	var patched, skipped, failed int
+	for _, t := range targets {
+		name := fmt.Sprintf("%s/%s/%s", t.kind, t.obj.GetNamespace(), t.obj.GetName())
+		if len(phaseFilter) > 0 && !phaseFilter[t.phase] {
+			skipped++
+			continue
+		}
+		if action == "abort" && t.terminal {
+			fmt.Printf("%s\tskipped (%s)\n", name, t.phase)
+			skipped++
+			continue
+		}
+		if *dryRun {
+			fmt.Printf("%s\t%s (dry run)\n", name, action)
+			patched++
+			continue
+		}
+		limiter.Accept()
+		if err := c.Patch(ctx, t.obj, client.RawPatch(types.MergePatchType, patch)); err != nil {
+			fmt.Printf("%s\tfailed: %v\n", name, err)
+			failed++
+			continue
+		}
+		fmt.Printf("%s\t%s requested\n", name, action)
+		patched++
+	}
+
+	fmt.Fprintf(os.Stderr, "%d %s, %d skipped, %d failed\n", patched, action, skipped, failed)
+	if failed > 0 {
+		return 1
+	}
+	return 0
+}
+
+// newClusterClient 使用 KUBECONFIG（或集群内配置）创建客户端。
+func newClusterClient() (client.Client, error) {
+	cfg, err := config.GetConfig()
+	if err != nil {
+		return nil, fmt.Errorf("load kubeconfig: %w", err)
+	}
+	scheme := runtime.NewScheme()
+	if err := infrav1alpha1.AddToScheme(scheme); err != nil {
+		return nil, err
+	}
+	return client.New(cfg, client.Options{Scheme: scheme})
+}
+
+// listBulkTargets 列出匹配的 IntegrationTest 与 LoadTest。
+func listBulkTargets(ctx context.Context, c client.Client, kind string, opts []client.ListOption) ([]bulkTarget, error) {
+	var targets []bulkTarget
+	if kind == "all" || kind == "integrationtest" {
+		var list infrav1alpha1.IntegrationTestList
+		if err := c.List(ctx, &list, opts...); err != nil {
+			return nil, fmt.Errorf("list integrationtests: %w", err)
+		}
+		for i := range list.Items {
+			it := &list.Items[i]
+			phase := it.Status.Phase
+			terminal := phase == infrav1alpha1.IntegrationTestPhaseSucceeded ||
+				phase == infrav1alpha1.IntegrationTestPhaseFailed ||
+				phase == infrav1alpha1.IntegrationTestPhaseAborted
+			targets = append(targets, bulkTarget{kind: "integrationtest", obj: it, phase: string(phase), terminal: terminal})
+		}
+	}
+	if kind == "all" || kind == "loadtest" {
+		var list infrav1alpha1.LoadTestList
+		if err := c.List(ctx, &list, opts...); err != nil {
+			return nil, fmt.Errorf("list loadtests: %w", err)
+		}
+		for i := range list.Items {
+			lt := &list.Items[i]
+			phase := lt.Status.Phase
+			terminal := phase == infrav1alpha1.LoadTestSucceeded || phase == infrav1alpha1.LoadTestFailed
+			targets = append(targets, bulkTarget{kind: "loadtest", obj: lt, phase: string(phase), terminal: terminal})
+		}
+	}
+	return targets, nil
+}
+
+// bulkPatch 生成注解 merge patch。
+// rerun 设置新的重跑令牌并移除中止注解（否则重跑后会立即再次中止）；abort 设置中止注解。
+func bulkPatch(action, reason string) ([]byte, error) {
+	annotations := map[string]interface{}{}
+	switch action {
+	case "rerun":
+		annotations[infrav1alpha1.AnnotationRerun] = time.Now().UTC().Format(time.RFC3339Nano)
+		annotations[infrav1alpha1.AnnotationAbort] = nil
+	case "abort":
+		annotations[infrav1alpha1.AnnotationAbort] = reason
+	}
+	return json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{"annotations": annotations},
+	})
+}
+
+// parsePhases 解析逗号分隔的阶段列表。
+func parsePhases(value string) map[string]bool {
+	phases := map[string]bool{}
+	for _, p := range strings.Split(value, ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			phases[p] = true
+		}
+	}
+	return phases
+}
@@ -0,0 +1,124 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+
+	infrav1alpha1 "github.com/lunz1207/testplane/api/v1alpha1"
+	"github.com/lunz1207/testplane/pkg/assertiontest"
+)
+
+// runEval 对夹具中的每个资源执行函数，全部通过时返回 0。
+//
+//	testplane eval --fixture pod.yaml --function PodReady
+//	testplane eval --fixture cm.yaml --function ConfigMapValueEquals --params '{"key":"mode","value":"on"}'
+//	testplane eval --fixture cluster.yaml --function ClusterID --extract
+func runEval(args []string) int {
+	fs := flag.NewFlagSet("eval", flag.ContinueOnError)
+	fixture := fs.String("fixture", "", "资源夹具文件（YAML/JSON，支持多文档）")
+	function := fs.String("function", "", "函数名")
+	params := fs.String("params", "", "函数参数（JSON 或 YAML）")
+	webhook := fs.String("webhook", "", "Webhook 地址（可选，设置后调用外部服务）")
+	extract := fs.Bool("extract", false, "以提取模式执行，输出提取值")
+	list := fs.Bool("list", false, "列出全部内置函数")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	h := assertiontest.New()
+
+	if *list {
+		for _, name := range h.Functions() {
+			fmt.Println(name)
+		}
+		return 0
+	}
+
+	if *fixture == "" || *function == "" {
+		fmt.Fprintln(os.Stderr, "--fixture and --function are required")
+		fs.Usage()
+		return 2
+	}
+
+	resources, err := assertiontest.LoadFixtures(*fixture)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+
+	exp := infrav1alpha1.Expectation{Function: *function, Webhook: *webhook}
+	if *params != "" {
+		raw, err := utilyaml.ToJSON([]byte(*params))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "invalid --params: %v\n", err)
+			return 2
+		}
+		exp.Params = runtime.RawExtension{Raw: raw}
+	}
+	var extractParams map[string]interface{}
+	if len(exp.Params.Raw) > 0 {
+		if err := json.Unmarshal(exp.Params.Raw, &extractParams); err != nil {
+			fmt.Fprintf(os.Stderr, "invalid --params: %v\n", err)
+			return 2
+		}
+	}
+
+	exitCode := 0
+	for _, res := range resources {
+		name := resourceName(res)
+
+		if *extract {
+			value, err := h.Extract(res, exp.Function, extractParams)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "%s: %v\n", name, err)
+				return 2
+			}
+			fmt.Printf("%s\t%s\n", name, value)
+			continue
+		}
+
+		result, err := h.EvalExpectation(res, exp)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", name, err)
+			return 2
+		}
+		if result.Passed {
+			fmt.Printf("PASS\t%s\n", name)
+			continue
+		}
+		exitCode = 1
+		fmt.Printf("FAIL\t%s\tmessage=%q actual=%q\n", name, result.Message, result.Actual)
+	}
+	return exitCode
+}
+
+// resourceName 返回 kind/name 形式的资源标识。
+func resourceName(res map[string]interface{}) string {
+	kind, _ := res["kind"].(string)
+	name := ""
+	if meta, ok := res["metadata"].(map[string]interface{}); ok {
+		name, _ = meta["name"].(string)
+	}
+	return fmt.Sprintf("%s/%s", kind, name)
+}
@@ -0,0 +1,99 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/lunz1207/testplane/pkg/assertiontest"
+	"github.com/lunz1207/testplane/pkg/lint"
+)
+
+// lintFinding 带文件名的检查结果。
+type lintFinding struct {
+	File string `json:"file"`
+	lint.Finding
+}
+
+// runLint 检查测试定义文件，存在问题时返回 1。
+//
+//	testplane lint tests/smoke.yaml tests/soak.yaml
+//	testplane lint --output json --fail-on error tests/*.yaml
+func runLint(args []string) int {
+	fs := flag.NewFlagSet("lint", flag.ContinueOnError)
+	output := fs.String("output", "text", "输出格式：text 或 json")
+	failOn := fs.String("fail-on", string(lint.SeverityWarning), "达到该级别的问题时返回 1：warning 或 error")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() == 0 {
+		fmt.Fprintln(os.Stderr, "at least one file is required")
+		fs.Usage()
+		return 2
+	}
+	if *output != "text" && *output != "json" {
+		fmt.Fprintf(os.Stderr, "invalid --output %q\n", *output)
+		return 2
+	}
+	if *failOn != string(lint.SeverityWarning) && *failOn != string(lint.SeverityError) {
+		fmt.Fprintf(os.Stderr, "invalid --fail-on %q\n", *failOn)
+		return 2
+	}
+
+	findings := []lintFinding{}
+	for _, file := range fs.Args() {
+		objects, err := assertiontest.LoadFixtures(file)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 2
+		}
+		for _, obj := range objects {
+			results, err := lint.Object(obj)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "%s: %v\n", file, err)
+				return 2
+			}
+			for _, f := range results {
+				findings = append(findings, lintFinding{File: file, Finding: f})
+			}
+		}
+	}
+
+	exitCode := 0
+	for _, f := range findings {
+		if *failOn == string(lint.SeverityWarning) || f.Severity == lint.SeverityError {
+			exitCode = 1
+		}
+	}
+
+	if *output == "json" {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(findings); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 2
+		}
+		return exitCode
+	}
+	for _, f := range findings {
+		fmt.Printf("%s\t%s/%s\t%s\t%s\t%s: %s\n", f.File, f.Kind, f.Name, f.Severity, f.Rule, f.Path, f.Message)
+	}
+	return exitCode
+}
@@ -0,0 +1,160 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/yaml"
+
+	infrav1alpha1 "github.com/lunz1207/testplane/api/v1alpha1"
+	"github.com/lunz1207/testplane/pkg/assertiontest"
+	"github.com/lunz1207/testplane/pkg/render"
+)
+
+// renderedResource 带文件名与测试名的展开结果。
+type renderedResource struct {
+	File string `json:"file"`
+	Test string `json:"test"`
+	render.Resource
+}
+
+// runRender 展开测试中的资源模板，输出控制器将 apply（或 delete）的清单，不访问集群。
+//
+//	testplane render tests/smoke.yaml
+//	testplane render --environment env/staging.yaml --workspace ws-demo tests/smoke.yaml
+//	testplane render --output table tests/*.yaml
+//	testplane render tests/smoke.yaml bases/configmaps.yaml   # 资源 base 引用的 ConfigMap 与测试一起传入
+func runRender(args []string) int {
+	fs := flag.NewFlagSet("render", flag.ContinueOnError)
+	envFile := fs.String("environment", "", "替换 ${env.*} 使用的 Environment 清单文件")
+	workspace := fs.String("workspace", "", "替换 ${WORKSPACE} 使用的工作区名称")
+	namespace := fs.String("namespace", "default", "测试未设置命名空间时使用的命名空间")
+	output := fs.String("output", "yaml", "输出格式：yaml、table 或 json")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() == 0 {
+		fmt.Fprintln(os.Stderr, "at least one file is required")
+		fs.Usage()
+		return 2
+	}
+	if *output != "yaml" && *output != "table" && *output != "json" {
+		fmt.Fprintf(os.Stderr, "invalid --output %q\n", *output)
+		return 2
+	}
+
+	opts := render.Options{Workspace: *workspace, Namespace: *namespace}
+	if *envFile != "" {
+		env, err := loadEnvironment(*envFile)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 2
+		}
+		opts.Environment = env
+	}
+
+	// 输入文件中的 ConfigMap 作为资源 base 的来源
+	inputs := map[string][]map[string]interface{}{}
+	for _, file := range fs.Args() {
+		objects, err := assertiontest.LoadFixtures(file)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 2
+		}
+		inputs[file] = objects
+		for _, obj := range objects {
+			if obj["kind"] != "ConfigMap" {
+				continue
+			}
+			var cm corev1.ConfigMap
+			if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj, &cm); err != nil {
+				fmt.Fprintf(os.Stderr, "%s: decode ConfigMap: %v\n", file, err)
+				return 2
+			}
+			opts.ConfigMaps = append(opts.ConfigMaps, cm)
+		}
+	}
+
+	resources := []renderedResource{}
+	for _, file := range fs.Args() {
+		for _, obj := range inputs[file] {
+			results, err := render.Object(obj, opts)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "%s: %v\n", file, err)
+				return 1
+			}
+			name, _, _ := unstructured.NestedString(obj, "metadata", "name")
+			for _, r := range results {
+				resources = append(resources, renderedResource{File: file, Test: fmt.Sprintf("%v/%s", obj["kind"], name), Resource: r})
+			}
+		}
+	}
+
+	switch *output {
+	case "json":
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(resources); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 2
+		}
+	case "table":
+		for _, r := range resources {
+			fmt.Printf("%s\t%s\t%s\t%s\t%s/%s\t%s\n", r.Test, r.Source, r.Action, r.Kind, r.Namespace, r.Name, r.Hash)
+		}
+	default:
+		for _, r := range resources {
+			data, err := yaml.Marshal(r.Object)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				return 2
+			}
+			fmt.Printf("---\n# %s %s %s hash=%s\n%s", r.Test, r.Source, r.Action, r.Hash, data)
+		}
+	}
+	return 0
+}
+
+// loadEnvironment 读取文件中的第一个 Environment。
+func loadEnvironment(file string) (*infrav1alpha1.Environment, error) {
+	objects, err := assertiontest.LoadFixtures(file)
+	if err != nil {
+		return nil, err
+	}
+	for _, obj := range objects {
+		if obj["kind"] != "Environment" {
+			continue
+		}
+		data, err := json.Marshal(obj)
+		if err != nil {
+			return nil, err
+		}
+		var env infrav1alpha1.Environment
+		if err := json.Unmarshal(data, &env); err != nil {
+			return nil, fmt.Errorf("%s: decode Environment: %w", file, err)
+		}
+		return &env, nil
+	}
+	return nil, fmt.Errorf("%s: no Environment found", file)
+}
@@ -0,0 +1,102 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	infrav1alpha1 "github.com/lunz1207/testplane/api/v1alpha1"
+	"github.com/lunz1207/testplane/pkg/assertiontest"
+	"github.com/lunz1207/testplane/pkg/recording"
+)
+
+// runReplay 对录制归档中的快照重新执行期望，并与录制结果比较。
+// 存在结果不一致的快照时返回 1。
+//
+//	testplane replay --archive default/integrationtest-demo.jsonl
+//	testplane replay --archive default/loadtest-soak.jsonl --index 4123 --verbose
+func runReplay(args []string) int {
+	fs := flag.NewFlagSet("replay", flag.ContinueOnError)
+	archive := fs.String("archive", "", "录制归档文件（--record-dir 下的 .jsonl 文件）")
+	index := fs.Int("index", -1, "只重放指定序号（从 0 开始）的快照")
+	failedOnly := fs.Bool("failed-only", false, "只重放录制时未通过的快照")
+	verbose := fs.Bool("verbose", false, "输出每个期望的结果")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if *archive == "" {
+		fmt.Fprintln(os.Stderr, "--archive is required")
+		fs.Usage()
+		return 2
+	}
+
+	snapshots, err := recording.ReadArchive(*archive)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+	if *index >= len(snapshots) {
+		fmt.Fprintf(os.Stderr, "index %d out of range (%d snapshots)\n", *index, len(snapshots))
+		return 2
+	}
+
+	h := assertiontest.New()
+	exitCode := 0
+	for i, snapshot := range snapshots {
+		if (*index >= 0 && i != *index) || (*failedOnly && snapshot.Passed) {
+			continue
+		}
+
+		results, replayed, err := h.Replay(snapshot)
+		status := "same"
+		if replayed != snapshot.Passed {
+			status = "DIFF"
+			exitCode = 1
+		}
+		fmt.Printf("#%d\t%s\t%s\trecorded=%s\treplayed=%s\t%s\n",
+			i, snapshot.Time.Format(time.RFC3339), snapshot.Condition,
+			passText(snapshot.Passed), passText(replayed), status)
+		if err != nil {
+			fmt.Printf("\terror: %v\n", err)
+		}
+		if *verbose {
+			printResults("recorded", snapshot.Results)
+			printResults("replayed", results)
+		}
+	}
+	return exitCode
+}
+
+func passText(passed bool) string {
+	if passed {
+		return "PASS"
+	}
+	return "FAIL"
+}
+
+func printResults(label string, results []infrav1alpha1.ExpectationResult) {
+	for _, r := range results {
+		name := r.Expect
+		if r.Name != "" {
+			name = fmt.Sprintf("%s (%s)", r.Name, r.Expect)
+		}
+		fmt.Printf("\t%s\t%s\t%s\tmessage=%q actual=%q\n", label, name, passText(r.Passed), r.Message, r.Actual)
+	}
+}
@@ -18,6 +18,7 @@ package builtins
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/lunz1207/testplane/internal/plugin"
@@ -233,3 +234,228 @@ func ClusterCeased(resource, params map[string]interface{}) plugin.Result {
 	}
 	return plugin.Fail("cluster not ceased").WithActual(fmt.Sprintf("phase=%s, transition=%s", phase, transition))
 }
+
+// nodeZone 读取节点所在可用区（兼容 zone、zoneID、availabilityZone 字段）。
+func nodeZone(node map[string]interface{}) string {
+	for _, key := range []string{"zone", "zoneID", "availabilityZone"} {
+		if zone := plugin.GetString(node, key); zone != "" {
+			return zone
+		}
+	}
+	return ""
+}
+
+// ClusterNodesSpreadAcrossZones 检查集群节点是否分布在足够多的可用区（HA 校验）。
+// params: minZones (int, 默认 2), role (string, 可选，仅统计该角色节点)
+func ClusterNodesSpreadAcrossZones(resource, params map[string]interface{}) plugin.Result {
+	status := plugin.GetMap(resource, "status")
+	if status == nil {
+		return plugin.Fail("no status")
+	}
+
+	minZones := plugin.GetInt(params, "minZones")
+	if minZones <= 0 {
+		minZones = 2
+	}
+	role := plugin.GetString(params, "role")
+
+	zones := make(map[string]int)
+	missing := 0
+	for _, node := range plugin.GetSlice(status, "nodes") {
+		nodeMap, ok := node.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if role != "" && !strings.EqualFold(plugin.GetString(nodeMap, "role"), role) {
+			continue
+		}
+		zone := nodeZone(nodeMap)
+		if zone == "" {
+			missing++
+			continue
+		}
+		zones[zone]++
+	}
+
+	names := make([]string, 0, len(zones))
+	for z, n := range zones {
+		names = append(names, fmt.Sprintf("%s=%d", z, n))
+	}
+	sort.Strings(names)
+	actual := strings.Join(names, ",")
+	if missing > 0 {
+		actual = fmt.Sprintf("%s (no zone: %d)", actual, missing)
+	}
+
+	if len(zones) >= minZones {
+		return plugin.Pass()
+	}
+	return plugin.Fail(fmt.Sprintf("nodes spread across %d zones, want >= %d", len(zones), minZones)).WithActual(actual)
+}
+
+// ClusterVersionEquals 检查集群当前版本（status.currentVersion）是否为期望值。
+// params: version (string, 可选，默认取 spec.version)
+func ClusterVersionEquals(resource, params map[string]interface{}) plugin.Result {
+	status := plugin.GetMap(resource, "status")
+	if status == nil {
+		return plugin.Fail("no status")
+	}
+
+	expected := plugin.GetString(params, "version")
+	if expected == "" {
+		expected = plugin.GetNestedString(resource, "spec.version")
+	}
+	if expected == "" {
+		return plugin.Fail("missing required param: version (spec.version is empty)")
+	}
+
+	actual := plugin.GetString(status, "currentVersion")
+	if actual == expected {
+		return plugin.Pass()
+	}
+	return plugin.Fail(fmt.Sprintf("expected version=%s", expected)).WithActual(actual)
+}
+
+// ClusterUpgradeCompleted 检查集群升级是否完成。
+// 完成条件：status.currentVersion == 目标版本、无 transitionStatus、所有节点 version 均为目标版本。
+// params: version (string, 可选，默认取 spec.version)
+func ClusterUpgradeCompleted(resource, params map[string]interface{}) plugin.Result {
+	status := plugin.GetMap(resource, "status")
+	if status == nil {
+		return plugin.Fail("no status")
+	}
+
+	target := plugin.GetString(params, "version")
+	if target == "" {
+		target = plugin.GetNestedString(resource, "spec.version")
+	}
+	if target == "" {
+		return plugin.Fail("missing required param: version (spec.version is empty)")
+	}
+
+	current := plugin.GetString(status, "currentVersion")
+	transition := plugin.GetString(status, "transitionStatus")
+	if current != target || transition != "" {
+		return plugin.Fail(fmt.Sprintf("cluster not upgraded to %s", target)).
+			WithActual(fmt.Sprintf("currentVersion=%s, transition=%s", current, transition))
+	}
+
+	var lagging []string
+	for i, node := range plugin.GetSlice(status, "nodes") {
+		nodeMap, ok := node.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if v := plugin.GetString(nodeMap, "version"); v != target {
+			id := plugin.GetString(nodeMap, "nodeID")
+			if id == "" {
+				id = fmt.Sprintf("#%d", i)
+			}
+			lagging = append(lagging, fmt.Sprintf("%s=%s", id, v))
+		}
+	}
+	if len(lagging) > 0 {
+		return plugin.Fail(fmt.Sprintf("%d nodes not on version %s", len(lagging), target)).
+			WithActual(strings.Join(lagging, ","))
+	}
+	return plugin.Pass()
+}
+
+// findBackupEntry 在 status 列表中查找备份/快照条目。
+// id 非空时按 idKeys 匹配；否则返回 finishedAt（缺失时 createTime）最新的条目。
+func findBackupEntry(entries []interface{}, id string, idKeys ...string) map[string]interface{} {
+	var latest map[string]interface{}
+	latestTime := ""
+	for _, e := range entries {
+		entry, ok := e.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if id != "" {
+			for _, key := range idKeys {
+				if plugin.GetString(entry, key) == id {
+					return entry
+				}
+			}
+			continue
+		}
+		// RFC3339 时间字符串可直接按字典序比较
+		t := plugin.GetString(entry, "finishedAt")
+		if t == "" {
+			t = plugin.GetString(entry, "createTime")
+		}
+		if latest == nil || t > latestTime {
+			latest, latestTime = entry, t
+		}
+	}
+	return latest
+}
+
+// ClusterBackupCompleted 检查集群备份是否完成（读取 status.backups）。
+// 完成条件：条目 state 为期望值且 finishedAt 已设置。
+// params: backupID (string, 可选，默认最新一条), state (string, 默认 "completed")
+func ClusterBackupCompleted(resource, params map[string]interface{}) plugin.Result {
+	status := plugin.GetMap(resource, "status")
+	if status == nil {
+		return plugin.Fail("no status")
+	}
+
+	backupID := plugin.GetString(params, "backupID")
+	expectedState := plugin.GetString(params, "state")
+	if expectedState == "" {
+		expectedState = "completed"
+	}
+
+	entry := findBackupEntry(plugin.GetSlice(status, "backups"), backupID, "backupID", "id", "name")
+	if entry == nil {
+		if backupID != "" {
+			return plugin.Fail(fmt.Sprintf("backup %s not found", backupID))
+		}
+		return plugin.Fail("no backups found")
+	}
+
+	state := plugin.GetString(entry, "state")
+	finishedAt := plugin.GetString(entry, "finishedAt")
+	actual := fmt.Sprintf("state=%s, finishedAt=%s", state, finishedAt)
+	if !strings.EqualFold(state, expectedState) {
+		return plugin.Fail(fmt.Sprintf("expected backup state=%s", expectedState)).WithActual(actual)
+	}
+	if finishedAt == "" {
+		return plugin.Fail("backup has no finishedAt").WithActual(actual)
+	}
+	return plugin.Pass()
+}
+
+// SnapshotAvailable 检查快照是否可用（读取 status.snapshots，缺失时回退到 status.backups）。
+// params: snapshotID (string, 可选，默认最新一条), state (string, 默认 "available")
+func SnapshotAvailable(resource, params map[string]interface{}) plugin.Result {
+	status := plugin.GetMap(resource, "status")
+	if status == nil {
+		return plugin.Fail("no status")
+	}
+
+	snapshotID := plugin.GetString(params, "snapshotID")
+	expectedState := plugin.GetString(params, "state")
+	if expectedState == "" {
+		expectedState = "available"
+	}
+
+	entries := plugin.GetSlice(status, "snapshots")
+	if entries == nil {
+		entries = plugin.GetSlice(status, "backups")
+	}
+
+	entry := findBackupEntry(entries, snapshotID, "snapshotID", "id", "name")
+	if entry == nil {
+		if snapshotID != "" {
+			return plugin.Fail(fmt.Sprintf("snapshot %s not found", snapshotID))
+		}
+		return plugin.Fail("no snapshots found")
+	}
+
+	state := plugin.GetString(entry, "state")
+	if strings.EqualFold(state, expectedState) {
+		return plugin.Pass()
+	}
+	return plugin.Fail(fmt.Sprintf("expected snapshot state=%s", expectedState)).WithActual(fmt.Sprintf("state=%s", state))
+}
@@ -0,0 +1,107 @@
+package builtins
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Cluster builtins", func() {
+	node := func(id, role, zone, version string) obj {
+		return obj{"nodeID": id, "role": role, "zone": zone, "version": version}
+	}
+	cluster := obj{
+		"spec": obj{"version": "1.30"},
+		"status": obj{
+			"currentVersion": "1.30",
+			"nodes": []interface{}{
+				node("m1", "master", "zone-a", "1.30"),
+				node("w1", "worker", "zone-a", "1.30"),
+				node("w2", "Worker", "zone-b", "1.30"),
+				obj{"nodeID": "w3", "role": "worker", "availabilityZone": "zone-c", "version": "1.30"},
+			},
+		},
+	}
+
+	DescribeTable("ClusterNodesSpreadAcrossZones",
+		func(params obj, passed bool, message string) {
+			expectResult(ClusterNodesSpreadAcrossZones(cluster, params), passed, message)
+		},
+		Entry("default two zones", obj{}, true, ""),
+		Entry("three zones", obj{"minZones": 3}, true, ""),
+		Entry("too few zones", obj{"minZones": 4}, false, "nodes spread across 3 zones, want >= 4"),
+		Entry("role filter is case-insensitive", obj{"role": "worker", "minZones": 3}, true, ""),
+		Entry("role in a single zone", obj{"role": "master"}, false, "nodes spread across 1 zones"),
+	)
+
+	DescribeTable("ClusterNodeCountByRole",
+		func(params obj, passed bool, value string) {
+			result := ClusterNodeCountByRole(cluster, params)
+			Expect(result.Passed).To(Equal(passed), result.Message)
+			Expect(result.Value).To(Equal(value))
+		},
+		Entry("all nodes", obj{}, true, "4"),
+		Entry("by role", obj{"role": "WORKER"}, true, "3"),
+		Entry("expected count", obj{"role": "master", "expected": 1}, true, "1"),
+		Entry("unexpected count", obj{"role": "master", "expected": 3}, false, "1"),
+	)
+
+	upgrading := obj{
+		"spec": obj{"version": "1.31"},
+		"status": obj{
+			"currentVersion": "1.31",
+			"nodes":          []interface{}{node("m1", "master", "zone-a", "1.31"), obj{"version": "1.30"}},
+		},
+	}
+
+	DescribeTable("ClusterVersionEquals and ClusterUpgradeCompleted",
+		func(resource, params obj, versionPassed, upgradePassed bool, message string) {
+			Expect(ClusterVersionEquals(resource, params).Passed).To(Equal(versionPassed))
+			expectResult(ClusterUpgradeCompleted(resource, params), upgradePassed, message)
+		},
+		Entry("upgraded to spec.version", cluster, obj{}, true, true, ""),
+		Entry("explicit version", cluster, obj{"version": "1.29"}, false, false, "cluster not upgraded to 1.29"),
+		Entry("node lagging behind", upgrading, obj{}, true, false, "1 nodes not on version 1.31"),
+		Entry("transition in progress",
+			obj{"spec": obj{"version": "1.30"}, "status": obj{"currentVersion": "1.30", "transitionStatus": "Upgrading"}},
+			obj{}, true, false, "cluster not upgraded to 1.30"),
+		Entry("no target version", obj{"status": obj{"currentVersion": "1.30"}}, obj{}, false, false, "missing required param: version"),
+	)
+
+	It("should name lagging nodes by ID or index", func() {
+		Expect(ClusterUpgradeCompleted(upgrading, obj{}).Actual).To(Equal("#1=1.30"))
+	})
+
+	backups := obj{"status": obj{"backups": []interface{}{
+		obj{"backupID": "b1", "state": "Completed", "finishedAt": "2025-06-01T10:00:00Z"},
+		obj{"id": "b2", "state": "failed", "finishedAt": "2025-06-02T10:00:00Z"},
+		obj{"name": "b3", "state": "running", "createTime": "2025-06-03T10:00:00Z"},
+	}}}
+
+	DescribeTable("ClusterBackupCompleted",
+		func(params obj, passed bool, message string) {
+			expectResult(ClusterBackupCompleted(backups, params), passed, message)
+		},
+		Entry("by backupID, state case-insensitive", obj{"backupID": "b1"}, true, ""),
+		Entry("by id", obj{"backupID": "b2"}, false, "expected backup state=completed"),
+		Entry("expected failure state", obj{"backupID": "b2", "state": "failed"}, true, ""),
+		Entry("latest entry by createTime", obj{}, false, "expected backup state=completed"),
+		Entry("unfinished entry", obj{"backupID": "b3", "state": "running"}, false, "backup has no finishedAt"),
+		Entry("unknown backup", obj{"backupID": "b9"}, false, "backup b9 not found"),
+	)
+
+	DescribeTable("SnapshotAvailable",
+		func(resource, params obj, passed bool, message string) {
+			expectResult(SnapshotAvailable(resource, params), passed, message)
+		},
+		Entry("latest snapshot",
+			obj{"status": obj{"snapshots": []interface{}{
+				obj{"snapshotID": "s1", "state": "creating", "createTime": "2025-06-01T10:00:00Z"},
+				obj{"snapshotID": "s2", "state": "Available", "createTime": "2025-06-02T10:00:00Z"},
+			}}}, obj{}, true, ""),
+		Entry("by snapshotID",
+			obj{"status": obj{"snapshots": []interface{}{obj{"snapshotID": "s1", "state": "creating"}}}},
+			obj{"snapshotID": "s1"}, false, "expected snapshot state=available"),
+		Entry("falls back to backups", backups, obj{"snapshotID": "b3", "state": "running"}, true, ""),
+		Entry("no snapshots", obj{"status": obj{}}, obj{}, false, "no snapshots found"),
+	)
+})
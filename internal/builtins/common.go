@@ -71,3 +71,41 @@ func DeploymentAvailable(resource, params map[string]interface{}) plugin.Result
 	return plugin.Fail(fmt.Sprintf("deployment not available: %d/%d replicas ready", readyReplicas, desiredReplicas)).
 		WithActual(fmt.Sprintf("available=%d, ready=%d, desired=%d", availableReplicas, readyReplicas, desiredReplicas))
 }
+
+// OwnedBy 检查资源的 ownerReferences 中包含期望的 owner，用于验证被测 Operator 的 GC 关联。
+// params: kind (string), name (string), apiVersion (string, 可选), controller (bool, 可选：要求该引用为 controller 引用)。
+func OwnedBy(resource, params map[string]interface{}) plugin.Result {
+	if len(resource) == 0 {
+		return plugin.Fail("resource not found")
+	}
+	kind := plugin.GetString(params, "kind")
+	name := plugin.GetString(params, "name")
+	if kind == "" || name == "" {
+		return plugin.Fail("kind and name are required")
+	}
+	apiVersion := plugin.GetString(params, "apiVersion")
+	requireController := plugin.GetBoolOr(params, "controller", false)
+
+	refs := plugin.GetNestedSlice(resource, "metadata.ownerReferences")
+	owners := make([]string, 0, len(refs))
+	for _, item := range refs {
+		ref, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		refKind := plugin.GetString(ref, "kind")
+		refName := plugin.GetString(ref, "name")
+		owners = append(owners, refKind+"/"+refName)
+		if refKind != kind || refName != name {
+			continue
+		}
+		if apiVersion != "" && plugin.GetString(ref, "apiVersion") != apiVersion {
+			continue
+		}
+		if requireController && !plugin.GetBoolOr(ref, "controller", false) {
+			return plugin.Fail(fmt.Sprintf("owner %s/%s is not the controller", kind, name)).WithActual(owners)
+		}
+		return plugin.Pass()
+	}
+	return plugin.Fail(fmt.Sprintf("not owned by %s/%s", kind, name)).WithActual(owners)
+}
@@ -0,0 +1,58 @@
+package builtins
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/lunz1207/testplane/internal/plugin"
+)
+
+// obj 测试中构造资源与参数的简写。
+type obj = map[string]interface{}
+
+// expectResult 断言结果是否通过，message 非空时同时断言消息。
+func expectResult(result plugin.Result, passed bool, message string) {
+	Expect(result.Passed).To(Equal(passed), result.Message)
+	if message != "" {
+		Expect(result.Message).To(ContainSubstring(message))
+	}
+}
+
+var _ = Describe("Common builtins", func() {
+	pod := obj{"metadata": obj{"ownerReferences": []interface{}{
+		obj{"apiVersion": "apps/v1", "kind": "ReplicaSet", "name": "web-5d8f", "controller": true},
+		obj{"apiVersion": "v1", "kind": "ConfigMap", "name": "settings"},
+	}}}
+
+	DescribeTable("OwnedBy",
+		func(resource, params obj, passed bool, message string) {
+			expectResult(OwnedBy(resource, params), passed, message)
+		},
+		Entry("kind and name", pod, obj{"kind": "ReplicaSet", "name": "web-5d8f"}, true, ""),
+		Entry("matching apiVersion", pod, obj{"apiVersion": "apps/v1", "kind": "ReplicaSet", "name": "web-5d8f"}, true, ""),
+		Entry("other apiVersion", pod, obj{"apiVersion": "extensions/v1beta1", "kind": "ReplicaSet", "name": "web-5d8f"}, false, "not owned by ReplicaSet/web-5d8f"),
+		Entry("other name", pod, obj{"kind": "ReplicaSet", "name": "web-7c9d"}, false, "not owned by ReplicaSet/web-7c9d"),
+		Entry("other kind with the same name", pod, obj{"kind": "Deployment", "name": "web-5d8f"}, false, "not owned by"),
+		Entry("controller owner", pod, obj{"kind": "ReplicaSet", "name": "web-5d8f", "controller": true}, true, ""),
+		Entry("non-controller owner", pod, obj{"kind": "ConfigMap", "name": "settings", "controller": true}, false, "is not the controller"),
+		Entry("non-controller owner allowed", pod, obj{"kind": "ConfigMap", "name": "settings", "controller": false}, true, ""),
+		Entry("no owner references", obj{"metadata": obj{"name": "orphan"}}, obj{"kind": "ReplicaSet", "name": "web-5d8f"}, false, "not owned by"),
+		Entry("missing name", pod, obj{"kind": "ReplicaSet"}, false, "kind and name are required"),
+		Entry("missing resource", obj{}, obj{"kind": "ReplicaSet", "name": "web-5d8f"}, false, "resource not found"),
+	)
+
+	It("should list the owners in the failure", func() {
+		result := OwnedBy(pod, obj{"kind": "Deployment", "name": "web"})
+		Expect(result.Actual).To(ContainSubstring("ReplicaSet/web-5d8f"))
+		Expect(result.Actual).To(ContainSubstring("ConfigMap/settings"))
+	})
+
+	DescribeTable("ResourceExists and ResourceNotExists",
+		func(resource obj, exists bool) {
+			Expect(ResourceExists(resource, nil).Passed).To(Equal(exists))
+			Expect(ResourceNotExists(resource, nil).Passed).To(Equal(!exists))
+		},
+		Entry("present", obj{"metadata": obj{"name": "web"}}, true),
+		Entry("absent", obj{}, false),
+	)
+})
@@ -0,0 +1,391 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package builtins
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+
+	apiresource "k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/lunz1207/testplane/internal/plugin"
+)
+
+// FieldsMatchAcrossResources 比较两个资源的字段。
+// params: left/right (string, 状态键或别名，为空时为默认被断言资源),
+// leftPath/rightPath (string, 如 "spec.ports[0].targetPort"),
+// operator (string, 可选：==（默认）、!=、>、>=、<、<=、contains、regex、in，见 compareValues；大小比较按数值)。
+// 字段为列表时按长度比较，如 "_related.nodes" 为关联资源数量。
+func FieldsMatchAcrossResources(lookup plugin.Lookup, params map[string]interface{}) plugin.Result {
+	left, res := resolveComparedField(lookup, plugin.GetString(params, "left"), plugin.GetString(params, "leftPath"))
+	if res != nil {
+		return *res
+	}
+	right, res := resolveComparedField(lookup, plugin.GetString(params, "right"), plugin.GetString(params, "rightPath"))
+	if res != nil {
+		return *res
+	}
+
+	operator := plugin.GetString(params, "operator")
+	if operator == "" {
+		operator = "=="
+	}
+	actual := fmt.Sprintf("%s %s %s", left, operator, right)
+
+	matched, err := compareValues(left, operator, right)
+	if err != nil {
+		return plugin.Fail(err.Error()).WithActual(actual)
+	}
+	if matched {
+		return plugin.Pass()
+	}
+	return plugin.Fail(fmt.Sprintf("expected %s %s %s", plugin.GetString(params, "leftPath"), operator, plugin.GetString(params, "rightPath"))).
+		WithActual(actual)
+}
+
+// FieldCompare 将资源字段与给定值比较。
+// params: path (string, 如 "status.stats.maxRoundSeconds"), value (比较值),
+// operator (string, 可选，同 FieldsMatchAcrossResources)。
+// 字段为列表时按长度比较。
+func FieldCompare(resource, params map[string]interface{}) plugin.Result {
+	path := plugin.GetString(params, "path")
+	if path == "" {
+		return plugin.Fail("path is required")
+	}
+	raw, ok := params["value"]
+	if !ok {
+		return plugin.Fail("value is required")
+	}
+	// 字段按字符串读取（列表为其长度），比较值同样转为字符串，in 的候选列表保留
+	var expected interface{} = formatValue(raw)
+	if list, isList := raw.([]interface{}); isList {
+		expected = list
+	}
+	value, found := fieldValue(resource, path)
+	if !found {
+		return plugin.Fail(fmt.Sprintf("field %s not found", path))
+	}
+
+	operator := plugin.GetString(params, "operator")
+	if operator == "" {
+		operator = "=="
+	}
+	matched, err := compareValues(value, operator, expected)
+	if err != nil {
+		return plugin.Fail(err.Error()).WithActual(value)
+	}
+	if matched {
+		return plugin.Pass()
+	}
+	return plugin.Fail(fmt.Sprintf("expected %s %s %s", path, operator, expected)).WithActual(value)
+}
+
+// UnchangedFields 断言字段在步骤 apply 前后保持不变（如升级后 clusterID、VIP 不变）。
+// params: paths ([]string, 如 ["status.clusterID", "status.vip"])。
+// apply 前的快照由控制器保存在资源的 _before 字段下，资源由该步骤创建时快照为空（字段均视为不存在）；
+// 快照丢失（控制器重启）时无法判定。
+// 字段在前后都不存在视为不变。
+func UnchangedFields(resource, params map[string]interface{}) plugin.Result {
+	var paths []string
+	for _, p := range plugin.GetSlice(params, "paths") {
+		if path, ok := p.(string); ok && path != "" {
+			paths = append(paths, path)
+		}
+	}
+	if len(paths) == 0 {
+		return plugin.Fail("paths is required")
+	}
+	before := plugin.GetMap(resource, "_before")
+	if before == nil {
+		return plugin.Unknown("no pre-step snapshot recorded for this resource (lost after a controller restart?)")
+	}
+
+	var changed []string
+	for _, path := range paths {
+		old, oldFound := fieldValue(before, path)
+		current, found := fieldValue(resource, path)
+		if oldFound == found && valuesEqual(old, current) {
+			continue
+		}
+		changed = append(changed, fmt.Sprintf("%s: %s -> %s", path, describeField(old, oldFound), describeField(current, found)))
+	}
+	if len(changed) == 0 {
+		return plugin.Pass()
+	}
+	return plugin.Fail(fmt.Sprintf("%d field(s) changed", len(changed))).WithActual(strings.Join(changed, "; "))
+}
+
+// describeField 返回字段值的显示形式，不存在时为 <absent>。
+func describeField(value string, found bool) string {
+	if !found {
+		return "<absent>"
+	}
+	return value
+}
+
+// resolveComparedField 获取资源并读取字段，失败时返回未通过的结果。
+func resolveComparedField(lookup plugin.Lookup, name, path string) (string, *plugin.Result) {
+	if path == "" {
+		res := plugin.Fail("leftPath and rightPath are required")
+		return "", &res
+	}
+	obj, err := lookup(name)
+	if err != nil {
+		res := plugin.Fail(err.Error())
+		return "", &res
+	}
+	value, found := fieldValue(obj, path)
+	if !found {
+		label := name
+		if label == "" {
+			label = "resource"
+		}
+		res := plugin.Fail(fmt.Sprintf("%s: field %s not found", label, path))
+		return "", &res
+	}
+	return value, nil
+}
+
+// fieldValue 按路径读取字段的字符串表示，支持 "a.b[0].c" 形式的列表下标；
+// 列表返回其长度，对象返回 false。
+func fieldValue(obj map[string]interface{}, path string) (string, bool) {
+	var current interface{} = obj
+	for _, part := range strings.Split(strings.TrimPrefix(path, "."), ".") {
+		key, indexes := part, []int(nil)
+		if i := strings.Index(part, "["); i >= 0 {
+			key = part[:i]
+			for _, idx := range strings.Split(strings.TrimSuffix(part[i+1:], "]"), "][") {
+				n, err := strconv.Atoi(idx)
+				if err != nil {
+					return "", false
+				}
+				indexes = append(indexes, n)
+			}
+		}
+		if key != "" {
+			m, ok := current.(map[string]interface{})
+			if !ok {
+				return "", false
+			}
+			if current, ok = m[key]; !ok {
+				return "", false
+			}
+		}
+		for _, n := range indexes {
+			list, ok := current.([]interface{})
+			if !ok || n < 0 || n >= len(list) {
+				return "", false
+			}
+			current = list[n]
+		}
+	}
+
+	switch v := current.(type) {
+	case nil, map[string]interface{}:
+		return "", false
+	case []interface{}:
+		return strconv.Itoa(len(v)), true
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), true
+	default:
+		return fmt.Sprintf("%v", v), true
+	}
+}
+
+// operatorAliases 运算符的字母别名（如 eq 即 ==）。
+var operatorAliases = map[string]string{"eq": "==", "ne": "!=", "gt": ">", "ge": ">=", "lt": "<", "le": "<="}
+
+// compareValues 按运算符比较字段值与期望值，FieldCompare、FieldsMatchAcrossResources、FieldEquals 共用。
+// 运算符：==、!=、>、>=、<、<=（也可写作 eq、ne、gt、ge、lt、le）、contains、regex、in；
+// 运算符不支持或值类型不适用时返回错误。
+func compareValues(actual interface{}, operator string, expected interface{}) (bool, error) {
+	if alias, ok := operatorAliases[operator]; ok {
+		operator = alias
+	}
+	switch operator {
+	case "==":
+		return valuesEqual(actual, expected), nil
+	case "!=":
+		return !valuesEqual(actual, expected), nil
+	case ">", ">=", "<", "<=":
+		l, lok := numericValue(actual)
+		r, rok := numericValue(expected)
+		if !lok || !rok {
+			return false, fmt.Errorf("operator %s requires numeric values", operator)
+		}
+		switch operator {
+		case ">":
+			return l > r, nil
+		case ">=":
+			return l >= r, nil
+		case "<":
+			return l < r, nil
+		default:
+			return l <= r, nil
+		}
+	case "contains":
+		switch v := actual.(type) {
+		case string:
+			return strings.Contains(v, formatValue(expected)), nil
+		case []interface{}:
+			for _, item := range v {
+				if valuesEqual(item, expected) {
+					return true, nil
+				}
+			}
+			return false, nil
+		case map[string]interface{}:
+			_, ok := v[formatValue(expected)]
+			return ok, nil
+		default:
+			return false, fmt.Errorf("operator contains requires a string, list or object field")
+		}
+	case "regex":
+		pattern, ok := expected.(string)
+		if !ok {
+			return false, fmt.Errorf("operator regex requires a string pattern")
+		}
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return false, fmt.Errorf("invalid pattern %q: %w", pattern, err)
+		}
+		if !isScalar(actual) {
+			return false, fmt.Errorf("operator regex requires a scalar field")
+		}
+		return re.MatchString(formatValue(actual)), nil
+	case "in":
+		candidates, ok := expected.([]interface{})
+		if !ok {
+			return false, fmt.Errorf("operator in requires a list value")
+		}
+		for _, c := range candidates {
+			if valuesEqual(actual, c) {
+				return true, nil
+			}
+		}
+		return false, nil
+	default:
+		return false, fmt.Errorf("unsupported operator %q", operator)
+	}
+}
+
+// valuesEqual 比较两个值：数值按数值比较（数值与可解析为数值的字符串也按数值比较，如 8080 与 "8080"），
+// 列表与对象逐项比较，其余按类型与值比较。
+func valuesEqual(a, b interface{}) bool {
+	if x, ok := scalarNumber(a); ok {
+		if y, ok := scalarNumber(b); ok {
+			return x == y
+		}
+	}
+	switch x := a.(type) {
+	case []interface{}:
+		y, ok := b.([]interface{})
+		if !ok || len(x) != len(y) {
+			return false
+		}
+		for i := range x {
+			if !valuesEqual(x[i], y[i]) {
+				return false
+			}
+		}
+		return true
+	case map[string]interface{}:
+		y, ok := b.(map[string]interface{})
+		if !ok || len(x) != len(y) {
+			return false
+		}
+		for k, v := range x {
+			if w, ok := y[k]; !ok || !valuesEqual(v, w) {
+				return false
+			}
+		}
+		return true
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+// scalarNumber 返回数值或数值字符串对应的浮点数。
+func scalarNumber(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case int:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case float32:
+		return float64(n), true
+	case float64:
+		return n, true
+	case json.Number:
+		f, err := n.Float64()
+		return f, err == nil
+	case string:
+		f, err := strconv.ParseFloat(n, 64)
+		return f, err == nil
+	}
+	return 0, false
+}
+
+// numericValue 返回大小比较使用的数值：数值、数值字符串、Kubernetes 数量（如 "500m"、"10Gi"），列表取其长度。
+func numericValue(v interface{}) (float64, bool) {
+	if f, ok := scalarNumber(v); ok {
+		return f, true
+	}
+	switch x := v.(type) {
+	case []interface{}:
+		return float64(len(x)), true
+	case string:
+		q, err := apiresource.ParseQuantity(x)
+		if err != nil {
+			return 0, false
+		}
+		return q.AsApproximateFloat64(), true
+	}
+	return 0, false
+}
+
+// isScalar 判断值是否为字符串、数值或布尔值。
+func isScalar(v interface{}) bool {
+	switch v.(type) {
+	case nil, []interface{}, map[string]interface{}:
+		return false
+	}
+	return true
+}
+
+// formatValue 返回值的显示形式：字符串原样返回，数值不带多余的小数位，列表与对象序列化为 JSON。
+func formatValue(v interface{}) string {
+	switch x := v.(type) {
+	case string:
+		return x
+	case float64:
+		return strconv.FormatFloat(x, 'f', -1, 64)
+	case []interface{}, map[string]interface{}:
+		data, err := json.Marshal(x)
+		if err != nil {
+			return fmt.Sprintf("%v", x)
+		}
+		return string(data)
+	default:
+		return fmt.Sprintf("%v", x)
+	}
+}
@@ -0,0 +1,146 @@
+package builtins
+
+import (
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Field comparison", func() {
+	DescribeTable("compareValues",
+		func(actual interface{}, operator string, expected interface{}, want bool) {
+			matched, err := compareValues(actual, operator, expected)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(matched).To(Equal(want))
+		},
+		Entry("== numbers and numeric strings", "8080", "==", 8080.0, true),
+		Entry("== numeric strings", "8080", "==", "8080.0", true),
+		Entry("== strings", "Running", "==", "Pending", false),
+		Entry("== lists item by item", []interface{}{"a", 1.0}, "==", []interface{}{"a", "1"}, true),
+		Entry("== objects", map[string]interface{}{"a": true}, "==", map[string]interface{}{"a": true}, true),
+		Entry("!=", "a", "!=", "b", true),
+		Entry("> numbers", 3.0, ">", 2.0, true),
+		Entry(">= equal numbers", "2", ">=", 2.0, true),
+		Entry("< quantities", "500m", "<", "1", true),
+		Entry("<= list length", []interface{}{"a", "b"}, "<=", 1.0, false),
+		Entry("eq alias", "a", "eq", "a", true),
+		Entry("ne alias", "a", "ne", "a", false),
+		Entry("gt alias", 3.0, "gt", 2.0, true),
+		Entry("ge alias", 2.0, "ge", 3.0, false),
+		Entry("lt alias", "1Gi", "lt", "2Gi", true),
+		Entry("le alias", 2.0, "le", 2.0, true),
+		Entry("contains substring", "nginx:1.25", "contains", ":1.25", true),
+		Entry("contains list item", []interface{}{"a", "b"}, "contains", "b", true),
+		Entry("contains object key", map[string]interface{}{"app": "web"}, "contains", "app", true),
+		Entry("regex", "node-42", "regex", `^node-\d+$`, true),
+		Entry("regex on a number", 42.0, "regex", `^4`, true),
+		Entry("in", "b", "in", []interface{}{"a", "b"}, true),
+		Entry("in numeric", "2", "in", []interface{}{1.0, 2.0}, true),
+	)
+
+	DescribeTable("compareValues errors",
+		func(actual interface{}, operator string, expected interface{}, message string) {
+			_, err := compareValues(actual, operator, expected)
+			Expect(err).To(MatchError(ContainSubstring(message)))
+		},
+		Entry("non-numeric ordering", "abc", ">", 1.0, "requires numeric values"),
+		Entry("contains on a number", 1.0, "contains", "1", "requires a string, list or object"),
+		Entry("regex pattern not a string", "a", "regex", 1.0, "requires a string pattern"),
+		Entry("invalid regex", "a", "regex", "(", "invalid pattern"),
+		Entry("regex on a list", []interface{}{"a"}, "regex", "a", "requires a scalar field"),
+		Entry("in without a list", "a", "in", "a", "requires a list value"),
+		Entry("unknown operator", "a", "~=", "a", "unsupported operator"),
+	)
+
+	DescribeTable("formatValue",
+		func(value interface{}, want string) {
+			Expect(formatValue(value)).To(Equal(want))
+		},
+		Entry("string", "on", "on"),
+		Entry("integral float", 3.0, "3"),
+		Entry("fractional float", 0.25, "0.25"),
+		Entry("bool", true, "true"),
+		Entry("list", []interface{}{"a", 1.0}, `["a",1]`),
+		Entry("object", map[string]interface{}{"b": 1.0, "a": "x"}, `{"a":"x","b":1}`),
+	)
+
+	Context("When comparing a field with a value", func() {
+		resource := map[string]interface{}{
+			"spec":   map[string]interface{}{"ports": []interface{}{map[string]interface{}{"port": 8080.0}}},
+			"status": map[string]interface{}{"phase": "Running", "nodes": []interface{}{"a", "b"}},
+		}
+
+		DescribeTable("FieldCompare",
+			func(params map[string]interface{}, passed bool) {
+				Expect(FieldCompare(resource, params).Passed).To(Equal(passed))
+			},
+			Entry("default ==", map[string]interface{}{"path": "spec.ports[0].port", "value": "8080"}, true),
+			Entry("list length", map[string]interface{}{"path": "status.nodes", "operator": ">=", "value": 2.0}, true),
+			Entry("in", map[string]interface{}{"path": "status.phase", "operator": "in", "value": []interface{}{"Pending", "Running"}}, true),
+			Entry("regex", map[string]interface{}{"path": "status.phase", "operator": "regex", "value": "^Run"}, true),
+			Entry("alias", map[string]interface{}{"path": "status.phase", "operator": "ne", "value": "Running"}, false),
+			Entry("missing field", map[string]interface{}{"path": "status.ready", "value": true}, false),
+		)
+
+		DescribeTable("FieldEquals",
+			func(params map[string]interface{}, passed bool) {
+				Expect(FieldEquals(resource, params).Passed).To(Equal(passed))
+			},
+			Entry("default ==", map[string]interface{}{"path": "{.status.phase}", "value": "Running"}, true),
+			Entry("list value", map[string]interface{}{"path": "status.nodes", "value": []interface{}{"a", "b"}}, true),
+			Entry("wildcard list", map[string]interface{}{"path": "{.spec.ports[*].port}", "operator": "contains", "value": 8080.0}, true),
+			Entry("symbol operator", map[string]interface{}{"path": "spec.ports[0].port", "operator": ">", "value": 80.0}, true),
+			Entry("missing field", map[string]interface{}{"path": "status.ready", "value": true}, false),
+		)
+
+		It("should render lists and objects in the failure", func() {
+			result := FieldEquals(resource, map[string]interface{}{"path": "status", "operator": "==", "value": "x"})
+			Expect(result.Passed).To(BeFalse())
+			Expect(result.Actual).To(Equal(`{"nodes":["a","b"],"phase":"Running"}`))
+
+			result = FieldEquals(resource, map[string]interface{}{"path": "status.nodes", "value": []interface{}{"a"}})
+			Expect(result.Message).To(Equal(`expected status.nodes == ["a"]`))
+			Expect(result.Actual).To(Equal(`["a","b"]`))
+		})
+
+		It("should default FieldMatches to regex", func() {
+			Expect(FieldMatches(resource, map[string]interface{}{"path": "status.phase", "value": "^Run"}).Passed).To(BeTrue())
+		})
+	})
+
+	Context("When comparing fields across resources", func() {
+		states := map[string]map[string]interface{}{
+			"": {"spec": obj{"ports": []interface{}{obj{"targetPort": 8080.0}}}},
+			"deploy": {
+				"spec":     obj{"template": obj{"spec": obj{"containers": []interface{}{obj{"ports": []interface{}{obj{"containerPort": 8080.0}}}}}}},
+				"_related": obj{"pods": []interface{}{obj{}, obj{}}},
+			},
+		}
+		lookup := func(name string) (map[string]interface{}, error) {
+			state, ok := states[name]
+			if !ok {
+				return nil, fmt.Errorf("state %q not found", name)
+			}
+			return state, nil
+		}
+
+		DescribeTable("FieldsMatchAcrossResources",
+			func(params obj, passed bool, message string) {
+				expectResult(FieldsMatchAcrossResources(lookup, params), passed, message)
+			},
+			Entry("default resource against a named one", obj{
+				"leftPath": "spec.ports[0].targetPort", "right": "deploy", "rightPath": "spec.template.spec.containers[0].ports[0].containerPort",
+			}, true, ""),
+			Entry("list length with an operator", obj{
+				"left": "deploy", "leftPath": "_related.pods", "operator": ">=", "right": "deploy", "rightPath": "spec.template.spec.containers",
+			}, true, ""),
+			Entry("mismatch", obj{
+				"leftPath": "spec.ports[0].targetPort", "operator": "!=", "right": "deploy", "rightPath": "spec.template.spec.containers[0].ports[0].containerPort",
+			}, false, "expected spec.ports[0].targetPort != spec.template.spec.containers[0].ports[0].containerPort"),
+			Entry("missing field", obj{"leftPath": "spec.selector", "right": "deploy", "rightPath": "spec.replicas"}, false, "resource: field spec.selector not found"),
+			Entry("unknown resource", obj{"leftPath": "spec.ports", "right": "svc", "rightPath": "spec.ports"}, false, `state "svc" not found`),
+			Entry("missing path", obj{"leftPath": "spec.ports"}, false, "leftPath and rightPath are required"),
+		)
+	})
+})
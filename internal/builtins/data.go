@@ -0,0 +1,234 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package builtins
+
+import (
+	"encoding/base64"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/lunz1207/testplane/internal/plugin"
+)
+
+// resourceData 返回 ConfigMap/Secret 的数据内容（解码后的明文）。
+// - ConfigMap：合并 data 与 binaryData（binaryData 做 base64 解码）
+// - Secret：data 做 base64 解码，并合并 stringData
+// 第二个返回值为解码失败的 key。
+func resourceData(resource map[string]interface{}) (map[string]string, string) {
+	result := make(map[string]string)
+	kind := plugin.GetString(resource, "kind")
+
+	for k, v := range plugin.GetMap(resource, "data") {
+		s, _ := v.(string)
+		if kind == "Secret" {
+			decoded, err := base64.StdEncoding.DecodeString(s)
+			if err != nil {
+				return nil, k
+			}
+			s = string(decoded)
+		}
+		result[k] = s
+	}
+	for k, v := range plugin.GetMap(resource, "binaryData") {
+		s, _ := v.(string)
+		decoded, err := base64.StdEncoding.DecodeString(s)
+		if err != nil {
+			return nil, k
+		}
+		result[k] = string(decoded)
+	}
+	for k, v := range plugin.GetMap(resource, "stringData") {
+		s, _ := v.(string)
+		result[k] = s
+	}
+	return result, ""
+}
+
+// dataKeys 返回排序后的 key 列表（用于 Actual 输出）。
+func dataKeys(data map[string]string) string {
+	keys := make([]string, 0, len(data))
+	for k := range data {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return strings.Join(keys, ",")
+}
+
+// DataHasKey 检查 ConfigMap/Secret 是否包含指定 key。
+// 参数：key（必填）。
+func DataHasKey(resource, params map[string]interface{}) plugin.Result {
+	if len(resource) == 0 {
+		return plugin.Fail("resource not found")
+	}
+
+	key := plugin.GetString(params, "key")
+	if key == "" {
+		return plugin.Fail("missing required param: key")
+	}
+
+	data, badKey := resourceData(resource)
+	if badKey != "" {
+		return plugin.Fail(fmt.Sprintf("decode data key %s failed", badKey))
+	}
+
+	if _, ok := data[key]; ok {
+		return plugin.Pass()
+	}
+	return plugin.Fail(fmt.Sprintf("key %s not found in data", key)).WithActual("keys=" + dataKeys(data))
+}
+
+// DataEquals 检查 ConfigMap/Secret 的数据内容（Secret 自动 base64 解码）。
+// 参数：
+//   - key + value：检查单个 key 的值
+//   - data：map 形式，检查多个 key 的值（子集匹配，未列出的 key 不检查）
+func DataEquals(resource, params map[string]interface{}) plugin.Result {
+	if len(resource) == 0 {
+		return plugin.Fail("resource not found")
+	}
+
+	expected := make(map[string]string)
+	for k, v := range plugin.GetMap(params, "data") {
+		expected[k] = fmt.Sprintf("%v", v)
+	}
+	if key := plugin.GetString(params, "key"); key != "" {
+		if _, ok := params["value"]; !ok {
+			return plugin.Fail("missing required param: value")
+		}
+		expected[key] = fmt.Sprintf("%v", params["value"])
+	}
+	if len(expected) == 0 {
+		return plugin.Fail("missing required param: key/value or data")
+	}
+
+	data, badKey := resourceData(resource)
+	if badKey != "" {
+		return plugin.Fail(fmt.Sprintf("decode data key %s failed", badKey))
+	}
+
+	keys := make([]string, 0, len(expected))
+	for k := range expected {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		actual, ok := data[k]
+		if !ok {
+			return plugin.Fail(fmt.Sprintf("key %s not found in data", k)).WithActual("keys=" + dataKeys(data))
+		}
+		if actual != expected[k] {
+			return plugin.Fail(fmt.Sprintf("expected %s=%q", k, expected[k])).WithActual(fmt.Sprintf("%s=%q", k, actual))
+		}
+	}
+	return plugin.Pass()
+}
+
+// encodedDataKey 判断 key 在资源中是否以 base64 存储（Secret.data 或 binaryData），即 resourceData 是否解码了它。
+func encodedDataKey(resource map[string]interface{}, key string) bool {
+	if _, ok := plugin.GetMap(resource, "binaryData")[key]; ok {
+		return true
+	}
+	_, ok := plugin.GetMap(resource, "data")[key]
+	return ok && plugin.GetString(resource, "kind") == "Secret"
+}
+
+// dataValue 经 resourceData 读取指定 key 的值。decode 参数未设置时与 DataEquals 相同（Secret.data 与 binaryData 解码）；
+// 显式设置时：decode=true 再解码一层明文值（如 ConfigMap.data 中存放的 base64），decode=false 返回存储的 base64 原文。
+func dataValue(resource, params map[string]interface{}, key string) (string, *plugin.Result) {
+	data, badKey := resourceData(resource)
+	if badKey != "" {
+		r := plugin.Fail(fmt.Sprintf("decode data key %s failed", badKey))
+		return "", &r
+	}
+	value, ok := data[key]
+	if !ok {
+		r := plugin.Fail(fmt.Sprintf("key %s not found in data", key)).WithActual("keys=" + dataKeys(data))
+		return "", &r
+	}
+
+	encoded := encodedDataKey(resource, key)
+	switch decode := plugin.GetBoolOr(params, "decode", encoded); {
+	case decode && !encoded:
+		decoded, err := base64.StdEncoding.DecodeString(value)
+		if err != nil {
+			r := plugin.Fail(fmt.Sprintf("base64 decode key %s failed: %v", key, err))
+			return "", &r
+		}
+		return string(decoded), nil
+	case !decode && encoded:
+		return base64.StdEncoding.EncodeToString([]byte(value)), nil
+	}
+	return value, nil
+}
+
+// ConfigMapValueEquals 检查 ConfigMap/Secret 指定 key 的值是否相等，与 DataEquals 共用 resourceData 的解码。
+// 参数：key、value（必填），decode（可选，见 dataValue）。
+func ConfigMapValueEquals(resource, params map[string]interface{}) plugin.Result {
+	if len(resource) == 0 {
+		return plugin.Fail("resource not found")
+	}
+
+	key := plugin.GetString(params, "key")
+	if key == "" {
+		return plugin.Fail("missing required param: key")
+	}
+	if _, ok := params["value"]; !ok {
+		return plugin.Fail("missing required param: value")
+	}
+	expected := fmt.Sprintf("%v", params["value"])
+
+	actual, failed := dataValue(resource, params, key)
+	if failed != nil {
+		return *failed
+	}
+	if actual != expected {
+		return plugin.Fail(fmt.Sprintf("expected %s=%q", key, expected)).WithActual(fmt.Sprintf("%s=%q", key, actual))
+	}
+	return plugin.Pass()
+}
+
+// ConfigMapValueMatches 检查 ConfigMap/Secret 指定 key 的值是否匹配正则表达式。
+// 参数：key、pattern（必填），decode（可选，见 dataValue）。
+func ConfigMapValueMatches(resource, params map[string]interface{}) plugin.Result {
+	if len(resource) == 0 {
+		return plugin.Fail("resource not found")
+	}
+
+	key := plugin.GetString(params, "key")
+	if key == "" {
+		return plugin.Fail("missing required param: key")
+	}
+	pattern := plugin.GetString(params, "pattern")
+	if pattern == "" {
+		return plugin.Fail("missing required param: pattern")
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return plugin.Fail(fmt.Sprintf("invalid pattern %q: %v", pattern, err))
+	}
+
+	actual, failed := dataValue(resource, params, key)
+	if failed != nil {
+		return *failed
+	}
+	if !re.MatchString(actual) {
+		return plugin.Fail(fmt.Sprintf("%s does not match %q", key, pattern)).WithActual(fmt.Sprintf("%s=%q", key, actual))
+	}
+	return plugin.Pass()
+}
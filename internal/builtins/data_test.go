@@ -0,0 +1,72 @@
+package builtins
+
+import (
+	"encoding/base64"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/lunz1207/testplane/internal/plugin"
+)
+
+var _ = Describe("Data assertions", func() {
+	b64 := func(s string) string { return base64.StdEncoding.EncodeToString([]byte(s)) }
+
+	configMap := map[string]interface{}{
+		"kind":       "ConfigMap",
+		"data":       map[string]interface{}{"mode": "on", "blob": b64("payload")},
+		"binaryData": map[string]interface{}{"cert": b64("pem")},
+	}
+	secret := map[string]interface{}{
+		"kind":       "Secret",
+		"data":       map[string]interface{}{"password": b64("s3cret")},
+		"stringData": map[string]interface{}{"user": "admin"},
+	}
+
+	It("should decode Secret data and binaryData once for every function", func() {
+		data, badKey := resourceData(secret)
+		Expect(badKey).To(BeEmpty())
+		Expect(data).To(Equal(map[string]string{"password": "s3cret", "user": "admin"}))
+		data, _ = resourceData(configMap)
+		Expect(data).To(HaveKeyWithValue("cert", "pem"))
+		Expect(data).To(HaveKeyWithValue("blob", b64("payload")))
+	})
+
+	DescribeTable("functions sharing resourceData",
+		func(fn plugin.Function, resource, params map[string]interface{}, passed bool) {
+			Expect(fn(resource, params).Passed).To(Equal(passed))
+		},
+		Entry("DataHasKey binaryData", DataHasKey, configMap, map[string]interface{}{"key": "cert"}, true),
+		Entry("DataHasKey stringData", DataHasKey, secret, map[string]interface{}{"key": "user"}, true),
+		Entry("DataHasKey missing", DataHasKey, configMap, map[string]interface{}{"key": "absent"}, false),
+		Entry("DataEquals Secret", DataEquals, secret, map[string]interface{}{"key": "password", "value": "s3cret"}, true),
+		Entry("DataEquals subset", DataEquals, configMap, map[string]interface{}{"data": map[string]interface{}{"mode": "on", "cert": "pem"}}, true),
+		Entry("ConfigMapValueEquals plain", ConfigMapValueEquals, configMap, map[string]interface{}{"key": "mode", "value": "on"}, true),
+		Entry("ConfigMapValueEquals binaryData decoded", ConfigMapValueEquals, configMap, map[string]interface{}{"key": "cert", "value": "pem"}, true),
+		Entry("ConfigMapValueEquals Secret decoded", ConfigMapValueEquals, secret, map[string]interface{}{"key": "password", "value": "s3cret"}, true),
+		Entry("ConfigMapValueEquals Secret raw", ConfigMapValueEquals, secret, map[string]interface{}{"key": "password", "value": b64("s3cret"), "decode": false}, true),
+		Entry("ConfigMapValueEquals ConfigMap.data decoded", ConfigMapValueEquals, configMap, map[string]interface{}{"key": "blob", "value": "payload", "decode": true}, true),
+		Entry("ConfigMapValueEquals mismatch", ConfigMapValueEquals, configMap, map[string]interface{}{"key": "mode", "value": "off"}, false),
+		Entry("ConfigMapValueMatches", ConfigMapValueMatches, secret, map[string]interface{}{"key": "user", "pattern": "^adm"}, true),
+		Entry("ConfigMapValueMatches invalid pattern", ConfigMapValueMatches, secret, map[string]interface{}{"key": "user", "pattern": "("}, false),
+		Entry("ConfigMapValueMatches undecodable", ConfigMapValueMatches, configMap, map[string]interface{}{"key": "mode", "pattern": ".", "decode": true}, false),
+	)
+
+	It("should report the same missing key and decode errors as DataEquals", func() {
+		params := map[string]interface{}{"key": "absent", "value": "x"}
+		Expect(ConfigMapValueEquals(configMap, params).Actual).To(Equal(DataEquals(configMap, params).Actual))
+		Expect(ConfigMapValueEquals(configMap, params).Actual).To(Equal("keys=blob,cert,mode"))
+
+		broken := map[string]interface{}{"kind": "Secret", "data": map[string]interface{}{"password": "not base64!"}}
+		Expect(ConfigMapValueEquals(broken, map[string]interface{}{"key": "password", "value": "x"}).Message).
+			To(Equal(DataEquals(broken, map[string]interface{}{"key": "password", "value": "x"}).Message))
+	})
+
+	It("should register ConfigMapHasKey as an alias of DataHasKey", func() {
+		registry := plugin.NewRegistry()
+		RegisterData(registry)
+		result, err := registry.Call("ConfigMapHasKey", secret, []byte(`{"key":"user"}`))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Passed).To(BeTrue())
+	})
+})
@@ -175,34 +175,48 @@ func ClusterClientPort(resource, params map[string]interface{}) plugin.Result {
 }
 
 // FieldPath 通用字段路径提取器。
-// params: path (string, 如 "status.phase" 或 ".data.url")
+// params: path (string, 如 "status.phase"、".data.url" 或 JSONPath "{.status.nodes[0].ip}")。
+// 数值与布尔值转为字符串，列表与对象序列化为 JSON；字段不存在时返回空字符串。
 func FieldPath(resource, params map[string]interface{}) plugin.Result {
 	path := plugin.GetString(params, "path")
 	if path == "" {
 		return plugin.Extract("")
 	}
+	value, found, err := jsonPathValue(resource, path)
+	if err != nil || !found {
+		return plugin.Extract("")
+	}
+	return plugin.Extract(formatValue(value))
+}
 
-	// 去掉开头的点（支持 JSONPath 风格如 ".data.url"）
-	path = strings.TrimPrefix(path, ".")
-
-	parts := strings.Split(path, ".")
-	current := resource
+// ClusterNodeCountByRole 返回指定角色的节点数量（读取 status.nodes[].role）。
+// params: role (string, 可选，为空统计全部节点), expected (int, 可选，设置后同时作为断言使用)
+func ClusterNodeCountByRole(resource, params map[string]interface{}) plugin.Result {
+	status := plugin.GetMap(resource, "status")
+	if status == nil {
+		return plugin.Fail("no status")
+	}
 
-	for _, part := range parts {
-		if current == nil {
-			return plugin.Extract("")
+	role := plugin.GetString(params, "role")
+	count := 0
+	for _, node := range plugin.GetSlice(status, "nodes") {
+		nodeMap, ok := node.(map[string]interface{})
+		if !ok {
+			continue
 		}
-		next := plugin.GetMap(current, part)
-		if next != nil {
-			current = next
-		} else {
-			// 尝试获取字符串值
-			if val := plugin.GetString(current, part); val != "" {
-				return plugin.Extract(val)
-			}
-			return plugin.Extract("")
+		if role != "" && !strings.EqualFold(plugin.GetString(nodeMap, "role"), role) {
+			continue
 		}
+		count++
 	}
 
-	return plugin.Extract("")
+	value := fmt.Sprintf("%d", count)
+	if _, ok := params["expected"]; ok {
+		expected := plugin.GetInt(params, "expected")
+		if count != expected {
+			return plugin.Fail(fmt.Sprintf("expected %d nodes with role %q, got %d", expected, role, count)).
+				WithActual(count).WithValue(value)
+		}
+	}
+	return plugin.Extract(value)
 }
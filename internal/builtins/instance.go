@@ -205,3 +205,108 @@ func InstanceCeased(resource, params map[string]interface{}) plugin.Result {
 	}
 	return plugin.Fail("instance not ceased").WithActual(fmt.Sprintf("phase=%s, transition=%s", phase, transition))
 }
+
+// attachedIDs 从实例 status 列表字段中提取 ID。
+// 列表元素可以是字符串 ID，也可以是包含 idKeys 中任一字段的对象。
+func attachedIDs(items []interface{}, idKeys ...string) []string {
+	ids := make([]string, 0, len(items))
+	for _, item := range items {
+		switch v := item.(type) {
+		case string:
+			ids = append(ids, v)
+		case map[string]interface{}:
+			for _, key := range idKeys {
+				if id := plugin.GetString(v, key); id != "" {
+					ids = append(ids, id)
+					break
+				}
+			}
+		}
+	}
+	return ids
+}
+
+// containsID 检查 ID 列表中是否包含指定 ID。
+func containsID(ids []string, id string) bool {
+	for _, v := range ids {
+		if v == id {
+			return true
+		}
+	}
+	return false
+}
+
+// InstanceVolumeAttached 检查实例数据盘挂载情况（读取 status.volumes）。
+// params: volumeID (string, 可选), count (int, 可选，挂载数量), expected (bool, 默认 true)
+func InstanceVolumeAttached(resource, params map[string]interface{}) plugin.Result {
+	status := plugin.GetMap(resource, "status")
+	if status == nil {
+		return plugin.Fail("no status")
+	}
+
+	ids := attachedIDs(plugin.GetSlice(status, "volumes"), "volumeID", "id")
+	volumeID := plugin.GetString(params, "volumeID")
+	expectAttached := plugin.GetBoolOr(params, "expected", true)
+
+	if volumeID != "" {
+		found := containsID(ids, volumeID)
+		if found == expectAttached {
+			return plugin.Pass()
+		}
+		if expectAttached {
+			return plugin.Fail(fmt.Sprintf("volume %s not attached", volumeID)).WithActual(ids)
+		}
+		return plugin.Fail(fmt.Sprintf("volume %s still attached", volumeID)).WithActual(ids)
+	}
+
+	if _, ok := params["count"]; ok {
+		count := plugin.GetInt(params, "count")
+		if len(ids) == count {
+			return plugin.Pass()
+		}
+		return plugin.Fail(fmt.Sprintf("expected %d volumes attached, got %d", count, len(ids))).WithActual(len(ids))
+	}
+
+	// 未指定 ID 和数量，检查是否有任何数据盘
+	hasAny := len(ids) > 0
+	if hasAny == expectAttached {
+		return plugin.Pass()
+	}
+	if expectAttached {
+		return plugin.Fail("no volumes attached")
+	}
+	return plugin.Fail(fmt.Sprintf("%d volumes still attached", len(ids)))
+}
+
+// InstanceEIPBound 检查实例是否绑定了公网 IP（读取 status.eips）。
+// params: eipID (string, 可选), expected (bool, 默认 true)
+func InstanceEIPBound(resource, params map[string]interface{}) plugin.Result {
+	status := plugin.GetMap(resource, "status")
+	if status == nil {
+		return plugin.Fail("no status")
+	}
+
+	ids := attachedIDs(plugin.GetSlice(status, "eips"), "eipID", "id")
+	eipID := plugin.GetString(params, "eipID")
+	expectBound := plugin.GetBoolOr(params, "expected", true)
+
+	if eipID != "" {
+		found := containsID(ids, eipID)
+		if found == expectBound {
+			return plugin.Pass()
+		}
+		if expectBound {
+			return plugin.Fail(fmt.Sprintf("eip %s not bound", eipID)).WithActual(ids)
+		}
+		return plugin.Fail(fmt.Sprintf("eip %s still bound", eipID)).WithActual(ids)
+	}
+
+	hasAny := len(ids) > 0
+	if hasAny == expectBound {
+		return plugin.Pass()
+	}
+	if expectBound {
+		return plugin.Fail("no eip bound")
+	}
+	return plugin.Fail(fmt.Sprintf("%d eips still bound", len(ids)))
+}
@@ -0,0 +1,42 @@
+package builtins
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+)
+
+var _ = Describe("Instance builtins", func() {
+	instance := obj{"status": obj{
+		"volumes": []interface{}{"vol-1", obj{"volumeID": "vol-2"}, obj{"id": "vol-3"}},
+		"eips":    []interface{}{obj{"eipID": "eip-1"}},
+	}}
+	bare := obj{"status": obj{}}
+
+	DescribeTable("InstanceVolumeAttached",
+		func(resource, params obj, passed bool, message string) {
+			expectResult(InstanceVolumeAttached(resource, params), passed, message)
+		},
+		Entry("string ID", instance, obj{"volumeID": "vol-1"}, true, ""),
+		Entry("object ID", instance, obj{"volumeID": "vol-3"}, true, ""),
+		Entry("not attached", instance, obj{"volumeID": "vol-9"}, false, "volume vol-9 not attached"),
+		Entry("expected detached", instance, obj{"volumeID": "vol-2", "expected": false}, false, "volume vol-2 still attached"),
+		Entry("detached", instance, obj{"volumeID": "vol-9", "expected": false}, true, ""),
+		Entry("count", instance, obj{"count": 3}, true, ""),
+		Entry("wrong count", instance, obj{"count": 0}, false, "expected 0 volumes attached, got 3"),
+		Entry("any volume", instance, obj{}, true, ""),
+		Entry("no volumes", bare, obj{}, false, "no volumes attached"),
+		Entry("no volumes expected", bare, obj{"expected": false}, true, ""),
+		Entry("missing status", obj{}, obj{}, false, "no status"),
+	)
+
+	DescribeTable("InstanceEIPBound",
+		func(resource, params obj, passed bool, message string) {
+			expectResult(InstanceEIPBound(resource, params), passed, message)
+		},
+		Entry("bound", instance, obj{"eipID": "eip-1"}, true, ""),
+		Entry("not bound", instance, obj{"eipID": "eip-2"}, false, "eip eip-2 not bound"),
+		Entry("expected unbound", instance, obj{"eipID": "eip-1", "expected": false}, false, "eip eip-1 still bound"),
+		Entry("any eip", instance, obj{}, true, ""),
+		Entry("no eips", bare, obj{}, false, "no eip bound"),
+		Entry("still bound", instance, obj{"expected": false}, false, "1 eips still bound"),
+	)
+})
@@ -0,0 +1,100 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package builtins
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/client-go/util/jsonpath"
+
+	"github.com/lunz1207/testplane/internal/plugin"
+)
+
+// FieldEquals 按 JSONPath 读取字段并与期望值比较，默认运算符 ==。
+// params: path (string, kubectl 风格 JSONPath，如 "{.status.nodes[0].ready}"，可省略花括号写作 "status.phase"),
+// operator (string, 可选，与 FieldCompare 相同：==（默认）、!=、>、>=、<、<=、contains、regex、in), value (期望值，保留 YAML 类型)。
+// 通配符、过滤器、切片或递归下降匹配到的值作为列表比较。
+func FieldEquals(resource, params map[string]interface{}) plugin.Result {
+	return matchField(resource, params, "==")
+}
+
+// FieldMatches 与 FieldEquals 相同，默认运算符 regex。
+func FieldMatches(resource, params map[string]interface{}) plugin.Result {
+	return matchField(resource, params, "regex")
+}
+
+// matchField 读取字段并按运算符与期望值比较。
+func matchField(resource, params map[string]interface{}, defaultOperator string) plugin.Result {
+	path := plugin.GetString(params, "path")
+	if path == "" {
+		return plugin.Fail("path is required")
+	}
+	expected, ok := params["value"]
+	if !ok {
+		return plugin.Fail("value is required")
+	}
+	operator := plugin.GetString(params, "operator")
+	if operator == "" {
+		operator = defaultOperator
+	}
+
+	actual, found, err := jsonPathValue(resource, path)
+	if err != nil {
+		return plugin.Fail(err.Error())
+	}
+	if !found {
+		return plugin.Fail(fmt.Sprintf("field %s not found", path))
+	}
+	matched, err := compareValues(actual, operator, expected)
+	if err != nil {
+		return plugin.Fail(err.Error()).WithActual(formatValue(actual))
+	}
+	if matched {
+		return plugin.Pass()
+	}
+	return plugin.Fail(fmt.Sprintf("expected %s %s %s", path, operator, formatValue(expected))).WithActual(formatValue(actual))
+}
+
+// jsonPathValue 按 JSONPath 读取字段。路径含通配符、过滤器、切片、并集或递归下降时返回匹配值的列表（可能为空）；
+// 否则返回单个值，字段或下标不存在时 found 为 false。路径无法解析时返回错误。
+func jsonPathValue(obj map[string]interface{}, path string) (interface{}, bool, error) {
+	expr := strings.TrimSpace(path)
+	if !strings.HasPrefix(expr, "{") {
+		expr = "{." + strings.TrimPrefix(expr, ".") + "}"
+	}
+	jp := jsonpath.New("field")
+	if err := jp.Parse(expr); err != nil {
+		return nil, false, fmt.Errorf("invalid path %q: %w", path, err)
+	}
+	results, err := jp.FindResults(obj)
+	if err != nil || len(results) == 0 {
+		return nil, false, nil
+	}
+
+	values := make([]interface{}, 0, len(results[0]))
+	for _, v := range results[0] {
+		values = append(values, v.Interface())
+	}
+	if strings.ContainsAny(expr, "*?:,") || strings.Contains(expr, "..") {
+		return values, true, nil
+	}
+	if len(values) != 1 || values[0] == nil {
+		return nil, false, nil
+	}
+	return values[0], true, nil
+}
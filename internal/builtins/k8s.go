@@ -19,6 +19,8 @@ package builtins
 import (
 	"fmt"
 
+	apiresource "k8s.io/apimachinery/pkg/api/resource"
+
 	"github.com/lunz1207/testplane/internal/plugin"
 )
 
@@ -261,6 +263,52 @@ func ServiceReady(resource, params map[string]interface{}) plugin.Result {
 	return plugin.Pass()
 }
 
+// EndpointsReady 检查 Service 是否有足够的就绪 endpoint 地址。
+// 状态收集时会自动将 Service 对应的 EndpointSlice 附加到 _related.endpointSlices 下。
+// 参数：minReadyAddresses（可选，默认 1）。
+func EndpointsReady(resource, params map[string]interface{}) plugin.Result {
+	if len(resource) == 0 {
+		return plugin.Fail("service not found")
+	}
+
+	minReady := plugin.GetInt(params, "minReadyAddresses")
+	if minReady <= 0 {
+		minReady = 1
+	}
+
+	related := plugin.GetMap(resource, "_related")
+	slices, ok := related["endpointSlices"].([]interface{})
+	if !ok {
+		return plugin.Fail("no endpointslices gathered for service")
+	}
+
+	ready, notReady := 0, 0
+	for _, item := range slices {
+		slice, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		for _, ep := range plugin.GetSlice(slice, "endpoints") {
+			endpoint, ok := ep.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			// conditions.ready 为空时按 EndpointSlice 语义视为就绪
+			if plugin.GetBoolOr(plugin.GetMap(endpoint, "conditions"), "ready", true) {
+				ready += len(plugin.GetSlice(endpoint, "addresses"))
+			} else {
+				notReady += len(plugin.GetSlice(endpoint, "addresses"))
+			}
+		}
+	}
+
+	if ready >= minReady {
+		return plugin.Pass().WithActual(fmt.Sprintf("ready=%d, notReady=%d", ready, notReady))
+	}
+	return plugin.Fail(fmt.Sprintf("endpoints not ready: ready=%d, want>=%d", ready, minReady)).
+		WithActual(fmt.Sprintf("ready=%d, notReady=%d, slices=%d", ready, notReady, len(slices)))
+}
+
 // PVCBound 检查 PVC 是否已绑定。
 // 就绪条件：phase=Bound
 func PVCBound(resource, params map[string]interface{}) plugin.Result {
@@ -280,3 +328,53 @@ func PVCBound(resource, params map[string]interface{}) plugin.Result {
 
 	return plugin.Fail(fmt.Sprintf("pvc not bound: phase=%s", phase)).WithActual(phase)
 }
+
+// PVCCapacityAtLeast 检查 PVC 实际分配容量是否不小于期望值。
+// 读取 status.capacity.storage（如 "10Gi"），参数 size 为期望的最小容量（如 "5Gi"）。
+func PVCCapacityAtLeast(resource, params map[string]interface{}) plugin.Result {
+	if len(resource) == 0 {
+		return plugin.Fail("pvc not found")
+	}
+
+	size := plugin.GetString(params, "size")
+	if size == "" {
+		return plugin.Fail("missing required param: size")
+	}
+	expected, err := apiresource.ParseQuantity(size)
+	if err != nil {
+		return plugin.Fail(fmt.Sprintf("invalid param size %q: %v", size, err))
+	}
+
+	capacity := plugin.GetNestedString(resource, "status.capacity.storage")
+	if capacity == "" {
+		return plugin.Fail("no status.capacity.storage")
+	}
+	actual, err := apiresource.ParseQuantity(capacity)
+	if err != nil {
+		return plugin.Fail(fmt.Sprintf("invalid capacity %q: %v", capacity, err)).WithActual(capacity)
+	}
+
+	if actual.Cmp(expected) >= 0 {
+		return plugin.Pass()
+	}
+	return plugin.Fail(fmt.Sprintf("pvc capacity %s < %s", capacity, size)).WithActual(capacity)
+}
+
+// PVCStorageClassEquals 检查 PVC 的 StorageClass 是否为期望值。
+// 参数 storageClass 为期望的 StorageClass 名称（读取 spec.storageClassName）。
+func PVCStorageClassEquals(resource, params map[string]interface{}) plugin.Result {
+	if len(resource) == 0 {
+		return plugin.Fail("pvc not found")
+	}
+
+	expected := plugin.GetString(params, "storageClass")
+	if expected == "" {
+		return plugin.Fail("missing required param: storageClass")
+	}
+
+	actual := plugin.GetNestedString(resource, "spec.storageClassName")
+	if actual == expected {
+		return plugin.Pass()
+	}
+	return plugin.Fail(fmt.Sprintf("expected storageClassName=%s", expected)).WithActual(actual)
+}
@@ -0,0 +1,61 @@
+package builtins
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+)
+
+var _ = Describe("Kubernetes builtins", func() {
+	pvc := obj{
+		"spec":   obj{"storageClassName": "ssd"},
+		"status": obj{"phase": "Bound", "capacity": obj{"storage": "10Gi"}},
+	}
+
+	DescribeTable("PVCCapacityAtLeast",
+		func(resource, params obj, passed bool, message string) {
+			expectResult(PVCCapacityAtLeast(resource, params), passed, message)
+		},
+		Entry("larger capacity", pvc, obj{"size": "5Gi"}, true, ""),
+		Entry("equal capacity in other units", pvc, obj{"size": "10240Mi"}, true, ""),
+		Entry("smaller capacity", pvc, obj{"size": "20Gi"}, false, "pvc capacity 10Gi < 20Gi"),
+		Entry("missing size", pvc, obj{}, false, "missing required param: size"),
+		Entry("invalid size", pvc, obj{"size": "lots"}, false, "invalid param size"),
+		Entry("not provisioned", obj{"status": obj{"phase": "Pending"}}, obj{"size": "1Gi"}, false, "no status.capacity.storage"),
+		Entry("missing resource", obj{}, obj{"size": "1Gi"}, false, "pvc not found"),
+	)
+
+	DescribeTable("PVCStorageClassEquals",
+		func(resource, params obj, passed bool, message string) {
+			expectResult(PVCStorageClassEquals(resource, params), passed, message)
+		},
+		Entry("same class", pvc, obj{"storageClass": "ssd"}, true, ""),
+		Entry("other class", pvc, obj{"storageClass": "hdd"}, false, "expected storageClassName=hdd"),
+		Entry("missing param", pvc, obj{}, false, "missing required param: storageClass"),
+	)
+
+	service := func(endpoints ...interface{}) obj {
+		return obj{
+			"metadata": obj{"name": "web"},
+			"_related": obj{"endpointSlices": []interface{}{obj{"endpoints": endpoints}}},
+		}
+	}
+	endpoint := func(ready interface{}, addresses ...interface{}) obj {
+		ep := obj{"addresses": addresses}
+		if ready != nil {
+			ep["conditions"] = obj{"ready": ready}
+		}
+		return ep
+	}
+
+	DescribeTable("EndpointsReady",
+		func(resource, params obj, passed bool, message string) {
+			expectResult(EndpointsReady(resource, params), passed, message)
+		},
+		Entry("one ready address", service(endpoint(true, "10.0.0.1")), obj{}, true, ""),
+		Entry("ready condition unset", service(endpoint(nil, "10.0.0.1")), obj{}, true, ""),
+		Entry("enough ready addresses", service(endpoint(true, "10.0.0.1", "10.0.0.2"), endpoint(false, "10.0.0.3")), obj{"minReadyAddresses": 2}, true, ""),
+		Entry("not enough ready addresses", service(endpoint(true, "10.0.0.1"), endpoint(false, "10.0.0.2")), obj{"minReadyAddresses": 2}, false, "ready=1, want>=2"),
+		Entry("no endpoints", service(), obj{}, false, "ready=0, want>=1"),
+		Entry("no slices gathered", obj{"metadata": obj{"name": "web"}}, obj{}, false, "no endpointslices gathered"),
+		Entry("missing service", obj{}, obj{}, false, "service not found"),
+	)
+})
@@ -26,6 +26,7 @@ func RegisterAll(r *plugin.Registry) {
 	RegisterInstance(r)
 	RegisterK8s(r)
 	RegisterCommon(r)
+	RegisterData(r)
 	RegisterExtraction(r)
 }
 
@@ -39,6 +40,11 @@ func RegisterCluster(r *plugin.Registry) {
 	r.Register("ClusterCeased", ClusterCeased)
 	r.Register("ClusterPhaseEquals", ClusterPhaseEquals)
 	r.Register("ClusterNodeCount", ClusterNodeCount)
+	r.Register("ClusterNodesSpreadAcrossZones", ClusterNodesSpreadAcrossZones)
+	r.Register("ClusterVersionEquals", ClusterVersionEquals)
+	r.Register("ClusterUpgradeCompleted", ClusterUpgradeCompleted)
+	r.Register("ClusterBackupCompleted", ClusterBackupCompleted)
+	r.Register("SnapshotAvailable", SnapshotAvailable)
 	r.Register("ClusterSecurityGroupExists", ClusterSecurityGroupExists)
 	r.Register("ClusterSecurityGroupNotExists", ClusterSecurityGroupNotExists)
 }
@@ -54,6 +60,8 @@ func RegisterInstance(r *plugin.Registry) {
 	r.Register("InstancePhaseEquals", InstancePhaseEquals)
 	r.Register("InstanceSecurityGroupExists", InstanceSecurityGroupExists)
 	r.Register("InstanceSecurityGroupNotExists", InstanceSecurityGroupNotExists)
+	r.Register("InstanceVolumeAttached", InstanceVolumeAttached)
+	r.Register("InstanceEIPBound", InstanceEIPBound)
 }
 
 // RegisterK8s 注册 Kubernetes 资源就绪检查函数。
@@ -65,7 +73,10 @@ func RegisterK8s(r *plugin.Registry) {
 	r.Register("PodComplete", PodComplete)
 	r.Register("JobComplete", JobComplete)
 	r.Register("ServiceReady", ServiceReady)
+	r.Register("EndpointsReady", EndpointsReady)
 	r.Register("PVCBound", PVCBound)
+	r.Register("PVCCapacityAtLeast", PVCCapacityAtLeast)
+	r.Register("PVCStorageClassEquals", PVCStorageClassEquals)
 }
 
 // RegisterCommon 注册通用断言函数。
@@ -73,6 +84,21 @@ func RegisterCommon(r *plugin.Registry) {
 	r.Register("ResourceExists", ResourceExists)
 	r.Register("ResourceNotExists", ResourceNotExists)
 	r.Register("DeploymentAvailable", DeploymentAvailable)
+	r.RegisterState("FieldsMatchAcrossResources", FieldsMatchAcrossResources)
+	r.Register("FieldCompare", FieldCompare)
+	r.Register("FieldEquals", FieldEquals)
+	r.Register("FieldMatches", FieldMatches)
+	r.Register("UnchangedFields", UnchangedFields)
+	r.Register("OwnedBy", OwnedBy)
+}
+
+// RegisterData 注册 ConfigMap/Secret 等无 status 资源的数据断言函数。
+func RegisterData(r *plugin.Registry) {
+	r.Register("DataHasKey", DataHasKey)
+	r.Register("DataEquals", DataEquals)
+	r.Register("ConfigMapHasKey", DataHasKey) // DataHasKey 的别名
+	r.Register("ConfigMapValueEquals", ConfigMapValueEquals)
+	r.Register("ConfigMapValueMatches", ConfigMapValueMatches)
 }
 
 // RegisterExtraction 注册提取函数（用于 EnvInjection）。
@@ -82,5 +108,6 @@ func RegisterExtraction(r *plugin.Registry) {
 	r.Register("ClusterID", ClusterID)
 	r.Register("ClusterVIP", ClusterVIP)
 	r.Register("ClusterClientPort", ClusterClientPort)
+	r.Register("ClusterNodeCountByRole", ClusterNodeCountByRole)
 	r.Register("FieldPath", FieldPath)
 }
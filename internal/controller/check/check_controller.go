@@ -0,0 +1,324 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package check
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	infrav1alpha1 "github.com/lunz1207/testplane/api/v1alpha1"
+	"github.com/lunz1207/testplane/internal/controller/shared"
+	"github.com/lunz1207/testplane/internal/controller/shared/logging"
+	"github.com/lunz1207/testplane/internal/controller/shared/metrics"
+	"github.com/lunz1207/testplane/internal/plugin"
+	"github.com/lunz1207/testplane/pkg/export"
+	"github.com/lunz1207/testplane/pkg/recording"
+)
+
+const defaultRequeue = 5 * time.Second
+
+// CheckReconciler reconciles a Check object.
+// Check 只读取已有资源，不创建任何资源，因此无需 finalizer。
+type CheckReconciler struct {
+	client.Client
+	Scheme         *runtime.Scheme
+	PluginRegistry *plugin.Registry
+	APIReader      client.Reader // 用于终态判定前绕过缓存读取最新状态
+	Recorder       record.EventRecorder
+	// SnapshotArchive 状态快照归档（可选，用于录制与重放）。
+	SnapshotArchive recording.Archive
+	// Exporter 结果导出（可选），每轮检查结束时导出 CheckResult。
+	Exporter *shared.ResultExporter
+	// Debounce 更新事件合并窗口（可选）。
+	Debounce time.Duration
+}
+
+// +kubebuilder:rbac:groups=infra.testplane.io,resources=checks,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=infra.testplane.io,resources=checks/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=*,resources=*,verbs=get;list;watch
+
+func (r *CheckReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	// 按调和结果记录耗时、重新入队与错误指标
+	ctx, done := metrics.Start(ctx, "check")
+	result, err := r.reconcile(ctx, req)
+	done(result, err)
+	return result, err
+}
+
+func (r *CheckReconciler) reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	baseLog := logf.FromContext(ctx)
+	// API 请求的 User-Agent 附带测试对象，便于 API Server 端按测试归属负载
+	ctx = shared.WithRequestUserAgent(ctx, "Check", req.NamespacedName)
+
+	var chk infrav1alpha1.Check
+	if err := r.Get(ctx, req.NamespacedName, &chk); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	// 添加资源上下文到 logger
+	log := logging.WithKindName(baseLog, "Check", chk.Namespace, chk.Name)
+	ctx = logf.IntoContext(ctx, log)
+
+	if r.PluginRegistry == nil {
+		panic("PluginRegistry must be set before using CheckReconciler")
+	}
+
+	if !chk.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, nil
+	}
+
+	// 故障注入（调试用）：注解解析失败时忽略注入，不影响正常执行
+	if injCtx, err := shared.ContextWithFailureInjection(ctx, chk.GetAnnotations()); err != nil {
+		log.Info("ignore invalid failure injection annotation", "error", err.Error())
+	} else {
+		ctx = injCtx
+	}
+	ctx = shared.ContextWithRecording(ctx, r.SnapshotArchive, &chk, "Check")
+
+	res, err := r.reconcileNormal(ctx, &chk)
+	if err != nil {
+		log.Error(err, "reconcile failed")
+	}
+	return res, err
+}
+
+func (r *CheckReconciler) reconcileNormal(ctx context.Context, chk *infrav1alpha1.Check) (ctrl.Result, error) {
+	log := logf.FromContext(ctx)
+
+	// 首次执行或 spec 变更：开始新一轮检查
+	if chk.Status.Phase == "" || chk.Generation > chk.Status.ObservedGeneration {
+		return r.startRun(ctx, chk)
+	}
+
+	logging.Reconciling(log, string(chk.Status.Phase))
+
+	switch chk.Status.Phase {
+	case infrav1alpha1.CheckPhaseRunning:
+		return r.reconcileRunning(ctx, chk)
+	case infrav1alpha1.CheckPhasePassed, infrav1alpha1.CheckPhaseFailed:
+		return r.reconcileCompleted(ctx, chk)
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// startRun 开始新一轮检查。
+func (r *CheckReconciler) startRun(ctx context.Context, chk *infrav1alpha1.Check) (ctrl.Result, error) {
+	now := metav1.Now()
+	timeout := shared.GetTimeoutDuration(chk.Spec.Condition.TimeoutSeconds, shared.DefaultReadyConditionTimeout)
+	deadline := metav1.NewTime(shared.CalculateDeadline(now.Time, timeout))
+
+	chk.Status.Phase = infrav1alpha1.CheckPhaseRunning
+	chk.Status.Reason = infrav1alpha1.ReasonRunning
+	chk.Status.Message = ""
+	chk.Status.StartedAt = &now
+	chk.Status.Deadline = &deadline
+	chk.Status.Results = nil
+	chk.Status.SelectorDiagnostics = nil
+	chk.Status.ObservedGeneration = chk.Generation
+	shared.SetCondition(&chk.Status.Conditions, infrav1alpha1.ConditionProgressing,
+		metav1.ConditionTrue, infrav1alpha1.ReasonRunning, "check started", chk.Generation)
+
+	if err := shared.PatchCheckStatus(ctx, r.Client, chk.Name, chk.Namespace, chk.Status); err != nil {
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{Requeue: true}, nil
+}
+
+// reconcileCompleted 处理已完成的轮次：单次模式结束，周期模式到期后开始下一轮。
+func (r *CheckReconciler) reconcileCompleted(ctx context.Context, chk *infrav1alpha1.Check) (ctrl.Result, error) {
+	if chk.Spec.IntervalSeconds <= 0 || chk.Status.LastCompletionTime == nil {
+		return ctrl.Result{}, nil
+	}
+
+	interval := time.Duration(chk.Spec.IntervalSeconds) * time.Second
+	if elapsed := time.Since(chk.Status.LastCompletionTime.Time); elapsed < interval {
+		return ctrl.Result{RequeueAfter: interval - elapsed}, nil
+	}
+	return r.startRun(ctx, chk)
+}
+
+// reconcileRunning 执行一次检查：全部目标资源满足条件则通过，超时则失败。
+func (r *CheckReconciler) reconcileRunning(ctx context.Context, chk *infrav1alpha1.Check) (ctrl.Result, error) {
+	now := metav1.Now()
+	chk.Status.LastCheckTime = &now
+	timedOut := chk.Status.Deadline != nil && now.After(chk.Status.Deadline.Time)
+
+	targets, err := r.listTargets(ctx, chk)
+	if err != nil {
+		return r.finishRun(ctx, chk, false, infrav1alpha1.ReasonFailed, err.Error())
+	}
+
+	chk.Status.MatchedResources = make([]string, 0, len(targets))
+	for _, target := range targets {
+		chk.Status.MatchedResources = append(chk.Status.MatchedResources, stateKey(target))
+	}
+
+	// 未匹配到资源：记录诊断信息并等待
+	if len(targets) == 0 {
+		diag := shared.DiagnoseSelector(ctx, r.Client, chk.Spec.Target, targetNamespace(chk), 0)
+		chk.Status.SelectorDiagnostics = diag
+		if timedOut {
+			return r.finishRun(ctx, chk, false, infrav1alpha1.ReasonTimeout,
+				(&shared.SelectorNoMatchError{Diagnostics: diag}).Error())
+		}
+		chk.Status.Reason = infrav1alpha1.ReasonWaitingForResource
+		return r.patchAndRequeue(ctx, chk, defaultRequeue)
+	}
+	chk.Status.SelectorDiagnostics = nil
+
+	passed, results, err := r.evaluateTargets(ctx, chk, targets)
+	chk.Status.Results = results
+	if err != nil {
+		return r.finishRun(ctx, chk, false, infrav1alpha1.ReasonFailed, fmt.Sprintf("expectations error: %v", err))
+	}
+	if passed {
+		return r.finishRun(ctx, chk, true, infrav1alpha1.ReasonSucceeded,
+			fmt.Sprintf("%d resource(s) passed", len(targets)))
+	}
+	if timedOut {
+		msg := "condition not satisfied before timeout"
+		if failed := shared.FailedExpectationLabels(results); failed != "" {
+			msg += ": " + failed
+		}
+		return r.finishRun(ctx, chk, false, infrav1alpha1.ReasonTimeout, msg)
+	}
+
+	chk.Status.Reason = infrav1alpha1.ReasonRunning
+	// 期望给出重试提示（如平台预计的剩余时间）时延后下次检查
+	return r.patchAndRequeue(ctx, chk, shared.HintedRequeue(shared.RetryAfterHint(results), defaultRequeue, chk.Status.Deadline))
+}
+
+// evaluateTargets 对每个目标资源执行检查条件。
+// 全部通过时返回最后一个资源的结果，否则返回第一个未通过资源的结果。
+func (r *CheckReconciler) evaluateTargets(
+	ctx context.Context,
+	chk *infrav1alpha1.Check,
+	targets []map[string]interface{},
+) (bool, []infrav1alpha1.ExpectationResult, error) {
+	runner := shared.NewExpectationRunner(r.PluginRegistry).WithRelatedReader(ctx, r.Client).WithSubresourceClient(ctx, r.Client).WithFailureInjection(ctx).WithRecording(ctx)
+
+	var last []infrav1alpha1.ExpectationResult
+	for _, target := range targets {
+		state := map[string]interface{}{stateKey(target): target}
+		results, err := runner.RunReadyCondition(&chk.Spec.Condition, state)
+		if err != nil {
+			return false, results.All(), err
+		}
+		if !results.Passed() {
+			return false, results.All(), nil
+		}
+		last = results.All()
+	}
+	return true, last, nil
+}
+
+// finishRun 结束当前轮次：先持久化状态，再发送事件。
+func (r *CheckReconciler) finishRun(ctx context.Context, chk *infrav1alpha1.Check, passed bool, reason, message string) (ctrl.Result, error) {
+	// 检查 API Server 最新状态：本轮已结束时不再重复计数和发送事件
+	if r.runAlreadyFinished(ctx, chk) {
+		return ctrl.Result{Requeue: true}, nil
+	}
+
+	now := metav1.Now()
+	chk.Status.Reason = reason
+	chk.Status.Message = message
+	chk.Status.LastCompletionTime = &now
+	chk.Status.RunCount++
+
+	conditionStatus := metav1.ConditionTrue
+	if passed {
+		chk.Status.Phase = infrav1alpha1.CheckPhasePassed
+		chk.Status.PassCount++
+	} else {
+		chk.Status.Phase = infrav1alpha1.CheckPhaseFailed
+		chk.Status.FailCount++
+		conditionStatus = metav1.ConditionFalse
+	}
+	shared.SetCondition(&chk.Status.Conditions, infrav1alpha1.ConditionReady, conditionStatus, reason, message, chk.Generation)
+	shared.SetCondition(&chk.Status.Conditions, infrav1alpha1.ConditionProgressing,
+		metav1.ConditionFalse, reason, "check completed", chk.Generation)
+
+	if err := shared.PatchCheckStatus(ctx, r.Client, chk.Name, chk.Namespace, chk.Status); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// 每轮检查一个幂等键（轮次即 runCount），各轮同类结果合并为一个 Event 系列
+	round := int(chk.Status.RunCount)
+	eventType, eventReason := corev1.EventTypeNormal, shared.EventReasonCheckPassed
+	if !passed {
+		eventType, eventReason = corev1.EventTypeWarning, shared.EventReasonCheckFailed
+	}
+	shared.EmitSeriesEvent(r.Recorder, chk, shared.EventKey(chk, round, "", eventReason), shared.EventSeriesKey(chk, "", eventReason), eventType, eventReason, message)
+	r.Exporter.Export(ctx, chk, "Check", chk.Spec.Exporters, export.Event{
+		Type:    infrav1alpha1.ExportEventCheckResult,
+		Key:     shared.EventKey(chk, round, "", string(infrav1alpha1.ExportEventCheckResult)),
+		Phase:   string(chk.Status.Phase),
+		Reason:  reason,
+		Message: message,
+		Round:   round,
+		Results: shared.ToExpectationResultSummaries(chk.Status.Results),
+	})
+
+	if chk.Spec.IntervalSeconds > 0 {
+		return ctrl.Result{RequeueAfter: time.Duration(chk.Spec.IntervalSeconds) * time.Second}, nil
+	}
+	return ctrl.Result{}, nil
+}
+
+// runAlreadyFinished 从 API Server 读取最新状态，检查当前轮次是否已被之前的 reconcile 结束。
+func (r *CheckReconciler) runAlreadyFinished(ctx context.Context, chk *infrav1alpha1.Check) bool {
+	var latest infrav1alpha1.Check
+	if !shared.ReadLatest(ctx, r.APIReader, chk, &latest) {
+		return false
+	}
+	return latest.Status.RunCount > chk.Status.RunCount
+}
+
+// patchAndRequeue 持久化中间状态并在 requeueAfter 后重试。
+func (r *CheckReconciler) patchAndRequeue(ctx context.Context, chk *infrav1alpha1.Check, requeueAfter time.Duration) (ctrl.Result, error) {
+	if err := shared.PatchCheckStatus(ctx, r.Client, chk.Name, chk.Namespace, chk.Status); err != nil {
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{RequeueAfter: requeueAfter}, nil
+}
+
+// SetupWithManager wires the controller.
+func (r *CheckReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if r.Recorder == nil {
+		r.Recorder = shared.NewManagerEventRecorder(mgr, "check")
+	}
+	// 只有 spec、标签、注解变化与删除触发调和，status 写入后的推进由 Requeue 安排；
+	// 更新事件按 Debounce 合并
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("check").
+		Watches(&infrav1alpha1.Check{},
+			shared.DebounceUpdates(&handler.EnqueueRequestForObject{}, r.Debounce),
+			builder.WithPredicates(shared.IgnoreSelfUpdates())).
+		Complete(r)
+}
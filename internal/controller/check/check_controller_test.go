@@ -0,0 +1,195 @@
+package check
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	infrav1alpha1 "github.com/lunz1207/testplane/api/v1alpha1"
+	"github.com/lunz1207/testplane/internal/controller/shared"
+	"github.com/lunz1207/testplane/internal/plugin"
+)
+
+var _ = Describe("Check Controller", func() {
+	ctx := context.Background()
+
+	var (
+		c        client.Client
+		r        *CheckReconciler
+		recorder *record.FakeRecorder
+	)
+
+	newCheck := func(interval int32) *infrav1alpha1.Check {
+		return &infrav1alpha1.Check{
+			ObjectMeta: metav1.ObjectMeta{Name: "app-ready", Namespace: "default", Generation: 1},
+			Spec: infrav1alpha1.CheckSpec{
+				Target: infrav1alpha1.ResourceSelector{APIVersion: "v1", Kind: "ConfigMap", Name: "app"},
+				Condition: infrav1alpha1.ReadyCondition{
+					TimeoutSeconds: 60,
+					AllOf:          []infrav1alpha1.Expectation{{Function: "DataReady"}},
+				},
+				IntervalSeconds: interval,
+			},
+		}
+	}
+
+	configMap := func(ready string) *corev1.ConfigMap {
+		return &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default"},
+			Data:       map[string]string{"ready": ready},
+		}
+	}
+
+	setup := func(objs ...client.Object) {
+		scheme := runtime.NewScheme()
+		Expect(corev1.AddToScheme(scheme)).To(Succeed())
+		Expect(infrav1alpha1.AddToScheme(scheme)).To(Succeed())
+		// status SSA 不被 fake client 支持：检查状态在内存中推进
+		c = fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).WithInterceptorFuncs(interceptor.Funcs{
+			SubResourcePatch: func(context.Context, client.Client, string, client.Object, client.Patch, ...client.SubResourcePatchOption) error {
+				return nil
+			},
+		}).Build()
+		registry := plugin.NewRegistry()
+		registry.Register("DataReady", func(res, _ map[string]interface{}) plugin.Result {
+			if plugin.GetNestedString(res, "data.ready") == "true" {
+				return plugin.Pass()
+			}
+			return plugin.Fail("data.ready is not true")
+		})
+		recorder = record.NewFakeRecorder(100)
+		r = &CheckReconciler{Client: c, APIReader: c, Scheme: scheme, PluginRegistry: registry, Recorder: recorder}
+	}
+
+	reconcile := func(chk *infrav1alpha1.Check) (time.Duration, bool) {
+		result, err := r.reconcileNormal(ctx, chk)
+		Expect(err).NotTo(HaveOccurred())
+		return result.RequeueAfter, result.Requeue
+	}
+
+	events := func() string {
+		var out []string
+		for len(recorder.Events) > 0 {
+			out = append(out, <-recorder.Events)
+		}
+		return strings.Join(out, "\n")
+	}
+
+	Context("When the target satisfies the condition", func() {
+		It("should pass the check once", func() {
+			chk := newCheck(0)
+			setup(chk, configMap("true"))
+
+			_, requeue := reconcile(chk)
+			Expect(requeue).To(BeTrue())
+			Expect(chk.Status.Phase).To(Equal(infrav1alpha1.CheckPhaseRunning))
+			Expect(chk.Status.ObservedGeneration).To(Equal(int64(1)))
+
+			after, _ := reconcile(chk)
+			Expect(after).To(BeZero())
+			Expect(chk.Status.Phase).To(Equal(infrav1alpha1.CheckPhasePassed))
+			Expect(chk.Status.MatchedResources).To(ConsistOf("v1/ConfigMap/app"))
+			Expect(chk.Status.RunCount).To(Equal(int32(1)))
+			Expect(chk.Status.PassCount).To(Equal(int32(1)))
+			Expect(shared.IsConditionTrue(chk.Status.Conditions, infrav1alpha1.ConditionReady)).To(BeTrue())
+			Expect(events()).To(ContainSubstring(shared.EventReasonCheckPassed))
+
+			// 单次模式结束后不再调度
+			after, requeue = reconcile(chk)
+			Expect(after).To(BeZero())
+			Expect(requeue).To(BeFalse())
+			Expect(chk.Status.RunCount).To(Equal(int32(1)))
+		})
+	})
+
+	Context("When the target does not satisfy the condition", func() {
+		It("should keep waiting before the deadline and fail after it", func() {
+			chk := newCheck(0)
+			setup(chk, configMap("false"))
+			reconcile(chk)
+
+			after, _ := reconcile(chk)
+			Expect(after).To(Equal(defaultRequeue))
+			Expect(chk.Status.Phase).To(Equal(infrav1alpha1.CheckPhaseRunning))
+			Expect(chk.Status.Reason).To(Equal(infrav1alpha1.ReasonRunning))
+
+			past := metav1.NewTime(time.Now().Add(-time.Second))
+			chk.Status.Deadline = &past
+			reconcile(chk)
+			Expect(chk.Status.Phase).To(Equal(infrav1alpha1.CheckPhaseFailed))
+			Expect(chk.Status.Reason).To(Equal(infrav1alpha1.ReasonTimeout))
+			Expect(chk.Status.FailCount).To(Equal(int32(1)))
+			Expect(shared.IsConditionTrue(chk.Status.Conditions, infrav1alpha1.ConditionReady)).To(BeFalse())
+			Expect(events()).To(ContainSubstring(shared.EventReasonCheckFailed))
+		})
+
+		It("should wait for a missing target and fail with diagnostics after the deadline", func() {
+			chk := newCheck(0)
+			setup(chk)
+			reconcile(chk)
+
+			after, _ := reconcile(chk)
+			Expect(after).To(Equal(defaultRequeue))
+			Expect(chk.Status.Reason).To(Equal(infrav1alpha1.ReasonWaitingForResource))
+
+			past := metav1.NewTime(time.Now().Add(-time.Second))
+			chk.Status.Deadline = &past
+			reconcile(chk)
+			Expect(chk.Status.Phase).To(Equal(infrav1alpha1.CheckPhaseFailed))
+			Expect(chk.Status.Reason).To(Equal(infrav1alpha1.ReasonTimeout))
+		})
+	})
+
+	Context("When the check runs on an interval", func() {
+		It("should requeue after the interval and start the next run when it is due", func() {
+			chk := newCheck(300)
+			setup(chk, configMap("true"))
+			reconcile(chk)
+
+			after, _ := reconcile(chk)
+			Expect(after).To(Equal(300 * time.Second))
+			Expect(chk.Status.Phase).To(Equal(infrav1alpha1.CheckPhasePassed))
+
+			// 未到期：按剩余时间重新入队
+			after, _ = reconcile(chk)
+			Expect(after).To(BeNumerically(">", 290*time.Second))
+			Expect(after).To(BeNumerically("<=", 300*time.Second))
+			Expect(chk.Status.Phase).To(Equal(infrav1alpha1.CheckPhasePassed))
+
+			// 到期：开始下一轮，结果在新一轮结束时计数
+			done := metav1.NewTime(time.Now().Add(-301 * time.Second))
+			chk.Status.LastCompletionTime = &done
+			_, requeue := reconcile(chk)
+			Expect(requeue).To(BeTrue())
+			Expect(chk.Status.Phase).To(Equal(infrav1alpha1.CheckPhaseRunning))
+			Expect(chk.Status.Results).To(BeNil())
+
+			reconcile(chk)
+			Expect(chk.Status.Phase).To(Equal(infrav1alpha1.CheckPhasePassed))
+			Expect(chk.Status.RunCount).To(Equal(int32(2)))
+			Expect(chk.Status.PassCount).To(Equal(int32(2)))
+		})
+
+		It("should restart the run when the spec changes", func() {
+			chk := newCheck(300)
+			setup(chk, configMap("true"))
+			reconcile(chk)
+			reconcile(chk)
+
+			chk.Generation = 2
+			reconcile(chk)
+			Expect(chk.Status.Phase).To(Equal(infrav1alpha1.CheckPhaseRunning))
+			Expect(chk.Status.ObservedGeneration).To(Equal(int64(2)))
+		})
+	})
+})
@@ -0,0 +1,109 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package check
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	infrav1alpha1 "github.com/lunz1207/testplane/api/v1alpha1"
+	"github.com/lunz1207/testplane/internal/controller/shared/resource"
+)
+
+// targetNamespace 返回目标资源的命名空间，为空时使用 Check 所在命名空间。
+func targetNamespace(chk *infrav1alpha1.Check) string {
+	if chk.Spec.Target.Namespace != "" {
+		return chk.Spec.Target.Namespace
+	}
+	return chk.Namespace
+}
+
+// listTargets 按名称、标签或注解选择器查找目标资源（按名称排序）。
+// Name、LabelSelector 和 AnnotationSelector 互斥，只能指定其中一个。
+func (r *CheckReconciler) listTargets(ctx context.Context, chk *infrav1alpha1.Check) ([]map[string]interface{}, error) {
+	sel := chk.Spec.Target
+	ns := targetNamespace(chk)
+
+	selectorCount := 0
+	for _, set := range []bool{sel.Name != "", len(sel.LabelSelector) > 0, len(sel.AnnotationSelector) > 0} {
+		if set {
+			selectorCount++
+		}
+	}
+	if selectorCount != 1 {
+		return nil, fmt.Errorf("target %s/%s: must specify exactly one of name, labelSelector or annotationSelector", sel.APIVersion, sel.Kind)
+	}
+
+	// 按名称查找
+	if sel.Name != "" {
+		obj := &unstructured.Unstructured{}
+		obj.SetAPIVersion(sel.APIVersion)
+		obj.SetKind(sel.Kind)
+		if err := r.Get(ctx, client.ObjectKey{Namespace: ns, Name: sel.Name}, obj); err != nil {
+			if client.IgnoreNotFound(err) == nil {
+				return nil, nil
+			}
+			return nil, fmt.Errorf("get target: %w", err)
+		}
+		resource.StripManagedFields(obj)
+		resource.AttachRelated(ctx, r.Client, obj)
+		return []map[string]interface{}{obj.Object}, nil
+	}
+
+	list := &unstructured.UnstructuredList{}
+	list.SetAPIVersion(sel.APIVersion)
+	list.SetKind(sel.Kind)
+	opts := []client.ListOption{client.InNamespace(ns)}
+	if len(sel.LabelSelector) > 0 {
+		opts = append(opts, client.MatchingLabels(sel.LabelSelector))
+	}
+	if err := r.List(ctx, list, opts...); err != nil {
+		return nil, fmt.Errorf("list targets: %w", err)
+	}
+	sort.Slice(list.Items, func(i, j int) bool { return list.Items[i].GetName() < list.Items[j].GetName() })
+
+	results := make([]map[string]interface{}, 0, len(list.Items))
+	for i := range list.Items {
+		if !matchAnnotations(list.Items[i].GetAnnotations(), sel.AnnotationSelector) {
+			continue
+		}
+		resource.StripManagedFields(&list.Items[i])
+		resource.AttachRelated(ctx, r.Client, &list.Items[i])
+		results = append(results, list.Items[i].Object)
+	}
+	return results, nil
+}
+
+// matchAnnotations 检查注解是否包含选择器中的全部键值。
+func matchAnnotations(annotations, selector map[string]string) bool {
+	for k, v := range selector {
+		if annotations[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+// stateKey 生成 apiVersion/kind/name 格式的 state key。
+func stateKey(obj map[string]interface{}) string {
+	u := unstructured.Unstructured{Object: obj}
+	return fmt.Sprintf("%s/%s/%s", u.GetAPIVersion(), u.GetKind(), u.GetName())
+}
@@ -0,0 +1,110 @@
+package integrationtest
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	infrav1alpha1 "github.com/lunz1207/testplane/api/v1alpha1"
+	"github.com/lunz1207/testplane/internal/controller/shared"
+)
+
+// abort.go 包含 IntegrationTest 的中止（Aborted）逻辑：
+// - 手动中止 / 抢占：通过 infra.testplane.io/abort 注解触发
+// - 依赖失败：spec.dependsOn 中任一测试 Failed/Aborted
+// - 命名空间删除中：测试所在命名空间 Terminating，apply 会被持续拒绝直到超时
+// Aborted 与 Failed 的区别：测试并未因断言或资源操作失败，而是被外部因素终止。
+
+// checkDependencies 检查 spec.dependsOn 中的依赖测试状态，missing 为不存在的依赖。
+// 返回 ready=true 表示所有依赖已成功；failedMsg 非空表示有依赖失败或被中止。
+func (r *IntegrationTestReconciler) checkDependencies(ctx context.Context, it *infrav1alpha1.IntegrationTest) (ready bool, missing []string, failedMsg string, err error) {
+	var pending []string
+	for _, name := range it.Spec.DependsOn {
+		var dep infrav1alpha1.IntegrationTest
+		key := client.ObjectKey{Namespace: it.Namespace, Name: name}
+		if err := r.Get(ctx, key, &dep); err != nil {
+			if apierrors.IsNotFound(err) {
+				pending = append(pending, name)
+				missing = append(missing, name)
+				continue
+			}
+			return false, nil, "", fmt.Errorf("get dependency %s: %w", name, err)
+		}
+
+		switch dep.Status.Phase {
+		case infrav1alpha1.IntegrationTestPhaseSucceeded:
+		case infrav1alpha1.IntegrationTestPhaseFailed, infrav1alpha1.IntegrationTestPhaseAborted:
+			return false, nil, fmt.Sprintf("dependency %s is %s: %s", name, dep.Status.Phase, dep.Status.Message), nil
+		default:
+			pending = append(pending, name)
+		}
+	}
+	return len(pending) == 0, missing, "", nil
+}
+
+// namespaceTerminating 检查测试所在命名空间是否正在删除，是则返回中止消息。
+// 经 APIReader 只读取元数据，不为 Namespace 建立缓存；命名空间不存在时不处理（测试对象会随之删除）。
+func (r *IntegrationTestReconciler) namespaceTerminating(ctx context.Context, it *infrav1alpha1.IntegrationTest) (string, error) {
+	reader := r.APIReader
+	if reader == nil {
+		reader = r.Client
+	}
+	ns := &metav1.PartialObjectMetadata{}
+	ns.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("Namespace"))
+	if err := reader.Get(ctx, client.ObjectKey{Name: it.Namespace}, ns); err != nil {
+		if apierrors.IsNotFound(err) {
+			return "", nil
+		}
+		return "", fmt.Errorf("get namespace %s: %w", it.Namespace, err)
+	}
+	if ns.DeletionTimestamp.IsZero() {
+		return "", nil
+	}
+	return fmt.Sprintf("namespace %s is terminating", it.Namespace), nil
+}
+
+// isNamespaceTerminatingError 判断 apply 错误是否因命名空间正在删除被拒绝。
+func isNamespaceTerminatingError(err error) bool {
+	return apierrors.HasStatusCause(err, corev1.NamespaceTerminatingCause)
+}
+
+// setAborted 设置 IntegrationTest 为中止状态，未结束的步骤一并标记为 Aborted。
+func setAborted(status *infrav1alpha1.IntegrationTestStatus, reason, message string) {
+	now := metav1.Now()
+	for i := range status.Steps {
+		st := &status.Steps[i]
+		if st.State == shared.StateSucceeded || st.State == shared.StateFailed {
+			continue
+		}
+		st.State = shared.StateAborted
+		st.Reason = reason
+		st.FinishedAt = &now
+	}
+
+	status.Phase = infrav1alpha1.IntegrationTestPhaseAborted
+	status.Reason = reason
+	status.Message = message
+	status.CompletionTime = &now
+}
+
+// abortTest 中止测试。
+// 先 patch 状态，成功后再发送 Event。
+func (r *IntegrationTestReconciler) abortTest(ctx context.Context, it *infrav1alpha1.IntegrationTest, reason, message string) (ctrl.Result, error) {
+	// 检查 API Server 最新状态，避免重复事件
+	if r.testAlreadyCompleted(ctx, it) {
+		return ctrl.Result{}, nil
+	}
+
+	setAborted(&it.Status, reason, message)
+	if err := r.patchStatus(ctx, it, it.Status); err != nil {
+		return ctrl.Result{}, err
+	}
+	r.emitWarningEvent(it, -1, shared.EventReasonIntegrationTestAborted, fmt.Sprintf("测试用例已中止 (%s): %s", reason, message))
+	// 中止的步骤的 postStep 与 cleanupPolicy 删除由下一次调和执行
+	return ctrl.Result{Requeue: pendingPostSteps(it) || testCleanupPending(it)}, nil
+}
@@ -0,0 +1,51 @@
+package integrationtest
+
+import (
+	"context"
+	"fmt"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	infrav1alpha1 "github.com/lunz1207/testplane/api/v1alpha1"
+	"github.com/lunz1207/testplane/internal/controller/shared"
+	"github.com/lunz1207/testplane/internal/controller/shared/logging"
+)
+
+// ReasonBreakpoint 测试停在断点（spec.debug.breakOnFailure 时步骤失败）的原因，阶段保持 Running。
+const ReasonBreakpoint = "Breakpoint"
+
+// resumeFromBreakpoint 处理停在断点的测试：infra.testplane.io/resume 注解的令牌与 status.resumeToken 不同时，
+// 重置失败的步骤（下一次调和重新执行，已 apply 的资源按台账保留）并清除断点；否则保持等待，不安排 requeue。
+// 中止由 infra.testplane.io/abort 注解在调和入口处理。
+func (r *IntegrationTestReconciler) resumeFromBreakpoint(ctx context.Context, it *infrav1alpha1.IntegrationTest) (ctrl.Result, error) {
+	log := logf.FromContext(ctx)
+	bp := it.Status.Breakpoint
+	token := it.GetAnnotations()[infrav1alpha1.AnnotationResume]
+	if token == "" || token == it.Status.ResumeToken {
+		logging.WaitingFor(log, "breakpoint resume", "step", bp.Step, "round", bp.Round)
+		return ctrl.Result{}, nil
+	}
+	// 缓存尚未同步上一次恢复：避免重置已重新开始的步骤
+	if latest := r.latestStatus(ctx, it); latest != nil && latest.ResumeToken == token {
+		return ctrl.Result{Requeue: true}, nil
+	}
+
+	for i := range it.Status.Steps {
+		st := &it.Status.Steps[i]
+		if st.State == shared.StateFailed {
+			*st = infrav1alpha1.StepStatus{Name: st.Name, Index: st.Index}
+		}
+	}
+	it.Status.Breakpoint = nil
+	it.Status.ResumeToken = token
+	it.Status.Reason = ""
+	it.Status.Message = ""
+	// 先 patch，成功后再发 Event
+	if err := r.patchStatus(ctx, it, it.Status); err != nil {
+		return ctrl.Result{}, err
+	}
+	log.Info("resumed from breakpoint", "step", bp.Step, "round", bp.Round, "token", token)
+	r.emitNormalEvent(it, -1, shared.EventReasonIntegrationTestResumed, fmt.Sprintf("[Round %d] 从断点恢复，重试步骤 %s", bp.Round, bp.Step))
+	return ctrl.Result{Requeue: true}, nil
+}
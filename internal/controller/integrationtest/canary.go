@@ -0,0 +1,105 @@
+package integrationtest
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	infrav1alpha1 "github.com/lunz1207/testplane/api/v1alpha1"
+)
+
+// canaryExpect 金丝雀比较结果的期望函数名称。
+const canaryExpect = "CanaryDeviation"
+
+// defaultCanaryMaxDeviationPercent 未设置 maxDeviationPercent 时允许的偏差百分比。
+const defaultCanaryMaxDeviationPercent = 10
+
+// compareCanary 获取基线与金丝雀资源并逐个比较指标，每个指标返回一个结果。
+// 任一资源未匹配或提取值不是数值时结果标记为错误（可重试）；提取器未知、参数无效等永久性错误直接返回。
+func (r *IntegrationTestReconciler) compareCanary(ctx context.Context, it *infrav1alpha1.IntegrationTest, canary *infrav1alpha1.CanarySpec) ([]infrav1alpha1.ExpectationResult, error) {
+	baseline, err := r.canaryResource(ctx, it, canary.Baseline)
+	if err != nil {
+		return nil, fmt.Errorf("canary baseline: %w", err)
+	}
+	target, err := r.canaryResource(ctx, it, canary.Canary)
+	if err != nil {
+		return nil, fmt.Errorf("canary target: %w", err)
+	}
+
+	results := make([]infrav1alpha1.ExpectationResult, 0, len(canary.Metrics))
+	for _, metric := range canary.Metrics {
+		result := infrav1alpha1.ExpectationResult{Name: metric.Name, Expect: canaryExpect, Params: metric.Extract.Params}
+		switch {
+		case baseline == nil:
+			result.Error, result.Message = true, fmt.Sprintf("baseline %s not found", getSelectorKey(canary.Baseline))
+		case target == nil:
+			result.Error, result.Message = true, fmt.Sprintf("canary %s not found", getSelectorKey(canary.Canary))
+		default:
+			base, err := r.extractCanaryValue(metric, baseline)
+			if err != nil {
+				return nil, err
+			}
+			value, err := r.extractCanaryValue(metric, target)
+			if err != nil {
+				return nil, err
+			}
+			evaluateCanaryMetric(&result, metric, base, value)
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// canaryResource 返回选择器匹配的第一个资源（按名称排序），未匹配时返回 nil。
+func (r *IntegrationTestReconciler) canaryResource(ctx context.Context, it *infrav1alpha1.IntegrationTest, sel infrav1alpha1.ResourceSelector) (map[string]interface{}, error) {
+	resources, err := r.listBySelector(ctx, it, sel)
+	if err != nil {
+		return nil, err
+	}
+	return r.findMatchingResource(ctx, it, sel, resources, nil).Matched, nil
+}
+
+// extractCanaryValue 对资源执行指标的提取器，返回提取值。
+func (r *IntegrationTestReconciler) extractCanaryValue(metric infrav1alpha1.CanaryMetric, obj map[string]interface{}) (string, error) {
+	result, err := r.PluginRegistry.Call(metric.Extract.Function, obj, metric.Extract.Params.Raw)
+	if err != nil {
+		return "", fmt.Errorf("canary metric %s: run function %s: %w", metric.Name, metric.Extract.Function, err)
+	}
+	return strings.TrimSpace(result.Value), nil
+}
+
+// evaluateCanaryMetric 按 maxDeviationPercent 判定金丝雀值相对基线的偏差，提取值不是数值时记为可重试错误。
+func evaluateCanaryMetric(result *infrav1alpha1.ExpectationResult, metric infrav1alpha1.CanaryMetric, base, value string) {
+	result.Actual = fmt.Sprintf("baseline=%s canary=%s", base, value)
+	b, errB := strconv.ParseFloat(base, 64)
+	c, errC := strconv.ParseFloat(value, 64)
+	if errB != nil || errC != nil {
+		result.Error, result.Message = true, "extracted values are not numeric"
+		return
+	}
+
+	limit := float64(defaultCanaryMaxDeviationPercent)
+	if metric.MaxDeviationPercent != nil {
+		limit = float64(*metric.MaxDeviationPercent)
+	}
+	deviation := canaryDeviation(b, c)
+	result.Passed = deviation <= limit
+	if math.IsInf(deviation, 1) {
+		result.Message = fmt.Sprintf("baseline is 0, canary is %s", value)
+		return
+	}
+	result.Message = fmt.Sprintf("deviation %.2f%% (max %.0f%%)", deviation, limit)
+}
+
+// canaryDeviation 返回金丝雀相对基线的偏差百分比；基线为 0 时金丝雀也为 0 返回 0，否则返回 +Inf。
+func canaryDeviation(base, value float64) float64 {
+	if base == 0 {
+		if value == 0 {
+			return 0
+		}
+		return math.Inf(1)
+	}
+	return math.Abs(value-base) / math.Abs(base) * 100
+}
@@ -0,0 +1,194 @@
+package integrationtest
+
+import (
+	"context"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	infrav1alpha1 "github.com/lunz1207/testplane/api/v1alpha1"
+	"github.com/lunz1207/testplane/internal/controller/shared"
+	"github.com/lunz1207/testplane/internal/controller/shared/resource"
+)
+
+// handleDeletion 测试删除时先按依赖顺序清理步骤 apply 的资源（见 resource.Manager.DeleteInOrder），
+// 全部删除完成或超过等待时长后再移除 finalizer。
+func (r *IntegrationTestReconciler) handleDeletion(ctx context.Context, it *infrav1alpha1.IntegrationTest) (ctrl.Result, error) {
+	if !controllerutil.ContainsFinalizer(it, integrationTestFinalizer) {
+		return ctrl.Result{}, nil
+	}
+	// 只在副本上合成 base：移除 finalizer 会写回整个对象；合成失败的步骤在 stepObjects 中跳过
+	resolved := it.DeepCopy()
+	_ = r.resolveManifests(ctx, resolved)
+	done, err := r.ResourceManager.DeleteInOrder(ctx, it, r.stepObjects(resolved), it.Spec.Cleanup)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if !done {
+		return ctrl.Result{RequeueAfter: defaultRequeue}, nil
+	}
+	r.forgetStatusDigest(it)
+	r.forgetResourceSnapshots(it)
+	return shared.HandleDeletion(ctx, r.Client, it, integrationTestFinalizer)
+}
+
+// stepObjects 按步骤声明顺序（步骤内依次为 preStep、步骤资源、postStep）返回 Apply 清单的资源；
+// 展开失败的清单跳过，其资源由 GC 清理。
+func (r *IntegrationTestReconciler) stepObjects(it *infrav1alpha1.IntegrationTest) []*unstructured.Unstructured {
+	var objs []*unstructured.Unstructured
+	for _, step := range it.Spec.Steps {
+		objs = append(objs, r.objectsOfStep(it, step)...)
+	}
+	return objs
+}
+
+// objectsOfStep 按 preStep、步骤资源、postStep 的顺序返回单个步骤 Apply 清单的资源。
+func (r *IntegrationTestReconciler) objectsOfStep(it *infrav1alpha1.IntegrationTest, step infrav1alpha1.TestStep) []*unstructured.Unstructured {
+	var objs []*unstructured.Unstructured
+	add := func(manifest *resource.ExpandedManifest, err error) {
+		if err == nil && manifest != nil && !manifest.IsDelete() {
+			objs = append(objs, manifest.Object)
+		}
+	}
+	for _, ref := range resource.StepRefs(step) {
+		if len(ref.Ref.Manifest.Raw) > 0 {
+			add(expandStepRef(it, *ref.Ref))
+		}
+	}
+	return objs
+}
+
+// runCleanupPolicies 按 cleanupPolicy 推进测试执行期间的资源删除：已结束步骤按步骤级策略，
+// 测试结束后其余步骤按测试级策略。返回是否仍有删除在进行（调用方等待后重新调和）。
+// 停在断点的测试保留现场；删除失败记录在删除进度中并发送 Warning 事件，不再重试，资源留待测试删除时清理。
+func (r *IntegrationTestReconciler) runCleanupPolicies(ctx context.Context, it *infrav1alpha1.IntegrationTest) (bool, error) {
+	terminal := isTerminalPhase(it.Status.Phase)
+	if it.Status.Breakpoint != nil && !terminal {
+		return false, nil
+	}
+	var (
+		running, changed bool
+		failures         []string
+	)
+	advance := func(objs []*unstructured.Unstructured, status **infrav1alpha1.CleanupStatus, what string) {
+		if cleanupFinished(*status) {
+			return
+		}
+		before := *status
+		done, err := r.ResourceManager.DeleteInOrder(ctx, it, objs, it.Spec.Cleanup)
+		*status = cleanupStatus(done, err)
+		changed = changed || before == nil || (*status).State != before.State
+		switch {
+		case err != nil:
+			failures = append(failures, fmt.Sprintf("%s: %v", what, err))
+		case !done:
+			running = true
+		}
+	}
+
+	for i := range it.Status.Steps {
+		st := &it.Status.Steps[i]
+		step, ok := stepSpec(it, st)
+		// 周期步骤在本轮其余步骤期间仍会重新执行，随测试一起清理
+		if !ok || step.PeriodicSeconds > 0 || !cleanupDue(step.CleanupPolicy, st.State == shared.StateSucceeded, stepFinished(st.State)) {
+			continue
+		}
+		advance(r.objectsOfStep(it, step), &st.Cleanup, "step "+step.Name)
+	}
+	if terminal && testCleanupPending(it) {
+		advance(r.testCleanupObjects(it), &it.Status.Cleanup, "test")
+	}
+
+	if changed {
+		if err := r.patchStatus(ctx, it, it.Status); err != nil {
+			return false, err
+		}
+	}
+	for _, msg := range failures {
+		r.emitWarningEvent(it, -1, shared.EventReasonCleanupFailed, fmt.Sprintf("[Round %d] 按 cleanupPolicy 删除资源失败 (%s)", it.Status.CurrentRound, msg))
+	}
+	return running, nil
+}
+
+// testCleanupObjects 返回测试结束时按测试级策略删除的资源：未设置步骤级策略的步骤，
+// 以及步骤级策略对其结果生效的周期步骤。
+func (r *IntegrationTestReconciler) testCleanupObjects(it *infrav1alpha1.IntegrationTest) []*unstructured.Unstructured {
+	succeeded := it.Status.Phase == infrav1alpha1.IntegrationTestPhaseSucceeded
+	var objs []*unstructured.Unstructured
+	for i, step := range it.Spec.Steps {
+		switch {
+		case step.CleanupPolicy == "":
+			if !cleanupDue(it.Spec.CleanupPolicy, succeeded, true) {
+				continue
+			}
+		case step.PeriodicSeconds > 0:
+			state := roundStepState(it, i)
+			if !cleanupDue(step.CleanupPolicy, state == shared.StateSucceeded, stepFinished(state)) {
+				continue
+			}
+		default:
+			continue
+		}
+		objs = append(objs, r.objectsOfStep(it, step)...)
+	}
+	return objs
+}
+
+// hasPeriodicCleanup 判断是否有设置了步骤级删除策略的周期步骤（测试结束时清理）。
+func hasPeriodicCleanup(it *infrav1alpha1.IntegrationTest) bool {
+	for _, step := range it.Spec.Steps {
+		if step.PeriodicSeconds > 0 && step.CleanupPolicy != "" && step.CleanupPolicy != infrav1alpha1.CleanupPolicyKeep {
+			return true
+		}
+	}
+	return false
+}
+
+// roundStepState 返回步骤在当前轮次的状态，尚未执行时为空。
+func roundStepState(it *infrav1alpha1.IntegrationTest, index int) string {
+	for i := range it.Status.Steps {
+		if st := &it.Status.Steps[i]; st.Index == index && st.Name == it.Spec.Steps[index].Name {
+			return st.State
+		}
+	}
+	return ""
+}
+
+// testCleanupPending 判断结束的测试是否还需要按 cleanupPolicy 删除资源。
+func testCleanupPending(it *infrav1alpha1.IntegrationTest) bool {
+	if cleanupFinished(it.Status.Cleanup) {
+		return false
+	}
+	return cleanupDue(it.Spec.CleanupPolicy, it.Status.Phase == infrav1alpha1.IntegrationTestPhaseSucceeded, true) || hasPeriodicCleanup(it)
+}
+
+// cleanupDue 判断删除策略对给定结果是否生效：DeleteOnSuccess 只在成功时，DeleteAlways 在结束后总是生效。
+func cleanupDue(policy infrav1alpha1.CleanupPolicy, succeeded, finished bool) bool {
+	switch policy {
+	case infrav1alpha1.CleanupPolicyDeleteOnSuccess:
+		return succeeded
+	case infrav1alpha1.CleanupPolicyDeleteAlways:
+		return finished
+	}
+	return false
+}
+
+// cleanupFinished 判断删除是否已完成或已失败。
+func cleanupFinished(status *infrav1alpha1.CleanupStatus) bool {
+	return status != nil && status.State != shared.StateRunning
+}
+
+// cleanupStatus 由一次删除推进的结果构造删除进度。
+func cleanupStatus(done bool, err error) *infrav1alpha1.CleanupStatus {
+	now := metav1.Now()
+	switch {
+	case err != nil:
+		return &infrav1alpha1.CleanupStatus{State: shared.StateFailed, Message: err.Error(), FinishedAt: &now}
+	case done:
+		return &infrav1alpha1.CleanupStatus{State: shared.StateSucceeded, FinishedAt: &now}
+	}
+	return &infrav1alpha1.CleanupStatus{State: shared.StateRunning}
+}
@@ -0,0 +1,82 @@
+package integrationtest
+
+import (
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	infrav1alpha1 "github.com/lunz1207/testplane/api/v1alpha1"
+	"github.com/lunz1207/testplane/internal/controller/shared"
+)
+
+// 截止时间 Condition 类型，对应的截止时间记录在 status.deadlines 中（key 为 Condition 类型）。
+const (
+	// ConditionTypeStepDeadline 进行中步骤的超时时间（并行时取最早的）。
+	ConditionTypeStepDeadline = "StepDeadline"
+	// ConditionTypeReadyConditionDeadline 进行中步骤 readyCondition 的超时时间。
+	ConditionTypeReadyConditionDeadline = "ReadyConditionDeadline"
+	// ConditionTypeMaxDurationDeadline repeat.maxDurationSeconds 到期、不再开始新一轮的时间。
+	ConditionTypeMaxDurationDeadline = "MaxDurationDeadline"
+)
+
+// deadlineReasonInactive 截止时间 Condition 为 False 时的原因。
+const deadlineReasonInactive = "NoPendingDeadline"
+
+// pendingDeadline 一个进行中的截止时间。
+type pendingDeadline struct {
+	at      *metav1.Time
+	reason  string
+	message string
+}
+
+// syncDeadlines 按当前状态刷新截止时间 Condition 与 status.deadlines，由 patchStatus 在写入前调用，
+// 仪表盘可直接显示倒计时而无需根据 spec 推算。
+// 有进行中的截止时间时 Condition 为 True，截止时间变化（如进入下一步骤）时重置 lastTransitionTime；
+// 截止时间结束后 Condition 变为 False 并移除对应的 deadlines 项。从未有过截止时间的类型不创建 Condition。
+func syncDeadlines(it *infrav1alpha1.IntegrationTest) {
+	status := &it.Status
+	pending := map[string]pendingDeadline{}
+	// 暂停期间截止时间不再生效，恢复时按顺延后的时间重新设置
+	if status.Phase == infrav1alpha1.IntegrationTestPhaseRunning && status.SuspendedAt == nil {
+		for i := range status.Steps {
+			st := &status.Steps[i]
+			if st.State != shared.StateRunning {
+				continue
+			}
+			if st.Deadline != nil && (pending[ConditionTypeStepDeadline].at == nil || st.Deadline.Before(pending[ConditionTypeStepDeadline].at)) {
+				pending[ConditionTypeStepDeadline] = pendingDeadline{st.Deadline, "StepRunning", fmt.Sprintf("step %s times out at %s", st.Name, st.Deadline.UTC().Format(time.RFC3339))}
+			}
+			if rc := st.ReadyConditionStatus; rc != nil && rc.Deadline != nil && rc.State != shared.StatePassed && rc.State != shared.StateFailed {
+				pending[ConditionTypeReadyConditionDeadline] = pendingDeadline{rc.Deadline, "ReadyConditionPending", fmt.Sprintf("readyCondition of step %s times out at %s", st.Name, rc.Deadline.UTC().Format(time.RFC3339))}
+			}
+		}
+		if repeat := it.Spec.Repeat; repeat != nil && repeat.MaxDurationSeconds > 0 && status.StartTime != nil {
+			at := metav1.NewTime(status.StartTime.Add(time.Duration(repeat.MaxDurationSeconds) * time.Second))
+			pending[ConditionTypeMaxDurationDeadline] = pendingDeadline{&at, "RoundsRunning", fmt.Sprintf("no new round starts after %s", at.UTC().Format(time.RFC3339))}
+		}
+	}
+
+	for _, conditionType := range []string{ConditionTypeStepDeadline, ConditionTypeReadyConditionDeadline, ConditionTypeMaxDurationDeadline} {
+		d, ok := pending[conditionType]
+		if !ok {
+			delete(status.Deadlines, conditionType)
+			if shared.IsConditionTrue(status.Conditions, conditionType) {
+				shared.SetCondition(&status.Conditions, conditionType, metav1.ConditionFalse, deadlineReasonInactive, "", it.Generation)
+			}
+			continue
+		}
+		if previous, ok := status.Deadlines[conditionType]; ok && !previous.Equal(d.at) {
+			// 新的截止时间：重新计时
+			shared.RemoveCondition(&status.Conditions, conditionType)
+		}
+		if status.Deadlines == nil {
+			status.Deadlines = map[string]metav1.Time{}
+		}
+		status.Deadlines[conditionType] = *d.at
+		shared.SetCondition(&status.Conditions, conditionType, metav1.ConditionTrue, d.reason, d.message, it.Generation)
+	}
+	if len(status.Deadlines) == 0 {
+		status.Deadlines = nil
+	}
+}
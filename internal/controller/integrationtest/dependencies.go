@@ -0,0 +1,81 @@
+package integrationtest
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	infrav1alpha1 "github.com/lunz1207/testplane/api/v1alpha1"
+	"github.com/lunz1207/testplane/internal/controller/shared"
+	"github.com/lunz1207/testplane/internal/controller/shared/logging"
+)
+
+// ConditionTypeDependencyNotFound spec.dependsOn 中有测试不存在：True 时 Message 列出缺失的依赖，
+// lastTransitionTime 为开始等待的时间。
+const ConditionTypeDependencyNotFound = "DependencyNotFound"
+
+// ReasonDependencyNotFound 依赖的测试在等待时限内仍不存在时测试的失败原因。
+const ReasonDependencyNotFound = "DependencyNotFound"
+
+// reasonDependenciesFound DependencyNotFound Condition 为 False 时的原因。
+const reasonDependenciesFound = "DependenciesFound"
+
+// defaultDependencyWait 未设置 repeat.maxDurationSeconds 时等待依赖出现的时限。
+const defaultDependencyWait = 10 * time.Minute
+
+// dependencyWait 返回等待不存在的依赖出现的时限：设置了 repeat.maxDurationSeconds 时取该值，否则为 defaultDependencyWait。
+func dependencyWait(it *infrav1alpha1.IntegrationTest) time.Duration {
+	if repeat := it.Spec.Repeat; repeat != nil && repeat.MaxDurationSeconds > 0 {
+		return time.Duration(repeat.MaxDurationSeconds) * time.Second
+	}
+	return defaultDependencyWait
+}
+
+// syncMissingDependencies 维护 DependencyNotFound Condition，只在 Condition 变化时写入 status：
+//   - 有依赖不存在时置为 True，从置为 True 起超过 dependencyWait 仍未出现则测试失败（依赖名拼写错误等不会永远停在 Pending）
+//   - 依赖全部出现后置为 False
+//
+// 返回 true 表示仍在等待或测试已失败，调用方直接返回 result。
+func (r *IntegrationTestReconciler) syncMissingDependencies(ctx context.Context, it *infrav1alpha1.IntegrationTest, missing []string) (bool, ctrl.Result, error) {
+	status := &it.Status
+	if len(missing) == 0 {
+		if !shared.IsConditionTrue(status.Conditions, ConditionTypeDependencyNotFound) {
+			return false, ctrl.Result{}, nil
+		}
+		shared.SetCondition(&status.Conditions, ConditionTypeDependencyNotFound, metav1.ConditionFalse, reasonDependenciesFound, "", it.Generation)
+		return false, ctrl.Result{}, r.patchStatus(ctx, it, it.Status)
+	}
+
+	message := "dependencies not found: " + strings.Join(missing, ", ")
+	existing := shared.GetCondition(status.Conditions, ConditionTypeDependencyNotFound)
+	if existing == nil || existing.Status != metav1.ConditionTrue || existing.Message != message {
+		shared.SetCondition(&status.Conditions, ConditionTypeDependencyNotFound, metav1.ConditionTrue, ReasonDependencyNotFound, message, it.Generation)
+		if err := r.patchStatus(ctx, it, it.Status); err != nil {
+			return true, ctrl.Result{}, err
+		}
+		existing = shared.GetCondition(status.Conditions, ConditionTypeDependencyNotFound)
+	}
+
+	wait := dependencyWait(it)
+	if remaining := time.Until(existing.LastTransitionTime.Add(wait)); remaining > 0 {
+		logging.WaitingFor(logf.FromContext(ctx), "dependencies", "missing", missing, "remaining", remaining.Round(time.Second))
+		return true, ctrl.Result{RequeueAfter: min(defaultRequeue, remaining)}, nil
+	}
+
+	if r.testAlreadyCompleted(ctx, it) {
+		return true, ctrl.Result{}, nil
+	}
+	failedMsg := fmt.Sprintf("%s after waiting %s", message, wait)
+	setTestFailed(status, ReasonDependencyNotFound, failedMsg)
+	if err := r.patchStatus(ctx, it, it.Status); err != nil {
+		return true, ctrl.Result{}, err
+	}
+	r.emitWarningEvent(it, -1, shared.EventReasonIntegrationTestFailed, "依赖的测试用例不存在: "+failedMsg)
+	// 失败分诊由下一次调和处理；status 写入不会触发 watch，需显式 Requeue
+	return true, ctrl.Result{Requeue: true}, nil
+}
@@ -2,6 +2,7 @@ package integrationtest
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
@@ -21,15 +22,72 @@ func (r *IntegrationTestReconciler) executeTest(ctx context.Context, it *infrav1
 		return ctrl.Result{}, nil
 	}
 
-	// Pending → Running：初始化并开始测试
+	// 暂停：不再推进步骤与轮次，恢复时顺延步骤截止时间
+	if suspended, result, err := r.suspendOrResume(ctx, it); suspended || err != nil {
+		return result, err
+	}
+
+	// 断点：保留资源等待调试，resume 注解重试失败的步骤
+	if it.Status.Breakpoint != nil {
+		return r.resumeFromBreakpoint(ctx, it)
+	}
+
+	// 已结束步骤的 postStep：在推进下一步骤或下一轮之前执行
+	if err := r.runPostSteps(ctx, it); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// 步骤级 cleanupPolicy：已结束步骤的资源删除完成后再推进
+	if running, err := r.runCleanupPolicies(ctx, it); err != nil || running {
+		return ctrl.Result{RequeueAfter: defaultRequeue}, err
+	}
+
+	// Pending → Running：依赖全部成功后初始化并开始测试
 	if it.Status.Phase == infrav1alpha1.IntegrationTestPhasePending {
+		ready, missing, failedMsg, err := r.checkDependencies(ctx, it)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if failedMsg != "" {
+			return r.abortTest(ctx, it, infrav1alpha1.AbortReasonDependencyFailed, failedMsg)
+		}
+		if waiting, result, err := r.syncMissingDependencies(ctx, it, missing); waiting || err != nil {
+			return result, err
+		}
+		if !ready {
+			logging.WaitingFor(logf.FromContext(ctx), "dependencies", "dependsOn", it.Spec.DependsOn)
+			return ctrl.Result{RequeueAfter: defaultRequeue}, nil
+		}
+
+		// 预检：引用的 Secret/ConfigMap、选择器类型、Webhook 地址，配置错误直接失败
+		problems, err := r.preflight(ctx, it)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if problems != "" {
+			return r.failPreflight(ctx, it, problems)
+		}
+
+		// 缓存尚未同步上一次 status 写入：测试已开始，避免重复事件
+		if r.phaseAlreadyAdvanced(ctx, it) {
+			return ctrl.Result{Requeue: true}, nil
+		}
+		// 以最小权限身份执行资源写入：预检通过后生成 Role 并绑定到测试专属 ServiceAccount
+		if it.Spec.ScopedRBAC {
+			// 预检之后才出现的同名身份对象同样按预检失败处理，不接管
+			if err := r.ensureScopedRBAC(ctx, it); errors.Is(err, errScopedRBACConflict) {
+				return r.failPreflight(ctx, it, err.Error())
+			} else if err != nil {
+				return ctrl.Result{}, err
+			}
+		}
 		it.Status.Phase = infrav1alpha1.IntegrationTestPhaseRunning
 		r.initRepeatStatus(&it.Status)
 		// 先 patch，成功后再发 Event
 		if err := r.patchStatus(ctx, it, it.Status); err != nil {
 			return ctrl.Result{}, err
 		}
-		shared.EmitNormalEvent(r.Recorder, it, shared.EventReasonIntegrationTestStarted, fmt.Sprintf("开始执行测试用例，模式: %s, 轮数: %s", it.Spec.Mode, formatTotalRounds(it)))
+		r.emitNormalEvent(it, -1, shared.EventReasonIntegrationTestStarted, fmt.Sprintf("开始执行测试用例，模式: %s, 轮数: %s", it.Spec.Mode, formatTotalRounds(it)))
 	}
 
 	// 检查是否达到停止条件
@@ -37,6 +95,30 @@ func (r *IntegrationTestReconciler) executeTest(ctx context.Context, it *infrav1
 		return r.finishTest(ctx, it)
 	}
 
+	// 固定速率：新一轮按 ratePerMinute 的时间槽开始
+	if waiting, result, err := r.waitForRateSlot(ctx, it); waiting || err != nil {
+		return result, err
+	}
+
+	// 轮次时间窗口：新一轮只在窗口内开始
+	if waiting, result, err := r.waitForWindow(ctx, it); waiting || err != nil {
+		return result, err
+	}
+
+	// 轮次闸门：新一轮在外部信号放行后开始
+	if waiting, result, err := r.waitForGate(ctx, it); waiting || err != nil {
+		return result, err
+	}
+
+	// 推进已成功的周期步骤，与本轮其余步骤并行
+	failed, err := r.runPeriodicSteps(ctx, it)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if failed {
+		return r.handleStepFailure(ctx, it)
+	}
+
 	// 从 spec 获取 mode
 	mode := it.Spec.Mode
 	if mode == "" {
@@ -91,36 +173,38 @@ func (r *IntegrationTestReconciler) shouldStopRepeat(tc *infrav1alpha1.Integrati
 	return false
 }
 
-// startNextRound 开始下一轮执行。
+// startNextRound 结束当前轮次并开始下一轮执行。
+// 轮次切换涉及 CompletedRounds、CurrentRound、CurrentStepIndex、Steps 多个字段，
+// 全部在内存中完成后由一次 status patch 提交；字段以赋值而非自增更新，
+// 因此缓存延迟或崩溃后重复进入不会重复计数或跳过轮次。
 func (r *IntegrationTestReconciler) startNextRound(ctx context.Context, it *infrav1alpha1.IntegrationTest) (ctrl.Result, error) {
 	log := logf.FromContext(ctx)
 
-	// 避免重复增加 CompletedRounds（轮间延迟返回后会再次进入此函数）
-	if len(it.Status.Steps) > 0 {
-		it.Status.CompletedRounds++
-		logging.RoundCompleted(log, it.Status.CurrentRound)
+	// 缓存尚未同步上一次轮次切换：等待缓存追上，避免基于旧状态再次切换
+	if r.roundAlreadyAdvanced(ctx, it) {
+		return ctrl.Result{Requeue: true}, nil
+	}
 
-		// 重置步骤索引（准备下一轮或结束）
-		zero := 0
-		it.Status.CurrentStepIndex = &zero
+	if completeRound(&it.Status, len(it.Spec.Steps)) {
+		logging.RoundCompleted(log, it.Status.CurrentRound)
 	}
 
-	// 检查是否应该停止
+	// 检查是否应该停止（完成记录与终态在 finishTest 中一次提交）
 	if r.shouldStopRepeat(it, &it.Status) {
 		return r.finishTest(ctx, it)
 	}
 
-	// 继续下一轮，递增轮数并重置 Steps 状态
-	it.Status.CurrentRound++
-	it.Status.Steps = nil
+	// 轮次之间清理步骤资源的已完成子对象
+	r.pruneCompletedChildren(ctx, it)
 
-	// patch 状态
+	// 继续下一轮：递增轮数并重置 Steps 状态，与完成记录一次提交
+	advanceRound(&it.Status)
 	if err := r.patchStatus(ctx, it, it.Status); err != nil {
 		return ctrl.Result{}, err
 	}
 
-	// 轮间延迟
-	if it.Spec.Repeat != nil && it.Spec.Repeat.DelayBetweenRounds > 0 {
+	// 轮间延迟（设置 ratePerMinute 时由时间槽决定开始时间）
+	if it.Spec.Repeat != nil && it.Spec.Repeat.DelayBetweenRounds > 0 && it.Spec.Repeat.RatePerMinute == 0 {
 		log.V(logging.LevelVerbose).Info("delay between rounds", "seconds", it.Spec.Repeat.DelayBetweenRounds)
 		return ctrl.Result{RequeueAfter: time.Duration(it.Spec.Repeat.DelayBetweenRounds) * time.Second}, nil
 	}
@@ -128,3 +212,46 @@ func (r *IntegrationTestReconciler) startNextRound(ctx context.Context, it *infr
 	logging.RoundStarted(log, it.Status.CurrentRound)
 	return ctrl.Result{Requeue: true}, nil
 }
+
+// completeRound 记录当前轮次已完成，返回是否发生变更。
+// CompletedRounds 以当前轮次赋值：同一轮重复调用结果相同。
+// 有步骤的测试在步骤状态为空时（刚切换到新一轮、尚未开始）不视为完成。
+func completeRound(status *infrav1alpha1.IntegrationTestStatus, totalSteps int) bool {
+	if status.CompletedRounds >= status.CurrentRound {
+		return false
+	}
+	if totalSteps > 0 && len(status.Steps) == 0 {
+		return false
+	}
+	status.CompletedRounds = status.CurrentRound
+	recordRoundStats(status)
+	zero := 0
+	status.CurrentStepIndex = &zero
+	return true
+}
+
+// advanceRound 切换到下一轮：CurrentRound 由 CompletedRounds 推导，清空步骤状态。
+// 当前轮次尚未记录完成时（已切换过）不做任何变更。
+func advanceRound(status *infrav1alpha1.IntegrationTestStatus) {
+	if status.CurrentRound > status.CompletedRounds {
+		return
+	}
+	status.CurrentRound = status.CompletedRounds + 1
+	status.Steps = nil
+	zero := 0
+	status.CurrentStepIndex = &zero
+}
+
+// failPreflight 以 PreflightFailed 结束测试并发送失败事件。
+func (r *IntegrationTestReconciler) failPreflight(ctx context.Context, it *infrav1alpha1.IntegrationTest, problems string) (ctrl.Result, error) {
+	if r.testAlreadyCompleted(ctx, it) {
+		return ctrl.Result{}, nil
+	}
+	setTestFailed(&it.Status, ReasonPreflightFailed, "preflight failed: "+problems)
+	if err := r.patchStatus(ctx, it, it.Status); err != nil {
+		return ctrl.Result{}, err
+	}
+	r.emitWarningEvent(it, -1, shared.EventReasonIntegrationTestFailed, "测试用例预检未通过: "+problems)
+	// 失败分诊由下一次调和处理；status 写入不会触发 watch，需显式 Requeue
+	return ctrl.Result{Requeue: true}, nil
+}
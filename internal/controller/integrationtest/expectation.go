@@ -1,12 +1,115 @@
 package integrationtest
 
 import (
+	"context"
+	"fmt"
+
 	infrav1alpha1 "github.com/lunz1207/testplane/api/v1alpha1"
 	"github.com/lunz1207/testplane/internal/controller/shared"
 )
 
 // runExpectations 执行一组期望检查（委托给 shared.ExpectationRunner）。
-func (r *IntegrationTestReconciler) runExpectations(expectations *infrav1alpha1.StepCondition, state map[string]interface{}) (shared.ExpectationResults, error) {
-	runner := shared.NewExpectationRunner(r.PluginRegistry)
+// 期望声明的 relatedResources 通过 Client 获取，参数引用按测试变量与已完成步骤的输出替换，
+// resource 别名按步骤的 as 解析，tombstone 期望读取 Delete 步骤资源删除前的快照。
+// valueFrom 变量在此时读取引用的测试，取不到值时引用它的期望稍后重试。
+// held 为上次检查的 holdSeconds 跟踪状态，为 nil 时不跟踪（holdSeconds 被忽略）。
+func (r *IntegrationTestReconciler) runExpectations(ctx context.Context, it *infrav1alpha1.IntegrationTest, expectations *infrav1alpha1.StepCondition, state map[string]interface{}, held []shared.HeldSince) (shared.ExpectationResults, error) {
+	runner := shared.NewExpectationRunner(r.PluginRegistry).WithRelatedReader(ctx, r.Client).WithSubresourceClient(ctx, r.Client).WithFailureInjection(ctx).WithEnvironment(ctx).WithParamValues(paramValues(it)).WithRecording(ctx)
+	if held != nil {
+		runner.WithHeldSince(held)
+	}
+	runner.WithUnresolvedParams(r.resolveVariableSources(ctx, it, runner.ParamValues))
+	runner.WithResourceResolver(func(alias string) (map[string]interface{}, error) {
+		return r.resolveAlias(ctx, it, alias)
+	})
+	runner.WithTombstones(func(name string) (map[string]interface{}, bool) {
+		return r.stepTombstone(it, name)
+	})
 	return runner.RunStepCondition(expectations, state)
 }
+
+// paramValues 汇总期望参数可引用的值：spec.variables 与本轮已记录输出的步骤。
+func paramValues(it *infrav1alpha1.IntegrationTest) shared.ParamValues {
+	values := shared.ParamValues{}
+	for _, v := range it.Spec.Variables {
+		if v.ValueFrom != nil {
+			continue
+		}
+		values[shared.VarKey(v.Name)] = v.Value
+	}
+	for _, st := range it.Status.Steps {
+		for k, v := range st.Outputs {
+			values[shared.StepOutputKey(st.Name, k)] = v
+		}
+	}
+	return values
+}
+
+// resolveVariableSources 解析 valueFrom 变量并写入 values，返回无法取值的引用及原因。
+func (r *IntegrationTestReconciler) resolveVariableSources(ctx context.Context, it *infrav1alpha1.IntegrationTest, values shared.ParamValues) map[string]error {
+	var unresolved map[string]error
+	for _, v := range it.Spec.Variables {
+		if v.ValueFrom == nil || v.ValueFrom.TestRef == nil {
+			continue
+		}
+		value, err := shared.ResolveTestField(ctx, r.Client, it.Namespace, v.ValueFrom.TestRef)
+		if err != nil {
+			if unresolved == nil {
+				unresolved = map[string]error{}
+			}
+			unresolved[shared.VarKey(v.Name)] = err
+			continue
+		}
+		values[shared.VarKey(v.Name)] = value
+	}
+	return unresolved
+}
+
+// extractStepOutputs 返回步骤的输出：内置输出加上 spec.steps[].outputs 提取的值（同名时覆盖内置输出）。
+// 提取函数不存在、参数引用未定义、执行失败或结果为空时返回错误。
+func (r *IntegrationTestReconciler) extractStepOutputs(it *infrav1alpha1.IntegrationTest, step infrav1alpha1.TestStep, state map[string]interface{}) (map[string]string, error) {
+	outputs := stepOutputs(state)
+	if len(step.Outputs) == 0 {
+		return outputs, nil
+	}
+	obj := shared.SelectStateForExpectation(state)
+	if obj == nil {
+		return nil, fmt.Errorf("no resource to extract outputs from")
+	}
+	values := paramValues(it)
+	for _, out := range step.Outputs {
+		params, err := shared.ExpandParams(out.Extract.Params, values)
+		if err != nil {
+			return nil, fmt.Errorf("output %s: %w", out.Name, err)
+		}
+		result, err := r.PluginRegistry.Call(out.Extract.Function, obj, params.Raw)
+		if err != nil {
+			return nil, fmt.Errorf("output %s: %w", out.Name, err)
+		}
+		if result.Value == "" {
+			return nil, fmt.Errorf("output %s: %s returned no value", out.Name, out.Extract.Function)
+		}
+		if outputs == nil {
+			outputs = map[string]string{}
+		}
+		outputs[out.Name] = result.Value
+	}
+	return outputs, nil
+}
+
+// stepOutputs 从步骤断言的资源中提取输出（kind、name、namespace），无资源时返回 nil。
+func stepOutputs(state map[string]interface{}) map[string]string {
+	obj := shared.SelectStateForExpectation(state)
+	kind, _ := obj["kind"].(string)
+	meta, _ := obj["metadata"].(map[string]interface{})
+	if kind == "" || meta == nil {
+		return nil
+	}
+	outputs := map[string]string{"kind": kind}
+	for _, key := range []string{"name", "namespace"} {
+		if v, ok := meta[key].(string); ok && v != "" {
+			outputs[key] = v
+		}
+	}
+	return outputs
+}
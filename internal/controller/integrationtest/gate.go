@@ -0,0 +1,111 @@
+package integrationtest
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	infrav1alpha1 "github.com/lunz1207/testplane/api/v1alpha1"
+	"github.com/lunz1207/testplane/internal/controller/shared/logging"
+)
+
+// reasonWaitingForGate 轮次等待闸门放行时的 status.reason。
+const reasonWaitingForGate = "WaitingForGate"
+
+// defaultGatePoll 闸门未放行时的默认轮询间隔。
+const defaultGatePoll = 30 * time.Second
+
+// gateHTTPClient 查询闸门 Webhook 使用的 HTTP 客户端。
+var gateHTTPClient = &http.Client{Timeout: 10 * time.Second}
+
+// waitForGate 检查新一轮能否开始：未设置 repeat.gate 或本轮已开始时直接放行；
+// 未放行时记录 WaitingForGate 并按 pollSeconds 重新查询，返回 waiting=true。
+func (r *IntegrationTestReconciler) waitForGate(ctx context.Context, it *infrav1alpha1.IntegrationTest) (bool, ctrl.Result, error) {
+	if it.Spec.Repeat == nil || it.Spec.Repeat.Gate == nil || len(it.Status.Steps) > 0 {
+		return false, ctrl.Result{}, nil
+	}
+	gate := it.Spec.Repeat.Gate
+	open, detail, err := r.gateOpen(ctx, it.Namespace, gate)
+	if err != nil {
+		return false, ctrl.Result{}, fmt.Errorf("invalid repeat gate: %w", err)
+	}
+
+	if open {
+		if it.Status.Reason != reasonWaitingForGate {
+			return false, ctrl.Result{}, nil
+		}
+		// 闸门已放行：清除等待原因后开始本轮
+		it.Status.Reason = ""
+		it.Status.Message = ""
+		if err := r.patchStatus(ctx, it, it.Status); err != nil {
+			return false, ctrl.Result{}, err
+		}
+		return false, ctrl.Result{}, nil
+	}
+
+	message := fmt.Sprintf("round %d waiting for gate: %s", it.Status.CurrentRound, detail)
+	if len(message) > 256 {
+		message = message[:253] + "..."
+	}
+	if it.Status.Reason != reasonWaitingForGate || it.Status.Message != message {
+		it.Status.Reason = reasonWaitingForGate
+		it.Status.Message = message
+		if err := r.patchStatus(ctx, it, it.Status); err != nil {
+			return false, ctrl.Result{}, err
+		}
+		logging.WaitingFor(logf.FromContext(ctx), "round gate", "detail", detail)
+	}
+
+	poll := defaultGatePoll
+	if gate.PollSeconds > 0 {
+		poll = time.Duration(gate.PollSeconds) * time.Second
+	}
+	return true, ctrl.Result{RequeueAfter: boundedWait(it, poll)}, nil
+}
+
+// gateOpen 查询闸门是否放行，未放行时返回原因。Webhook 与 configMapKey 都未设置或同时设置时返回错误。
+func (r *IntegrationTestReconciler) gateOpen(ctx context.Context, namespace string, gate *infrav1alpha1.RoundGate) (bool, string, error) {
+	if (gate.Webhook == "") == (gate.ConfigMapKey == nil) {
+		return false, "", fmt.Errorf("exactly one of webhook or configMapKey must be set")
+	}
+
+	if gate.Webhook != "" {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, gate.Webhook, nil)
+		if err != nil {
+			return false, "", fmt.Errorf("build webhook request: %w", err)
+		}
+		resp, err := gateHTTPClient.Do(req)
+		if err != nil {
+			return false, fmt.Sprintf("webhook request failed: %v", err), nil
+		}
+		defer func() { _ = resp.Body.Close() }()
+		if resp.StatusCode != http.StatusOK {
+			return false, fmt.Sprintf("webhook returned %d", resp.StatusCode), nil
+		}
+		return true, "", nil
+	}
+
+	ref := gate.ConfigMapKey
+	reader := r.APIReader
+	if reader == nil {
+		reader = r.Client
+	}
+	var cm corev1.ConfigMap
+	if err := reader.Get(ctx, client.ObjectKey{Namespace: namespace, Name: ref.Name}, &cm); err != nil {
+		if apierrors.IsNotFound(err) {
+			return false, fmt.Sprintf("ConfigMap %s not found", ref.Name), nil
+		}
+		return false, fmt.Sprintf("get ConfigMap %s failed: %v", ref.Name, err), nil
+	}
+	if value := cm.Data[ref.Key]; value != "true" {
+		return false, fmt.Sprintf("ConfigMap %s key %s is %q", ref.Name, ref.Key, value), nil
+	}
+	return true, "", nil
+}
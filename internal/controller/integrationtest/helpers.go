@@ -2,18 +2,58 @@ package integrationtest
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
 	"time"
 
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	infrav1alpha1 "github.com/lunz1207/testplane/api/v1alpha1"
 	"github.com/lunz1207/testplane/internal/controller/shared"
 )
 
-// patchStatus 使用纯正 SSA 更新 IntegrationTest 状态。
+// patchStatus 使用纯正 SSA 更新 IntegrationTest 状态：status.steps 与其余字段由不同的字段管理者写入，
+// 步骤状态与上一次写入相同时只写测试级字段。
 func (r *IntegrationTestReconciler) patchStatus(ctx context.Context, it *infrav1alpha1.IntegrationTest, _ infrav1alpha1.IntegrationTestStatus) error {
-	return shared.PatchIntegrationTestStatusFromObject(ctx, r.Client, it)
+	syncDeadlines(it)
+	key := statusDigestKey(it)
+	digest, err := shared.NormalizedHash(it.Status.Steps)
+	if err != nil {
+		return err
+	}
+	if written, ok := r.stepDigests.Load(key); !ok || written != digest {
+		if err := shared.PatchIntegrationTestSteps(ctx, r.Client, it.Name, it.Namespace, it.Status.Steps); err != nil {
+			r.stepDigests.Delete(key)
+			return err
+		}
+		r.stepDigests.Store(key, digest)
+	}
+	// 终态后步骤状态不再推进：不保留摘要，避免已结束的测试一直占用内存；之后的少量写入（如重跑）照常写入步骤状态
+	if isTerminalPhase(it.Status.Phase) {
+		r.stepDigests.Delete(key)
+		r.forgetResourceSnapshots(it)
+	}
+	return shared.PatchIntegrationTestFields(ctx, r.Client, it.Name, it.Namespace, it.Status)
+}
+
+// forgetStatusDigest 删除测试后清除记录的步骤状态摘要。
+func (r *IntegrationTestReconciler) forgetStatusDigest(it *infrav1alpha1.IntegrationTest) {
+	r.stepDigests.Delete(statusDigestKey(it))
+}
+
+// forgetResourceSnapshots 测试删除或进入终态后清除资源管理器为其保存的墓碑与 apply 前快照。
+func (r *IntegrationTestReconciler) forgetResourceSnapshots(it *infrav1alpha1.IntegrationTest) {
+	if r.ResourceManager != nil {
+		r.ResourceManager.ForgetOwner(it.UID)
+	}
+}
+
+// statusDigestKey 返回 stepDigests 的键，包含 UID，删除后同名重建的测试不会沿用旧摘要。
+func statusDigestKey(it *infrav1alpha1.IntegrationTest) string {
+	return string(it.UID) + "/" + it.Namespace + "/" + it.Name
 }
 
 // ensureStepStatus 确保步骤状态存在并填充超时信息。
@@ -66,7 +106,8 @@ func stepTimeout(step infrav1alpha1.TestStep) time.Duration {
 	return defaultStepTimeout
 }
 
-// isTerminalPhase 检查是否为终态。
+// isTerminalPhase 检查是否为终态（Succeeded、Failed、Aborted）。
+// Aborted 表示被外部中止，不代表测试本身失败。
 func isTerminalPhase(phase infrav1alpha1.IntegrationTestPhase) bool {
 	return phase == infrav1alpha1.IntegrationTestPhaseSucceeded ||
 		phase == infrav1alpha1.IntegrationTestPhaseFailed ||
@@ -83,33 +124,113 @@ func nextStepIndex(statuses []infrav1alpha1.StepStatus) int {
 	return len(statuses)
 }
 
-// stepAlreadyFinished 从 API Server 读取最新状态，检查步骤是否已完成。
-// 用于在 patch 前检查，避免缓存延迟导致的重复事件。
-func (r *IntegrationTestReconciler) stepAlreadyFinished(ctx context.Context, it *infrav1alpha1.IntegrationTest, stepIndex int) bool {
-	if r.APIReader == nil {
-		return false
+// emitNormalEvent 发送带幂等键的 Normal 事件，stepIndex < 0 表示测试级事件。
+func (r *IntegrationTestReconciler) emitNormalEvent(it *infrav1alpha1.IntegrationTest, stepIndex int, reason, message string) {
+	shared.EmitAnnotatedSeriesEvent(r.Recorder, it, eventKey(it, stepIndex, reason), seriesKey(it, stepIndex, reason), corev1.EventTypeNormal, reason, message, eventAnnotations(it, stepIndex))
+}
+
+// emitWarningEvent 发送带幂等键的 Warning 事件，stepIndex < 0 表示测试级事件。
+func (r *IntegrationTestReconciler) emitWarningEvent(it *infrav1alpha1.IntegrationTest, stepIndex int, reason, message string) {
+	shared.EmitAnnotatedSeriesEvent(r.Recorder, it, eventKey(it, stepIndex, reason), seriesKey(it, stepIndex, reason), corev1.EventTypeWarning, reason, message, eventAnnotations(it, stepIndex))
+}
+
+// eventAnnotations 生成事件的结果注解：轮次；步骤事件另附步骤名称、输出（JSON）与期望通过数/总数。
+func eventAnnotations(it *infrav1alpha1.IntegrationTest, stepIndex int) map[string]string {
+	annotations := map[string]string{shared.AnnotationEventRound: strconv.Itoa(it.Status.CurrentRound)}
+	if stepIndex < 0 || stepIndex >= len(it.Status.Steps) {
+		return annotations
+	}
+	step := it.Status.Steps[stepIndex]
+	annotations[shared.AnnotationEventStep] = step.Name
+	if len(step.Outputs) > 0 {
+		if data, err := json.Marshal(step.Outputs); err == nil {
+			annotations[shared.AnnotationEventOutputs] = string(data)
+		}
+	}
+	if len(step.ExpectationResults) > 0 {
+		passed := 0
+		for _, result := range step.ExpectationResults {
+			if result.Passed {
+				passed++
+			}
+		}
+		annotations[shared.AnnotationEventExpectationsPassed] = strconv.Itoa(passed)
+		annotations[shared.AnnotationEventExpectationsTotal] = strconv.Itoa(len(step.ExpectationResults))
+	}
+	return annotations
+}
+
+// eventKey 生成事件幂等键：测试 UID + 当前轮次 + 步骤序号（重试后附尝试序号）+ 状态转换。
+func eventKey(it *infrav1alpha1.IntegrationTest, stepIndex int, transition string) string {
+	step := ""
+	if stepIndex >= 0 {
+		step = strconv.Itoa(stepIndex)
+		if stepIndex < len(it.Status.Steps) && len(it.Status.Steps[stepIndex].Attempts) > 0 {
+			step += "-attempt-" + strconv.Itoa(len(it.Status.Steps[stepIndex].Attempts))
+		}
+	}
+	return shared.EventKey(it, it.Status.CurrentRound, step, transition)
+}
+
+// seriesKey 生成事件系列键：重复测试中各轮相同步骤的相同状态转换合并为一个 Event 系列。
+func seriesKey(it *infrav1alpha1.IntegrationTest, stepIndex int, transition string) string {
+	step := ""
+	if stepIndex >= 0 {
+		step = strconv.Itoa(stepIndex)
 	}
+	return shared.EventSeriesKey(it, step, transition)
+}
+
+// latestStatus 从 API Server 读取最新状态，读取失败或未配置 APIReader 时返回 nil。
+func (r *IntegrationTestReconciler) latestStatus(ctx context.Context, it *infrav1alpha1.IntegrationTest) *infrav1alpha1.IntegrationTestStatus {
 	var latest infrav1alpha1.IntegrationTest
-	if err := r.APIReader.Get(ctx, client.ObjectKeyFromObject(it), &latest); err != nil {
+	if !shared.ReadLatest(ctx, r.APIReader, it, &latest) {
+		return nil
+	}
+	return &latest.Status
+}
+
+// phaseAlreadyAdvanced 从 API Server 读取最新状态，检查阶段是否已被之前的 reconcile 推进。
+// 用于在阶段转换前检查，避免缓存延迟导致的重复转换和重复事件。
+func (r *IntegrationTestReconciler) phaseAlreadyAdvanced(ctx context.Context, it *infrav1alpha1.IntegrationTest) bool {
+	latest := r.latestStatus(ctx, it)
+	return latest != nil && latest.Phase != it.Status.Phase
+}
+
+// roundAlreadyAdvanced 从 API Server 读取最新状态，检查轮次是否已被之前的 reconcile 切换。
+func (r *IntegrationTestReconciler) roundAlreadyAdvanced(ctx context.Context, it *infrav1alpha1.IntegrationTest) bool {
+	latest := r.latestStatus(ctx, it)
+	return latest != nil && latest.CurrentRound > it.Status.CurrentRound
+}
+
+// stepAlreadyStarted 从 API Server 读取最新状态，检查步骤是否已开始执行（资源已 apply）。
+// 用于在 apply 前检查，避免缓存延迟导致重复进入步骤。
+func (r *IntegrationTestReconciler) stepAlreadyStarted(ctx context.Context, it *infrav1alpha1.IntegrationTest, stepIndex int) bool {
+	latest := r.latestStatus(ctx, it)
+	if latest == nil || latest.CurrentRound != it.Status.CurrentRound {
 		return false
 	}
-	if stepIndex < 0 || stepIndex >= len(latest.Status.Steps) {
+	if stepIndex < 0 || stepIndex >= len(latest.Steps) {
 		return false
 	}
-	return latest.Status.Steps[stepIndex].FinishedAt != nil
+	return latest.Steps[stepIndex].State != ""
 }
 
-// testAlreadyCompleted 从 API Server 读取最新状态，检查测试是否已完成。
+// stepAlreadyFinished 从 API Server 读取最新状态，检查步骤是否已完成。
 // 用于在 patch 前检查，避免缓存延迟导致的重复事件。
-func (r *IntegrationTestReconciler) testAlreadyCompleted(ctx context.Context, it *infrav1alpha1.IntegrationTest) bool {
-	if r.APIReader == nil {
-		return false
-	}
-	var latest infrav1alpha1.IntegrationTest
-	if err := r.APIReader.Get(ctx, client.ObjectKeyFromObject(it), &latest); err != nil {
+func (r *IntegrationTestReconciler) stepAlreadyFinished(ctx context.Context, it *infrav1alpha1.IntegrationTest, stepIndex int) bool {
+	latest := r.latestStatus(ctx, it)
+	if latest == nil || stepIndex < 0 || stepIndex >= len(latest.Steps) {
 		return false
 	}
-	return latest.Status.CompletionTime != nil
+	return latest.Steps[stepIndex].FinishedAt != nil
+}
+
+// testAlreadyCompleted 从 API Server 读取最新状态，检查测试是否已完成。
+// 用于在 patch 前检查，避免缓存延迟导致的重复事件。
+func (r *IntegrationTestReconciler) testAlreadyCompleted(ctx context.Context, it *infrav1alpha1.IntegrationTest) bool {
+	latest := r.latestStatus(ctx, it)
+	return latest != nil && latest.CompletionTime != nil
 }
 
 // Condition 类型常量
@@ -117,8 +238,9 @@ const (
 	ConditionTypeSpecChangedIgnored = "SpecChangedIgnored"
 )
 
-// detectAndIgnoreSpecChange 检测运行中的 IntegrationTest spec 变更并忽略。
-// 返回 true 表示检测到 spec 变更（已被忽略），调用方需要 patch 状态。
+// detectAndIgnoreSpecChange 检测运行中的 IntegrationTest spec 变更并忽略，
+// 与开始执行时的摘要比较，把变更路径写入 status.ignoredSpecChanges 与 Condition 消息。
+// 返回 true 表示检测到新的 spec 变更（已被忽略），调用方需要 patch 状态并发送事件；同一 generation 只返回一次。
 func (r *IntegrationTestReconciler) detectAndIgnoreSpecChange(_ context.Context, it *infrav1alpha1.IntegrationTest) bool {
 	// 只有在已开始执行后（ObservedGeneration > 0）才检测变更
 	if it.Status.ObservedGeneration == 0 {
@@ -130,11 +252,32 @@ func (r *IntegrationTestReconciler) detectAndIgnoreSpecChange(_ context.Context,
 		return false
 	}
 
-	// spec 已变更，设置 Condition 警告用户
+	// 本 generation 的变更已记录
+	if cond := shared.GetCondition(it.Status.Conditions, ConditionTypeSpecChangedIgnored); cond != nil && cond.ObservedGeneration == it.Generation {
+		return false
+	}
+
+	// spec 已变更，设置 Condition 警告用户；早于摘要记录开始的测试无法比较，只给出通用消息
+	message := "spec was modified while integrationtest is running, changes are ignored"
+	if it.Status.SpecDigest != nil {
+		changes := diffSpecDigest(it.Status.SpecDigest, specDigest(it.Spec))
+		if len(changes) > maxIgnoredSpecChanges {
+			changes = append(changes[:maxIgnoredSpecChanges], fmt.Sprintf("(%d more)", len(changes)-maxIgnoredSpecChanges))
+		}
+		// 与开始时相比没有差异（如只切换了运行中生效的 spec.suspend），不算作被忽略的变更
+		if len(changes) == 0 {
+			return false
+		}
+		it.Status.IgnoredSpecChanges = changes
+		if len(changes) > 0 {
+			message += ": " + strings.Join(changes, ", ")
+		}
+		if len(message) > 256 {
+			message = message[:253] + "..."
+		}
+	}
 	shared.SetCondition(&it.Status.Conditions, ConditionTypeSpecChangedIgnored,
-		metav1.ConditionTrue, "SpecModified",
-		"spec was modified while integrationtest is running, changes are ignored",
-		it.Generation)
+		metav1.ConditionTrue, "SpecModified", message, it.Generation)
 
 	return true
 }
@@ -0,0 +1,63 @@
+package integrationtest
+
+import (
+	"context"
+	"time"
+
+	infrav1alpha1 "github.com/lunz1207/testplane/api/v1alpha1"
+	"github.com/lunz1207/testplane/internal/controller/shared"
+	"github.com/lunz1207/testplane/pkg/hooks"
+)
+
+// dispatchHooks 比较调和前后的状态，对本次调和中开始/结束的步骤与进入终态的测试调用阶段钩子。
+// 只比较同一轮次的步骤；状态只在 patch 成功后才会进入内存对象，因此每个转换只通知一次。
+func (r *IntegrationTestReconciler) dispatchHooks(ctx context.Context, it *infrav1alpha1.IntegrationTest, previous *infrav1alpha1.IntegrationTestStatus) {
+	if previous == nil {
+		return
+	}
+	object := hooks.ObjectRef{
+		Kind:      "IntegrationTest",
+		Namespace: it.Namespace,
+		Name:      it.Name,
+		UID:       string(it.UID),
+		Labels:    it.Labels,
+	}
+	status := &it.Status
+	for i := range status.Steps {
+		step := status.Steps[i]
+		before := ""
+		if previous.CurrentRound == status.CurrentRound && i < len(previous.Steps) {
+			before = previous.Steps[i].State
+		}
+		if step.State == before {
+			continue
+		}
+		event := hooks.StepEvent{Object: object, Round: status.CurrentRound, Index: i, Step: step}
+		if before == "" {
+			r.Hooks.StepStarted(ctx, event)
+		}
+		if stepFinished(step.State) && !stepFinished(before) {
+			r.Hooks.StepFinished(ctx, event)
+		}
+	}
+
+	if isTerminalPhase(status.Phase) && !isTerminalPhase(previous.Phase) {
+		var duration time.Duration
+		if status.StartTime != nil && status.CompletionTime != nil {
+			duration = status.CompletionTime.Sub(status.StartTime.Time)
+		}
+		r.Hooks.TestFinished(ctx, hooks.TestEvent{
+			Object:   object,
+			Round:    status.CurrentRound,
+			Phase:    string(status.Phase),
+			Reason:   status.Reason,
+			Message:  status.Message,
+			Duration: duration,
+		})
+	}
+}
+
+// stepFinished 判断步骤状态是否为结束状态。
+func stepFinished(state string) bool {
+	return state == shared.StateSucceeded || state == shared.StateFailed || state == shared.StateAborted
+}
@@ -18,20 +18,27 @@ package integrationtest
 
 import (
 	"context"
+	"sync"
 	"time"
 
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 
 	infrav1alpha1 "github.com/lunz1207/testplane/api/v1alpha1"
 	"github.com/lunz1207/testplane/internal/controller/shared"
 	"github.com/lunz1207/testplane/internal/controller/shared/logging"
+	"github.com/lunz1207/testplane/internal/controller/shared/metrics"
 	"github.com/lunz1207/testplane/internal/controller/shared/resource"
 	"github.com/lunz1207/testplane/internal/plugin"
+	"github.com/lunz1207/testplane/pkg/hooks"
+	"github.com/lunz1207/testplane/pkg/recording"
 )
 
 const (
@@ -47,20 +54,41 @@ type IntegrationTestReconciler struct {
 	client.Client
 	Scheme          *runtime.Scheme
 	PluginRegistry  *plugin.Registry
-	APIReader       client.Reader        // 用于 waitResourcesConverge 绕过缓存检查收敛状态
-	Recorder        record.EventRecorder // 事件记录器
-	ResourceManager *resource.Manager    // 资源管理器
+	APIReader       client.Reader          // 用于 waitResourcesConverge 绕过缓存检查收敛状态
+	Recorder        record.EventRecorder   // 事件记录器
+	ResourceManager *resource.Manager      // 资源管理器
+	SnapshotArchive recording.Archive      // 状态快照归档（可选，用于录制与重放）
+	Exporter        *shared.ResultExporter // 结果导出（可选）
+	Debounce        time.Duration          // 更新事件合并窗口（可选）
+	Hooks           *hooks.Registry        // 阶段钩子（可选）
+	APIRemoval      *shared.APIRemoval     // 资源类型移除检测（可选，未设置时由 SetupWithManager 创建）
+	RESTConfig      *rest.Config           // 以 spec.scopedRBAC 身份写入时模拟身份的基础配置（可选，未设置时使用 manager 的配置）
+
+	stepDigests sync.Map // 每个测试最近一次写入的 status.steps 摘要，未变化时跳过步骤级写入；终态与删除时清除
 }
 
 // +kubebuilder:rbac:groups=infra.testplane.io,resources=integrationtests,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=infra.testplane.io,resources=integrationtests/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=infra.testplane.io,resources=integrationtests/finalizers,verbs=update
+// +kubebuilder:rbac:groups=infra.testplane.io,resources=environments,verbs=get;list;watch
 // +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+// +kubebuilder:rbac:groups=events.k8s.io,resources=events,verbs=get;create;update;patch
 // 需要操作任意资源用于测试。
 // +kubebuilder:rbac:groups=*,resources=*,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups="",resources=serviceaccounts,verbs=impersonate
 
 func (r *IntegrationTestReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	// 按调和结果记录耗时、重新入队与错误指标
+	ctx, done := metrics.Start(ctx, "integrationtest")
+	result, err := r.reconcile(ctx, req)
+	done(result, err)
+	return result, err
+}
+
+func (r *IntegrationTestReconciler) reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	baseLog := logf.FromContext(ctx)
+	// API 请求的 User-Agent 附带测试对象，便于 API Server 端按测试归属负载
+	ctx = shared.WithRequestUserAgent(ctx, "IntegrationTest", req.NamespacedName)
 
 	var it infrav1alpha1.IntegrationTest
 	if err := r.Get(ctx, req.NamespacedName, &it); err != nil {
@@ -71,27 +99,86 @@ func (r *IntegrationTestReconciler) Reconcile(ctx context.Context, req ctrl.Requ
 	log := logging.WithKindName(baseLog, "IntegrationTest", it.Namespace, it.Name)
 	ctx = logf.IntoContext(ctx, log)
 
+	// 故障注入（调试用）：注解解析失败时忽略注入，不影响正常执行
+	if injCtx, err := shared.ContextWithFailureInjection(ctx, it.GetAnnotations()); err != nil {
+		log.Info("ignore invalid failure injection annotation", "error", err.Error())
+	} else {
+		ctx = injCtx
+	}
+	ctx = shared.ContextWithRecording(ctx, r.SnapshotArchive, &it, "IntegrationTest")
+
 	r.ensureRegistry()
 	r.ensureResourceManager()
 
 	if !it.DeletionTimestamp.IsZero() {
-		return shared.HandleDeletion(ctx, r.Client, &it, integrationTestFinalizer)
+		return r.handleDeletion(ctx, &it)
+	}
+
+	// 模板只供 TestSuite 复制，不执行
+	if shared.IsTemplate(it.GetAnnotations()) {
+		return ctrl.Result{}, nil
 	}
 
 	if !controllerutil.ContainsFinalizer(&it, integrationTestFinalizer) {
 		return shared.EnsureFinalizer(ctx, r.Client, &it, integrationTestFinalizer)
 	}
 
+	// base 引用的基础清单与 patches 合成到内存中的 spec，之后的 ${env.*} 等替换作用于合成结果
+	if err := r.resolveManifests(ctx, &it); err != nil && !isTerminalPhase(it.Status.Phase) {
+		log.Error(err, "resolve manifest base failed")
+		return ctrl.Result{}, err
+	}
+
+	// 引用的 Environment 展开到内存中的 spec；终态只用于失败分诊，解析失败不影响
+	env, err := shared.ResolveEnvironment(ctx, r.Client, it.Namespace, it.Spec.EnvironmentRef)
+	if err == nil {
+		err = shared.ExpandEnvironment(env, &it.Spec)
+	}
+	switch {
+	case err == nil:
+		ctx = shared.ContextWithEnvironment(ctx, env)
+	case isTerminalPhase(it.Status.Phase):
+		log.Info("ignore environment error in terminal phase", "error", err.Error())
+	default:
+		log.Error(err, "resolve environment failed")
+		return ctrl.Result{}, err
+	}
+	if err := expandWorkspace(&it); err != nil && !isTerminalPhase(it.Status.Phase) {
+		log.Error(err, "expand workspace failed")
+		return ctrl.Result{}, err
+	}
+
+	previous := it.Status.Phase
+	var previousStatus *infrav1alpha1.IntegrationTestStatus
+	if !r.Hooks.Empty() {
+		previousStatus = it.Status.DeepCopy()
+	}
 	res, err := r.reconcileNormal(ctx, &it)
 	if err != nil {
 		log.Error(err, "reconcile failed")
+		return res, err
+	}
+	// 本次调和进入终态（成功、失败或中止）时立即记录结果摘要：status 写入不会触发新的调和
+	if isTerminalPhase(it.Status.Phase) && !isTerminalPhase(previous) {
+		if err := r.recordSummary(ctx, &it); err != nil {
+			log.Error(err, "record summary failed")
+			return ctrl.Result{}, err
+		}
 	}
-	return res, err
+	r.dispatchHooks(ctx, &it, previousStatus)
+	r.Exporter.ExportPhaseChange(ctx, &it, "IntegrationTest", it.Spec.Exporters, it.Status.CurrentRound,
+		string(previous), string(it.Status.Phase), it.Status.Reason, it.Status.Message)
+	return res, nil
 }
 
 func (r *IntegrationTestReconciler) reconcileNormal(ctx context.Context, it *infrav1alpha1.IntegrationTest) (ctrl.Result, error) {
 	log := logf.FromContext(ctx)
 
+	// 重跑：清空状态后从头执行
+	if token, ok := shared.RerunRequested(it.GetAnnotations(), it.Status.RerunToken); ok {
+		return r.rerunTest(ctx, it, token)
+	}
+
 	// 初始化状态（如需要）
 	if it.Status.Phase == "" {
 		return r.initializeTest(ctx, it)
@@ -100,7 +187,33 @@ func (r *IntegrationTestReconciler) reconcileNormal(ctx context.Context, it *inf
 	logging.Reconciling(log, string(it.Status.Phase))
 
 	if isTerminalPhase(it.Status.Phase) {
-		return ctrl.Result{}, nil
+		// 进入终态时未能记录结果摘要（如 patch 失败）时补齐
+		if err := r.recordSummary(ctx, it); err != nil {
+			return ctrl.Result{}, err
+		}
+		// 失败或中止的步骤的 postStep 在测试结束后执行
+		if err := r.runPostSteps(ctx, it); err != nil {
+			return ctrl.Result{}, err
+		}
+		// 按 cleanupPolicy 删除资源，完成后再进行失败分诊
+		if running, err := r.runCleanupPolicies(ctx, it); err != nil || running {
+			return ctrl.Result{RequeueAfter: defaultRequeue}, err
+		}
+		return r.triageFailure(ctx, it)
+	}
+
+	// 手动中止 / 抢占
+	if reason, message, ok := shared.AbortRequested(it.GetAnnotations()); ok {
+		return r.abortTest(ctx, it, reason, message)
+	}
+
+	// 命名空间删除中：中止测试，不再 apply
+	message, err := r.namespaceTerminating(ctx, it)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if message != "" {
+		return r.abortTest(ctx, it, infrav1alpha1.AbortReasonNamespaceTerminating, message)
 	}
 
 	// 检测运行中的 spec 变更并忽略
@@ -109,6 +222,8 @@ func (r *IntegrationTestReconciler) reconcileNormal(ctx context.Context, it *inf
 		if err := r.patchStatus(ctx, it, it.Status); err != nil {
 			return ctrl.Result{}, err
 		}
+		cond := shared.GetCondition(it.Status.Conditions, ConditionTypeSpecChangedIgnored)
+		r.emitWarningEvent(it, -1, shared.EventReasonSpecChangeIgnored, cond.Message)
 	}
 
 	// 执行测试逻辑（子函数负责各自的状态持久化）
@@ -118,11 +233,25 @@ func (r *IntegrationTestReconciler) reconcileNormal(ctx context.Context, it *inf
 // SetupWithManager wires the controller.
 func (r *IntegrationTestReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	if r.Recorder == nil {
-		r.Recorder = mgr.GetEventRecorderFor("integrationtest")
+		r.Recorder = shared.NewManagerEventRecorder(mgr, "integrationtest")
 	}
+	if r.APIRemoval == nil {
+		removal, err := shared.NewAPIRemoval(mgr)
+		if err != nil {
+			return err
+		}
+		r.APIRemoval = removal
+	}
+	if r.RESTConfig == nil {
+		r.RESTConfig = mgr.GetConfig()
+	}
+	// 只有 spec、标签、注解变化与删除触发调和，status 写入后的推进由 Requeue 安排；
+	// 更新事件按 Debounce 合并
 	return ctrl.NewControllerManagedBy(mgr).
-		For(&infrav1alpha1.IntegrationTest{}).
 		Named("integrationtest").
+		Watches(&infrav1alpha1.IntegrationTest{},
+			shared.DebounceUpdates(&handler.EnqueueRequestForObject{}, r.Debounce),
+			builder.WithPredicates(shared.IgnoreSelfUpdates())).
 		Complete(r)
 }
 
@@ -137,5 +266,8 @@ func (r *IntegrationTestReconciler) ensureRegistry() {
 func (r *IntegrationTestReconciler) ensureResourceManager() {
 	if r.ResourceManager == nil {
 		r.ResourceManager = resource.NewManager(r.Client, r.Scheme, integrationTestFieldOwner, r.APIReader)
+		if r.RESTConfig != nil {
+			r.ResourceManager.Impersonator = resource.NewImpersonator(r.RESTConfig, r.Scheme, r.RESTMapper())
+		}
 	}
 }
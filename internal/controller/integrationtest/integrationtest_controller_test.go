@@ -2,16 +2,49 @@ package integrationtest
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
+	discoveryfake "k8s.io/client-go/discovery/fake"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	clienttesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/workqueue"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	infrav1alpha1 "github.com/lunz1207/testplane/api/v1alpha1"
+	infrav1alpha2 "github.com/lunz1207/testplane/api/v1alpha2"
+	"github.com/lunz1207/testplane/internal/builtins"
+	"github.com/lunz1207/testplane/internal/controller/shared"
+	"github.com/lunz1207/testplane/internal/controller/shared/metrics"
+	"github.com/lunz1207/testplane/internal/controller/shared/resource"
+	"github.com/lunz1207/testplane/internal/plugin"
+	"github.com/lunz1207/testplane/pkg/hooks"
 )
 
 var _ = Describe("IntegrationTest Controller", func() {
@@ -47,7 +80,7 @@ var _ = Describe("IntegrationTest Controller", func() {
 			controllerReconciler := &IntegrationTestReconciler{
 				Client:         fakeClient,
 				Scheme:         scheme,
-				PluginRegistry: nil, // Reconcile 中会初始化
+				PluginRegistry: plugin.NewRegistry(),
 			}
 
 			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
@@ -56,4 +89,2729 @@ var _ = Describe("IntegrationTest Controller", func() {
 			Expect(err).NotTo(HaveOccurred())
 		})
 	})
+
+	// 缓存尚未同步上一次 status 写入时，决策点以 APIReader 读取的最新状态为准，不得重复发送事件。
+	Context("When the cache is stale after a status write", func() {
+		ctx := context.Background()
+
+		var (
+			scheme   *runtime.Scheme
+			recorder *record.FakeRecorder
+		)
+
+		newIntegrationTest := func(status infrav1alpha1.IntegrationTestStatus) *infrav1alpha1.IntegrationTest {
+			return &infrav1alpha1.IntegrationTest{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:       "stale-cache",
+					Namespace:  "default",
+					Finalizers: []string{integrationTestFinalizer},
+				},
+				Spec: infrav1alpha1.IntegrationTestSpec{
+					Mode:  infrav1alpha1.IntegrationTestModeSequential,
+					Steps: []infrav1alpha1.TestStep{{Name: "create"}},
+				},
+				Status: status,
+			}
+		}
+
+		// newReconciler 构造缓存（Client）与 API Server（APIReader）状态不一致的 reconciler。
+		newReconciler := func(cached, latest *infrav1alpha1.IntegrationTest) *IntegrationTestReconciler {
+			return &IntegrationTestReconciler{
+				Client:         fake.NewClientBuilder().WithScheme(scheme).WithObjects(cached).Build(),
+				APIReader:      fake.NewClientBuilder().WithScheme(scheme).WithObjects(latest).Build(),
+				Scheme:         scheme,
+				PluginRegistry: plugin.NewRegistry(),
+				Recorder:       recorder,
+			}
+		}
+
+		BeforeEach(func() {
+			scheme = runtime.NewScheme()
+			Expect(infrav1alpha1.AddToScheme(scheme)).To(Succeed())
+			recorder = record.NewFakeRecorder(10)
+		})
+
+		It("should not emit IntegrationTestStarted twice", func() {
+			cached := newIntegrationTest(infrav1alpha1.IntegrationTestStatus{
+				Phase: infrav1alpha1.IntegrationTestPhasePending,
+			})
+			latest := newIntegrationTest(infrav1alpha1.IntegrationTestStatus{
+				Phase:        infrav1alpha1.IntegrationTestPhaseRunning,
+				CurrentRound: 1,
+			})
+			r := newReconciler(cached, latest)
+
+			result, err := r.executeTest(ctx, cached)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.Requeue).To(BeTrue())
+			Expect(recorder.Events).To(BeEmpty())
+		})
+
+		It("should not re-enter a step that already started", func() {
+			cached := newIntegrationTest(infrav1alpha1.IntegrationTestStatus{
+				Phase:        infrav1alpha1.IntegrationTestPhaseRunning,
+				CurrentRound: 1,
+			})
+			latest := newIntegrationTest(infrav1alpha1.IntegrationTestStatus{
+				Phase:        infrav1alpha1.IntegrationTestPhaseRunning,
+				CurrentRound: 1,
+				Steps:        []infrav1alpha1.StepStatus{{Name: "create", State: shared.StateRunning}},
+			})
+			r := newReconciler(cached, latest)
+
+			Expect(r.stepAlreadyStarted(ctx, cached, 0)).To(BeTrue())
+			Expect(r.stepAlreadyStarted(ctx, cached, 1)).To(BeFalse())
+		})
+
+		It("should not emit IntegrationTestSucceeded twice", func() {
+			now := metav1.Now()
+			cached := newIntegrationTest(infrav1alpha1.IntegrationTestStatus{
+				Phase:        infrav1alpha1.IntegrationTestPhaseRunning,
+				CurrentRound: 1,
+			})
+			latest := newIntegrationTest(infrav1alpha1.IntegrationTestStatus{
+				Phase:          infrav1alpha1.IntegrationTestPhaseSucceeded,
+				CurrentRound:   1,
+				CompletionTime: &now,
+			})
+			r := newReconciler(cached, latest)
+
+			_, err := r.finishTest(ctx, cached)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(recorder.Events).To(BeEmpty())
+		})
+
+		It("should not emit StepSucceeded twice", func() {
+			now := metav1.Now()
+			cached := newIntegrationTest(infrav1alpha1.IntegrationTestStatus{
+				Phase:        infrav1alpha1.IntegrationTestPhaseRunning,
+				CurrentRound: 1,
+				Steps:        []infrav1alpha1.StepStatus{{Name: "create", State: shared.StateRunning}},
+			})
+			latest := newIntegrationTest(infrav1alpha1.IntegrationTestStatus{
+				Phase:        infrav1alpha1.IntegrationTestPhaseRunning,
+				CurrentRound: 1,
+				Steps:        []infrav1alpha1.StepStatus{{Name: "create", State: shared.StateSucceeded, FinishedAt: &now}},
+			})
+			r := newReconciler(cached, latest)
+
+			Expect(r.stepAlreadyFinished(ctx, cached, 0)).To(BeTrue())
+			Expect(r.testAlreadyCompleted(ctx, cached)).To(BeFalse())
+		})
+
+		It("should fall back to the cached state without an APIReader", func() {
+			cached := newIntegrationTest(infrav1alpha1.IntegrationTestStatus{
+				Phase: infrav1alpha1.IntegrationTestPhasePending,
+			})
+			r := newReconciler(cached, cached)
+			r.APIReader = nil
+
+			Expect(r.phaseAlreadyAdvanced(ctx, cached)).To(BeFalse())
+			Expect(r.stepAlreadyStarted(ctx, cached, 0)).To(BeFalse())
+			Expect(r.testAlreadyCompleted(ctx, cached)).To(BeFalse())
+		})
+	})
+
+	// 轮次切换在内存中完成后一次提交；模拟 patch 之间崩溃（重试基于旧状态）与缓存延迟，结果必须一致。
+	Context("When transitioning between rounds", func() {
+		ctx := context.Background()
+
+		roundDone := func(round, completed int) infrav1alpha1.IntegrationTestStatus {
+			idx := 1
+			return infrav1alpha1.IntegrationTestStatus{
+				Phase:            infrav1alpha1.IntegrationTestPhaseRunning,
+				CurrentRound:     round,
+				CompletedRounds:  completed,
+				CurrentStepIndex: &idx,
+				Steps:            []infrav1alpha1.StepStatus{{Name: "create", State: shared.StateSucceeded}},
+			}
+		}
+
+		transition := func(status infrav1alpha1.IntegrationTestStatus) infrav1alpha1.IntegrationTestStatus {
+			completeRound(&status, 1)
+			advanceRound(&status)
+			return status
+		}
+
+		It("should commit completion and the next round together", func() {
+			next := transition(roundDone(2, 1))
+			Expect(next.CompletedRounds).To(Equal(2))
+			Expect(next.CurrentRound).To(Equal(3))
+			Expect(next.Steps).To(BeNil())
+			Expect(*next.CurrentStepIndex).To(Equal(0))
+		})
+
+		It("should not double-count when retried from the pre-crash state", func() {
+			before := roundDone(2, 1)
+			first := transition(before)
+			// 崩溃发生在 patch 之前：下一次 reconcile 仍基于旧状态重新切换
+			retried := transition(before)
+			Expect(retried).To(Equal(first))
+		})
+
+		It("should not skip a round when re-entered after the transition was committed", func() {
+			committed := transition(roundDone(2, 1))
+			again := transition(committed)
+			Expect(again.CurrentRound).To(Equal(3))
+			Expect(again.CompletedRounds).To(Equal(2))
+		})
+
+		It("should not complete the same round twice", func() {
+			status := roundDone(2, 1)
+			Expect(completeRound(&status, 1)).To(BeTrue())
+			Expect(completeRound(&status, 1)).To(BeFalse())
+			Expect(status.CompletedRounds).To(Equal(2))
+		})
+
+		It("should skip the transition when the API server already advanced the round", func() {
+			scheme := runtime.NewScheme()
+			Expect(infrav1alpha1.AddToScheme(scheme)).To(Succeed())
+			newIT := func(status infrav1alpha1.IntegrationTestStatus) *infrav1alpha1.IntegrationTest {
+				return &infrav1alpha1.IntegrationTest{
+					ObjectMeta: metav1.ObjectMeta{Name: "rounds", Namespace: "default"},
+					Spec: infrav1alpha1.IntegrationTestSpec{
+						Steps:  []infrav1alpha1.TestStep{{Name: "create"}},
+						Repeat: &infrav1alpha1.RepeatConfig{Count: 5},
+					},
+					Status: status,
+				}
+			}
+			cached := newIT(roundDone(2, 1))
+			latest := newIT(transition(roundDone(2, 1)))
+			r := &IntegrationTestReconciler{
+				Client:         fake.NewClientBuilder().WithScheme(scheme).WithObjects(cached).Build(),
+				APIReader:      fake.NewClientBuilder().WithScheme(scheme).WithObjects(latest).Build(),
+				Scheme:         scheme,
+				PluginRegistry: plugin.NewRegistry(),
+			}
+
+			result, err := r.startNextRound(ctx, cached)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.Requeue).To(BeTrue())
+			Expect(cached.Status.CompletedRounds).To(Equal(1))
+			Expect(cached.Status.CurrentRound).To(Equal(2))
+		})
+	})
+
+	Context("When asserting aggregated round statistics", func() {
+		ctx := context.Background()
+
+		timedRound := func(round int, seconds ...int) infrav1alpha1.IntegrationTestStatus {
+			base := time.Now().Add(-time.Hour)
+			status := infrav1alpha1.IntegrationTestStatus{Phase: infrav1alpha1.IntegrationTestPhaseRunning, CurrentRound: round, CompletedRounds: round - 1}
+			for i, s := range seconds {
+				started := metav1.NewTime(base)
+				base = base.Add(time.Duration(s) * time.Second)
+				finished := metav1.NewTime(base)
+				status.Steps = append(status.Steps, infrav1alpha1.StepStatus{Name: fmt.Sprintf("step-%d", i), StartedAt: &started, FinishedAt: &finished})
+			}
+			return status
+		}
+
+		It("should aggregate round and step durations", func() {
+			status := timedRound(1, 10, 30)
+			Expect(completeRound(&status, 2)).To(BeTrue())
+			next := timedRound(2, 5, 5)
+			next.Stats = status.Stats
+			Expect(completeRound(&next, 2)).To(BeTrue())
+
+			stats := next.Stats
+			Expect(stats.Rounds).To(Equal(2))
+			Expect(stats.MinRoundSeconds).To(Equal(int64(10)))
+			Expect(stats.MaxRoundSeconds).To(Equal(int64(40)))
+			Expect(stats.AvgRoundSeconds).To(Equal(int64(25)))
+			Expect(stats.LastRoundSeconds).To(Equal(int64(10)))
+			Expect(stats.MaxStepSeconds).To(Equal(int64(30)))
+			Expect(stats.SlowestStep).To(Equal("step-1"))
+		})
+
+		It("should report unmet stats expectations before succeeding", func() {
+			scheme := runtime.NewScheme()
+			Expect(infrav1alpha1.AddToScheme(scheme)).To(Succeed())
+			status := timedRound(1, 90)
+			completeRound(&status, 1)
+			it := &infrav1alpha1.IntegrationTest{
+				ObjectMeta: metav1.ObjectMeta{Name: "slow", Namespace: "default"},
+				Spec: infrav1alpha1.IntegrationTestSpec{
+					Steps: []infrav1alpha1.TestStep{{Name: "step-0"}},
+					StatsExpectations: &infrav1alpha1.StepCondition{AllOf: []infrav1alpha1.Expectation{
+						{Name: "round under a minute", Function: "FieldCompare", Params: runtime.RawExtension{
+							Raw: []byte(`{"path":"status.stats.maxRoundSeconds","operator":"<","value":60}`),
+						}},
+						{Function: "FieldCompare", Params: runtime.RawExtension{
+							Raw: []byte(`{"path":"status.stats.rounds","value":1}`),
+						}},
+					}},
+				},
+				Status: status,
+			}
+			registry := plugin.NewRegistry()
+			builtins.RegisterCommon(registry)
+			r := &IntegrationTestReconciler{Client: fake.NewClientBuilder().WithScheme(scheme).Build(), Scheme: scheme, PluginRegistry: registry}
+
+			msg, err := r.checkStatsExpectations(ctx, it)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(msg).To(Equal("stats expectations not satisfied: round under a minute"))
+
+			setTestFailed(&it.Status, ReasonStatsExpectationsFailed, msg)
+			Expect(it.Status.Phase).To(Equal(infrav1alpha1.IntegrationTestPhaseFailed))
+			Expect(it.Status.Reason).To(Equal(ReasonStatsExpectationsFailed))
+		})
+	})
+
+	Context("When resource budgets are annotated", func() {
+		It("should refuse to apply beyond the budget and fail with BudgetExceeded", func() {
+			scheme := runtime.NewScheme()
+			Expect(infrav1alpha1.AddToScheme(scheme)).To(Succeed())
+			it := &infrav1alpha1.IntegrationTest{ObjectMeta: metav1.ObjectMeta{
+				Name: "budgeted", Namespace: "default",
+				Annotations: map[string]string{
+					infrav1alpha1.AnnotationBudgetMaxPods: "4",
+					infrav1alpha1.AnnotationBudgetMaxCPU:  "2",
+				},
+			}}
+			budget, err := budgetFromAnnotations(it)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(budget).To(Equal(resource.Budget{MaxPods: 4, MaxCPUMillis: 2000}))
+
+			manifest, err := resource.ExpandSingleResourceRef(infrav1alpha1.ResourceRef{Manifest: runtime.RawExtension{Raw: []byte(`{
+				"apiVersion":"apps/v1","kind":"Deployment","metadata":{"name":"web"},
+				"spec":{"replicas":3,"template":{"spec":{"containers":[{"name":"c","resources":{"requests":{"cpu":"500m"}}}]}}}}`)}}, "default")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resource.ObjectCost(manifest.Object)).To(Equal(infrav1alpha1.BudgetUsage{Objects: 1, Pods: 3, CPUMillis: 1500}))
+
+			// 已用 1 个 Pod：再创建 3 副本未超 Pod 预算，但 CPU 超出
+			used := &infrav1alpha1.BudgetUsage{Objects: 1, Pods: 1, CPUMillis: 600}
+			m := resource.NewManager(fake.NewClientBuilder().WithScheme(scheme).Build(), scheme, integrationTestFieldOwner, nil)
+			err = m.ExecuteManifestWithinBudget(context.Background(), it, manifest, budget, used)
+			Expect(err).To(MatchError(resource.ErrBudgetExceeded))
+			Expect(used.Pods).To(Equal(int64(1)))
+
+			failed := &infrav1alpha1.IntegrationTest{}
+			setStepFailed(failed, &infrav1alpha1.StepStatus{}, "deploy", (&IntegrationTestReconciler{}).applyFailureReason(context.Background(), manifest, err), err.Error())
+			Expect(failed.Status.Reason).To(Equal(shared.ReasonBudgetExceeded))
+
+			it.Annotations[infrav1alpha1.AnnotationBudgetMaxObjects] = "many"
+			_, err = budgetFromAnnotations(it)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Context("When a step races concurrent applies", func() {
+		It("should record the outcome of every field owner", func() {
+			scheme := runtime.NewScheme()
+			Expect(infrav1alpha1.AddToScheme(scheme)).To(Succeed())
+			Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+
+			// 模拟 API Server：第 1 次尝试的字段管理者拥有 data.value，其余尝试写入不同的值而冲突
+			c := fake.NewClientBuilder().WithScheme(scheme).WithInterceptorFuncs(interceptor.Funcs{
+				Patch: func(_ context.Context, _ client.WithWatch, obj client.Object, _ client.Patch, opts ...client.PatchOption) error {
+					po := &client.PatchOptions{}
+					po.ApplyOptions(opts)
+					value, _, _ := unstructured.NestedString(obj.(*unstructured.Unstructured).Object, "data", "value")
+					if value != "1" {
+						return apierrors.NewConflict(schema.GroupResource{Resource: "configmaps"}, obj.GetName(),
+							fmt.Errorf("conflict with %q", integrationTestFieldOwner+"-race-1"))
+					}
+					if po.FieldManager != integrationTestFieldOwner+"-race-1" {
+						return fmt.Errorf("unexpected field manager %q", po.FieldManager)
+					}
+					return nil
+				},
+			}).Build()
+			r := &IntegrationTestReconciler{Client: c, Scheme: scheme}
+			r.ResourceManager = resource.NewManager(c, scheme, integrationTestFieldOwner, nil)
+
+			step := infrav1alpha1.TestStep{
+				Name: "race",
+				Race: &infrav1alpha1.RaceSpec{Attempts: 3},
+				Resource: &infrav1alpha1.ResourceRef{Manifest: runtime.RawExtension{
+					Raw: []byte(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"shared"},"data":{"value":"${RACE_ATTEMPT}"}}`),
+				}},
+			}
+			it := &infrav1alpha1.IntegrationTest{
+				ObjectMeta: metav1.ObjectMeta{Name: "race", Namespace: "default", UID: "race-uid"},
+				Spec:       infrav1alpha1.IntegrationTestSpec{Steps: []infrav1alpha1.TestStep{step}},
+			}
+			stepStatus := &infrav1alpha1.StepStatus{Name: "race"}
+			manifest, err := r.expandStepResource(it, step)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(r.applyStepResource(context.Background(), it, step, stepStatus, manifest)).To(Succeed())
+			Expect(stepStatus.RaceAttempts).To(HaveLen(3))
+			outcomes := []string{}
+			for _, attempt := range stepStatus.RaceAttempts {
+				outcomes = append(outcomes, attempt.Outcome)
+			}
+			Expect(outcomes).To(Equal([]string{RaceOutcomeApplied, RaceOutcomeConflict, RaceOutcomeConflict}))
+			Expect(stepStatus.RaceAttempts[1].FieldOwner).To(Equal(integrationTestFieldOwner + "-race-2"))
+
+			// 其他错误使步骤失败
+			result := raceAttemptResult(1, "owner", fmt.Errorf("forbidden"))
+			Expect(result.Outcome).To(Equal(RaceOutcomeError))
+		})
+	})
+
+	Context("When test annotations are propagated", func() {
+		It("should copy the selected annotations onto applied resources only", func() {
+			r := &IntegrationTestReconciler{}
+			it := &infrav1alpha1.IntegrationTest{
+				ObjectMeta: metav1.ObjectMeta{Name: "trace", Namespace: "default", Annotations: map[string]string{
+					"ci.example.com/pipeline-url": "https://ci.example.com/runs/42",
+					"ci.example.com/job":          "e2e",
+					"tickets.example.com/id":      "OPS-7",
+					"owner":                       "platform",
+				}},
+				Spec: infrav1alpha1.IntegrationTestSpec{PropagateAnnotations: []string{"ci.example.com/*", "tickets.example.com/id"}},
+			}
+			step := infrav1alpha1.TestStep{Name: "create", Resource: &infrav1alpha1.ResourceRef{Manifest: runtime.RawExtension{
+				Raw: []byte(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"app","annotations":{"ci.example.com/job":"manual"}}}`),
+			}}}
+			manifest, err := r.expandStepResource(it, step)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(manifest.Object.GetAnnotations()).To(Equal(map[string]string{
+				"ci.example.com/pipeline-url": "https://ci.example.com/runs/42",
+				"ci.example.com/job":          "manual", // 清单自身声明的注解优先
+				"tickets.example.com/id":      "OPS-7",
+			}))
+
+			step.Resource.Action = infrav1alpha1.TemplateActionDelete
+			manifest, err = r.expandStepResource(it, step)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(manifest.Object.GetAnnotations()).NotTo(HaveKey("tickets.example.com/id"))
+		})
+	})
+
+	Context("When a step keeps a bounded number of completed children", func() {
+		It("should delete the oldest finished children between rounds", func() {
+			scheme := runtime.NewScheme()
+			Expect(infrav1alpha1.AddToScheme(scheme)).To(Succeed())
+			Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+
+			cron := &batchv1.CronJob{ObjectMeta: metav1.ObjectMeta{Name: "probe", Namespace: "default", UID: "cron-uid"}}
+			owned := []metav1.OwnerReference{{APIVersion: "batch/v1", Kind: "CronJob", Name: "probe", UID: "cron-uid"}}
+			job := func(name string, age time.Duration, finished bool) *batchv1.Job {
+				j := &batchv1.Job{ObjectMeta: metav1.ObjectMeta{
+					Name: name, Namespace: "default", OwnerReferences: owned,
+					CreationTimestamp: metav1.NewTime(time.Now().Add(-age)),
+				}}
+				if finished {
+					j.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobComplete, Status: corev1.ConditionTrue}}
+				}
+				return j
+			}
+			keep := int32(1)
+			it := &infrav1alpha1.IntegrationTest{
+				ObjectMeta: metav1.ObjectMeta{Name: "retention", Namespace: "default"},
+				Spec: infrav1alpha1.IntegrationTestSpec{Steps: []infrav1alpha1.TestStep{{
+					Name:          "probe",
+					KeepCompleted: &keep,
+					Resource: &infrav1alpha1.ResourceRef{Manifest: runtime.RawExtension{
+						Raw: []byte(`{"apiVersion":"batch/v1","kind":"CronJob","metadata":{"name":"probe"}}`),
+					}},
+				}}},
+			}
+			c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(cron,
+				job("probe-1", 3*time.Hour, true), job("probe-2", 2*time.Hour, true),
+				job("probe-3", time.Hour, true), job("probe-4", time.Minute, false),
+			).Build()
+			r := &IntegrationTestReconciler{Client: c, Scheme: scheme}
+
+			r.pruneCompletedChildren(context.Background(), it)
+			var jobs batchv1.JobList
+			Expect(c.List(context.Background(), &jobs)).To(Succeed())
+			names := []string{}
+			for _, j := range jobs.Items {
+				names = append(names, j.Name)
+			}
+			Expect(names).To(ConsistOf("probe-3", "probe-4"))
+		})
+	})
+
+	Context("When running preflight checks", func() {
+		It("should report missing references, unknown selector kinds and unreachable webhooks", func() {
+			scheme := runtime.NewScheme()
+			Expect(infrav1alpha1.AddToScheme(scheme)).To(Succeed())
+			Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+
+			// 监听后立即关闭，得到一个不可达的地址
+			server := httptest.NewServer(http.NotFoundHandler())
+			closedURL := server.URL
+			server.Close()
+
+			it := &infrav1alpha1.IntegrationTest{
+				ObjectMeta: metav1.ObjectMeta{Name: "preflight", Namespace: "default"},
+				Spec: infrav1alpha1.IntegrationTestSpec{Steps: []infrav1alpha1.TestStep{
+					{Name: "config", Resource: &infrav1alpha1.ResourceRef{Manifest: runtime.RawExtension{
+						Raw: []byte(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"app-config"}}`),
+					}}},
+					{Name: "pod", Resource: &infrav1alpha1.ResourceRef{Manifest: runtime.RawExtension{
+						Raw: []byte(`{"apiVersion":"v1","kind":"Pod","metadata":{"name":"app"},"spec":{"containers":[{"name":"c",
+							"envFrom":[{"configMapRef":{"name":"app-config"}},{"secretRef":{"name":"app-creds"}},{"secretRef":{"name":"extra","optional":true}}],
+							"env":[{"name":"TOKEN","valueFrom":{"secretKeyRef":{"name":"present","key":"token"}}}]}]}}`),
+					}}},
+					{Name: "watch", Resource: &infrav1alpha1.ResourceRef{Selector: &infrav1alpha1.ResourceSelector{
+						APIVersion: "example.com/v1", Kind: "Widget", Name: "w",
+					}}, Expectations: &infrav1alpha1.StepCondition{AllOf: []infrav1alpha1.Expectation{
+						{Function: "Check", Webhook: closedURL},
+					}}},
+				}},
+			}
+			present := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "present", Namespace: "default"}}
+			c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(present).Build()
+			r := &IntegrationTestReconciler{Client: c, Scheme: scheme}
+
+			problems, err := r.preflight(context.Background(), it)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(problems).To(ContainSubstring("Secret default/app-creds not found"))
+			Expect(problems).To(ContainSubstring("selector kind example.com/v1/Widget not found"))
+			Expect(problems).To(ContainSubstring("webhook " + closedURL + " unreachable"))
+			Expect(problems).NotTo(ContainSubstring("app-config"))
+			Expect(problems).NotTo(ContainSubstring("extra"))
+			Expect(problems).NotTo(ContainSubstring("present"))
+		})
+
+		It("should reject manifests with unknown fields in strict schema mode", func() {
+			scheme := runtime.NewScheme()
+			Expect(infrav1alpha1.AddToScheme(scheme)).To(Succeed())
+			Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+
+			// 模拟 API Server 的严格字段校验：只接受 dry-run 请求，spec.replica 为未知字段
+			c := fake.NewClientBuilder().WithScheme(scheme).WithInterceptorFuncs(interceptor.Funcs{
+				Patch: func(_ context.Context, _ client.WithWatch, obj client.Object, _ client.Patch, opts ...client.PatchOption) error {
+					po := &client.PatchOptions{}
+					po.ApplyOptions(opts)
+					if len(po.DryRun) == 0 || po.FieldValidation != "Strict" {
+						return fmt.Errorf("expected a strict dry-run")
+					}
+					if _, found, _ := unstructured.NestedFieldNoCopy(obj.(*unstructured.Unstructured).Object, "spec", "replica"); found {
+						return apierrors.NewBadRequest(`.spec.replica: field not declared in schema`)
+					}
+					return nil
+				},
+			}).Build()
+			r := &IntegrationTestReconciler{Client: c, Scheme: scheme}
+			r.ResourceManager = resource.NewManager(c, scheme, integrationTestFieldOwner, nil)
+
+			it := &infrav1alpha1.IntegrationTest{
+				ObjectMeta: metav1.ObjectMeta{Name: "strict", Namespace: "default", UID: "strict-uid"},
+				Spec: infrav1alpha1.IntegrationTestSpec{StrictSchema: true, Steps: []infrav1alpha1.TestStep{
+					{Name: "typo", Resource: &infrav1alpha1.ResourceRef{Manifest: runtime.RawExtension{
+						Raw: []byte(`{"apiVersion":"apps/v1","kind":"Deployment","metadata":{"name":"web"},"spec":{"replica":3}}`),
+					}}},
+					{Name: "ok", Resource: &infrav1alpha1.ResourceRef{Manifest: runtime.RawExtension{
+						Raw: []byte(`{"apiVersion":"apps/v1","kind":"Deployment","metadata":{"name":"api"},"spec":{"replicas":3}}`),
+					}}},
+				}},
+			}
+			problems, err := r.preflight(context.Background(), it)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(problems).To(ContainSubstring("step typo: Deployment/web rejected by strict validation"))
+			Expect(problems).NotTo(ContainSubstring("step ok"))
+		})
+
+		It("should generate a minimal role and apply under the scoped identity", func() {
+			scheme := runtime.NewScheme()
+			Expect(infrav1alpha1.AddToScheme(scheme)).To(Succeed())
+			Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+			mapper := apimeta.NewDefaultRESTMapper(nil)
+			mapper.Add(corev1.SchemeGroupVersion.WithKind("ConfigMap"), apimeta.RESTScopeNamespace)
+			mapper.Add(corev1.SchemeGroupVersion.WithKind("Pod"), apimeta.RESTScopeNamespace)
+			mapper.Add(corev1.SchemeGroupVersion.WithKind("Namespace"), apimeta.RESTScopeRoot)
+			mapper.Add(appsv1.SchemeGroupVersion.WithKind("Deployment"), apimeta.RESTScopeNamespace)
+			c := fake.NewClientBuilder().WithScheme(scheme).WithRESTMapper(mapper).Build()
+			r := &IntegrationTestReconciler{Client: c, Scheme: scheme}
+
+			manifest := func(raw string) runtime.RawExtension { return runtime.RawExtension{Raw: []byte(raw)} }
+			it := &infrav1alpha1.IntegrationTest{
+				ObjectMeta: metav1.ObjectMeta{Name: "scoped", Namespace: "default", UID: "scoped-uid"},
+				Spec: infrav1alpha1.IntegrationTestSpec{ScopedRBAC: true, Steps: []infrav1alpha1.TestStep{
+					{Name: "config", Resource: &infrav1alpha1.ResourceRef{Manifest: manifest(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"app"}}`)}},
+					{Name: "cleanup", Resource: &infrav1alpha1.ResourceRef{Action: infrav1alpha1.TemplateActionDelete,
+						Manifest: manifest(`{"apiVersion":"apps/v1","kind":"Deployment","metadata":{"name":"old"}}`)}},
+					{Name: "watch", Resource: &infrav1alpha1.ResourceRef{Selector: &infrav1alpha1.ResourceSelector{APIVersion: "v1", Kind: "Pod"}}},
+				}},
+			}
+			rules, problems := r.scopedRBACRules(it)
+			Expect(problems).To(BeEmpty())
+			Expect(rules).To(Equal([]rbacv1.PolicyRule{
+				{APIGroups: []string{""}, Resources: []string{"configmaps"}, Verbs: []string{"create", "get", "list", "patch", "watch"}},
+				{APIGroups: []string{"apps"}, Resources: []string{"deployments"}, Verbs: []string{"delete", "get", "list", "watch"}},
+				{APIGroups: []string{""}, Resources: []string{"pods"}, Verbs: []string{"get", "list", "watch"}},
+			}))
+
+			Expect(r.ensureScopedRBAC(context.Background(), it)).To(Succeed())
+			Expect(it.Status.RunAs).To(Equal("system:serviceaccount:default:scoped-runner"))
+			var binding rbacv1.RoleBinding
+			Expect(c.Get(context.Background(), client.ObjectKey{Namespace: "default", Name: "scoped-runner"}, &binding)).To(Succeed())
+			Expect(binding.Subjects[0].Name).To(Equal("scoped-runner"))
+			Expect(binding.OwnerReferences[0].UID).To(Equal(it.UID))
+
+			// apply 以 status.runAs 的身份执行
+			var impersonated []string
+			r.ResourceManager = resource.NewManager(c, scheme, integrationTestFieldOwner, nil)
+			r.ResourceManager.Impersonator = func(username string) (client.Client, error) {
+				impersonated = append(impersonated, username)
+				return nil, errors.New("forbidden")
+			}
+			expanded, err := r.expandStepResource(it, it.Spec.Steps[0])
+			Expect(err).NotTo(HaveOccurred())
+			Expect(r.applyResource(context.Background(), it, expanded)).To(MatchError(ContainSubstring("forbidden")))
+			Expect(impersonated).To(Equal([]string{it.Status.RunAs}))
+
+			// 集群级资源不在测试命名空间的 Role 范围内
+			it.Spec.Steps = append(it.Spec.Steps, infrav1alpha1.TestStep{Name: "ns", Resource: &infrav1alpha1.ResourceRef{
+				Manifest: manifest(`{"apiVersion":"v1","kind":"Namespace","metadata":{"name":"extra"}}`)}})
+			message, err := r.preflight(context.Background(), it)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(message).To(ContainSubstring("step ns: scopedRBAC does not cover cluster-scoped Namespace/extra"))
+		})
+
+		It("should not adopt an existing identity it does not own", func() {
+			ctx := context.Background()
+			scheme := runtime.NewScheme()
+			Expect(infrav1alpha1.AddToScheme(scheme)).To(Succeed())
+			Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+			mapper := apimeta.NewDefaultRESTMapper(nil)
+			mapper.Add(corev1.SchemeGroupVersion.WithKind("ConfigMap"), apimeta.RESTScopeNamespace)
+			userRole := &rbacv1.Role{
+				ObjectMeta: metav1.ObjectMeta{Name: "taken-runner", Namespace: "default"},
+				Rules:      []rbacv1.PolicyRule{{APIGroups: []string{""}, Resources: []string{"secrets"}, Verbs: []string{"get"}}},
+			}
+			c := fake.NewClientBuilder().WithScheme(scheme).WithRESTMapper(mapper).WithObjects(userRole).Build()
+			r := &IntegrationTestReconciler{Client: c, Scheme: scheme}
+			it := &infrav1alpha1.IntegrationTest{
+				ObjectMeta: metav1.ObjectMeta{Name: "taken", Namespace: "default", UID: "taken-uid"},
+				Spec: infrav1alpha1.IntegrationTestSpec{ScopedRBAC: true, Steps: []infrav1alpha1.TestStep{
+					{Name: "config", Resource: &infrav1alpha1.ResourceRef{Manifest: runtime.RawExtension{
+						Raw: []byte(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"app"}}`)}}},
+				}},
+			}
+
+			message, err := r.preflight(ctx, it)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(message).To(Equal("scopedRBAC: Role taken-runner already exists and is not owned by this test"))
+
+			// 预检之后才出现的冲突同样不接管：用户 Role 的规则与 ownerRef 保持不变
+			Expect(errors.Is(r.ensureScopedRBAC(ctx, it), errScopedRBACConflict)).To(BeTrue())
+			var role rbacv1.Role
+			Expect(c.Get(ctx, client.ObjectKeyFromObject(userRole), &role)).To(Succeed())
+			Expect(role.Rules).To(Equal(userRole.Rules))
+			Expect(role.OwnerReferences).To(BeEmpty())
+		})
+
+		It("should keep long test names apart when truncating the identity name", func() {
+			long := strings.Repeat("a", 300)
+			first := scopedRBACName(&infrav1alpha1.IntegrationTest{ObjectMeta: metav1.ObjectMeta{Name: long + "-first"}})
+			second := scopedRBACName(&infrav1alpha1.IntegrationTest{ObjectMeta: metav1.ObjectMeta{Name: long + "-second"}})
+			Expect(first).To(HaveLen(253))
+			Expect(first).To(HaveSuffix("-runner"))
+			Expect(first).NotTo(Equal(second))
+			Expect(scopedRBACName(&infrav1alpha1.IntegrationTest{ObjectMeta: metav1.ObjectMeta{Name: "short"}})).To(Equal("short-runner"))
+		})
+	})
+
+	Context("When a parallel step waits for a selector", func() {
+		It("should persist diagnostics only when they change and return status write errors", func() {
+			ctx := context.Background()
+			scheme := runtime.NewScheme()
+			Expect(infrav1alpha1.AddToScheme(scheme)).To(Succeed())
+			Expect(corev1.AddToScheme(scheme)).To(Succeed())
+			patches := 0
+			var patchErr error
+			c := fake.NewClientBuilder().WithScheme(scheme).WithInterceptorFuncs(interceptor.Funcs{
+				SubResourcePatch: func(context.Context, client.Client, string, client.Object, client.Patch, ...client.SubResourcePatchOption) error {
+					patches++
+					return patchErr
+				},
+			}).Build()
+			r := &IntegrationTestReconciler{Client: c, Scheme: scheme, PluginRegistry: plugin.NewRegistry()}
+
+			step := infrav1alpha1.TestStep{Name: "watch", Resource: &infrav1alpha1.ResourceRef{Selector: &infrav1alpha1.ResourceSelector{
+				APIVersion: "v1", Kind: "Pod", LabelSelector: map[string]string{"app": "web"},
+			}}}
+			deadline := metav1.NewTime(time.Now().Add(time.Minute))
+			it := &infrav1alpha1.IntegrationTest{
+				ObjectMeta: metav1.ObjectMeta{Name: "parallel", Namespace: "default", UID: "parallel-uid"},
+				Spec:       infrav1alpha1.IntegrationTestSpec{Steps: []infrav1alpha1.TestStep{step}},
+				Status: infrav1alpha1.IntegrationTestStatus{
+					Phase: infrav1alpha1.IntegrationTestPhaseRunning,
+					Steps: []infrav1alpha1.StepStatus{{Name: "watch", State: shared.StatePending, Deadline: &deadline}},
+				},
+			}
+
+			result, passed, err := r.checkParallelStepExpectations(ctx, it, &it.Status.Steps[0], step, nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(passed).To(BeFalse())
+			Expect(result.RequeueAfter).To(Equal(defaultRequeue))
+			Expect(it.Status.Steps[0].SelectorDiagnostics).NotTo(BeNil())
+			Expect(patches).To(BeNumerically(">", 0))
+
+			// 诊断信息不变：等待期间的轮询不再写入
+			written := patches
+			_, _, err = r.checkParallelStepExpectations(ctx, it, &it.Status.Steps[0], step, nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(patches).To(Equal(written))
+
+			// 出现同 Kind 的候选资源后诊断变化，写入失败时返回错误
+			Expect(c.Create(ctx, &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "web-0", Namespace: "default",
+				Labels: map[string]string{"app": "api"}}})).To(Succeed())
+			patchErr = errors.New("apiserver unavailable")
+			_, _, err = r.checkParallelStepExpectations(ctx, it, &it.Status.Steps[0], step, nil)
+			Expect(err).To(MatchError(ContainSubstring("apiserver unavailable")))
+		})
+	})
+
+	Context("When emitting step events", func() {
+		It("should annotate events with machine-readable step results", func() {
+			it := &infrav1alpha1.IntegrationTest{ObjectMeta: metav1.ObjectMeta{Name: "annotated", Namespace: "default"}}
+			it.Status.CurrentRound = 3
+			it.Status.Steps = []infrav1alpha1.StepStatus{{
+				Name:    "deploy",
+				State:   shared.StateSucceeded,
+				Outputs: map[string]string{"kind": "Deployment", "name": "web"},
+				ExpectationResults: []infrav1alpha1.ExpectationResultSummary{
+					{Expect: "DeploymentReady", Passed: true}, {Expect: "FieldEquals", Passed: false},
+				},
+			}}
+
+			Expect(eventAnnotations(it, 0)).To(Equal(map[string]string{
+				shared.AnnotationEventRound:              "3",
+				shared.AnnotationEventStep:               "deploy",
+				shared.AnnotationEventOutputs:            `{"kind":"Deployment","name":"web"}`,
+				shared.AnnotationEventExpectationsPassed: "1",
+				shared.AnnotationEventExpectationsTotal:  "2",
+			}))
+			Expect(eventAnnotations(it, -1)).To(Equal(map[string]string{shared.AnnotationEventRound: "3"}))
+		})
+	})
+
+	Context("When phase hooks are registered", func() {
+		It("should notify step and test transitions once", func() {
+			recorder := &recordingHook{}
+			registry := hooks.NewRegistry()
+			Expect(registry.Register(recorder)).To(Succeed())
+			Expect(registry.Register(struct{}{})).NotTo(Succeed())
+			r := &IntegrationTestReconciler{Hooks: registry}
+
+			it := &infrav1alpha1.IntegrationTest{ObjectMeta: metav1.ObjectMeta{Name: "hooked", Namespace: "default"}}
+			it.Status = infrav1alpha1.IntegrationTestStatus{
+				CurrentRound: 1,
+				Phase:        infrav1alpha1.IntegrationTestPhaseRunning,
+				Steps:        []infrav1alpha1.StepStatus{{Name: "a", State: shared.StateRunning}},
+			}
+			previous := it.Status.DeepCopy()
+
+			// a 结束、b 开始
+			it.Status.Steps[0].State = shared.StateSucceeded
+			it.Status.Steps = append(it.Status.Steps, infrav1alpha1.StepStatus{Name: "b", Index: 1, State: shared.StateRunning})
+			r.dispatchHooks(context.Background(), it, previous)
+			Expect(recorder.calls).To(Equal([]string{"finish:a", "start:b"}))
+
+			// 重复调和状态未变化时不再通知
+			r.dispatchHooks(context.Background(), it, it.Status.DeepCopy())
+			Expect(recorder.calls).To(HaveLen(2))
+
+			previous = it.Status.DeepCopy()
+			it.Status.Phase = infrav1alpha1.IntegrationTestPhaseFailed
+			r.dispatchHooks(context.Background(), it, previous)
+			Expect(recorder.calls).To(Equal([]string{"finish:a", "start:b", "test:Failed"}))
+		})
+	})
+
+	Context("When a step is periodic", func() {
+		step := infrav1alpha1.TestStep{Name: "rotate", PeriodicSeconds: 30}
+
+		It("should schedule iterations from the last start time", func() {
+			finished := metav1.NewTime(time.Now().Add(-40 * time.Second))
+			stepStatus := &infrav1alpha1.StepStatus{Name: "rotate", State: shared.StateSucceeded, FinishedAt: &finished}
+			Expect(periodicIterationDue(stepStatus, step, time.Now())).To(BeTrue())
+
+			started := metav1.NewTime(time.Now().Add(-10 * time.Second))
+			appendIteration(stepStatus, infrav1alpha1.StepIteration{Iteration: 1, State: shared.StateSucceeded, StartedAt: &started})
+			Expect(periodicIterationDue(stepStatus, step, time.Now())).To(BeFalse())
+			Expect(periodicIterationDue(stepStatus, step, time.Now().Add(20*time.Second))).To(BeTrue())
+		})
+
+		It("should keep a bounded iteration history", func() {
+			stepStatus := &infrav1alpha1.StepStatus{Name: "rotate"}
+			for i := int32(1); i <= maxStepIterations+3; i++ {
+				appendIteration(stepStatus, infrav1alpha1.StepIteration{Iteration: i})
+			}
+			Expect(stepStatus.Iterations).To(HaveLen(maxStepIterations))
+			Expect(stepStatus.Iterations[0].Iteration).To(Equal(int32(4)))
+			Expect(lastIteration(stepStatus).Iteration).To(Equal(int32(maxStepIterations + 3)))
+		})
+	})
+
+	Context("When parallel steps declare a barrier", func() {
+		steps := []infrav1alpha1.TestStep{{Name: "a"}, {Name: "b"}, {Name: "gate", Barrier: true}, {Name: "c"}}
+		status := func(states ...string) *infrav1alpha1.IntegrationTestStatus {
+			st := &infrav1alpha1.IntegrationTestStatus{}
+			for i, state := range states {
+				st.Steps = append(st.Steps, infrav1alpha1.StepStatus{Name: steps[i].Name, Index: i, State: state})
+			}
+			return st
+		}
+
+		It("should hold the barrier until previous steps succeed", func() {
+			Expect(parallelWindow(steps, status())).To(Equal(2))
+			Expect(parallelWindow(steps, status(shared.StateSucceeded, shared.StateRunning))).To(Equal(2))
+		})
+
+		It("should hold later steps until the barrier succeeds", func() {
+			Expect(parallelWindow(steps, status(shared.StateSucceeded, shared.StateSucceeded))).To(Equal(3))
+			Expect(parallelWindow(steps, status(shared.StateSucceeded, shared.StateSucceeded, shared.StateRunning))).To(Equal(3))
+			Expect(parallelWindow(steps, status(shared.StateSucceeded, shared.StateSucceeded, shared.StateSucceeded))).To(Equal(4))
+		})
+	})
+
+	Context("When rounds are limited to a time window", func() {
+		It("should only open the window between the cron time and its duration", func() {
+			schedule := &infrav1alpha1.RepeatSchedule{Cron: "0 1 * * 1-5", WindowSeconds: 7200}
+			// 2025-01-06 为周一
+			inWindow := time.Date(2025, 1, 6, 2, 30, 0, 0, time.UTC)
+			open, start, err := windowState(schedule, inWindow)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(open).To(BeTrue())
+			Expect(start).To(Equal(time.Date(2025, 1, 6, 1, 0, 0, 0, time.UTC)))
+
+			afterWindow := time.Date(2025, 1, 6, 3, 30, 0, 0, time.UTC)
+			open, next, err := windowState(schedule, afterWindow)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(open).To(BeFalse())
+			Expect(next).To(Equal(time.Date(2025, 1, 7, 1, 0, 0, 0, time.UTC)))
+
+			// 周五窗口之后，下一个窗口为周一
+			open, next, err = windowState(schedule, time.Date(2025, 1, 10, 12, 0, 0, 0, time.UTC))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(open).To(BeFalse())
+			Expect(next).To(Equal(time.Date(2025, 1, 13, 1, 0, 0, 0, time.UTC)))
+		})
+
+		It("should reject malformed cron expressions", func() {
+			for _, expr := range []string{"* * *", "60 * * * *", "*/0 * * * *", "5-1 * * * *"} {
+				_, err := shared.ParseCron(expr)
+				Expect(err).To(HaveOccurred(), expr)
+			}
+		})
+
+		It("should hold new rounds until the gate opens", func() {
+			scheme := runtime.NewScheme()
+			Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+			cm := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "pipeline", Namespace: "default"},
+				Data:       map[string]string{"fresh": "false"},
+			}
+			c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(cm).Build()
+			r := &IntegrationTestReconciler{Client: c, Scheme: scheme}
+			ctx := context.Background()
+
+			gate := &infrav1alpha1.RoundGate{ConfigMapKey: &infrav1alpha1.ConfigMapKeyReference{Name: "pipeline", Key: "fresh"}}
+			open, detail, err := r.gateOpen(ctx, "default", gate)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(open).To(BeFalse())
+			Expect(detail).To(ContainSubstring(`key fresh is "false"`))
+
+			cm.Data["fresh"] = "true"
+			Expect(c.Update(ctx, cm)).To(Succeed())
+			open, _, err = r.gateOpen(ctx, "default", gate)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(open).To(BeTrue())
+
+			status := http.StatusServiceUnavailable
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(status)
+			}))
+			defer server.Close()
+			webhookGate := &infrav1alpha1.RoundGate{Webhook: server.URL}
+			open, detail, err = r.gateOpen(ctx, "default", webhookGate)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(open).To(BeFalse())
+			Expect(detail).To(Equal("webhook returned 503"))
+			status = http.StatusOK
+			open, _, _ = r.gateOpen(ctx, "default", webhookGate)
+			Expect(open).To(BeTrue())
+
+			_, _, err = r.gateOpen(ctx, "default", &infrav1alpha1.RoundGate{})
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should start rounds on ratePerMinute slots and skip overrun slots", func() {
+			start := time.Date(2025, 1, 6, 1, 0, 0, 0, time.UTC)
+			it := &infrav1alpha1.IntegrationTest{
+				Status: infrav1alpha1.IntegrationTestStatus{StartTime: &metav1.Time{Time: start}, CurrentRound: 3},
+			}
+			// 每分钟 4 轮：第 3 轮的时间槽为开始后 30s
+			interval := time.Minute / 4
+			Expect(rateSlot(it, interval)).To(Equal(start.Add(30 * time.Second)))
+			Expect(skipMissedSlots(it, interval, start.Add(30*time.Second+500*time.Millisecond))).To(BeZero())
+
+			// 上一轮超时到 70s：错过 30s、45s、60s 三个槽，下一轮在 75s 开始
+			Expect(skipMissedSlots(it, interval, start.Add(70*time.Second))).To(Equal(3))
+			Expect(it.Status.SkippedRounds).To(Equal(3))
+			Expect(rateSlot(it, interval)).To(Equal(start.Add(75 * time.Second)))
+		})
+	})
+
+	Context("When the test references an Environment", func() {
+		It("should expand environment references in the spec", func() {
+			env := &infrav1alpha1.Environment{
+				ObjectMeta: metav1.ObjectMeta{Name: "staging", Namespace: "default"},
+				Spec: infrav1alpha1.EnvironmentSpec{
+					BaseURLs:         map[string]string{"api": "http://api.staging:8080"},
+					DefaultNamespace: "staging",
+					Variables:        map[string]string{"region": "cn-north-1"},
+				},
+			}
+			spec := infrav1alpha1.IntegrationTestSpec{
+				Steps: []infrav1alpha1.TestStep{{
+					Name: "create",
+					Resource: &infrav1alpha1.ResourceRef{Manifest: runtime.RawExtension{
+						Raw: []byte(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"cm"},"data":{"region":"${env.region}","ns":"${env.namespace}"}}`),
+					}},
+					Expectations: &infrav1alpha1.StepCondition{AllOf: []infrav1alpha1.Expectation{{
+						Function: "Healthy",
+						Webhook:  "${env.baseURLs.api}/check",
+					}}},
+				}},
+			}
+			Expect(shared.ExpandEnvironment(env, &spec)).To(Succeed())
+			Expect(string(spec.Steps[0].Resource.Manifest.Raw)).To(ContainSubstring(`"region":"cn-north-1"`))
+			Expect(string(spec.Steps[0].Resource.Manifest.Raw)).To(ContainSubstring(`"ns":"staging"`))
+			Expect(spec.Steps[0].Expectations.AllOf[0].Webhook).To(Equal("http://api.staging:8080/check"))
+
+			spec.Steps[0].Expectations.AllOf[0].Webhook = "${env.baseURLs.missing}"
+			Expect(shared.ExpandEnvironment(env, &spec)).To(MatchError(ContainSubstring("baseURLs.missing")))
+		})
+	})
+
+	Context("When expectation params reference variables and step outputs", func() {
+		It("should resolve references before calling the function", func() {
+			it := &infrav1alpha1.IntegrationTest{
+				Spec: infrav1alpha1.IntegrationTestSpec{
+					Variables: []infrav1alpha1.TestVariable{{Name: "nodes", Value: "3"}},
+				},
+				Status: infrav1alpha1.IntegrationTestStatus{
+					Steps: []infrav1alpha1.StepStatus{{
+						Name:    "create-pool",
+						Outputs: stepOutputs(map[string]interface{}{"v1/ConfigMap/pool-x7k": map[string]interface{}{"kind": "ConfigMap", "metadata": map[string]interface{}{"name": "pool-x7k", "namespace": "default"}}}),
+					}},
+				},
+			}
+			params := runtime.RawExtension{Raw: []byte(`{"count":"${vars.nodes}","owner":"pool/${steps.create-pool.outputs.name}"}`)}
+			expanded, err := shared.ExpandParams(params, paramValues(it))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(expanded.Raw).To(MatchJSON(`{"count":3,"owner":"pool/pool-x7k"}`))
+
+			_, err = shared.ExpandParams(runtime.RawExtension{Raw: []byte(`{"count":"${vars.missing}"}`)}, paramValues(it))
+			Expect(err).To(MatchError(ContainSubstring("vars.missing")))
+		})
+
+		It("should resolve valueFrom from another test's status and retry until it is available", func() {
+			scheme := runtime.NewScheme()
+			Expect(infrav1alpha1.AddToScheme(scheme)).To(Succeed())
+			provision := &infrav1alpha1.IntegrationTest{
+				ObjectMeta: metav1.ObjectMeta{Name: "provision", Namespace: "default"},
+				Status: infrav1alpha1.IntegrationTestStatus{
+					Steps: []infrav1alpha1.StepStatus{{Name: "create-cluster", Outputs: map[string]string{"name": "cluster-9f2"}}},
+				},
+			}
+			r := &IntegrationTestReconciler{Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(provision).Build()}
+			it := &infrav1alpha1.IntegrationTest{
+				ObjectMeta: metav1.ObjectMeta{Name: "upgrade", Namespace: "default"},
+				Spec: infrav1alpha1.IntegrationTestSpec{
+					Variables: []infrav1alpha1.TestVariable{
+						{Name: "cluster", ValueFrom: &infrav1alpha1.VariableSource{TestRef: &infrav1alpha1.TestFieldReference{
+							Name: "provision", JSONPath: ".status.steps[0].outputs.name",
+						}}},
+						{Name: "pending", ValueFrom: &infrav1alpha1.VariableSource{TestRef: &infrav1alpha1.TestFieldReference{
+							Name: "teardown", JSONPath: ".status.phase",
+						}}},
+					},
+				},
+			}
+
+			values := paramValues(it)
+			unresolved := r.resolveVariableSources(context.Background(), it, values)
+			Expect(values).To(HaveKeyWithValue("vars.cluster", "cluster-9f2"))
+			Expect(unresolved).To(HaveKey("vars.pending"))
+
+			registry := plugin.NewRegistry()
+			registry.Register("Always", func(_, _ map[string]interface{}) plugin.Result { return plugin.Pass() })
+			runner := shared.NewExpectationRunner(registry).WithParamValues(values).WithUnresolvedParams(unresolved)
+			results, err := runner.RunStepCondition(&infrav1alpha1.StepCondition{AllOf: []infrav1alpha1.Expectation{
+				{Function: "Always", Params: runtime.RawExtension{Raw: []byte(`{"cluster":"${vars.cluster}"}`)}},
+				{Function: "Always", Params: runtime.RawExtension{Raw: []byte(`{"phase":"${vars.pending}"}`)}},
+			}}, nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(results.AllOf[0].Passed).To(BeTrue(), results.AllOf[0].Message)
+			Expect(results.AllOf[1].Error).To(BeTrue())
+			Expect(results.AllOf[1].Message).To(ContainSubstring("teardown"))
+		})
+	})
+
+	Context("When an expectation targets a resource alias", func() {
+		It("should assert the aliased resource instead of the step resource", func() {
+			registry := plugin.NewRegistry()
+			registry.Register("NameIs", func(res, params map[string]interface{}) plugin.Result {
+				if plugin.GetNestedString(res, "metadata.name") == plugin.GetString(params, "name") {
+					return plugin.Pass()
+				}
+				return plugin.Fail("name mismatch")
+			})
+			runner := shared.NewExpectationRunner(registry).WithResourceResolver(func(alias string) (map[string]interface{}, error) {
+				if alias != "db" {
+					return nil, fmt.Errorf("unknown resource alias %q", alias)
+				}
+				return map[string]interface{}{"kind": "StatefulSet", "metadata": map[string]interface{}{"name": "db-7f9c"}}, nil
+			})
+			state := map[string]interface{}{"v1/ConfigMap/app": map[string]interface{}{"kind": "ConfigMap", "metadata": map[string]interface{}{"name": "app"}}}
+
+			results, err := runner.RunStepCondition(&infrav1alpha1.StepCondition{AllOf: []infrav1alpha1.Expectation{
+				{Function: "NameIs", Resource: "db", Params: runtime.RawExtension{Raw: []byte(`{"name":"db-7f9c"}`)}},
+				{Function: "NameIs", Resource: "v1/ConfigMap/app", Params: runtime.RawExtension{Raw: []byte(`{"name":"app"}`)}},
+			}}, state)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(results.Passed()).To(BeTrue())
+
+			_, err = runner.RunStepCondition(&infrav1alpha1.StepCondition{AllOf: []infrav1alpha1.Expectation{
+				{Function: "NameIs", Resource: "cache"},
+			}}, state)
+			Expect(err).To(MatchError(ContainSubstring(`unknown resource alias "cache"`)))
+		})
+
+		It("should assert the tombstone of a deleted resource", func() {
+			ctx := context.Background()
+			scheme := runtime.NewScheme()
+			Expect(corev1.AddToScheme(scheme)).To(Succeed())
+			Expect(infrav1alpha1.AddToScheme(scheme)).To(Succeed())
+			volumes := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "volumes", Namespace: "default"},
+				Data:       map[string]string{"attached": "0"},
+			}
+			c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(volumes).Build()
+			registry := plugin.NewRegistry()
+			builtins.RegisterData(registry)
+			r := &IntegrationTestReconciler{Client: c, APIReader: c, Scheme: scheme, PluginRegistry: registry}
+			r.ResourceManager = resource.NewManager(c, scheme, integrationTestFieldOwner, c)
+
+			step := infrav1alpha1.TestStep{
+				Name: "delete-volumes",
+				Resource: &infrav1alpha1.ResourceRef{
+					Action:   infrav1alpha1.TemplateActionDelete,
+					Manifest: runtime.RawExtension{Raw: []byte(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"volumes"}}`)},
+				},
+				Expectations: &infrav1alpha1.StepCondition{AllOf: []infrav1alpha1.Expectation{
+					{Function: "DataEquals", Tombstone: true, Params: runtime.RawExtension{Raw: []byte(`{"key":"attached","value":"0"}`)}},
+				}},
+			}
+			it := &infrav1alpha1.IntegrationTest{
+				ObjectMeta: metav1.ObjectMeta{Name: "tombstone", Namespace: "default"},
+				Spec:       infrav1alpha1.IntegrationTestSpec{Steps: []infrav1alpha1.TestStep{step}},
+			}
+			manifest, err := r.expandStepResource(it, step)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(manifest.Tombstone).To(BeTrue())
+			Expect(r.applyResource(ctx, it, manifest)).To(Succeed())
+
+			state, err := r.gatherResourceState(ctx, it, manifest)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(state).To(HaveKeyWithValue("v1/ConfigMap/volumes", BeEmpty()))
+			results, err := r.runExpectations(ctx, it, step.Expectations, state, nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(results.Passed()).To(BeTrue())
+
+			// 测试结束后墓碑被清除（与控制器重启后相同）：记为无法判定的错误结果而非未通过
+			r.forgetResourceSnapshots(it)
+			results, err = r.runExpectations(ctx, it, step.Expectations, state, nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(results.AllOf[0].Error).To(BeTrue())
+			Expect(results.AllOf[0].Message).To(ContainSubstring("no tombstone recorded"))
+		})
+
+		It("should compare fields before and after an update step", func() {
+			ctx := context.Background()
+			scheme := runtime.NewScheme()
+			Expect(corev1.AddToScheme(scheme)).To(Succeed())
+			Expect(infrav1alpha1.AddToScheme(scheme)).To(Succeed())
+			cluster := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "cluster", Namespace: "default"},
+				Data:       map[string]string{"clusterID": "c-1", "vip": "10.0.0.1", "version": "1"},
+			}
+			// 模拟升级：apply 只改变 version
+			c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(cluster).WithInterceptorFuncs(interceptor.Funcs{
+				Patch: func(ctx context.Context, cl client.WithWatch, obj client.Object, _ client.Patch, _ ...client.PatchOption) error {
+					existing := &corev1.ConfigMap{}
+					if err := cl.Get(ctx, client.ObjectKeyFromObject(obj), existing); err != nil {
+						return err
+					}
+					existing.Data["version"] = "2"
+					return cl.Update(ctx, existing)
+				},
+			}).Build()
+			registry := plugin.NewRegistry()
+			builtins.RegisterCommon(registry)
+			r := &IntegrationTestReconciler{Client: c, APIReader: c, Scheme: scheme, PluginRegistry: registry}
+			r.ResourceManager = resource.NewManager(c, scheme, integrationTestFieldOwner, c)
+
+			step := infrav1alpha1.TestStep{
+				Name: "upgrade",
+				Resource: &infrav1alpha1.ResourceRef{
+					Manifest: runtime.RawExtension{Raw: []byte(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"cluster"},"data":{"version":"2"}}`)},
+				},
+				Expectations: &infrav1alpha1.StepCondition{AllOf: []infrav1alpha1.Expectation{
+					{Function: "UnchangedFields", Params: runtime.RawExtension{Raw: []byte(`{"paths":["data.clusterID","data.vip","data.zone"]}`)}},
+					{Function: "UnchangedFields", Fields: []string{"data.version"}, Params: runtime.RawExtension{Raw: []byte(`{"paths":["data.version"]}`)}},
+				}},
+			}
+			it := &infrav1alpha1.IntegrationTest{
+				ObjectMeta: metav1.ObjectMeta{Name: "upgrade", Namespace: "default"},
+				Spec:       infrav1alpha1.IntegrationTestSpec{Steps: []infrav1alpha1.TestStep{step}},
+			}
+			manifest, err := r.expandStepResource(it, step)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(manifest.Snapshot).To(BeTrue())
+			Expect(r.applyResource(ctx, it, manifest)).To(Succeed())
+
+			state, err := r.gatherResourceState(ctx, it, manifest)
+			Expect(err).NotTo(HaveOccurred())
+			results, err := r.runExpectations(ctx, it, step.Expectations, state, nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(results.AllOf[0].Passed).To(BeTrue())
+			Expect(results.AllOf[1].Passed).To(BeFalse())
+			Expect(results.AllOf[1].Actual).To(Equal("data.version: 1 -> 2"))
+
+			// 快照丢失（控制器重启）时无法判定
+			lost := builtins.UnchangedFields(map[string]interface{}{"data": map[string]interface{}{}}, map[string]interface{}{"paths": []interface{}{"data.clusterID"}})
+			Expect(lost.Passed).To(BeFalse())
+			Expect(lost.Error).To(BeTrue())
+		})
+
+		It("should compare fields across two resources", func() {
+			registry := plugin.NewRegistry()
+			builtins.RegisterCommon(registry)
+			runner := shared.NewExpectationRunner(registry).WithResourceResolver(func(alias string) (map[string]interface{}, error) {
+				return map[string]interface{}{"kind": "Cluster", "status": map[string]interface{}{"nodeCount": int64(3)}}, nil
+			})
+			state := map[string]interface{}{
+				"v1/Service/web": map[string]interface{}{"kind": "Service", "spec": map[string]interface{}{
+					"ports": []interface{}{map[string]interface{}{"targetPort": int64(8080)}},
+				}},
+				"apps/v1/Deployment/web": map[string]interface{}{"kind": "Deployment", "spec": map[string]interface{}{"template": map[string]interface{}{"spec": map[string]interface{}{
+					"containers": []interface{}{map[string]interface{}{"ports": []interface{}{map[string]interface{}{"containerPort": float64(8080)}}}},
+				}}}},
+				"v1/ConfigMap/nodes": map[string]interface{}{"kind": "ConfigMap", "items": []interface{}{"n1", "n2"}},
+			}
+
+			results, err := runner.RunStepCondition(&infrav1alpha1.StepCondition{AllOf: []infrav1alpha1.Expectation{
+				{Function: "FieldsMatchAcrossResources", Params: runtime.RawExtension{Raw: []byte(`{"left":"v1/Service/web","leftPath":"spec.ports[0].targetPort","right":"apps/v1/Deployment/web","rightPath":"spec.template.spec.containers[0].ports[0].containerPort"}`)}},
+				{Function: "FieldsMatchAcrossResources", Params: runtime.RawExtension{Raw: []byte(`{"left":"cluster","leftPath":"status.nodeCount","right":"v1/ConfigMap/nodes","rightPath":"items","operator":">="}`)}},
+				{Function: "FieldsMatchAcrossResources", Params: runtime.RawExtension{Raw: []byte(`{"left":"cluster","leftPath":"status.nodeCount","right":"v1/ConfigMap/nodes","rightPath":"items"}`)}},
+			}}, state)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(results.AllOf[0].Passed).To(BeTrue(), results.AllOf[0].Message)
+			Expect(results.AllOf[1].Passed).To(BeTrue())
+			Expect(results.AllOf[2].Passed).To(BeFalse())
+			Expect(results.AllOf[2].Actual).To(Equal("3 == 2"))
+		})
+	})
+
+	Context("When a failed test is triaged", func() {
+		ctx := context.Background()
+
+		newFailedTest := func(webhook string, triage *infrav1alpha1.TriageStatus) *infrav1alpha1.IntegrationTest {
+			return &infrav1alpha1.IntegrationTest{
+				ObjectMeta: metav1.ObjectMeta{Name: "triaged", Namespace: "default"},
+				Spec: infrav1alpha1.IntegrationTestSpec{
+					Steps:  []infrav1alpha1.TestStep{{Name: "create"}},
+					Triage: &infrav1alpha1.TriageSpec{Webhook: webhook},
+				},
+				Status: infrav1alpha1.IntegrationTestStatus{
+					Phase:  infrav1alpha1.IntegrationTestPhaseFailed,
+					Reason: "StepFailed",
+					Steps: []infrav1alpha1.StepStatus{{
+						Name:  "create",
+						State: shared.StateFailed,
+						ExpectationResults: []infrav1alpha1.ExpectationResultSummary{
+							{Expect: "PodReady", Passed: false, Message: "pod not ready"},
+							{Expect: "Exists", Passed: true},
+						},
+					}},
+					Triage: triage,
+				},
+			}
+		}
+
+		It("should send the failure context and record the response", func() {
+			var got shared.TriageRequest
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				Expect(json.NewDecoder(req.Body).Decode(&got)).To(Succeed())
+				_, _ = w.Write([]byte(`{"knownIssue":true,"ticket":"BUG-42"}`))
+			}))
+			defer server.Close()
+
+			it := newFailedTest(server.URL, nil)
+			triage := shared.RunTriage(ctx, it.Spec.Triage, nil, buildTriageRequest(ctx, it))
+			Expect(triage.State).To(Equal(infrav1alpha1.TriageStateSucceeded))
+			Expect(triage.Attempts).To(Equal(int32(1)))
+			Expect(triage.KnownIssue).To(BeTrue())
+			Expect(triage.Ticket).To(Equal("BUG-42"))
+			Expect(got.FailedStep.Name).To(Equal("create"))
+			Expect(got.Expectations).To(HaveLen(1))
+			Expect(got.Expectations[0].Expect).To(Equal("PodReady"))
+		})
+
+		It("should not call the webhook again when the cache is stale", func() {
+			calls := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				calls++
+			}))
+			defer server.Close()
+
+			scheme := runtime.NewScheme()
+			Expect(infrav1alpha1.AddToScheme(scheme)).To(Succeed())
+			cached := newFailedTest(server.URL, nil)
+			latest := newFailedTest(server.URL, &infrav1alpha1.TriageStatus{State: infrav1alpha1.TriageStateSucceeded, Attempts: 1})
+			r := &IntegrationTestReconciler{
+				Client:    fake.NewClientBuilder().WithScheme(scheme).WithObjects(cached).Build(),
+				APIReader: fake.NewClientBuilder().WithScheme(scheme).WithObjects(latest).Build(),
+				Scheme:    scheme,
+				Recorder:  record.NewFakeRecorder(10),
+			}
+
+			result, err := r.triageFailure(ctx, cached)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.Requeue).To(BeTrue())
+			Expect(calls).To(BeZero())
+		})
+	})
+
+	Context("When converting between API versions", func() {
+		It("should round-trip readyCondition through v1alpha2 ready", func() {
+			src := &infrav1alpha1.IntegrationTest{
+				ObjectMeta: metav1.ObjectMeta{Name: "convert", Namespace: "default"},
+				Spec: infrav1alpha1.IntegrationTestSpec{
+					Mode: infrav1alpha1.IntegrationTestModeSequential,
+					Steps: []infrav1alpha1.TestStep{{
+						Name: "create",
+						ReadyCondition: &infrav1alpha1.StepCondition{
+							TimeoutSeconds: 5,
+							AllOf:          []infrav1alpha1.Expectation{{Function: "PodReady"}},
+						},
+					}},
+				},
+				Status: infrav1alpha1.IntegrationTestStatus{Phase: infrav1alpha1.IntegrationTestPhaseRunning},
+			}
+
+			v2 := &infrav1alpha2.IntegrationTest{}
+			Expect(v2.ConvertFrom(src)).To(Succeed())
+			Expect(v2.Spec.Steps).To(HaveLen(1))
+			Expect(v2.Spec.Steps[0].Ready).NotTo(BeNil())
+			Expect(v2.Spec.Steps[0].Ready.AllOf[0].Function).To(Equal("PodReady"))
+			Expect(v2.Status.Phase).To(Equal(infrav1alpha1.IntegrationTestPhaseRunning))
+
+			back := &infrav1alpha1.IntegrationTest{}
+			Expect(v2.ConvertTo(back)).To(Succeed())
+			Expect(back.Spec).To(Equal(src.Spec))
+			Expect(back.Status).To(Equal(src.Status))
+		})
+	})
+
+	Context("When a rerun is requested", func() {
+		It("should reset the status once per rerun token", func() {
+			it := &infrav1alpha1.IntegrationTest{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "rerun",
+					Namespace:   "default",
+					UID:         "uid-1",
+					Annotations: map[string]string{infrav1alpha1.AnnotationRerun: "t1"},
+				},
+				Status: infrav1alpha1.IntegrationTestStatus{
+					Phase:           infrav1alpha1.IntegrationTestPhaseFailed,
+					CompletedRounds: 3,
+					Steps:           []infrav1alpha1.StepStatus{{Name: "create", State: shared.StateFailed}},
+				},
+			}
+
+			token, ok := shared.RerunRequested(it.GetAnnotations(), it.Status.RerunToken)
+			Expect(ok).To(BeTrue())
+			Expect(token).To(Equal("t1"))
+
+			resetForRerun(&it.Status, token)
+			Expect(it.Status).To(Equal(infrav1alpha1.IntegrationTestStatus{RerunToken: "t1"}))
+			_, ok = shared.RerunRequested(it.GetAnnotations(), it.Status.RerunToken)
+			Expect(ok).To(BeFalse())
+			Expect(shared.EventKey(it, 0, "", "IntegrationTestStarted")).To(Equal("uid-1@t1/0/-/IntegrationTestStarted"))
+		})
+
+		It("should create a fresh workspace per run and expand ${WORKSPACE}", func() {
+			ctx := context.Background()
+			scheme := runtime.NewScheme()
+			Expect(infrav1alpha1.AddToScheme(scheme)).To(Succeed())
+			Expect(corev1.AddToScheme(scheme)).To(Succeed())
+			it := &infrav1alpha1.IntegrationTest{
+				ObjectMeta: metav1.ObjectMeta{Name: "ws", Namespace: "default", UID: "uid-1"},
+				Spec: infrav1alpha1.IntegrationTestSpec{
+					Workspace: &infrav1alpha1.WorkspaceSpec{Kind: infrav1alpha1.WorkspaceKindConfigMap},
+					Steps: []infrav1alpha1.TestStep{{Name: "job", Expectations: &infrav1alpha1.StepCondition{
+						AllOf: []infrav1alpha1.Expectation{{Function: "FieldEquals", Params: runtime.RawExtension{Raw: []byte(`{"value":"${WORKSPACE}"}`)}}},
+					}}},
+				},
+			}
+			c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(it).Build()
+			r := &IntegrationTestReconciler{Client: c, Scheme: scheme}
+
+			Expect(r.ensureWorkspace(ctx, it)).To(Succeed())
+			first := it.Status.Workspace
+			var cm corev1.ConfigMap
+			Expect(c.Get(ctx, types.NamespacedName{Namespace: "default", Name: first}, &cm)).To(Succeed())
+			Expect(cm.OwnerReferences).To(HaveLen(1))
+
+			expanded := it.DeepCopy()
+			Expect(expandWorkspace(expanded)).To(Succeed())
+			Expect(string(expanded.Spec.Steps[0].Expectations.AllOf[0].Params.Raw)).To(ContainSubstring(first))
+
+			// 重跑：删除上一次的工作区，新运行使用新名称
+			r.deleteWorkspace(ctx, it)
+			resetForRerun(&it.Status, "t1")
+			Expect(r.ensureWorkspace(ctx, it)).To(Succeed())
+			Expect(it.Status.Workspace).NotTo(Equal(first))
+			Expect(c.Get(ctx, types.NamespacedName{Namespace: "default", Name: first}, &cm)).NotTo(Succeed())
+
+			// 未声明工作区却引用时报错
+			it.Spec.Workspace = nil
+			Expect(expandWorkspace(it)).NotTo(Succeed())
+		})
+	})
+
+	Context("When a running test is suspended", func() {
+		It("should stop advancing and extend step deadlines on resume", func() {
+			ctx := context.Background()
+			scheme := runtime.NewScheme()
+			Expect(infrav1alpha1.AddToScheme(scheme)).To(Succeed())
+			patches := 0
+			c := fake.NewClientBuilder().WithScheme(scheme).WithInterceptorFuncs(interceptor.Funcs{
+				SubResourcePatch: func(context.Context, client.Client, string, client.Object, client.Patch, ...client.SubResourcePatchOption) error {
+					patches++
+					return nil
+				},
+			}).Build()
+			recorder := record.NewFakeRecorder(10)
+			r := &IntegrationTestReconciler{Client: c, Scheme: scheme, PluginRegistry: plugin.NewRegistry(), Recorder: recorder}
+
+			deadline := metav1.NewTime(time.Now().Add(time.Minute))
+			it := &infrav1alpha1.IntegrationTest{
+				ObjectMeta: metav1.ObjectMeta{Name: "paused", Namespace: "default", Generation: 2},
+				Spec: infrav1alpha1.IntegrationTestSpec{
+					Suspend: true,
+					Steps:   []infrav1alpha1.TestStep{{Name: "create"}},
+				},
+				Status: infrav1alpha1.IntegrationTestStatus{
+					Phase:        infrav1alpha1.IntegrationTestPhaseRunning,
+					CurrentRound: 1,
+					Steps: []infrav1alpha1.StepStatus{{Name: "create", State: shared.StateRunning, Deadline: &deadline,
+						ReadyConditionStatus: &infrav1alpha1.ReadyConditionStatus{State: shared.StateRunning, Deadline: &deadline}}},
+				},
+			}
+			result, err := r.executeTest(ctx, it)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(Equal(ctrl.Result{}))
+			Expect(it.Status.SuspendedAt).NotTo(BeNil())
+			Expect(shared.GetCondition(it.Status.Conditions, ConditionTypeSuspended).Status).To(Equal(metav1.ConditionTrue))
+			Expect(it.Status.Deadlines).To(BeEmpty())
+			Expect(recorder.Events).To(Receive(ContainSubstring(shared.EventReasonIntegrationTestSuspended)))
+
+			// 暂停期间不再写入状态
+			written := patches
+			_, err = r.executeTest(ctx, it)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(patches).To(Equal(written))
+
+			// 恢复：暂停的 5 分钟不计入超时
+			suspendedAt := metav1.NewTime(time.Now().Add(-5 * time.Minute))
+			it.Status.SuspendedAt = &suspendedAt
+			it.Spec.Suspend = false
+			suspended, _, err := r.suspendOrResume(ctx, it)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(suspended).To(BeFalse())
+			Expect(it.Status.SuspendedAt).To(BeNil())
+			Expect(it.Status.Steps[0].Deadline.Sub(deadline.Time)).To(BeNumerically("~", 5*time.Minute, time.Second))
+			Expect(it.Status.Steps[0].ReadyConditionStatus.Deadline.Sub(deadline.Time)).To(BeNumerically("~", 5*time.Minute, time.Second))
+			Expect(shared.GetCondition(it.Status.Conditions, ConditionTypeSuspended).Reason).To(Equal("Resumed"))
+			Expect(it.Status.Deadlines).To(HaveKey(ConditionTypeStepDeadline))
+
+			// 切换 suspend 不算作被忽略的 spec 变更
+			it.Status.ObservedGeneration = 1
+			it.Status.SpecDigest = specDigest(it.Spec)
+			it.Spec.Suspend = true
+			Expect(r.detectAndIgnoreSpecChange(ctx, it)).To(BeFalse())
+		})
+	})
+
+	Context("When a failing step hits a breakpoint", func() {
+		It("should keep the test running until resumed and retry the failed step", func() {
+			ctx := context.Background()
+			scheme := runtime.NewScheme()
+			Expect(infrav1alpha1.AddToScheme(scheme)).To(Succeed())
+			c := fake.NewClientBuilder().WithScheme(scheme).WithInterceptorFuncs(interceptor.Funcs{
+				SubResourcePatch: func(context.Context, client.Client, string, client.Object, client.Patch, ...client.SubResourcePatchOption) error {
+					return nil
+				},
+			}).Build()
+			recorder := record.NewFakeRecorder(10)
+			r := &IntegrationTestReconciler{Client: c, Scheme: scheme, PluginRegistry: plugin.NewRegistry(), Recorder: recorder}
+
+			it := &infrav1alpha1.IntegrationTest{
+				ObjectMeta: metav1.ObjectMeta{Name: "debug", Namespace: "default", Annotations: map[string]string{}},
+				Spec: infrav1alpha1.IntegrationTestSpec{
+					Debug: &infrav1alpha1.DebugSpec{BreakOnFailure: true},
+					Steps: []infrav1alpha1.TestStep{{Name: "create"}, {Name: "verify"}},
+				},
+				Status: infrav1alpha1.IntegrationTestStatus{
+					Phase:        infrav1alpha1.IntegrationTestPhaseRunning,
+					CurrentRound: 1,
+					Steps: []infrav1alpha1.StepStatus{
+						{Name: "create", State: shared.StateSucceeded},
+						{Name: "verify", Index: 1, State: shared.StateRunning},
+					},
+				},
+			}
+			setStepFailed(it, &it.Status.Steps[1], "verify", shared.ReasonTimeout, "expectations not satisfied before timeout")
+			Expect(it.Status.Phase).To(Equal(infrav1alpha1.IntegrationTestPhaseRunning))
+			Expect(it.Status.Reason).To(Equal(ReasonBreakpoint))
+			Expect(it.Status.CompletionTime).To(BeNil())
+			Expect(it.Status.Breakpoint).NotTo(BeNil())
+			Expect(it.Status.Breakpoint.Step).To(Equal("verify"))
+			Expect(it.Status.Breakpoint.Reason).To(Equal(shared.ReasonTimeout))
+
+			result, err := r.handleStepFailure(ctx, it)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(Equal(ctrl.Result{}))
+			Expect(recorder.Events).To(Receive(ContainSubstring(shared.EventReasonIntegrationTestBreakpoint)))
+
+			// 没有恢复令牌时保持断点
+			result, err = r.executeTest(ctx, it)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(Equal(ctrl.Result{}))
+			Expect(it.Status.Breakpoint).NotTo(BeNil())
+
+			// 恢复：重置失败的步骤，成功的步骤保持不变
+			it.Annotations[infrav1alpha1.AnnotationResume] = "1"
+			result, err = r.executeTest(ctx, it)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.Requeue).To(BeTrue())
+			Expect(it.Status.Breakpoint).To(BeNil())
+			Expect(it.Status.ResumeToken).To(Equal("1"))
+			Expect(it.Status.Reason).To(BeEmpty())
+			Expect(it.Status.Steps[0].State).To(Equal(shared.StateSucceeded))
+			Expect(it.Status.Steps[1]).To(Equal(infrav1alpha1.StepStatus{Name: "verify", Index: 1}))
+		})
+	})
+
+	Context("When the spec changes mid-run", func() {
+		It("should record the ignored paths once per generation", func() {
+			ctx := context.Background()
+			it := &infrav1alpha1.IntegrationTest{
+				ObjectMeta: metav1.ObjectMeta{Name: "drift", Namespace: "default", Generation: 1},
+				Spec: infrav1alpha1.IntegrationTestSpec{
+					Steps: []infrav1alpha1.TestStep{
+						{Name: "create", TimeoutSeconds: 60},
+						{Name: "delete"},
+					},
+				},
+			}
+			it.Status.ObservedGeneration = 1
+			it.Status.SpecDigest = specDigest(it.Spec)
+			r := &IntegrationTestReconciler{}
+			Expect(r.detectAndIgnoreSpecChange(ctx, it)).To(BeFalse())
+
+			it.Generation = 2
+			it.Spec.StrictSchema = true
+			it.Spec.Steps[0].TimeoutSeconds = 120
+			it.Spec.Steps[1] = infrav1alpha1.TestStep{Name: "verify"}
+			Expect(r.detectAndIgnoreSpecChange(ctx, it)).To(BeTrue())
+			Expect(it.Status.IgnoredSpecChanges).To(Equal([]string{"~steps", "~steps[create].timeoutSeconds", "-steps[delete]", "+steps[verify]", "+strictSchema"}))
+			cond := shared.GetCondition(it.Status.Conditions, ConditionTypeSpecChangedIgnored)
+			Expect(cond.Message).To(HaveSuffix("changes are ignored: ~steps, ~steps[create].timeoutSeconds, -steps[delete], +steps[verify], +strictSchema"))
+			Expect(r.detectAndIgnoreSpecChange(ctx, it)).To(BeFalse())
+		})
+	})
+
+	Context("When expectations declare a severity", func() {
+		It("should report warning failures without failing the step", func() {
+			warning := infrav1alpha1.ExpectationSeverityWarning
+			results := shared.ExpectationResults{
+				AllOf: []infrav1alpha1.ExpectationResult{
+					{Expect: "FieldEquals", Passed: true},
+					{Name: "no deprecated APIs", Expect: "FieldExists", Severity: warning},
+				},
+				AnyOf: []infrav1alpha1.ExpectationResult{{Expect: "FieldExists", Severity: warning}},
+			}
+			Expect(results.Passed()).To(BeTrue())
+			Expect(shared.FailedExpectationLabels(results.All())).To(BeEmpty())
+			Expect(results.Warnings()).To(HaveLen(2))
+
+			it := &infrav1alpha1.IntegrationTest{}
+			Expect(shared.SetExpectationWarnings(&it.Status.Conditions, "step create", results.All(), 1)).To(BeTrue())
+			Expect(shared.SetExpectationWarnings(&it.Status.Conditions, "step create", results.All(), 1)).To(BeFalse())
+			cond := shared.GetCondition(it.Status.Conditions, shared.ConditionTypeExpectationWarnings)
+			Expect(cond.Message).To(Equal("step create: no deprecated APIs, FieldExists"))
+
+			// Blocker 未通过时仍判定失败
+			results.AllOf[0].Passed = false
+			Expect(results.Passed()).To(BeFalse())
+			Expect(shared.FailedExpectationLabels(results.All())).To(Equal("FieldEquals"))
+		})
+	})
+
+	Context("When a step compares a canary against its baseline", func() {
+		It("should assert the deviation of each extracted metric", func() {
+			scheme := runtime.NewScheme()
+			Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+			deployment := func(name string, ready int32) *appsv1.Deployment {
+				return &appsv1.Deployment{
+					ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+					Status:     appsv1.DeploymentStatus{ReadyReplicas: ready},
+				}
+			}
+			c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(deployment("operator-stable", 20), deployment("operator-canary", 19)).Build()
+			registry := plugin.NewRegistry()
+			registry.Register("ReadyReplicas", func(res, _ map[string]interface{}) plugin.Result {
+				return plugin.Extract(fmt.Sprint(plugin.GetInt(plugin.GetMap(res, "status"), "readyReplicas")))
+			})
+			r := &IntegrationTestReconciler{Client: c, Scheme: scheme, PluginRegistry: registry}
+			it := &infrav1alpha1.IntegrationTest{ObjectMeta: metav1.ObjectMeta{Name: "canary", Namespace: "default"}}
+
+			selector := func(name string) infrav1alpha1.ResourceSelector {
+				return infrav1alpha1.ResourceSelector{APIVersion: "apps/v1", Kind: "Deployment", Name: name}
+			}
+			extract := infrav1alpha1.Extractor{Function: "ReadyReplicas"}
+			strict := int32(2)
+			canary := &infrav1alpha1.CanarySpec{
+				Baseline: selector("operator-stable"),
+				Canary:   selector("operator-canary"),
+				Metrics: []infrav1alpha1.CanaryMetric{
+					{Name: "ready", Extract: extract},
+					{Name: "ready-strict", Extract: extract, MaxDeviationPercent: &strict},
+				},
+			}
+			results, err := r.compareCanary(context.Background(), it, canary)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(results).To(HaveLen(2))
+			Expect(results[0].Passed).To(BeTrue())
+			Expect(results[0].Actual).To(Equal("baseline=20 canary=19"))
+			Expect(results[1].Passed).To(BeFalse())
+			Expect(results[1].Message).To(Equal("deviation 5.00% (max 2%)"))
+
+			// 金丝雀尚未创建：记为可重试错误，步骤继续等待
+			canary.Canary = selector("operator-missing")
+			results, err = r.compareCanary(context.Background(), it, canary)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(results[0].Error).To(BeTrue())
+			Expect(results[0].Message).To(ContainSubstring("not found"))
+		})
+	})
+
+	Context("When the controller restarts between apply and status write", func() {
+		It("should resume from the apply ledger without re-applying", func() {
+			scheme := runtime.NewScheme()
+			Expect(infrav1alpha1.AddToScheme(scheme)).To(Succeed())
+			Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+			it := &infrav1alpha1.IntegrationTest{
+				ObjectMeta: metav1.ObjectMeta{Name: "ledger", Namespace: "default", UID: "ledger-uid"},
+				Spec: infrav1alpha1.IntegrationTestSpec{Steps: []infrav1alpha1.TestStep{
+					{Name: "config", Resource: &infrav1alpha1.ResourceRef{Manifest: runtime.RawExtension{
+						Raw: []byte(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"app"},"data":{"k":"v"}}`),
+					}}},
+				}},
+				Status: infrav1alpha1.IntegrationTestStatus{CurrentRound: 2, ApplyLedger: []infrav1alpha1.ApplyLedgerEntry{{Step: "old", Round: 1, Hash: "x"}}},
+			}
+			step := it.Spec.Steps[0]
+			r := &IntegrationTestReconciler{Scheme: scheme}
+			manifest, err := r.expandStepResource(it, step)
+			Expect(err).NotTo(HaveOccurred())
+			entry, ok := ledgerEntry(it.Status.CurrentRound, step, manifest)
+			Expect(ok).To(BeTrue())
+			Expect(recordApplyLedger(&it.Status, []infrav1alpha1.ApplyLedgerEntry{entry})).To(BeTrue())
+			Expect(it.Status.ApplyLedger).To(Equal([]infrav1alpha1.ApplyLedgerEntry{entry}))
+			Expect(recordApplyLedger(&it.Status, []infrav1alpha1.ApplyLedgerEntry{entry})).To(BeFalse())
+
+			// 上一个 leader 已 apply（对象带有相同的清单哈希）但未写入步骤状态
+			applied := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default",
+				Annotations: map[string]string{infrav1alpha1.AnnotationManifestHash: entry.Hash}}}
+			applies := 0
+			c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(applied).WithInterceptorFuncs(interceptor.Funcs{
+				Patch: func(context.Context, client.WithWatch, client.Object, client.Patch, ...client.PatchOption) error {
+					applies++
+					return nil
+				},
+			}).Build()
+			r.Client = c
+			r.ResourceManager = resource.NewManager(c, scheme, integrationTestFieldOwner, nil)
+			stepStatus := &infrav1alpha1.StepStatus{Name: "config"}
+			Expect(r.applyStepOnce(context.Background(), it, step, stepStatus, manifest)).To(Succeed())
+			Expect(applies).To(BeZero())
+
+			// 清单变化后哈希不同，照常 apply 并记录新哈希
+			it.Spec.Steps[0].Resource.Manifest.Raw = []byte(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"app"},"data":{"k":"v2"}}`)
+			manifest, err = r.expandStepResource(it, it.Spec.Steps[0])
+			Expect(err).NotTo(HaveOccurred())
+			Expect(r.applyStepOnce(context.Background(), it, it.Spec.Steps[0], stepStatus, manifest)).To(Succeed())
+			Expect(applies).To(Equal(1))
+			Expect(manifest.Object.GetAnnotations()[infrav1alpha1.AnnotationManifestHash]).NotTo(Equal(entry.Hash))
+		})
+	})
+
+	Context("When resource writes are rejected", func() {
+		It("should classify errors into step reasons and retry conflicts", func() {
+			scheme := runtime.NewScheme()
+			Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+			var applyErr error
+			c := fake.NewClientBuilder().WithScheme(scheme).WithInterceptorFuncs(interceptor.Funcs{
+				Patch: func(context.Context, client.WithWatch, client.Object, client.Patch, ...client.PatchOption) error {
+					return applyErr
+				},
+			}).Build()
+			manager := resource.NewManager(c, scheme, integrationTestFieldOwner, nil)
+			owner := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "owner", Namespace: "default", UID: "owner-uid"}}
+			obj := &unstructured.Unstructured{}
+			obj.SetAPIVersion("v1")
+			obj.SetKind("ConfigMap")
+			obj.SetName("app")
+			obj.SetNamespace("default")
+
+			gr := corev1.Resource("configmaps")
+			denied := apierrors.NewForbidden(gr, "app", errors.New(`admission webhook "policy.example.com" denied the request: missing team label`))
+			r := &IntegrationTestReconciler{}
+			for _, tc := range []struct {
+				err    error
+				class  resource.ErrorClass
+				reason string
+			}{
+				{apierrors.NewForbidden(gr, "app", errors.New("rbac")), resource.ErrorClassForbidden, shared.ReasonForbidden},
+				{apierrors.NewInvalid(corev1.SchemeGroupVersion.WithKind("ConfigMap").GroupKind(), "app", nil), resource.ErrorClassInvalid, shared.ReasonInvalid},
+				{denied, resource.ErrorClassWebhookDenied, shared.ReasonWebhookDenied},
+				{apierrors.NewConflict(gr, "app", errors.New("field manager conflict")), resource.ErrorClassConflict, shared.ReasonConflict},
+				{apierrors.NewServiceUnavailable("etcd leader changed"), resource.ErrorClassTransient, shared.ReasonFailed},
+			} {
+				applyErr = tc.err
+				err := manager.ApplyObject(context.Background(), owner, obj.DeepCopy())
+				var classified *resource.ClassifiedError
+				Expect(errors.As(err, &classified)).To(BeTrue())
+				Expect(classified.Class).To(Equal(tc.class))
+				Expect(r.applyFailureReason(context.Background(), nil, err)).To(Equal(tc.reason))
+			}
+
+			// 冲突与暂时性错误在步骤超时前重试，其他错误直接失败
+			future := metav1.NewTime(time.Now().Add(time.Minute))
+			stepStatus := &infrav1alpha1.StepStatus{Deadline: &future}
+			conflict := fmt.Errorf("failed to apply: %w", &resource.ClassifiedError{Class: resource.ErrorClassConflict, Err: errors.New("conflict")})
+			Expect(r.retryingApply(stepStatus, conflict)).To(BeTrue())
+			Expect(stepStatus.Message).To(HavePrefix("retrying apply after conflict error"))
+			Expect(r.retryingApply(stepStatus, denied)).To(BeFalse())
+			past := metav1.NewTime(time.Now().Add(-time.Second))
+			stepStatus.Deadline = &past
+			Expect(r.retryingApply(stepStatus, conflict)).To(BeFalse())
+		})
+	})
+
+	Context("When a step resource's API is uninstalled", func() {
+		It("should fail the step with APIRemoved only once discovery confirms the removal", func() {
+			ctx := context.Background()
+			removal := &shared.APIRemoval{Discovery: &discoveryfake.FakeDiscovery{Fake: &clienttesting.Fake{Resources: []*metav1.APIResourceList{
+				{GroupVersion: "apps/v1", APIResources: []metav1.APIResource{{Name: "deployments", Kind: "Deployment"}}},
+			}}}}
+			r := &IntegrationTestReconciler{APIRemoval: removal}
+			widget := schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Widget"}
+			manifest, err := resource.ExpandSingleResourceRef(infrav1alpha1.ResourceRef{Manifest: runtime.RawExtension{Raw: []byte(
+				`{"apiVersion":"example.com/v1","kind":"Widget","metadata":{"name":"w"}}`)}}, "default")
+			Expect(err).NotTo(HaveOccurred())
+
+			noMatch := &apimeta.NoKindMatchError{GroupKind: widget.GroupKind(), SearchedVersions: []string{"v1"}}
+			Expect(r.applyFailureReason(ctx, manifest, noMatch)).To(Equal(shared.ReasonAPIRemoved))
+			notFound := apierrors.NewNotFound(schema.GroupResource{Group: "example.com", Resource: "widgets"}, "w")
+			Expect(removal.Removed(ctx, widget, fmt.Errorf("%w: Widget/w not found: %w", ErrResourceNotReady, notFound))).To(BeTrue())
+
+			// 类型仍被提供时只是对象不存在；其他错误不查询 discovery
+			Expect(removal.Removed(ctx, appsv1.SchemeGroupVersion.WithKind("Deployment"), notFound)).To(BeFalse())
+			Expect(removal.Removed(ctx, widget, errors.New("timeout"))).To(BeFalse())
+
+			failed := &infrav1alpha1.IntegrationTest{}
+			setStepFailed(failed, &infrav1alpha1.StepStatus{}, "create", shared.ReasonAPIRemoved, "gone")
+			Expect(failed.Status.Reason).To(Equal(shared.ReasonAPIRemoved))
+		})
+	})
+
+	Context("When steps have pending deadlines", func() {
+		It("should expose deadlines as conditions and clear them when finished", func() {
+			start := metav1.NewTime(time.Now().Add(-time.Minute).Truncate(time.Second))
+			stepDeadline := metav1.NewTime(start.Add(5 * time.Minute))
+			readyDeadline := metav1.NewTime(start.Add(2 * time.Minute))
+			it := &infrav1alpha1.IntegrationTest{
+				Spec: infrav1alpha1.IntegrationTestSpec{Repeat: &infrav1alpha1.RepeatConfig{MaxDurationSeconds: 3600}},
+				Status: infrav1alpha1.IntegrationTestStatus{
+					Phase:     infrav1alpha1.IntegrationTestPhaseRunning,
+					StartTime: &start,
+					Steps: []infrav1alpha1.StepStatus{{
+						Name:                 "create",
+						State:                shared.StateRunning,
+						Deadline:             &stepDeadline,
+						ReadyConditionStatus: &infrav1alpha1.ReadyConditionStatus{State: shared.StateRunning, Deadline: &readyDeadline},
+					}},
+				},
+			}
+			syncDeadlines(it)
+			Expect(it.Status.Deadlines).To(HaveLen(3))
+			Expect(it.Status.Deadlines[ConditionTypeStepDeadline].Time).To(Equal(stepDeadline.Time))
+			Expect(it.Status.Deadlines[ConditionTypeMaxDurationDeadline].Time).To(Equal(start.Add(time.Hour)))
+			Expect(shared.GetCondition(it.Status.Conditions, ConditionTypeReadyConditionDeadline).Message).To(ContainSubstring(readyDeadline.UTC().Format(time.RFC3339)))
+
+			// readyCondition 通过后对应的截止时间结束
+			it.Status.Steps[0].ReadyConditionStatus.State = shared.StatePassed
+			syncDeadlines(it)
+			Expect(it.Status.Deadlines).NotTo(HaveKey(ConditionTypeReadyConditionDeadline))
+			Expect(shared.IsConditionTrue(it.Status.Conditions, ConditionTypeReadyConditionDeadline)).To(BeFalse())
+
+			it.Status.Phase = infrav1alpha1.IntegrationTestPhaseSucceeded
+			syncDeadlines(it)
+			Expect(it.Status.Deadlines).To(BeNil())
+			Expect(shared.IsConditionTrue(it.Status.Conditions, ConditionTypeStepDeadline)).To(BeFalse())
+		})
+	})
+
+	Context("When the test namespace is terminating", func() {
+		It("should detect the terminating namespace and its apply rejections", func() {
+			ctx := context.Background()
+			scheme := runtime.NewScheme()
+			Expect(corev1.AddToScheme(scheme)).To(Succeed())
+			now := metav1.Now()
+			ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "doomed", DeletionTimestamp: &now, Finalizers: []string{"kubernetes"}}}
+			c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(ns, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "alive"}}).Build()
+			r := &IntegrationTestReconciler{Client: c, Scheme: scheme}
+
+			message, err := r.namespaceTerminating(ctx, &infrav1alpha1.IntegrationTest{ObjectMeta: metav1.ObjectMeta{Name: "t", Namespace: "doomed"}})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(message).To(Equal("namespace doomed is terminating"))
+			for _, name := range []string{"alive", "missing"} {
+				message, err = r.namespaceTerminating(ctx, &infrav1alpha1.IntegrationTest{ObjectMeta: metav1.ObjectMeta{Name: "t", Namespace: name}})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(message).To(BeEmpty())
+			}
+
+			rejected := apierrors.NewForbidden(corev1.Resource("configmaps"), "cm", errors.New("namespace doomed is being terminated"))
+			rejected.ErrStatus.Details.Causes = []metav1.StatusCause{{Type: corev1.NamespaceTerminatingCause}}
+			Expect(isNamespaceTerminatingError(fmt.Errorf("apply: %w", rejected))).To(BeTrue())
+			Expect(isNamespaceTerminatingError(apierrors.NewForbidden(corev1.Resource("configmaps"), "cm", errors.New("rbac")))).To(BeFalse())
+		})
+	})
+
+	Context("When a step re-applies an object that is still being deleted", func() {
+		It("should wait until the step deadline and then fail", func() {
+			r := &IntegrationTestReconciler{}
+			terminating := fmt.Errorf("apply: %w", ErrObjectTerminating)
+			future := metav1.NewTime(time.Now().Add(time.Minute))
+			stepStatus := &infrav1alpha1.StepStatus{Deadline: &future}
+
+			Expect(r.waitingForTermination(stepStatus, terminating)).To(BeTrue())
+			Expect(stepStatus.Message).To(ContainSubstring("waiting for previous object to be deleted"))
+			Expect(r.waitingForTermination(stepStatus, errors.New("forbidden"))).To(BeFalse())
+
+			past := metav1.NewTime(time.Now().Add(-time.Second))
+			stepStatus.Deadline = &past
+			Expect(r.waitingForTermination(stepStatus, terminating)).To(BeFalse())
+		})
+	})
+
+	Context("When a test depends on other tests", func() {
+		newDependency := func(name string, phase infrav1alpha1.IntegrationTestPhase) *infrav1alpha1.IntegrationTest {
+			return &infrav1alpha1.IntegrationTest{
+				ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+				Status:     infrav1alpha1.IntegrationTestStatus{Phase: phase, Message: "step create failed"},
+			}
+		}
+
+		It("should classify succeeded, running, failed and missing dependencies", func() {
+			ctx := context.Background()
+			scheme := runtime.NewScheme()
+			Expect(infrav1alpha1.AddToScheme(scheme)).To(Succeed())
+			c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+				newDependency("setup", infrav1alpha1.IntegrationTestPhaseSucceeded),
+				newDependency("warmup", infrav1alpha1.IntegrationTestPhaseRunning),
+				newDependency("broken", infrav1alpha1.IntegrationTestPhaseFailed),
+			).Build()
+			r := &IntegrationTestReconciler{Client: c, Scheme: scheme}
+			dependsOn := func(names ...string) *infrav1alpha1.IntegrationTest {
+				return &infrav1alpha1.IntegrationTest{
+					ObjectMeta: metav1.ObjectMeta{Name: "t", Namespace: "default"},
+					Spec:       infrav1alpha1.IntegrationTestSpec{DependsOn: names},
+				}
+			}
+
+			ready, missing, failedMsg, err := r.checkDependencies(ctx, dependsOn("setup"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ready).To(BeTrue())
+			Expect(missing).To(BeEmpty())
+			Expect(failedMsg).To(BeEmpty())
+
+			ready, missing, failedMsg, err = r.checkDependencies(ctx, dependsOn("setup", "warmup", "ghost"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ready).To(BeFalse())
+			Expect(missing).To(Equal([]string{"ghost"}))
+			Expect(failedMsg).To(BeEmpty())
+
+			ready, _, failedMsg, err = r.checkDependencies(ctx, dependsOn("setup", "broken"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ready).To(BeFalse())
+			Expect(failedMsg).To(Equal("dependency broken is Failed: step create failed"))
+		})
+
+		It("should abort the test when a dependency failed", func() {
+			ctx := context.Background()
+			scheme := runtime.NewScheme()
+			Expect(infrav1alpha1.AddToScheme(scheme)).To(Succeed())
+			Expect(corev1.AddToScheme(scheme)).To(Succeed())
+			it := &infrav1alpha1.IntegrationTest{
+				ObjectMeta: metav1.ObjectMeta{Name: "dependent", Namespace: "default", UID: "dependent-uid",
+					Finalizers: []string{integrationTestFinalizer}},
+				Spec: infrav1alpha1.IntegrationTestSpec{
+					DependsOn: []string{"broken"},
+					Steps:     []infrav1alpha1.TestStep{{Name: "create"}},
+				},
+				Status: infrav1alpha1.IntegrationTestStatus{Phase: infrav1alpha1.IntegrationTestPhasePending},
+			}
+			var phases []infrav1alpha1.IntegrationTestPhase
+			var reasons []string
+			c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(it, newDependency("broken", infrav1alpha1.IntegrationTestPhaseFailed)).
+				WithInterceptorFuncs(interceptor.Funcs{
+					SubResourcePatch: func(_ context.Context, _ client.Client, _ string, obj client.Object, _ client.Patch, _ ...client.SubResourcePatchOption) error {
+						status := obj.(*infrav1alpha1.IntegrationTest).Status
+						phases = append(phases, status.Phase)
+						reasons = append(reasons, status.Reason)
+						return nil
+					},
+				}).Build()
+			recorder := record.NewFakeRecorder(10)
+			r := &IntegrationTestReconciler{Client: c, Scheme: scheme, PluginRegistry: plugin.NewRegistry(), Recorder: recorder}
+
+			_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(it)})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(phases).To(ContainElement(infrav1alpha1.IntegrationTestPhaseAborted))
+			Expect(reasons).To(ContainElement(infrav1alpha1.AbortReasonDependencyFailed))
+			Expect(<-recorder.Events).To(And(ContainSubstring(shared.EventReasonIntegrationTestAborted), ContainSubstring("dependency broken is Failed")))
+		})
+
+		It("should wait for a missing dependency with a condition and fail after the bounded wait", func() {
+			ctx := context.Background()
+			scheme := runtime.NewScheme()
+			Expect(infrav1alpha1.AddToScheme(scheme)).To(Succeed())
+			it := &infrav1alpha1.IntegrationTest{
+				ObjectMeta: metav1.ObjectMeta{Name: "dependent", Namespace: "default", UID: "dependent-uid"},
+				Spec:       infrav1alpha1.IntegrationTestSpec{DependsOn: []string{"ghost"}},
+				Status:     infrav1alpha1.IntegrationTestStatus{Phase: infrav1alpha1.IntegrationTestPhasePending},
+			}
+			patches := 0
+			c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(it).WithInterceptorFuncs(interceptor.Funcs{
+				SubResourcePatch: func(_ context.Context, _ client.Client, _ string, _ client.Object, _ client.Patch, _ ...client.SubResourcePatchOption) error {
+					patches++
+					return nil
+				},
+			}).Build()
+			recorder := record.NewFakeRecorder(10)
+			r := &IntegrationTestReconciler{Client: c, Scheme: scheme, Recorder: recorder}
+
+			waiting, result, err := r.syncMissingDependencies(ctx, it, []string{"ghost"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(waiting).To(BeTrue())
+			Expect(result.RequeueAfter).To(Equal(defaultRequeue))
+			Expect(patches).To(BeNumerically(">", 0))
+			written := patches
+			cond := shared.GetCondition(it.Status.Conditions, ConditionTypeDependencyNotFound)
+			Expect(cond).NotTo(BeNil())
+			Expect(cond.Status).To(Equal(metav1.ConditionTrue))
+			Expect(cond.Message).To(Equal("dependencies not found: ghost"))
+
+			// 缺失列表不变时不再写入 status
+			waiting, _, err = r.syncMissingDependencies(ctx, it, []string{"ghost"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(waiting).To(BeTrue())
+			Expect(patches).To(Equal(written))
+
+			// 依赖出现后 Condition 变为 False，继续正常流程
+			waiting, _, err = r.syncMissingDependencies(ctx, it, nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(waiting).To(BeFalse())
+			Expect(patches).To(BeNumerically(">", written))
+			written = patches
+			Expect(shared.GetCondition(it.Status.Conditions, ConditionTypeDependencyNotFound).Status).To(Equal(metav1.ConditionFalse))
+			waiting, _, err = r.syncMissingDependencies(ctx, it, nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(waiting).To(BeFalse())
+			Expect(patches).To(Equal(written))
+
+			// 从开始等待起超过时限仍不存在：测试失败
+			_, _, err = r.syncMissingDependencies(ctx, it, []string{"ghost"})
+			Expect(err).NotTo(HaveOccurred())
+			cond = shared.GetCondition(it.Status.Conditions, ConditionTypeDependencyNotFound)
+			cond.LastTransitionTime = metav1.NewTime(time.Now().Add(-defaultDependencyWait - time.Second))
+			waiting, result, err = r.syncMissingDependencies(ctx, it, []string{"ghost"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(waiting).To(BeTrue())
+			Expect(result.Requeue).To(BeTrue())
+			Expect(it.Status.Phase).To(Equal(infrav1alpha1.IntegrationTestPhaseFailed))
+			Expect(it.Status.Reason).To(Equal(ReasonDependencyNotFound))
+			Expect(it.Status.Message).To(Equal("dependencies not found: ghost after waiting 10m0s"))
+			Expect(<-recorder.Events).To(And(HavePrefix("Warning "+shared.EventReasonIntegrationTestFailed), ContainSubstring("ghost")))
+
+			// 设置 maxDurationSeconds 时按其等待
+			it.Spec.Repeat = &infrav1alpha1.RepeatConfig{MaxDurationSeconds: 60}
+			Expect(dependencyWait(it)).To(Equal(time.Minute))
+		})
+	})
+
+	Context("When exporters are configured", func() {
+		ctx := context.Background()
+
+		It("should export phase transitions to the spec exporters", func() {
+			received := make(chan map[string]interface{}, 1)
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				var body map[string]interface{}
+				_ = json.NewDecoder(req.Body).Decode(&body)
+				received <- body
+			}))
+			defer server.Close()
+
+			it := &infrav1alpha1.IntegrationTest{
+				ObjectMeta: metav1.ObjectMeta{Name: "export", Namespace: "default", UID: "uid-2"},
+				Spec: infrav1alpha1.IntegrationTestSpec{
+					Exporters: []infrav1alpha1.ExporterSpec{{Type: infrav1alpha1.ExporterTypeHTTP, URL: server.URL}},
+				},
+			}
+			exporter := &shared.ResultExporter{}
+			exporter.ExportPhaseChange(ctx, it, "IntegrationTest", it.Spec.Exporters, 1, "Running", "Running", "", "")
+			exporter.ExportPhaseChange(ctx, it, "IntegrationTest", it.Spec.Exporters, 1, "Running", "Failed", "StepFailed", "step create failed")
+
+			var body map[string]interface{}
+			Eventually(received).Should(Receive(&body))
+			Expect(body["type"]).To(Equal("PhaseChanged"))
+			Expect(body["previousPhase"]).To(Equal("Running"))
+			Expect(body["phase"]).To(Equal("Failed"))
+			Expect(body["key"]).To(Equal("uid-2/1/-/PhaseChanged-Failed"))
+			Expect(body["object"]).To(HaveKeyWithValue("kind", "IntegrationTest"))
+			Consistently(received).ShouldNot(Receive())
+		})
+	})
+
+	Context("When reconcile metrics are recorded", func() {
+		ctx := context.Background()
+
+		// metricValue 返回指定指标与标签的计数（直方图为样本数）。
+		metricValue := func(name string, labels map[string]string) float64 {
+			families, err := ctrlmetrics.Registry.Gather()
+			Expect(err).NotTo(HaveOccurred())
+			for _, family := range families {
+				if family.GetName() != name {
+					continue
+				}
+			metric:
+				for _, m := range family.GetMetric() {
+					for _, pair := range m.GetLabel() {
+						if labels[pair.GetName()] != pair.GetValue() {
+							continue metric
+						}
+					}
+					if h := m.GetHistogram(); h != nil {
+						return float64(h.GetSampleCount())
+					}
+					return m.GetCounter().GetValue()
+				}
+			}
+			return 0
+		}
+
+		It("should record the highest outcome and classify reconcile errors", func() {
+			applied := map[string]string{"controller": "metrics-test", "outcome": "applied"}
+			before := metricValue("testplane_reconcile_duration_seconds", applied)
+			requeues := metricValue("testplane_reconcile_requeues_total", applied)
+			rctx, done := metrics.Start(ctx, "metrics-test")
+			metrics.Mark(rctx, metrics.OutcomeWaitedConvergence)
+			metrics.Mark(rctx, metrics.OutcomeApplied)
+			metrics.Mark(rctx, metrics.OutcomePatchedStatus)
+			done(ctrl.Result{RequeueAfter: time.Second}, nil)
+			Expect(metricValue("testplane_reconcile_duration_seconds", applied)).To(Equal(before + 1))
+			Expect(metricValue("testplane_reconcile_requeues_total", applied)).To(Equal(requeues + 1))
+
+			scheme := runtime.NewScheme()
+			Expect(infrav1alpha1.AddToScheme(scheme)).To(Succeed())
+			c := fake.NewClientBuilder().WithScheme(scheme).WithInterceptorFuncs(interceptor.Funcs{
+				Get: func(ctx context.Context, c client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+					return apierrors.NewTimeoutError("etcd slow", 1)
+				},
+			}).Build()
+			r := &IntegrationTestReconciler{Client: c, Scheme: scheme, PluginRegistry: plugin.NewRegistry()}
+			timeouts := map[string]string{"controller": "integrationtest", "type": "timeout"}
+			errorsBefore := metricValue("testplane_reconcile_errors_total", timeouts)
+			_, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: types.NamespacedName{Name: "slow", Namespace: "default"}})
+			Expect(err).To(HaveOccurred())
+			Expect(metricValue("testplane_reconcile_errors_total", timeouts)).To(Equal(errorsBefore + 1))
+
+			Expect(metrics.ErrorType(fmt.Errorf("apply: %w", apierrors.NewConflict(corev1.Resource("configmaps"), "cm", errors.New("stale"))))).To(Equal("conflict"))
+			Expect(metrics.ErrorType(errors.New("boom"))).To(Equal("other"))
+		})
+	})
+
+	Context("When watch events arrive", func() {
+		ctx := context.Background()
+
+		It("should ignore status-only updates and coalesce bursts", func() {
+			old := &infrav1alpha1.IntegrationTest{ObjectMeta: metav1.ObjectMeta{Name: "watch", Namespace: "default", Generation: 1}}
+			statusOnly := old.DeepCopy()
+			statusOnly.Status.Phase = infrav1alpha1.IntegrationTestPhaseRunning
+			rerun := old.DeepCopy()
+			rerun.Annotations = map[string]string{infrav1alpha1.AnnotationRerun: "1"}
+
+			pred := shared.IgnoreSelfUpdates()
+			Expect(pred.Update(event.UpdateEvent{ObjectOld: old, ObjectNew: statusOnly})).To(BeFalse())
+			Expect(pred.Update(event.UpdateEvent{ObjectOld: old, ObjectNew: rerun})).To(BeTrue())
+
+			q := workqueue.NewTypedRateLimitingQueue(workqueue.DefaultTypedControllerRateLimiter[reconcile.Request]())
+			defer q.ShutDown()
+			h := shared.DebounceUpdates(&handler.EnqueueRequestForObject{}, 50*time.Millisecond)
+			for range 5 {
+				h.Update(ctx, event.UpdateEvent{ObjectOld: old, ObjectNew: rerun}, q)
+			}
+			Expect(q.Len()).To(BeZero())
+			Eventually(q.Len).Should(Equal(1))
+			Consistently(q.Len, 100*time.Millisecond).Should(Equal(1))
+		})
+	})
+
+	Context("When a step waits for a long operation", func() {
+		It("should record heartbeats and phase transitions of the target", func() {
+			cluster := func(phase string) map[string]interface{} {
+				return map[string]interface{}{"v1/Cluster/c1": map[string]interface{}{
+					"kind": "Cluster", "status": map[string]interface{}{"phase": phase, "message": "provisioning nodes"},
+				}}
+			}
+			start := time.Now()
+			st := &infrav1alpha1.StepStatus{Name: "create-cluster"}
+
+			Expect(recordStepProgress(st, cluster("Provisioning"), start)).To(BeTrue())
+			Expect(st.Progress.ObservedPhase).To(Equal("Provisioning"))
+			Expect(st.Progress.ObservedMessage).To(Equal("provisioning nodes"))
+
+			// 阶段未变化：心跳间隔内不持久化，到期后只更新心跳
+			Expect(recordStepProgress(st, cluster("Provisioning"), start.Add(10*time.Second))).To(BeFalse())
+			Expect(recordStepProgress(st, cluster("Provisioning"), start.Add(2*time.Minute))).To(BeTrue())
+			Expect(st.Progress.LastTransitionTime.Time).To(BeTemporally("~", start, time.Second))
+			Expect(st.Progress.LastHeartbeatTime.Time).To(BeTemporally("~", start.Add(2*time.Minute), time.Second))
+
+			Expect(recordStepProgress(st, cluster("Running"), start.Add(2*time.Minute+5*time.Second))).To(BeTrue())
+			Expect(st.Progress.LastTransitionTime.Time).To(BeTemporally("~", start.Add(2*time.Minute+5*time.Second), time.Second))
+		})
+
+		It("should require holdSeconds of continuous success", func() {
+			ready := true
+			registry := plugin.NewRegistry()
+			registry.Register("Ready", func(_, _ map[string]interface{}) plugin.Result {
+				if ready {
+					return plugin.Pass()
+				}
+				return plugin.Fail("crashed")
+			})
+			cond := &infrav1alpha1.StepCondition{AllOf: []infrav1alpha1.Expectation{{Function: "Ready", HoldSeconds: 60}}}
+			state := map[string]interface{}{"c1": map[string]interface{}{}}
+			run := func(held []shared.HeldSince) infrav1alpha1.ExpectationResult {
+				results, err := shared.NewExpectationRunner(registry).WithHeldSince(held).RunStepCondition(cond, state)
+				Expect(err).NotTo(HaveOccurred())
+				return results.AllOf[0]
+			}
+
+			// 首次成立：开始计时，未满 60 秒不通过
+			first := run(nil)
+			Expect(first.Passed).To(BeFalse())
+			Expect(first.HeldSince).NotTo(BeNil())
+			Expect(shared.HoldChanged(nil, []infrav1alpha1.ExpectationResult{first})).To(BeTrue())
+
+			// 连续成立满 60 秒后通过
+			since := metav1.NewTime(time.Now().Add(-90 * time.Second))
+			Expect(run([]shared.HeldSince{{Expect: "Ready", Since: &since}}).Passed).To(BeTrue())
+
+			// 中途中断：计时清除
+			ready = false
+			Expect(run([]shared.HeldSince{{Expect: "Ready", Since: &since}}).HeldSince).To(BeNil())
+
+			// 未启用跟踪时忽略 holdSeconds
+			ready = true
+			results, err := shared.NewExpectationRunner(registry).RunStepCondition(cond, state)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(results.Passed()).To(BeTrue())
+		})
+
+		It("should label results with expectation names", func() {
+			registry := plugin.NewRegistry()
+			registry.Register("TCPReachable", func(_, _ map[string]interface{}) plugin.Result {
+				return plugin.Fail("connection refused")
+			})
+			registry.Register("Ready", func(_, _ map[string]interface{}) plugin.Result { return plugin.Pass() })
+			cond := &infrav1alpha1.StepCondition{AllOf: []infrav1alpha1.Expectation{
+				{Name: "primary VIP reachable", Description: "VIP accepts TCP on 8443", Function: "TCPReachable"},
+				{Function: "Ready"},
+				{Function: "TCPReachable"},
+			}}
+			results, err := shared.NewExpectationRunner(registry).RunStepCondition(cond, map[string]interface{}{"c1": map[string]interface{}{}})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(results.AllOf[0].Name).To(Equal("primary VIP reachable"))
+			Expect(results.AllOf[0].Description).To(Equal("VIP accepts TCP on 8443"))
+			Expect(shared.ToExpectationResultSummary(&results.AllOf[0]).Name).To(Equal("primary VIP reachable"))
+			Expect(shared.FailedExpectationLabels(results.All())).To(Equal("primary VIP reachable, TCPReachable"))
+		})
+
+		It("should assert on the scale subresource of a scalable resource", func() {
+			scheme := runtime.NewScheme()
+			Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+			replicas := int32(3)
+			deploy := &appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+				Spec:       appsv1.DeploymentSpec{Replicas: &replicas},
+				Status:     appsv1.DeploymentStatus{Replicas: 2},
+			}
+			c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(deploy).Build()
+			registry := plugin.NewRegistry()
+			builtins.RegisterCommon(registry)
+
+			cond := &infrav1alpha1.StepCondition{AllOf: []infrav1alpha1.Expectation{
+				{Function: "FieldCompare", Subresource: resource.SubresourceScale, Params: runtime.RawExtension{
+					Raw: []byte(`{"path":"spec.replicas","value":3}`),
+				}},
+				{Function: "FieldsMatchAcrossResources", Subresource: resource.SubresourceScale, Params: runtime.RawExtension{
+					Raw: []byte(`{"leftPath":"spec.replicas","rightPath":"status.replicas"}`),
+				}},
+			}}
+			state := map[string]interface{}{"apps/v1/Deployment/web": map[string]interface{}{
+				"apiVersion": "apps/v1", "kind": "Deployment",
+				"metadata": map[string]interface{}{"name": "web", "namespace": "default"},
+			}}
+			runner := shared.NewExpectationRunner(registry).WithSubresourceClient(context.Background(), c)
+			results, err := runner.RunStepCondition(cond, state)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(results.AllOf[0].Passed).To(BeTrue(), results.AllOf[0].Message)
+			Expect(results.AllOf[1].Passed).To(BeFalse())
+			Expect(results.AllOf[1].Actual).To(Equal("3 == 2"))
+		})
+
+		It("should schedule the next check from retry-after hints", func() {
+			registry := plugin.NewRegistry()
+			registry.Register("ClusterReady", func(_, _ map[string]interface{}) plugin.Result {
+				return plugin.Fail("cluster is provisioning").WithRetryAfter(20 * time.Minute)
+			})
+			runner := shared.NewExpectationRunner(registry)
+			results, err := runner.RunReadyCondition(&infrav1alpha1.ReadyCondition{
+				AllOf: []infrav1alpha1.Expectation{{Function: "ClusterReady"}},
+			}, map[string]interface{}{"c1": map[string]interface{}{}})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(results.All()[0].RetryAfterSeconds).To(Equal(int32(1200)))
+
+			// 提示被 MaxRetryAfterHint 与步骤截止时间截断，且不短于默认间隔
+			hint := shared.RetryAfterHint(results.All())
+			Expect(shared.HintedRequeue(hint, defaultRequeue, nil)).To(Equal(shared.MaxRetryAfterHint))
+			deadline := metav1.NewTime(time.Now().Add(3 * time.Minute))
+			Expect(shared.HintedRequeue(hint, defaultRequeue, &deadline)).To(BeNumerically("~", 3*time.Minute, time.Second))
+			Expect(shared.HintedRequeue(time.Second, defaultRequeue, nil)).To(Equal(defaultRequeue))
+
+			// 任一未通过的期望没有提示时按默认间隔轮询
+			mixed := append(results.All(), infrav1alpha1.ExpectationResult{Expect: "Other"})
+			Expect(shared.RetryAfterHint(mixed)).To(BeZero())
+		})
+
+		It("should recheck expectations before failing a step on timeout", func() {
+			start := time.Now()
+			step := infrav1alpha1.TestStep{Name: "wait-ready", ConfirmFailureSeconds: 30}
+			st := &infrav1alpha1.StepStatus{Name: "wait-ready"}
+
+			// 第一次观察：记录并等待复核
+			wait, confirmed := confirmFailure(st, step, "Ready", start)
+			Expect(confirmed).To(BeFalse())
+			Expect(wait).To(Equal(30 * time.Second))
+			Expect(st.FailureChecks).To(HaveLen(1))
+			Expect(st.FailureChecks[0].Failed).To(Equal("Ready"))
+
+			// 复核前不追加观察
+			wait, confirmed = confirmFailure(st, step, "Ready", start.Add(10*time.Second))
+			Expect(confirmed).To(BeFalse())
+			Expect(wait).To(BeNumerically("~", 20*time.Second, time.Second))
+			Expect(st.FailureChecks).To(HaveLen(1))
+
+			// 复核仍未满足：确认失败
+			_, confirmed = confirmFailure(st, step, "Ready", start.Add(31*time.Second))
+			Expect(confirmed).To(BeTrue())
+			Expect(st.FailureChecks).To(HaveLen(2))
+			Expect(st.FailureChecks[1].Passed).To(BeFalse())
+
+			// 未设置 confirmFailureSeconds 时立即失败
+			plain := &infrav1alpha1.StepStatus{Name: "wait-ready"}
+			_, confirmed = confirmFailure(plain, infrav1alpha1.TestStep{Name: "wait-ready"}, "Ready", start)
+			Expect(confirmed).To(BeTrue())
+			Expect(plain.FailureChecks).To(BeEmpty())
+		})
+	})
+
+	Context("When a step has retries", func() {
+		It("should re-execute the step with backoff before failing the test", func() {
+			it := &infrav1alpha1.IntegrationTest{Spec: infrav1alpha1.IntegrationTestSpec{Steps: []infrav1alpha1.TestStep{
+				{Name: "flaky", Retries: 2, RetryBackoffSeconds: 5},
+			}}}
+			now := metav1.Now()
+			it.Status.Phase = infrav1alpha1.IntegrationTestPhaseRunning
+			it.Status.Steps = []infrav1alpha1.StepStatus{{Name: "flaky", State: shared.StateRunning, StartedAt: &now}}
+			st := &it.Status.Steps[0]
+
+			// 第一次失败：记录尝试并在 5 秒后重试，测试保持运行
+			setStepFailed(it, st, "flaky", shared.ReasonConflict, "apply failed: conflict")
+			Expect(st.State).To(BeEmpty())
+			Expect(st.Reason).To(Equal(ReasonRetrying))
+			Expect(st.Attempts).To(HaveLen(1))
+			Expect(st.Attempts[0].State).To(Equal(shared.StateFailed))
+			Expect(st.Attempts[0].Reason).To(Equal(shared.ReasonConflict))
+			Expect(it.Status.Phase).To(Equal(infrav1alpha1.IntegrationTestPhaseRunning))
+			wait, retrying := pendingRetry(&it.Status)
+			Expect(retrying).To(BeTrue())
+			Expect(wait).To(BeNumerically("~", 5*time.Second, time.Second))
+			Expect(st.Deadline.Time).To(BeTemporally("~", st.StartedAt.Add(defaultStepTimeout), time.Second))
+
+			// 第二次失败：退避翻倍
+			st.State = shared.StateRunning
+			setStepFailed(it, st, "flaky", shared.ReasonTimeout, "expectations not satisfied before timeout: Ready")
+			Expect(st.Attempts).To(HaveLen(2))
+			Expect(retryWait(st, time.Now())).To(BeNumerically("~", 10*time.Second, time.Second))
+
+			// 重试次数用尽：步骤与测试失败，记录全部尝试
+			st.State = shared.StateRunning
+			setStepFailed(it, st, "flaky", shared.ReasonTimeout, "expectations not satisfied before timeout: Ready")
+			Expect(st.State).To(Equal(shared.StateFailed))
+			Expect(st.Attempts).To(HaveLen(3))
+			Expect(it.Status.Phase).To(Equal(infrav1alpha1.IntegrationTestPhaseFailed))
+			_, retrying = pendingRetry(&it.Status)
+			Expect(retrying).To(BeFalse())
+		})
+
+		It("should record the successful attempt and not retry permanent failures", func() {
+			it := &infrav1alpha1.IntegrationTest{Spec: infrav1alpha1.IntegrationTestSpec{Steps: []infrav1alpha1.TestStep{
+				{Name: "flaky", Retries: 3},
+			}}}
+			it.Status.Steps = []infrav1alpha1.StepStatus{{Name: "flaky", State: shared.StateRunning}}
+			st := &it.Status.Steps[0]
+
+			setStepFailed(it, st, "flaky", shared.ReasonFailed, "expectations error: connection refused")
+			Expect(retryWait(st, time.Now())).To(BeNumerically("~", defaultRetryBackoff, time.Second))
+			st.State = shared.StateRunning
+			setStepSucceeded(st)
+			Expect(st.Attempts).To(HaveLen(2))
+			Expect(st.Attempts[1].Attempt).To(Equal(int32(2)))
+			Expect(st.Attempts[1].State).To(Equal(shared.StateSucceeded))
+
+			// 确定性失败不重试
+			it.Status.Steps[0] = infrav1alpha1.StepStatus{Name: "flaky", State: shared.StateRunning}
+			setStepFailed(it, &it.Status.Steps[0], "flaky", shared.ReasonForbidden, "apply failed: forbidden")
+			Expect(it.Status.Steps[0].State).To(Equal(shared.StateFailed))
+			Expect(it.Status.Steps[0].Attempts).To(BeEmpty())
+
+			Expect(retryBackoff(infrav1alpha1.TestStep{RetryBackoffSeconds: 120}, 4)).To(Equal(maxRetryBackoff))
+		})
+	})
+
+	Context("When a test is a TestSuite template", func() {
+		It("should not execute it", func() {
+			ctx := context.Background()
+			scheme := runtime.NewScheme()
+			Expect(infrav1alpha1.AddToScheme(scheme)).To(Succeed())
+			it := &infrav1alpha1.IntegrationTest{
+				ObjectMeta: metav1.ObjectMeta{Name: "template", Namespace: "default",
+					Annotations: map[string]string{infrav1alpha1.AnnotationTemplate: "true"}},
+				Spec: infrav1alpha1.IntegrationTestSpec{Steps: []infrav1alpha1.TestStep{{Name: "create"}}},
+			}
+			c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(it).WithStatusSubresource(it).Build()
+			r := &IntegrationTestReconciler{Client: c, Scheme: scheme, PluginRegistry: plugin.NewRegistry()}
+
+			result, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(it)})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(Equal(ctrl.Result{}))
+
+			var got infrav1alpha1.IntegrationTest
+			Expect(c.Get(ctx, client.ObjectKeyFromObject(it), &got)).To(Succeed())
+			Expect(got.Finalizers).To(BeEmpty())
+			Expect(got.Status.Phase).To(BeEmpty())
+		})
+	})
+
+	Context("When a deleted test cleans up its resources", func() {
+		It("should delete dependents first and the rest in reverse creation order", func() {
+			ctx := context.Background()
+			scheme := runtime.NewScheme()
+			Expect(infrav1alpha1.AddToScheme(scheme)).To(Succeed())
+			Expect(corev1.AddToScheme(scheme)).To(Succeed())
+			now := metav1.Now()
+			it := &infrav1alpha1.IntegrationTest{
+				ObjectMeta: metav1.ObjectMeta{Name: "cleanup", Namespace: "default", UID: "it-uid",
+					DeletionTimestamp: &now, Finalizers: []string{integrationTestFinalizer}},
+			}
+			owned := func(name string, uid types.UID, owners ...types.UID) *corev1.ConfigMap {
+				cm := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", UID: uid}}
+				for _, owner := range owners {
+					cm.OwnerReferences = append(cm.OwnerReferences, metav1.OwnerReference{
+						APIVersion: "v1", Kind: "ConfigMap", Name: string(owner), UID: owner})
+				}
+				return cm
+			}
+			// dep 先创建但依赖 app；shared 不属于测试
+			objs := []*corev1.ConfigMap{
+				owned("dep", "dep", "it-uid", "app"),
+				owned("base", "base", "it-uid"),
+				owned("app", "app", "it-uid"),
+				owned("shared", "shared"),
+			}
+			for _, cm := range objs {
+				it.Spec.Steps = append(it.Spec.Steps, infrav1alpha1.TestStep{Name: cm.Name, Resource: &infrav1alpha1.ResourceRef{
+					Manifest: runtime.RawExtension{Raw: []byte(fmt.Sprintf(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":%q}}`, cm.Name))}}})
+			}
+			var deleted []string
+			c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(it, objs[0], objs[1], objs[2], objs[3]).
+				WithInterceptorFuncs(interceptor.Funcs{Delete: func(ctx context.Context, cl client.WithWatch, obj client.Object, opts ...client.DeleteOption) error {
+					deleted = append(deleted, obj.GetName())
+					return cl.Delete(ctx, obj, opts...)
+				}}).Build()
+			r := &IntegrationTestReconciler{Client: c, Scheme: scheme, PluginRegistry: plugin.NewRegistry()}
+
+			for i := 0; i < 3; i++ {
+				result, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(it)})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(result.RequeueAfter).To(Equal(defaultRequeue))
+			}
+			Expect(deleted).To(Equal([]string{"dep", "app", "base"}))
+
+			_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(it)})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(apierrors.IsNotFound(c.Get(ctx, client.ObjectKeyFromObject(it), &infrav1alpha1.IntegrationTest{}))).To(BeTrue())
+			Expect(c.Get(ctx, client.ObjectKey{Namespace: "default", Name: "shared"}, &corev1.ConfigMap{})).To(Succeed())
+		})
+	})
+
+	Context("When a step declares outputs", func() {
+		It("should extract outputs and substitute them into later manifests", func() {
+			registry := plugin.NewRegistry()
+			builtins.RegisterExtraction(registry)
+			r := &IntegrationTestReconciler{PluginRegistry: registry}
+
+			fieldPath := func(path string) infrav1alpha1.Extractor {
+				return infrav1alpha1.Extractor{Function: "FieldPath", Params: runtime.RawExtension{Raw: []byte(`{"path":"` + path + `"}`)}}
+			}
+			it := &infrav1alpha1.IntegrationTest{
+				ObjectMeta: metav1.ObjectMeta{Name: "outputs", Namespace: "default"},
+				Spec: infrav1alpha1.IntegrationTestSpec{Steps: []infrav1alpha1.TestStep{
+					{Name: "create", Outputs: []infrav1alpha1.StepOutput{
+						{Name: "endpoint", Extract: fieldPath("status.endpoint")},
+						{Name: "replicas", Extract: fieldPath("spec.replicas")},
+					}},
+					{Name: "probe", Resource: &infrav1alpha1.ResourceRef{Manifest: runtime.RawExtension{Raw: []byte(
+						`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"probe"},"data":{"url":"http://${steps.create.outputs.endpoint}/health","replicas":"${steps.create.outputs.replicas}"}}`)}}},
+				}},
+				Status: infrav1alpha1.IntegrationTestStatus{Steps: []infrav1alpha1.StepStatus{{Name: "create", State: shared.StateRunning}}},
+			}
+			cluster := map[string]interface{}{
+				"kind":     "Cluster",
+				"metadata": map[string]interface{}{"name": "demo", "namespace": "default"},
+				"spec":     map[string]interface{}{"replicas": int64(3)},
+				"status":   map[string]interface{}{},
+			}
+			state := map[string]interface{}{"demo": cluster}
+
+			// 提取结果为空时返回错误，步骤继续等待
+			_, err := r.extractStepOutputs(it, it.Spec.Steps[0], state)
+			Expect(err).To(MatchError(ContainSubstring("output endpoint: FieldPath returned no value")))
+
+			cluster["status"] = map[string]interface{}{"endpoint": "10.0.0.1:8080"}
+			outputs, err := r.extractStepOutputs(it, it.Spec.Steps[0], state)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(outputs).To(Equal(map[string]string{"kind": "Cluster", "name": "demo", "namespace": "default", "endpoint": "10.0.0.1:8080", "replicas": "3"}))
+
+			// 引用的步骤尚未记录输出时展开失败
+			_, err = r.expandStepResource(it, it.Spec.Steps[1])
+			Expect(err).To(MatchError(ContainSubstring("steps.create.outputs.endpoint")))
+
+			it.Status.Steps[0].Outputs = outputs
+			manifest, err := r.expandStepResource(it, it.Spec.Steps[1])
+			Expect(err).NotTo(HaveOccurred())
+			data, _, _ := unstructured.NestedStringMap(manifest.Object.Object, "data")
+			Expect(data).To(Equal(map[string]string{"url": "http://10.0.0.1:8080/health", "replicas": "3"}))
+		})
+	})
+
+	Context("When status is written", func() {
+		It("should apply step status and test status with distinct field owners", func() {
+			ctx := context.Background()
+			scheme := runtime.NewScheme()
+			Expect(infrav1alpha1.AddToScheme(scheme)).To(Succeed())
+			type write struct {
+				owner string
+				steps int
+				phase infrav1alpha1.IntegrationTestPhase
+			}
+			var writes []write
+			c := fake.NewClientBuilder().WithScheme(scheme).WithInterceptorFuncs(interceptor.Funcs{
+				SubResourcePatch: func(_ context.Context, _ client.Client, _ string, obj client.Object, patch client.Patch, opts ...client.SubResourcePatchOption) error {
+					Expect(patch.Type()).To(Equal(types.ApplyPatchType))
+					options := &client.SubResourcePatchOptions{}
+					options.ApplyOptions(opts)
+					status := obj.(*infrav1alpha1.IntegrationTest).Status
+					writes = append(writes, write{owner: options.FieldManager, steps: len(status.Steps), phase: status.Phase})
+					return nil
+				},
+			}).Build()
+			r := &IntegrationTestReconciler{Client: c, Scheme: scheme}
+
+			it := &infrav1alpha1.IntegrationTest{
+				ObjectMeta: metav1.ObjectMeta{Name: "owners", Namespace: "default", UID: "owners-uid"},
+				Status: infrav1alpha1.IntegrationTestStatus{Phase: infrav1alpha1.IntegrationTestPhaseRunning,
+					Steps: []infrav1alpha1.StepStatus{{Name: "create", State: shared.StateRunning}}},
+			}
+			Expect(r.patchStatus(ctx, it, it.Status)).To(Succeed())
+			// 步骤状态未变化：只写测试级字段
+			it.Status.Message = "waiting"
+			Expect(r.patchStatus(ctx, it, it.Status)).To(Succeed())
+			// 进入下一轮清空步骤状态：步骤级字段管理者写入空列表以释放所有权
+			it.Status.Steps = nil
+			Expect(r.patchStatus(ctx, it, it.Status)).To(Succeed())
+
+			running := infrav1alpha1.IntegrationTestPhaseRunning
+			Expect(writes).To(Equal([]write{
+				{owner: shared.FieldOwnerIntegrationTestSteps, steps: 1},
+				{owner: shared.FieldOwnerIntegrationTest, phase: running},
+				{owner: shared.FieldOwnerIntegrationTest, phase: running},
+				{owner: shared.FieldOwnerIntegrationTestSteps},
+				{owner: shared.FieldOwnerIntegrationTest, phase: running},
+			}))
+
+			// 进入终态后清除摘要，删除后同样清除
+			_, ok := r.stepDigests.Load(statusDigestKey(it))
+			Expect(ok).To(BeTrue())
+			it.Status.Phase = infrav1alpha1.IntegrationTestPhaseSucceeded
+			Expect(r.patchStatus(ctx, it, it.Status)).To(Succeed())
+			_, ok = r.stepDigests.Load(statusDigestKey(it))
+			Expect(ok).To(BeFalse())
+
+			it.Status.Phase = running
+			Expect(r.patchStatus(ctx, it, it.Status)).To(Succeed())
+			r.forgetStatusDigest(it)
+			_, ok = r.stepDigests.Load(statusDigestKey(it))
+			Expect(ok).To(BeFalse())
+		})
+	})
+
+	Context("When the test finishes", func() {
+		It("should record the summary condition and event in the terminal reconcile", func() {
+			ctx := context.Background()
+			scheme := runtime.NewScheme()
+			Expect(infrav1alpha1.AddToScheme(scheme)).To(Succeed())
+			Expect(corev1.AddToScheme(scheme)).To(Succeed())
+
+			start := metav1.NewTime(time.Now().Add(-90 * time.Second))
+			it := &infrav1alpha1.IntegrationTest{
+				ObjectMeta: metav1.ObjectMeta{Name: "summary", Namespace: "default", UID: "summary-uid",
+					Finalizers:  []string{integrationTestFinalizer},
+					Annotations: map[string]string{infrav1alpha1.AnnotationAbort: "true"}},
+				Spec: infrav1alpha1.IntegrationTestSpec{Steps: []infrav1alpha1.TestStep{{Name: "create"}, {Name: "verify"}, {Name: "cleanup"}}},
+				Status: infrav1alpha1.IntegrationTestStatus{
+					Phase:        infrav1alpha1.IntegrationTestPhaseRunning,
+					CurrentRound: 3,
+					StartTime:    &start,
+					Steps: []infrav1alpha1.StepStatus{
+						{Name: "create", Index: 0, State: shared.StateSucceeded},
+						{Name: "verify", Index: 1, State: shared.StateFailed},
+						{Name: "cleanup", Index: 2},
+					},
+				},
+			}
+			var summaries []*metav1.Condition
+			patches := 0
+			c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(it).WithInterceptorFuncs(interceptor.Funcs{
+				SubResourcePatch: func(_ context.Context, _ client.Client, _ string, obj client.Object, _ client.Patch, _ ...client.SubResourcePatchOption) error {
+					patches++
+					if cond := shared.GetCondition(obj.(*infrav1alpha1.IntegrationTest).Status.Conditions, shared.ConditionTypeSummary); cond != nil {
+						summaries = append(summaries, cond.DeepCopy())
+					}
+					return nil
+				},
+			}).Build()
+			recorder := record.NewFakeRecorder(10)
+			r := &IntegrationTestReconciler{Client: c, Scheme: scheme, PluginRegistry: plugin.NewRegistry(), Recorder: recorder}
+
+			// 中止后不再需要调和（无 postStep 与删除策略），摘要必须在本次调和中记录
+			result, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(it)})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(Equal(ctrl.Result{}))
+			Expect(summaries).NotTo(BeEmpty())
+			cond := summaries[len(summaries)-1]
+			Expect(cond.Reason).To(Equal("Aborted"))
+			Expect(cond.Message).To(MatchRegexp(`^rounds=3 steps=3 passed=1 failed=1 aborted=1 duration=1m3\ds$`))
+			Expect(<-recorder.Events).To(ContainSubstring(shared.EventReasonIntegrationTestAborted))
+			Expect(<-recorder.Events).To(And(HavePrefix("Warning "+shared.EventReasonTestSummary), ContainSubstring(cond.Message)))
+
+			// 已记录：之后的终态调和不再写入与发送事件
+			it.Status.Phase = infrav1alpha1.IntegrationTestPhaseAborted
+			it.Status.Conditions = []metav1.Condition{*cond}
+			written := patches
+			Expect(r.recordSummary(ctx, it)).To(Succeed())
+			Expect(patches).To(Equal(written))
+			Expect(recorder.Events).To(BeEmpty())
+		})
+	})
+
+	Context("When cleanupPolicy is set", func() {
+		It("should delete step resources by step policy and the rest when the test ends", func() {
+			ctx := context.Background()
+			scheme := runtime.NewScheme()
+			Expect(infrav1alpha1.AddToScheme(scheme)).To(Succeed())
+			Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+
+			owner := []metav1.OwnerReference{{APIVersion: infrav1alpha1.GroupVersion.String(), Kind: "IntegrationTest", Name: "cleanup", UID: "cleanup-uid"}}
+			var objs []client.Object
+			for _, name := range []string{"a", "b", "c"} {
+				objs = append(objs, &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", OwnerReferences: owner}})
+			}
+			c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).WithInterceptorFuncs(interceptor.Funcs{
+				SubResourcePatch: func(context.Context, client.Client, string, client.Object, client.Patch, ...client.SubResourcePatchOption) error {
+					return nil
+				},
+			}).Build()
+			r := &IntegrationTestReconciler{Client: c, Scheme: scheme, Recorder: record.NewFakeRecorder(10)}
+			r.ResourceManager = resource.NewManager(c, scheme, integrationTestFieldOwner, nil)
+
+			step := func(name string, policy infrav1alpha1.CleanupPolicy) infrav1alpha1.TestStep {
+				return infrav1alpha1.TestStep{Name: name, CleanupPolicy: policy, Resource: &infrav1alpha1.ResourceRef{
+					Manifest: runtime.RawExtension{Raw: []byte(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"` + name + `"}}`)}}}
+			}
+			it := &infrav1alpha1.IntegrationTest{
+				ObjectMeta: metav1.ObjectMeta{Name: "cleanup", Namespace: "default", UID: "cleanup-uid"},
+				Spec: infrav1alpha1.IntegrationTestSpec{
+					CleanupPolicy: infrav1alpha1.CleanupPolicyDeleteAlways,
+					Steps: []infrav1alpha1.TestStep{
+						step("a", infrav1alpha1.CleanupPolicyDeleteOnSuccess),
+						step("b", infrav1alpha1.CleanupPolicyKeep),
+						step("c", ""),
+					},
+				},
+				Status: infrav1alpha1.IntegrationTestStatus{Phase: infrav1alpha1.IntegrationTestPhaseRunning, CurrentRound: 1, Steps: []infrav1alpha1.StepStatus{
+					{Name: "a", Index: 0, State: shared.StateSucceeded},
+					{Name: "b", Index: 1, State: shared.StateSucceeded},
+					{Name: "c", Index: 2, State: shared.StateRunning},
+				}},
+			}
+			exists := func(name string) bool {
+				return c.Get(ctx, client.ObjectKey{Namespace: "default", Name: name}, &corev1.ConfigMap{}) == nil
+			}
+
+			// 步骤 a 成功后删除其资源，删除完成前保持 Running 等待
+			running, err := r.runCleanupPolicies(ctx, it)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(running).To(BeTrue())
+			Expect(it.Status.Steps[0].Cleanup.State).To(Equal(shared.StateRunning))
+			running, err = r.runCleanupPolicies(ctx, it)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(running).To(BeFalse())
+			Expect(it.Status.Steps[0].Cleanup.State).To(Equal(shared.StateSucceeded))
+			Expect(exists("a")).To(BeFalse())
+			Expect(exists("c")).To(BeTrue())
+			Expect(it.Status.Cleanup).To(BeNil())
+
+			// 测试结束后按测试级 DeleteAlways 删除未设置策略的步骤，Keep 的步骤保留
+			it.Status.Steps[2].State = shared.StateFailed
+			setTestFailed(&it.Status, shared.ReasonTimeout, "step c timed out")
+			Expect(testCleanupPending(it)).To(BeTrue())
+			for running = true; running; {
+				running, err = r.runCleanupPolicies(ctx, it)
+				Expect(err).NotTo(HaveOccurred())
+			}
+			Expect(it.Status.Cleanup.State).To(Equal(shared.StateSucceeded))
+			Expect(testCleanupPending(it)).To(BeFalse())
+			Expect(exists("b")).To(BeTrue())
+			Expect(exists("c")).To(BeFalse())
+		})
+	})
+
+	Context("When a test is initialized", func() {
+		It("should record the touched kinds in status and labels", func() {
+			ctx := context.Background()
+			scheme := runtime.NewScheme()
+			Expect(infrav1alpha1.AddToScheme(scheme)).To(Succeed())
+			Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+
+			manifest := func(apiVersion, kind string) runtime.RawExtension {
+				return runtime.RawExtension{Raw: []byte(`{"apiVersion":"` + apiVersion + `","kind":"` + kind + `","metadata":{"name":"x"}}`)}
+			}
+			stale := infrav1alpha1.LabelTouchedKindPrefix + "Job.batch"
+			it := &infrav1alpha1.IntegrationTest{
+				ObjectMeta: metav1.ObjectMeta{Name: "impact", Namespace: "default", Labels: map[string]string{"team": "infra", stale: "true"}},
+				Spec: infrav1alpha1.IntegrationTestSpec{Steps: []infrav1alpha1.TestStep{
+					{
+						Name:     "create",
+						PreStep:  []infrav1alpha1.ResourceRef{{Manifest: manifest("v1", "ConfigMap")}},
+						Resource: &infrav1alpha1.ResourceRef{Manifest: manifest("infra.example.io/v1", "Cluster")},
+					},
+					{
+						Name: "observe",
+						Resource: &infrav1alpha1.ResourceRef{Selector: &infrav1alpha1.ResourceSelector{
+							APIVersion: "apps/v1", Kind: "Deployment", Name: "x"}},
+						PostStep: []infrav1alpha1.ResourceRef{{Manifest: manifest("infra.example.io/v1", "Cluster")}},
+					},
+				}},
+			}
+			c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(it).WithInterceptorFuncs(interceptor.Funcs{
+				SubResourcePatch: func(context.Context, client.Client, string, client.Object, client.Patch, ...client.SubResourcePatchOption) error {
+					return nil
+				},
+			}).Build()
+			r := &IntegrationTestReconciler{Client: c, Scheme: scheme, Recorder: record.NewFakeRecorder(10)}
+
+			_, err := r.initializeTest(ctx, it)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(it.Status.TouchedKinds).To(Equal([]string{"Cluster.infra.example.io", "ConfigMap", "Deployment.apps"}))
+
+			// 标签只增删 touches 前缀的键，其余标签保持不变
+			var stored infrav1alpha1.IntegrationTest
+			Expect(c.Get(ctx, client.ObjectKeyFromObject(it), &stored)).To(Succeed())
+			Expect(stored.Labels).To(Equal(map[string]string{
+				"team": "infra",
+				infrav1alpha1.LabelTouchedKindPrefix + "Cluster.infra.example.io": "true",
+				infrav1alpha1.LabelTouchedKindPrefix + "ConfigMap":                "true",
+				infrav1alpha1.LabelTouchedKindPrefix + "Deployment.apps":          "true",
+			}))
+			Expect(it.Labels).To(Equal(stored.Labels))
+		})
+	})
+
+	Context("When a step declares preStep and postStep manifests", func() {
+		It("should run preStep before the step and postStep even after the step fails", func() {
+			ctx := context.Background()
+			scheme := runtime.NewScheme()
+			Expect(infrav1alpha1.AddToScheme(scheme)).To(Succeed())
+			Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+			var calls []string
+			seed := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "seed", Namespace: "default"}}
+			c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(seed).WithInterceptorFuncs(interceptor.Funcs{
+				Patch: func(_ context.Context, _ client.WithWatch, obj client.Object, _ client.Patch, _ ...client.PatchOption) error {
+					calls = append(calls, "apply:"+obj.GetName())
+					if obj.GetName() == "broken" {
+						return apierrors.NewForbidden(corev1.Resource("configmaps"), "broken", fmt.Errorf("denied"))
+					}
+					return nil
+				},
+				Delete: func(_ context.Context, _ client.WithWatch, obj client.Object, _ ...client.DeleteOption) error {
+					calls = append(calls, "delete:"+obj.GetName())
+					return nil
+				},
+				SubResourcePatch: func(context.Context, client.Client, string, client.Object, client.Patch, ...client.SubResourcePatchOption) error {
+					return nil
+				},
+			}).Build()
+			recorder := record.NewFakeRecorder(10)
+			r := &IntegrationTestReconciler{Client: c, Scheme: scheme, Recorder: recorder}
+			r.ResourceManager = resource.NewManager(c, scheme, integrationTestFieldOwner, nil)
+
+			configMap := func(name string) runtime.RawExtension {
+				return runtime.RawExtension{Raw: []byte(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"` + name + `"}}`)}
+			}
+			it := &infrav1alpha1.IntegrationTest{
+				ObjectMeta: metav1.ObjectMeta{Name: "hooked", Namespace: "default", UID: "hooked-uid"},
+				Spec: infrav1alpha1.IntegrationTestSpec{Steps: []infrav1alpha1.TestStep{{
+					Name:    "migrate",
+					PreStep: []infrav1alpha1.ResourceRef{{Manifest: configMap("seed")}},
+					PostStep: []infrav1alpha1.ResourceRef{
+						{Manifest: configMap("seed"), Action: infrav1alpha1.TemplateActionDelete},
+						{Manifest: configMap("broken")},
+					},
+				}}},
+				Status: infrav1alpha1.IntegrationTestStatus{Phase: infrav1alpha1.IntegrationTestPhaseRunning, CurrentRound: 1,
+					Steps: []infrav1alpha1.StepStatus{{Name: "migrate", State: shared.StateRunning}}},
+			}
+			st := &it.Status.Steps[0]
+
+			Expect(r.runPreStep(ctx, it, it.Spec.Steps[0], st)).To(Succeed())
+			Expect(st.PreStep.State).To(Equal(shared.StateSucceeded))
+			// 已成功的 preStep 不重复执行
+			Expect(r.runPreStep(ctx, it, it.Spec.Steps[0], st)).To(Succeed())
+			Expect(calls).To(Equal([]string{"apply:seed"}))
+
+			// 步骤未结束时不执行 postStep
+			Expect(r.runPostSteps(ctx, it)).To(Succeed())
+			Expect(st.PostStep).To(BeNil())
+
+			// 步骤失败后执行全部 postStep，单个清单失败不影响其余清单，也不改变步骤结果
+			setStepFailed(it, st, "migrate", shared.ReasonTimeout, "expectations not satisfied before timeout")
+			Expect(pendingPostSteps(it)).To(BeTrue())
+			Expect(r.runPostSteps(ctx, it)).To(Succeed())
+			Expect(calls).To(Equal([]string{"apply:seed", "delete:seed", "apply:broken"}))
+			Expect(st.PostStep.State).To(Equal(shared.StateFailed))
+			Expect(st.PostStep.Message).To(ContainSubstring("[1]"))
+			Expect(st.State).To(Equal(shared.StateFailed))
+			Expect(recorder.Events).To(Receive(ContainSubstring(shared.EventReasonStepHookFailed)))
+
+			// postStep 只执行一次
+			Expect(r.runPostSteps(ctx, it)).To(Succeed())
+			Expect(calls).To(HaveLen(3))
+			Expect(pendingPostSteps(it)).To(BeFalse())
+		})
+	})
+
+	Context("When a step references a base manifest with patches", func() {
+		It("should patch the base from the ConfigMap before expanding the step", func() {
+			ctx := context.Background()
+			scheme := runtime.NewScheme()
+			Expect(infrav1alpha1.AddToScheme(scheme)).To(Succeed())
+			Expect(corev1.AddToScheme(scheme)).To(Succeed())
+			base := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "bases", Namespace: "default"}, Data: map[string]string{
+				"web.yaml": "apiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: web\nspec:\n  replicas: 1\n",
+			}}
+			it := &infrav1alpha1.IntegrationTest{
+				ObjectMeta: metav1.ObjectMeta{Name: "patched", Namespace: "default"},
+				Spec: infrav1alpha1.IntegrationTestSpec{Steps: []infrav1alpha1.TestStep{{Name: "deploy", Resource: &infrav1alpha1.ResourceRef{
+					Base: &infrav1alpha1.ManifestBase{ConfigMapRef: infrav1alpha1.ConfigMapKeyReference{Name: "bases", Key: "web.yaml"}},
+					Patches: []infrav1alpha1.JSONPatchOperation{
+						{Op: "replace", Path: "/spec/replicas", Value: &runtime.RawExtension{Raw: []byte(`3`)}},
+						{Op: "add", Path: "/metadata/labels", Value: &runtime.RawExtension{Raw: []byte(`{"tier":"web"}`)}},
+					},
+				}}}},
+			}
+			r := &IntegrationTestReconciler{Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(base).Build(), Scheme: scheme}
+
+			Expect(r.resolveManifests(ctx, it)).To(Succeed())
+			Expect(it.Spec.Steps[0].Resource.Base).To(BeNil())
+			manifest, err := r.expandStepResource(it, it.Spec.Steps[0])
+			Expect(err).NotTo(HaveOccurred())
+			Expect(manifest.Object.GetKind()).To(Equal("Deployment"))
+			Expect(manifest.Object.GetLabels()).To(Equal(map[string]string{"tier": "web"}))
+			Expect(manifest.Object.Object["spec"]).To(HaveKeyWithValue("replicas", BeNumerically("==", 3)))
+
+			missing := it.DeepCopy()
+			missing.Spec.Steps[0].Resource = &infrav1alpha1.ResourceRef{
+				Base: &infrav1alpha1.ManifestBase{ConfigMapRef: infrav1alpha1.ConfigMapKeyReference{Name: "bases", Key: "db.yaml"}}}
+			Expect(r.resolveManifests(ctx, missing)).To(MatchError(ContainSubstring(`has no key "db.yaml"`)))
+		})
+	})
 })
+
+// recordingHook 记录钩子调用顺序。
+type recordingHook struct {
+	calls []string
+}
+
+func (h *recordingHook) OnStepStart(_ context.Context, e hooks.StepEvent) {
+	h.calls = append(h.calls, "start:"+e.Step.Name)
+}
+
+func (h *recordingHook) OnStepFinish(_ context.Context, e hooks.StepEvent) {
+	h.calls = append(h.calls, "finish:"+e.Step.Name)
+}
+
+func (h *recordingHook) OnTestFinish(_ context.Context, e hooks.TestEvent) {
+	h.calls = append(h.calls, "test:"+e.Phase)
+}
@@ -0,0 +1,99 @@
+package integrationtest
+
+import (
+	"context"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	infrav1alpha1 "github.com/lunz1207/testplane/api/v1alpha1"
+	"github.com/lunz1207/testplane/internal/controller/shared/resource"
+)
+
+// ledgerEntry 返回步骤 apply 前应写入台账的记录。删除清单与 race 步骤重复执行无副作用或自行记录结果，不写台账。
+func ledgerEntry(round int, step infrav1alpha1.TestStep, manifest *resource.ExpandedManifest) (infrav1alpha1.ApplyLedgerEntry, bool) {
+	if manifest == nil || manifest.IsDelete() || step.Race != nil {
+		return infrav1alpha1.ApplyLedgerEntry{}, false
+	}
+	return infrav1alpha1.ApplyLedgerEntry{Step: step.Name, Round: round, Hash: resource.ManifestHash(manifest)}, true
+}
+
+// recordApplyLedger 把 apply 意图写入台账并丢弃其他轮次的记录（调用方负责在 apply 之前 patch），返回台账是否变化。
+func recordApplyLedger(status *infrav1alpha1.IntegrationTestStatus, entries []infrav1alpha1.ApplyLedgerEntry) bool {
+	changed := false
+	ledger := status.ApplyLedger[:0:0]
+	for _, e := range status.ApplyLedger {
+		if e.Round != status.CurrentRound {
+			changed = true
+			continue
+		}
+		ledger = append(ledger, e)
+	}
+	for _, entry := range entries {
+		found := false
+		for i := range ledger {
+			if ledger[i].Step == entry.Step {
+				found = true
+				if ledger[i] != entry {
+					ledger[i] = entry
+					changed = true
+				}
+			}
+		}
+		if !found {
+			ledger = append(ledger, entry)
+			changed = true
+		}
+	}
+	if changed {
+		status.ApplyLedger = ledger
+	}
+	return changed
+}
+
+// applyStepOnce apply 步骤资源，对象上记录清单哈希注解。
+// 台账中本轮已有该步骤同一清单的记录、且对象上的哈希注解一致时，说明上一个 leader 已 apply
+// 但未来得及写入步骤状态，跳过 apply 直接从中断处继续。
+func (r *IntegrationTestReconciler) applyStepOnce(ctx context.Context, it *infrav1alpha1.IntegrationTest, step infrav1alpha1.TestStep, stepStatus *infrav1alpha1.StepStatus, manifest *resource.ExpandedManifest) error {
+	entry, ok := ledgerEntry(it.Status.CurrentRound, step, manifest)
+	if !ok {
+		return r.applyStepResource(ctx, it, step, stepStatus, manifest)
+	}
+	if r.alreadyApplied(ctx, it, entry, manifest) {
+		logf.FromContext(ctx).Info("resuming step applied before restart", "step", step.Name, "hash", entry.Hash)
+		return nil
+	}
+	annotations := manifest.Object.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[infrav1alpha1.AnnotationManifestHash] = entry.Hash
+	manifest.Object.SetAnnotations(annotations)
+	return r.applyStepResource(ctx, it, step, stepStatus, manifest)
+}
+
+// alreadyApplied 判断台账记录的 apply 是否已经生效：只在台账中有本轮同一清单的记录时读取对象元数据。
+func (r *IntegrationTestReconciler) alreadyApplied(ctx context.Context, it *infrav1alpha1.IntegrationTest, entry infrav1alpha1.ApplyLedgerEntry, manifest *resource.ExpandedManifest) bool {
+	recorded := false
+	for _, e := range it.Status.ApplyLedger {
+		if e == entry {
+			recorded = true
+			break
+		}
+	}
+	if !recorded {
+		return false
+	}
+	reader := r.APIReader
+	if reader == nil {
+		reader = r.Client
+	}
+	meta := &metav1.PartialObjectMetadata{}
+	meta.SetGroupVersionKind(manifest.Object.GroupVersionKind())
+	key := client.ObjectKey{Namespace: manifest.Object.GetNamespace(), Name: manifest.Object.GetName()}
+	if err := reader.Get(ctx, key, meta); err != nil {
+		return false
+	}
+	return meta.GetDeletionTimestamp() == nil && meta.GetAnnotations()[infrav1alpha1.AnnotationManifestHash] == entry.Hash
+}
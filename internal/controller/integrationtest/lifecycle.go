@@ -2,24 +2,34 @@ package integrationtest
 
 import (
 	"context"
+	"time"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
 
 	infrav1alpha1 "github.com/lunz1207/testplane/api/v1alpha1"
 	"github.com/lunz1207/testplane/internal/controller/shared"
+	"github.com/lunz1207/testplane/pkg/impact"
 )
 
 // 注意：发送 Event 前先用 APIReader 检查 API Server 最新状态，避免缓存延迟导致重复事件
 
 // lifecycle.go 包含 IntegrationTest 资源的生命周期管理和状态设置函数
 
-// initializeTest 初始化测试状态并持久化。
+// initializeTest 初始化测试状态（含创建工作区）并持久化。
 func (r *IntegrationTestReconciler) initializeTest(ctx context.Context, it *infrav1alpha1.IntegrationTest) (ctrl.Result, error) {
 	now := metav1.Now()
 	it.Status.Phase = infrav1alpha1.IntegrationTestPhasePending
 	it.Status.StartTime = &now
 	it.Status.ObservedGeneration = it.Generation
+	it.Status.SpecDigest = specDigest(it.Spec)
+	it.Status.TouchedKinds = impact.IntegrationTest(&it.Spec)
+	if err := shared.SyncPrefixedLabels(ctx, r.Client, it, infrav1alpha1.LabelTouchedKindPrefix, impact.Labels(it.Status.TouchedKinds)); err != nil {
+		return ctrl.Result{}, err
+	}
+	if err := r.ensureWorkspace(ctx, it); err != nil {
+		return ctrl.Result{}, err
+	}
 
 	if err := r.patchStatus(ctx, it, it.Status); err != nil {
 		return ctrl.Result{}, err
@@ -33,26 +43,47 @@ func setStepSucceeded(stepStatus *infrav1alpha1.StepStatus) {
 	stepStatus.State = shared.StateSucceeded
 	stepStatus.Reason = shared.ReasonSucceeded
 	now := metav1.Now()
+	// 重试后成功：记录最后一次尝试
+	if len(stepStatus.Attempts) > 0 {
+		recordStepAttempt(stepStatus, shared.StateSucceeded, "", "", now.Time)
+	}
 	stepStatus.FinishedAt = &now
 }
 
-// setStepFailed 设置步骤为失败状态。
-func setStepFailed(status *infrav1alpha1.IntegrationTestStatus, stepStatus *infrav1alpha1.StepStatus, stepName, reason, message string) {
+// setStepFailed 设置步骤为失败状态，测试随之失败。
+// 步骤设置了 retries 且还有剩余次数时改为安排重试：步骤状态重置，测试保持 Running。
+// 设置 spec.debug.breakOnFailure 时测试改为停在断点：保持 Running 阶段，原因为 Breakpoint，等待恢复或中止。
+func setStepFailed(it *infrav1alpha1.IntegrationTest, stepStatus *infrav1alpha1.StepStatus, stepName, reason, message string) {
+	if scheduleStepRetry(it, stepStatus, reason, message, time.Now()) {
+		return
+	}
+	status := &it.Status
 	stepStatus.State = shared.StateFailed
 	stepStatus.Reason = reason
 	stepStatus.Message = message
 	now := metav1.Now()
 	stepStatus.FinishedAt = &now
+	status.Message = "step " + stepName + " failed: " + message
+
+	if it.Spec.Debug != nil && it.Spec.Debug.BreakOnFailure {
+		status.Reason = ReasonBreakpoint
+		status.Breakpoint = &infrav1alpha1.BreakpointStatus{Round: status.CurrentRound, Step: stepName, Reason: reason, Message: message, At: &now}
+		return
+	}
 
 	status.Phase = infrav1alpha1.IntegrationTestPhaseFailed
 	status.CompletionTime = &now
-	// 传递实际的失败原因（如 Timeout、Failed 等）
-	if reason == shared.ReasonTimeout {
+	// 传递实际的失败原因（如 Timeout、BudgetExceeded 等）
+	switch reason {
+	case shared.ReasonTimeout:
 		status.Reason = "Timeout"
-	} else {
+	case shared.ReasonBudgetExceeded:
+		status.Reason = shared.ReasonBudgetExceeded
+	case shared.ReasonAPIRemoved:
+		status.Reason = shared.ReasonAPIRemoved
+	default:
 		status.Reason = "StepFailed"
 	}
-	status.Message = "step " + stepName + " failed: " + message
 }
 
 // setSucceeded 设置 IntegrationTest 为成功状态。
@@ -62,6 +93,15 @@ func setSucceeded(status *infrav1alpha1.IntegrationTestStatus) {
 	status.CompletionTime = &now
 }
 
+// setTestFailed 设置 IntegrationTest 为失败状态（非步骤失败，如预检或汇总统计期望不满足）。
+func setTestFailed(status *infrav1alpha1.IntegrationTestStatus, reason, message string) {
+	status.Phase = infrav1alpha1.IntegrationTestPhaseFailed
+	now := metav1.Now()
+	status.CompletionTime = &now
+	status.Reason = reason
+	status.Message = message
+}
+
 // finishTest 完成测试，根据当前状态设置最终结果。
 // 先 patch 状态，成功后再发送 Event。
 func (r *IntegrationTestReconciler) finishTest(ctx context.Context, it *infrav1alpha1.IntegrationTest) (ctrl.Result, error) {
@@ -70,10 +110,26 @@ func (r *IntegrationTestReconciler) finishTest(ctx context.Context, it *infrav1a
 		return ctrl.Result{}, nil
 	}
 
+	// 判定成功前断言汇总统计，不满足时以失败结束
+	failedMsg, err := r.checkStatsExpectations(ctx, it)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if failedMsg != "" {
+		setTestFailed(&it.Status, ReasonStatsExpectationsFailed, failedMsg)
+		if err := r.patchStatus(ctx, it, it.Status); err != nil {
+			return ctrl.Result{}, err
+		}
+		r.emitWarningEvent(it, -1, shared.EventReasonIntegrationTestFailed, "汇总统计期望不满足: "+failedMsg)
+		// 失败分诊由下一次调和处理；status 写入不会触发 watch，需显式 Requeue
+		return ctrl.Result{Requeue: true}, nil
+	}
+
 	setSucceeded(&it.Status)
 	if err := r.patchStatus(ctx, it, it.Status); err != nil {
 		return ctrl.Result{}, err
 	}
-	shared.EmitNormalEvent(r.Recorder, it, shared.EventReasonIntegrationTestSucceeded, "测试用例执行成功")
-	return ctrl.Result{}, nil
+	r.emitNormalEvent(it, -1, shared.EventReasonIntegrationTestSucceeded, "测试用例执行成功")
+	// cleanupPolicy 删除由下一次调和执行；status 写入不会触发 watch，需显式 Requeue
+	return ctrl.Result{Requeue: testCleanupPending(it)}, nil
 }
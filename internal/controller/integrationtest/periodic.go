@@ -0,0 +1,219 @@
+package integrationtest
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	infrav1alpha1 "github.com/lunz1207/testplane/api/v1alpha1"
+	"github.com/lunz1207/testplane/internal/controller/shared"
+	"github.com/lunz1207/testplane/internal/controller/shared/logging"
+)
+
+// maxStepIterations 周期步骤保留的迭代记录数。
+const maxStepIterations = 10
+
+// runPeriodicSteps 推进本轮已成功的周期步骤（periodicSeconds > 0）：
+// 到期时重新应用资源开始新迭代，进行中的迭代等待收敛并检查期望。
+// 状态有变化时先 patch，成功后再发 Event；任一迭代失败时测试失败，返回 true。
+// 本轮所有步骤完成后不再推进，未完成的迭代不计入结果。
+func (r *IntegrationTestReconciler) runPeriodicSteps(ctx context.Context, it *infrav1alpha1.IntegrationTest) (bool, error) {
+	var changed []int
+	for i, step := range it.Spec.Steps {
+		if step.PeriodicSeconds <= 0 || i >= len(it.Status.Steps) {
+			continue
+		}
+		stepStatus := &it.Status.Steps[i]
+		if stepStatus.State != shared.StateSucceeded {
+			continue
+		}
+		// 缓存尚未同步上一次 status 写入：迭代已推进，避免重复 apply 和重复事件
+		if r.iterationAlreadyAdvanced(ctx, it, stepStatus) {
+			continue
+		}
+		if r.advancePeriodicStep(ctx, it, stepStatus, step) {
+			changed = append(changed, i)
+		}
+	}
+	if len(changed) == 0 {
+		return false, nil
+	}
+
+	if err := r.patchStatus(ctx, it, it.Status); err != nil {
+		return false, err
+	}
+
+	failed := false
+	for _, i := range changed {
+		stepStatus := &it.Status.Steps[i]
+		last := lastIteration(stepStatus)
+		switch last.State {
+		case shared.StateSucceeded:
+			r.emitIterationEvent(it, i, last.Iteration, corev1.EventTypeNormal, shared.EventReasonStepIterationSucceeded,
+				fmt.Sprintf("[Round %d] 步骤 %s 第 %d 次迭代成功", it.Status.CurrentRound, stepStatus.Name, last.Iteration))
+		case shared.StateFailed:
+			failed = true
+			r.emitIterationEvent(it, i, last.Iteration, corev1.EventTypeWarning, shared.EventReasonStepIterationFailed,
+				fmt.Sprintf("[Round %d] 步骤 %s 第 %d 次迭代失败: %s", it.Status.CurrentRound, stepStatus.Name, last.Iteration, last.Message))
+		}
+	}
+	return failed, nil
+}
+
+// advancePeriodicStep 推进单个周期步骤，返回状态是否变化。
+// 迭代失败时将步骤与测试标记为失败。
+func (r *IntegrationTestReconciler) advancePeriodicStep(ctx context.Context, it *infrav1alpha1.IntegrationTest, stepStatus *infrav1alpha1.StepStatus, step infrav1alpha1.TestStep) bool {
+	log := logging.WithStep(logging.WithRound(logf.FromContext(ctx), it.Status.CurrentRound), step.Name, stepStatus.Index)
+
+	last := lastIteration(stepStatus)
+	if last == nil || last.State != shared.StateRunning {
+		if !periodicIterationDue(stepStatus, step, time.Now()) {
+			return false
+		}
+		return r.startIteration(ctx, it, stepStatus, step)
+	}
+
+	manifest, err := r.expandStepResource(it, step)
+	if err != nil {
+		r.finishIteration(it, stepStatus, step, shared.ReasonFailed, fmt.Sprintf("expand manifest failed: %v", err))
+		return true
+	}
+	deadline := last.StartedAt.Add(stepTimeout(step))
+
+	if manifest != nil {
+		if err := r.waitResourceConverge(ctx, manifest); err != nil {
+			if time.Now().After(deadline) {
+				r.finishIteration(it, stepStatus, step, shared.ReasonTimeout, fmt.Sprintf("resource not converged before timeout: %v", err))
+				return true
+			}
+			logging.WaitingFor(log, "iteration convergence", "iteration", last.Iteration)
+			return false
+		}
+	}
+
+	if step.Expectations != nil {
+		built, err := r.buildStepState(ctx, it, selectorsFromStep(step), expectationsFromStepCondition(step.Expectations), manifest)
+		if err != nil {
+			r.finishIteration(it, stepStatus, step, shared.ReasonFailed, fmt.Sprintf("gather state failed: %v", err))
+			return true
+		}
+		passed, failed := false, ""
+		if !built.Waiting {
+			results, err := r.runExpectations(ctx, it, step.Expectations, built.State, nil)
+			if err != nil {
+				r.finishIteration(it, stepStatus, step, shared.ReasonFailed, fmt.Sprintf("expectations error: %v", err))
+				return true
+			}
+			passed, failed = results.Passed(), shared.FailedExpectationLabels(results.All())
+			shared.SetExpectationWarnings(&it.Status.Conditions, "step "+step.Name, results.All(), it.Generation)
+		}
+		if !passed {
+			if time.Now().After(deadline) {
+				msg := "expectations not satisfied before timeout"
+				if failed != "" {
+					msg += ": " + failed
+				}
+				r.finishIteration(it, stepStatus, step, shared.ReasonTimeout, msg)
+				return true
+			}
+			logging.WaitingFor(log, "iteration expectations", "iteration", last.Iteration)
+			return false
+		}
+	}
+
+	r.finishIteration(it, stepStatus, step, shared.ReasonSucceeded, "")
+	log.Info("periodic step iteration succeeded", "iteration", last.Iteration)
+	return true
+}
+
+// startIteration 重新应用步骤资源并记录新迭代，返回状态是否变化。
+func (r *IntegrationTestReconciler) startIteration(ctx context.Context, it *infrav1alpha1.IntegrationTest, stepStatus *infrav1alpha1.StepStatus, step infrav1alpha1.TestStep) bool {
+	now := metav1.Now()
+	stepStatus.IterationCount++
+	appendIteration(stepStatus, infrav1alpha1.StepIteration{
+		Iteration: stepStatus.IterationCount,
+		State:     shared.StateRunning,
+		StartedAt: &now,
+	})
+
+	manifest, err := r.expandStepResource(it, step)
+	if err != nil {
+		r.finishIteration(it, stepStatus, step, shared.ReasonFailed, fmt.Sprintf("expand manifest failed: %v", err))
+		return true
+	}
+	if manifest != nil {
+		if err := r.applyStepResource(ctx, it, step, stepStatus, manifest); err != nil {
+			r.finishIteration(it, stepStatus, step, r.applyFailureReason(ctx, manifest, err), fmt.Sprintf("apply failed: %v", err))
+			return true
+		}
+	}
+	return true
+}
+
+// finishIteration 结束最近一次迭代；reason 非 Succeeded 时步骤与测试失败。
+func (r *IntegrationTestReconciler) finishIteration(it *infrav1alpha1.IntegrationTest, stepStatus *infrav1alpha1.StepStatus, step infrav1alpha1.TestStep, reason, message string) {
+	last := lastIteration(stepStatus)
+	now := metav1.Now()
+	last.FinishedAt = &now
+	if reason == shared.ReasonSucceeded {
+		last.State = shared.StateSucceeded
+		return
+	}
+	last.State = shared.StateFailed
+	last.Message = message
+	setStepFailed(it, stepStatus, step.Name, reason, fmt.Sprintf("iteration %d: %s", last.Iteration, message))
+}
+
+// periodicIterationDue 判断是否到达下一次迭代时间：以上一次迭代开始时间（或步骤首次完成时间）为基准。
+func periodicIterationDue(stepStatus *infrav1alpha1.StepStatus, step infrav1alpha1.TestStep, now time.Time) bool {
+	base := stepStatus.FinishedAt
+	if last := lastIteration(stepStatus); last != nil {
+		base = last.StartedAt
+	}
+	if base == nil {
+		return false
+	}
+	return !now.Before(base.Add(time.Duration(step.PeriodicSeconds) * time.Second))
+}
+
+// appendIteration 追加迭代记录，只保留最近 maxStepIterations 条。
+func appendIteration(stepStatus *infrav1alpha1.StepStatus, iteration infrav1alpha1.StepIteration) {
+	stepStatus.Iterations = append(stepStatus.Iterations, iteration)
+	if n := len(stepStatus.Iterations); n > maxStepIterations {
+		stepStatus.Iterations = stepStatus.Iterations[n-maxStepIterations:]
+	}
+}
+
+// lastIteration 返回最近一次迭代，没有迭代时返回 nil。
+func lastIteration(stepStatus *infrav1alpha1.StepStatus) *infrav1alpha1.StepIteration {
+	if len(stepStatus.Iterations) == 0 {
+		return nil
+	}
+	return &stepStatus.Iterations[len(stepStatus.Iterations)-1]
+}
+
+// iterationAlreadyAdvanced 检查 API Server 上该步骤的迭代是否已比本地状态更新。
+func (r *IntegrationTestReconciler) iterationAlreadyAdvanced(ctx context.Context, it *infrav1alpha1.IntegrationTest, stepStatus *infrav1alpha1.StepStatus) bool {
+	latest := r.latestStatus(ctx, it)
+	if latest == nil || latest.CurrentRound != it.Status.CurrentRound || stepStatus.Index >= len(latest.Steps) {
+		return false
+	}
+	latestStep := &latest.Steps[stepStatus.Index]
+	if latestStep.IterationCount != stepStatus.IterationCount {
+		return latestStep.IterationCount > stepStatus.IterationCount
+	}
+	ours, theirs := lastIteration(stepStatus), lastIteration(latestStep)
+	return ours != nil && theirs != nil && ours.State == shared.StateRunning && theirs.State != shared.StateRunning
+}
+
+// emitIterationEvent 发送周期迭代事件：每次迭代独立幂等，同一步骤的迭代合并为一个 Event 系列。
+func (r *IntegrationTestReconciler) emitIterationEvent(it *infrav1alpha1.IntegrationTest, stepIndex int, iteration int32, eventType, reason, message string) {
+	step := strconv.Itoa(stepIndex) + "-iteration"
+	key := shared.EventKey(it, it.Status.CurrentRound, step+"-"+strconv.Itoa(int(iteration)), reason)
+	shared.EmitSeriesEvent(r.Recorder, it, key, shared.EventSeriesKey(it, step, reason), eventType, reason, message)
+}
@@ -0,0 +1,248 @@
+package integrationtest
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	infrav1alpha1 "github.com/lunz1207/testplane/api/v1alpha1"
+)
+
+// ReasonPreflightFailed 预检未通过时测试的失败原因。
+const ReasonPreflightFailed = "PreflightFailed"
+
+// preflightDialTimeout 检查 Webhook 地址可达的连接超时。
+const preflightDialTimeout = 3 * time.Second
+
+// preflight 在 Pending → Running 之前检查配置错误，使其在数秒内失败而不是等到步骤超时：
+//   - 步骤清单引用的 Secret/ConfigMap（卷、envFrom、secretKeyRef 等，optional 除外）存在，由前序步骤创建的除外
+//   - 步骤选择器（含金丝雀比较的基线与金丝雀选择器）的 apiVersion/kind 在 API 发现中存在
+//   - 期望声明的 Webhook 地址可以建立 TCP 连接
+//   - 设置 strictSchema 时，步骤清单通过服务端 dry-run 的严格字段校验
+//   - 设置 scopedRBAC 时，步骤清单只写入测试所在命名空间的命名空间级资源，且同名身份对象不存在或由该测试控制
+//
+// 返回所有问题（以 "; " 连接），全部通过时返回空字符串。
+func (r *IntegrationTestReconciler) preflight(ctx context.Context, it *infrav1alpha1.IntegrationTest) (string, error) {
+	var problems []string
+
+	refs, err := r.missingConfigRefs(ctx, it)
+	if err != nil {
+		return "", err
+	}
+	problems = append(problems, refs...)
+
+	for _, step := range it.Spec.Steps {
+		selectors := selectorsFromStep(step)
+		if step.Canary != nil {
+			selectors = append(selectors, step.Canary.Baseline, step.Canary.Canary)
+		}
+		for _, sel := range selectors {
+			gv, err := schema.ParseGroupVersion(sel.APIVersion)
+			if err != nil {
+				problems = append(problems, fmt.Sprintf("step %s: invalid selector apiVersion %q", step.Name, sel.APIVersion))
+				continue
+			}
+			if _, err := r.RESTMapper().RESTMapping(gv.WithKind(sel.Kind).GroupKind(), gv.Version); err != nil {
+				problems = append(problems, fmt.Sprintf("step %s: selector kind %s/%s not found in discovery", step.Name, sel.APIVersion, sel.Kind))
+			}
+		}
+	}
+
+	for _, endpoint := range webhookEndpoints(it) {
+		if err := dialEndpoint(ctx, endpoint); err != nil {
+			problems = append(problems, fmt.Sprintf("webhook %s unreachable: %v", endpoint, err))
+		}
+	}
+
+	if it.Spec.StrictSchema {
+		problems = append(problems, r.strictSchemaProblems(ctx, it)...)
+	}
+
+	if it.Spec.ScopedRBAC {
+		_, rbacProblems := r.scopedRBACRules(it)
+		problems = append(problems, rbacProblems...)
+		conflicts, err := r.scopedRBACConflicts(ctx, it)
+		if err != nil {
+			return "", err
+		}
+		problems = append(problems, conflicts...)
+	}
+
+	msg := strings.Join(problems, "; ")
+	if len(msg) > 256 {
+		msg = msg[:253] + "..."
+	}
+	return msg, nil
+}
+
+// strictSchemaProblems 以服务端 dry-run（fieldValidation=Strict）校验步骤的 Apply 清单，返回 schema 问题。
+// 只报告 BadRequest（未知/重复字段）与 Invalid（不符合 schema）；其他错误（类型未注册、命名空间不存在、
+// 准入拒绝等）可能依赖前序步骤创建的对象，留给步骤执行时处理。
+func (r *IntegrationTestReconciler) strictSchemaProblems(ctx context.Context, it *infrav1alpha1.IntegrationTest) []string {
+	var problems []string
+	for _, step := range it.Spec.Steps {
+		manifest, err := r.expandStepResource(it, step)
+		if err != nil || manifest == nil || manifest.IsDelete() {
+			continue
+		}
+		err = r.ResourceManager.DryRunApply(ctx, it, manifest.Object)
+		if apierrors.IsBadRequest(err) || apierrors.IsInvalid(err) {
+			problems = append(problems, fmt.Sprintf("step %s: %s/%s rejected by strict validation: %v",
+				step.Name, manifest.Object.GetKind(), manifest.Object.GetName(), err))
+		}
+	}
+	return problems
+}
+
+// configRef 步骤清单引用的 Secret 或 ConfigMap。
+type configRef struct {
+	Kind      string
+	Namespace string
+	Name      string
+}
+
+// missingConfigRefs 返回步骤清单引用但不存在的 Secret/ConfigMap。
+// 任一步骤以清单创建的同名对象与工作区视为存在；名称中仍含未展开的 ${...} 时跳过。
+func (r *IntegrationTestReconciler) missingConfigRefs(ctx context.Context, it *infrav1alpha1.IntegrationTest) ([]string, error) {
+	created := map[configRef]bool{}
+	var refs []configRef
+	for _, step := range it.Spec.Steps {
+		manifest, err := r.expandStepResource(it, step)
+		if err != nil || manifest == nil || manifest.IsDelete() {
+			continue
+		}
+		obj := manifest.Object
+		created[configRef{Kind: obj.GetKind(), Namespace: obj.GetNamespace(), Name: obj.GetName()}] = true
+		collectConfigRefs(obj.Object, obj.GetNamespace(), &refs)
+	}
+	if it.Status.Workspace != "" {
+		for _, kind := range []string{"Secret", "ConfigMap"} {
+			created[configRef{Kind: kind, Namespace: it.Namespace, Name: it.Status.Workspace}] = true
+		}
+	}
+
+	reader := r.APIReader
+	if reader == nil {
+		reader = r.Client
+	}
+	seen := map[configRef]bool{}
+	var missing []string
+	for _, ref := range refs {
+		if created[ref] || seen[ref] || strings.Contains(ref.Name, "${") {
+			continue
+		}
+		seen[ref] = true
+		// 只读取元数据，不缓存 Secret 内容
+		meta := &metav1.PartialObjectMetadata{}
+		meta.SetGroupVersionKind(schema.GroupVersionKind{Version: "v1", Kind: ref.Kind})
+		err := reader.Get(ctx, client.ObjectKey{Namespace: ref.Namespace, Name: ref.Name}, meta)
+		if apierrors.IsNotFound(err) {
+			missing = append(missing, fmt.Sprintf("%s %s/%s not found", ref.Kind, ref.Namespace, ref.Name))
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("get %s %s: %w", ref.Kind, ref.Name, err)
+		}
+	}
+	sort.Strings(missing)
+	return missing, nil
+}
+
+// collectConfigRefs 递归收集对象中引用的 Secret/ConfigMap：
+// secretRef/secretKeyRef/configMapRef/configMapKeyRef/configMap（.name）、secret（.secretName 或 .name），
+// 以及 imagePullSecrets；标记 optional: true 的引用忽略。
+func collectConfigRefs(v interface{}, namespace string, refs *[]configRef) {
+	switch node := v.(type) {
+	case []interface{}:
+		for _, item := range node {
+			collectConfigRefs(item, namespace, refs)
+		}
+	case map[string]interface{}:
+		for key, child := range node {
+			kind := ""
+			switch key {
+			case "secretRef", "secretKeyRef", "secret":
+				kind = "Secret"
+			case "configMapRef", "configMapKeyRef", "configMap":
+				kind = "ConfigMap"
+			case "imagePullSecrets":
+				if items, ok := child.([]interface{}); ok {
+					for _, item := range items {
+						if m, ok := item.(map[string]interface{}); ok {
+							if name, _ := m["name"].(string); name != "" {
+								*refs = append(*refs, configRef{Kind: "Secret", Namespace: namespace, Name: name})
+							}
+						}
+					}
+				}
+				continue
+			}
+			if m, ok := child.(map[string]interface{}); ok && kind != "" {
+				if optional, _ := m["optional"].(bool); !optional {
+					name, _ := m["name"].(string)
+					if secretName, _ := m["secretName"].(string); secretName != "" {
+						name = secretName
+					}
+					if name != "" {
+						*refs = append(*refs, configRef{Kind: kind, Namespace: namespace, Name: name})
+					}
+				}
+			}
+			collectConfigRefs(child, namespace, refs)
+		}
+	}
+}
+
+// webhookEndpoints 返回期望声明的 Webhook 地址（去重、排序），含未展开 ${...} 的地址跳过。
+func webhookEndpoints(it *infrav1alpha1.IntegrationTest) []string {
+	seen := map[string]bool{}
+	add := func(condition *infrav1alpha1.StepCondition) {
+		for _, exp := range expectationsFromStepCondition(condition) {
+			if exp.Webhook != "" && !strings.Contains(exp.Webhook, "${") {
+				seen[exp.Webhook] = true
+			}
+		}
+	}
+	for _, step := range it.Spec.Steps {
+		add(step.ReadyCondition)
+		add(step.Expectations)
+	}
+	add(it.Spec.StatsExpectations)
+	endpoints := make([]string, 0, len(seen))
+	for endpoint := range seen {
+		endpoints = append(endpoints, endpoint)
+	}
+	sort.Strings(endpoints)
+	return endpoints
+}
+
+// dialEndpoint 对 Webhook URL 的主机与端口建立 TCP 连接（未指定端口时按 scheme 取 80/443）。
+func dialEndpoint(ctx context.Context, endpoint string) error {
+	u, err := url.Parse(endpoint)
+	if err != nil || u.Host == "" {
+		return fmt.Errorf("invalid url")
+	}
+	host := u.Host
+	if u.Port() == "" {
+		port := "80"
+		if u.Scheme == "https" {
+			port = "443"
+		}
+		host = net.JoinHostPort(u.Hostname(), port)
+	}
+	dialer := net.Dialer{Timeout: preflightDialTimeout}
+	conn, err := dialer.DialContext(ctx, "tcp", host)
+	if err != nil {
+		return err
+	}
+	return conn.Close()
+}
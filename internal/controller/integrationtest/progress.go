@@ -0,0 +1,60 @@
+package integrationtest
+
+import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	infrav1alpha1 "github.com/lunz1207/testplane/api/v1alpha1"
+	"github.com/lunz1207/testplane/internal/controller/shared"
+	"github.com/lunz1207/testplane/internal/plugin"
+)
+
+// progressHeartbeatInterval 目标阶段未变化时记录进度快照的间隔。
+const progressHeartbeatInterval = time.Minute
+
+// recordStepProgress 根据步骤断言的资源更新进度快照，返回是否需要持久化：
+// 目标阶段变化或距上次心跳超过 progressHeartbeatInterval（消息只随心跳更新）。state 为空时不记录。
+func recordStepProgress(stepStatus *infrav1alpha1.StepStatus, state map[string]interface{}, now time.Time) bool {
+	if len(state) == 0 {
+		return false
+	}
+	phase, message := observedProgress(shared.SelectStateForExpectation(state))
+	progress := stepStatus.Progress
+	if progress == nil {
+		progress = &infrav1alpha1.StepProgress{}
+		stepStatus.Progress = progress
+	}
+
+	ts := metav1.NewTime(now)
+	transitioned := progress.LastTransitionTime == nil || progress.ObservedPhase != phase
+	if !transitioned && progress.LastHeartbeatTime != nil && now.Sub(progress.LastHeartbeatTime.Time) < progressHeartbeatInterval {
+		return false
+	}
+	if transitioned {
+		progress.LastTransitionTime = &ts
+	}
+	progress.ObservedPhase = phase
+	progress.ObservedMessage = message
+	progress.LastHeartbeatTime = &ts
+	return true
+}
+
+// observedProgress 提取资源的阶段与消息：优先 status.phase/status.message，其次 Ready 条件。
+func observedProgress(obj map[string]interface{}) (string, string) {
+	if phase := plugin.GetNestedString(obj, "status.phase"); phase != "" {
+		return phase, plugin.GetNestedString(obj, "status.message")
+	}
+	status, _ := obj["status"].(map[string]interface{})
+	conditions, _ := status["conditions"].([]interface{})
+	for _, c := range conditions {
+		cond, ok := c.(map[string]interface{})
+		if !ok || cond["type"] != "Ready" {
+			continue
+		}
+		s, _ := cond["status"].(string)
+		msg, _ := cond["message"].(string)
+		return "Ready=" + s, msg
+	}
+	return "", ""
+}
@@ -0,0 +1,137 @@
+package integrationtest
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	infrav1alpha1 "github.com/lunz1207/testplane/api/v1alpha1"
+	"github.com/lunz1207/testplane/internal/controller/shared/resource"
+)
+
+// raceAttemptRef 竞争步骤清单中替换为尝试序号的占位符。
+const raceAttemptRef = "${RACE_ATTEMPT}"
+
+// 竞争尝试结果。
+const (
+	RaceOutcomeApplied  = "Applied"
+	RaceOutcomeConflict = "Conflict"
+	RaceOutcomeError    = "Error"
+)
+
+// applyStepResource 应用步骤资源：声明 race 的 Apply 步骤并发竞争 apply，其余按普通方式 apply。
+func (r *IntegrationTestReconciler) applyStepResource(ctx context.Context, it *infrav1alpha1.IntegrationTest, step infrav1alpha1.TestStep, stepStatus *infrav1alpha1.StepStatus, manifest *resource.ExpandedManifest) error {
+	if step.Race == nil || manifest == nil || manifest.IsDelete() {
+		return r.applyResource(ctx, it, manifest)
+	}
+	return r.applyRace(ctx, it, step, stepStatus)
+}
+
+// applyRace 以不同的字段管理者并发 SSA apply 步骤资源 race.attempts 次，结果记录到 stepStatus.raceAttempts。
+// 冲突是被验证的行为而不是失败；有尝试出现其他错误或没有任何尝试成功时返回错误。
+// 设置资源预算时对象只计入一次。
+func (r *IntegrationTestReconciler) applyRace(ctx context.Context, it *infrav1alpha1.IntegrationTest, step infrav1alpha1.TestStep, stepStatus *infrav1alpha1.StepStatus) error {
+	attempts := int(step.Race.Attempts)
+	manifests := make([]*resource.ExpandedManifest, attempts)
+	for i := range manifests {
+		manifest, err := raceManifest(it, step, i+1)
+		if err != nil {
+			return err
+		}
+		manifests[i] = manifest
+	}
+
+	budget, err := budgetFromAnnotations(it)
+	if err != nil {
+		return err
+	}
+	cost := resource.ObjectCost(manifests[0].Object)
+	if !budget.IsZero() {
+		if it.Status.BudgetUsage == nil {
+			it.Status.BudgetUsage = &infrav1alpha1.BudgetUsage{}
+		}
+		if err := budget.Check(*it.Status.BudgetUsage, cost); err != nil {
+			return fmt.Errorf("apply %s/%s: %w", manifests[0].Object.GetKind(), manifests[0].Object.GetName(), err)
+		}
+	}
+
+	results := make([]infrav1alpha1.RaceAttempt, attempts)
+	errs := make([]error, attempts)
+	var wg sync.WaitGroup
+	for i, manifest := range manifests {
+		wg.Add(1)
+		go func(i int, manifest *resource.ExpandedManifest) {
+			defer wg.Done()
+			fieldOwner := fmt.Sprintf("%s-race-%d", integrationTestFieldOwner, i+1)
+			errs[i] = r.ResourceManager.ApplyObjectAs(ctx, it, manifest.Object, fieldOwner, step.Race.Force)
+			results[i] = raceAttemptResult(int32(i+1), fieldOwner, errs[i])
+		}(i, manifest)
+	}
+	wg.Wait()
+
+	// 同名对象仍在删除中：所有尝试都在 apply 前被拦截，交由调用方等待后重试
+	for _, err := range errs {
+		if errors.Is(err, ErrObjectTerminating) {
+			return err
+		}
+	}
+
+	stepStatus.RaceAttempts = results
+	applied, conflicts := 0, 0
+	for _, result := range results {
+		switch result.Outcome {
+		case RaceOutcomeApplied:
+			applied++
+		case RaceOutcomeConflict:
+			conflicts++
+		default:
+			return fmt.Errorf("race attempt %d (%s): %s", result.Attempt, result.FieldOwner, result.Message)
+		}
+	}
+	logf.FromContext(ctx).Info("race step applied", "step", step.Name, "attempts", attempts, "applied", applied, "conflicts", conflicts)
+	if applied == 0 {
+		return fmt.Errorf("no race attempt applied: %d conflicts", conflicts)
+	}
+	if it.Status.BudgetUsage != nil {
+		it.Status.BudgetUsage.Objects += cost.Objects
+		it.Status.BudgetUsage.Pods += cost.Pods
+		it.Status.BudgetUsage.CPUMillis += cost.CPUMillis
+	}
+	return nil
+}
+
+// raceManifest 展开第 attempt 次竞争尝试的清单，清单中的 ${RACE_ATTEMPT} 替换为尝试序号。
+func raceManifest(it *infrav1alpha1.IntegrationTest, step infrav1alpha1.TestStep, attempt int) (*resource.ExpandedManifest, error) {
+	ref := *step.Resource
+	ref.Manifest = runtime.RawExtension{Raw: bytes.ReplaceAll(ref.Manifest.Raw, []byte(raceAttemptRef), []byte(strconv.Itoa(attempt)))}
+	manifest, err := expandStepRef(it, ref)
+	if err != nil {
+		return nil, err
+	}
+	resource.PropagateAnnotations(manifest, it.Annotations, it.Spec.PropagateAnnotations)
+	return manifest, nil
+}
+
+// raceAttemptResult 将单次 apply 的错误归类为竞争尝试结果。
+func raceAttemptResult(attempt int32, fieldOwner string, err error) infrav1alpha1.RaceAttempt {
+	result := infrav1alpha1.RaceAttempt{Attempt: attempt, FieldOwner: fieldOwner, Outcome: RaceOutcomeApplied}
+	if err == nil {
+		return result
+	}
+	result.Outcome = RaceOutcomeError
+	if apierrors.IsConflict(err) {
+		result.Outcome = RaceOutcomeConflict
+	}
+	result.Message = err.Error()
+	if len(result.Message) > 256 {
+		result.Message = result.Message[:253] + "..."
+	}
+	return result
+}
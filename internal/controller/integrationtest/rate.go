@@ -0,0 +1,80 @@
+package integrationtest
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	infrav1alpha1 "github.com/lunz1207/testplane/api/v1alpha1"
+	"github.com/lunz1207/testplane/internal/controller/shared/logging"
+)
+
+// reasonWaitingForRateSlot 轮次等待 ratePerMinute 时间槽时的 status.reason。
+const reasonWaitingForRateSlot = "WaitingForRateSlot"
+
+// rateSlotGrace 调和延迟的容忍时间：晚于时间槽不超过该时长时不视为错过。
+const rateSlotGrace = time.Second
+
+// rateSlot 返回本轮的时间槽开始时间：第 currentRound-1+skippedRounds 个槽。
+func rateSlot(it *infrav1alpha1.IntegrationTest, interval time.Duration) time.Time {
+	slot := int64(it.Status.CurrentRound - 1 + it.Status.SkippedRounds)
+	return it.Status.StartTime.Add(time.Duration(slot) * interval)
+}
+
+// skipMissedSlots 按 Skip 策略跳过 now 之前已错过的时间槽，返回跳过的槽数。
+func skipMissedSlots(it *infrav1alpha1.IntegrationTest, interval time.Duration, now time.Time) int {
+	late := now.Sub(rateSlot(it, interval))
+	if late <= rateSlotGrace {
+		return 0
+	}
+	skipped := int((late + interval - 1) / interval)
+	it.Status.SkippedRounds += skipped
+	return skipped
+}
+
+// waitForRateSlot 设置 repeat.ratePerMinute 时，新一轮在其时间槽到达后开始：
+// 未到时记录 WaitingForRateSlot 并在槽开始时重新调和，返回 waiting=true；
+// 上一轮超过时间槽时按 overrunPolicy 跳过错过的槽（Skip）或立即开始（Queue）。
+func (r *IntegrationTestReconciler) waitForRateSlot(ctx context.Context, it *infrav1alpha1.IntegrationTest) (bool, ctrl.Result, error) {
+	repeat := it.Spec.Repeat
+	if repeat == nil || repeat.RatePerMinute <= 0 || len(it.Status.Steps) > 0 || it.Status.StartTime == nil {
+		return false, ctrl.Result{}, nil
+	}
+	interval := time.Minute / time.Duration(repeat.RatePerMinute)
+	now := time.Now()
+
+	skipped := 0
+	if repeat.OverrunPolicy != infrav1alpha1.RoundOverrunQueue {
+		skipped = skipMissedSlots(it, interval, now)
+	}
+	slot := rateSlot(it, interval)
+	if !now.Before(slot) {
+		if it.Status.Reason != reasonWaitingForRateSlot {
+			return false, ctrl.Result{}, nil
+		}
+		// 时间槽已到：清除等待原因后开始本轮
+		it.Status.Reason = ""
+		it.Status.Message = ""
+		if err := r.patchStatus(ctx, it, it.Status); err != nil {
+			return false, ctrl.Result{}, err
+		}
+		return false, ctrl.Result{}, nil
+	}
+
+	message := fmt.Sprintf("round %d scheduled at %s", it.Status.CurrentRound, slot.UTC().Format(time.RFC3339Nano))
+	if skipped > 0 {
+		message += fmt.Sprintf(" (skipped %d overrun slots)", skipped)
+	}
+	if skipped > 0 || it.Status.Reason != reasonWaitingForRateSlot {
+		it.Status.Reason = reasonWaitingForRateSlot
+		it.Status.Message = message
+		if err := r.patchStatus(ctx, it, it.Status); err != nil {
+			return false, ctrl.Result{}, err
+		}
+		logging.WaitingFor(logf.FromContext(ctx), "rate slot", "slot", slot, "skipped", skipped)
+	}
+	return true, ctrl.Result{RequeueAfter: boundedWait(it, slot.Sub(now))}, nil
+}
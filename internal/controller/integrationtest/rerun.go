@@ -0,0 +1,37 @@
+package integrationtest
+
+import (
+	"context"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	infrav1alpha1 "github.com/lunz1207/testplane/api/v1alpha1"
+	"github.com/lunz1207/testplane/internal/controller/shared"
+)
+
+// rerunTest 处理 infra.testplane.io/rerun 注解：清空状态（只保留重跑令牌），
+// 下一次调和按新测试重新初始化。步骤资源通过 SSA 重新 apply，无需预先清理。
+// 先 patch，成功后再发 Event。
+func (r *IntegrationTestReconciler) rerunTest(ctx context.Context, it *infrav1alpha1.IntegrationTest, token string) (ctrl.Result, error) {
+	// 缓存尚未同步上一次重跑：避免重复清空已开始的新一次运行
+	if latest := r.latestStatus(ctx, it); latest != nil && latest.RerunToken == token {
+		return ctrl.Result{Requeue: true}, nil
+	}
+	logf.FromContext(ctx).Info("rerun requested", "token", token, "previousPhase", it.Status.Phase)
+
+	previous := it.Status.Phase
+	// 每次运行使用新的工作区，重新初始化时创建
+	r.deleteWorkspace(ctx, it)
+	resetForRerun(&it.Status, token)
+	if err := r.patchStatus(ctx, it, it.Status); err != nil {
+		return ctrl.Result{}, err
+	}
+	r.emitNormalEvent(it, -1, shared.EventReasonTestRerun, shared.RerunEventMessage(string(previous)))
+	return ctrl.Result{Requeue: true}, nil
+}
+
+// resetForRerun 清空状态，只保留重跑令牌。
+func resetForRerun(status *infrav1alpha1.IntegrationTestStatus, token string) {
+	*status = infrav1alpha1.IntegrationTestStatus{RerunToken: token}
+}
@@ -1,9 +1,17 @@
 package integrationtest
 
 import (
+	"bytes"
 	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	apiresource "k8s.io/apimachinery/pkg/api/resource"
+	ctrl "sigs.k8s.io/controller-runtime"
 
 	infrav1alpha1 "github.com/lunz1207/testplane/api/v1alpha1"
+	"github.com/lunz1207/testplane/internal/controller/shared"
 	"github.com/lunz1207/testplane/internal/controller/shared/resource"
 )
 
@@ -11,19 +19,170 @@ import (
 // 调用方应该 requeue 等待，而不是将此视为失败。
 var ErrResourceNotReady = resource.ErrResourceNotReady
 
+// ErrObjectTerminating 表示同名资源仍在删除中，需等待删除完成后再 apply。
+var ErrObjectTerminating = resource.ErrObjectTerminating
+
+// resolveManifests 把步骤 resource 与 preStep/postStep 的 base 与 patches 合成为 Manifest（只修改内存中的对象）。
+func (r *IntegrationTestReconciler) resolveManifests(ctx context.Context, it *infrav1alpha1.IntegrationTest) error {
+	load := resource.ConfigMapBaseLoader(ctx, r.Client, it.Namespace)
+	for _, step := range it.Spec.Steps {
+		for _, ref := range resource.StepRefs(step) {
+			if err := resource.ResolveManifest(ref.Ref, load); err != nil {
+				return fmt.Errorf("step %s: %w", step.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// expandStepRef 替换清单中引用的步骤输出（${steps.<name>.outputs.<key>}）后展开单个 ResourceRef。
+// 引用的步骤在本轮尚未成功、没有该输出时返回错误。
+func expandStepRef(it *infrav1alpha1.IntegrationTest, ref infrav1alpha1.ResourceRef) (*resource.ExpandedManifest, error) {
+	if bytes.Contains(ref.Manifest.Raw, []byte("${steps.")) {
+		manifest, err := shared.ExpandStepOutputRefs(ref.Manifest, paramValues(it))
+		if err != nil {
+			return nil, err
+		}
+		ref.Manifest = manifest
+	}
+	return resource.ExpandSingleResourceRef(ref, it.Namespace)
+}
+
 // expandStepResource 展开步骤的单个 ResourceRef 为 ExpandedManifest。
 // 如果 step.Resource 为空或没有 Manifest，返回 nil。
 func (r *IntegrationTestReconciler) expandStepResource(tc *infrav1alpha1.IntegrationTest, step infrav1alpha1.TestStep) (*resource.ExpandedManifest, error) {
 	if step.Resource == nil || len(step.Resource.Manifest.Raw) == 0 {
 		return nil, nil
 	}
-	return resource.ExpandSingleResourceRef(*step.Resource, tc.Namespace)
+	manifest, err := expandStepRef(tc, *step.Resource)
+	if err != nil {
+		return nil, err
+	}
+	resource.PropagateAnnotations(manifest, tc.Annotations, tc.Spec.PropagateAnnotations)
+	// 步骤期望断言墓碑快照时，删除前保存对象状态
+	manifest.Tombstone = manifest.IsDelete() && wantsTombstone(step)
+	// 步骤期望比较 apply 前后的字段时，apply 前保存对象状态
+	manifest.Snapshot = !manifest.IsDelete() && wantsSnapshot(step)
+	return manifest, nil
+}
+
+// wantsTombstone 判断步骤是否有期望声明 tombstone。
+func wantsTombstone(step infrav1alpha1.TestStep) bool {
+	for _, exp := range expectationsFromStepCondition(step.Expectations) {
+		if exp.Tombstone {
+			return true
+		}
+	}
+	return false
+}
+
+// unchangedFieldsExpect 比较 apply 前后字段的期望函数名称，声明它的步骤在 apply 前保存对象快照。
+const unchangedFieldsExpect = "UnchangedFields"
+
+// wantsSnapshot 判断步骤是否有期望需要 apply 前的快照。
+func wantsSnapshot(step infrav1alpha1.TestStep) bool {
+	for _, exp := range expectationsFromStepCondition(step.Expectations) {
+		if exp.Function == unchangedFieldsExpect {
+			return true
+		}
+	}
+	return false
+}
+
+// stepTombstone 按状态键或步骤别名查找 Delete 步骤资源的墓碑快照。
+func (r *IntegrationTestReconciler) stepTombstone(it *infrav1alpha1.IntegrationTest, name string) (map[string]interface{}, bool) {
+	for _, step := range it.Spec.Steps {
+		manifest, err := r.expandStepResource(it, step)
+		if err != nil || manifest == nil || !manifest.IsDelete() {
+			continue
+		}
+		if step.As == name || manifest.StateKey() == name {
+			return r.ResourceManager.Tombstone(it, manifest.Object)
+		}
+	}
+	return nil, false
 }
 
 // applyResource 应用单个资源。
 // 资源通过 ownerRef 关联到 IntegrationTest，删除时 GC 自动清理。
+// 设置预算注解时在预算内 apply，用量累加到 status.budgetUsage（调用方负责 patch）。
+// 设置 spec.scopedRBAC 时以 status.runAs 的身份写入。
 func (r *IntegrationTestReconciler) applyResource(ctx context.Context, tc *infrav1alpha1.IntegrationTest, manifest *resource.ExpandedManifest) error {
-	return r.ResourceManager.ExecuteManifest(ctx, tc, manifest)
+	ctx = resource.WithIdentity(ctx, tc.Status.RunAs)
+	budget, err := budgetFromAnnotations(tc)
+	if err != nil {
+		return err
+	}
+	if budget.IsZero() {
+		return r.ResourceManager.ExecuteManifest(ctx, tc, manifest)
+	}
+	if tc.Status.BudgetUsage == nil {
+		tc.Status.BudgetUsage = &infrav1alpha1.BudgetUsage{}
+	}
+	return r.ResourceManager.ExecuteManifestWithinBudget(ctx, tc, manifest, budget, tc.Status.BudgetUsage)
+}
+
+// budgetFromAnnotations 解析资源预算注解，值无效时返回错误。
+func budgetFromAnnotations(tc *infrav1alpha1.IntegrationTest) (resource.Budget, error) {
+	var budget resource.Budget
+	annotations := tc.GetAnnotations()
+	for key, target := range map[string]*int64{
+		infrav1alpha1.AnnotationBudgetMaxObjects: &budget.MaxObjects,
+		infrav1alpha1.AnnotationBudgetMaxPods:    &budget.MaxPods,
+	} {
+		value, ok := annotations[key]
+		if !ok {
+			continue
+		}
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || n < 0 {
+			return budget, fmt.Errorf("invalid annotation %s=%q: must be a non-negative integer", key, value)
+		}
+		*target = n
+	}
+	if value, ok := annotations[infrav1alpha1.AnnotationBudgetMaxCPU]; ok {
+		q, err := apiresource.ParseQuantity(value)
+		if err != nil || q.Sign() < 0 {
+			return budget, fmt.Errorf("invalid annotation %s=%q: must be a non-negative quantity", infrav1alpha1.AnnotationBudgetMaxCPU, value)
+		}
+		budget.MaxCPUMillis = q.MilliValue()
+	}
+	return budget, nil
+}
+
+// applyFailureReason 返回 apply 失败时的步骤原因：超出资源预算为 BudgetExceeded，
+// 资源类型已从集群中移除为 APIRemoved，其余按错误分类为 Forbidden、Invalid、WebhookDenied、Conflict，无法分类时为 Failed。
+func (r *IntegrationTestReconciler) applyFailureReason(ctx context.Context, manifest *resource.ExpandedManifest, err error) string {
+	if errors.Is(err, resource.ErrBudgetExceeded) {
+		return shared.ReasonBudgetExceeded
+	}
+	if manifest != nil && r.APIRemoval.Removed(ctx, manifest.Object.GroupVersionKind(), err) {
+		return shared.ReasonAPIRemoved
+	}
+	switch resource.Classify(err) {
+	case resource.ErrorClassForbidden:
+		return shared.ReasonForbidden
+	case resource.ErrorClassInvalid:
+		return shared.ReasonInvalid
+	case resource.ErrorClassWebhookDenied:
+		return shared.ReasonWebhookDenied
+	case resource.ErrorClassConflict:
+		return shared.ReasonConflict
+	}
+	return shared.ReasonFailed
+}
+
+// failRemovedAPI 等待收敛时发现步骤资源的类型已从集群中移除（如 CRD 被卸载）：
+// 步骤以 APIRemoved 失败，不再等待到超时。
+func (r *IntegrationTestReconciler) failRemovedAPI(ctx context.Context, it *infrav1alpha1.IntegrationTest, stepStatus *infrav1alpha1.StepStatus, step infrav1alpha1.TestStep, stepIndex int, manifest *resource.ExpandedManifest) (ctrl.Result, error) {
+	message := fmt.Sprintf("%s is no longer served by the cluster", manifest.Object.GroupVersionKind().String())
+	setStepFailed(it, stepStatus, step.Name, shared.ReasonAPIRemoved, message)
+	// 先 patch，成功后再发 Event
+	if err := r.patchStatus(ctx, it, it.Status); err != nil {
+		return ctrl.Result{}, err
+	}
+	r.emitWarningEvent(it, stepIndex, shared.EventReasonStepFailed, fmt.Sprintf("[Round %d] 步骤 %d 执行失败: %s - %s", it.Status.CurrentRound, stepIndex+1, step.Name, message))
+	return r.handleStepFailure(ctx, it)
 }
 
 // waitResourceConverge 等待单个资源收敛。
@@ -32,6 +191,6 @@ func (r *IntegrationTestReconciler) waitResourceConverge(ctx context.Context, ma
 }
 
 // gatherResourceState 获取单个资源的当前状态，用于期望检查。
-func (r *IntegrationTestReconciler) gatherResourceState(ctx context.Context, manifest *resource.ExpandedManifest) (map[string]interface{}, error) {
-	return r.ResourceManager.GatherManifestState(ctx, manifest)
+func (r *IntegrationTestReconciler) gatherResourceState(ctx context.Context, it *infrav1alpha1.IntegrationTest, manifest *resource.ExpandedManifest) (map[string]interface{}, error) {
+	return r.ResourceManager.GatherManifestState(ctx, it, manifest)
 }
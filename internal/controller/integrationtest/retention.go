@@ -0,0 +1,112 @@
+package integrationtest
+
+import (
+	"context"
+	"sort"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	infrav1alpha1 "github.com/lunz1207/testplane/api/v1alpha1"
+)
+
+// pruneCompletedChildren 在轮次之间清理步骤资源创建的已完成子对象（如 Job 的 Pod、CronJob 的 Job），
+// 每个设置 keepCompleted 的步骤只保留最新的 N 个，避免上千轮后命名空间膨胀。
+// 清理为尽力而为：失败只记录日志，不影响测试结果。
+func (r *IntegrationTestReconciler) pruneCompletedChildren(ctx context.Context, it *infrav1alpha1.IntegrationTest) {
+	log := logf.FromContext(ctx)
+	reader := r.APIReader
+	if reader == nil {
+		reader = r.Client
+	}
+	for _, step := range it.Spec.Steps {
+		if step.KeepCompleted == nil {
+			continue
+		}
+		manifest, err := r.expandStepResource(it, step)
+		if err != nil || manifest == nil || manifest.IsDelete() {
+			continue
+		}
+		owner := &metav1.PartialObjectMetadata{}
+		owner.SetGroupVersionKind(manifest.Object.GroupVersionKind())
+		key := client.ObjectKey{Namespace: manifest.Object.GetNamespace(), Name: manifest.Object.GetName()}
+		if err := reader.Get(ctx, key, owner); err != nil {
+			if !apierrors.IsNotFound(err) {
+				log.Info("get step resource for pruning failed", "step", step.Name, "error", err.Error())
+			}
+			continue
+		}
+		children, err := completedChildren(ctx, reader, owner.Namespace, owner.UID)
+		if err != nil {
+			log.Info("list completed children failed", "step", step.Name, "error", err.Error())
+			continue
+		}
+		keep := int(*step.KeepCompleted)
+		if len(children) <= keep {
+			continue
+		}
+		// 按创建时间从新到旧排序，删除超出保留数量的旧对象
+		sort.Slice(children, func(i, j int) bool {
+			return children[i].GetCreationTimestamp().After(children[j].GetCreationTimestamp().Time)
+		})
+		for _, child := range children[keep:] {
+			if err := r.Delete(ctx, child, client.PropagationPolicy(metav1.DeletePropagationBackground)); err != nil && !apierrors.IsNotFound(err) {
+				log.Info("delete completed child failed", "step", step.Name, "name", child.GetName(), "error", err.Error())
+			}
+		}
+	}
+}
+
+// completedChildren 返回由 owner 直接拥有且已结束的 Job（Complete/Failed）与 Pod（Succeeded/Failed）。
+func completedChildren(ctx context.Context, reader client.Reader, namespace string, owner types.UID) ([]client.Object, error) {
+	var children []client.Object
+
+	var jobs batchv1.JobList
+	if err := reader.List(ctx, &jobs, client.InNamespace(namespace)); err != nil {
+		return nil, err
+	}
+	for i := range jobs.Items {
+		job := &jobs.Items[i]
+		if ownedBy(job, owner) && job.DeletionTimestamp.IsZero() && jobFinished(job) {
+			children = append(children, job)
+		}
+	}
+
+	var pods corev1.PodList
+	if err := reader.List(ctx, &pods, client.InNamespace(namespace)); err != nil {
+		return nil, err
+	}
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		finished := pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed
+		if ownedBy(pod, owner) && pod.DeletionTimestamp.IsZero() && finished {
+			children = append(children, pod)
+		}
+	}
+	return children, nil
+}
+
+// ownedBy 判断对象的 OwnerReference 中是否包含 owner。
+func ownedBy(obj metav1.Object, owner types.UID) bool {
+	for _, ref := range obj.GetOwnerReferences() {
+		if ref.UID == owner {
+			return true
+		}
+	}
+	return false
+}
+
+// jobFinished 判断 Job 是否已结束（Complete 或 Failed 条件为 True）。
+func jobFinished(job *batchv1.Job) bool {
+	for _, c := range job.Status.Conditions {
+		if (c.Type == batchv1.JobComplete || c.Type == batchv1.JobFailed) && c.Status == corev1.ConditionTrue {
+			return true
+		}
+	}
+	return false
+}
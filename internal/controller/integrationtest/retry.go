@@ -0,0 +1,119 @@
+package integrationtest
+
+import (
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	infrav1alpha1 "github.com/lunz1207/testplane/api/v1alpha1"
+	"github.com/lunz1207/testplane/internal/controller/shared"
+)
+
+// ReasonRetrying 步骤失败后等待重试（spec.steps[].retries）的原因，步骤状态为空，测试保持 Running。
+const ReasonRetrying = "Retrying"
+
+const (
+	defaultRetryBackoff = 10 * time.Second
+	maxRetryBackoff     = 5 * time.Minute
+)
+
+// scheduleStepRetry 在步骤还有剩余重试次数且失败原因可重试时记录本次尝试并重置步骤状态，返回是否已安排重试。
+// 重置后的步骤在退避结束（StartedAt）后重新执行，截止时间从重试开始重新计算；
+// 清单已按台账生效时跳过 apply，从收敛等待与期望检查开始。
+func scheduleStepRetry(it *infrav1alpha1.IntegrationTest, stepStatus *infrav1alpha1.StepStatus, reason, message string, now time.Time) bool {
+	step, ok := stepSpec(it, stepStatus)
+	if !ok || step.Retries <= 0 || !retryableReason(reason) || len(stepStatus.Iterations) > 0 {
+		return false
+	}
+	attempt := recordStepAttempt(stepStatus, shared.StateFailed, reason, message, now)
+	if attempt > step.Retries {
+		return false
+	}
+
+	startAt := metav1.NewTime(now.Add(retryBackoff(step, attempt)))
+	deadline := metav1.NewTime(stepDeadline(startAt.Time, step))
+	*stepStatus = infrav1alpha1.StepStatus{
+		Name:      stepStatus.Name,
+		Index:     stepStatus.Index,
+		Reason:    ReasonRetrying,
+		Message:   fmt.Sprintf("attempt %d/%d failed, retrying at %s: %s", attempt, step.Retries+1, startAt.UTC().Format(time.RFC3339), message),
+		StartedAt: &startAt,
+		Deadline:  &deadline,
+		Attempts:  stepStatus.Attempts,
+	}
+	return true
+}
+
+// recordStepAttempt 追加一次尝试的结果，返回其序号。
+func recordStepAttempt(stepStatus *infrav1alpha1.StepStatus, state, reason, message string, now time.Time) int32 {
+	attempt := int32(len(stepStatus.Attempts)) + 1
+	finishedAt := metav1.NewTime(now)
+	stepStatus.Attempts = append(stepStatus.Attempts, infrav1alpha1.StepAttempt{
+		Attempt:    attempt,
+		State:      state,
+		Reason:     reason,
+		Message:    message,
+		StartedAt:  stepStatus.StartedAt,
+		FinishedAt: &finishedAt,
+	})
+	return attempt
+}
+
+// retryBackoff 返回第 attempt 次尝试失败后的等待时间：retryBackoffSeconds（默认 10 秒）每次翻倍，最长 5 分钟。
+func retryBackoff(step infrav1alpha1.TestStep, attempt int32) time.Duration {
+	backoff := defaultRetryBackoff
+	if step.RetryBackoffSeconds > 0 {
+		backoff = time.Duration(step.RetryBackoffSeconds) * time.Second
+	}
+	for i := int32(1); i < attempt && backoff < maxRetryBackoff; i++ {
+		backoff *= 2
+	}
+	return min(backoff, maxRetryBackoff)
+}
+
+// retryableReason 判断失败原因是否可能是暂时性的：权限、清单校验、Webhook 拒绝、资源类型移除与预算超出重试也不会成功。
+func retryableReason(reason string) bool {
+	switch reason {
+	case shared.ReasonForbidden, shared.ReasonInvalid, shared.ReasonWebhookDenied, shared.ReasonAPIRemoved, shared.ReasonBudgetExceeded:
+		return false
+	}
+	return true
+}
+
+// stepSpec 返回步骤状态对应的步骤定义。
+func stepSpec(it *infrav1alpha1.IntegrationTest, stepStatus *infrav1alpha1.StepStatus) (infrav1alpha1.TestStep, bool) {
+	if stepStatus.Index < 0 || stepStatus.Index >= len(it.Spec.Steps) || it.Spec.Steps[stepStatus.Index].Name != stepStatus.Name {
+		return infrav1alpha1.TestStep{}, false
+	}
+	return it.Spec.Steps[stepStatus.Index], true
+}
+
+// retryWait 返回等待重试的步骤距重新执行的剩余时间，不在等待重试时返回 0。
+func retryWait(stepStatus *infrav1alpha1.StepStatus, now time.Time) time.Duration {
+	if stepStatus.State != "" || stepStatus.Reason != ReasonRetrying || stepStatus.StartedAt == nil {
+		return 0
+	}
+	return stepStatus.StartedAt.Sub(now)
+}
+
+// pendingRetry 判断步骤失败后是否已安排重试（测试仍在运行），返回最近一次重试的等待时间。
+func pendingRetry(status *infrav1alpha1.IntegrationTestStatus) (time.Duration, bool) {
+	if status.Phase == infrav1alpha1.IntegrationTestPhaseFailed || status.Breakpoint != nil {
+		return 0, false
+	}
+	now := time.Now()
+	var wait time.Duration
+	found := false
+	for i := range status.Steps {
+		st := &status.Steps[i]
+		if st.State != "" || st.Reason != ReasonRetrying {
+			continue
+		}
+		if w := retryWait(st, now); !found || w < wait {
+			wait = w
+		}
+		found = true
+	}
+	return max(wait, 0), found
+}
@@ -0,0 +1,95 @@
+package integrationtest
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	infrav1alpha1 "github.com/lunz1207/testplane/api/v1alpha1"
+	"github.com/lunz1207/testplane/internal/controller/shared"
+	"github.com/lunz1207/testplane/internal/controller/shared/logging"
+)
+
+// reasonWaitingForWindow 轮次等待时间窗口时的 status.reason。
+const reasonWaitingForWindow = "WaitingForWindow"
+
+// waitForWindow 检查新一轮能否开始：未设置 repeat.schedule 或本轮已开始时直接放行；
+// 窗口外记录 WaitingForWindow 并在下一个窗口打开时重新调和，返回 waiting=true。
+func (r *IntegrationTestReconciler) waitForWindow(ctx context.Context, it *infrav1alpha1.IntegrationTest) (bool, ctrl.Result, error) {
+	if it.Spec.Repeat == nil || it.Spec.Repeat.Schedule == nil || len(it.Status.Steps) > 0 {
+		return false, ctrl.Result{}, nil
+	}
+	now := time.Now()
+	open, next, err := windowState(it.Spec.Repeat.Schedule, now)
+	if err != nil {
+		return false, ctrl.Result{}, fmt.Errorf("invalid repeat schedule: %w", err)
+	}
+
+	if open {
+		if it.Status.Reason != reasonWaitingForWindow {
+			return false, ctrl.Result{}, nil
+		}
+		// 窗口已打开：清除等待原因后开始本轮
+		it.Status.Reason = ""
+		it.Status.Message = ""
+		if err := r.patchStatus(ctx, it, it.Status); err != nil {
+			return false, ctrl.Result{}, err
+		}
+		return false, ctrl.Result{}, nil
+	}
+
+	message := fmt.Sprintf("round %d waiting for window opening at %s", it.Status.CurrentRound, next.Format(time.RFC3339))
+	if it.Status.Reason != reasonWaitingForWindow || it.Status.Message != message {
+		it.Status.Reason = reasonWaitingForWindow
+		it.Status.Message = message
+		if err := r.patchStatus(ctx, it, it.Status); err != nil {
+			return false, ctrl.Result{}, err
+		}
+		logging.WaitingFor(logf.FromContext(ctx), "round window", "nextWindow", next)
+	}
+
+	return true, ctrl.Result{RequeueAfter: boundedWait(it, next.Sub(now))}, nil
+}
+
+// boundedWait 将等待新一轮的重新调和间隔限制在 maxDurationSeconds 之内（到期后由 shouldStopRepeat 结束测试），且不短于 1 秒。
+func boundedWait(it *infrav1alpha1.IntegrationTest, requeue time.Duration) time.Duration {
+	if maxSeconds := it.Spec.Repeat.MaxDurationSeconds; maxSeconds > 0 && it.Status.StartTime != nil {
+		if remaining := time.Until(it.Status.StartTime.Add(time.Duration(maxSeconds) * time.Second)); remaining < requeue {
+			requeue = remaining
+		}
+	}
+	if requeue < time.Second {
+		requeue = time.Second
+	}
+	return requeue
+}
+
+// windowState 返回 now 是否处于时间窗口内，以及下一个窗口的开始时间。
+// now 处于窗口内当且仅当 (now - windowSeconds) 之后的下一次触发不晚于 now。
+func windowState(schedule *infrav1alpha1.RepeatSchedule, now time.Time) (bool, time.Time, error) {
+	cron, err := shared.ParseCron(schedule.Cron)
+	if err != nil {
+		return false, time.Time{}, err
+	}
+	loc := time.UTC
+	if schedule.TimeZone != "" {
+		if loc, err = time.LoadLocation(schedule.TimeZone); err != nil {
+			return false, time.Time{}, fmt.Errorf("load time zone %q: %w", schedule.TimeZone, err)
+		}
+	}
+	now = now.In(loc)
+	window := time.Duration(schedule.WindowSeconds) * time.Second
+	// Next 不含起点，回退 1ns 使恰好在触发时刻也视为窗口内
+	start := cron.Next(now.Add(-window - time.Nanosecond))
+	if !start.IsZero() && !start.After(now) {
+		return true, start, nil
+	}
+	next := cron.Next(now)
+	if next.IsZero() {
+		return false, time.Time{}, fmt.Errorf("cron %q never fires", schedule.Cron)
+	}
+	return false, next, nil
+}
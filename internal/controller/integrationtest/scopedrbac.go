@@ -0,0 +1,186 @@
+package integrationtest
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	stderrors "errors"
+	"fmt"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	infrav1alpha1 "github.com/lunz1207/testplane/api/v1alpha1"
+	"github.com/lunz1207/testplane/internal/controller/shared/resource"
+)
+
+// scopedRBAC 各类操作需要的权限。
+var (
+	scopedReadVerbs   = []string{"get", "list", "watch"}
+	scopedApplyVerbs  = []string{"create", "patch"}
+	scopedDeleteVerbs = []string{"delete"}
+)
+
+// scopedRBACSuffix 测试专属身份名称的后缀。
+const scopedRBACSuffix = "-runner"
+
+// errScopedRBACConflict 同名的 ServiceAccount、Role 或 RoleBinding 已存在且不由该测试控制。
+var errScopedRBACConflict = stderrors.New("scopedRBAC identity conflict")
+
+// scopedRBACName 返回测试专属的 ServiceAccount、Role、RoleBinding 名称：<测试名>-runner。
+// 超过 253 字符时截断测试名并附加完整测试名的哈希，不同的长测试名不会得到同一身份。
+func scopedRBACName(it *infrav1alpha1.IntegrationTest) string {
+	const maxName = 253
+	if len(it.Name)+len(scopedRBACSuffix) <= maxName {
+		return it.Name + scopedRBACSuffix
+	}
+	sum := sha256.Sum256([]byte(it.Name))
+	hash := hex.EncodeToString(sum[:])[:8]
+	return it.Name[:maxName-len(scopedRBACSuffix)-len(hash)-1] + "-" + hash + scopedRBACSuffix
+}
+
+// scopedRBACObjects 返回测试专属身份的 ServiceAccount、Role、RoleBinding（只含名称与命名空间）。
+func scopedRBACObjects(it *infrav1alpha1.IntegrationTest) (*corev1.ServiceAccount, *rbacv1.Role, *rbacv1.RoleBinding) {
+	objectMeta := metav1.ObjectMeta{Name: scopedRBACName(it), Namespace: it.Namespace}
+	return &corev1.ServiceAccount{ObjectMeta: objectMeta}, &rbacv1.Role{ObjectMeta: objectMeta}, &rbacv1.RoleBinding{ObjectMeta: objectMeta}
+}
+
+// scopedRBACConflicts 返回已存在但不由该测试控制的同名身份对象，由预检报告。
+// 这些对象可能属于用户，接管会改写其规则与主体，并在测试删除时被 GC 一并删除。
+func (r *IntegrationTestReconciler) scopedRBACConflicts(ctx context.Context, it *infrav1alpha1.IntegrationTest) ([]string, error) {
+	sa, role, binding := scopedRBACObjects(it)
+	var problems []string
+	for _, o := range []struct {
+		kind string
+		obj  client.Object
+	}{{"ServiceAccount", sa}, {"Role", role}, {"RoleBinding", binding}} {
+		err := r.Get(ctx, client.ObjectKeyFromObject(o.obj), o.obj)
+		if apierrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("get scoped %s %s: %w", o.kind, o.obj.GetName(), err)
+		}
+		if !metav1.IsControlledBy(o.obj, it) {
+			problems = append(problems, fmt.Sprintf("scopedRBAC: %s %s already exists and is not owned by this test", o.kind, o.obj.GetName()))
+		}
+	}
+	return problems, nil
+}
+
+// scopedRBACRules 根据步骤清单与选择器引用的类型生成最小权限规则：
+// 清单类型授予读取与 apply（create、patch）或删除，选择器类型只授予读取。
+// 清单写入其他命名空间、集群级资源或类型不在 API 发现中时返回问题，由预检报告。
+func (r *IntegrationTestReconciler) scopedRBACRules(it *infrav1alpha1.IntegrationTest) ([]rbacv1.PolicyRule, []string) {
+	var problems []string
+	verbs := map[schema.GroupResource]sets.Set[string]{}
+	grant := func(gr schema.GroupResource, vs ...[]string) {
+		if verbs[gr] == nil {
+			verbs[gr] = sets.New(scopedReadVerbs...)
+		}
+		for _, v := range vs {
+			verbs[gr].Insert(v...)
+		}
+	}
+
+	for _, step := range it.Spec.Steps {
+		manifest, err := r.expandStepResource(it, step)
+		if err != nil && step.Resource != nil {
+			// 引用的步骤输出在初始化时尚未产生，按未替换的清单确定资源类型
+			manifest, err = resource.ExpandSingleResourceRef(*step.Resource, it.Namespace)
+		}
+		if err == nil && manifest != nil {
+			obj := manifest.Object
+			gvk := obj.GroupVersionKind()
+			mapping, err := r.RESTMapper().RESTMapping(gvk.GroupKind(), gvk.Version)
+			switch {
+			case err != nil:
+				problems = append(problems, fmt.Sprintf("step %s: scopedRBAC cannot resolve kind %s", step.Name, gvk.Kind))
+			case mapping.Scope.Name() == meta.RESTScopeNameRoot:
+				problems = append(problems, fmt.Sprintf("step %s: scopedRBAC does not cover cluster-scoped %s/%s", step.Name, gvk.Kind, obj.GetName()))
+			case obj.GetNamespace() != it.Namespace:
+				problems = append(problems, fmt.Sprintf("step %s: scopedRBAC does not cover namespace %s", step.Name, obj.GetNamespace()))
+			case manifest.IsDelete():
+				grant(mapping.Resource.GroupResource(), scopedDeleteVerbs)
+			default:
+				grant(mapping.Resource.GroupResource(), scopedApplyVerbs)
+			}
+		}
+
+		// 选择器只读取，读取仍以控制器身份进行；只为命名空间级类型授予读取
+		if step.Resource == nil || step.Resource.Selector == nil {
+			continue
+		}
+		gv, err := schema.ParseGroupVersion(step.Resource.Selector.APIVersion)
+		if err != nil {
+			continue
+		}
+		mapping, err := r.RESTMapper().RESTMapping(gv.WithKind(step.Resource.Selector.Kind).GroupKind(), gv.Version)
+		if err == nil && mapping.Scope.Name() != meta.RESTScopeNameRoot {
+			grant(mapping.Resource.GroupResource())
+		}
+	}
+
+	resources := make([]schema.GroupResource, 0, len(verbs))
+	for gr := range verbs {
+		resources = append(resources, gr)
+	}
+	sort.Slice(resources, func(i, j int) bool { return resources[i].String() < resources[j].String() })
+	rules := make([]rbacv1.PolicyRule, 0, len(resources))
+	for _, gr := range resources {
+		rules = append(rules, rbacv1.PolicyRule{
+			APIGroups: []string{gr.Group},
+			Resources: []string{gr.Resource},
+			Verbs:     sets.List(verbs[gr]),
+		})
+	}
+	return rules, problems
+}
+
+// ensureScopedRBAC 创建或更新测试专属的 ServiceAccount、Role、RoleBinding，并把身份记录到 status.runAs
+// （调用方负责 patch）。三者通过 OwnerReference 关联到测试，测试删除时 GC 自动清理。
+// 不接管已存在且不由该测试控制的同名对象：预检之后才出现的冲突返回包装 errScopedRBACConflict 的错误。
+func (r *IntegrationTestReconciler) ensureScopedRBAC(ctx context.Context, it *infrav1alpha1.IntegrationTest) error {
+	rules, problems := r.scopedRBACRules(it)
+	if len(problems) > 0 {
+		return fmt.Errorf("scopedRBAC: %s", problems[0])
+	}
+	name := scopedRBACName(it)
+	labels := map[string]string{"app.kubernetes.io/managed-by": "testplane"}
+
+	sa, role, binding := scopedRBACObjects(it)
+	mutations := []struct {
+		kind   string
+		obj    client.Object
+		mutate func()
+	}{
+		{"ServiceAccount", sa, func() {}},
+		{"Role", role, func() { role.Rules = rules }},
+		{"RoleBinding", binding, func() {
+			binding.RoleRef = rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "Role", Name: name}
+			binding.Subjects = []rbacv1.Subject{{Kind: rbacv1.ServiceAccountKind, Namespace: it.Namespace, Name: name}}
+		}},
+	}
+	for _, m := range mutations {
+		if _, err := controllerutil.CreateOrUpdate(ctx, r.Client, m.obj, func() error {
+			if m.obj.GetResourceVersion() != "" && !metav1.IsControlledBy(m.obj, it) {
+				return fmt.Errorf("%w: %s %s already exists and is not owned by this test", errScopedRBACConflict, m.kind, name)
+			}
+			m.obj.SetLabels(labels)
+			m.mutate()
+			return controllerutil.SetControllerReference(it, m.obj, r.Scheme)
+		}); err != nil {
+			return fmt.Errorf("ensure scoped %s %s: %w", m.kind, name, err)
+		}
+	}
+	it.Status.RunAs = fmt.Sprintf("system:serviceaccount:%s:%s", it.Namespace, name)
+	return nil
+}
@@ -10,6 +10,8 @@ import (
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 
 	infrav1alpha1 "github.com/lunz1207/testplane/api/v1alpha1"
+	"github.com/lunz1207/testplane/internal/controller/shared"
+	"github.com/lunz1207/testplane/internal/controller/shared/resource"
 )
 
 // SelectorResult 保存选择器查找结果。
@@ -22,6 +24,8 @@ type SelectorResult struct {
 	Matched map[string]interface{}
 	// MatchedName 匹配资源的名称。
 	MatchedName string
+	// Diagnostics 未匹配到资源时的诊断信息。
+	Diagnostics *infrav1alpha1.SelectorDiagnostics
 }
 
 // getSelectorKey 生成 ResourceSelector 的唯一标识。
@@ -42,11 +46,7 @@ func (r *IntegrationTestReconciler) listBySelector(
 ) ([]map[string]interface{}, error) {
 	log := logf.FromContext(ctx)
 
-	// 命名空间
-	ns := sel.Namespace
-	if ns == "" {
-		ns = tc.Namespace
-	}
+	ns := selectorNamespace(ctx, tc, sel)
 
 	// 验证互斥：Name、LabelSelector 和 AnnotationSelector 不能同时指定
 	hasName := sel.Name != ""
@@ -95,6 +95,8 @@ func (r *IntegrationTestReconciler) listBySelector(
 			"kind", sel.Kind,
 			"name", sel.Name)
 
+		resource.StripManagedFields(obj)
+		resource.AttachRelated(ctx, r.Client, obj)
 		return []map[string]interface{}{obj.Object}, nil
 	}
 
@@ -119,8 +121,10 @@ func (r *IntegrationTestReconciler) listBySelector(
 			"count", len(list.Items))
 
 		results := make([]map[string]interface{}, 0, len(list.Items))
-		for _, item := range list.Items {
-			results = append(results, item.Object)
+		for i := range list.Items {
+			resource.StripManagedFields(&list.Items[i])
+			resource.AttachRelated(ctx, r.Client, &list.Items[i])
+			results = append(results, list.Items[i].Object)
 		}
 
 		return results, nil
@@ -139,9 +143,11 @@ func (r *IntegrationTestReconciler) listBySelector(
 
 	// 客户端过滤注解
 	results := make([]map[string]interface{}, 0)
-	for _, item := range list.Items {
-		if matchAnnotations(item.GetAnnotations(), sel.AnnotationSelector) {
-			results = append(results, item.Object)
+	for i := range list.Items {
+		if matchAnnotations(list.Items[i].GetAnnotations(), sel.AnnotationSelector) {
+			resource.StripManagedFields(&list.Items[i])
+			resource.AttachRelated(ctx, r.Client, &list.Items[i])
+			results = append(results, list.Items[i].Object)
 		}
 	}
 
@@ -153,6 +159,24 @@ func (r *IntegrationTestReconciler) listBySelector(
 	return results, nil
 }
 
+// selectorNamespace 返回选择器的命名空间，为空时使用 Environment 的默认命名空间或 IntegrationTest 所在命名空间。
+func selectorNamespace(ctx context.Context, tc *infrav1alpha1.IntegrationTest, sel infrav1alpha1.ResourceSelector) string {
+	if sel.Namespace != "" {
+		return sel.Namespace
+	}
+	return shared.EnvironmentNamespace(shared.EnvironmentFromContext(ctx), tc.Namespace)
+}
+
+// selectorDiagnostics 返回第一个未匹配选择器的诊断信息（全部匹配时返回 nil）。
+func selectorDiagnostics(results map[string]*SelectorResult) *infrav1alpha1.SelectorDiagnostics {
+	for _, result := range results {
+		if result != nil && result.Diagnostics != nil {
+			return result.Diagnostics
+		}
+	}
+	return nil
+}
+
 // matchAnnotations 检查资源的注解是否包含所有指定的注解。
 func matchAnnotations(annotations, selector map[string]string) bool {
 	for key, value := range selector {
@@ -167,6 +191,7 @@ func matchAnnotations(annotations, selector map[string]string) bool {
 // 资源列表会按名称排序，确保选择的确定性。
 func (r *IntegrationTestReconciler) findMatchingResource(
 	ctx context.Context,
+	tc *infrav1alpha1.IntegrationTest,
 	sel infrav1alpha1.ResourceSelector,
 	resources []map[string]interface{},
 	expectations []infrav1alpha1.Expectation,
@@ -198,7 +223,7 @@ func (r *IntegrationTestReconciler) findMatchingResource(
 		allPassed := true
 
 		for _, exp := range expectations {
-			passed := r.runSingleExpectation(ctx, exp, res)
+			passed := r.runSingleExpectation(ctx, tc, exp, res)
 			if !passed {
 				log.V(1).Info("expectation not passed", "resource", name, "expect", getExpectName(exp))
 				allPassed = false
@@ -229,6 +254,7 @@ func sortResourcesByName(resources []map[string]interface{}) {
 // 支持声明式和函数式两种模式。
 func (r *IntegrationTestReconciler) runSingleExpectation(
 	ctx context.Context,
+	tc *infrav1alpha1.IntegrationTest,
 	exp infrav1alpha1.Expectation,
 	res map[string]interface{},
 ) bool {
@@ -238,7 +264,7 @@ func (r *IntegrationTestReconciler) runSingleExpectation(
 	condition := &infrav1alpha1.StepCondition{
 		AllOf: []infrav1alpha1.Expectation{exp},
 	}
-	results, err := r.runExpectations(condition, res)
+	results, err := r.runExpectations(ctx, tc, condition, res, nil)
 	if err != nil {
 		log.V(1).Info("expectation error", "expect", getExpectName(exp), "error", err)
 		return false
@@ -266,7 +292,10 @@ func (r *IntegrationTestReconciler) gatherSelectorStates(
 			return nil, fmt.Errorf("selector %s: %w", getSelectorKey(sel), err)
 		}
 
-		result := r.findMatchingResource(ctx, sel, resources, expectations)
+		result := r.findMatchingResource(ctx, tc, sel, resources, expectations)
+		if result.Matched == nil {
+			result.Diagnostics = shared.DiagnoseSelector(ctx, r.Client, sel, selectorNamespace(ctx, tc, sel), len(resources))
+		}
 		results[getSelectorKey(sel)] = result
 	}
 
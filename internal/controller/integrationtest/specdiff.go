@@ -0,0 +1,110 @@
+package integrationtest
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	infrav1alpha1 "github.com/lunz1207/testplane/api/v1alpha1"
+)
+
+// maxIgnoredSpecChanges status.ignoredSpecChanges 最多记录的路径数。
+const maxIgnoredSpecChanges = 20
+
+// specDigest 计算 spec 各路径的摘要：展开顶层字段的一层子字段，对象数组（如 steps）按元素 name（无 name 时按下标）展开，
+// 数组本身记录元素顺序的摘要。只比较摘要，status 中不保存 spec 原文。
+// spec.suspend 在运行中生效，不参与比较。
+func specDigest(spec infrav1alpha1.IntegrationTestSpec) map[string]string {
+	spec.Suspend = false
+	data, err := json.Marshal(spec)
+	if err != nil {
+		return nil
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil
+	}
+	digest := make(map[string]string, len(fields))
+	for key, value := range fields {
+		addDigest(digest, key, value, true)
+	}
+	return digest
+}
+
+// addDigest 记录 path 的摘要，expand 为 true 时继续展开对象字段与对象数组元素。
+func addDigest(digest map[string]string, path string, value json.RawMessage, expand bool) {
+	if expand {
+		var object map[string]json.RawMessage
+		if json.Unmarshal(value, &object) == nil {
+			for key, field := range object {
+				addDigest(digest, path+"."+key, field, false)
+			}
+			return
+		}
+		var items []map[string]json.RawMessage
+		if json.Unmarshal(value, &items) == nil {
+			elements := make([]string, 0, len(items))
+			for i, item := range items {
+				element := fmt.Sprintf("%s[%d]", path, i)
+				var name string
+				if raw, ok := item["name"]; ok && json.Unmarshal(raw, &name) == nil && name != "" {
+					element = fmt.Sprintf("%s[%s]", path, name)
+				}
+				elements = append(elements, element)
+				for key, field := range item {
+					addDigest(digest, element+"."+key, field, false)
+				}
+			}
+			value = []byte(strings.Join(elements, ","))
+		}
+	}
+	sum := sha256.Sum256(value)
+	digest[path] = hex.EncodeToString(sum[:])[:12]
+}
+
+// diffSpecDigest 比较两份摘要，返回按路径排序的变更：~ 修改、+ 新增、- 删除。
+// 整个数组元素新增或删除时只记录元素路径（如 +steps[verify]）。
+func diffSpecDigest(before, after map[string]string) []string {
+	seen := map[string]bool{}
+	var changes []string
+	add := func(change string) {
+		if !seen[change] {
+			seen[change] = true
+			changes = append(changes, change)
+		}
+	}
+	for path, sum := range after {
+		previous, ok := before[path]
+		switch {
+		case !ok:
+			add("+" + elementPath(path, before))
+		case previous != sum:
+			add("~" + path)
+		}
+	}
+	for path := range before {
+		if _, ok := after[path]; !ok {
+			add("-" + elementPath(path, after))
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i][1:] < changes[j][1:] })
+	return changes
+}
+
+// elementPath 路径所属的数组元素在 other 中完全不存在时返回元素路径，否则返回原路径。
+func elementPath(path string, other map[string]string) string {
+	end := strings.Index(path, "].")
+	if end < 0 {
+		return path
+	}
+	element := path[:end+1]
+	for p := range other {
+		if strings.HasPrefix(p, element+".") {
+			return path
+		}
+	}
+	return element
+}
@@ -0,0 +1,82 @@
+package integrationtest
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/runtime"
+
+	infrav1alpha1 "github.com/lunz1207/testplane/api/v1alpha1"
+	"github.com/lunz1207/testplane/internal/controller/shared"
+)
+
+// ReasonStatsExpectationsFailed spec.statsExpectations 不满足时测试的失败原因。
+const ReasonStatsExpectationsFailed = "StatsExpectationsFailed"
+
+// recordRoundStats 将当前轮次（status.steps）的耗时累加到 status.stats。
+// 轮次耗时为最早开始的步骤到最晚结束的步骤之间的时长，没有步骤时间记录的轮次只计数。
+func recordRoundStats(status *infrav1alpha1.IntegrationTestStatus) {
+	stats := status.Stats
+	if stats == nil {
+		stats = &infrav1alpha1.RoundStats{}
+		status.Stats = stats
+	}
+
+	var first, last *infrav1alpha1.StepStatus
+	for i := range status.Steps {
+		st := &status.Steps[i]
+		if st.StartedAt == nil || st.FinishedAt == nil {
+			continue
+		}
+		if first == nil || st.StartedAt.Before(first.StartedAt) {
+			first = st
+		}
+		if last == nil || last.FinishedAt.Before(st.FinishedAt) {
+			last = st
+		}
+		if seconds := int64(st.FinishedAt.Sub(st.StartedAt.Time).Seconds()); seconds > stats.MaxStepSeconds || stats.SlowestStep == "" {
+			stats.MaxStepSeconds = seconds
+			stats.SlowestStep = st.Name
+		}
+	}
+
+	stats.Rounds++
+	if first == nil {
+		return
+	}
+	seconds := int64(last.FinishedAt.Sub(first.StartedAt.Time).Seconds())
+	if stats.Rounds == 1 || seconds < stats.MinRoundSeconds {
+		stats.MinRoundSeconds = seconds
+	}
+	if seconds > stats.MaxRoundSeconds {
+		stats.MaxRoundSeconds = seconds
+	}
+	stats.LastRoundSeconds = seconds
+	stats.TotalRoundSeconds += seconds
+	stats.AvgRoundSeconds = stats.TotalRoundSeconds / int64(stats.Rounds)
+}
+
+// checkStatsExpectations 以测试自身为断言对象执行 spec.statsExpectations（只执行一次，不重试）。
+// 返回未满足时的失败消息，全部满足或未设置时返回空字符串。
+func (r *IntegrationTestReconciler) checkStatsExpectations(ctx context.Context, it *infrav1alpha1.IntegrationTest) (string, error) {
+	if it.Spec.StatsExpectations == nil {
+		return "", nil
+	}
+	obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(it)
+	if err != nil {
+		return "", fmt.Errorf("convert test: %w", err)
+	}
+	state := map[string]interface{}{"IntegrationTest/" + it.Name: obj}
+	results, err := r.runExpectations(ctx, it, it.Spec.StatsExpectations, state, nil)
+	if err != nil {
+		return "", err
+	}
+	if results.Passed() {
+		return "", nil
+	}
+	msg := "stats expectations not satisfied: " + shared.FailedExpectationLabels(results.All())
+	if len(msg) > 256 {
+		msg = msg[:253] + "..."
+	}
+	return msg, nil
+}
@@ -4,7 +4,9 @@ import (
 	"context"
 	stderrors "errors"
 	"fmt"
+	"time"
 
+	"k8s.io/apimachinery/pkg/api/equality"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
@@ -12,6 +14,7 @@ import (
 	infrav1alpha1 "github.com/lunz1207/testplane/api/v1alpha1"
 	"github.com/lunz1207/testplane/internal/controller/shared"
 	"github.com/lunz1207/testplane/internal/controller/shared/logging"
+	"github.com/lunz1207/testplane/internal/controller/shared/metrics"
 	"github.com/lunz1207/testplane/internal/controller/shared/resource"
 )
 
@@ -26,108 +29,216 @@ const (
 	outcomeSucceeded                               // 步骤成功
 )
 
+// stepCheck 步骤期望检查的结果。
+type stepCheck struct {
+	outcome stepExpectationOutcome
+	// eventMsg 需要发送的 Event 消息，为空时不发送。
+	eventMsg string
+	// persist 等待时是否需要持久化状态（诊断信息、错误计数、进度快照或 holdSeconds 计时有更新）。
+	persist bool
+	// requeueAfter 等待时的下次检查间隔。
+	requeueAfter time.Duration
+}
+
 // checkStepExpectationsCore 核心期望检查逻辑，被 checkStepExpectations 和 checkParallelStepExpectations 共用。
-// 返回 outcome 和是否需要发送 Event（调用方负责 patch 和发送 Event）。
-func (r *IntegrationTestReconciler) checkStepExpectationsCore(ctx context.Context, it *infrav1alpha1.IntegrationTest, stepStatus *infrav1alpha1.StepStatus, step infrav1alpha1.TestStep, manifest *resource.ExpandedManifest) (stepExpectationOutcome, string) {
+// 调用方负责 patch 和发送 Event。
+func (r *IntegrationTestReconciler) checkStepExpectationsCore(ctx context.Context, it *infrav1alpha1.IntegrationTest, stepStatus *infrav1alpha1.StepStatus, step infrav1alpha1.TestStep, manifest *resource.ExpandedManifest) stepCheck {
 	log := logf.FromContext(ctx)
 
 	selectors := selectorsFromStep(step)
 	allExpectations := expectationsFromStepCondition(step.Expectations)
 
-	state, waiting, err := r.buildStepState(ctx, it, selectors, allExpectations, manifest)
+	built, err := r.buildStepState(ctx, it, selectors, allExpectations, manifest)
 	if err != nil {
-		setStepFailed(&it.Status, stepStatus, step.Name, shared.ReasonFailed, fmt.Sprintf("gather state failed: %v", err))
-		return outcomeFailed, ""
+		setStepFailed(it, stepStatus, step.Name, shared.ReasonFailed, fmt.Sprintf("gather state failed: %v", err))
+		return stepCheck{outcome: outcomeFailed}
 	}
 
-	if waiting {
+	// 诊断信息只在变化时持久化：选择器持续未匹配时每次轮询的诊断相同，不重复写入
+	diagnosticsChanged := !equality.Semantic.DeepEqual(stepStatus.SelectorDiagnostics, built.Diagnostics)
+	stepStatus.SelectorDiagnostics = built.Diagnostics
+	if built.Waiting {
 		if r.stepTimedOut(stepStatus) {
-			setStepFailed(&it.Status, stepStatus, step.Name, shared.ReasonTimeout, "resources/selectors not ready before timeout")
-			return outcomeFailed, ""
+			setStepFailed(it, stepStatus, step.Name, shared.ReasonTimeout, waitingMessage("resources/selectors not ready before timeout", built.Diagnostics))
+			return stepCheck{outcome: outcomeFailed}
 		}
+		started := stepStatus.State != shared.StateRunning
 		stepStatus.State = shared.StateRunning
-		return outcomeWaiting, ""
+		progressed := recordStepProgress(stepStatus, built.State, time.Now())
+		return stepCheck{
+			outcome:      outcomeWaiting,
+			persist:      started || progressed || diagnosticsChanged,
+			requeueAfter: defaultRequeue,
+		}
 	}
 
-	// 执行期望检查
-	results, err := r.runExpectations(step.Expectations, state)
+	// 执行期望检查（holdSeconds 计时沿用上次检查的结果）
+	held := shared.HeldSinceFromSummaries(stepStatus.ExpectationResults)
+	results, err := r.runExpectations(ctx, it, step.Expectations, built.State, held)
 	if err != nil {
-		setStepFailed(&it.Status, stepStatus, step.Name, shared.ReasonFailed, fmt.Sprintf("expectations error: %v", err))
-		return outcomeFailed, fmt.Sprintf("[Round %d] 步骤 %s 期望检查错误: %v", it.Status.CurrentRound, step.Name, err)
+		setStepFailed(it, stepStatus, step.Name, shared.ReasonFailed, fmt.Sprintf("expectations error: %v", err))
+		return stepCheck{outcome: outcomeFailed, eventMsg: fmt.Sprintf("[Round %d] 步骤 %s 期望检查错误: %v", it.Status.CurrentRound, step.Name, err)}
+	}
+
+	// 金丝雀比较：各指标的结果计入 allOf，与期望一起判定
+	if step.Canary != nil {
+		canary, err := r.compareCanary(ctx, it, step.Canary)
+		if err != nil {
+			setStepFailed(it, stepStatus, step.Name, shared.ReasonFailed, fmt.Sprintf("canary error: %v", err))
+			return stepCheck{outcome: outcomeFailed, eventMsg: fmt.Sprintf("[Round %d] 步骤 %s 金丝雀比较错误: %v", it.Status.CurrentRound, step.Name, err)}
+		}
+		results.AllOf = append(results.AllOf, canary...)
 	}
 
 	allResults := results.All()
+	wasErrored := summariesErrored(stepStatus.ExpectationResults)
 	stepStatus.ExpectationResults = shared.ToExpectationResultSummaries(allResults)
+	warned := shared.SetExpectationWarnings(&it.Status.Conditions, "step "+step.Name, allResults, it.Generation)
 
 	for _, result := range allResults {
 		if result.Passed {
-			logging.ExpectationPassed(log, result.Expect)
+			logging.ExpectationPassed(log, shared.ExpectationLabel(result.Name, result.Expect))
 		} else {
-			logging.ExpectationFailed(log, result.Expect, result.Actual)
+			logging.ExpectationFailed(log, shared.ExpectationLabel(result.Name, result.Expect), result.Actual)
 		}
 	}
 
 	if !results.Passed() {
+		// 可重试错误（如 Webhook 暂时不可用）不判定失败，继续等待，超时前重试；
+		// 连续出错只在开始出错时计数，出错状态变化时才持久化，避免每次轮询写入
+		errCount := results.ErrorCount()
+		erroredChanged := (errCount > 0) != wasErrored
+		if errCount > 0 && !wasErrored {
+			stepStatus.ErrorCount++
+			log.Info("expectations errored, will retry", "errors", errCount, "errorCount", stepStatus.ErrorCount)
+		}
 		if r.stepTimedOut(stepStatus) {
-			setStepFailed(&it.Status, stepStatus, step.Name, shared.ReasonTimeout, "expectations not satisfied before timeout")
-			return outcomeFailed, fmt.Sprintf("[Round %d] 步骤 %s 期望检查超时", it.Status.CurrentRound, step.Name)
+			failed := shared.FailedExpectationLabels(allResults)
+			// 超时复核：第一次观察后间隔 confirmFailureSeconds 再检查一次，仍未满足才判定失败
+			checks := len(stepStatus.FailureChecks)
+			if wait, confirmed := confirmFailure(stepStatus, step, failed, time.Now()); !confirmed {
+				stepStatus.State = shared.StateRunning
+				return stepCheck{outcome: outcomeWaiting, persist: len(stepStatus.FailureChecks) != checks, requeueAfter: wait}
+			}
+			message := "expectations not satisfied before timeout: " + failed
+			if step.ConfirmFailureSeconds > 0 {
+				message = fmt.Sprintf("expectations not satisfied before timeout (confirmed after %ds): %s", step.ConfirmFailureSeconds, failed)
+			}
+			setStepFailed(it, stepStatus, step.Name, shared.ReasonTimeout, message)
+			return stepCheck{outcome: outcomeFailed, eventMsg: fmt.Sprintf("[Round %d] 步骤 %s 期望检查超时: %s", it.Status.CurrentRound, step.Name, failed)}
 		}
 		stepStatus.State = shared.StateRunning
-		return outcomeWaiting, ""
+		progressed := recordStepProgress(stepStatus, built.State, time.Now())
+		// 期望给出重试提示（如平台预计的剩余时间）时延后下次检查
+		return stepCheck{
+			outcome:      outcomeWaiting,
+			persist:      progressed || warned || diagnosticsChanged || erroredChanged || shared.HoldChanged(held, allResults),
+			requeueAfter: shared.HintedRequeue(shared.RetryAfterHint(allResults), defaultRequeue, stepStatus.Deadline),
+		}
 	}
 
-	// 步骤成功
+	// 步骤输出：提取为空或失败时继续等待，超时后失败
+	outputs, err := r.extractStepOutputs(it, step, built.State)
+	if err != nil {
+		if r.stepTimedOut(stepStatus) {
+			setStepFailed(it, stepStatus, step.Name, shared.ReasonTimeout, "outputs not available before timeout: "+err.Error())
+			return stepCheck{outcome: outcomeFailed, eventMsg: fmt.Sprintf("[Round %d] 步骤 %s 输出提取超时: %v", it.Status.CurrentRound, step.Name, err)}
+		}
+		stepStatus.State = shared.StateRunning
+		changed := stepStatus.Message != err.Error()
+		stepStatus.Message = err.Error()
+		return stepCheck{outcome: outcomeWaiting, persist: changed, requeueAfter: defaultRequeue}
+	}
+
+	// 超时复核时期望已恢复满足：记录复核观察，步骤成功
+	if len(stepStatus.FailureChecks) == 1 {
+		stepStatus.FailureChecks = append(stepStatus.FailureChecks, infrav1alpha1.FailureCheck{At: metav1.Now(), Passed: true})
+		log.Info("timeout failure not confirmed, expectations satisfied on recheck")
+	}
+	// 步骤成功：记录输出供后续步骤的清单与期望参数引用
+	stepStatus.Outputs = outputs
 	setStepSucceeded(stepStatus)
 	logging.StepCompleted(log)
-	return outcomeSucceeded, fmt.Sprintf("[Round %d] 步骤 %s 执行成功", it.Status.CurrentRound, step.Name)
+	return stepCheck{outcome: outcomeSucceeded, eventMsg: fmt.Sprintf("[Round %d] 步骤 %s 执行成功", it.Status.CurrentRound, step.Name)}
 }
 
-// checkParallelStepExpectations 检查并行步骤的期望，返回是否通过。
-func (r *IntegrationTestReconciler) checkParallelStepExpectations(ctx context.Context, it *infrav1alpha1.IntegrationTest, stepStatus *infrav1alpha1.StepStatus, step infrav1alpha1.TestStep, manifest *resource.ExpandedManifest) (ctrl.Result, bool) {
+// confirmFailure 记录超时时期望未满足的观察，返回是否确认失败；未确认时返回距复核的等待时长。
+// 未设置 confirmFailureSeconds 时直接确认；第一次观察后间隔满该时长的检查为复核，记录后确认。
+func confirmFailure(stepStatus *infrav1alpha1.StepStatus, step infrav1alpha1.TestStep, failed string, now time.Time) (time.Duration, bool) {
+	if step.ConfirmFailureSeconds <= 0 {
+		return 0, true
+	}
+	delay := time.Duration(step.ConfirmFailureSeconds) * time.Second
+	if len(stepStatus.FailureChecks) == 0 {
+		stepStatus.FailureChecks = append(stepStatus.FailureChecks, infrav1alpha1.FailureCheck{At: metav1.NewTime(now), Failed: failed})
+		return delay, false
+	}
+	if wait := stepStatus.FailureChecks[0].At.Add(delay).Sub(now); wait > 0 {
+		return wait, false
+	}
+	stepStatus.FailureChecks = append(stepStatus.FailureChecks, infrav1alpha1.FailureCheck{At: metav1.NewTime(now), Failed: failed})
+	return 0, true
+}
+
+// checkParallelStepExpectations 检查并行步骤的期望，返回是否通过；status 写入失败时返回错误。
+func (r *IntegrationTestReconciler) checkParallelStepExpectations(ctx context.Context, it *infrav1alpha1.IntegrationTest, stepStatus *infrav1alpha1.StepStatus, step infrav1alpha1.TestStep, manifest *resource.ExpandedManifest) (ctrl.Result, bool, error) {
 
 	// ReadyCondition（可选，仅并行步骤需要）
 	if step.ReadyCondition != nil {
 		result, err := r.checkStepReadyCondition(ctx, it, stepStatus, step, manifest)
 		if err != nil || result.RequeueAfter > 0 {
-			return result, false
+			return result, false, err
 		}
 	}
 
-	outcome, eventMsg := r.checkStepExpectationsCore(ctx, it, stepStatus, step, manifest)
-	switch outcome {
+	check := r.checkStepExpectationsCore(ctx, it, stepStatus, step, manifest)
+	switch check.outcome {
 	case outcomeWaiting:
-		return ctrl.Result{RequeueAfter: defaultRequeue}, false
+		metrics.Mark(ctx, metrics.OutcomeWaitedExpectation)
+		if check.persist {
+			if err := r.patchStatus(ctx, it, it.Status); err != nil {
+				return ctrl.Result{}, false, err
+			}
+		}
+		return ctrl.Result{RequeueAfter: check.requeueAfter}, false, nil
 	case outcomeFailed:
 		if r.stepAlreadyFinished(ctx, it, stepStatus.Index) {
-			return ctrl.Result{}, false
+			return ctrl.Result{}, false, nil
 		}
 		if err := r.patchStatus(ctx, it, it.Status); err != nil {
-			return ctrl.Result{}, false
+			return ctrl.Result{}, false, err
 		}
-		if eventMsg != "" {
-			shared.EmitWarningEvent(r.Recorder, it, shared.EventReasonStepFailed, eventMsg)
+		if check.eventMsg != "" {
+			r.emitWarningEvent(it, stepStatus.Index, shared.EventReasonStepFailed, check.eventMsg)
 		}
-		return ctrl.Result{}, false
+		return ctrl.Result{}, false, nil
 	default: // outcomeSucceeded
 		if r.stepAlreadyFinished(ctx, it, stepStatus.Index) {
-			return ctrl.Result{}, true
+			return ctrl.Result{}, true, nil
 		}
 		if err := r.patchStatus(ctx, it, it.Status); err != nil {
-			return ctrl.Result{}, false
+			return ctrl.Result{}, false, err
 		}
-		if eventMsg != "" {
-			shared.EmitNormalEvent(r.Recorder, it, shared.EventReasonStepSucceeded, eventMsg)
+		if check.eventMsg != "" {
+			r.emitNormalEvent(it, stepStatus.Index, shared.EventReasonStepSucceeded, check.eventMsg)
 		}
-		return ctrl.Result{}, true
+		return ctrl.Result{}, true, nil
 	}
 }
 
 // checkStepExpectations 检查步骤的期望。
 func (r *IntegrationTestReconciler) checkStepExpectations(ctx context.Context, it *infrav1alpha1.IntegrationTest, stepStatus *infrav1alpha1.StepStatus, step infrav1alpha1.TestStep, manifest *resource.ExpandedManifest) (ctrl.Result, error) {
-	outcome, eventMsg := r.checkStepExpectationsCore(ctx, it, stepStatus, step, manifest)
-	switch outcome {
+	check := r.checkStepExpectationsCore(ctx, it, stepStatus, step, manifest)
+	switch check.outcome {
 	case outcomeWaiting:
-		return ctrl.Result{RequeueAfter: defaultRequeue}, nil
+		metrics.Mark(ctx, metrics.OutcomeWaitedExpectation)
+		// 持久化诊断信息、错误计数与进度快照，便于用户在等待期间排查选择器配置、Webhook 故障或目标卡住
+		if check.persist {
+			if err := r.patchStatus(ctx, it, it.Status); err != nil {
+				return ctrl.Result{}, err
+			}
+		}
+		return ctrl.Result{RequeueAfter: check.requeueAfter}, nil
 	case outcomeFailed:
 		// patch 前检查 API Server 最新状态，避免重复事件
 		if r.stepAlreadyFinished(ctx, it, stepStatus.Index) {
@@ -136,8 +247,8 @@ func (r *IntegrationTestReconciler) checkStepExpectations(ctx context.Context, i
 		if err := r.patchStatus(ctx, it, it.Status); err != nil {
 			return ctrl.Result{}, err
 		}
-		if eventMsg != "" {
-			shared.EmitWarningEvent(r.Recorder, it, shared.EventReasonStepFailed, eventMsg)
+		if check.eventMsg != "" {
+			r.emitWarningEvent(it, stepStatus.Index, shared.EventReasonStepFailed, check.eventMsg)
 		}
 		return r.handleStepFailure(ctx, it)
 	default: // outcomeSucceeded
@@ -148,8 +259,8 @@ func (r *IntegrationTestReconciler) checkStepExpectations(ctx context.Context, i
 		if err := r.patchStatus(ctx, it, it.Status); err != nil {
 			return ctrl.Result{}, err
 		}
-		if eventMsg != "" {
-			shared.EmitNormalEvent(r.Recorder, it, shared.EventReasonStepSucceeded, eventMsg)
+		if check.eventMsg != "" {
+			r.emitNormalEvent(it, stepStatus.Index, shared.EventReasonStepSucceeded, check.eventMsg)
 		}
 		return ctrl.Result{Requeue: true}, nil
 	}
@@ -178,46 +289,58 @@ func (r *IntegrationTestReconciler) checkStepReadyCondition(ctx context.Context,
 	selectors := selectorsFromStep(step)
 	allExpectations := expectationsFromStepCondition(ready)
 
-	state, waiting, err := r.buildStepState(ctx, it, selectors, allExpectations, manifest)
+	built, err := r.buildStepState(ctx, it, selectors, allExpectations, manifest)
 	if err != nil {
 		stepStatus.ReadyConditionStatus.State = shared.StateFailed
 		stepStatus.ReadyConditionStatus.Results = nil
-		setStepFailed(&it.Status, stepStatus, step.Name, shared.ReasonFailed, fmt.Sprintf("readyCondition gather state failed: %v", err))
+		setStepFailed(it, stepStatus, step.Name, shared.ReasonFailed, fmt.Sprintf("readyCondition gather state failed: %v", err))
 		// 先 patch，成功后再发 Event
 		if patchErr := r.patchStatus(ctx, it, it.Status); patchErr != nil {
 			return ctrl.Result{}, patchErr
 		}
-		shared.EmitWarningEvent(r.Recorder, it, shared.EventReasonStepFailed, fmt.Sprintf("[Round %d] 步骤 %s readyCondition 错误: %v", it.Status.CurrentRound, step.Name, err))
+		r.emitWarningEvent(it, stepStatus.Index, shared.EventReasonStepFailed, fmt.Sprintf("[Round %d] 步骤 %s readyCondition 错误: %v", it.Status.CurrentRound, step.Name, err))
 		return r.handleStepFailure(ctx, it)
 	}
 
-	if waiting {
+	diagnosticsChanged := !equality.Semantic.DeepEqual(stepStatus.ReadyConditionStatus.SelectorDiagnostics, built.Diagnostics)
+	stepStatus.ReadyConditionStatus.SelectorDiagnostics = built.Diagnostics
+	if built.Waiting {
 		if r.stepTimedOut(stepStatus) {
 			stepStatus.ReadyConditionStatus.State = shared.StateFailed
 			now := metav1.Now()
 			stepStatus.ReadyConditionStatus.FinishedAt = &now
-			setStepFailed(&it.Status, stepStatus, step.Name, shared.ReasonTimeout, "readyCondition timeout")
+			setStepFailed(it, stepStatus, step.Name, shared.ReasonTimeout, waitingMessage("readyCondition timeout", built.Diagnostics))
 			// 先 patch，成功后再发 Event
 			if patchErr := r.patchStatus(ctx, it, it.Status); patchErr != nil {
 				return ctrl.Result{}, patchErr
 			}
-			shared.EmitWarningEvent(r.Recorder, it, shared.EventReasonIntegrationTestTimeout, fmt.Sprintf("[Round %d] 步骤 %s readyCondition 超时", it.Status.CurrentRound, step.Name))
+			r.emitWarningEvent(it, stepStatus.Index, shared.EventReasonIntegrationTestTimeout, fmt.Sprintf("[Round %d] 步骤 %s readyCondition 超时", it.Status.CurrentRound, step.Name))
 			return r.handleStepFailure(ctx, it)
 		}
+		started := stepStatus.ReadyConditionStatus.State != shared.StateRunning
 		stepStatus.ReadyConditionStatus.State = shared.StateRunning
+		metrics.Mark(ctx, metrics.OutcomeWaitedExpectation)
+		// 诊断信息或进度快照变化时持久化，便于用户在等待期间排查选择器配置
+		if recordStepProgress(stepStatus, built.State, time.Now()) || started || diagnosticsChanged {
+			if err := r.patchStatus(ctx, it, it.Status); err != nil {
+				return ctrl.Result{}, err
+			}
+		}
 		return ctrl.Result{RequeueAfter: defaultRequeue}, nil
 	}
 
-	results, err := r.runExpectations(ready, state)
+	held := shared.HeldSinceFromResults(stepStatus.ReadyConditionStatus.Results)
+	wasErrored := shared.ExpectationResults{AllOf: stepStatus.ReadyConditionStatus.Results}.ErrorCount() > 0
+	results, err := r.runExpectations(ctx, it, ready, built.State, held)
 	stepStatus.ReadyConditionStatus.Results = results.All()
 	if err != nil {
 		stepStatus.ReadyConditionStatus.State = shared.StateFailed
-		setStepFailed(&it.Status, stepStatus, step.Name, shared.ReasonFailed, fmt.Sprintf("readyCondition error: %v", err))
+		setStepFailed(it, stepStatus, step.Name, shared.ReasonFailed, fmt.Sprintf("readyCondition error: %v", err))
 		// 先 patch，成功后再发 Event
 		if patchErr := r.patchStatus(ctx, it, it.Status); patchErr != nil {
 			return ctrl.Result{}, patchErr
 		}
-		shared.EmitWarningEvent(r.Recorder, it, shared.EventReasonStepFailed, fmt.Sprintf("[Round %d] 步骤 %s readyCondition 错误: %v", it.Status.CurrentRound, step.Name, err))
+		r.emitWarningEvent(it, stepStatus.Index, shared.EventReasonStepFailed, fmt.Sprintf("[Round %d] 步骤 %s readyCondition 错误: %v", it.Status.CurrentRound, step.Name, err))
 		return r.handleStepFailure(ctx, it)
 	}
 
@@ -226,16 +349,27 @@ func (r *IntegrationTestReconciler) checkStepReadyCondition(ctx context.Context,
 			stepStatus.ReadyConditionStatus.State = shared.StateFailed
 			now := metav1.Now()
 			stepStatus.ReadyConditionStatus.FinishedAt = &now
-			setStepFailed(&it.Status, stepStatus, step.Name, shared.ReasonTimeout, "readyCondition not satisfied before timeout")
+			setStepFailed(it, stepStatus, step.Name, shared.ReasonTimeout, "readyCondition not satisfied before timeout")
 			// 先 patch，成功后再发 Event
 			if patchErr := r.patchStatus(ctx, it, it.Status); patchErr != nil {
 				return ctrl.Result{}, patchErr
 			}
-			shared.EmitWarningEvent(r.Recorder, it, shared.EventReasonIntegrationTestTimeout, fmt.Sprintf("[Round %d] 步骤 %s readyCondition 超时", it.Status.CurrentRound, step.Name))
+			r.emitWarningEvent(it, stepStatus.Index, shared.EventReasonIntegrationTestTimeout, fmt.Sprintf("[Round %d] 步骤 %s readyCondition 超时", it.Status.CurrentRound, step.Name))
 			return r.handleStepFailure(ctx, it)
 		}
 		stepStatus.ReadyConditionStatus.State = shared.StateRunning
-		return ctrl.Result{RequeueAfter: defaultRequeue}, nil
+		metrics.Mark(ctx, metrics.OutcomeWaitedExpectation)
+		// 可重试错误在开始出错时计数后继续等待；出错状态、进度快照或 holdSeconds 计时有变化时持久化
+		errored := results.ErrorCount() > 0
+		if errored && !wasErrored {
+			stepStatus.ErrorCount++
+		}
+		if recordStepProgress(stepStatus, built.State, time.Now()) || errored != wasErrored || shared.HoldChanged(held, results.All()) {
+			if patchErr := r.patchStatus(ctx, it, it.Status); patchErr != nil {
+				return ctrl.Result{}, patchErr
+			}
+		}
+		return ctrl.Result{RequeueAfter: shared.HintedRequeue(shared.RetryAfterHint(results.All()), defaultRequeue, stepStatus.ReadyConditionStatus.Deadline)}, nil
 	}
 
 	now := metav1.Now()
@@ -245,17 +379,27 @@ func (r *IntegrationTestReconciler) checkStepReadyCondition(ctx context.Context,
 	return ctrl.Result{}, nil
 }
 
+// stepState 步骤期望检查使用的资源状态。
+type stepState struct {
+	// State 资源状态（key 为 apiVersion/kind/name）。
+	State map[string]interface{}
+	// Waiting 资源尚未就绪或选择器尚未匹配，需要继续等待。
+	Waiting bool
+	// Diagnostics 选择器未匹配时的诊断信息。
+	Diagnostics *infrav1alpha1.SelectorDiagnostics
+}
+
 // buildStepState 收集模板资源与选择器资源的状态。
-func (r *IntegrationTestReconciler) buildStepState(ctx context.Context, it *infrav1alpha1.IntegrationTest, selectors []infrav1alpha1.ResourceSelector, expectations []infrav1alpha1.Expectation, manifest *resource.ExpandedManifest) (map[string]interface{}, bool, error) {
+func (r *IntegrationTestReconciler) buildStepState(ctx context.Context, it *infrav1alpha1.IntegrationTest, selectors []infrav1alpha1.ResourceSelector, expectations []infrav1alpha1.Expectation, manifest *resource.ExpandedManifest) (stepState, error) {
 	state := make(map[string]interface{})
 
 	if manifest != nil {
-		resourceState, err := r.gatherResourceState(ctx, manifest)
+		resourceState, err := r.gatherResourceState(ctx, it, manifest)
 		if err != nil {
 			if stderrors.Is(err, ErrResourceNotReady) {
-				return nil, true, nil
+				return stepState{Waiting: true}, nil
 			}
-			return nil, false, err
+			return stepState{}, err
 		}
 		for k, v := range resourceState {
 			state[k] = v
@@ -263,22 +407,63 @@ func (r *IntegrationTestReconciler) buildStepState(ctx context.Context, it *infr
 	}
 
 	if len(selectors) == 0 {
-		return state, false, nil
+		return stepState{State: state}, nil
 	}
 
 	selectorResults, err := r.gatherSelectorStates(ctx, it, selectors, expectations)
 	if err != nil {
-		return nil, false, err
+		return stepState{}, err
 	}
 	selectorState := selectorResultsToState(selectorResults)
 	if len(selectorState) == 0 {
-		return nil, true, nil
+		return stepState{Waiting: true, Diagnostics: selectorDiagnostics(selectorResults)}, nil
 	}
 	for k, v := range selectorState {
 		state[k] = v
 	}
 
-	return state, false, nil
+	return stepState{State: state}, nil
+}
+
+// resolveAlias 按别名获取步骤资源的当前状态，供期望通过 resource: <alias> 断言其他步骤的资源。
+// 资源尚未就绪或选择器未匹配时返回可重试错误。
+func (r *IntegrationTestReconciler) resolveAlias(ctx context.Context, it *infrav1alpha1.IntegrationTest, alias string) (map[string]interface{}, error) {
+	for _, step := range it.Spec.Steps {
+		if step.As != alias {
+			continue
+		}
+		manifest, err := r.expandStepResource(it, step)
+		if err != nil {
+			return nil, fmt.Errorf("resource %q: %w", alias, err)
+		}
+		built, err := r.buildStepState(ctx, it, selectorsFromStep(step), nil, manifest)
+		if err != nil {
+			return nil, shared.NewTransientError(fmt.Errorf("resource %q: %w", alias, err), shared.ShortRequeueAfter)
+		}
+		if built.Waiting || len(built.State) == 0 {
+			return nil, shared.NewTransientError(fmt.Errorf("resource %q not ready", alias), shared.ShortRequeueAfter)
+		}
+		return shared.SelectStateForExpectation(built.State), nil
+	}
+	return nil, fmt.Errorf("unknown resource alias %q", alias)
+}
+
+// waitingMessage 生成等待超时的失败消息，有诊断信息时附加诊断摘要。
+func waitingMessage(base string, diag *infrav1alpha1.SelectorDiagnostics) string {
+	if diag == nil || diag.Message == "" {
+		return base
+	}
+	return fmt.Sprintf("%s: %s", base, diag.Message)
+}
+
+// summariesErrored 判断上一次期望检查的结果中是否有可重试错误。
+func summariesErrored(summaries []infrav1alpha1.ExpectationResultSummary) bool {
+	for _, summary := range summaries {
+		if summary.Error {
+			return true
+		}
+	}
+	return false
 }
 
 // selectorsFromStep 从步骤中提取选择器。
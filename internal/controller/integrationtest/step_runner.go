@@ -2,7 +2,10 @@ package integrationtest
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strings"
+	"time"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -37,12 +40,12 @@ func (r *IntegrationTestReconciler) executeSequential(ctx context.Context, it *i
 	// 展开资源模板
 	manifest, err := r.expandStepResource(it, step)
 	if err != nil {
-		setStepFailed(&it.Status, stepStatus, step.Name, shared.ReasonFailed, fmt.Sprintf("expand manifest failed: %v", err))
+		setStepFailed(it, stepStatus, step.Name, shared.ReasonFailed, fmt.Sprintf("expand manifest failed: %v", err))
 		// 先 patch，成功后再发 Event
 		if patchErr := r.patchStatus(ctx, it, it.Status); patchErr != nil {
 			return ctrl.Result{}, patchErr
 		}
-		shared.EmitWarningEvent(r.Recorder, it, shared.EventReasonStepFailed, fmt.Sprintf("[Round %d] 步骤 %d 扩展资源失败: %s - %s", it.Status.CurrentRound, currentIdx+1, step.Name, err.Error()))
+		r.emitWarningEvent(it, currentIdx, shared.EventReasonStepFailed, fmt.Sprintf("[Round %d] 步骤 %d 扩展资源失败: %s - %s", it.Status.CurrentRound, currentIdx+1, step.Name, err.Error()))
 		return r.handleStepFailure(ctx, it)
 	}
 
@@ -51,26 +54,72 @@ func (r *IntegrationTestReconciler) executeSequential(ctx context.Context, it *i
 
 	// 1. 应用资源（仅首次执行）
 	if isFirstExecution {
-		if err := r.applyResource(ctx, it, manifest); err != nil {
-			setStepFailed(&it.Status, stepStatus, step.Name, shared.ReasonFailed, fmt.Sprintf("apply failed: %v", err))
+		// 步骤失败后等待重试：退避结束后重新执行
+		if wait := retryWait(stepStatus, time.Now()); wait > 0 {
+			logging.WaitingFor(log, "step retry", "attempt", len(stepStatus.Attempts)+1)
+			return ctrl.Result{RequeueAfter: wait}, nil
+		}
+		// 缓存尚未同步上一次 status 写入：步骤已开始，避免重复 apply 和重复事件
+		if r.stepAlreadyStarted(ctx, it, currentIdx) {
+			return ctrl.Result{Requeue: true}, nil
+		}
+		// apply 之前写入台账，崩溃重启后据此判断是否已 apply
+		if entry, ok := ledgerEntry(it.Status.CurrentRound, step, manifest); ok && recordApplyLedger(&it.Status, []infrav1alpha1.ApplyLedgerEntry{entry}) {
+			if err := r.patchStatus(ctx, it, it.Status); err != nil {
+				return ctrl.Result{}, err
+			}
+		}
+		err := r.runPreStep(ctx, it, step, stepStatus)
+		if err == nil {
+			err = r.applyStepOnce(ctx, it, step, stepStatus, manifest)
+		}
+		if err != nil {
+			// 同名资源仍在删除中（如上一轮/上一步的 delete 尚未完成）：超时前保持等待
+			if r.waitingForTermination(stepStatus, err) {
+				logging.WaitingFor(log, "previous object deletion", "targetKind", manifest.Object.GetKind(), "targetName", manifest.Object.GetName())
+				if patchErr := r.patchStatus(ctx, it, it.Status); patchErr != nil {
+					return ctrl.Result{}, patchErr
+				}
+				return ctrl.Result{RequeueAfter: defaultRequeue}, nil
+			}
+			// 冲突与暂时性错误：超时前保持等待并重试 apply
+			if r.retryingApply(stepStatus, err) {
+				logging.WaitingFor(log, "apply retry", "class", resource.Classify(err), "targetKind", manifest.Object.GetKind(), "targetName", manifest.Object.GetName())
+				if patchErr := r.patchStatus(ctx, it, it.Status); patchErr != nil {
+					return ctrl.Result{}, patchErr
+				}
+				return ctrl.Result{RequeueAfter: defaultRequeue}, nil
+			}
+			// 命名空间在预检之后进入删除：中止而不是判定步骤失败
+			if isNamespaceTerminatingError(err) {
+				return r.abortTest(ctx, it, infrav1alpha1.AbortReasonNamespaceTerminating, fmt.Sprintf("namespace %s is terminating", it.Namespace))
+			}
+			setStepFailed(it, stepStatus, step.Name, r.applyFailureReason(ctx, manifest, err), fmt.Sprintf("apply failed: %v", err))
 			// 先 patch，成功后再发 Event
 			if patchErr := r.patchStatus(ctx, it, it.Status); patchErr != nil {
 				return ctrl.Result{}, patchErr
 			}
-			shared.EmitWarningEvent(r.Recorder, it, shared.EventReasonStepFailed, fmt.Sprintf("[Round %d] 步骤 %d 执行失败: %s - %s", it.Status.CurrentRound, currentIdx+1, step.Name, err.Error()))
+			r.emitWarningEvent(it, currentIdx, shared.EventReasonStepFailed, fmt.Sprintf("[Round %d] 步骤 %d 执行失败: %s - %s", it.Status.CurrentRound, currentIdx+1, step.Name, err.Error()))
 			return r.handleStepFailure(ctx, it)
 		}
 		stepStatus.State = shared.StateRunning
+		stepStatus.Reason = ""
+		stepStatus.Message = ""
 		// 先 patch，成功后再发 Event
 		if err := r.patchStatus(ctx, it, it.Status); err != nil {
 			return ctrl.Result{}, err
 		}
-		shared.EmitNormalEvent(r.Recorder, it, shared.EventReasonStepStarted, fmt.Sprintf("[Round %d] 开始执行步骤 %d: %s", it.Status.CurrentRound, currentIdx+1, step.Name))
-		logging.ResourceApplied(log, manifest.Object.GetKind(), manifest.Object.GetName())
+		r.emitNormalEvent(it, currentIdx, shared.EventReasonStepStarted, fmt.Sprintf("[Round %d] 开始执行步骤 %d: %s", it.Status.CurrentRound, currentIdx+1, step.Name))
+		if manifest != nil {
+			logging.ResourceApplied(log, manifest.Object.GetKind(), manifest.Object.GetName())
+		}
 	}
 
 	// 2. 等待资源收敛
 	if err := r.waitResourceConverge(ctx, manifest); err != nil {
+		if r.APIRemoval.Removed(ctx, manifest.Object.GroupVersionKind(), err) {
+			return r.failRemovedAPI(ctx, it, stepStatus, step, currentIdx, manifest)
+		}
 		logging.WaitingFor(log, "convergence", "targetKind", manifest.Object.GetKind(), "targetName", manifest.Object.GetName())
 		return ctrl.Result{RequeueAfter: defaultRequeue}, nil
 	}
@@ -88,6 +137,7 @@ func (r *IntegrationTestReconciler) executeSequential(ctx context.Context, it *i
 }
 
 // executeParallel 并行执行：所有步骤同时执行，全部完成后验证期望。
+// 声明了 barrier 的步骤将步骤划分为依次执行的批次，批次内仍并行。
 func (r *IntegrationTestReconciler) executeParallel(ctx context.Context, it *infrav1alpha1.IntegrationTest) (ctrl.Result, error) {
 	baseLog := logf.FromContext(ctx)
 	steps := it.Spec.Steps
@@ -101,60 +151,122 @@ func (r *IntegrationTestReconciler) executeParallel(ctx context.Context, it *inf
 		return r.startNextRound(ctx, it)
 	}
 
+	// barrier 之后的步骤在屏障完成前不启动，本次只推进当前批次
+	window := parallelWindow(steps, &it.Status)
+	active := steps[:window]
+
 	log := logging.WithRound(baseLog, it.Status.CurrentRound)
-	log.Info("parallel execution started", "totalSteps", len(steps))
+	log.Info("parallel execution started", "totalSteps", len(steps), "activeSteps", window)
 
 	// 1. 确保所有步骤状态已初始化
-	for i, step := range steps {
+	for i, step := range active {
 		r.ensureStepStatus(&it.Status, i, step)
 	}
 
 	// 1b. 展开所有步骤资源模板
 	stepManifests := make([]*resource.ExpandedManifest, len(steps))
-	for i, step := range steps {
+	for i, step := range active {
 		manifest, err := r.expandStepResource(it, step)
 		if err != nil {
 			stepStatus := &it.Status.Steps[i]
-			setStepFailed(&it.Status, stepStatus, step.Name, shared.ReasonFailed, fmt.Sprintf("expand manifest failed: %v", err))
+			setStepFailed(it, stepStatus, step.Name, shared.ReasonFailed, fmt.Sprintf("expand manifest failed: %v", err))
 			// 先 patch，成功后再发 Event
 			if patchErr := r.patchStatus(ctx, it, it.Status); patchErr != nil {
 				return ctrl.Result{}, patchErr
 			}
-			shared.EmitWarningEvent(r.Recorder, it, shared.EventReasonStepFailed, fmt.Sprintf("[Round %d] 步骤 %d 扩展资源失败: %s - %s", it.Status.CurrentRound, i+1, step.Name, err.Error()))
+			r.emitWarningEvent(it, i, shared.EventReasonStepFailed, fmt.Sprintf("[Round %d] 步骤 %d 扩展资源失败: %s - %s", it.Status.CurrentRound, i+1, step.Name, err.Error()))
 			return r.handleStepFailure(ctx, it)
 		}
 		stepManifests[i] = manifest
 	}
 
-	// 2. 并行应用所有步骤的资源
-	for i, step := range steps {
+	// 2. 并行应用所有步骤的资源：apply 之前一次性写入台账，崩溃重启后只 apply 尚未生效的步骤
+	var entries []infrav1alpha1.ApplyLedgerEntry
+	for i, step := range active {
+		if it.Status.Steps[i].State != "" {
+			continue
+		}
+		if entry, ok := ledgerEntry(it.Status.CurrentRound, step, stepManifests[i]); ok {
+			entries = append(entries, entry)
+		}
+	}
+	if recordApplyLedger(&it.Status, entries) {
+		if err := r.patchStatus(ctx, it, it.Status); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+	waitingTermination := false
+	for i, step := range active {
 		stepStatus := &it.Status.Steps[i]
 		// 状态为空表示首次执行
 		if stepStatus.State == "" {
-			if err := r.applyResource(ctx, it, stepManifests[i]); err != nil {
-				setStepFailed(&it.Status, stepStatus, step.Name, shared.ReasonFailed, fmt.Sprintf("apply failed: %v", err))
+			// 步骤失败后等待重试：退避结束前不 apply
+			if retryWait(stepStatus, time.Now()) > 0 {
+				logging.WaitingFor(logging.WithStep(log, step.Name, i), "step retry", "attempt", len(stepStatus.Attempts)+1)
+				waitingTermination = true
+				continue
+			}
+			// 缓存尚未同步上一次 status 写入：步骤已开始，避免重复 apply 和重复事件
+			if r.stepAlreadyStarted(ctx, it, i) {
+				return ctrl.Result{Requeue: true}, nil
+			}
+			err := r.runPreStep(ctx, it, step, stepStatus)
+			if err == nil {
+				err = r.applyStepOnce(ctx, it, step, stepStatus, stepManifests[i])
+			}
+			if err != nil {
+				// 同名资源仍在删除中：记录等待原因，下次 reconcile 重试 apply
+				if r.waitingForTermination(stepStatus, err) {
+					logging.WaitingFor(logging.WithStep(log, step.Name, i), "previous object deletion",
+						"targetKind", stepManifests[i].Object.GetKind(), "targetName", stepManifests[i].Object.GetName())
+					waitingTermination = true
+					continue
+				}
+				// 冲突与暂时性错误：与等待删除相同，下次 reconcile 重试 apply
+				if r.retryingApply(stepStatus, err) {
+					logging.WaitingFor(logging.WithStep(log, step.Name, i), "apply retry", "class", resource.Classify(err),
+						"targetKind", stepManifests[i].Object.GetKind(), "targetName", stepManifests[i].Object.GetName())
+					waitingTermination = true
+					continue
+				}
+				if isNamespaceTerminatingError(err) {
+					return r.abortTest(ctx, it, infrav1alpha1.AbortReasonNamespaceTerminating, fmt.Sprintf("namespace %s is terminating", it.Namespace))
+				}
+				setStepFailed(it, stepStatus, step.Name, r.applyFailureReason(ctx, stepManifests[i], err), fmt.Sprintf("apply failed: %v", err))
 				// 先 patch，成功后再发 Event
 				if patchErr := r.patchStatus(ctx, it, it.Status); patchErr != nil {
 					return ctrl.Result{}, patchErr
 				}
-				shared.EmitWarningEvent(r.Recorder, it, shared.EventReasonStepFailed, fmt.Sprintf("[Round %d] 步骤 %d 执行失败: %s - %s", it.Status.CurrentRound, i+1, step.Name, err.Error()))
+				r.emitWarningEvent(it, i, shared.EventReasonStepFailed, fmt.Sprintf("[Round %d] 步骤 %d 执行失败: %s - %s", it.Status.CurrentRound, i+1, step.Name, err.Error()))
 				return r.handleStepFailure(ctx, it)
 			}
 			stepStatus.State = shared.StateRunning
+			stepStatus.Reason = ""
+			stepStatus.Message = ""
 			// 先 patch，成功后再发 Event
 			if err := r.patchStatus(ctx, it, it.Status); err != nil {
 				return ctrl.Result{}, err
 			}
-			shared.EmitNormalEvent(r.Recorder, it, shared.EventReasonStepStarted, fmt.Sprintf("[Round %d] 开始执行步骤 %d: %s", it.Status.CurrentRound, i+1, step.Name))
-			stepLog := logging.WithStep(log, step.Name, i)
-			logging.ResourceApplied(stepLog, stepManifests[i].Object.GetKind(), stepManifests[i].Object.GetName())
+			r.emitNormalEvent(it, i, shared.EventReasonStepStarted, fmt.Sprintf("[Round %d] 开始执行步骤 %d: %s", it.Status.CurrentRound, i+1, step.Name))
+			if stepManifests[i] != nil {
+				logging.ResourceApplied(logging.WithStep(log, step.Name, i), stepManifests[i].Object.GetKind(), stepManifests[i].Object.GetName())
+			}
 		}
 	}
+	if waitingTermination {
+		if err := r.patchStatus(ctx, it, it.Status); err != nil {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{RequeueAfter: defaultRequeue}, nil
+	}
 
 	// 3. 等待所有资源收敛
 	allConverged := true
-	for i, step := range steps {
+	for i, step := range active {
 		if err := r.waitResourceConverge(ctx, stepManifests[i]); err != nil {
+			if r.APIRemoval.Removed(ctx, stepManifests[i].Object.GroupVersionKind(), err) {
+				return r.failRemovedAPI(ctx, it, &it.Status.Steps[i], step, i, stepManifests[i])
+			}
 			stepLog := logging.WithStep(log, step.Name, i)
 			logging.WaitingFor(stepLog, "convergence", "targetKind", stepManifests[i].Object.GetKind(), "targetName", stepManifests[i].Object.GetName())
 			allConverged = false
@@ -167,7 +279,7 @@ func (r *IntegrationTestReconciler) executeParallel(ctx context.Context, it *inf
 	// 4. 并行检查所有步骤的期望
 	allPassed := true
 	anyFailed := false
-	for i, step := range steps {
+	for i, step := range active {
 		stepStatus := &it.Status.Steps[i]
 		if stepStatus.State == shared.StateSucceeded {
 			continue
@@ -177,7 +289,10 @@ func (r *IntegrationTestReconciler) executeParallel(ctx context.Context, it *inf
 			continue
 		}
 
-		result, stepPassed := r.checkParallelStepExpectations(ctx, it, stepStatus, step, stepManifests[i])
+		result, stepPassed, err := r.checkParallelStepExpectations(ctx, it, stepStatus, step, stepManifests[i])
+		if err != nil {
+			return ctrl.Result{}, err
+		}
 		if !stepPassed {
 			allPassed = false
 			if stepStatus.State == shared.StateFailed {
@@ -197,15 +312,55 @@ func (r *IntegrationTestReconciler) executeParallel(ctx context.Context, it *inf
 		log.Info("all parallel steps completed")
 		return ctrl.Result{Requeue: true}, nil
 	}
+	if allPassed && r.allStepsSucceeded(&it.Status, window) {
+		log.Info("parallel batch completed, starting steps after barrier")
+		return ctrl.Result{Requeue: true}, nil
+	}
 
 	return ctrl.Result{RequeueAfter: defaultRequeue}, nil
 }
 
+// waitingForTermination 判断 apply 错误是否为"重建正在删除的对象"且步骤未超时。
+// 是则在步骤消息中记录等待原因，调用方应 requeue 而非判定失败；
+// 超时后返回 false，由调用方以 apply failed 失败（消息中包含 terminating 原因）。
+func (r *IntegrationTestReconciler) waitingForTermination(stepStatus *infrav1alpha1.StepStatus, err error) bool {
+	if !errors.Is(err, ErrObjectTerminating) || r.stepTimedOut(stepStatus) {
+		return false
+	}
+	stepStatus.Message = fmt.Sprintf("waiting for previous object to be deleted: %v", err)
+	return true
+}
+
+// retryingApply 判断 apply 错误是否可重试（冲突、API Server 暂时不可用）且步骤未超时，是则记录等待原因。
+// 超时后按错误分类判定失败（如持续冲突为 Conflict）。
+func (r *IntegrationTestReconciler) retryingApply(stepStatus *infrav1alpha1.StepStatus, err error) bool {
+	class := resource.Classify(err)
+	if !class.Retryable() || r.stepTimedOut(stepStatus) {
+		return false
+	}
+	stepStatus.Message = fmt.Sprintf("retrying apply after %s error: %v", strings.ToLower(string(class)), err)
+	if len(stepStatus.Message) > 256 {
+		stepStatus.Message = stepStatus.Message[:253] + "..."
+	}
+	return true
+}
+
 // handleStepFailure 处理步骤失败，检查是否应该停止。
 // 先 patch 状态，成功后再发送 Event。
 func (r *IntegrationTestReconciler) handleStepFailure(ctx context.Context, it *infrav1alpha1.IntegrationTest) (ctrl.Result, error) {
-	// 检查 API Server 最新状态，避免重复事件
+	// 检查 API Server 最新状态，避免重复事件；缓存同步后由下一次调和处理失败分诊
 	if r.testAlreadyCompleted(ctx, it) {
+		return ctrl.Result{Requeue: true}, nil
+	}
+
+	// 步骤失败后已安排重试：测试继续运行，退避结束后重新执行步骤
+	if wait, retrying := pendingRetry(&it.Status); retrying {
+		return ctrl.Result{Requeue: true, RequeueAfter: wait}, nil
+	}
+
+	// 断点：测试保持 Running 并保留资源，由 resume 或 abort 注解继续（注解变化触发调和）
+	if bp := it.Status.Breakpoint; bp != nil {
+		r.emitWarningEvent(it, -1, shared.EventReasonIntegrationTestBreakpoint, fmt.Sprintf("[Round %d] 步骤 %s 失败，测试停在断点: %s", bp.Round, bp.Step, bp.Message))
 		return ctrl.Result{}, nil
 	}
 
@@ -218,8 +373,39 @@ func (r *IntegrationTestReconciler) handleStepFailure(ctx context.Context, it *i
 		}
 	}
 	// 发送失败事件（状态已在调用方或上面 patch）
-	shared.EmitWarningEvent(r.Recorder, it, shared.EventReasonIntegrationTestFailed, fmt.Sprintf("测试用例执行失败: %s", it.Status.Message))
-	return ctrl.Result{}, nil
+	r.emitWarningEvent(it, -1, shared.EventReasonIntegrationTestFailed, fmt.Sprintf("测试用例执行失败: %s", it.Status.Message))
+	// 失败分诊由下一次调和处理；status 写入不会触发 watch，需显式 Requeue
+	return ctrl.Result{Requeue: true}, nil
+}
+
+// parallelWindow 计算并行模式下可推进的步骤数（步骤前缀）：
+// 遇到 barrier 步骤时，之前的步骤未全部成功则止于屏障之前，屏障步骤未成功则止于屏障本身。
+func parallelWindow(steps []infrav1alpha1.TestStep, status *infrav1alpha1.IntegrationTestStatus) int {
+	for i, step := range steps {
+		if !step.Barrier {
+			continue
+		}
+		if !stepsSucceeded(status, i) {
+			return i
+		}
+		if !stepsSucceeded(status, i+1) {
+			return i + 1
+		}
+	}
+	return len(steps)
+}
+
+// stepsSucceeded 检查前 n 个步骤是否都已成功。
+func stepsSucceeded(status *infrav1alpha1.IntegrationTestStatus, n int) bool {
+	if len(status.Steps) < n {
+		return false
+	}
+	for _, s := range status.Steps[:n] {
+		if s.State != shared.StateSucceeded {
+			return false
+		}
+	}
+	return true
 }
 
 // allStepsSucceeded 检查是否所有步骤都已成功完成。
@@ -0,0 +1,113 @@
+package integrationtest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	infrav1alpha1 "github.com/lunz1207/testplane/api/v1alpha1"
+	"github.com/lunz1207/testplane/internal/controller/shared"
+	"github.com/lunz1207/testplane/internal/controller/shared/resource"
+)
+
+// stephooks.go 步骤级 preStep/postStep 清单：preStep 在步骤资源 apply 之前执行，
+// postStep 在步骤结束（成功、失败或中止）后执行，由 runPostSteps 在之后的调和中补齐。
+
+// runPreStep 依次执行步骤的 preStep 清单，结果记录到 stepStatus.PreStep（调用方负责 patch）。
+// 已成功执行过（如 apply 冲突后重试主清单）时跳过；失败时返回错误，调用方按 apply 失败处理。
+func (r *IntegrationTestReconciler) runPreStep(ctx context.Context, it *infrav1alpha1.IntegrationTest, step infrav1alpha1.TestStep, stepStatus *infrav1alpha1.StepStatus) error {
+	if len(step.PreStep) == 0 || (stepStatus.PreStep != nil && stepStatus.PreStep.State == shared.StateSucceeded) {
+		return nil
+	}
+	err := r.executeHookManifests(ctx, it, step.PreStep, true)
+	stepStatus.PreStep = hookStatus(err)
+	if err != nil {
+		return fmt.Errorf("preStep%w", err)
+	}
+	logf.FromContext(ctx).Info("preStep completed", "step", step.Name, "resources", len(step.PreStep))
+	return nil
+}
+
+// runPostSteps 对已结束且尚未执行 postStep 的步骤执行 postStep 清单并持久化结果。
+// 停在断点的测试保留现场，推迟到恢复（步骤重置后重新执行）或测试结束。
+// 执行为尽力而为：单个清单失败不影响其余清单，失败记录在 stepStatus.PostStep 并发送 Warning 事件。
+func (r *IntegrationTestReconciler) runPostSteps(ctx context.Context, it *infrav1alpha1.IntegrationTest) error {
+	if it.Status.Breakpoint != nil && !isTerminalPhase(it.Status.Phase) {
+		return nil
+	}
+	log := logf.FromContext(ctx)
+	var failed []int
+	ran := false
+	for i := range it.Status.Steps {
+		st := &it.Status.Steps[i]
+		step, ok := stepSpec(it, st)
+		if !ok || len(step.PostStep) == 0 || st.PostStep != nil || !stepFinished(st.State) {
+			continue
+		}
+		err := r.executeHookManifests(ctx, it, step.PostStep, false)
+		st.PostStep = hookStatus(err)
+		ran = true
+		if err != nil {
+			log.Info("postStep failed", "step", step.Name, "error", err.Error())
+			failed = append(failed, i)
+			continue
+		}
+		log.Info("postStep completed", "step", step.Name, "resources", len(step.PostStep))
+	}
+	if !ran {
+		return nil
+	}
+	if err := r.patchStatus(ctx, it, it.Status); err != nil {
+		return err
+	}
+	for _, i := range failed {
+		st := it.Status.Steps[i]
+		r.emitWarningEvent(it, i, shared.EventReasonStepHookFailed, fmt.Sprintf("[Round %d] 步骤 %s postStep 执行失败: %s", it.Status.CurrentRound, st.Name, st.PostStep.Message))
+	}
+	return nil
+}
+
+// pendingPostSteps 判断是否有已结束的步骤尚未执行 postStep。
+func pendingPostSteps(it *infrav1alpha1.IntegrationTest) bool {
+	for i := range it.Status.Steps {
+		st := &it.Status.Steps[i]
+		if step, ok := stepSpec(it, st); ok && len(step.PostStep) > 0 && st.PostStep == nil && stepFinished(st.State) {
+			return true
+		}
+	}
+	return false
+}
+
+// executeHookManifests 依次展开并执行 preStep/postStep 清单，不等待收敛。
+// stopOnError 时遇到第一个错误即返回（preStep），否则继续执行其余清单并合并错误（postStep）。
+func (r *IntegrationTestReconciler) executeHookManifests(ctx context.Context, it *infrav1alpha1.IntegrationTest, refs []infrav1alpha1.ResourceRef, stopOnError bool) error {
+	var errs []error
+	for i, ref := range refs {
+		manifest, err := expandStepRef(it, ref)
+		if err == nil {
+			resource.PropagateAnnotations(manifest, it.Annotations, it.Spec.PropagateAnnotations)
+			err = r.applyResource(ctx, it, manifest)
+		}
+		if err == nil {
+			continue
+		}
+		err = fmt.Errorf("[%d]: %w", i, err)
+		if stopOnError {
+			return err
+		}
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
+
+// hookStatus 由执行结果构造 StepHookStatus。
+func hookStatus(err error) *infrav1alpha1.StepHookStatus {
+	now := metav1.Now()
+	if err != nil {
+		return &infrav1alpha1.StepHookStatus{State: shared.StateFailed, Message: err.Error(), FinishedAt: &now}
+	}
+	return &infrav1alpha1.StepHookStatus{State: shared.StateSucceeded, FinishedAt: &now}
+}
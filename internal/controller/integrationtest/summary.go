@@ -0,0 +1,47 @@
+package integrationtest
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	infrav1alpha1 "github.com/lunz1207/testplane/api/v1alpha1"
+	"github.com/lunz1207/testplane/internal/controller/shared"
+)
+
+// recordSummary 测试进入终态后设置一次 Summary Condition 并发送 TestSummary 事件（先 patch，成功后再发 Event）。
+// 在进入终态的同一次调和中调用，之后的终态调和补齐未能记录的摘要。
+// 已记录（包括缓存尚未同步、API Server 中已记录）时跳过；重跑清空状态后重新记录。
+func (r *IntegrationTestReconciler) recordSummary(ctx context.Context, it *infrav1alpha1.IntegrationTest) error {
+	if shared.GetCondition(it.Status.Conditions, shared.ConditionTypeSummary) != nil {
+		return nil
+	}
+	if latest := r.latestStatus(ctx, it); latest != nil && shared.GetCondition(latest.Conditions, shared.ConditionTypeSummary) != nil {
+		return nil
+	}
+	message := summaryMessage(&it.Status, time.Now())
+	shared.SetCondition(&it.Status.Conditions, shared.ConditionTypeSummary, metav1.ConditionTrue, string(it.Status.Phase), message, it.Generation)
+	if err := r.patchStatus(ctx, it, it.Status); err != nil {
+		return err
+	}
+	event := fmt.Sprintf("测试用例结束 (%s): %s", it.Status.Phase, message)
+	if it.Status.Phase == infrav1alpha1.IntegrationTestPhaseSucceeded {
+		r.emitNormalEvent(it, -1, shared.EventReasonTestSummary, event)
+	} else {
+		r.emitWarningEvent(it, -1, shared.EventReasonTestSummary, event)
+	}
+	return nil
+}
+
+// summaryMessage 生成结果摘要：执行的轮数、最后一轮各状态的步骤数与总时长。
+func summaryMessage(status *infrav1alpha1.IntegrationTestStatus, now time.Time) string {
+	counts := map[string]int{}
+	for i := range status.Steps {
+		counts[status.Steps[i].State]++
+	}
+	return fmt.Sprintf("rounds=%d steps=%d passed=%d failed=%d aborted=%d duration=%s",
+		status.CurrentRound, len(status.Steps), counts[shared.StateSucceeded], counts[shared.StateFailed], counts[shared.StateAborted],
+		shared.SummaryDuration(status.StartTime, status.CompletionTime, now))
+}
@@ -0,0 +1,74 @@
+package integrationtest
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	infrav1alpha1 "github.com/lunz1207/testplane/api/v1alpha1"
+	"github.com/lunz1207/testplane/internal/controller/shared"
+)
+
+// ConditionTypeSuspended spec.suspend 暂停执行的状态：暂停时为 True，恢复后为 False。
+const ConditionTypeSuspended = "Suspended"
+
+// suspendOrResume 处理 spec.suspend。暂停时记录 status.suspendedAt 与 Suspended Condition 后停止推进，
+// 不安排 requeue（恢复由 spec 变更触发）；恢复时把未结束步骤的截止时间顺延暂停时长，清除暂停时间后继续执行。
+// 返回 true 表示测试处于暂停状态，调用方直接返回 result。
+func (r *IntegrationTestReconciler) suspendOrResume(ctx context.Context, it *infrav1alpha1.IntegrationTest) (bool, ctrl.Result, error) {
+	log := logf.FromContext(ctx)
+	if it.Spec.Suspend {
+		if it.Status.SuspendedAt != nil {
+			return true, ctrl.Result{}, nil
+		}
+		now := metav1.Now()
+		it.Status.SuspendedAt = &now
+		shared.SetCondition(&it.Status.Conditions, ConditionTypeSuspended, metav1.ConditionTrue, "Suspended", "execution is suspended by spec.suspend", it.Generation)
+		// 先 patch，成功后再发 Event
+		if err := r.patchStatus(ctx, it, it.Status); err != nil {
+			return true, ctrl.Result{}, err
+		}
+		log.Info("integrationtest suspended", "round", it.Status.CurrentRound)
+		r.emitNormalEvent(it, -1, shared.EventReasonIntegrationTestSuspended, fmt.Sprintf("[Round %d] 测试用例已暂停", it.Status.CurrentRound))
+		return true, ctrl.Result{}, nil
+	}
+
+	if it.Status.SuspendedAt == nil {
+		return false, ctrl.Result{}, nil
+	}
+	paused := time.Since(it.Status.SuspendedAt.Time).Round(time.Second)
+	shiftStepDeadlines(&it.Status, paused)
+	it.Status.SuspendedAt = nil
+	shared.SetCondition(&it.Status.Conditions, ConditionTypeSuspended, metav1.ConditionFalse, "Resumed", fmt.Sprintf("resumed after %s, step deadlines extended accordingly", paused), it.Generation)
+	if err := r.patchStatus(ctx, it, it.Status); err != nil {
+		return false, ctrl.Result{}, err
+	}
+	log.Info("integrationtest resumed", "round", it.Status.CurrentRound, "suspended", paused.String())
+	r.emitNormalEvent(it, -1, shared.EventReasonIntegrationTestResumed, fmt.Sprintf("[Round %d] 测试用例已恢复，暂停 %s", it.Status.CurrentRound, paused))
+	return false, ctrl.Result{}, nil
+}
+
+// shiftStepDeadlines 把未结束步骤的超时与 readyCondition 截止时间顺延 d。
+func shiftStepDeadlines(status *infrav1alpha1.IntegrationTestStatus, d time.Duration) {
+	shift := func(t *metav1.Time) *metav1.Time {
+		if t == nil {
+			return nil
+		}
+		shifted := metav1.NewTime(t.Add(d))
+		return &shifted
+	}
+	for i := range status.Steps {
+		st := &status.Steps[i]
+		if st.State == shared.StateSucceeded || st.State == shared.StateFailed || st.State == shared.StateAborted {
+			continue
+		}
+		st.Deadline = shift(st.Deadline)
+		if rc := st.ReadyConditionStatus; rc != nil && rc.State != shared.StatePassed && rc.State != shared.StateFailed {
+			rc.Deadline = shift(rc.Deadline)
+		}
+	}
+}
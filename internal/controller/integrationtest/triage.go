@@ -0,0 +1,74 @@
+package integrationtest
+
+import (
+	"context"
+	"fmt"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	infrav1alpha1 "github.com/lunz1207/testplane/api/v1alpha1"
+	"github.com/lunz1207/testplane/internal/controller/shared"
+)
+
+// triageFailure 测试以 Failed 结束且配置了 spec.triage 时调用分诊 Webhook，结果记录到 status.triage。
+// 调用失败按间隔重试，直到达到最大尝试次数。先 patch，成功后再发 Event。
+func (r *IntegrationTestReconciler) triageFailure(ctx context.Context, it *infrav1alpha1.IntegrationTest) (ctrl.Result, error) {
+	if it.Status.Phase != infrav1alpha1.IntegrationTestPhaseFailed {
+		return ctrl.Result{}, nil
+	}
+	due, wait := shared.TriageDue(it.Spec.Triage, it.Status.Triage)
+	if !due {
+		return ctrl.Result{RequeueAfter: wait}, nil
+	}
+	// 缓存尚未同步上一次分诊结果：避免重复调用 Webhook
+	if r.triageAlreadyAttempted(ctx, it) {
+		return ctrl.Result{Requeue: true}, nil
+	}
+
+	it.Status.Triage = shared.RunTriage(ctx, it.Spec.Triage, it.Status.Triage, buildTriageRequest(ctx, it))
+	if err := r.patchStatus(ctx, it, it.Status); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	triage := it.Status.Triage
+	switch {
+	case triage.State == infrav1alpha1.TriageStateSucceeded:
+		logf.FromContext(ctx).Info("failure triaged", "ticket", triage.Ticket, "knownIssue", triage.KnownIssue)
+		r.emitNormalEvent(it, -1, shared.EventReasonTriageCompleted, shared.TriageEventMessage(triage))
+		return ctrl.Result{}, nil
+	case shared.TriageExhausted(it.Spec.Triage, triage):
+		r.emitWarningEvent(it, -1, shared.EventReasonTriageFailed, fmt.Sprintf("Triage failed after %d attempts: %s", triage.Attempts, triage.Message))
+		return ctrl.Result{}, nil
+	}
+	return ctrl.Result{RequeueAfter: shared.TriageRetryInterval}, nil
+}
+
+// buildTriageRequest 构造分诊请求：失败步骤及其未通过的期望。
+func buildTriageRequest(ctx context.Context, it *infrav1alpha1.IntegrationTest) shared.TriageRequest {
+	req := shared.NewTriageRequest(ctx, "IntegrationTest", it, it.Spec.Triage)
+	req.Reason = it.Status.Reason
+	req.Message = it.Status.Message
+	req.Round = it.Status.CurrentRound
+	req.StartTime = it.Status.StartTime
+	req.CompletionTime = it.Status.CompletionTime
+	for i := range it.Status.Steps {
+		st := &it.Status.Steps[i]
+		if st.State != shared.StateFailed {
+			continue
+		}
+		req.FailedStep = &shared.TriageFailedStep{Name: st.Name, Index: st.Index, Reason: st.Reason, Message: st.Message}
+		req.Expectations = shared.FailedExpectations(st.ExpectationResults)
+		break
+	}
+	return req
+}
+
+// triageAlreadyAttempted 检查 API Server 上的分诊尝试次数是否已比本地状态多。
+func (r *IntegrationTestReconciler) triageAlreadyAttempted(ctx context.Context, it *infrav1alpha1.IntegrationTest) bool {
+	latest := r.latestStatus(ctx, it)
+	if latest == nil || latest.Triage == nil {
+		return false
+	}
+	return it.Status.Triage == nil || latest.Triage.Attempts > it.Status.Triage.Attempts
+}
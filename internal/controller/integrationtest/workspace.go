@@ -0,0 +1,116 @@
+package integrationtest
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apiresource "k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	infrav1alpha1 "github.com/lunz1207/testplane/api/v1alpha1"
+	"github.com/lunz1207/testplane/internal/controller/shared/resource"
+)
+
+// defaultWorkspaceSize PersistentVolumeClaim 工作区的默认容量。
+var defaultWorkspaceSize = apiresource.MustParse("1Gi")
+
+// workspaceName 返回本次运行的工作区名称：<测试名>-ws-<UID 与重跑令牌的短哈希>。
+// 同名测试重建或重跑时得到新名称，避免读到上一次运行留下的数据。
+func workspaceName(it *infrav1alpha1.IntegrationTest) string {
+	sum := sha256.Sum256([]byte(string(it.UID) + "/" + it.Status.RerunToken))
+	name := it.Name
+	if len(name) > 240 {
+		name = name[:240]
+	}
+	return name + "-ws-" + hex.EncodeToString(sum[:])[:8]
+}
+
+// workspaceObject 构造工作区资源，spec 为 nil 时返回 nil。
+func workspaceObject(it *infrav1alpha1.IntegrationTest, name string) client.Object {
+	spec := it.Spec.Workspace
+	if spec == nil {
+		return nil
+	}
+	meta := metav1.ObjectMeta{
+		Name:      name,
+		Namespace: it.Namespace,
+		Labels:    map[string]string{"app.kubernetes.io/managed-by": "testplane"},
+	}
+	switch spec.Kind {
+	case infrav1alpha1.WorkspaceKindSecret:
+		return &corev1.Secret{ObjectMeta: meta, Type: corev1.SecretTypeOpaque}
+	case infrav1alpha1.WorkspaceKindPersistentVolumeClaim:
+		size := defaultWorkspaceSize
+		if spec.Size != nil {
+			size = *spec.Size
+		}
+		return &corev1.PersistentVolumeClaim{
+			ObjectMeta: meta,
+			Spec: corev1.PersistentVolumeClaimSpec{
+				AccessModes:      []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+				StorageClassName: spec.StorageClassName,
+				Resources: corev1.VolumeResourceRequirements{
+					Requests: corev1.ResourceList{corev1.ResourceStorage: size},
+				},
+			},
+		}
+	default:
+		return &corev1.ConfigMap{ObjectMeta: meta}
+	}
+}
+
+// ensureWorkspace 创建本次运行的工作区并记录到 status（调用方负责 patch）。
+// 工作区通过 OwnerReference 关联到测试，测试删除时 GC 自动清理；已存在时直接沿用。
+func (r *IntegrationTestReconciler) ensureWorkspace(ctx context.Context, it *infrav1alpha1.IntegrationTest) error {
+	name := workspaceName(it)
+	obj := workspaceObject(it, name)
+	if obj == nil {
+		return nil
+	}
+	if err := controllerutil.SetOwnerReference(it, obj, r.Scheme); err != nil {
+		return fmt.Errorf("set workspace owner: %w", err)
+	}
+	if err := r.Create(ctx, obj); err != nil && !apierrors.IsAlreadyExists(err) {
+		return fmt.Errorf("create workspace %s: %w", name, err)
+	}
+	it.Status.Workspace = name
+	return nil
+}
+
+// deleteWorkspace 删除上一次运行的工作区（重跑时调用），失败只记录日志，残留对象随测试删除由 GC 清理。
+func (r *IntegrationTestReconciler) deleteWorkspace(ctx context.Context, it *infrav1alpha1.IntegrationTest) {
+	if it.Status.Workspace == "" {
+		return
+	}
+	obj := workspaceObject(it, it.Status.Workspace)
+	if obj == nil {
+		return
+	}
+	if err := r.Delete(ctx, obj); err != nil && !apierrors.IsNotFound(err) {
+		logf.FromContext(ctx).Info("delete previous workspace failed", "workspace", it.Status.Workspace, "error", err.Error())
+	}
+}
+
+// expandWorkspace 将 spec 中的 ${WORKSPACE} 替换为本次运行的工作区名称，只修改内存中的对象。
+// 工作区尚未创建时不做处理（步骤在初始化之后才执行）；未声明 spec.workspace 却引用时返回错误。
+func expandWorkspace(it *infrav1alpha1.IntegrationTest) error {
+	name := it.Status.Workspace
+	if it.Spec.Workspace == nil {
+		name = ""
+	}
+	referenced, err := resource.ExpandWorkspace(&it.Spec, name)
+	if err != nil {
+		return err
+	}
+	if referenced && it.Spec.Workspace == nil {
+		return fmt.Errorf("spec references %s but spec.workspace is not set", resource.WorkspaceRef)
+	}
+	return nil
+}
@@ -0,0 +1,116 @@
+package loadtest
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	infrav1alpha1 "github.com/lunz1207/testplane/api/v1alpha1"
+	"github.com/lunz1207/testplane/pkg/artifacts"
+)
+
+const (
+	// maxArtifactPods 每个工件最多收集的 Pod 数（按名称排序）。
+	maxArtifactPods = 10
+	// artifactCopyTimeout 从单个 Pod 复制工件的超时。
+	artifactCopyTimeout = 2 * time.Minute
+)
+
+// collectArtifacts 按 spec.artifacts 从 Pod 中复制文件到工件存储，结果写入 status.artifacts（调用方负责 patch）。
+// 只在测试结束时调用一次：成功路径在停止 workload 前，失败路径在进入终态后。
+// 收集失败只记录在对应条目的 error 中，不影响测试结果。
+func (r *LoadTestReconciler) collectArtifacts(ctx context.Context, lt *infrav1alpha1.LoadTest) {
+	if len(lt.Spec.Artifacts) == 0 || lt.Status.Artifacts != nil {
+		return
+	}
+	log := logf.FromContext(ctx)
+	statuses := make([]infrav1alpha1.ArtifactStatus, 0, len(lt.Spec.Artifacts))
+	for _, spec := range lt.Spec.Artifacts {
+		if r.ArtifactStore == nil || r.PodExecutor == nil {
+			statuses = append(statuses, infrav1alpha1.ArtifactStatus{Name: spec.Name, Error: "artifact store not configured"})
+			continue
+		}
+		pods, err := r.artifactPods(ctx, lt, spec)
+		if err != nil {
+			statuses = append(statuses, infrav1alpha1.ArtifactStatus{Name: spec.Name, Error: err.Error()})
+			continue
+		}
+		if len(pods) == 0 {
+			statuses = append(statuses, infrav1alpha1.ArtifactStatus{Name: spec.Name, Error: "no running pods matched"})
+			continue
+		}
+		for i := range pods {
+			status := r.copyArtifact(ctx, lt, spec, &pods[i])
+			if status.Error != "" {
+				log.Info("collect artifact failed", "artifact", spec.Name, "pod", status.Pod, "error", status.Error)
+			}
+			statuses = append(statuses, status)
+		}
+	}
+	lt.Status.Artifacts = statuses
+}
+
+// artifactPods 返回工件选中的运行中 Pod，podSelector 为空时选择内置 HTTP 负载的 worker Pod。
+func (r *LoadTestReconciler) artifactPods(ctx context.Context, lt *infrav1alpha1.LoadTest, spec infrav1alpha1.ArtifactSpec) ([]corev1.Pod, error) {
+	selector := spec.PodSelector
+	if len(selector) == 0 {
+		selector = map[string]string{labelLoadTestOwner: lt.Name, labelComponent: componentHTTPLoad}
+	}
+	reader := r.APIReader
+	if reader == nil {
+		reader = r.Client
+	}
+	var list corev1.PodList
+	if err := reader.List(ctx, &list, client.InNamespace(lt.Namespace), client.MatchingLabels(selector)); err != nil {
+		return nil, fmt.Errorf("list pods: %w", err)
+	}
+	pods := make([]corev1.Pod, 0, len(list.Items))
+	for _, pod := range list.Items {
+		if pod.Status.Phase == corev1.PodRunning && pod.DeletionTimestamp.IsZero() {
+			pods = append(pods, pod)
+		}
+	}
+	sort.Slice(pods, func(i, j int) bool { return pods[i].Name < pods[j].Name })
+	if len(pods) > maxArtifactPods {
+		pods = pods[:maxArtifactPods]
+	}
+	return pods, nil
+}
+
+// copyArtifact 在 Pod 中执行 tar 并将输出流式写入工件存储。
+func (r *LoadTestReconciler) copyArtifact(ctx context.Context, lt *infrav1alpha1.LoadTest, spec infrav1alpha1.ArtifactSpec, pod *corev1.Pod) infrav1alpha1.ArtifactStatus {
+	status := infrav1alpha1.ArtifactStatus{Name: spec.Name, Pod: pod.Name}
+	container := spec.Container
+	if container == "" && len(pod.Spec.Containers) > 0 {
+		container = pod.Spec.Containers[0].Name
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, artifactCopyTimeout)
+	defer cancel()
+
+	pr, pw := io.Pipe()
+	command := append([]string{"tar", "cf", "-"}, spec.Paths...)
+	go func() {
+		_ = pw.CloseWithError(r.PodExecutor.Exec(ctx, pod.Namespace, pod.Name, container, command, pw))
+	}()
+	ref := artifacts.ObjectRef{Kind: "LoadTest", Namespace: lt.Namespace, Name: lt.Name}
+	location, size, err := r.ArtifactStore.Put(ctx, ref, fmt.Sprintf("%s-%s.tar", spec.Name, pod.Name), pr)
+	// 存储提前失败时关闭读端，结束 exec
+	_ = pr.CloseWithError(err)
+	if err != nil {
+		status.Error = err.Error()
+		if len(status.Error) > 256 {
+			status.Error = status.Error[:253] + "..."
+		}
+		return status
+	}
+	status.Location = location
+	status.SizeBytes = size
+	return status
+}
@@ -0,0 +1,66 @@
+package loadtest
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	infrav1alpha1 "github.com/lunz1207/testplane/api/v1alpha1"
+	"github.com/lunz1207/testplane/internal/controller/shared"
+	"github.com/lunz1207/testplane/internal/controller/shared/resource"
+)
+
+// handleDeletion 测试删除时先按依赖顺序清理 LoadTest 创建的资源（监控、workload、target 依次删除，
+// 见 resource.Manager.DeleteInOrder），全部删除完成或超过等待时长后再移除 finalizer。
+func (r *LoadTestReconciler) handleDeletion(ctx context.Context, lt *infrav1alpha1.LoadTest) (ctrl.Result, error) {
+	if !controllerutil.ContainsFinalizer(lt, loadTestFinalizer) {
+		return ctrl.Result{}, nil
+	}
+	// 只在副本上合成 base：移除 finalizer 会写回整个对象；合成失败的资源在 createdObjects 中跳过
+	resolved := lt.DeepCopy()
+	_ = r.resolveManifests(ctx, resolved)
+	done, err := r.ResourceManager.DeleteInOrder(ctx, lt, r.createdObjects(resolved), lt.Spec.Cleanup)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if !done {
+		return ctrl.Result{RequeueAfter: defaultRequeue}, nil
+	}
+	deleteHealthCheckMetrics(lt)
+	return shared.HandleDeletion(ctx, r.Client, lt, loadTestFinalizer)
+}
+
+// createdObjects 按创建顺序返回 LoadTest 创建的资源：target、workload、监控。展开失败的部分跳过，其资源由 GC 清理。
+func (r *LoadTestReconciler) createdObjects(lt *infrav1alpha1.LoadTest) []*unstructured.Unstructured {
+	var objs []*unstructured.Unstructured
+	if len(lt.Spec.Target.Resource.Manifest.Raw) > 0 {
+		if manifest, err := resource.ExpandRawTemplate(&lt.Spec.Target.Resource.Manifest, lt.Namespace); err == nil {
+			objs = append(objs, manifest.Object)
+		}
+	}
+	if specs, err := r.expandResources(lt, lt.Spec.Workload.Resources); err == nil {
+		for i := range specs {
+			if !specs[i].IsDelete() {
+				objs = append(objs, specs[i].Object)
+			}
+		}
+	}
+	if lt.Spec.Workload.HTTPLoad != nil {
+		if manifest, err := r.buildHTTPLoadManifest(lt); err == nil {
+			objs = append(objs, manifest.Object)
+		}
+	}
+	if mon := lt.Spec.Monitoring; mon != nil {
+		if mon.Dashboard {
+			if obj, err := buildDashboardConfigMap(lt); err == nil {
+				objs = append(objs, obj)
+			}
+		}
+		if mon.Alerts && lt.Spec.HealthCheck != nil {
+			objs = append(objs, buildPrometheusRule(lt))
+		}
+	}
+	return objs
+}
@@ -0,0 +1,227 @@
+package loadtest
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	apiresource "k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	infrav1alpha1 "github.com/lunz1207/testplane/api/v1alpha1"
+	"github.com/lunz1207/testplane/internal/controller/shared"
+	"github.com/lunz1207/testplane/internal/controller/shared/resource"
+)
+
+const (
+	// defaultDriftInterval 默认漂移检测间隔。
+	defaultDriftInterval = 60 * time.Second
+	// maxDriftFields status 中记录的漂移字段上限。
+	maxDriftFields = 10
+)
+
+// checkTargetDrift 按间隔比较 Target 实际状态与模板声明的字段，并按 driftPolicy 处理漂移。
+// 先 patch 状态，成功后再发送 Event；Fail 策略下测试失败，返回 handled=true。
+// 读取 Target 失败不影响运行，等待下次检测。
+func (r *LoadTestReconciler) checkTargetDrift(ctx context.Context, lt *infrav1alpha1.LoadTest) (bool, ctrl.Result, error) {
+	spec := lt.Spec.Target.DriftDetection
+	if spec == nil || len(lt.Spec.Target.Resource.Manifest.Raw) == 0 {
+		return false, ctrl.Result{}, nil
+	}
+	interval := shared.GetTimeoutDuration(spec.IntervalSeconds, defaultDriftInterval)
+	if ds := lt.Status.Drift; ds != nil && ds.LastCheckTime != nil && time.Since(ds.LastCheckTime.Time) < interval {
+		return false, ctrl.Result{}, nil
+	}
+
+	log := logf.FromContext(ctx)
+	manifest, err := resource.ExpandRawTemplate(&lt.Spec.Target.Resource.Manifest, lt.Namespace)
+	if err != nil {
+		return false, ctrl.Result{}, fmt.Errorf("expand target template: %w", err)
+	}
+	live, err := r.getResourceByManifest(ctx, manifest)
+	if err != nil {
+		log.Info("skip drift check", "error", err.Error())
+		return false, ctrl.Result{}, nil
+	}
+
+	if lt.Status.Drift == nil {
+		lt.Status.Drift = &infrav1alpha1.DriftStatus{}
+	}
+	ds := lt.Status.Drift
+	now := metav1.Now()
+	ds.LastCheckTime = &now
+
+	fields := driftedFields(manifest.Object.Object, live.Object)
+	if len(fields) == 0 {
+		ds.Drifted = false
+		ds.Fields = nil
+		shared.SetCondition(&lt.Status.Conditions, ConditionTypeTargetDrifted, metav1.ConditionFalse, "NoDrift", "Target matches template", lt.Generation)
+		return false, ctrl.Result{}, shared.PatchLoadTestStatusFromObject(ctx, r.Client, lt)
+	}
+
+	newDrift := !ds.Drifted
+	if newDrift {
+		ds.DetectedCount++
+	}
+	ds.Drifted = true
+	ds.Fields = fields
+	msg := fmt.Sprintf("Target %s/%s drifted from template: %s", live.GetKind(), live.GetName(), strings.Join(fields, ", "))
+	log.Info("target drift detected", "fields", fields, "policy", driftPolicy(spec))
+
+	switch driftPolicy(spec) {
+	case infrav1alpha1.DriftPolicyFail:
+		shared.SetCondition(&lt.Status.Conditions, ConditionTypeTargetDrifted, metav1.ConditionTrue, "DriftDetected", msg, lt.Generation)
+		result, err := r.setFailed(ctx, lt, "TargetDrifted", msg)
+		return true, result, err
+
+	case infrav1alpha1.DriftPolicyReapply:
+		if err := r.ResourceManager.ApplyObject(ctx, lt, manifest.Object); err != nil {
+			shared.SetCondition(&lt.Status.Conditions, ConditionTypeTargetDrifted, metav1.ConditionTrue, "ReapplyFailed", fmt.Sprintf("%s; re-apply failed: %v", msg, err), lt.Generation)
+			if patchErr := shared.PatchLoadTestStatusFromObject(ctx, r.Client, lt); patchErr != nil {
+				return false, ctrl.Result{}, patchErr
+			}
+			if newDrift {
+				r.emitWarningEvent(lt, fmt.Sprintf("drift-%d", ds.DetectedCount), shared.EventReasonTargetDrifted, msg)
+			}
+			return false, ctrl.Result{}, nil
+		}
+		ds.ReappliedCount++
+		// 已恢复：下次检测到漂移时重新计数
+		ds.Drifted = false
+		shared.SetCondition(&lt.Status.Conditions, ConditionTypeTargetDrifted, metav1.ConditionFalse, "Reapplied", msg+"; template re-applied", lt.Generation)
+		if err := shared.PatchLoadTestStatusFromObject(ctx, r.Client, lt); err != nil {
+			return false, ctrl.Result{}, err
+		}
+		r.emitWarningEvent(lt, fmt.Sprintf("drift-%d", ds.DetectedCount), shared.EventReasonTargetReapplied, msg+"; template re-applied")
+		return false, ctrl.Result{}, nil
+
+	default: // Warn
+		shared.SetCondition(&lt.Status.Conditions, ConditionTypeTargetDrifted, metav1.ConditionTrue, "DriftDetected", msg, lt.Generation)
+		if err := shared.PatchLoadTestStatusFromObject(ctx, r.Client, lt); err != nil {
+			return false, ctrl.Result{}, err
+		}
+		if newDrift {
+			r.emitWarningEvent(lt, fmt.Sprintf("drift-%d", ds.DetectedCount), shared.EventReasonTargetDrifted, msg)
+		}
+		return false, ctrl.Result{}, nil
+	}
+}
+
+// driftPolicy 返回漂移处理策略，未设置时为 Warn。
+func driftPolicy(spec *infrav1alpha1.DriftDetection) infrav1alpha1.DriftPolicy {
+	if spec.DriftPolicy == "" {
+		return infrav1alpha1.DriftPolicyWarn
+	}
+	return spec.DriftPolicy
+}
+
+// driftedFields 比较模板声明的字段与实际对象，返回不一致的字段路径（排序后最多 maxDriftFields 个）。
+// 只比较模板中出现的字段：API Server 默认值、status 以及除 labels/annotations 外的 metadata 不参与比较。
+func driftedFields(desired, live map[string]interface{}) []string {
+	var fields []string
+	for key, want := range desired {
+		switch key {
+		case "apiVersion", "kind", "status":
+			continue
+		case "metadata":
+			wantMeta, _ := want.(map[string]interface{})
+			liveMeta, _ := live["metadata"].(map[string]interface{})
+			for _, metaKey := range []string{"labels", "annotations"} {
+				if v, ok := wantMeta[metaKey]; ok {
+					fields = collectDrift(fields, "metadata."+metaKey, v, liveMeta[metaKey])
+				}
+			}
+		default:
+			fields = collectDrift(fields, key, want, live[key])
+		}
+	}
+	sort.Strings(fields)
+	if len(fields) > maxDriftFields {
+		fields = fields[:maxDriftFields]
+	}
+	return fields
+}
+
+// collectDrift 递归比较 want 是否为 got 的子集，不一致时追加字段路径。
+// 列表要求长度一致并逐项比较，数值统一按 float64 比较。
+func collectDrift(fields []string, path string, want, got interface{}) []string {
+	switch w := want.(type) {
+	case map[string]interface{}:
+		g, ok := got.(map[string]interface{})
+		if !ok {
+			return append(fields, path)
+		}
+		for key, v := range w {
+			fields = collectDrift(fields, path+"."+key, v, g[key])
+		}
+		return fields
+	case []interface{}:
+		g, ok := got.([]interface{})
+		if !ok || len(g) != len(w) {
+			return append(fields, path)
+		}
+		for i := range w {
+			fields = collectDrift(fields, fmt.Sprintf("%s[%d]", path, i), w[i], g[i])
+		}
+		return fields
+	default:
+		if !scalarEqual(want, got) {
+			return append(fields, path)
+		}
+		return fields
+	}
+}
+
+// scalarEqual 比较标量值，int64/float64 等数值类型按数值比较。
+// API Server 会规范化资源数量（如 cpu 1000m → 1、memory 0.5Gi → 512Mi），两侧都能解析为数量时按数量比较。
+func scalarEqual(a, b interface{}) bool {
+	if fa, ok := toFloat(a); ok {
+		if fb, ok := toFloat(b); ok {
+			return fa == fb
+		}
+	}
+	if reflect.DeepEqual(a, b) {
+		return true
+	}
+	qa, ok := toQuantity(a)
+	if !ok {
+		return false
+	}
+	qb, ok := toQuantity(b)
+	return ok && qa.Cmp(qb) == 0
+}
+
+// toQuantity 将字符串或数值解析为资源数量。
+func toQuantity(v interface{}) (apiresource.Quantity, bool) {
+	s, ok := v.(string)
+	if !ok {
+		f, isNumber := toFloat(v)
+		if !isNumber {
+			return apiresource.Quantity{}, false
+		}
+		s = strconv.FormatFloat(f, 'f', -1, 64)
+	}
+	q, err := apiresource.ParseQuantity(s)
+	return q, err == nil
+}
+
+// toFloat 将数值类型转换为 float64。
+func toFloat(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case int:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case float64:
+		return n, true
+	}
+	return 0, false
+}
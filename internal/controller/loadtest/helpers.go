@@ -16,6 +16,14 @@ limitations under the License.
 
 package loadtest
 
+import (
+	"context"
+	"fmt"
+
+	infrav1alpha1 "github.com/lunz1207/testplane/api/v1alpha1"
+	"github.com/lunz1207/testplane/internal/controller/shared"
+)
+
 // Condition 类型常量
 const (
 	// ConditionTypeReady 表示 LoadTest 是否处于正常运行状态
@@ -24,6 +32,8 @@ const (
 	ConditionTypeTargetReady = "TargetReady"
 	// ConditionTypeExpectationsMet 表示断言检查是否通过
 	ConditionTypeExpectationsMet = "ExpectationsMet"
+	// ConditionTypeTargetDrifted 表示目标资源是否偏离模板（启用漂移检测时设置）
+	ConditionTypeTargetDrifted = "TargetDrifted"
 )
 
 // getOrDefaultInt32 返回非零值或默认值。
@@ -33,3 +43,50 @@ func getOrDefaultInt32(value, defaultVal int32) int32 {
 	}
 	return value
 }
+
+// emitNormalEvent 发送带幂等键的 Normal 事件，step 为空表示测试级事件。
+// LoadTest 没有轮次，幂等键的轮次固定为 0。
+func (r *LoadTestReconciler) emitNormalEvent(lt *infrav1alpha1.LoadTest, step, reason, message string) {
+	shared.EmitKeyedNormalEvent(r.Recorder, lt, shared.EventKey(lt, 0, step, reason), reason, message)
+}
+
+// emitWarningEvent 发送带幂等键的 Warning 事件，step 为空表示测试级事件。
+func (r *LoadTestReconciler) emitWarningEvent(lt *infrav1alpha1.LoadTest, step, reason, message string) {
+	shared.EmitKeyedWarningEvent(r.Recorder, lt, shared.EventKey(lt, 0, step, reason), reason, message)
+}
+
+// emitHealthCheckEvent 发送健康检查事件：每次检查一个幂等键，所有检查的同类结果合并为一个 Event 系列。
+func (r *LoadTestReconciler) emitHealthCheckEvent(lt *infrav1alpha1.LoadTest, checkCount int32, eventType, reason, message string) {
+	key := shared.EventKey(lt, 0, fmt.Sprintf("healthcheck-%d", checkCount), reason)
+	shared.EmitSeriesEvent(r.Recorder, lt, key, shared.EventSeriesKey(lt, "healthcheck", reason), eventType, reason, message)
+}
+
+// latestStatus 从 API Server 读取最新状态，读取失败或未配置 APIReader 时返回 nil。
+func (r *LoadTestReconciler) latestStatus(ctx context.Context, lt *infrav1alpha1.LoadTest) *infrav1alpha1.LoadTestStatus {
+	var latest infrav1alpha1.LoadTest
+	if !shared.ReadLatest(ctx, r.APIReader, lt, &latest) {
+		return nil
+	}
+	return &latest.Status
+}
+
+// phaseAlreadyAdvanced 从 API Server 读取最新状态，检查阶段是否已被之前的 reconcile 推进。
+// 用于在阶段转换前检查，避免缓存延迟导致的重复转换和重复事件。
+func (r *LoadTestReconciler) phaseAlreadyAdvanced(ctx context.Context, lt *infrav1alpha1.LoadTest) bool {
+	latest := r.latestStatus(ctx, lt)
+	return latest != nil && latest.Phase != lt.Status.Phase
+}
+
+// testAlreadyCompleted 从 API Server 读取最新状态，检查测试是否已完成。
+// 用于在 patch 前检查，避免缓存延迟导致的重复事件。
+func (r *LoadTestReconciler) testAlreadyCompleted(ctx context.Context, lt *infrav1alpha1.LoadTest) bool {
+	latest := r.latestStatus(ctx, lt)
+	return latest != nil && latest.CompletionTime != nil
+}
+
+// healthCheckAlreadyRecorded 从 API Server 读取最新状态，检查第 checkCount 次健康检查是否已被记录。
+// 用于在 patch 前检查，避免缓存延迟导致同一次检查重复记录和重复事件。
+func (r *LoadTestReconciler) healthCheckAlreadyRecorded(ctx context.Context, lt *infrav1alpha1.LoadTest, checkCount int32) bool {
+	latest := r.latestStatus(ctx, lt)
+	return latest != nil && latest.HealthCheckStatus != nil && latest.HealthCheckStatus.CheckCount >= checkCount
+}
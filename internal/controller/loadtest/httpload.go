@@ -0,0 +1,226 @@
+package loadtest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	infrav1alpha1 "github.com/lunz1207/testplane/api/v1alpha1"
+	"github.com/lunz1207/testplane/internal/controller/shared"
+	"github.com/lunz1207/testplane/internal/controller/shared/resource"
+	"github.com/lunz1207/testplane/internal/loadgen"
+)
+
+const (
+	// labelLoadTestOwner 控制器生成资源（worker Pod、验证测试）所属 LoadTest 的标签。
+	labelLoadTestOwner = "infra.testplane.io/loadtest"
+	// labelComponent 控制器生成资源的组件标签。
+	labelComponent = "infra.testplane.io/component"
+	// componentHTTPLoad 内置 HTTP 负载 worker 组件名。
+	componentHTTPLoad = "httpload"
+
+	// httpLoadStatsInterval 汇总 worker 统计的最小间隔。
+	httpLoadStatsInterval = 10 * time.Second
+	// httpLoadStatsTimeout 读取单个 worker 统计的超时。
+	httpLoadStatsTimeout = 3 * time.Second
+)
+
+// httpLoadName worker Deployment 名称。
+func httpLoadName(lt *infrav1alpha1.LoadTest) string {
+	return lt.Name + "-" + componentHTTPLoad
+}
+
+// buildHTTPLoadManifest 生成内置 HTTP 负载的 worker Deployment。
+// 注入值以环境变量提供给 worker，URL 中的 $(NAME) 由 kubelet 在启动参数中展开。
+func (r *LoadTestReconciler) buildHTTPLoadManifest(lt *infrav1alpha1.LoadTest) (resource.ExpandedManifest, error) {
+	spec := lt.Spec.Workload.HTTPLoad
+	image := spec.Image
+	if image == "" {
+		image = r.LoadGenImage
+	}
+	if image == "" {
+		return resource.ExpandedManifest{}, fmt.Errorf("httpLoad requires spec.workload.httpLoad.image or controller flag --loadgen-image")
+	}
+
+	replicas := getOrDefaultInt32(spec.Replicas, 1)
+	rps := float64(spec.RPS) / float64(replicas)
+	args := []string{
+		"loadgen",
+		"--url=" + spec.URL,
+		"--method=" + valueOrDefault(spec.Method, http.MethodGet),
+		"--rps=" + strconv.FormatFloat(rps, 'f', -1, 64),
+		fmt.Sprintf("--concurrency=%d", getOrDefaultInt32(spec.Concurrency, 10)),
+		fmt.Sprintf("--timeout=%ds", getOrDefaultInt32(spec.TimeoutSeconds, 10)),
+		fmt.Sprintf("--duration=%ds", spec.DurationSeconds),
+	}
+	if spec.Body != "" {
+		args = append(args, "--body="+spec.Body)
+	}
+	headerNames := make([]string, 0, len(spec.Headers))
+	for name := range spec.Headers {
+		headerNames = append(headerNames, name)
+	}
+	sort.Strings(headerNames)
+	for _, name := range headerNames {
+		args = append(args, "--header="+name+"="+spec.Headers[name])
+	}
+
+	// 按名称排序，保证重复 apply 时 Pod 模板不变
+	envNames := make([]string, 0, len(lt.Status.InjectedValues))
+	for name := range lt.Status.InjectedValues {
+		envNames = append(envNames, name)
+	}
+	sort.Strings(envNames)
+	env := make([]corev1.EnvVar, 0, len(envNames))
+	for _, name := range envNames {
+		env = append(env, corev1.EnvVar{Name: name, Value: lt.Status.InjectedValues[name]})
+	}
+
+	labels := map[string]string{
+		labelLoadTestOwner: lt.Name,
+		labelComponent:     componentHTTPLoad,
+	}
+	deploy := &appsv1.Deployment{
+		TypeMeta: metav1.TypeMeta{APIVersion: "apps/v1", Kind: "Deployment"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      httpLoadName(lt),
+			Namespace: lt.Namespace,
+			Labels:    labels,
+		},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: &replicas,
+			Selector: &metav1.LabelSelector{MatchLabels: labels},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: labels},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{
+						Name:    "loadgen",
+						Image:   image,
+						Command: []string{"/manager"},
+						Args:    args,
+						Env:     env,
+						Ports: []corev1.ContainerPort{{
+							Name:          "stats",
+							ContainerPort: loadgen.StatsPort,
+						}},
+					}},
+				},
+			},
+		},
+	}
+
+	obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(deploy)
+	if err != nil {
+		return resource.ExpandedManifest{}, fmt.Errorf("convert httpLoad deployment: %w", err)
+	}
+	return resource.ExpandedManifest{
+		Object: &unstructured.Unstructured{Object: obj},
+		Action: infrav1alpha1.TemplateActionApply,
+	}, nil
+}
+
+// refreshHTTPLoadStatus 汇总 worker 统计到 status.httpLoad 并 patch。
+// 按 httpLoadStatsInterval 节流；单个 worker 读取失败时跳过该 worker。
+func (r *LoadTestReconciler) refreshHTTPLoadStatus(ctx context.Context, lt *infrav1alpha1.LoadTest) error {
+	if lt.Spec.Workload.HTTPLoad == nil {
+		return nil
+	}
+	if s := lt.Status.HTTPLoad; s != nil && s.LastUpdateTime != nil && time.Since(s.LastUpdateTime.Time) < httpLoadStatsInterval {
+		return nil
+	}
+
+	reader := r.APIReader
+	if reader == nil {
+		reader = r.Client
+	}
+	var pods corev1.PodList
+	if err := reader.List(ctx, &pods, client.InNamespace(lt.Namespace), client.MatchingLabels{
+		labelLoadTestOwner: lt.Name,
+		labelComponent:     componentHTTPLoad,
+	}); err != nil {
+		return fmt.Errorf("list httpLoad workers: %w", err)
+	}
+
+	log := logf.FromContext(ctx)
+	snapshots := make([]loadgen.Snapshot, 0, len(pods.Items))
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if pod.Status.PodIP == "" || pod.DeletionTimestamp != nil {
+			continue
+		}
+		snap, err := fetchWorkerStats(ctx, pod.Status.PodIP)
+		if err != nil {
+			log.Info("skip httpLoad worker stats", "pod", pod.Name, "error", err.Error())
+			continue
+		}
+		snapshots = append(snapshots, snap)
+	}
+
+	lt.Status.HTTPLoad = httpLoadStatusFrom(loadgen.Merge(snapshots), int32(len(snapshots)))
+	return shared.PatchLoadTestStatusFromObject(ctx, r.Client, lt)
+}
+
+// httpLoadStatusFrom 将汇总快照转换为 status。
+func httpLoadStatusFrom(merged loadgen.Snapshot, workers int32) *infrav1alpha1.HTTPLoadStatus {
+	now := metav1.Now()
+	status := &infrav1alpha1.HTTPLoadStatus{
+		LastUpdateTime: &now,
+		Workers:        workers,
+		Completed:      merged.Done,
+		Requests:       merged.Requests,
+		Errors:         merged.Errors,
+		Dropped:        merged.Dropped,
+		LatencyP50Ms:   merged.Quantile(0.50),
+		LatencyP95Ms:   merged.Quantile(0.95),
+		LatencyP99Ms:   merged.Quantile(0.99),
+	}
+	if merged.Requests > 0 {
+		status.ErrorRate = strconv.FormatFloat(float64(merged.Errors)/float64(merged.Requests), 'f', 4, 64)
+	}
+	return status
+}
+
+// fetchWorkerStats 读取单个 worker 的统计快照。
+func fetchWorkerStats(ctx context.Context, podIP string) (loadgen.Snapshot, error) {
+	ctx, cancel := context.WithTimeout(ctx, httpLoadStatsTimeout)
+	defer cancel()
+
+	var snap loadgen.Snapshot
+	endpoint := fmt.Sprintf("http://%s:%d%s", podIP, loadgen.StatsPort, loadgen.StatsPath)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return snap, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return snap, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return snap, fmt.Errorf("stats returned status %d", resp.StatusCode)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&snap); err != nil {
+		return snap, fmt.Errorf("decode stats: %w", err)
+	}
+	return snap, nil
+}
+
+// valueOrDefault 返回非空值或默认值。
+func valueOrDefault(value, defaultVal string) string {
+	if value == "" {
+		return defaultVal
+	}
+	return value
+}
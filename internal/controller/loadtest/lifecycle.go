@@ -18,6 +18,8 @@ package loadtest
 
 import (
 	"context"
+	"fmt"
+	"time"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -26,30 +28,49 @@ import (
 	infrav1alpha1 "github.com/lunz1207/testplane/api/v1alpha1"
 	"github.com/lunz1207/testplane/internal/controller/shared"
 	"github.com/lunz1207/testplane/internal/controller/shared/logging"
+	"github.com/lunz1207/testplane/pkg/impact"
 )
 
 // initializeLoadTest 初始化 LoadTest 状态。
 func (r *LoadTestReconciler) initializeLoadTest(ctx context.Context, lt *infrav1alpha1.LoadTest) (ctrl.Result, error) {
 	log := logf.FromContext(ctx)
+
+	// 缓存尚未同步上一次 status 写入：已初始化，避免重复事件
+	if r.phaseAlreadyAdvanced(ctx, lt) {
+		return ctrl.Result{Requeue: true}, nil
+	}
 	log.Info("initializing")
 
 	now := metav1.Now()
 	lt.Status.Phase = infrav1alpha1.LoadTestPending
 	lt.Status.StartTime = &now
 	lt.Status.ObservedGeneration = lt.Generation
+	lt.Status.TouchedKinds = impact.LoadTest(&lt.Spec)
+	if err := shared.SyncPrefixedLabels(ctx, r.Client, lt, infrav1alpha1.LabelTouchedKindPrefix, impact.Labels(lt.Status.TouchedKinds)); err != nil {
+		return ctrl.Result{}, err
+	}
 
 	// 设置初始 Conditions
 	shared.SetCondition(&lt.Status.Conditions, ConditionTypeReady, metav1.ConditionFalse, "Initializing", "LoadTest is initializing", lt.Generation)
 	shared.SetCondition(&lt.Status.Conditions, ConditionTypeTargetReady, metav1.ConditionUnknown, "Pending", "Target readiness not yet checked", lt.Generation)
 
-	if err := shared.PatchStatusMerge(ctx, r.Client, lt); err != nil {
+	if err := shared.PatchLoadTestStatusFromObject(ctx, r.Client, lt); err != nil {
 		return ctrl.Result{}, err
 	}
 
-	shared.EmitNormalEvent(r.Recorder, lt, shared.EventReasonLoadTestStarted, "LoadTest started")
+	r.emitNormalEvent(lt, "", shared.EventReasonLoadTestStarted, "LoadTest started")
+	r.reconcileMonitoring(ctx, lt)
 	return ctrl.Result{Requeue: true}, nil
 }
 
+// reconcileMonitoring 应用监控资源。监控是辅助功能，失败只记录 Warning 事件，不影响测试。
+func (r *LoadTestReconciler) reconcileMonitoring(ctx context.Context, lt *infrav1alpha1.LoadTest) {
+	if err := r.applyMonitoring(ctx, lt); err != nil {
+		logf.FromContext(ctx).Error(err, "failed to apply monitoring resources")
+		r.emitWarningEvent(lt, fmt.Sprintf("monitoring-%d", lt.Generation), shared.EventReasonMonitoringApplyFailed, err.Error())
+	}
+}
+
 // reconcilePending 处理 Pending 阶段。
 func (r *LoadTestReconciler) reconcilePending(ctx context.Context, lt *infrav1alpha1.LoadTest) (ctrl.Result, error) {
 	log := logf.FromContext(ctx)
@@ -57,7 +78,7 @@ func (r *LoadTestReconciler) reconcilePending(ctx context.Context, lt *infrav1al
 
 	lt.Status.Phase = infrav1alpha1.LoadTestInitializing
 
-	if err := shared.PatchStatusMerge(ctx, r.Client, lt); err != nil {
+	if err := shared.PatchLoadTestStatusFromObject(ctx, r.Client, lt); err != nil {
 		return ctrl.Result{}, err
 	}
 
@@ -65,34 +86,57 @@ func (r *LoadTestReconciler) reconcilePending(ctx context.Context, lt *infrav1al
 }
 
 // reconcileTerminal 处理终态。
-// workload 通过 OwnerReference 由 K8s 自动清理。
+// workload 通过 OwnerReference 由 K8s 自动清理；失败时收集工件，并按 spec.triage 调用分诊 Webhook。
 func (r *LoadTestReconciler) reconcileTerminal(ctx context.Context, lt *infrav1alpha1.LoadTest) (ctrl.Result, error) {
-	// 设置完成时间
-	if lt.Status.CompletionTime == nil {
+	// 设置完成时间（检查 API Server 最新状态，避免重复事件）
+	if lt.Status.CompletionTime == nil && !r.testAlreadyCompleted(ctx, lt) {
 		now := metav1.Now()
 		lt.Status.CompletionTime = &now
+		// 失败时 workload 仍在运行，收集工件（成功路径已在停止 workload 前收集）
+		r.collectArtifacts(ctx, lt)
 
 		// 只在 Succeeded 状态下设置 Ready Condition 为 True
 		if lt.Status.Phase == infrav1alpha1.LoadTestSucceeded {
 			shared.SetCondition(&lt.Status.Conditions, ConditionTypeReady, metav1.ConditionTrue, "Succeeded", "LoadTest completed successfully", lt.Generation)
 		}
+		summary := summaryMessage(&lt.Status)
+		shared.SetCondition(&lt.Status.Conditions, shared.ConditionTypeSummary, metav1.ConditionTrue, string(lt.Status.Phase), summary, lt.Generation)
 
-		if err := shared.PatchStatusMerge(ctx, r.Client, lt); err != nil {
+		if err := shared.PatchLoadTestStatusFromObject(ctx, r.Client, lt); err != nil {
 			return ctrl.Result{}, err
 		}
 
 		// 只在 Succeeded 状态下发送成功事件
 		if lt.Status.Phase == infrav1alpha1.LoadTestSucceeded {
-			shared.EmitNormalEvent(r.Recorder, lt, shared.EventReasonLoadTestSucceeded, "LoadTest completed successfully")
+			r.emitNormalEvent(lt, "", shared.EventReasonLoadTestSucceeded, "LoadTest completed successfully")
+			r.emitNormalEvent(lt, "", shared.EventReasonTestSummary, fmt.Sprintf("LoadTest %s: %s", lt.Status.Phase, summary))
+		} else {
+			r.emitWarningEvent(lt, "", shared.EventReasonTestSummary, fmt.Sprintf("LoadTest %s: %s", lt.Status.Phase, summary))
 		}
 	}
 
-	return ctrl.Result{}, nil
+	return r.triageFailure(ctx, lt)
+}
+
+// summaryMessage 生成结果摘要：健康检查次数与通过率（不含出错与预热期的检查）、总时长。
+func summaryMessage(status *infrav1alpha1.LoadTestStatus) string {
+	checks := status.HealthCheckStatus
+	if checks == nil {
+		checks = &infrav1alpha1.HealthCheckStatus{}
+	}
+	return fmt.Sprintf("checks=%d passed=%d failed=%d errored=%d warmup=%d passRate=%s duration=%s",
+		checks.CheckCount, checks.PassCount, checks.FailCount, checks.ErrorCount, checks.WarmupCount,
+		shared.PassRate(checks.PassCount, checks.FailCount), shared.SummaryDuration(status.StartTime, status.CompletionTime, time.Now()))
 }
 
 // setFailed 设置失败状态。
 func (r *LoadTestReconciler) setFailed(ctx context.Context, lt *infrav1alpha1.LoadTest, reason, message string) (ctrl.Result, error) {
 	log := logf.FromContext(ctx)
+
+	// 缓存尚未同步上一次 status 写入：阶段已推进（可能已失败），本次判定基于旧状态，跳过
+	if r.phaseAlreadyAdvanced(ctx, lt) {
+		return ctrl.Result{Requeue: true}, nil
+	}
 	logging.StepFailed(log, reason, message)
 
 	lt.Status.Phase = infrav1alpha1.LoadTestFailed
@@ -102,11 +146,11 @@ func (r *LoadTestReconciler) setFailed(ctx context.Context, lt *infrav1alpha1.Lo
 	// 设置 Ready Condition 为 False
 	shared.SetCondition(&lt.Status.Conditions, ConditionTypeReady, metav1.ConditionFalse, reason, message, lt.Generation)
 
-	if err := shared.PatchStatusMerge(ctx, r.Client, lt); err != nil {
+	if err := shared.PatchLoadTestStatusFromObject(ctx, r.Client, lt); err != nil {
 		return ctrl.Result{}, err
 	}
 
-	shared.EmitWarningEvent(r.Recorder, lt, shared.EventReasonLoadTestFailed, message)
-	// Failed 是终态，无需 Requeue
-	return ctrl.Result{}, nil
+	r.emitWarningEvent(lt, "", shared.EventReasonLoadTestFailed, message)
+	// 终态收尾（完成时间、失败分诊）由下一次调和处理；status 写入不会触发 watch，需显式 Requeue
+	return ctrl.Result{Requeue: true}, nil
 }
@@ -0,0 +1,275 @@
+package loadtest
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	infrav1alpha1 "github.com/lunz1207/testplane/api/v1alpha1"
+	"github.com/lunz1207/testplane/internal/controller/shared"
+	"github.com/lunz1207/testplane/internal/controller/shared/resource"
+)
+
+// defaultLoadControlInterval 默认闭环调整间隔。
+const defaultLoadControlInterval = 30 * time.Second
+
+// reconcileLoadControl 闭环负载控制：按间隔查询指标，偏离目标值时调整负载参数，
+// 连续 stableSamples 次落在允许偏差内时记录收敛值。
+// 读取参数或查询指标失败只记录到 status.loadControl.message，不影响测试。
+func (r *LoadTestReconciler) reconcileLoadControl(ctx context.Context, lt *infrav1alpha1.LoadTest) error {
+	spec := lt.Spec.LoadControl
+	if spec == nil {
+		return nil
+	}
+	interval := shared.GetTimeoutDuration(spec.IntervalSeconds, defaultLoadControlInterval)
+	if st := lt.Status.LoadControl; st != nil && st.LastSampleTime != nil && time.Since(st.LastSampleTime.Time) < interval {
+		return nil
+	}
+	setpoint, err := parseSample(spec.Setpoint)
+	if err != nil {
+		return fmt.Errorf("invalid loadControl setpoint: %w", err)
+	}
+
+	if lt.Status.LoadControl == nil {
+		lt.Status.LoadControl = &infrav1alpha1.LoadControlStatus{}
+	}
+	st := lt.Status.LoadControl
+	now := metav1.Now()
+	st.LastSampleTime = &now
+
+	param := spec.Parameter
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion(param.APIVersion)
+	obj.SetKind(param.Kind)
+	if err := r.Get(ctx, client.ObjectKey{Namespace: lt.Namespace, Name: param.Name}, obj); err != nil {
+		st.Message = fmt.Sprintf("get %s/%s: %v", param.Kind, param.Name, err)
+		return shared.PatchLoadTestStatusFromObject(ctx, r.Client, lt)
+	}
+	current, err := readLoadParameter(obj, param)
+	if err != nil {
+		st.Message = err.Error()
+		return shared.PatchLoadTestStatusFromObject(ctx, r.Client, lt)
+	}
+	st.CurrentValue = current
+
+	metric, err := queryPrometheus(ctx, spec.Metric)
+	if err != nil {
+		st.Message = fmt.Sprintf("query metric: %v", err)
+		return shared.PatchLoadTestStatusFromObject(ctx, r.Client, lt)
+	}
+	st.LastMetric = strconv.FormatFloat(metric, 'f', -1, 64)
+
+	next, inBand := nextLoadValue(spec, current, metric, setpoint)
+	converged := false
+	if inBand {
+		st.StableCount++
+		st.Message = fmt.Sprintf("metric %s within tolerance of setpoint %s", st.LastMetric, spec.Setpoint)
+		if !st.Converged && st.StableCount >= getOrDefaultInt32(spec.StableSamples, 3) {
+			st.Converged = true
+			st.ConvergedValue = current
+			converged = true
+		}
+	} else {
+		st.StableCount = 0
+		st.Converged = false
+		if next == current {
+			st.Message = fmt.Sprintf("metric %s, setpoint %s, parameter at bound %d", st.LastMetric, spec.Setpoint, current)
+		} else {
+			st.Message = fmt.Sprintf("metric %s, setpoint %s, parameter %d", st.LastMetric, spec.Setpoint, current)
+		}
+	}
+
+	adjusted := false
+	if next != current {
+		if err := r.applyLoadParameter(ctx, lt, next); err != nil {
+			st.Message = err.Error()
+		} else {
+			st.Adjustments++
+			st.CurrentValue = next
+			st.Message = fmt.Sprintf("adjusted %d -> %d (metric %s, setpoint %s)", current, next, st.LastMetric, spec.Setpoint)
+			adjusted = true
+		}
+	}
+
+	if err := shared.PatchLoadTestStatusFromObject(ctx, r.Client, lt); err != nil {
+		return err
+	}
+
+	// patch 成功后发送 Event
+	if adjusted {
+		logf.FromContext(ctx).Info("load adjusted", "from", current, "to", next, "metric", st.LastMetric)
+		r.emitLoadControlEvent(lt, fmt.Sprintf("loadcontrol-%d", st.Adjustments), shared.EventReasonLoadAdjusted, st.Message)
+	}
+	if converged {
+		r.emitLoadControlEvent(lt, fmt.Sprintf("loadcontrol-converged-%d", st.Adjustments), shared.EventReasonLoadConverged,
+			fmt.Sprintf("Load converged at %d (metric %s, setpoint %s)", current, st.LastMetric, spec.Setpoint))
+	}
+	return nil
+}
+
+// nextLoadValue 计算下一次的参数值，返回 (next, inBand)。
+// 指标在允许偏差内时保持不变；否则按 setpoint/metric 比例调整，单次变化不超过 maxStep 且至少为 1，并限制在 [min, max]。
+func nextLoadValue(spec *infrav1alpha1.LoadControlSpec, current int32, metric, setpoint float64) (int32, bool) {
+	tolerance := float64(getOrDefaultInt32(spec.TolerancePercent, 5)) / 100
+	if math.Abs(metric-setpoint) <= tolerance*math.Abs(setpoint) {
+		return current, true
+	}
+
+	maxStep := float64(getOrDefaultInt32(spec.MaxStep, 1))
+	delta := maxStep
+	if metric > 0 {
+		delta = math.Max(-maxStep, math.Min(maxStep, math.Round(float64(current)*(setpoint/metric-1))))
+	}
+	if delta == 0 {
+		delta = 1
+		if metric > setpoint {
+			delta = -1
+		}
+	}
+
+	next := int32(float64(current) + delta)
+	if next < spec.Min {
+		next = spec.Min
+	}
+	if next > spec.Max {
+		next = spec.Max
+	}
+	return next, false
+}
+
+// applyLoadParameter 以控制器的字段管理者（SSA）重新应用参数所在的 workload 资源。
+// 与部署 workload 使用同一份清单与字段所有权，调整后的值不会被重新部署覆盖，也不会与之争夺字段。
+func (r *LoadTestReconciler) applyLoadParameter(ctx context.Context, lt *infrav1alpha1.LoadTest, value int32) error {
+	param := lt.Spec.LoadControl.Parameter
+	specs, err := r.workloadManifests(lt)
+	if err != nil {
+		return err
+	}
+	obj := loadParameterManifest(specs, param)
+	if obj == nil {
+		return fmt.Errorf("%s/%s is not a workload resource of this LoadTest", param.Kind, param.Name)
+	}
+	if err := writeLoadParameter(obj, param, value); err != nil {
+		return err
+	}
+	if err := r.ResourceManager.ApplyObject(ctx, lt, obj); err != nil {
+		return fmt.Errorf("apply %s/%s: %w", param.Kind, param.Name, err)
+	}
+	return nil
+}
+
+// loadParameterManifest 返回 workload 清单中负载参数所指的资源，不在清单中时返回 nil。
+func loadParameterManifest(specs []resource.ExpandedManifest, param infrav1alpha1.LoadParameter) *unstructured.Unstructured {
+	for _, spec := range specs {
+		obj := spec.Object
+		if !spec.IsDelete() && obj.GetAPIVersion() == param.APIVersion && obj.GetKind() == param.Kind && obj.GetName() == param.Name {
+			return obj
+		}
+	}
+	return nil
+}
+
+// podSpecPath 返回 workload 资源中 Pod spec 的路径。
+func podSpecPath(kind string) []string {
+	switch kind {
+	case "Pod":
+		return []string{"spec"}
+	case "CronJob":
+		return []string{"spec", "jobTemplate", "spec", "template", "spec"}
+	default:
+		return []string{"spec", "template", "spec"}
+	}
+}
+
+// readLoadParameter 读取负载参数的当前值；未设置 replicas 时按 1 处理。
+func readLoadParameter(obj *unstructured.Unstructured, param infrav1alpha1.LoadParameter) (int32, error) {
+	if param.Type == infrav1alpha1.LoadParameterReplicas {
+		replicas, found, err := unstructured.NestedInt64(obj.Object, "spec", "replicas")
+		if err != nil {
+			return 0, fmt.Errorf("read spec.replicas: %w", err)
+		}
+		if !found {
+			return 1, nil
+		}
+		return int32(replicas), nil
+	}
+
+	containers, idx, err := findContainer(obj, param)
+	if err != nil {
+		return 0, err
+	}
+	container, _ := containers[idx].(map[string]interface{})
+	envs, _, _ := unstructured.NestedSlice(container, "env")
+	for _, e := range envs {
+		env, ok := e.(map[string]interface{})
+		if !ok || env["name"] != param.EnvName {
+			continue
+		}
+		value, _ := env["value"].(string)
+		v, err := strconv.ParseInt(value, 10, 32)
+		if err != nil {
+			return 0, fmt.Errorf("env %s value %q is not an integer", param.EnvName, value)
+		}
+		return int32(v), nil
+	}
+	return 0, fmt.Errorf("env %s not found in container %v", param.EnvName, container["name"])
+}
+
+// writeLoadParameter 设置负载参数的值。
+func writeLoadParameter(obj *unstructured.Unstructured, param infrav1alpha1.LoadParameter, value int32) error {
+	if param.Type == infrav1alpha1.LoadParameterReplicas {
+		return unstructured.SetNestedField(obj.Object, int64(value), "spec", "replicas")
+	}
+
+	containers, idx, err := findContainer(obj, param)
+	if err != nil {
+		return err
+	}
+	container, _ := containers[idx].(map[string]interface{})
+	envs, _, _ := unstructured.NestedSlice(container, "env")
+	for i := range envs {
+		env, ok := envs[i].(map[string]interface{})
+		if ok && env["name"] == param.EnvName {
+			env["value"] = strconv.Itoa(int(value))
+			envs[i] = env
+		}
+	}
+	if err := unstructured.SetNestedSlice(container, envs, "env"); err != nil {
+		return fmt.Errorf("set env %s: %w", param.EnvName, err)
+	}
+	containers[idx] = container
+	path := append(podSpecPath(obj.GetKind()), "containers")
+	return unstructured.SetNestedSlice(obj.Object, containers, path...)
+}
+
+// findContainer 返回 Pod 模板的容器列表与目标容器下标（未指定名称时为第一个容器）。
+func findContainer(obj *unstructured.Unstructured, param infrav1alpha1.LoadParameter) ([]interface{}, int, error) {
+	path := append(podSpecPath(obj.GetKind()), "containers")
+	containers, found, err := unstructured.NestedSlice(obj.Object, path...)
+	if err != nil || !found || len(containers) == 0 {
+		return nil, 0, fmt.Errorf("%s/%s has no containers", obj.GetKind(), obj.GetName())
+	}
+	if param.Container == "" {
+		return containers, 0, nil
+	}
+	for i, c := range containers {
+		if m, ok := c.(map[string]interface{}); ok && m["name"] == param.Container {
+			return containers, i, nil
+		}
+	}
+	return nil, 0, fmt.Errorf("container %s not found in %s/%s", param.Container, obj.GetKind(), obj.GetName())
+}
+
+// emitLoadControlEvent 发送闭环控制事件：每次调整一个幂等键，同类事件合并为一个 Event 系列。
+func (r *LoadTestReconciler) emitLoadControlEvent(lt *infrav1alpha1.LoadTest, step, reason, message string) {
+	key := shared.EventKey(lt, 0, step, reason)
+	shared.EmitSeriesEvent(r.Recorder, lt, key, shared.EventSeriesKey(lt, "loadcontrol", reason), corev1.EventTypeNormal, reason, message)
+}
@@ -18,20 +18,27 @@ package loadtest
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 
 	infrav1alpha1 "github.com/lunz1207/testplane/api/v1alpha1"
 	"github.com/lunz1207/testplane/internal/controller/shared"
 	"github.com/lunz1207/testplane/internal/controller/shared/logging"
+	"github.com/lunz1207/testplane/internal/controller/shared/metrics"
 	"github.com/lunz1207/testplane/internal/controller/shared/resource"
 	"github.com/lunz1207/testplane/internal/plugin"
+	"github.com/lunz1207/testplane/pkg/artifacts"
+	"github.com/lunz1207/testplane/pkg/hooks"
+	"github.com/lunz1207/testplane/pkg/recording"
 )
 
 const (
@@ -49,16 +56,34 @@ type LoadTestReconciler struct {
 	APIReader       client.Reader // 用于 waitResourcesConverge 绕过缓存检查收敛
 	Recorder        record.EventRecorder
 	ResourceManager *resource.Manager
+	SnapshotArchive recording.Archive      // 状态快照归档（可选，用于录制与重放）
+	LoadGenImage    string                 // 内置 HTTP 负载 worker 的默认镜像
+	Exporter        *shared.ResultExporter // 结果导出（可选）
+	Debounce        time.Duration          // 更新事件合并窗口（可选）
+	ArtifactStore   artifacts.Store        // 工件存储（可选，未配置时 spec.artifacts 记录为收集失败）
+	PodExecutor     shared.PodExecutor     // 收集工件时在 Pod 中执行 tar
+	Hooks           *hooks.Registry        // 阶段钩子（可选）
 }
 
 // +kubebuilder:rbac:groups=infra.testplane.io,resources=loadtests,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=infra.testplane.io,resources=loadtests/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=infra.testplane.io,resources=loadtests/finalizers,verbs=update
+// +kubebuilder:rbac:groups=infra.testplane.io,resources=environments,verbs=get;list;watch
 // 需要操作任意资源用于负载测试。
 // +kubebuilder:rbac:groups=*,resources=*,verbs=get;list;watch;create;update;patch;delete
 
 func (r *LoadTestReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	// 按调和结果记录耗时、重新入队与错误指标
+	ctx, done := metrics.Start(ctx, "loadtest")
+	result, err := r.reconcile(ctx, req)
+	done(result, err)
+	return result, err
+}
+
+func (r *LoadTestReconciler) reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	baseLog := logf.FromContext(ctx)
+	// API 请求的 User-Agent 附带测试对象，便于 API Server 端按测试归属负载
+	ctx = shared.WithRequestUserAgent(ctx, "LoadTest", req.NamespacedName)
 
 	var lt infrav1alpha1.LoadTest
 	if err := r.Get(ctx, req.NamespacedName, &lt); err != nil {
@@ -69,12 +94,25 @@ func (r *LoadTestReconciler) Reconcile(ctx context.Context, req ctrl.Request) (c
 	log := logging.WithKindName(baseLog, "LoadTest", lt.Namespace, lt.Name)
 	ctx = logf.IntoContext(ctx, log)
 
+	// 故障注入（调试用）：注解解析失败时忽略注入，不影响正常执行
+	if injCtx, err := shared.ContextWithFailureInjection(ctx, lt.GetAnnotations()); err != nil {
+		log.Info("ignore invalid failure injection annotation", "error", err.Error())
+	} else {
+		ctx = injCtx
+	}
+	ctx = shared.ContextWithRecording(ctx, r.SnapshotArchive, &lt, "LoadTest")
+
 	r.ensurePluginRegistry()
 	r.ensureResourceManager()
 
 	// 处理删除
 	if !lt.DeletionTimestamp.IsZero() {
-		return shared.HandleDeletion(ctx, r.Client, &lt, loadTestFinalizer)
+		return r.handleDeletion(ctx, &lt)
+	}
+
+	// 模板只供 TestSuite 复制，不执行
+	if shared.IsTemplate(lt.GetAnnotations()) {
+		return ctrl.Result{}, nil
 	}
 
 	// 添加 finalizer
@@ -82,16 +120,46 @@ func (r *LoadTestReconciler) Reconcile(ctx context.Context, req ctrl.Request) (c
 		return shared.EnsureFinalizer(ctx, r.Client, &lt, loadTestFinalizer)
 	}
 
+	// base 引用的基础清单与 patches 合成到内存中的 spec，之后的 ${env.*} 等替换作用于合成结果
+	if err := r.resolveManifests(ctx, &lt); err != nil && lt.Status.Phase != infrav1alpha1.LoadTestSucceeded && lt.Status.Phase != infrav1alpha1.LoadTestFailed {
+		log.Error(err, "resolve manifest base failed")
+		return ctrl.Result{}, err
+	}
+
+	// 引用的 Environment 展开到内存中的 spec；终态只用于失败分诊，解析失败不影响
+	env, err := shared.ResolveEnvironment(ctx, r.Client, lt.Namespace, lt.Spec.EnvironmentRef)
+	if err == nil {
+		err = shared.ExpandEnvironment(env, &lt.Spec)
+	}
+	switch {
+	case err == nil:
+		ctx = shared.ContextWithEnvironment(ctx, env)
+	case lt.Status.Phase == infrav1alpha1.LoadTestSucceeded || lt.Status.Phase == infrav1alpha1.LoadTestFailed:
+		log.Info("ignore environment error in terminal phase", "error", err.Error())
+	default:
+		log.Error(err, "resolve environment failed")
+		return ctrl.Result{}, err
+	}
+
+	previous := lt.Status.Phase
 	res, err := r.reconcileNormal(ctx, &lt)
 	if err != nil {
 		log.Error(err, "reconcile failed")
+		return res, err
 	}
-	return res, err
+	r.Exporter.ExportPhaseChange(ctx, &lt, "LoadTest", lt.Spec.Exporters, 0,
+		string(previous), string(lt.Status.Phase), lt.Status.Reason, lt.Status.Message)
+	return res, nil
 }
 
 func (r *LoadTestReconciler) reconcileNormal(ctx context.Context, lt *infrav1alpha1.LoadTest) (ctrl.Result, error) {
 	log := logf.FromContext(ctx)
 
+	// 重跑：停止负载、清空状态后从头执行
+	if token, ok := shared.RerunRequested(lt.GetAnnotations(), lt.Status.RerunToken); ok {
+		return r.rerunLoadTest(ctx, lt, token)
+	}
+
 	// 初始化状态
 	if lt.Status.Phase == "" {
 		return r.initializeLoadTest(ctx, lt)
@@ -99,6 +167,12 @@ func (r *LoadTestReconciler) reconcileNormal(ctx context.Context, lt *infrav1alp
 
 	logging.Reconciling(log, string(lt.Status.Phase))
 
+	// 手动中止 / 抢占：停止负载并进入 Failed
+	terminal := lt.Status.Phase == infrav1alpha1.LoadTestSucceeded || lt.Status.Phase == infrav1alpha1.LoadTestFailed
+	if reason, message, ok := shared.AbortRequested(lt.GetAnnotations()); ok && !terminal {
+		return r.abortLoadTest(ctx, lt, reason, message)
+	}
+
 	// 检测 spec 变更（Generation 变化）
 	if lt.Generation > lt.Status.ObservedGeneration {
 		return r.handleSpecChange(ctx, lt)
@@ -112,6 +186,8 @@ func (r *LoadTestReconciler) reconcileNormal(ctx context.Context, lt *infrav1alp
 		return r.reconcileInitializing(ctx, lt)
 	case infrav1alpha1.LoadTestRunning:
 		return r.reconcileRunning(ctx, lt)
+	case infrav1alpha1.LoadTestVerifying:
+		return r.reconcileVerifying(ctx, lt)
 	case infrav1alpha1.LoadTestSucceeded, infrav1alpha1.LoadTestFailed:
 		return r.reconcileTerminal(ctx, lt)
 	}
@@ -121,6 +197,7 @@ func (r *LoadTestReconciler) reconcileNormal(ctx context.Context, lt *infrav1alp
 
 // handleSpecChange 处理 LoadTest spec 变更。
 // - target 变更：使用 Server-Side Apply 更新
+// - monitoring 变更：重新生成 dashboard 与告警规则
 // - workload 变更：在 Running 阶段重新部署
 func (r *LoadTestReconciler) handleSpecChange(ctx context.Context, lt *infrav1alpha1.LoadTest) (ctrl.Result, error) {
 	log := logf.FromContext(ctx)
@@ -135,24 +212,35 @@ func (r *LoadTestReconciler) handleSpecChange(ctx context.Context, lt *infrav1al
 		}
 	}
 
-	// 2. 只在 Running 阶段处理 workload 变更
+	// 2. 监控资源随 spec 更新（SSA 幂等）
+	r.reconcileMonitoring(ctx, lt)
+
+	// 3. 只在 Running 阶段处理 workload 变更
 	if lt.Status.Phase == infrav1alpha1.LoadTestRunning {
 		// 重新解析 env injection
 		if err := r.resolveAndUpdateEnvInjection(ctx, lt, "re-resolved values"); err != nil {
 			return ctrl.Result{}, err
 		}
 
-		// 重新应用 workload
-		log.Info("reapplying workload due to spec change")
-		if err := r.applyWorkload(ctx, lt); err != nil {
-			log.Error(err, "failed to reapply workload")
-			return r.setFailed(ctx, lt, "WorkloadApplyFailed", err.Error())
+		// workload 规范化后未变化（如只调整了字段顺序或健康检查）时不重新部署
+		if hash := workloadSpecHash(lt); hash != "" && hash == lt.Status.WorkloadSpecHash {
+			log.V(logging.LevelVerbose).Info("workload unchanged, skipping reapply", "hash", hash)
+		} else {
+			log.Info("reapplying workload due to spec change")
+			if err := r.applyWorkload(ctx, lt); err != nil {
+				if errors.Is(err, resource.ErrWaitingBefore) {
+					logging.WaitingFor(log, "workload resources before waitBefore", "detail", err.Error())
+					return ctrl.Result{RequeueAfter: defaultRequeue}, nil
+				}
+				log.Error(err, "failed to reapply workload")
+				return r.setFailed(ctx, lt, "WorkloadApplyFailed", err.Error())
+			}
 		}
 	}
 
 	// 更新 ObservedGeneration
 	lt.Status.ObservedGeneration = lt.Generation
-	if err := shared.PatchStatusMerge(ctx, r.Client, lt); err != nil {
+	if err := shared.PatchLoadTestStatusFromObject(ctx, r.Client, lt); err != nil {
 		return ctrl.Result{}, err
 	}
 
@@ -162,10 +250,18 @@ func (r *LoadTestReconciler) handleSpecChange(ctx context.Context, lt *infrav1al
 // SetupWithManager wires the controller.
 func (r *LoadTestReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	if r.Recorder == nil {
-		r.Recorder = mgr.GetEventRecorderFor("loadtest")
+		r.Recorder = shared.NewManagerEventRecorder(mgr, "loadtest")
 	}
+	// 只有 spec、标签、用户注解变化与删除触发调和，status 与控制器注解写入后的推进由 Requeue 安排；
+	// 更新事件按 Debounce 合并
 	return ctrl.NewControllerManagedBy(mgr).
-		For(&infrav1alpha1.LoadTest{}).
 		Named("loadtest").
+		Watches(&infrav1alpha1.LoadTest{},
+			shared.DebounceUpdates(&handler.EnqueueRequestForObject{}, r.Debounce),
+			builder.WithPredicates(shared.IgnoreSelfUpdates(annotationTargetSpecHash, annotationSelectorResolved))).
+		// PostVerification 子测试状态变化时触发调和；创建子测试、添加 finalizer 等不改变状态的更新忽略
+		Watches(&infrav1alpha1.IntegrationTest{},
+			shared.DebounceUpdates(handler.EnqueueRequestForOwner(mgr.GetScheme(), mgr.GetRESTMapper(), &infrav1alpha1.LoadTest{}, handler.OnlyControllerOwner()), r.Debounce),
+			builder.WithPredicates(verificationStatusChanged())).
 		Complete(r)
 }
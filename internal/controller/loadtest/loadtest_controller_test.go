@@ -0,0 +1,864 @@
+package loadtest
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	infrav1alpha1 "github.com/lunz1207/testplane/api/v1alpha1"
+	"github.com/lunz1207/testplane/internal/controller/shared"
+	"github.com/lunz1207/testplane/internal/plugin"
+	"github.com/lunz1207/testplane/pkg/artifacts"
+)
+
+var _ = Describe("LoadTest Controller", func() {
+	// 缓存尚未同步上一次 status 写入时，决策点以 APIReader 读取的最新状态为准，不得重复发送事件。
+	Context("When the cache is stale after a status write", func() {
+		ctx := context.Background()
+
+		var (
+			scheme   *runtime.Scheme
+			recorder *record.FakeRecorder
+		)
+
+		newLoadTest := func(status infrav1alpha1.LoadTestStatus) *infrav1alpha1.LoadTest {
+			return &infrav1alpha1.LoadTest{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:       "stale-cache",
+					Namespace:  "default",
+					Finalizers: []string{loadTestFinalizer},
+				},
+				Status: status,
+			}
+		}
+
+		// newReconciler 构造缓存（Client）与 API Server（APIReader）状态不一致的 reconciler。
+		newReconciler := func(cached, latest *infrav1alpha1.LoadTest) *LoadTestReconciler {
+			return &LoadTestReconciler{
+				Client:         fake.NewClientBuilder().WithScheme(scheme).WithObjects(cached).Build(),
+				APIReader:      fake.NewClientBuilder().WithScheme(scheme).WithObjects(latest).Build(),
+				Scheme:         scheme,
+				PluginRegistry: plugin.NewRegistry(),
+				Recorder:       recorder,
+			}
+		}
+
+		BeforeEach(func() {
+			scheme = runtime.NewScheme()
+			Expect(infrav1alpha1.AddToScheme(scheme)).To(Succeed())
+			recorder = record.NewFakeRecorder(10)
+		})
+
+		It("should not emit LoadTestStarted twice", func() {
+			cached := newLoadTest(infrav1alpha1.LoadTestStatus{})
+			latest := newLoadTest(infrav1alpha1.LoadTestStatus{Phase: infrav1alpha1.LoadTestPending})
+			r := newReconciler(cached, latest)
+
+			result, err := r.initializeLoadTest(ctx, cached)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.Requeue).To(BeTrue())
+			Expect(recorder.Events).To(BeEmpty())
+		})
+
+		It("should not emit LoadTestSucceeded twice", func() {
+			now := metav1.Now()
+			cached := newLoadTest(infrav1alpha1.LoadTestStatus{Phase: infrav1alpha1.LoadTestSucceeded})
+			latest := newLoadTest(infrav1alpha1.LoadTestStatus{
+				Phase:          infrav1alpha1.LoadTestSucceeded,
+				CompletionTime: &now,
+			})
+			r := newReconciler(cached, latest)
+
+			_, err := r.reconcileTerminal(ctx, cached)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(recorder.Events).To(BeEmpty())
+		})
+
+		It("should summarize the run when the test completes", func() {
+			start := metav1.NewTime(time.Now().Add(-time.Hour))
+			status := infrav1alpha1.LoadTestStatus{
+				Phase:             infrav1alpha1.LoadTestSucceeded,
+				StartTime:         &start,
+				HealthCheckStatus: &infrav1alpha1.HealthCheckStatus{CheckCount: 124, PassCount: 118, FailCount: 2, ErrorCount: 1, WarmupCount: 3},
+			}
+			cached := newLoadTest(status)
+			r := newReconciler(cached, newLoadTest(status))
+			r.Client = fake.NewClientBuilder().WithScheme(scheme).WithInterceptorFuncs(interceptor.Funcs{
+				SubResourcePatch: func(context.Context, client.Client, string, client.Object, client.Patch, ...client.SubResourcePatchOption) error {
+					return nil
+				},
+			}).Build()
+
+			_, err := r.reconcileTerminal(ctx, cached)
+			Expect(err).NotTo(HaveOccurred())
+			cond := shared.GetCondition(cached.Status.Conditions, shared.ConditionTypeSummary)
+			Expect(cond).NotTo(BeNil())
+			Expect(cond.Reason).To(Equal("Succeeded"))
+			Expect(cond.Message).To(HavePrefix("checks=124 passed=118 failed=2 errored=1 warmup=3 passRate=98.3% duration=1h0m"))
+			Expect(<-recorder.Events).To(ContainSubstring(shared.EventReasonLoadTestSucceeded))
+			Expect(<-recorder.Events).To(And(HavePrefix("Normal "+shared.EventReasonTestSummary), ContainSubstring(cond.Message)))
+		})
+
+		It("should not emit LoadTestFailed after the phase already advanced", func() {
+			cached := newLoadTest(infrav1alpha1.LoadTestStatus{Phase: infrav1alpha1.LoadTestRunning})
+			latest := newLoadTest(infrav1alpha1.LoadTestStatus{Phase: infrav1alpha1.LoadTestFailed})
+			r := newReconciler(cached, latest)
+
+			_, err := r.setFailed(ctx, cached, "HealthCheckFailed", "consecutive failures reached threshold: 3")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cached.Status.Phase).To(Equal(infrav1alpha1.LoadTestRunning))
+			Expect(recorder.Events).To(BeEmpty())
+		})
+
+		It("should not record the same health check twice", func() {
+			cached := newLoadTest(infrav1alpha1.LoadTestStatus{
+				Phase:             infrav1alpha1.LoadTestRunning,
+				HealthCheckStatus: &infrav1alpha1.HealthCheckStatus{CheckCount: 2},
+			})
+			latest := newLoadTest(infrav1alpha1.LoadTestStatus{
+				Phase:             infrav1alpha1.LoadTestRunning,
+				HealthCheckStatus: &infrav1alpha1.HealthCheckStatus{CheckCount: 3},
+			})
+			r := newReconciler(cached, latest)
+
+			Expect(r.healthCheckAlreadyRecorded(ctx, cached, 3)).To(BeTrue())
+			Expect(r.healthCheckAlreadyRecorded(ctx, cached, 4)).To(BeFalse())
+		})
+	})
+
+	Context("When the workload uses the built-in HTTP load", func() {
+		newLoadTest := func(httpLoad *infrav1alpha1.HTTPLoadSpec) *infrav1alpha1.LoadTest {
+			return &infrav1alpha1.LoadTest{
+				ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "default"},
+				Spec: infrav1alpha1.LoadTestSpec{
+					Workload: infrav1alpha1.WorkloadSpec{HTTPLoad: httpLoad},
+				},
+				Status: infrav1alpha1.LoadTestStatus{
+					InjectedValues: map[string]string{"TARGET_HOST": "api.default.svc"},
+				},
+			}
+		}
+
+		It("should split RPS across replicas and expose injected values as env", func() {
+			lt := newLoadTest(&infrav1alpha1.HTTPLoadSpec{
+				URL:      "http://$(TARGET_HOST):8080/healthz",
+				RPS:      100,
+				Replicas: 4,
+			})
+			r := &LoadTestReconciler{LoadGenImage: "testplane:dev"}
+
+			manifest, err := r.buildHTTPLoadManifest(lt)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(manifest.Object.GetName()).To(Equal("api-httpload"))
+
+			containers, _, _ := unstructured.NestedSlice(manifest.Object.Object, "spec", "template", "spec", "containers")
+			Expect(containers).To(HaveLen(1))
+			container := containers[0].(map[string]interface{})
+			Expect(container["image"]).To(Equal("testplane:dev"))
+			Expect(container["args"]).To(ContainElements("loadgen", "--url=http://$(TARGET_HOST):8080/healthz", "--rps=25"))
+			Expect(container["env"]).To(ContainElement(map[string]interface{}{"name": "TARGET_HOST", "value": "api.default.svc"}))
+		})
+
+		It("should require an image", func() {
+			r := &LoadTestReconciler{}
+			_, err := r.buildHTTPLoadManifest(newLoadTest(&infrav1alpha1.HTTPLoadSpec{URL: "http://example", RPS: 1}))
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Context("When workload resources are listed in one manifest", func() {
+		It("should apply them by kind priority within waitBefore segments", func() {
+			lt := &infrav1alpha1.LoadTest{ObjectMeta: metav1.ObjectMeta{Name: "soak", Namespace: "default"}}
+			raw := `[
+				{"apiVersion": "admissionregistration.k8s.io/v1", "kind": "ValidatingWebhookConfiguration", "metadata": {"name": "guard"}},
+				{"apiVersion": "apps/v1", "kind": "Deployment", "metadata": {"name": "server"}},
+				{"apiVersion": "v1", "kind": "ConfigMap", "metadata": {"name": "config"}},
+				{"apiVersion": "v1", "kind": "Namespace", "metadata": {"name": "load"}},
+				{"apiVersion": "batch/v1", "kind": "Job", "metadata": {"name": "client"}, "waitBefore": true},
+				{"apiVersion": "v1", "kind": "Secret", "metadata": {"name": "token"}}
+			]`
+			r := &LoadTestReconciler{}
+			specs, err := r.expandResources(lt, []infrav1alpha1.ResourceRef{{Manifest: runtime.RawExtension{Raw: []byte(raw)}}})
+			Expect(err).NotTo(HaveOccurred())
+
+			var order []string
+			for _, spec := range specs {
+				order = append(order, spec.Object.GetKind())
+				Expect(spec.Object.Object).NotTo(HaveKey("waitBefore"))
+			}
+			// waitBefore 之后的 Secret 不会提前到 Job 等待之前；等待标记移到段内第一个资源
+			Expect(order).To(Equal([]string{"Namespace", "ConfigMap", "Deployment", "ValidatingWebhookConfiguration", "Secret", "Job"}))
+			Expect(specs[4].WaitBefore).To(BeTrue())
+			Expect(specs[5].WaitBefore).To(BeFalse())
+		})
+	})
+
+	Context("When monitoring resources are generated", func() {
+		It("should add a panel per promQuery trend and alert at the failure threshold", func() {
+			lt := &infrav1alpha1.LoadTest{
+				ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "perf"},
+				Spec: infrav1alpha1.LoadTestSpec{
+					HealthCheck: &infrav1alpha1.HealthCheck{
+						FailureThreshold: 5,
+						Trends: []infrav1alpha1.TrendCheck{{
+							Name:      "memory",
+							PromQuery: &infrav1alpha1.PromQuery{URL: "http://prometheus:9090", Query: "sum(container_memory_working_set_bytes)"},
+							Type:      infrav1alpha1.TrendGrowthRateBelow,
+						}},
+					},
+					Monitoring: &infrav1alpha1.MonitoringSpec{Dashboard: true, Alerts: true},
+				},
+			}
+
+			dashboard, err := buildDashboardJSON(lt)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(dashboard)).To(ContainSubstring("sum(container_memory_working_set_bytes)"))
+			Expect(string(dashboard)).To(ContainSubstring(`loadtest_namespace=\"perf\"`))
+
+			groups, _, _ := unstructured.NestedSlice(buildPrometheusRule(lt).Object, "spec", "groups")
+			rules := groups[0].(map[string]interface{})["rules"].([]interface{})
+			Expect(rules[1].(map[string]interface{})["expr"]).To(HaveSuffix(">= 5"))
+		})
+
+		It("should delete the generated resources once monitoring is turned off", func() {
+			ctx := context.Background()
+			scheme := runtime.NewScheme()
+			Expect(infrav1alpha1.AddToScheme(scheme)).To(Succeed())
+			Expect(corev1.AddToScheme(scheme)).To(Succeed())
+			lt := &infrav1alpha1.LoadTest{ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "perf", UID: "lt-uid"}}
+			owned := metav1.ObjectMeta{Name: "api-dashboard", Namespace: "perf",
+				OwnerReferences: []metav1.OwnerReference{{APIVersion: "v1alpha1", Kind: "LoadTest", Name: "api", UID: lt.UID}}}
+			rule := monitoringObject(lt, prometheusRuleAPIVersion, "PrometheusRule", alertsSuffix)
+			rule.SetOwnerReferences(owned.OwnerReferences)
+			c := fake.NewClientBuilder().WithScheme(scheme).
+				WithObjects(lt, &corev1.ConfigMap{ObjectMeta: owned}, rule).Build()
+			r := &LoadTestReconciler{Client: c, APIReader: c, Scheme: scheme}
+			r.ensureResourceManager()
+
+			Expect(r.applyMonitoring(ctx, lt)).To(Succeed())
+			Expect(c.Get(ctx, client.ObjectKey{Namespace: "perf", Name: "api-dashboard"}, &corev1.ConfigMap{})).NotTo(Succeed())
+			Expect(c.Get(ctx, client.ObjectKeyFromObject(rule), rule.DeepCopy())).NotTo(Succeed())
+
+			// 同名但不属于该 LoadTest 的资源保持不动
+			foreign := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "api-dashboard", Namespace: "perf"}}
+			Expect(c.Create(ctx, foreign)).To(Succeed())
+			Expect(r.applyMonitoring(ctx, lt)).To(Succeed())
+			Expect(c.Get(ctx, client.ObjectKeyFromObject(foreign), &corev1.ConfigMap{})).To(Succeed())
+		})
+	})
+
+	Context("When checking the target for drift", func() {
+		desired := map[string]interface{}{
+			"apiVersion": "apps/v1",
+			"kind":       "Deployment",
+			"metadata":   map[string]interface{}{"name": "web", "labels": map[string]interface{}{"app": "web"}},
+			"spec": map[string]interface{}{
+				"replicas": int64(3),
+				"template": map[string]interface{}{"spec": map[string]interface{}{
+					"containers": []interface{}{map[string]interface{}{"name": "web", "image": "nginx:1.27"}},
+				}},
+			},
+		}
+
+		It("should ignore server-side defaults and status", func() {
+			live := map[string]interface{}{
+				"metadata": map[string]interface{}{"name": "web", "uid": "1", "labels": map[string]interface{}{"app": "web", "extra": "x"}},
+				"spec": map[string]interface{}{
+					"replicas":             float64(3),
+					"revisionHistoryLimit": int64(10),
+					"template": map[string]interface{}{"spec": map[string]interface{}{
+						"containers": []interface{}{map[string]interface{}{"name": "web", "image": "nginx:1.27", "imagePullPolicy": "IfNotPresent"}},
+					}},
+				},
+				"status": map[string]interface{}{"replicas": int64(1)},
+			}
+			Expect(driftedFields(desired, live)).To(BeEmpty())
+		})
+
+		It("should compare resource quantities after API server normalization", func() {
+			resources := func(cpu, memory interface{}) map[string]interface{} {
+				return map[string]interface{}{"spec": map[string]interface{}{"resources": map[string]interface{}{
+					"limits": map[string]interface{}{"cpu": cpu, "memory": memory},
+				}}}
+			}
+			Expect(driftedFields(resources("1000m", "0.5Gi"), resources("1", "512Mi"))).To(BeEmpty())
+			Expect(driftedFields(resources(int64(2), "1Gi"), resources("2", "1024Mi"))).To(BeEmpty())
+			Expect(driftedFields(resources("500m", "0.5Gi"), resources("1", "256Mi"))).To(Equal([]string{
+				"spec.resources.limits.cpu",
+				"spec.resources.limits.memory",
+			}))
+			Expect(scalarEqual("nginx:1.27", "nginx:1.25")).To(BeFalse())
+		})
+
+		It("should report fields edited out from under the test", func() {
+			live := map[string]interface{}{
+				"metadata": map[string]interface{}{"name": "web"},
+				"spec": map[string]interface{}{
+					"replicas": int64(1),
+					"template": map[string]interface{}{"spec": map[string]interface{}{
+						"containers": []interface{}{map[string]interface{}{"name": "web", "image": "nginx:1.25"}},
+					}},
+				},
+			}
+			Expect(driftedFields(desired, live)).To(Equal([]string{
+				"metadata.labels",
+				"spec.replicas",
+				"spec.template.spec.containers[0].image",
+			}))
+		})
+	})
+
+	Context("When hashing templates for change detection", func() {
+		It("should ignore key order and empty values", func() {
+			hash := func(raw string) string {
+				return computeTemplateHash(&runtime.RawExtension{Raw: []byte(raw)})
+			}
+			base := hash(`{"kind":"Deployment","spec":{"replicas":2,"template":{"metadata":{"labels":{"app":"web"}}}}}`)
+			Expect(hash(`{"spec":{"template":{"metadata":{"labels":{"app":"web"},"annotations":{}}},"replicas":2.0},"kind":"Deployment","status":null}`)).To(Equal(base))
+			Expect(hash(`{"kind":"Deployment","spec":{"replicas":3,"template":{"metadata":{"labels":{"app":"web"}}}}}`)).NotTo(Equal(base))
+
+			lt := &infrav1alpha1.LoadTest{}
+			lt.Spec.Workload.Resources = []infrav1alpha1.ResourceRef{{Manifest: runtime.RawExtension{Raw: []byte(`{"kind":"Job","metadata":{"name":"k6"}}`)}}}
+			before := workloadSpecHash(lt)
+			lt.Spec.Workload.Resources[0].Manifest.Raw = []byte(`{"metadata":{"name":"k6","labels":{}},"kind":"Job"}`)
+			Expect(workloadSpecHash(lt)).To(Equal(before))
+			lt.Status.InjectedValues = map[string]string{"TARGET_URL": "http://10.0.0.1"}
+			Expect(workloadSpecHash(lt)).NotTo(Equal(before))
+		})
+	})
+
+	Context("When controlling load in a closed loop", func() {
+		spec := &infrav1alpha1.LoadControlSpec{Min: 1, Max: 20, MaxStep: 4, TolerancePercent: 5}
+
+		It("should step towards the setpoint within bounds", func() {
+			next, inBand := nextLoadValue(spec, 10, 100, 200)
+			Expect(inBand).To(BeFalse())
+			Expect(next).To(Equal(int32(14)))
+
+			next, _ = nextLoadValue(spec, 10, 220, 200)
+			Expect(next).To(Equal(int32(9)))
+
+			next, _ = nextLoadValue(spec, 19, 100, 200)
+			Expect(next).To(Equal(int32(20)))
+
+			next, inBand = nextLoadValue(spec, 10, 204, 200)
+			Expect(inBand).To(BeTrue())
+			Expect(next).To(Equal(int32(10)))
+		})
+
+		It("should read and write a numeric env in the pod template", func() {
+			obj := &unstructured.Unstructured{Object: map[string]interface{}{
+				"apiVersion": "apps/v1",
+				"kind":       "Deployment",
+				"metadata":   map[string]interface{}{"name": "k6"},
+				"spec": map[string]interface{}{"template": map[string]interface{}{"spec": map[string]interface{}{
+					"containers": []interface{}{map[string]interface{}{
+						"name": "k6",
+						"env":  []interface{}{map[string]interface{}{"name": "RPS", "value": "50"}},
+					}},
+				}}},
+			}}
+			param := infrav1alpha1.LoadParameter{Type: infrav1alpha1.LoadParameterEnv, EnvName: "RPS"}
+
+			value, err := readLoadParameter(obj, param)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(value).To(Equal(int32(50)))
+
+			Expect(writeLoadParameter(obj, param, 80)).To(Succeed())
+			value, err = readLoadParameter(obj, param)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(value).To(Equal(int32(80)))
+		})
+
+		It("should apply adjustments with the controller field owner and keep them on redeploy", func() {
+			ctx := context.Background()
+			metric := "100"
+			prometheus := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				_, _ = io.WriteString(w, `{"status":"success","data":{"resultType":"scalar","result":[0,"`+metric+`"]}}`)
+			}))
+			defer prometheus.Close()
+
+			scheme := runtime.NewScheme()
+			Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+			Expect(infrav1alpha1.AddToScheme(scheme)).To(Succeed())
+			live := &unstructured.Unstructured{Object: map[string]interface{}{
+				"apiVersion": "apps/v1",
+				"kind":       "Deployment",
+				"metadata":   map[string]interface{}{"name": "k6", "namespace": "default"},
+				"spec":       map[string]interface{}{"replicas": int64(2)},
+			}}
+			var applied []*unstructured.Unstructured
+			var owners []string
+			r := &LoadTestReconciler{
+				Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(live).WithInterceptorFuncs(interceptor.Funcs{
+					// SSA 不被 fake client 支持：记录 apply 的对象与字段管理者
+					Patch: func(_ context.Context, _ client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+						Expect(patch.Type()).To(Equal(client.Apply.Type()))
+						patchOpts := &client.PatchOptions{}
+						patchOpts.ApplyOptions(opts)
+						applied = append(applied, obj.(*unstructured.Unstructured).DeepCopy())
+						owners = append(owners, patchOpts.FieldManager)
+						return nil
+					},
+					SubResourcePatch: func(context.Context, client.Client, string, client.Object, client.Patch, ...client.SubResourcePatchOption) error {
+						return nil
+					},
+				}).Build(),
+				Scheme: scheme,
+			}
+			r.ensureResourceManager()
+			lt := &infrav1alpha1.LoadTest{
+				ObjectMeta: metav1.ObjectMeta{Name: "soak", Namespace: "default", UID: "lt-uid"},
+				Spec: infrav1alpha1.LoadTestSpec{
+					Workload: infrav1alpha1.WorkloadSpec{Resources: []infrav1alpha1.ResourceRef{{Manifest: runtime.RawExtension{
+						Raw: []byte(`{"apiVersion":"apps/v1","kind":"Deployment","metadata":{"name":"k6"},"spec":{"replicas":2}}`),
+					}}}},
+					LoadControl: &infrav1alpha1.LoadControlSpec{
+						Metric:    infrav1alpha1.PromQuery{URL: prometheus.URL, Query: "p99"},
+						Setpoint:  "200",
+						Parameter: infrav1alpha1.LoadParameter{Type: infrav1alpha1.LoadParameterReplicas, APIVersion: "apps/v1", Kind: "Deployment", Name: "k6"},
+						Min:       1,
+						Max:       4,
+						MaxStep:   2,
+					},
+				},
+			}
+
+			Expect(r.reconcileLoadControl(ctx, lt)).To(Succeed())
+			Expect(applied).To(HaveLen(1))
+			Expect(owners).To(Equal([]string{loadTestFieldOwner}))
+			replicas, _, _ := unstructured.NestedInt64(applied[0].Object, "spec", "replicas")
+			Expect(replicas).To(Equal(int64(4)))
+			Expect(lt.Status.LoadControl.CurrentValue).To(Equal(int32(4)))
+			Expect(lt.Status.LoadControl.Message).To(HavePrefix("adjusted 2 -> 4"))
+
+			// 重新部署 workload 时沿用调整后的值
+			specs, err := r.workloadManifests(lt)
+			Expect(err).NotTo(HaveOccurred())
+			replicas, _, _ = unstructured.NestedInt64(specs[0].Object.Object, "spec", "replicas")
+			Expect(replicas).To(Equal(int64(4)))
+
+			// 参数已在上限：不再调整，消息说明处于边界
+			Expect(unstructured.SetNestedField(live.Object, int64(4), "spec", "replicas")).To(Succeed())
+			Expect(r.Update(ctx, live)).To(Succeed())
+			lt.Status.LoadControl.LastSampleTime = nil
+			Expect(r.reconcileLoadControl(ctx, lt)).To(Succeed())
+			Expect(applied).To(HaveLen(1))
+			Expect(lt.Status.LoadControl.Message).To(ContainSubstring("parameter at bound 4"))
+
+			// 偏离目标但未到边界：消息不提边界
+			metric = "400"
+			lt.Status.LoadControl.LastSampleTime = nil
+			Expect(r.reconcileLoadControl(ctx, lt)).To(Succeed())
+			Expect(applied).To(HaveLen(2))
+			Expect(lt.Status.LoadControl.Message).To(HavePrefix("adjusted 4 -> 2"))
+		})
+	})
+
+	Context("When the run duration elapses", func() {
+		It("should requeue no later than the end of the run", func() {
+			start := metav1.NewTime(time.Now().Add(-50 * time.Second))
+			lt := &infrav1alpha1.LoadTest{
+				Spec:   infrav1alpha1.LoadTestSpec{DurationSeconds: 60},
+				Status: infrav1alpha1.LoadTestStatus{RunStartTime: &start},
+			}
+			remaining, limited := runDurationRemaining(lt)
+			Expect(limited).To(BeTrue())
+			Expect(remaining).To(BeNumerically("~", 10*time.Second, time.Second))
+			Expect(capRequeue(ctrl.Result{RequeueAfter: time.Minute}, remaining, limited).RequeueAfter).To(Equal(remaining))
+
+			lt.Spec.DurationSeconds = 0
+			_, limited = runDurationRemaining(lt)
+			Expect(limited).To(BeFalse())
+		})
+
+		It("should build the verification test from the inline steps", func() {
+			lt := &infrav1alpha1.LoadTest{
+				ObjectMeta: metav1.ObjectMeta{Name: "soak", Namespace: "default"},
+				Spec: infrav1alpha1.LoadTestSpec{
+					PostVerification: &infrav1alpha1.PostVerificationSpec{
+						Mode:  infrav1alpha1.IntegrationTestModeParallel,
+						Steps: []infrav1alpha1.TestStep{{Name: "check-data"}},
+					},
+				},
+			}
+			it := buildVerificationTest(lt)
+			Expect(it.Name).To(Equal("soak-verify"))
+			Expect(it.Namespace).To(Equal("default"))
+			Expect(it.Spec.Mode).To(Equal(infrav1alpha1.IntegrationTestModeParallel))
+			Expect(it.Spec.Steps).To(HaveLen(1))
+		})
+
+		It("should fail instead of adopting a verification test it does not control", func() {
+			ctx := context.Background()
+			scheme := runtime.NewScheme()
+			Expect(infrav1alpha1.AddToScheme(scheme)).To(Succeed())
+			lt := &infrav1alpha1.LoadTest{
+				ObjectMeta: metav1.ObjectMeta{Name: "soak", Namespace: "default", UID: "soak-uid"},
+				Spec: infrav1alpha1.LoadTestSpec{
+					DurationSeconds:  60,
+					PostVerification: &infrav1alpha1.PostVerificationSpec{Steps: []infrav1alpha1.TestStep{{Name: "check-data"}}},
+				},
+				Status: infrav1alpha1.LoadTestStatus{Phase: infrav1alpha1.LoadTestVerifying},
+			}
+			// 用户手动创建的同名测试，已成功结束
+			foreign := &infrav1alpha1.IntegrationTest{
+				ObjectMeta: metav1.ObjectMeta{Name: "soak-verify", Namespace: "default"},
+				Status:     infrav1alpha1.IntegrationTestStatus{Phase: infrav1alpha1.IntegrationTestPhaseSucceeded},
+			}
+			recorder := record.NewFakeRecorder(10)
+			r := &LoadTestReconciler{
+				Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(foreign).WithInterceptorFuncs(interceptor.Funcs{
+					SubResourcePatch: func(context.Context, client.Client, string, client.Object, client.Patch, ...client.SubResourcePatchOption) error {
+						return nil
+					},
+				}).Build(),
+				Scheme:   scheme,
+				Recorder: recorder,
+			}
+
+			_, err := r.reconcileVerifying(ctx, lt)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(lt.Status.Phase).To(Equal(infrav1alpha1.LoadTestFailed))
+			Expect(lt.Status.Reason).To(Equal(ReasonVerificationTestConflict))
+			Expect(lt.Status.PostVerification).To(BeNil())
+			Expect(<-recorder.Events).To(ContainSubstring("soak-verify"))
+		})
+	})
+
+	Context("When health checks are spread across the cluster", func() {
+		It("should offset checks by a stable jitter and align them to the minute", func() {
+			lt := &infrav1alpha1.LoadTest{
+				ObjectMeta: metav1.ObjectMeta{UID: "uid-1"},
+				Spec:       infrav1alpha1.LoadTestSpec{HealthCheck: &infrav1alpha1.HealthCheck{JitterSeconds: 5}},
+			}
+			offset := checkJitter(lt)
+			Expect(offset).To(BeNumerically(">=", 0))
+			Expect(offset).To(BeNumerically("<", 5*time.Second))
+			Expect(checkJitter(lt)).To(Equal(offset))
+
+			minute := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+			start := metav1.NewTime(minute.Add(7 * time.Second))
+			lt.Status.RunStartTime = &start
+			status := &infrav1alpha1.HealthCheckStatus{}
+			now := minute.Add(7 * time.Second)
+			Expect(nextCheckDelay(lt, status, 15*time.Second, now)).To(Equal(offset))
+
+			// 对齐：首次检查在下一个 15 秒对齐点之后偏移
+			lt.Spec.HealthCheck.AlignToMinute = true
+			Expect(nextCheckDelay(lt, status, 15*time.Second, now)).To(Equal(8*time.Second + offset))
+
+			// 调和延迟 2 秒后记录的检查不会跳过下一个对齐点
+			last := metav1.NewTime(minute.Add(15*time.Second + offset + 2*time.Second))
+			status.LastCheckTime = &last
+			Expect(nextCheckDelay(lt, status, 15*time.Second, last.Time)).To(Equal(13 * time.Second))
+			Expect(nextCheckDelay(lt, status, 5*time.Minute, last.Time)).To(Equal(5*time.Minute - 15*time.Second - 2*time.Second))
+		})
+	})
+
+	Context("When the test warms up", func() {
+		It("should run checks without counting them until the warm-up ends", func() {
+			ctx := context.Background()
+			scheme := runtime.NewScheme()
+			Expect(infrav1alpha1.AddToScheme(scheme)).To(Succeed())
+			start := metav1.NewTime(time.Now().Add(-30 * time.Second))
+			lt := &infrav1alpha1.LoadTest{
+				ObjectMeta: metav1.ObjectMeta{Name: "warmup", Namespace: "default"},
+				Spec: infrav1alpha1.LoadTestSpec{
+					WarmupSeconds: 60,
+					HealthCheck: &infrav1alpha1.HealthCheck{
+						FailureThreshold: 2,
+						AllOf:            []infrav1alpha1.Expectation{{Function: "AlwaysFail"}},
+					},
+				},
+				Status: infrav1alpha1.LoadTestStatus{Phase: infrav1alpha1.LoadTestRunning, RunStartTime: &start},
+			}
+			registry := plugin.NewRegistry()
+			registry.Register("AlwaysFail", func(_, _ map[string]interface{}) plugin.Result { return plugin.Fail("cold cache") })
+			recorder := record.NewFakeRecorder(10)
+			r := &LoadTestReconciler{
+				Client: fake.NewClientBuilder().WithScheme(scheme).WithInterceptorFuncs(interceptor.Funcs{
+					SubResourcePatch: func(context.Context, client.Client, string, client.Object, client.Patch, ...client.SubResourcePatchOption) error {
+						return nil
+					},
+				}).Build(),
+				Scheme:         scheme,
+				PluginRegistry: registry,
+				Recorder:       recorder,
+			}
+
+			_, status := r.getCheckIntervalAndStatus(lt)
+			_, err := r.executeAndRecordHealthCheck(ctx, lt, status, 10*time.Second)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(lt.Status.Phase).To(Equal(infrav1alpha1.LoadTestRunning))
+			Expect(status.CheckCount).To(Equal(int32(1)))
+			Expect(status.WarmupCount).To(Equal(int32(1)))
+			Expect(status.FailCount).To(BeZero())
+			Expect(status.ConsecutiveFailures).To(BeZero())
+			Expect(status.LastResults).To(HaveLen(1))
+			Expect(<-recorder.Events).To(And(ContainSubstring(shared.EventReasonHealthCheckWarmup), ContainSubstring("warm-up started")))
+
+			// 预热期内之后的检查不再发送事件
+			_, err = r.executeAndRecordHealthCheck(ctx, lt, status, 10*time.Second)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(status.WarmupCount).To(Equal(int32(2)))
+			Expect(recorder.Events).To(BeEmpty())
+
+			// 预热结束后发送一次结束事件，失败照常计入并触发阈值
+			earlier := metav1.NewTime(time.Now().Add(-2 * time.Minute))
+			lt.Status.RunStartTime = &earlier
+			lastWarmupCheck := metav1.NewTime(earlier.Add(30 * time.Second))
+			status.LastCheckTime = &lastWarmupCheck
+			_, err = r.executeAndRecordHealthCheck(ctx, lt, status, 10*time.Second)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(status.FailCount).To(Equal(int32(1)))
+			Expect(status.WarmupCount).To(Equal(int32(2)))
+			Expect(<-recorder.Events).To(And(ContainSubstring(shared.EventReasonHealthCheckWarmup), ContainSubstring("warm-up ended after 2 check(s)")))
+			Expect(<-recorder.Events).To(ContainSubstring(shared.EventReasonExpectationFailed))
+
+			_, err = r.executeAndRecordHealthCheck(ctx, lt, status, 10*time.Second)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(lt.Status.Phase).To(Equal(infrav1alpha1.LoadTestFailed))
+			for len(recorder.Events) > 0 {
+				Expect(<-recorder.Events).NotTo(ContainSubstring(shared.EventReasonHealthCheckWarmup))
+			}
+		})
+	})
+
+	Context("When health checks error", func() {
+		It("should count every errored check and emit an event only when errors start", func() {
+			ctx := context.Background()
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(http.StatusServiceUnavailable)
+			}))
+			defer server.Close()
+			scheme := runtime.NewScheme()
+			Expect(infrav1alpha1.AddToScheme(scheme)).To(Succeed())
+			lt := &infrav1alpha1.LoadTest{
+				ObjectMeta: metav1.ObjectMeta{Name: "flaky", Namespace: "default"},
+				Spec: infrav1alpha1.LoadTestSpec{
+					HealthCheck: &infrav1alpha1.HealthCheck{
+						FailureThreshold: 1,
+						AllOf:            []infrav1alpha1.Expectation{{Function: "remote", Webhook: server.URL}},
+					},
+				},
+				Status: infrav1alpha1.LoadTestStatus{Phase: infrav1alpha1.LoadTestRunning},
+			}
+			recorder := record.NewFakeRecorder(10)
+			r := &LoadTestReconciler{
+				Client: fake.NewClientBuilder().WithScheme(scheme).WithInterceptorFuncs(interceptor.Funcs{
+					SubResourcePatch: func(context.Context, client.Client, string, client.Object, client.Patch, ...client.SubResourcePatchOption) error {
+						return nil
+					},
+				}).Build(),
+				Scheme:         scheme,
+				PluginRegistry: plugin.NewRegistry(),
+				Recorder:       recorder,
+			}
+
+			_, status := r.getCheckIntervalAndStatus(lt)
+			for range 3 {
+				_, err := r.executeAndRecordHealthCheck(ctx, lt, status, 10*time.Second)
+				Expect(err).NotTo(HaveOccurred())
+			}
+			Expect(lt.Status.Phase).To(Equal(infrav1alpha1.LoadTestRunning))
+			Expect(status.ErrorCount).To(Equal(int32(3)))
+			Expect(status.FailCount).To(BeZero())
+			Expect(recorder.Events).To(HaveLen(1))
+			Expect(<-recorder.Events).To(ContainSubstring(shared.EventReasonExpectationError))
+		})
+
+		errored := infrav1alpha1.ExpectationResult{Error: true}
+		passed := infrav1alpha1.ExpectationResult{Passed: true}
+		failed := infrav1alpha1.ExpectationResult{}
+		DescribeTable("should tell retryable errors from assertion failures",
+			func(results shared.ExpectationResults, expected bool) {
+				Expect(onlyErrored(results)).To(Equal(expected))
+			},
+			Entry("no errors", shared.ExpectationResults{AllOf: []infrav1alpha1.ExpectationResult{failed}}, false),
+			Entry("allOf errored", shared.ExpectationResults{AllOf: []infrav1alpha1.ExpectationResult{passed, errored}}, true),
+			Entry("allOf errored and failed", shared.ExpectationResults{AllOf: []infrav1alpha1.ExpectationResult{errored, failed}}, false),
+			Entry("anyOf errored", shared.ExpectationResults{AnyOf: []infrav1alpha1.ExpectationResult{failed, errored}}, true),
+			Entry("anyOf failed with allOf errored", shared.ExpectationResults{
+				AllOf: []infrav1alpha1.ExpectationResult{errored},
+				AnyOf: []infrav1alpha1.ExpectationResult{failed},
+			}, false),
+		)
+	})
+
+	Context("When the test collects artifacts", func() {
+		It("should copy files from selected pods into the artifact store once", func() {
+			ctx := context.Background()
+			scheme := runtime.NewScheme()
+			Expect(corev1.AddToScheme(scheme)).To(Succeed())
+			running := func(name string) *corev1.Pod {
+				return &corev1.Pod{
+					ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Labels: map[string]string{"app": "k6"}},
+					Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "k6"}}},
+					Status:     corev1.PodStatus{Phase: corev1.PodRunning},
+				}
+			}
+			exec := &fakeExecutor{}
+			store := artifacts.NewFileStore(GinkgoT().TempDir())
+			r := &LoadTestReconciler{
+				Client:        fake.NewClientBuilder().WithScheme(scheme).WithObjects(running("k6-b"), running("k6-a")).Build(),
+				ArtifactStore: store,
+				PodExecutor:   exec,
+			}
+			lt := &infrav1alpha1.LoadTest{
+				ObjectMeta: metav1.ObjectMeta{Name: "soak", Namespace: "default"},
+				Spec: infrav1alpha1.LoadTestSpec{Artifacts: []infrav1alpha1.ArtifactSpec{
+					{Name: "results", PodSelector: map[string]string{"app": "k6"}, Paths: []string{"/results"}},
+					{Name: "missing", PodSelector: map[string]string{"app": "none"}, Paths: []string{"/x"}},
+				}},
+			}
+
+			r.collectArtifacts(ctx, lt)
+			Expect(lt.Status.Artifacts).To(HaveLen(3))
+			Expect(lt.Status.Artifacts[0].Pod).To(Equal("k6-a"))
+			Expect(lt.Status.Artifacts[0].SizeBytes).To(Equal(int64(len("tar:k6-a"))))
+			Expect(lt.Status.Artifacts[0].Location).To(Equal(store.Path(artifacts.ObjectRef{Kind: "LoadTest", Namespace: "default", Name: "soak"}, "results-k6-a.tar")))
+			Expect(lt.Status.Artifacts[2].Error).To(Equal("no running pods matched"))
+			Expect(exec.commands[0]).To(Equal([]string{"tar", "cf", "-", "/results"}))
+
+			// 已收集时不再重复执行
+			r.collectArtifacts(ctx, lt)
+			Expect(exec.commands).To(HaveLen(2))
+		})
+	})
+
+	Context("When the controller updates its own objects", func() {
+		It("should not re-enter the reconcile loop", func() {
+			lt := &infrav1alpha1.LoadTest{ObjectMeta: metav1.ObjectMeta{Name: "soak", Generation: 1}}
+			annotated := lt.DeepCopy()
+			annotated.Annotations = map[string]string{annotationTargetSpecHash: "abc"}
+			aborted := annotated.DeepCopy()
+			aborted.Annotations[infrav1alpha1.AnnotationAbort] = "Preempted"
+
+			pred := shared.IgnoreSelfUpdates(annotationTargetSpecHash, annotationSelectorResolved)
+			Expect(pred.Update(event.UpdateEvent{ObjectOld: lt, ObjectNew: annotated})).To(BeFalse())
+			Expect(pred.Update(event.UpdateEvent{ObjectOld: annotated, ObjectNew: aborted})).To(BeTrue())
+
+			child := &infrav1alpha1.IntegrationTest{ObjectMeta: metav1.ObjectMeta{Name: "soak-verify"}}
+			finalized := child.DeepCopy()
+			finalized.Finalizers = []string{"infra.testplane.io/integrationtest-finalizer"}
+			running := finalized.DeepCopy()
+			running.Status.Phase = infrav1alpha1.IntegrationTestPhaseRunning
+
+			childPred := verificationStatusChanged()
+			Expect(childPred.Update(event.UpdateEvent{ObjectOld: child, ObjectNew: finalized})).To(BeFalse())
+			Expect(childPred.Update(event.UpdateEvent{ObjectOld: finalized, ObjectNew: running})).To(BeTrue())
+		})
+	})
+
+	Context("When evaluating trends", func() {
+		start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+		// samples 每分钟一个样本
+		samples := func(values ...string) []infrav1alpha1.TrendSample {
+			out := make([]infrav1alpha1.TrendSample, len(values))
+			for i, v := range values {
+				out[i] = infrav1alpha1.TrendSample{Time: metav1.NewTime(start.Add(time.Duration(i) * time.Minute)), Value: v}
+			}
+			return out
+		}
+		sameTime := func(values ...string) []infrav1alpha1.TrendSample {
+			out := samples(values...)
+			for i := range out {
+				out[i].Time = metav1.NewTime(start)
+			}
+			return out
+		}
+
+		DescribeTable("should judge the sample window",
+			func(trend infrav1alpha1.TrendCheck, window []infrav1alpha1.TrendSample, passed bool, actual string) {
+				ok, got, msg := evaluateTrend(trend, window)
+				Expect(ok).To(Equal(passed), msg)
+				Expect(got).To(Equal(actual))
+			},
+			Entry("single sample waits for minSamples",
+				infrav1alpha1.TrendCheck{Type: infrav1alpha1.TrendNonDecreasing}, samples("5"), true, "samples=1/3, last=5"),
+			Entry("single sample with minSamples 1",
+				infrav1alpha1.TrendCheck{Type: infrav1alpha1.TrendGrowthRateBelow, MinSamples: 1, MaxGrowthPerMinute: "0"}, samples("5"), true, "last=5"),
+			Entry("non-decreasing with a plateau",
+				infrav1alpha1.TrendCheck{Type: infrav1alpha1.TrendNonDecreasing}, samples("1", "2", "2", "3"), true, "last=3"),
+			Entry("non-decreasing with a drop",
+				infrav1alpha1.TrendCheck{Type: infrav1alpha1.TrendNonDecreasing}, samples("1", "3", "2"), false, "3 -> 2"),
+			Entry("stable within the default tolerance",
+				infrav1alpha1.TrendCheck{Type: infrav1alpha1.TrendStableWithin}, samples("100", "105", "95"), true, "mean=100, maxDeviation=5.00%"),
+			Entry("stable beyond the tolerance",
+				infrav1alpha1.TrendCheck{Type: infrav1alpha1.TrendStableWithin, TolerancePercent: 5}, samples("100", "110", "90"), false, "mean=100, maxDeviation=10.00%"),
+			Entry("stable around zero",
+				infrav1alpha1.TrendCheck{Type: infrav1alpha1.TrendStableWithin}, samples("0", "0", "0"), true, "mean=0, maxDeviation=0.00%"),
+			Entry("unstable around zero",
+				infrav1alpha1.TrendCheck{Type: infrav1alpha1.TrendStableWithin}, samples("-1", "0", "1"), false, "mean=0, maxDeviation=+Inf%"),
+			Entry("flat series with a zero growth limit",
+				infrav1alpha1.TrendCheck{Type: infrav1alpha1.TrendGrowthRateBelow, MaxGrowthPerMinute: "0"}, samples("64", "64", "64"), true, "growth=0/min"),
+			Entry("growth at the limit",
+				infrav1alpha1.TrendCheck{Type: infrav1alpha1.TrendGrowthRateBelow, MaxGrowthPerMinute: "1.5"}, samples("0", "1.5", "3"), true, "growth=1.5/min"),
+			Entry("growth above the limit",
+				infrav1alpha1.TrendCheck{Type: infrav1alpha1.TrendGrowthRateBelow, MaxGrowthPerMinute: "1"}, samples("0", "2", "4"), false, "growth=2/min"),
+			Entry("zero elapsed time",
+				infrav1alpha1.TrendCheck{Type: infrav1alpha1.TrendGrowthRateBelow, MaxGrowthPerMinute: "0"}, sameTime("1", "2", "3"), true, "last=3"),
+			Entry("invalid growth limit",
+				infrav1alpha1.TrendCheck{Type: infrav1alpha1.TrendGrowthRateBelow, MaxGrowthPerMinute: "fast"}, samples("1", "2", "3"), false, ""),
+			Entry("invalid sample",
+				infrav1alpha1.TrendCheck{Type: infrav1alpha1.TrendNonDecreasing}, samples("1", "x"), false, "x"),
+		)
+
+		It("should bound Prometheus queries with a timeout", func() {
+			Expect(promClient.Timeout).To(Equal(promQueryTimeout))
+
+			responses := map[string]string{
+				"vector": `{"status":"success","data":{"resultType":"vector","result":[{"value":[1700000000,"42"]}]}}`,
+				"scalar": `{"status":"success","data":{"resultType":"scalar","result":[1700000000,"7.5"]}}`,
+				"empty":  `{"status":"success","data":{"resultType":"vector","result":[]}}`,
+				"bad":    `{"status":"error","error":"parse error"}`,
+			}
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_, _ = io.WriteString(w, responses[r.URL.Query().Get("query")])
+			}))
+			defer server.Close()
+			query := func(q string) (float64, error) {
+				return queryPrometheus(context.Background(), infrav1alpha1.PromQuery{URL: server.URL + "/", Query: q})
+			}
+
+			Expect(query("vector")).To(Equal(42.0))
+			Expect(query("scalar")).To(Equal(7.5))
+			_, err := query("empty")
+			Expect(err).To(MatchError(ContainSubstring("no data")))
+			_, err = query("bad")
+			Expect(err).To(MatchError(ContainSubstring("parse error")))
+		})
+	})
+})
+
+// fakeExecutor 记录执行的命令，输出 "tar:<pod>"。
+type fakeExecutor struct {
+	mu       sync.Mutex
+	commands [][]string
+}
+
+func (e *fakeExecutor) Exec(_ context.Context, _, pod, _ string, command []string, stdout io.Writer) error {
+	e.mu.Lock()
+	e.commands = append(e.commands, command)
+	e.mu.Unlock()
+	_, err := io.WriteString(stdout, "tar:"+pod)
+	return err
+}
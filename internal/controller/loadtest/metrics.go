@@ -0,0 +1,47 @@
+package loadtest
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	infrav1alpha1 "github.com/lunz1207/testplane/api/v1alpha1"
+)
+
+// 健康检查指标，由 manager 的 /metrics 暴露，供生成的 dashboard 与告警规则查询。
+// 指标值镜像 status.healthCheckStatus，LoadTest 删除时清理。
+// 命名空间标签使用 loadtest_namespace，避免与 Prometheus 抓取时附加的 namespace 标签冲突。
+var (
+	healthCheckCountGauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "testplane_loadtest_health_checks",
+		Help: "Number of LoadTest health checks by result (total, pass, fail, error, warmup).",
+	}, []string{"loadtest_namespace", "loadtest", "result"})
+
+	consecutiveFailuresGauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "testplane_loadtest_consecutive_failures",
+		Help: "Consecutive failed LoadTest health checks.",
+	}, []string{"loadtest_namespace", "loadtest"})
+)
+
+func init() {
+	ctrlmetrics.Registry.MustRegister(healthCheckCountGauge, consecutiveFailuresGauge)
+}
+
+// recordHealthCheckMetrics 将健康检查状态同步到指标。
+func recordHealthCheckMetrics(lt *infrav1alpha1.LoadTest, status *infrav1alpha1.HealthCheckStatus) {
+	if status == nil {
+		return
+	}
+	healthCheckCountGauge.WithLabelValues(lt.Namespace, lt.Name, "total").Set(float64(status.CheckCount))
+	healthCheckCountGauge.WithLabelValues(lt.Namespace, lt.Name, "pass").Set(float64(status.PassCount))
+	healthCheckCountGauge.WithLabelValues(lt.Namespace, lt.Name, "fail").Set(float64(status.FailCount))
+	healthCheckCountGauge.WithLabelValues(lt.Namespace, lt.Name, "error").Set(float64(status.ErrorCount))
+	healthCheckCountGauge.WithLabelValues(lt.Namespace, lt.Name, "warmup").Set(float64(status.WarmupCount))
+	consecutiveFailuresGauge.WithLabelValues(lt.Namespace, lt.Name).Set(float64(status.ConsecutiveFailures))
+}
+
+// deleteHealthCheckMetrics 删除 LoadTest 的指标序列。
+func deleteHealthCheckMetrics(lt *infrav1alpha1.LoadTest) {
+	labels := prometheus.Labels{"loadtest_namespace": lt.Namespace, "loadtest": lt.Name}
+	healthCheckCountGauge.DeletePartialMatch(labels)
+	consecutiveFailuresGauge.DeletePartialMatch(labels)
+}
@@ -0,0 +1,212 @@
+package loadtest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	infrav1alpha1 "github.com/lunz1207/testplane/api/v1alpha1"
+)
+
+// defaultDashboardLabels Grafana sidecar 默认识别的 dashboard 标签。
+var defaultDashboardLabels = map[string]string{"grafana_dashboard": "1"}
+
+// applyMonitoring 生成并应用 LoadTest 的监控资源（dashboard ConfigMap、PrometheusRule）。
+// 资源通过 ownerRef 关联到 LoadTest，Server-Side Apply 保证重复调用幂等；
+// 关闭的监控资源（或告警缺少 healthCheck）删除此前生成的对象，避免关闭后残留到 LoadTest 删除。
+func (r *LoadTestReconciler) applyMonitoring(ctx context.Context, lt *infrav1alpha1.LoadTest) error {
+	mon := lt.Spec.Monitoring
+
+	if mon != nil && mon.Dashboard {
+		obj, err := buildDashboardConfigMap(lt)
+		if err != nil {
+			return err
+		}
+		if err := r.ResourceManager.ApplyObject(ctx, lt, obj); err != nil {
+			return fmt.Errorf("apply dashboard: %w", err)
+		}
+	} else if err := r.ResourceManager.DeleteOwnedObject(ctx, lt, monitoringObject(lt, "v1", "ConfigMap", dashboardSuffix)); err != nil {
+		return fmt.Errorf("delete dashboard: %w", err)
+	}
+
+	// 告警基于健康检查指标，未配置健康检查时无可告警的内容
+	if mon != nil && mon.Alerts && lt.Spec.HealthCheck != nil {
+		if err := r.ResourceManager.ApplyObject(ctx, lt, buildPrometheusRule(lt)); err != nil {
+			return fmt.Errorf("apply alert rules: %w", err)
+		}
+	} else if err := r.ResourceManager.DeleteOwnedObject(ctx, lt, monitoringObject(lt, prometheusRuleAPIVersion, "PrometheusRule", alertsSuffix)); err != nil {
+		return fmt.Errorf("delete alert rules: %w", err)
+	}
+	return nil
+}
+
+// 生成的监控资源名称后缀：<name>-dashboard、<name>-alerts。
+const (
+	dashboardSuffix = "-dashboard"
+	alertsSuffix    = "-alerts"
+)
+
+// prometheusRuleAPIVersion Prometheus Operator 的 PrometheusRule API 版本。
+const prometheusRuleAPIVersion = "monitoring.coreos.com/v1"
+
+// monitoringObject 返回只含类型与名称的监控资源引用，用于删除。
+func monitoringObject(lt *infrav1alpha1.LoadTest, apiVersion, kind, suffix string) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion(apiVersion)
+	obj.SetKind(kind)
+	obj.SetNamespace(lt.Namespace)
+	obj.SetName(lt.Name + suffix)
+	return obj
+}
+
+// metricSelector 当前 LoadTest 的指标标签选择器。
+func metricSelector(lt *infrav1alpha1.LoadTest) string {
+	return fmt.Sprintf(`loadtest_namespace=%q,loadtest=%q`, lt.Namespace, lt.Name)
+}
+
+// failureThreshold 健康检查连续失败阈值。
+func failureThreshold(lt *infrav1alpha1.LoadTest) int32 {
+	if lt.Spec.HealthCheck == nil {
+		return 3
+	}
+	return getOrDefaultInt32(lt.Spec.HealthCheck.FailureThreshold, 3)
+}
+
+// dashboardPanel 生成单个时序面板。
+func dashboardPanel(id int, title, expr, legend string) map[string]interface{} {
+	return map[string]interface{}{
+		"id":    id,
+		"type":  "timeseries",
+		"title": title,
+		"gridPos": map[string]interface{}{
+			"h": 8, "w": 12, "x": ((id - 1) % 2) * 12, "y": ((id - 1) / 2) * 8,
+		},
+		"targets": []interface{}{
+			map[string]interface{}{"refId": "A", "expr": expr, "legendFormat": legend},
+		},
+	}
+}
+
+// buildDashboardJSON 生成 Grafana dashboard JSON：
+// 健康检查计数、连续失败次数（阈值线）以及 trends 中声明的 promQuery。
+func buildDashboardJSON(lt *infrav1alpha1.LoadTest) ([]byte, error) {
+	sel := metricSelector(lt)
+	panels := []interface{}{
+		dashboardPanel(1, "Health checks", fmt.Sprintf("testplane_loadtest_health_checks{%s}", sel), "{{result}}"),
+	}
+	failures := dashboardPanel(2, "Consecutive failures", fmt.Sprintf("testplane_loadtest_consecutive_failures{%s}", sel), "consecutive failures")
+	failures["fieldConfig"] = map[string]interface{}{
+		"defaults": map[string]interface{}{
+			"thresholds": map[string]interface{}{
+				"mode": "absolute",
+				"steps": []interface{}{
+					map[string]interface{}{"color": "green", "value": nil},
+					map[string]interface{}{"color": "red", "value": failureThreshold(lt)},
+				},
+			},
+			"custom": map[string]interface{}{"thresholdsStyle": map[string]interface{}{"mode": "line"}},
+		},
+	}
+	panels = append(panels, failures)
+
+	if lt.Spec.HealthCheck != nil {
+		for _, trend := range lt.Spec.HealthCheck.Trends {
+			if trend.PromQuery == nil {
+				continue
+			}
+			panels = append(panels, dashboardPanel(len(panels)+1, trend.Name, trend.PromQuery.Query, trend.Name))
+		}
+	}
+
+	return json.MarshalIndent(map[string]interface{}{
+		"uid":           fmt.Sprintf("testplane-%s", lt.UID),
+		"title":         fmt.Sprintf("LoadTest %s/%s", lt.Namespace, lt.Name),
+		"tags":          []string{"testplane", "loadtest"},
+		"schemaVersion": 39,
+		"refresh":       "30s",
+		"time":          map[string]interface{}{"from": "now-1h", "to": "now"},
+		"panels":        panels,
+	}, "", "  ")
+}
+
+// buildDashboardConfigMap 生成携带 dashboard JSON 的 ConfigMap。
+func buildDashboardConfigMap(lt *infrav1alpha1.LoadTest) (*unstructured.Unstructured, error) {
+	dashboard, err := buildDashboardJSON(lt)
+	if err != nil {
+		return nil, fmt.Errorf("build dashboard: %w", err)
+	}
+	labels := lt.Spec.Monitoring.DashboardLabels
+	if len(labels) == 0 {
+		labels = defaultDashboardLabels
+	}
+	cm := &corev1.ConfigMap{
+		TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "ConfigMap"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      lt.Name + dashboardSuffix,
+			Namespace: lt.Namespace,
+			Labels:    labels,
+		},
+		Data: map[string]string{
+			fmt.Sprintf("loadtest-%s-%s.json", lt.Namespace, lt.Name): string(dashboard),
+		},
+	}
+	obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(cm)
+	if err != nil {
+		return nil, fmt.Errorf("convert dashboard configmap: %w", err)
+	}
+	return &unstructured.Unstructured{Object: obj}, nil
+}
+
+// buildPrometheusRule 生成健康检查告警规则：
+// 出现连续失败时 warning，连续失败达到 failureThreshold（测试即将失败）时 critical。
+func buildPrometheusRule(lt *infrav1alpha1.LoadTest) *unstructured.Unstructured {
+	sel := metricSelector(lt)
+	threshold := failureThreshold(lt)
+	annotations := func(summary string) map[string]interface{} {
+		return map[string]interface{}{
+			"summary":     summary,
+			"description": fmt.Sprintf("LoadTest %s/%s: {{ $value }} consecutive failed health checks (threshold %d).", lt.Namespace, lt.Name, threshold),
+		}
+	}
+
+	labels := make(map[string]interface{}, len(lt.Spec.Monitoring.RuleLabels))
+	for k, v := range lt.Spec.Monitoring.RuleLabels {
+		labels[k] = v
+	}
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": prometheusRuleAPIVersion,
+		"kind":       "PrometheusRule",
+		"metadata": map[string]interface{}{
+			"name":      lt.Name + alertsSuffix,
+			"namespace": lt.Namespace,
+			"labels":    labels,
+		},
+		"spec": map[string]interface{}{
+			"groups": []interface{}{
+				map[string]interface{}{
+					"name": fmt.Sprintf("testplane-loadtest-%s-%s", lt.Namespace, lt.Name),
+					"rules": []interface{}{
+						map[string]interface{}{
+							"alert":       "LoadTestHealthCheckFailing",
+							"expr":        fmt.Sprintf("testplane_loadtest_consecutive_failures{%s} > 0", sel),
+							"for":         "1m",
+							"labels":      map[string]interface{}{"severity": "warning"},
+							"annotations": annotations("LoadTest health check is failing"),
+						},
+						map[string]interface{}{
+							"alert":       "LoadTestFailureThresholdReached",
+							"expr":        fmt.Sprintf("testplane_loadtest_consecutive_failures{%s} >= %d", sel, threshold),
+							"labels":      map[string]interface{}{"severity": "critical"},
+							"annotations": annotations("LoadTest health check failure threshold reached"),
+						},
+					},
+				},
+			},
+		},
+	}}
+}
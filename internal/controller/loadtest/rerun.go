@@ -0,0 +1,69 @@
+package loadtest
+
+import (
+	"context"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	infrav1alpha1 "github.com/lunz1207/testplane/api/v1alpha1"
+	"github.com/lunz1207/testplane/internal/controller/shared"
+)
+
+// rerun.go 包含 LoadTest 的中止与重跑逻辑（infra.testplane.io/abort、infra.testplane.io/rerun 注解）。
+// LoadTest 没有 Aborted 阶段：中止停止负载后以中止原因进入 Failed。
+
+// abortLoadTest 停止负载并将 LoadTest 标记为失败，原因为 ManualAbort 或 Preempted。
+func (r *LoadTestReconciler) abortLoadTest(ctx context.Context, lt *infrav1alpha1.LoadTest, reason, message string) (ctrl.Result, error) {
+	if err := r.stopWorkload(ctx, lt); err != nil {
+		return ctrl.Result{}, err
+	}
+	return r.setFailed(ctx, lt, reason, message)
+}
+
+// rerunLoadTest 处理重跑注解：停止负载、删除上一次的验证测试，然后清空状态（只保留重跑令牌），
+// 下一次调和按新测试重新初始化。先 patch，成功后再发 Event。
+func (r *LoadTestReconciler) rerunLoadTest(ctx context.Context, lt *infrav1alpha1.LoadTest, token string) (ctrl.Result, error) {
+	// 缓存尚未同步上一次重跑：避免重复清空已开始的新一次运行
+	if latest := r.latestStatus(ctx, lt); latest != nil && latest.RerunToken == token {
+		return ctrl.Result{Requeue: true}, nil
+	}
+	if err := r.stopWorkload(ctx, lt); err != nil {
+		return ctrl.Result{}, err
+	}
+	// 验证测试删除完成前不重置，避免新一次运行读到上一次的验证结果
+	if gone, err := r.deleteVerificationTest(ctx, lt); err != nil || !gone {
+		return ctrl.Result{RequeueAfter: defaultRequeue}, err
+	}
+	logf.FromContext(ctx).Info("rerun requested", "token", token, "previousPhase", lt.Status.Phase)
+
+	previous := lt.Status.Phase
+	lt.Status = infrav1alpha1.LoadTestStatus{RerunToken: token}
+	if err := shared.PatchLoadTestStatusFromObject(ctx, r.Client, lt); err != nil {
+		return ctrl.Result{}, err
+	}
+	deleteHealthCheckMetrics(lt)
+	r.emitNormalEvent(lt, "", shared.EventReasonTestRerun, shared.RerunEventMessage(string(previous)))
+	return ctrl.Result{Requeue: true}, nil
+}
+
+// deleteVerificationTest 删除验证用 IntegrationTest，返回是否已不存在。
+func (r *LoadTestReconciler) deleteVerificationTest(ctx context.Context, lt *infrav1alpha1.LoadTest) (bool, error) {
+	var it infrav1alpha1.IntegrationTest
+	err := r.Get(ctx, client.ObjectKey{Namespace: lt.Namespace, Name: verificationTestName(lt)}, &it)
+	if apierrors.IsNotFound(err) {
+		return true, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("get verification test: %w", err)
+	}
+	if it.DeletionTimestamp.IsZero() {
+		if err := r.Delete(ctx, &it); client.IgnoreNotFound(err) != nil {
+			return false, fmt.Errorf("delete verification test: %w", err)
+		}
+	}
+	return false, nil
+}
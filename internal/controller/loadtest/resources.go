@@ -2,18 +2,33 @@ package loadtest
 
 import (
 	"context"
+	"fmt"
 
 	infrav1alpha1 "github.com/lunz1207/testplane/api/v1alpha1"
 	"github.com/lunz1207/testplane/internal/controller/shared/resource"
 )
 
+// resolveManifests 把 target 与 workload 资源的 base 与 patches 合成为 Manifest（只修改内存中的对象）。
+func (r *LoadTestReconciler) resolveManifests(ctx context.Context, lt *infrav1alpha1.LoadTest) error {
+	load := resource.ConfigMapBaseLoader(ctx, r.Client, lt.Namespace)
+	if err := resource.ResolveManifest(&lt.Spec.Target.Resource, load); err != nil {
+		return fmt.Errorf("target: %w", err)
+	}
+	for i := range lt.Spec.Workload.Resources {
+		if err := resource.ResolveManifest(&lt.Spec.Workload.Resources[i], load); err != nil {
+			return fmt.Errorf("workload resource %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
 // expandResources 将 []ResourceRef 的模板展开为 ExpandedManifest 列表（支持 List/数组）。
 func (r *LoadTestReconciler) expandResources(lt *infrav1alpha1.LoadTest, resources []infrav1alpha1.ResourceRef) ([]resource.ExpandedManifest, error) {
 	return resource.ExpandResourceRefs(resources, lt.Namespace)
 }
 
 // applyResources 批量应用资源。
-// 资源通过 ownerRef 关联到 LoadTest，删除时 GC 自动清理。
+// 资源通过 ownerRef 关联到 LoadTest，测试删除时按依赖顺序清理（见 handleDeletion），超时未删除的由 GC 兜底。
 func (r *LoadTestReconciler) applyResources(ctx context.Context, lt *infrav1alpha1.LoadTest, manifests []resource.ExpandedManifest) error {
 	return r.ResourceManager.ExecuteManifests(ctx, lt, manifests)
 }
@@ -19,9 +19,12 @@ package loadtest
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"hash/fnv"
 	"time"
 
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -30,6 +33,8 @@ import (
 	infrav1alpha1 "github.com/lunz1207/testplane/api/v1alpha1"
 	"github.com/lunz1207/testplane/internal/controller/shared"
 	"github.com/lunz1207/testplane/internal/controller/shared/logging"
+	"github.com/lunz1207/testplane/internal/controller/shared/resource"
+	"github.com/lunz1207/testplane/pkg/hooks"
 )
 
 // transitionToRunning 进入 Running 阶段并应用 workload。
@@ -37,6 +42,11 @@ import (
 func (r *LoadTestReconciler) transitionToRunning(ctx context.Context, lt *infrav1alpha1.LoadTest, emitTargetReadyEvent ...bool) (ctrl.Result, error) {
 	log := logf.FromContext(ctx)
 
+	// 缓存尚未同步上一次 status 写入：已进入 Running，避免重复 apply workload 和重复事件
+	if r.phaseAlreadyAdvanced(ctx, lt) {
+		return ctrl.Result{Requeue: true}, nil
+	}
+
 	// 解析环境变量注入
 	if err := r.resolveAndUpdateEnvInjection(ctx, lt, "resolved values"); err != nil {
 		return ctrl.Result{}, err
@@ -44,6 +54,11 @@ func (r *LoadTestReconciler) transitionToRunning(ctx context.Context, lt *infrav
 
 	// 应用 workload
 	if err := r.applyWorkload(ctx, lt); err != nil {
+		// waitBefore 之前的资源尚未收敛：稍后重新 apply
+		if errors.Is(err, resource.ErrWaitingBefore) {
+			logging.WaitingFor(log, "workload resources before waitBefore", "detail", err.Error())
+			return ctrl.Result{RequeueAfter: defaultRequeue}, nil
+		}
 		log.Error(err, "failed to apply workload")
 		return r.setFailed(ctx, lt, "WorkloadApplyFailed", err.Error())
 	}
@@ -54,20 +69,22 @@ func (r *LoadTestReconciler) transitionToRunning(ctx context.Context, lt *infrav
 	}
 
 	lt.Status.Phase = infrav1alpha1.LoadTestRunning
+	now := metav1.Now()
+	lt.Status.RunStartTime = &now
 
 	// 设置 Conditions
 	shared.SetCondition(&lt.Status.Conditions, ConditionTypeTargetReady, metav1.ConditionTrue, "TargetReady", "Target is ready", lt.Generation)
 	shared.SetCondition(&lt.Status.Conditions, ConditionTypeReady, metav1.ConditionTrue, "Running", "LoadTest is running", lt.Generation)
 
-	if err := shared.PatchStatusMerge(ctx, r.Client, lt); err != nil {
+	if err := shared.PatchLoadTestStatusFromObject(ctx, r.Client, lt); err != nil {
 		return ctrl.Result{}, err
 	}
 
 	// patch 成功后发送 Event
 	if len(emitTargetReadyEvent) > 0 && emitTargetReadyEvent[0] {
-		shared.EmitNormalEvent(r.Recorder, lt, shared.EventReasonTargetReady, "Target is ready")
+		r.emitNormalEvent(lt, "", shared.EventReasonTargetReady, "Target is ready")
 	}
-	shared.EmitNormalEvent(r.Recorder, lt, shared.EventReasonLoadTestRunning, "LoadTest is now running")
+	r.emitNormalEvent(lt, "", shared.EventReasonLoadTestRunning, "LoadTest is now running")
 
 	return ctrl.Result{RequeueAfter: defaultRequeue}, nil
 }
@@ -103,13 +120,35 @@ func (r *LoadTestReconciler) resolveAndUpdateEnvInjection(ctx context.Context, l
 // reconcileRunning 处理 Running 阶段。
 // 根据 healthCheck 的 failureThreshold 判断是否失败。
 func (r *LoadTestReconciler) reconcileRunning(ctx context.Context, lt *infrav1alpha1.LoadTest) (ctrl.Result, error) {
+	// 运行时长到期：停止负载，进入验证或成功
+	remaining, limited := runDurationRemaining(lt)
+	if limited && remaining <= 0 {
+		return r.finishRun(ctx, lt)
+	}
+
+	// 汇总内置 HTTP 负载统计（节流），失败不影响健康检查
+	if err := r.refreshHTTPLoadStatus(ctx, lt); err != nil {
+		logf.FromContext(ctx).Info("refresh httpLoad status failed", "error", err.Error())
+	}
+
+	// Target 漂移检测（按间隔），Fail 策略下测试直接失败
+	if handled, result, err := r.checkTargetDrift(ctx, lt); handled || err != nil {
+		return result, err
+	}
+
+	// 闭环负载控制（按间隔），失败不影响健康检查
+	if err := r.reconcileLoadControl(ctx, lt); err != nil {
+		logf.FromContext(ctx).Info("load control failed", "error", err.Error())
+	}
+
 	// 执行健康检查
 	if lt.Spec.HealthCheck != nil {
-		return r.runHealthChecks(ctx, lt)
+		result, err := r.runHealthChecks(ctx, lt)
+		return capRequeue(result, remaining, limited), err
 	}
 
 	// 无健康检查，继续等待
-	return ctrl.Result{RequeueAfter: defaultRequeue}, nil
+	return capRequeue(ctrl.Result{RequeueAfter: defaultRequeue}, remaining, limited), nil
 }
 
 // runHealthChecks 执行健康检查。
@@ -117,7 +156,7 @@ func (r *LoadTestReconciler) runHealthChecks(ctx context.Context, lt *infrav1alp
 	interval, status := r.getCheckIntervalAndStatus(lt)
 
 	// 检查是否需要等待
-	if remaining := r.shouldWaitForNextCheck(status, interval); remaining > 0 {
+	if remaining := nextCheckDelay(lt, status, interval, time.Now()); remaining > 0 {
 		return ctrl.Result{RequeueAfter: remaining}, nil
 	}
 
@@ -135,14 +174,73 @@ func (r *LoadTestReconciler) getCheckIntervalAndStatus(lt *infrav1alpha1.LoadTes
 	return interval, lt.Status.HealthCheckStatus
 }
 
-// shouldWaitForNextCheck 检查是否需要等待下一次检查。
-func (r *LoadTestReconciler) shouldWaitForNextCheck(status *infrav1alpha1.HealthCheckStatus, interval time.Duration) time.Duration {
-	if status.LastCheckTime != nil && time.Since(status.LastCheckTime.Time) < interval {
-		return interval - time.Since(status.LastCheckTime.Time)
+// nextCheckDelay 返回距下一次健康检查的等待时间，0 表示立即检查。
+// 未设置 alignToMinute 时按上次检查时间加间隔；首次检查在进入 Running 后延迟抖动偏移。
+func nextCheckDelay(lt *infrav1alpha1.LoadTest, status *infrav1alpha1.HealthCheckStatus, interval time.Duration, now time.Time) time.Duration {
+	offset := checkJitter(lt)
+	start := now
+	if lt.Status.RunStartTime != nil {
+		start = lt.Status.RunStartTime.Time
+	}
+
+	var due time.Time
+	switch {
+	case lt.Spec.HealthCheck.AlignToMinute && status.LastCheckTime != nil:
+		// 从上次检查所在的对齐点推算，调和延迟不会让检查跳过对齐点
+		previous := floorSlot(status.LastCheckTime.Add(-offset), interval)
+		due = ceilSlot(previous.Add(interval), interval).Add(offset)
+	case lt.Spec.HealthCheck.AlignToMinute:
+		due = ceilSlot(start.Add(-offset), interval).Add(offset)
+	case status.LastCheckTime != nil:
+		due = status.LastCheckTime.Add(interval)
+	default:
+		due = start.Add(offset)
+	}
+	if delay := due.Sub(now); delay > 0 {
+		return delay
 	}
 	return 0
 }
 
+// checkJitter 按 UID 计算固定的检查偏移（毫秒精度），同一 LoadTest 每次计算结果相同。
+func checkJitter(lt *infrav1alpha1.LoadTest) time.Duration {
+	jitter := time.Duration(lt.Spec.HealthCheck.JitterSeconds) * time.Second
+	if jitter <= 0 {
+		return 0
+	}
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(lt.UID))
+	return time.Duration(h.Sum32()%uint32(jitter/time.Millisecond)) * time.Millisecond
+}
+
+// alignStep 对齐点的步长：间隔小于 1 分钟时为间隔（每分钟重新从 :00 开始），否则为 1 分钟。
+func alignStep(interval time.Duration) time.Duration {
+	if interval <= 0 || interval >= time.Minute {
+		return time.Minute
+	}
+	return interval
+}
+
+// floorSlot 返回不晚于 t 的最近对齐点。
+func floorSlot(t time.Time, interval time.Duration) time.Time {
+	minute := t.Truncate(time.Minute)
+	step := alignStep(interval)
+	return minute.Add(t.Sub(minute) / step * step)
+}
+
+// ceilSlot 返回不早于 t 的最近对齐点。
+func ceilSlot(t time.Time, interval time.Duration) time.Time {
+	slot := floorSlot(t, interval)
+	if slot.Equal(t) {
+		return slot
+	}
+	next := slot.Add(alignStep(interval))
+	if minute := t.Truncate(time.Minute).Add(time.Minute); next.After(minute) {
+		return minute
+	}
+	return next
+}
+
 // executeAndRecordHealthCheck 执行健康检查并记录结果。
 // 采用分散 patch 模式：先 patch 状态，成功后再发送 Event。
 func (r *LoadTestReconciler) executeAndRecordHealthCheck(
@@ -155,10 +253,26 @@ func (r *LoadTestReconciler) executeAndRecordHealthCheck(
 	state := r.buildStateForHealthCheck(ctx, lt)
 
 	// 执行检查
-	results, allPassed := r.runHealthCheckWithState(state, *lt.Spec.HealthCheck)
+	results, allPassed, errored := r.runHealthCheckWithState(ctx, state, *lt.Spec.HealthCheck)
+	now := metav1.Now()
+	warmupEnd, warmup := warmupDeadline(lt, now.Time)
+
+	// 趋势检查：每次采样一个数值并基于历史窗口判定（预热期内不采样）
+	if len(lt.Spec.HealthCheck.Trends) > 0 && !warmup {
+		trendResults, trendsPassed := r.runTrendChecks(ctx, shared.SelectStateForExpectation(state), lt.Spec.HealthCheck.Trends, status)
+		results = append(results, trendResults...)
+		allPassed = allPassed && trendsPassed
+		errored = errored && trendsPassed
+	}
+
+	if !warmup {
+		shared.SetExpectationWarnings(&lt.Status.Conditions, "health check", results, lt.Generation)
+	}
 
 	// 更新基础状态
-	now := metav1.Now()
+	wasErrored := erroredSummaries(status.LastResults)
+	// 上一次检查仍在预热期内、本次已结束：本次检查发送一次预热结束事件
+	leftWarmup := !warmup && status.WarmupCount > 0 && status.LastCheckTime != nil && status.LastCheckTime.Time.Before(warmupEnd)
 	status.LastCheckTime = &now
 	status.CheckCount++
 	status.LastResults = shared.ToExpectationResultSummaries(results)
@@ -166,31 +280,93 @@ func (r *LoadTestReconciler) executeAndRecordHealthCheck(
 	// 处理检查结果（只更新状态，不发送 Event）
 	var eventMsg string
 	var eventType string
-	if allPassed {
+	if warmup {
+		eventMsg = handleHealthCheckWarmup(ctx, status, results, allPassed, errored, warmupEnd)
+		eventType = "warmup"
+	} else if allPassed {
 		eventMsg = r.handleHealthCheckPass(lt, status)
 		eventType = "pass"
+	} else if errored {
+		eventMsg = r.handleHealthCheckError(ctx, status, results, wasErrored)
+		eventType = "error"
 	} else {
 		var shouldFail bool
-		eventMsg, shouldFail = r.handleHealthCheckFail(ctx, lt, status)
+		eventMsg, shouldFail = r.handleHealthCheckFail(ctx, lt, status, results)
 		eventType = "fail"
 		if shouldFail {
-			return ctrl.Result{}, nil
+			return ctrl.Result{Requeue: true}, nil
 		}
 	}
 
+	// 检查 API Server 最新状态：本次检查已被记录时不再重复 patch 和发送事件
+	if r.healthCheckAlreadyRecorded(ctx, lt, status.CheckCount) {
+		return ctrl.Result{RequeueAfter: interval}, nil
+	}
+
 	// 先 patch 状态
-	if err := shared.PatchStatusMerge(ctx, r.Client, lt); err != nil {
+	if err := shared.PatchLoadTestStatusFromObject(ctx, r.Client, lt); err != nil {
 		return ctrl.Result{}, err
 	}
+	recordHealthCheckMetrics(lt, status)
+	r.Hooks.HealthChecked(ctx, hooks.HealthCheckEvent{
+		Object:     hooks.ObjectRef{Kind: "LoadTest", Namespace: lt.Namespace, Name: lt.Name, UID: string(lt.UID), Labels: lt.Labels},
+		CheckCount: status.CheckCount,
+		Outcome:    eventType,
+		Results:    status.LastResults,
+	})
 
-	// patch 成功后再发送 Event
-	if eventType == "pass" {
-		shared.EmitNormalEvent(r.Recorder, lt, shared.EventReasonExpectationPassed, eventMsg)
-	} else {
-		shared.EmitWarningEvent(r.Recorder, lt, shared.EventReasonExpectationFailed, eventMsg)
+	// patch 成功后再发送 Event（每次检查一个幂等键，同类结果合并为一个系列）；预热期只在开始与结束时各发送一次
+	if leftWarmup {
+		r.emitHealthCheckEvent(lt, status.CheckCount, corev1.EventTypeNormal, shared.EventReasonHealthCheckWarmup,
+			fmt.Sprintf("Health check warm-up ended after %d check(s), checks are counted from now on", status.WarmupCount))
+	}
+	switch eventType {
+	case "warmup":
+		if eventMsg != "" {
+			r.emitHealthCheckEvent(lt, status.CheckCount, corev1.EventTypeNormal, shared.EventReasonHealthCheckWarmup, eventMsg)
+		}
+	case "pass":
+		r.emitHealthCheckEvent(lt, status.CheckCount, corev1.EventTypeNormal, shared.EventReasonExpectationPassed, eventMsg)
+	case "error":
+		if eventMsg != "" {
+			r.emitHealthCheckEvent(lt, status.CheckCount, corev1.EventTypeWarning, shared.EventReasonExpectationError, eventMsg)
+		}
+	default:
+		r.emitHealthCheckEvent(lt, status.CheckCount, corev1.EventTypeWarning, shared.EventReasonExpectationFailed, eventMsg)
+	}
+
+	// 检查耗时超过间隔时立即安排下一次
+	if delay := nextCheckDelay(lt, status, interval, time.Now()); delay > 0 {
+		return ctrl.Result{RequeueAfter: delay}, nil
+	}
+	return ctrl.Result{Requeue: true}, nil
+}
+
+// warmupDeadline 返回预热期（spec.warmupSeconds）的结束时间，以及 now 是否仍在预热期内。
+func warmupDeadline(lt *infrav1alpha1.LoadTest, now time.Time) (time.Time, bool) {
+	if lt.Spec.WarmupSeconds <= 0 || lt.Status.RunStartTime == nil {
+		return time.Time{}, false
 	}
+	end := lt.Status.RunStartTime.Add(time.Duration(lt.Spec.WarmupSeconds) * time.Second)
+	return end, now.Before(end)
+}
 
-	return ctrl.Result{RequeueAfter: interval}, nil
+// handleHealthCheckWarmup 处理预热期内的检查：只累加 WarmupCount，不计入通过/失败次数，也不改变连续失败与 ExpectationsMet。
+// 只在预热期的第一次检查返回 Event 消息（调用方负责 patch 后发送 Event），之后的检查只记录日志。
+func handleHealthCheckWarmup(ctx context.Context, status *infrav1alpha1.HealthCheckStatus, results []infrav1alpha1.ExpectationResult, passed, errored bool, end time.Time) string {
+	status.WarmupCount++
+	outcome := "failed: " + shared.FailedExpectationLabels(results)
+	switch {
+	case passed:
+		outcome = "passed"
+	case errored:
+		outcome = "errored"
+	}
+	logf.FromContext(ctx).Info("health check during warm-up, not counted", "outcome", outcome, "warmupEnd", end)
+	if status.WarmupCount > 1 {
+		return ""
+	}
+	return fmt.Sprintf("Health check warm-up started (until %s), checks are not counted; first check %s", end.UTC().Format(time.RFC3339), outcome)
 }
 
 // handleHealthCheckPass 处理健康检查通过的情况。
@@ -210,9 +386,32 @@ func (r *LoadTestReconciler) handleHealthCheckPass(lt *infrav1alpha1.LoadTest, s
 	return msg
 }
 
+// handleHealthCheckError 处理健康检查因可重试错误未得出结论的情况。
+// 只累加 ErrorCount，不计入失败次数，也不重置连续失败，下一次检查自动重试。
+// 返回 Event 消息：只在开始出错（上一次检查未出错）时返回，连续出错期间返回空字符串，不重复发送事件。
+func (r *LoadTestReconciler) handleHealthCheckError(ctx context.Context, status *infrav1alpha1.HealthCheckStatus, results []infrav1alpha1.ExpectationResult, wasErrored bool) string {
+	status.ErrorCount++
+	errCount := shared.ExpectationResults{AllOf: results}.ErrorCount()
+	logf.FromContext(ctx).Info("health check errored, will retry", "errors", errCount, "errorCount", status.ErrorCount)
+	if wasErrored {
+		return ""
+	}
+	return fmt.Sprintf("Health check errored, will retry (errors: %d, errored checks: %d)", errCount, status.ErrorCount)
+}
+
+// erroredSummaries 判断健康检查结果中是否有可重试错误。
+func erroredSummaries(summaries []infrav1alpha1.ExpectationResultSummary) bool {
+	for _, summary := range summaries {
+		if summary.Error {
+			return true
+		}
+	}
+	return false
+}
+
 // handleHealthCheckFail 处理健康检查失败的情况。
 // 只更新状态，返回 Event 消息和是否应该终止测试（调用方负责 patch 后发送 Event）。
-func (r *LoadTestReconciler) handleHealthCheckFail(ctx context.Context, lt *infrav1alpha1.LoadTest, status *infrav1alpha1.HealthCheckStatus) (string, bool) {
+func (r *LoadTestReconciler) handleHealthCheckFail(ctx context.Context, lt *infrav1alpha1.LoadTest, status *infrav1alpha1.HealthCheckStatus, results []infrav1alpha1.ExpectationResult) (string, bool) {
 	log := logf.FromContext(ctx)
 
 	status.FailCount++
@@ -221,7 +420,7 @@ func (r *LoadTestReconciler) handleHealthCheckFail(ctx context.Context, lt *infr
 	threshold := getOrDefaultInt32(lt.Spec.HealthCheck.FailureThreshold, 3)
 	logging.HealthCheckFailed(log, int(status.ConsecutiveFailures), int(threshold))
 
-	msg := fmt.Sprintf("Health check failed (consecutive failures: %d)", status.ConsecutiveFailures)
+	msg := fmt.Sprintf("Health check failed (consecutive failures: %d): %s", status.ConsecutiveFailures, shared.FailedExpectationLabels(results))
 
 	// 设置 ExpectationsMet Condition
 	shared.SetCondition(&lt.Status.Conditions, ConditionTypeExpectationsMet, metav1.ConditionFalse, "HealthCheckFailed", msg, lt.Generation)
@@ -245,29 +444,55 @@ func (r *LoadTestReconciler) buildStateForHealthCheck(
 	if err != nil || target == nil {
 		return map[string]interface{}{}
 	}
+	resource.AttachRelated(ctx, r.Client, target)
 	return buildStateFromTarget(target)
 }
 
 // runHealthCheckWithState 使用预构建的 state 执行健康检查。
-func (r *LoadTestReconciler) runHealthCheckWithState(state map[string]interface{}, healthCheck infrav1alpha1.HealthCheck) ([]infrav1alpha1.ExpectationResult, bool) {
-	runner := shared.NewExpectationRunner(r.PluginRegistry)
+// 返回结果、是否通过，以及未通过是否仅因可重试错误（此时不计为失败）。
+func (r *LoadTestReconciler) runHealthCheckWithState(ctx context.Context, state map[string]interface{}, healthCheck infrav1alpha1.HealthCheck) ([]infrav1alpha1.ExpectationResult, bool, bool) {
+	runner := shared.NewExpectationRunner(r.PluginRegistry).WithRelatedReader(ctx, r.Client).WithSubresourceClient(ctx, r.Client).WithFailureInjection(ctx).WithEnvironment(ctx).WithRecording(ctx)
 	results, err := runner.RunHealthCheck(&healthCheck, state)
 
 	// LoadTest 不中断执行，即使出错也继续
 	if err != nil {
-		return results.All(), false
+		return results.All(), false, false
 	}
 
-	return results.All(), results.Passed()
+	passed := results.Passed()
+	return results.All(), passed, !passed && onlyErrored(results)
+}
+
+// onlyErrored 判断检查未通过是否仅因可重试错误：
+// allOf 中没有断言未通过的项，且 anyOf（如有）中至少一项出错、结论尚不确定。
+func onlyErrored(results shared.ExpectationResults) bool {
+	if results.ErrorCount() == 0 {
+		return false
+	}
+	for _, result := range results.AllOf {
+		if !result.Passed && !result.Error && !shared.IsWarning(result) {
+			return false
+		}
+	}
+	if len(results.AnyOf) == 0 {
+		return true
+	}
+	for _, result := range results.AnyOf {
+		if result.Error {
+			return true
+		}
+	}
+	return false
 }
 
-// runReadyCondition 执行等待条件检查（用于 readyCondition）。
-func (r *LoadTestReconciler) runReadyCondition(target *unstructured.Unstructured, condition infrav1alpha1.ReadyCondition) ([]infrav1alpha1.ExpectationResult, bool) {
+// runReadyCondition 执行等待条件检查（用于 readyCondition），previous 为上次检查的结果（holdSeconds 计时）。
+func (r *LoadTestReconciler) runReadyCondition(ctx context.Context, target *unstructured.Unstructured, condition infrav1alpha1.ReadyCondition, previous []infrav1alpha1.ExpectationResult) ([]infrav1alpha1.ExpectationResult, bool) {
 	// 构建 state map，key 格式: apiVersion/kind/name
 	// 这样 SelectStateByResource 可以正确匹配 expectation.resource
 	state := buildStateFromTarget(target)
 
-	runner := shared.NewExpectationRunner(r.PluginRegistry)
+	runner := shared.NewExpectationRunner(r.PluginRegistry).WithRelatedReader(ctx, r.Client).WithSubresourceClient(ctx, r.Client).WithFailureInjection(ctx).WithEnvironment(ctx).WithRecording(ctx).
+		WithHeldSince(shared.HeldSinceFromResults(previous))
 	results, err := runner.RunReadyCondition(&condition, state)
 
 	if err != nil {
@@ -280,6 +505,7 @@ func (r *LoadTestReconciler) runReadyCondition(target *unstructured.Unstructured
 // buildStateFromTarget 将 target 资源转换为 state map。
 // key 格式: apiVersion/kind/name，与 SelectStateByResource 期望的格式一致。
 func buildStateFromTarget(target *unstructured.Unstructured) map[string]interface{} {
+	resource.StripManagedFields(target)
 	keyStr := fmt.Sprintf("%s/%s/%s", target.GetAPIVersion(), target.GetKind(), target.GetName())
 	return map[string]interface{}{
 		keyStr: target.Object,
@@ -20,9 +20,12 @@ import (
 	"context"
 	"crypto/sha256"
 	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"time"
 
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/labels"
@@ -34,6 +37,7 @@ import (
 	infrav1alpha1 "github.com/lunz1207/testplane/api/v1alpha1"
 	"github.com/lunz1207/testplane/internal/controller/shared"
 	"github.com/lunz1207/testplane/internal/controller/shared/logging"
+	"github.com/lunz1207/testplane/internal/controller/shared/metrics"
 	"github.com/lunz1207/testplane/internal/controller/shared/resource"
 )
 
@@ -44,8 +48,15 @@ func (r *LoadTestReconciler) reconcileInitializing(ctx context.Context, lt *infr
 	// 1. 应用并解析 Target
 	target, err := r.applyAndResolveTarget(ctx, lt)
 	if err != nil {
+		var noMatch *shared.SelectorNoMatchError
+		if errors.As(err, &noMatch) {
+			return r.waitForTargetSelector(ctx, lt, noMatch.Diagnostics)
+		}
 		return ctrl.Result{}, err
 	}
+	if lt.Status.ReadyConditionStatus != nil {
+		lt.Status.ReadyConditionStatus.SelectorDiagnostics = nil
+	}
 
 	// 2. 检查 ReadyCondition
 	readyCondition := lt.Spec.Target.ReadyCondition
@@ -62,6 +73,42 @@ func (r *LoadTestReconciler) reconcileInitializing(ctx context.Context, lt *infr
 	return r.checkReadyCondition(ctx, lt, target, readyCondition)
 }
 
+// waitForTargetSelector 处理 target 选择器未匹配到资源的情况。
+// 记录诊断信息到 ReadyConditionStatus 和 TargetReady Condition，超过就绪条件截止时间则失败。
+func (r *LoadTestReconciler) waitForTargetSelector(ctx context.Context, lt *infrav1alpha1.LoadTest, diag *infrav1alpha1.SelectorDiagnostics) (ctrl.Result, error) {
+	log := logf.FromContext(ctx)
+	logging.WaitingFor(log, "target selector", "selector", diag.Selector, "candidates", diag.CandidateCount)
+
+	if lt.Status.ReadyConditionStatus == nil {
+		now := metav1.Now()
+		timeout := shared.DefaultReadyConditionTimeout
+		if rc := lt.Spec.Target.ReadyCondition; rc != nil {
+			timeout = shared.GetTimeoutDuration(rc.TimeoutSeconds, shared.DefaultReadyConditionTimeout)
+		}
+		deadline := metav1.NewTime(now.Add(timeout))
+		lt.Status.ReadyConditionStatus = &infrav1alpha1.ReadyConditionStatus{
+			State:     shared.StatePending,
+			StartedAt: &now,
+			Deadline:  &deadline,
+		}
+	}
+	lt.Status.ReadyConditionStatus.SelectorDiagnostics = diag
+
+	if lt.Status.ReadyConditionStatus.Deadline != nil &&
+		time.Now().After(lt.Status.ReadyConditionStatus.Deadline.Time) {
+		msg := "target selector matched no resources before timeout: " + diag.Message
+		shared.SetCondition(&lt.Status.Conditions, ConditionTypeTargetReady, metav1.ConditionFalse, "SelectorNoMatch", msg, lt.Generation)
+		return r.setFailed(ctx, lt, "ReadyConditionTimeout", msg)
+	}
+
+	shared.SetCondition(&lt.Status.Conditions, ConditionTypeTargetReady, metav1.ConditionFalse, "SelectorNoMatch", diag.Message, lt.Generation)
+	metrics.Mark(ctx, metrics.OutcomeWaitedExpectation)
+	if err := shared.PatchLoadTestStatusFromObject(ctx, r.Client, lt); err != nil {
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{RequeueAfter: defaultRequeue}, nil
+}
+
 // initializeReadyConditionStatus 初始化就绪条件状态。
 func (r *LoadTestReconciler) initializeReadyConditionStatus(
 	ctx context.Context,
@@ -80,12 +127,12 @@ func (r *LoadTestReconciler) initializeReadyConditionStatus(
 		Deadline:  &deadline,
 	}
 
-	if err := shared.PatchStatusMerge(ctx, r.Client, lt); err != nil {
+	if err := shared.PatchLoadTestStatusFromObject(ctx, r.Client, lt); err != nil {
 		return ctrl.Result{}, fmt.Errorf("update readyConditionStatus: %w", err)
 	}
 
 	log.V(logging.LevelVerbose).Info("initialized readyCondition check", "timeout", timeout, "deadline", deadline.Time)
-	shared.EmitNormalEvent(r.Recorder, lt, shared.EventReasonReadyConditionWait,
+	r.emitNormalEvent(lt, "", shared.EventReasonReadyConditionWait,
 		fmt.Sprintf("Waiting for target to be ready (timeout: %v)", timeout))
 
 	return ctrl.Result{Requeue: true}, nil
@@ -109,7 +156,7 @@ func (r *LoadTestReconciler) checkReadyCondition(
 	}
 
 	// 执行 ReadyCondition 检查
-	results, allPassed := r.runReadyCondition(target, *readyCondition)
+	results, allPassed := r.runReadyCondition(ctx, target, *readyCondition, lt.Status.ReadyConditionStatus.Results)
 	lt.Status.ReadyConditionStatus.Results = results
 
 	if allPassed {
@@ -127,11 +174,13 @@ func (r *LoadTestReconciler) checkReadyCondition(
 
 	// 继续等待
 	logging.WaitingFor(log, "readyCondition", "results", summarizeResults(results))
-	if err := shared.PatchStatusMerge(ctx, r.Client, lt); err != nil {
+	metrics.Mark(ctx, metrics.OutcomeWaitedExpectation)
+	if err := shared.PatchLoadTestStatusFromObject(ctx, r.Client, lt); err != nil {
 		return ctrl.Result{}, err
 	}
 
-	return ctrl.Result{RequeueAfter: defaultRequeue}, nil
+	// 期望给出重试提示（如平台预计的剩余时间）时延后下次检查
+	return ctrl.Result{RequeueAfter: shared.HintedRequeue(shared.RetryAfterHint(results), defaultRequeue, lt.Status.ReadyConditionStatus.Deadline)}, nil
 }
 
 // annotationTargetSpecHash 用于存储 target spec hash 的 annotation key。
@@ -152,9 +201,10 @@ func (r *LoadTestReconciler) applyAndResolveTarget(ctx context.Context, lt *infr
 			return nil, fmt.Errorf("expand target template: %w", err)
 		}
 
-		// 计算当前 target spec 的 hash
+		// 计算当前 target spec 的 hash，同时记录到 status 便于追溯
 		currentHash := computeTemplateHash(&lt.Spec.Target.Resource.Manifest)
 		savedHash := lt.GetAnnotations()[annotationTargetSpecHash]
+		lt.Status.TargetSpecHash = currentHash
 
 		// 只在 hash 变化时 apply，避免重复 apply 导致 SSA 冲突
 		needEmitEvent := false
@@ -182,7 +232,7 @@ func (r *LoadTestReconciler) applyAndResolveTarget(ctx context.Context, lt *infr
 
 		// 只在实际 apply 时发送事件，避免重复
 		if needEmitEvent {
-			shared.EmitNormalEvent(r.Recorder, lt, shared.EventReasonTargetApplied,
+			r.emitNormalEvent(lt, "target-"+currentHash, shared.EventReasonTargetApplied,
 				fmt.Sprintf("Target %s/%s resolved", target.GetKind(), target.GetName()))
 		}
 		return target, nil
@@ -201,7 +251,7 @@ func (r *LoadTestReconciler) applyAndResolveTarget(ctx context.Context, lt *infr
 			if err := r.markSelectorResolved(ctx, lt); err != nil {
 				return nil, err
 			}
-			shared.EmitNormalEvent(r.Recorder, lt, shared.EventReasonTargetApplied,
+			r.emitNormalEvent(lt, "target", shared.EventReasonTargetApplied,
 				fmt.Sprintf("Target %s/%s resolved", target.GetKind(), target.GetName()))
 		}
 		return target, nil
@@ -245,11 +295,15 @@ func (r *LoadTestReconciler) markSelectorResolved(ctx context.Context, lt *infra
 	return nil
 }
 
-// computeTemplateHash 计算 template 的 SHA256 hash。
+// computeTemplateHash 计算 template 规范化后的 hash，字段顺序或空值写法不同不视为变化。
+// 模板不是合法 JSON 时退回原始字节的 hash。
 func computeTemplateHash(template *runtime.RawExtension) string {
 	if template == nil || len(template.Raw) == 0 {
 		return ""
 	}
+	if hash, err := shared.NormalizedHash(json.RawMessage(template.Raw)); err == nil {
+		return hash
+	}
 	hash := sha256.Sum256(template.Raw)
 	return hex.EncodeToString(hash[:])
 }
@@ -273,7 +327,7 @@ func (r *LoadTestReconciler) getResourceByManifest(ctx context.Context, manifest
 func (r *LoadTestReconciler) getResourceBySelector(ctx context.Context, lt *infrav1alpha1.LoadTest, sel infrav1alpha1.ResourceSelector) (*unstructured.Unstructured, error) {
 	ns := sel.Namespace
 	if ns == "" {
-		ns = lt.Namespace
+		ns = shared.EnvironmentNamespace(shared.EnvironmentFromContext(ctx), lt.Namespace)
 	}
 
 	if sel.Name != "" {
@@ -281,6 +335,9 @@ func (r *LoadTestReconciler) getResourceBySelector(ctx context.Context, lt *infr
 		obj.SetAPIVersion(sel.APIVersion)
 		obj.SetKind(sel.Kind)
 		if err := r.Get(ctx, client.ObjectKey{Namespace: ns, Name: sel.Name}, obj); err != nil {
+			if apierrors.IsNotFound(err) {
+				return nil, &shared.SelectorNoMatchError{Diagnostics: shared.DiagnoseSelector(ctx, r.Client, sel, ns, 0)}
+			}
 			return nil, fmt.Errorf("get target %s/%s: %w", sel.Kind, sel.Name, err)
 		}
 		return obj, nil
@@ -312,7 +369,7 @@ func (r *LoadTestReconciler) getResourceBySelector(ctx context.Context, lt *infr
 	}
 
 	if len(candidates) == 0 {
-		return nil, fmt.Errorf("no %s resource found for selector", sel.Kind)
+		return nil, &shared.SelectorNoMatchError{Diagnostics: shared.DiagnoseSelector(ctx, r.Client, sel, ns, 0)}
 	}
 
 	return &candidates[0], nil
@@ -0,0 +1,288 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package loadtest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	infrav1alpha1 "github.com/lunz1207/testplane/api/v1alpha1"
+	"github.com/lunz1207/testplane/internal/controller/shared"
+)
+
+const (
+	defaultTrendWindowSize       = 10
+	defaultTrendMinSamples       = 3
+	defaultTrendTolerancePercent = 10
+	promQueryTimeout             = 10 * time.Second
+)
+
+// promClient Prometheus 查询使用的 HTTP 客户端，超时兜底 context 未覆盖的阶段（如读取响应体）。
+var promClient = &http.Client{Timeout: promQueryTimeout}
+
+// trendResultPrefix 趋势检查结果在 ExpectationResult.Expect 中的前缀。
+const trendResultPrefix = "Trend:"
+
+// runTrendChecks 对每个趋势检查采样一次数值并判定趋势。
+// 样本窗口保存在 status.Trends 中，跨 reconcile 累积。
+func (r *LoadTestReconciler) runTrendChecks(
+	ctx context.Context,
+	target map[string]interface{},
+	trends []infrav1alpha1.TrendCheck,
+	status *infrav1alpha1.HealthCheckStatus,
+) ([]infrav1alpha1.ExpectationResult, bool) {
+	results := make([]infrav1alpha1.ExpectationResult, 0, len(trends))
+	allPassed := true
+	now := metav1.Now()
+
+	for _, trend := range trends {
+		ts := trendStatusFor(status, trend.Name)
+		result := infrav1alpha1.ExpectationResult{Expect: trendResultPrefix + trend.Name}
+
+		value, err := r.sampleTrend(ctx, target, trend)
+		if err != nil {
+			result.Message = fmt.Sprintf("sample failed: %v", err)
+			results = append(results, result)
+			allPassed = false
+			continue
+		}
+
+		window := trendWindowSize(trend)
+		ts.Samples = append(ts.Samples, infrav1alpha1.TrendSample{
+			Time:  now,
+			Value: strconv.FormatFloat(value, 'f', -1, 64),
+		})
+		if len(ts.Samples) > window {
+			ts.Samples = ts.Samples[len(ts.Samples)-window:]
+		}
+
+		passed, actual, msg := evaluateTrend(trend, ts.Samples)
+		result.Passed = passed
+		result.Actual = actual
+		if !passed {
+			result.Message = msg
+			allPassed = false
+		}
+		results = append(results, result)
+	}
+
+	return results, allPassed
+}
+
+// trendStatusFor 返回指定名称的趋势状态，不存在时创建。
+func trendStatusFor(status *infrav1alpha1.HealthCheckStatus, name string) *infrav1alpha1.TrendStatus {
+	for i := range status.Trends {
+		if status.Trends[i].Name == name {
+			return &status.Trends[i]
+		}
+	}
+	status.Trends = append(status.Trends, infrav1alpha1.TrendStatus{Name: name})
+	return &status.Trends[len(status.Trends)-1]
+}
+
+// trendWindowSize 返回趋势检查的窗口大小。
+func trendWindowSize(trend infrav1alpha1.TrendCheck) int {
+	if trend.WindowSize > 0 {
+		return int(trend.WindowSize)
+	}
+	return defaultTrendWindowSize
+}
+
+// sampleTrend 通过提取器或 Prometheus 查询获取一个数值样本。
+func (r *LoadTestReconciler) sampleTrend(ctx context.Context, target map[string]interface{}, trend infrav1alpha1.TrendCheck) (float64, error) {
+	switch {
+	case trend.Extract != nil && trend.PromQuery != nil:
+		return 0, fmt.Errorf("extract and promQuery are mutually exclusive")
+	case trend.Extract != nil:
+		if len(target) == 0 {
+			return 0, fmt.Errorf("target not found")
+		}
+		result, err := r.PluginRegistry.Call(trend.Extract.Function, target, trend.Extract.Params.Raw)
+		if err != nil {
+			return 0, fmt.Errorf("run function %s: %w", trend.Extract.Function, err)
+		}
+		return parseSample(result.Value)
+	case trend.PromQuery != nil:
+		return queryPrometheus(ctx, *trend.PromQuery)
+	default:
+		return 0, fmt.Errorf("one of extract or promQuery is required")
+	}
+}
+
+// parseSample 将提取值解析为数值。
+func parseSample(value string) (float64, error) {
+	v, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	if err != nil {
+		return 0, fmt.Errorf("value %q is not numeric", value)
+	}
+	return v, nil
+}
+
+// queryPrometheus 执行 Prometheus 即时查询，返回第一个结果值。
+// 未设置 url 时使用测试引用的 Environment 中名为 prometheus 的 baseURL。
+func queryPrometheus(ctx context.Context, q infrav1alpha1.PromQuery) (float64, error) {
+	ctx, cancel := context.WithTimeout(ctx, promQueryTimeout)
+	defer cancel()
+
+	base := q.URL
+	if base == "" {
+		base = shared.EnvironmentBaseURL(ctx, "prometheus")
+	}
+	if base == "" {
+		return 0, fmt.Errorf("prometheus url is empty and environment has no prometheus baseURL")
+	}
+	endpoint := strings.TrimRight(base, "/") + "/api/v1/query?query=" + url.QueryEscape(q.Query)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return 0, fmt.Errorf("build prometheus request: %w", err)
+	}
+	resp, err := promClient.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("query prometheus: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	var body struct {
+		Status string `json:"status"`
+		Error  string `json:"error"`
+		Data   struct {
+			ResultType string          `json:"resultType"`
+			Result     json.RawMessage `json:"result"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return 0, fmt.Errorf("decode prometheus response: %w", err)
+	}
+	if body.Status != "success" {
+		return 0, fmt.Errorf("prometheus query failed: %s", body.Error)
+	}
+
+	// scalar: [ts, "v"]；vector: [{"value": [ts, "v"]}, ...]
+	var pair []interface{}
+	switch body.Data.ResultType {
+	case "scalar":
+		if err := json.Unmarshal(body.Data.Result, &pair); err != nil {
+			return 0, fmt.Errorf("decode scalar result: %w", err)
+		}
+	case "vector":
+		var vector []struct {
+			Value []interface{} `json:"value"`
+		}
+		if err := json.Unmarshal(body.Data.Result, &vector); err != nil {
+			return 0, fmt.Errorf("decode vector result: %w", err)
+		}
+		if len(vector) == 0 {
+			return 0, fmt.Errorf("prometheus query returned no data")
+		}
+		pair = vector[0].Value
+	default:
+		return 0, fmt.Errorf("unsupported prometheus result type %q", body.Data.ResultType)
+	}
+
+	if len(pair) != 2 {
+		return 0, fmt.Errorf("unexpected prometheus sample %v", pair)
+	}
+	s, _ := pair[1].(string)
+	return parseSample(s)
+}
+
+// evaluateTrend 基于样本窗口判定趋势，返回 (passed, actual, message)。
+func evaluateTrend(trend infrav1alpha1.TrendCheck, samples []infrav1alpha1.TrendSample) (bool, string, string) {
+	values := make([]float64, 0, len(samples))
+	for _, s := range samples {
+		v, err := strconv.ParseFloat(s.Value, 64)
+		if err != nil {
+			return false, s.Value, fmt.Sprintf("invalid sample %q", s.Value)
+		}
+		values = append(values, v)
+	}
+
+	minSamples := defaultTrendMinSamples
+	if trend.MinSamples > 0 {
+		minSamples = int(trend.MinSamples)
+	}
+	last := strconv.FormatFloat(values[len(values)-1], 'f', -1, 64)
+	if len(values) < minSamples {
+		return true, fmt.Sprintf("samples=%d/%d, last=%s", len(values), minSamples, last), ""
+	}
+
+	switch trend.Type {
+	case infrav1alpha1.TrendNonDecreasing:
+		for i := 1; i < len(values); i++ {
+			if values[i] < values[i-1] {
+				return false, fmt.Sprintf("%v -> %v", values[i-1], values[i]),
+					fmt.Sprintf("value decreased at sample %d", i)
+			}
+		}
+		return true, fmt.Sprintf("last=%s", last), ""
+
+	case infrav1alpha1.TrendStableWithin:
+		tolerance := float64(defaultTrendTolerancePercent)
+		if trend.TolerancePercent > 0 {
+			tolerance = float64(trend.TolerancePercent)
+		}
+		mean := 0.0
+		for _, v := range values {
+			mean += v
+		}
+		mean /= float64(len(values))
+		maxDev := 0.0
+		for _, v := range values {
+			maxDev = math.Max(maxDev, math.Abs(v-mean))
+		}
+		devPercent := 0.0
+		if mean != 0 {
+			devPercent = maxDev / math.Abs(mean) * 100
+		} else if maxDev > 0 {
+			devPercent = math.Inf(1)
+		}
+		actual := fmt.Sprintf("mean=%.4g, maxDeviation=%.2f%%", mean, devPercent)
+		if devPercent > tolerance {
+			return false, actual, fmt.Sprintf("deviation exceeds ±%v%%", tolerance)
+		}
+		return true, actual, ""
+
+	case infrav1alpha1.TrendGrowthRateBelow:
+		maxRate, err := strconv.ParseFloat(trend.MaxGrowthPerMinute, 64)
+		if err != nil {
+			return false, "", fmt.Sprintf("invalid maxGrowthPerMinute %q", trend.MaxGrowthPerMinute)
+		}
+		elapsed := samples[len(samples)-1].Time.Sub(samples[0].Time.Time).Minutes()
+		if elapsed <= 0 {
+			return true, fmt.Sprintf("last=%s", last), ""
+		}
+		rate := (values[len(values)-1] - values[0]) / elapsed
+		actual := fmt.Sprintf("growth=%.4g/min", rate)
+		// 等于阈值视为通过："0" 允许平稳的序列，只拒绝增长
+		if rate > maxRate {
+			return false, actual, fmt.Sprintf("growth rate exceeds %v/min", maxRate)
+		}
+		return true, actual, ""
+
+	default:
+		return false, "", fmt.Sprintf("unknown trend type %q", trend.Type)
+	}
+}
@@ -0,0 +1,68 @@
+package loadtest
+
+import (
+	"context"
+	"fmt"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	infrav1alpha1 "github.com/lunz1207/testplane/api/v1alpha1"
+	"github.com/lunz1207/testplane/internal/controller/shared"
+)
+
+// triageFailure LoadTest 以 Failed 结束且配置了 spec.triage 时调用分诊 Webhook，结果记录到 status.triage。
+// 调用失败按间隔重试，直到达到最大尝试次数。先 patch，成功后再发 Event。
+func (r *LoadTestReconciler) triageFailure(ctx context.Context, lt *infrav1alpha1.LoadTest) (ctrl.Result, error) {
+	if lt.Status.Phase != infrav1alpha1.LoadTestFailed {
+		return ctrl.Result{}, nil
+	}
+	due, wait := shared.TriageDue(lt.Spec.Triage, lt.Status.Triage)
+	if !due {
+		return ctrl.Result{RequeueAfter: wait}, nil
+	}
+	// 缓存尚未同步上一次分诊结果：避免重复调用 Webhook
+	if r.triageAlreadyAttempted(ctx, lt) {
+		return ctrl.Result{Requeue: true}, nil
+	}
+
+	lt.Status.Triage = shared.RunTriage(ctx, lt.Spec.Triage, lt.Status.Triage, buildTriageRequest(ctx, lt))
+	if err := shared.PatchLoadTestStatusFromObject(ctx, r.Client, lt); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	triage := lt.Status.Triage
+	switch {
+	case triage.State == infrav1alpha1.TriageStateSucceeded:
+		logf.FromContext(ctx).Info("failure triaged", "ticket", triage.Ticket, "knownIssue", triage.KnownIssue)
+		r.emitNormalEvent(lt, "", shared.EventReasonTriageCompleted, shared.TriageEventMessage(triage))
+		return ctrl.Result{}, nil
+	case shared.TriageExhausted(lt.Spec.Triage, triage):
+		r.emitWarningEvent(lt, "", shared.EventReasonTriageFailed, fmt.Sprintf("Triage failed after %d attempts: %s", triage.Attempts, triage.Message))
+		return ctrl.Result{}, nil
+	}
+	return ctrl.Result{RequeueAfter: shared.TriageRetryInterval}, nil
+}
+
+// buildTriageRequest 构造分诊请求：失败原因、最近一次健康检查中未通过的期望与收集的工件。
+func buildTriageRequest(ctx context.Context, lt *infrav1alpha1.LoadTest) shared.TriageRequest {
+	req := shared.NewTriageRequest(ctx, "LoadTest", lt, lt.Spec.Triage)
+	req.Reason = lt.Status.Reason
+	req.Message = lt.Status.Message
+	req.StartTime = lt.Status.StartTime
+	req.CompletionTime = lt.Status.CompletionTime
+	req.Artifacts = lt.Status.Artifacts
+	if hc := lt.Status.HealthCheckStatus; hc != nil {
+		req.Expectations = shared.FailedExpectations(hc.LastResults)
+	}
+	return req
+}
+
+// triageAlreadyAttempted 检查 API Server 上的分诊尝试次数是否已比本地状态多。
+func (r *LoadTestReconciler) triageAlreadyAttempted(ctx context.Context, lt *infrav1alpha1.LoadTest) bool {
+	latest := r.latestStatus(ctx, lt)
+	if latest == nil || latest.Triage == nil {
+		return false
+	}
+	return lt.Status.Triage == nil || latest.Triage.Attempts > lt.Status.Triage.Attempts
+}
@@ -0,0 +1,213 @@
+package loadtest
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	infrav1alpha1 "github.com/lunz1207/testplane/api/v1alpha1"
+	"github.com/lunz1207/testplane/internal/controller/shared"
+)
+
+// verificationStatusChanged 只放行子测试 status 变化的更新：LoadTest 只镜像子测试的状态，
+// 自身创建子测试、IntegrationTest 控制器添加 finalizer 等引起的更新无需调和。
+func verificationStatusChanged() predicate.Predicate {
+	return predicate.Funcs{UpdateFunc: func(e event.UpdateEvent) bool {
+		oldIT, okOld := e.ObjectOld.(*infrav1alpha1.IntegrationTest)
+		newIT, okNew := e.ObjectNew.(*infrav1alpha1.IntegrationTest)
+		if !okOld || !okNew {
+			return true
+		}
+		return !equality.Semantic.DeepEqual(oldIT.Status, newIT.Status)
+	}}
+}
+
+// ReasonVerificationTestConflict 验证用 IntegrationTest 的名称被其他对象占用时 LoadTest 的失败原因。
+const ReasonVerificationTestConflict = "VerificationTestConflict"
+
+// verificationTestName 执行 PostVerification 的 IntegrationTest 名称。
+func verificationTestName(lt *infrav1alpha1.LoadTest) string {
+	return lt.Name + "-verify"
+}
+
+// runDurationRemaining 返回运行时长的剩余时间；未设置 DurationSeconds 时 limited 为 false。
+func runDurationRemaining(lt *infrav1alpha1.LoadTest) (time.Duration, bool) {
+	if lt.Spec.DurationSeconds <= 0 || lt.Status.RunStartTime == nil {
+		return 0, false
+	}
+	end := lt.Status.RunStartTime.Add(time.Duration(lt.Spec.DurationSeconds) * time.Second)
+	return time.Until(end), true
+}
+
+// capRequeue 保证在运行时长到期时重新调和。
+func capRequeue(result ctrl.Result, remaining time.Duration, limited bool) ctrl.Result {
+	if limited && remaining > 0 && (result.RequeueAfter == 0 || result.RequeueAfter > remaining) {
+		result.RequeueAfter = remaining
+	}
+	return result
+}
+
+// finishRun 运行时长到期：停止负载，有 PostVerification 时进入 Verifying，否则直接 Succeeded。
+// 先 patch，成功后再发 Event。
+func (r *LoadTestReconciler) finishRun(ctx context.Context, lt *infrav1alpha1.LoadTest) (ctrl.Result, error) {
+	// 缓存尚未同步上一次 status 写入：阶段已推进，避免重复事件
+	if r.phaseAlreadyAdvanced(ctx, lt) {
+		return ctrl.Result{Requeue: true}, nil
+	}
+	// 停止 workload 前收集工件，随阶段变化一起 patch
+	r.collectArtifacts(ctx, lt)
+	if err := r.stopWorkload(ctx, lt); err != nil {
+		return ctrl.Result{}, err
+	}
+	logf.FromContext(ctx).Info("run duration elapsed, workload stopped", "durationSeconds", lt.Spec.DurationSeconds)
+
+	if lt.Spec.PostVerification == nil {
+		lt.Status.Phase = infrav1alpha1.LoadTestSucceeded
+		lt.Status.Reason = "DurationElapsed"
+		lt.Status.Message = "run duration elapsed"
+		if err := shared.PatchLoadTestStatusFromObject(ctx, r.Client, lt); err != nil {
+			return ctrl.Result{}, err
+		}
+		// 完成时间与成功事件由 reconcileTerminal 处理
+		return ctrl.Result{Requeue: true}, nil
+	}
+
+	lt.Status.Phase = infrav1alpha1.LoadTestVerifying
+	lt.Status.Reason = "PostVerification"
+	lt.Status.Message = "run duration elapsed, verifying"
+	shared.SetCondition(&lt.Status.Conditions, ConditionTypeReady, metav1.ConditionFalse, "Verifying", "Running post verification", lt.Generation)
+	if err := shared.PatchLoadTestStatusFromObject(ctx, r.Client, lt); err != nil {
+		return ctrl.Result{}, err
+	}
+	r.emitNormalEvent(lt, "", shared.EventReasonPostVerificationStarted,
+		fmt.Sprintf("Run duration elapsed, verifying with IntegrationTest %s", verificationTestName(lt)))
+	return ctrl.Result{Requeue: true}, nil
+}
+
+// stopWorkload 删除 workload 资源（含内置 HTTP 负载 worker）以停止施压。
+func (r *LoadTestReconciler) stopWorkload(ctx context.Context, lt *infrav1alpha1.LoadTest) error {
+	specs, err := r.expandResources(lt, lt.Spec.Workload.Resources)
+	if err != nil {
+		return fmt.Errorf("expand workload resources: %w", err)
+	}
+	objs := make([]*unstructured.Unstructured, 0, len(specs)+1)
+	for i := range specs {
+		objs = append(objs, specs[i].Object)
+	}
+	if lt.Spec.Workload.HTTPLoad != nil {
+		worker := &unstructured.Unstructured{}
+		worker.SetAPIVersion("apps/v1")
+		worker.SetKind("Deployment")
+		worker.SetNamespace(lt.Namespace)
+		worker.SetName(httpLoadName(lt))
+		objs = append(objs, worker)
+	}
+	// 按 apply 的相反顺序删除
+	for i := len(objs) - 1; i >= 0; i-- {
+		if err := r.ResourceManager.DeleteObject(ctx, objs[i]); err != nil {
+			return fmt.Errorf("stop workload: %w", err)
+		}
+	}
+	return nil
+}
+
+// reconcileVerifying 处理 Verifying 阶段：创建验证用 IntegrationTest，
+// 将其状态镜像到 status.postVerification，完成后决定最终阶段。
+func (r *LoadTestReconciler) reconcileVerifying(ctx context.Context, lt *infrav1alpha1.LoadTest) (ctrl.Result, error) {
+	var it infrav1alpha1.IntegrationTest
+	err := r.Get(ctx, client.ObjectKey{Namespace: lt.Namespace, Name: verificationTestName(lt)}, &it)
+	if apierrors.IsNotFound(err) {
+		return r.createVerificationTest(ctx, lt)
+	}
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("get verification test: %w", err)
+	}
+	// 同名 IntegrationTest 不是本 LoadTest 创建的：不能把它的结果当作验证结果
+	if !metav1.IsControlledBy(&it, lt) {
+		return r.setFailed(ctx, lt, ReasonVerificationTestConflict,
+			fmt.Sprintf("IntegrationTest %s already exists and is not controlled by this LoadTest; rename or delete it", it.Name))
+	}
+
+	pv := &infrav1alpha1.PostVerificationStatus{
+		TestName: it.Name,
+		Phase:    it.Status.Phase,
+		Message:  it.Status.Message,
+		Steps:    it.Status.Steps,
+	}
+	changed := !equality.Semantic.DeepEqual(lt.Status.PostVerification, pv)
+	lt.Status.PostVerification = pv
+
+	switch it.Status.Phase {
+	case infrav1alpha1.IntegrationTestPhaseSucceeded:
+		if r.phaseAlreadyAdvanced(ctx, lt) {
+			return ctrl.Result{Requeue: true}, nil
+		}
+		lt.Status.Phase = infrav1alpha1.LoadTestSucceeded
+		lt.Status.Reason = "PostVerificationSucceeded"
+		lt.Status.Message = "post verification succeeded"
+		if err := shared.PatchLoadTestStatusFromObject(ctx, r.Client, lt); err != nil {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{Requeue: true}, nil
+	case infrav1alpha1.IntegrationTestPhaseFailed, infrav1alpha1.IntegrationTestPhaseAborted:
+		return r.setFailed(ctx, lt, "PostVerificationFailed", fmt.Sprintf("post verification %s: %s", it.Status.Phase, it.Status.Message))
+	}
+
+	if changed {
+		if err := shared.PatchLoadTestStatusFromObject(ctx, r.Client, lt); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+	return ctrl.Result{RequeueAfter: defaultRequeue}, nil
+}
+
+// createVerificationTest 以 LoadTest 为 controller owner 创建验证用 IntegrationTest。
+// 已存在（缓存延迟）时视为创建成功。
+func (r *LoadTestReconciler) createVerificationTest(ctx context.Context, lt *infrav1alpha1.LoadTest) (ctrl.Result, error) {
+	it := buildVerificationTest(lt)
+	if err := controllerutil.SetControllerReference(lt, it, r.Scheme); err != nil {
+		return ctrl.Result{}, fmt.Errorf("set owner reference for verification test: %w", err)
+	}
+	if err := r.Create(ctx, it); err != nil && !apierrors.IsAlreadyExists(err) {
+		return ctrl.Result{}, fmt.Errorf("create verification test: %w", err)
+	}
+
+	lt.Status.PostVerification = &infrav1alpha1.PostVerificationStatus{
+		TestName: it.Name,
+		Phase:    infrav1alpha1.IntegrationTestPhasePending,
+	}
+	if err := shared.PatchLoadTestStatusFromObject(ctx, r.Client, lt); err != nil {
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{RequeueAfter: defaultRequeue}, nil
+}
+
+// buildVerificationTest 生成执行 PostVerification 步骤的 IntegrationTest。
+func buildVerificationTest(lt *infrav1alpha1.LoadTest) *infrav1alpha1.IntegrationTest {
+	return &infrav1alpha1.IntegrationTest{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      verificationTestName(lt),
+			Namespace: lt.Namespace,
+			Labels: map[string]string{
+				labelLoadTestOwner: lt.Name,
+				labelComponent:     "post-verification",
+			},
+		},
+		Spec: infrav1alpha1.IntegrationTestSpec{
+			Mode:           lt.Spec.PostVerification.Mode,
+			Steps:          lt.Spec.PostVerification.Steps,
+			EnvironmentRef: lt.Spec.EnvironmentRef,
+		},
+	}
+}
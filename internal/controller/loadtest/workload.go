@@ -25,33 +25,80 @@ import (
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 
 	infrav1alpha1 "github.com/lunz1207/testplane/api/v1alpha1"
+	"github.com/lunz1207/testplane/internal/controller/shared"
+	"github.com/lunz1207/testplane/internal/controller/shared/resource"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 )
 
 const annotationInjectPrefix = "testplane.io/inject-"
 
-// applyWorkload 应用 workload 资源（创建或更新）。
+// applyWorkload 应用 workload 资源（创建或更新），并记录 workload hash（调用方负责 patch）。
 func (r *LoadTestReconciler) applyWorkload(ctx context.Context, lt *infrav1alpha1.LoadTest) error {
 	log := logf.FromContext(ctx)
 
+	specs, err := r.workloadManifests(lt)
+	if err != nil {
+		return err
+	}
+
+	if err := r.applyResources(ctx, lt, specs); err != nil {
+		return fmt.Errorf("apply workload resources: %w", err)
+	}
+
+	lt.Status.WorkloadSpecHash = workloadSpecHash(lt)
+	log.Info("workload resources applied", "count", len(specs), "hash", lt.Status.WorkloadSpecHash)
+	return nil
+}
+
+// workloadManifests 展开 workload 资源清单：注入提取值、追加内置 HTTP 负载 worker、复制测试注解，
+// 闭环负载控制调整过的参数使用调整后的值，重新部署不会回退到清单中的初始值。
+func (r *LoadTestReconciler) workloadManifests(lt *infrav1alpha1.LoadTest) ([]resource.ExpandedManifest, error) {
 	specs, err := r.expandResources(lt, lt.Spec.Workload.Resources)
 	if err != nil {
-		return fmt.Errorf("expand workload resources: %w", err)
+		return nil, fmt.Errorf("expand workload resources: %w", err)
 	}
 
 	// 将提取的值注入到 Pod template annotations
 	for i := range specs {
 		if err := injectAnnotationsToWorkload(specs[i].Object, lt.Status.InjectedValues); err != nil {
-			return fmt.Errorf("inject annotations to workload: %w", err)
+			return nil, fmt.Errorf("inject annotations to workload: %w", err)
 		}
 	}
 
-	if err := r.applyResources(ctx, lt, specs); err != nil {
-		return fmt.Errorf("apply workload resources: %w", err)
+	// 内置 HTTP 负载：由控制器生成 worker Deployment
+	if lt.Spec.Workload.HTTPLoad != nil {
+		manifest, err := r.buildHTTPLoadManifest(lt)
+		if err != nil {
+			return nil, err
+		}
+		specs = append(specs, manifest)
 	}
 
-	log.Info("workload resources applied", "count", len(specs))
-	return nil
+	// 复制测试注解，便于从 workload 资源追溯到 LoadTest 与 CI 运行
+	for i := range specs {
+		resource.PropagateAnnotations(&specs[i], lt.Annotations, lt.Spec.PropagateAnnotations)
+	}
+
+	if control, st := lt.Spec.LoadControl, lt.Status.LoadControl; control != nil && st != nil && st.Adjustments > 0 {
+		if obj := loadParameterManifest(specs, control.Parameter); obj != nil {
+			if err := writeLoadParameter(obj, control.Parameter, st.CurrentValue); err != nil {
+				return nil, fmt.Errorf("set load parameter: %w", err)
+			}
+		}
+	}
+	return specs, nil
+}
+
+// workloadSpecHash 计算 workload 定义与注入值的规范化 hash，注入值变化同样需要重新部署。
+func workloadSpecHash(lt *infrav1alpha1.LoadTest) string {
+	hash, err := shared.NormalizedHash(map[string]interface{}{
+		"workload": lt.Spec.Workload,
+		"injected": lt.Status.InjectedValues,
+	})
+	if err != nil {
+		return ""
+	}
+	return hash
 }
 
 // injectAnnotationsToWorkload 将提取的值注入到 workload 资源的 Pod template annotations 中。
@@ -0,0 +1,241 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduledtest
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	infrav1alpha1 "github.com/lunz1207/testplane/api/v1alpha1"
+	"github.com/lunz1207/testplane/internal/controller/shared"
+)
+
+// run ScheduledTest 创建的一个测试。
+type run struct {
+	obj       client.Object
+	scheduled time.Time
+	status    infrav1alpha1.ScheduledTestRun
+}
+
+// runName 返回触发时间对应的测试名称：<name>-<触发时间的 Unix 分钟数>（与 CronJob 一致），重复调和创建同名对象。
+func runName(st *infrav1alpha1.ScheduledTest, scheduled time.Time) string {
+	return fmt.Sprintf("%s-%d", st.Name, scheduled.Unix()/60)
+}
+
+// validateTemplate 检查必须且只能设置 templateRef 与 spec 之一，内联 spec 可按 kind 解析。
+func validateTemplate(t infrav1alpha1.ScheduledTestTemplate) error {
+	if (t.TemplateRef == nil) == (t.Spec == nil) {
+		return fmt.Errorf("exactly one of template.templateRef and template.spec must be set")
+	}
+	if t.Spec != nil {
+		if _, err := shared.DecodeInlineTest(t.Kind, t.Spec); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// listRuns 列出 ScheduledTest 创建且未在删除中的测试，按触发时间从新到旧排列。
+func (r *ScheduledTestReconciler) listRuns(ctx context.Context, st *infrav1alpha1.ScheduledTest) ([]run, error) {
+	opts := []client.ListOption{client.InNamespace(st.Namespace), client.MatchingLabels{infrav1alpha1.LabelScheduledTest: st.Name}}
+	var objs []client.Object
+	switch st.Spec.Template.Kind {
+	case infrav1alpha1.TestSuiteItemIntegrationTest:
+		var list infrav1alpha1.IntegrationTestList
+		if err := r.List(ctx, &list, opts...); err != nil {
+			return nil, err
+		}
+		for i := range list.Items {
+			objs = append(objs, &list.Items[i])
+		}
+	case infrav1alpha1.TestSuiteItemLoadTest:
+		var list infrav1alpha1.LoadTestList
+		if err := r.List(ctx, &list, opts...); err != nil {
+			return nil, err
+		}
+		for i := range list.Items {
+			objs = append(objs, &list.Items[i])
+		}
+	}
+
+	var runs []run
+	for _, obj := range objs {
+		if !metav1.IsControlledBy(obj, st) || !obj.GetDeletionTimestamp().IsZero() {
+			continue
+		}
+		scheduled := obj.GetCreationTimestamp().Time
+		if t, err := time.Parse(time.RFC3339, obj.GetAnnotations()[infrav1alpha1.AnnotationScheduledTime]); err == nil {
+			scheduled = t
+		}
+		phase, finished, passed, reason, _ := shared.TestOutcome(obj)
+		state := infrav1alpha1.ScheduledTestRunActive
+		switch {
+		case finished && passed:
+			state = infrav1alpha1.ScheduledTestRunSucceeded
+		case finished:
+			state = infrav1alpha1.ScheduledTestRunFailed
+		}
+		scheduledAt := metav1.NewTime(scheduled)
+		runs = append(runs, run{obj: obj, scheduled: scheduled, status: infrav1alpha1.ScheduledTestRun{
+			Name: obj.GetName(), ScheduledTime: &scheduledAt, State: state, Phase: phase, Reason: reason,
+		}})
+	}
+	sort.SliceStable(runs, func(i, j int) bool { return runs[i].scheduled.After(runs[j].scheduled) })
+	return runs, nil
+}
+
+// pruneHistory 删除超出 successfulRunsHistoryLimit/failedRunsHistoryLimit 的最旧测试，返回保留的运行。
+// 删除的测试按自身 finalizer 的依赖顺序清理资源。
+func (r *ScheduledTestReconciler) pruneHistory(ctx context.Context, st *infrav1alpha1.ScheduledTest, runs []run) ([]run, error) {
+	limits := map[infrav1alpha1.ScheduledTestRunState]int{
+		infrav1alpha1.ScheduledTestRunSucceeded: historyLimit(st.Spec.SuccessfulRunsHistoryLimit, 3),
+		infrav1alpha1.ScheduledTestRunFailed:    historyLimit(st.Spec.FailedRunsHistoryLimit, 1),
+	}
+	seen := map[infrav1alpha1.ScheduledTestRunState]int{}
+	kept := runs[:0:0]
+	for _, rn := range runs {
+		limit, bounded := limits[rn.status.State]
+		seen[rn.status.State]++
+		if !bounded || seen[rn.status.State] <= limit {
+			kept = append(kept, rn)
+			continue
+		}
+		if err := r.Delete(ctx, rn.obj, client.PropagationPolicy(metav1.DeletePropagationBackground)); client.IgnoreNotFound(err) != nil {
+			return nil, err
+		}
+		logf.FromContext(ctx).Info("pruned run beyond history limit", "test", rn.obj.GetName(), "state", rn.status.State)
+	}
+	return kept, nil
+}
+
+// historyLimit 返回保留数量，未设置时使用默认值。
+func historyLimit(limit *int32, def int) int {
+	if limit == nil {
+		return def
+	}
+	return int(*limit)
+}
+
+// syncRuns 把保留的运行写入状态，并更新运行中的数量与最近一次成功的结束时间。
+func (r *ScheduledTestReconciler) syncRuns(st *infrav1alpha1.ScheduledTest, runs []run) {
+	st.Status.Runs = make([]infrav1alpha1.ScheduledTestRun, 0, len(runs))
+	st.Status.Active = 0
+	for _, rn := range runs {
+		st.Status.Runs = append(st.Status.Runs, rn.status)
+		switch rn.status.State {
+		case infrav1alpha1.ScheduledTestRunActive:
+			st.Status.Active++
+		case infrav1alpha1.ScheduledTestRunSucceeded:
+			if done := completionTime(rn.obj); done != nil &&
+				(st.Status.LastSuccessfulTime == nil || done.After(st.Status.LastSuccessfulTime.Time)) {
+				st.Status.LastSuccessfulTime = done
+			}
+		}
+	}
+	if len(st.Status.Runs) == 0 {
+		st.Status.Runs = nil
+	}
+}
+
+// completionTime 返回测试的结束时间。
+func completionTime(obj client.Object) *metav1.Time {
+	switch o := obj.(type) {
+	case *infrav1alpha1.IntegrationTest:
+		return o.Status.CompletionTime
+	case *infrav1alpha1.LoadTest:
+		return o.Status.CompletionTime
+	}
+	return nil
+}
+
+// activeRuns 返回运行中的测试。
+func activeRuns(runs []run) []run {
+	var active []run
+	for _, rn := range runs {
+		if rn.status.State == infrav1alpha1.ScheduledTestRunActive {
+			active = append(active, rn)
+		}
+	}
+	return active
+}
+
+// createRun 由模板或内联 spec 创建触发时间对应的测试。同名测试已存在且归属该 ScheduledTest 时视为已创建
+// （缓存滞后的重复调和）；模板不存在时该次触发视为错过，Ready 置为 False。
+func (r *ScheduledTestReconciler) createRun(ctx context.Context, st *infrav1alpha1.ScheduledTest, scheduled time.Time) (runEvent, error) {
+	t := st.Spec.Template
+	at := scheduled.Format(time.RFC3339)
+	var obj client.Object
+	if t.TemplateRef != nil {
+		template, found, err := shared.TestFromTemplate(ctx, r.Client, st.Namespace, t.Kind, t.TemplateRef.Name)
+		if err != nil {
+			return runEvent{}, err
+		}
+		if !found {
+			message := fmt.Sprintf("%s template %q not found", t.Kind, t.TemplateRef.Name)
+			shared.SetCondition(&st.Status.Conditions, infrav1alpha1.ConditionReady, metav1.ConditionFalse, ReasonTemplateNotFound, message, st.Generation)
+			return runEvent{key: at, eventType: corev1.EventTypeWarning, reason: shared.EventReasonScheduledTestRunMissed,
+				message: fmt.Sprintf("错过 %s 的触发：%s", at, message)}, nil
+		}
+		obj = template
+	} else {
+		inline, err := shared.DecodeInlineTest(t.Kind, t.Spec)
+		if err != nil {
+			return runEvent{}, err
+		}
+		obj = inline
+	}
+
+	obj.SetName(runName(st, scheduled))
+	obj.SetNamespace(st.Namespace)
+	labels := obj.GetLabels()
+	labels[infrav1alpha1.LabelScheduledTest] = st.Name
+	obj.SetLabels(labels)
+	annotations := obj.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[infrav1alpha1.AnnotationScheduledTime] = at
+	obj.SetAnnotations(annotations)
+	if err := controllerutil.SetControllerReference(st, obj, r.Scheme); err != nil {
+		return runEvent{}, err
+	}
+
+	if err := r.Create(ctx, obj); err != nil {
+		if !apierrors.IsAlreadyExists(err) {
+			return runEvent{}, err
+		}
+		existing, _ := shared.NewTestObject(t.Kind)
+		if err := r.Get(ctx, client.ObjectKeyFromObject(obj), existing); err != nil {
+			return runEvent{}, err
+		}
+		if !metav1.IsControlledBy(existing, st) {
+			return runEvent{}, fmt.Errorf("%s %s already exists and is not owned by this scheduled test", t.Kind, obj.GetName())
+		}
+	}
+	logf.FromContext(ctx).Info("scheduled run created", "test", obj.GetName(), "kind", t.Kind, "scheduledTime", at)
+	return runEvent{key: obj.GetName(), eventType: corev1.EventTypeNormal, reason: shared.EventReasonScheduledTestRunCreated,
+		message: fmt.Sprintf("按 %s 的触发创建 %s %s", at, t.Kind, obj.GetName())}, nil
+}
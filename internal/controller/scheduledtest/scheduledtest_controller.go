@@ -0,0 +1,347 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduledtest
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	infrav1alpha1 "github.com/lunz1207/testplane/api/v1alpha1"
+	"github.com/lunz1207/testplane/internal/controller/shared"
+	"github.com/lunz1207/testplane/internal/controller/shared/logging"
+	"github.com/lunz1207/testplane/internal/controller/shared/metrics"
+)
+
+// Ready Condition 的原因。
+const (
+	// ReasonScheduled schedule 与 template 有效，按计划创建测试。
+	ReasonScheduled = "Scheduled"
+	// ReasonSuspended spec.suspend 暂停调度。
+	ReasonSuspended = "Suspended"
+	// ReasonInvalidSchedule cron 表达式或时区无效。
+	ReasonInvalidSchedule = "InvalidSchedule"
+	// ReasonInvalidTemplate 未设置或同时设置 templateRef 与 spec、内联 spec 无法解析。
+	ReasonInvalidTemplate = "InvalidTemplate"
+	// ReasonTemplateNotFound 引用的模板不存在（该次触发视为错过）。
+	ReasonTemplateNotFound = "TemplateNotFound"
+)
+
+// ScheduledTestReconciler reconciles a ScheduledTest object.
+// 创建的测试通过 ownerReference 归属 ScheduledTest，删除时由垃圾回收级联删除，因此无需 finalizer。
+type ScheduledTestReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+	// Debounce 更新事件合并窗口（可选）。
+	Debounce time.Duration
+}
+
+// +kubebuilder:rbac:groups=infra.testplane.io,resources=scheduledtests,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=infra.testplane.io,resources=scheduledtests/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=infra.testplane.io,resources=integrationtests;loadtests,verbs=get;list;watch;create;patch;delete
+
+func (r *ScheduledTestReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	// 按调和结果记录耗时、重新入队与错误指标
+	ctx, done := metrics.Start(ctx, "scheduledtest")
+	result, err := r.reconcile(ctx, req)
+	done(result, err)
+	return result, err
+}
+
+func (r *ScheduledTestReconciler) reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	baseLog := logf.FromContext(ctx)
+	// API 请求的 User-Agent 附带 ScheduledTest 对象，便于 API Server 端按测试归属负载
+	ctx = shared.WithRequestUserAgent(ctx, "ScheduledTest", req.NamespacedName)
+
+	var st infrav1alpha1.ScheduledTest
+	if err := r.Get(ctx, req.NamespacedName, &st); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	// 添加资源上下文到 logger
+	log := logging.WithKindName(baseLog, "ScheduledTest", st.Namespace, st.Name)
+	ctx = logf.IntoContext(ctx, log)
+
+	if !st.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, nil
+	}
+
+	res, err := r.reconcileNormal(ctx, &st)
+	if err != nil {
+		log.Error(err, "reconcile failed")
+	}
+	return res, err
+}
+
+// reconcileNormal 同步运行历史并清理超出保留数量的测试，到达触发时间时按 concurrencyPolicy 创建新的测试，
+// 最后安排在下一次触发时间调和。
+func (r *ScheduledTestReconciler) reconcileNormal(ctx context.Context, st *infrav1alpha1.ScheduledTest) (ctrl.Result, error) {
+	log := logf.FromContext(ctx)
+	original := st.Status.DeepCopy()
+	now := time.Now()
+	st.Status.ObservedGeneration = st.Generation
+
+	cron, loc, err := parseSchedule(st.Spec.Schedule, st.Spec.TimeZone)
+	if err != nil {
+		return r.markInvalid(ctx, st, original, ReasonInvalidSchedule, err)
+	}
+	if err := validateTemplate(st.Spec.Template); err != nil {
+		return r.markInvalid(ctx, st, original, ReasonInvalidTemplate, err)
+	}
+
+	// 1. 同步运行历史，删除超出保留数量的测试
+	runs, err := r.listRuns(ctx, st)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if runs, err = r.pruneHistory(ctx, st, runs); err != nil {
+		return ctrl.Result{}, err
+	}
+	r.syncRuns(st, runs)
+
+	// 2. 到达触发时间：按 concurrencyPolicy 创建测试
+	var events []runEvent
+	if st.Spec.Suspend {
+		st.Status.NextScheduleTime = nil
+		shared.SetCondition(&st.Status.Conditions, infrav1alpha1.ConditionReady, metav1.ConditionTrue,
+			ReasonSuspended, "scheduling is suspended by spec.suspend", st.Generation)
+	} else {
+		shared.SetCondition(&st.Status.Conditions, infrav1alpha1.ConditionReady, metav1.ConditionTrue,
+			ReasonScheduled, "scheduling runs by spec.schedule", st.Generation)
+		if scheduled, missed := dueSchedule(cron, st, now.In(loc)); !scheduled.IsZero() {
+			if missed > maxMissedSchedules {
+				at := scheduled.Format(time.RFC3339)
+				log.Info("too many missed schedules, running the most recent one", "limit", maxMissedSchedules, "scheduledTime", at)
+				events = append(events, runEvent{key: at, eventType: corev1.EventTypeWarning, reason: shared.EventReasonScheduledTestTooManyMissed,
+					message: fmt.Sprintf("错过超过 %d 次触发，只处理最近一次 %s 的触发", maxMissedSchedules, at)})
+			}
+			ev, err := r.runScheduled(ctx, st, runs, scheduled, missed, now)
+			if err != nil {
+				return ctrl.Result{}, err
+			}
+			events = append(events, ev...)
+		}
+		next := metav1.NewTime(cron.Next(now.In(loc)))
+		st.Status.NextScheduleTime = &next
+	}
+
+	// 先 patch，成功后再发 Event（事件带幂等键，重复调和不会重复记录）
+	if !equality.Semantic.DeepEqual(original, &st.Status) {
+		if err := shared.PatchScheduledTestStatus(ctx, r.Client, st.Name, st.Namespace, st.Status); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+	r.emitRunEvents(st, events)
+
+	// 运行结果由 Owns 监听推进；暂停时由 spec 变更触发，否则在下一次触发时间调和
+	if st.Status.NextScheduleTime == nil || st.Status.NextScheduleTime.IsZero() {
+		return ctrl.Result{}, nil
+	}
+	requeue := time.Until(st.Status.NextScheduleTime.Time)
+	if requeue < time.Second {
+		requeue = time.Second
+	}
+	logging.WaitingFor(log, "next schedule", "at", st.Status.NextScheduleTime.Time, "active", st.Status.Active)
+	return ctrl.Result{RequeueAfter: requeue}, nil
+}
+
+// runScheduled 处理到期的触发：超过 startingDeadlineSeconds 时记为错过；有运行中的测试时按 concurrencyPolicy
+// 跳过（不推进 lastScheduleTime，结束后补跑）、中止旧测试或直接创建。
+func (r *ScheduledTestReconciler) runScheduled(ctx context.Context, st *infrav1alpha1.ScheduledTest, runs []run, scheduled time.Time, missed int, now time.Time) ([]runEvent, error) {
+	log := logf.FromContext(ctx)
+	scheduledAt := metav1.NewTime(scheduled)
+	at := scheduled.Format(time.RFC3339)
+
+	// 该触发的测试已存在：上一次调和创建后 lastScheduleTime 尚未写入或缓存滞后
+	for _, rn := range runs {
+		if rn.obj.GetName() == runName(st, scheduled) {
+			st.Status.LastScheduleTime = &scheduledAt
+			return nil, nil
+		}
+	}
+
+	if d := st.Spec.StartingDeadlineSeconds; d != nil && now.Sub(scheduled) > time.Duration(*d)*time.Second {
+		st.Status.LastScheduleTime = &scheduledAt
+		log.Info("scheduled run missed its starting deadline", "scheduledTime", at, "missed", missed)
+		return []runEvent{{key: at, eventType: corev1.EventTypeWarning, reason: shared.EventReasonScheduledTestRunMissed,
+			message: fmt.Sprintf("错过 %s 的触发（超过 startingDeadlineSeconds=%d）", at, *d)}}, nil
+	}
+
+	var events []runEvent
+	if active := activeRuns(runs); len(active) > 0 {
+		switch concurrencyPolicy(st) {
+		case infrav1alpha1.ConcurrencyForbid:
+			logging.WaitingFor(log, "active run before scheduling", "scheduledTime", at, "active", len(active))
+			return []runEvent{{key: at, eventType: corev1.EventTypeNormal, reason: shared.EventReasonScheduledTestRunSkipped,
+				message: fmt.Sprintf("%s 的触发被跳过：%s 仍在运行（concurrencyPolicy=Forbid）", at, active[0].obj.GetName())}}, nil
+		case infrav1alpha1.ConcurrencyReplace:
+			for _, a := range active {
+				if err := shared.RequestAbort(ctx, r.Client, a.obj); err != nil {
+					return nil, err
+				}
+				events = append(events, runEvent{key: a.obj.GetName(), eventType: corev1.EventTypeNormal, reason: shared.EventReasonScheduledTestRunReplaced,
+					message: fmt.Sprintf("中止运行中的 %s，由 %s 的触发替代（concurrencyPolicy=Replace）", a.obj.GetName(), at)})
+			}
+		}
+	}
+
+	ev, err := r.createRun(ctx, st, scheduled)
+	if err != nil {
+		return nil, err
+	}
+	st.Status.LastScheduleTime = &scheduledAt
+	if missed > 1 {
+		log.Info("skipped earlier missed schedules", "count", missed-1, "scheduledTime", at)
+	}
+	return append(events, ev), nil
+}
+
+// markInvalid schedule 或 template 无效时把 Ready 置为 False 并停止调度，等待 spec 变更。
+func (r *ScheduledTestReconciler) markInvalid(ctx context.Context, st *infrav1alpha1.ScheduledTest, original *infrav1alpha1.ScheduledTestStatus, reason string, err error) (ctrl.Result, error) {
+	logf.FromContext(ctx).Info("invalid scheduled test", "reason", reason, "error", err.Error())
+	st.Status.NextScheduleTime = nil
+	shared.SetCondition(&st.Status.Conditions, infrav1alpha1.ConditionReady, metav1.ConditionFalse, reason, err.Error(), st.Generation)
+	if equality.Semantic.DeepEqual(original, &st.Status) {
+		return ctrl.Result{}, nil
+	}
+	if err := shared.PatchScheduledTestStatus(ctx, r.Client, st.Name, st.Namespace, st.Status); err != nil {
+		return ctrl.Result{}, err
+	}
+	shared.EmitKeyedWarningEvent(r.Recorder, st, shared.EventKey(st, int(st.Generation), "", reason),
+		shared.EventReasonScheduledTestInvalid, fmt.Sprintf("%s: %v", reason, err))
+	return ctrl.Result{}, nil
+}
+
+// runEvent 运行相关的事件，在状态持久化后发送。
+type runEvent struct {
+	key       string
+	eventType string
+	reason    string
+	message   string
+}
+
+// emitRunEvents 发送运行事件，幂等键按运行（或触发时间）与原因区分。
+func (r *ScheduledTestReconciler) emitRunEvents(st *infrav1alpha1.ScheduledTest, events []runEvent) {
+	for _, ev := range events {
+		shared.EmitKeyedEvent(r.Recorder, st, shared.EventKey(st, 0, ev.key, ev.reason), ev.eventType, ev.reason, ev.message)
+	}
+}
+
+// concurrencyPolicy 返回并发策略，未设置时为 Forbid。
+func concurrencyPolicy(st *infrav1alpha1.ScheduledTest) infrav1alpha1.ConcurrencyPolicy {
+	if st.Spec.ConcurrencyPolicy == "" {
+		return infrav1alpha1.ConcurrencyForbid
+	}
+	return st.Spec.ConcurrencyPolicy
+}
+
+// parseSchedule 解析 cron 表达式与时区，时区为空时使用 UTC。
+func parseSchedule(schedule, timeZone string) (*shared.CronSchedule, *time.Location, error) {
+	cron, err := shared.ParseCron(schedule)
+	if err != nil {
+		return nil, nil, err
+	}
+	loc := time.UTC
+	if timeZone != "" {
+		if loc, err = time.LoadLocation(timeZone); err != nil {
+			return nil, nil, fmt.Errorf("load time zone %q: %w", timeZone, err)
+		}
+	}
+	return cron, loc, nil
+}
+
+// maxMissedSchedules 一次调和逐个遍历的错过触发上限，超出时（控制器长时间停止、lastScheduleTime 很早等）
+// 不再逐个遍历，改为向 now 跳跃查找最近一次触发。
+const maxMissedSchedules = 100
+
+// dueSchedule 返回上一次处理的触发（lastScheduleTime，未设置时为创建时间）之后、不晚于 now 的最近一次触发时间，
+// 以及这期间的触发次数；没有到期的触发时返回零值。更早的触发不补跑。
+// 触发次数超过 maxMissedSchedules 时返回 maxMissedSchedules+1。
+func dueSchedule(cron *shared.CronSchedule, st *infrav1alpha1.ScheduledTest, now time.Time) (time.Time, int) {
+	since := st.CreationTimestamp.Time
+	if st.Status.LastScheduleTime != nil {
+		since = st.Status.LastScheduleTime.Time
+	}
+	first, latest, count := scanSchedules(cron, since.In(now.Location()), now)
+	if count <= maxMissedSchedules {
+		return latest, count
+	}
+	// 错过的触发过多：每轮从 now 之前、与上一轮遍历跨度相同的位置重新查找；跳过的区间内没有触发时
+	// 从上一轮遍历到的位置继续。轮数同样有上限，保证一次调和的遍历次数有界
+	jump := true
+	for round := 0; round < maxMissedSchedules; round++ {
+		from := latest
+		if span := latest.Sub(first); jump && now.Add(-span).After(from) {
+			from = now.Add(-span)
+		}
+		f, l, n := scanSchedules(cron, from, now)
+		if n == 0 {
+			if from.Equal(latest) {
+				break
+			}
+			jump = false
+			continue
+		}
+		first, latest, jump = f, l, true
+		if n <= maxMissedSchedules {
+			break
+		}
+	}
+	return latest, maxMissedSchedules + 1
+}
+
+// scanSchedules 从 from 之后逐个遍历不晚于 now 的触发，返回第一次与最后一次触发时间及次数，
+// 最多遍历 maxMissedSchedules+1 次。
+func scanSchedules(cron *shared.CronSchedule, from, now time.Time) (first, last time.Time, count int) {
+	for t := cron.Next(from); !t.IsZero() && !t.After(now) && count <= maxMissedSchedules; t = cron.Next(t) {
+		if count == 0 {
+			first = t
+		}
+		last = t
+		count++
+	}
+	return first, last, count
+}
+
+// SetupWithManager wires the controller.
+func (r *ScheduledTestReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if r.Recorder == nil {
+		r.Recorder = shared.NewManagerEventRecorder(mgr, "scheduledtest")
+	}
+	// ScheduledTest 只有 spec、标签、注解变化与删除触发调和（更新事件按 Debounce 合并）；
+	// 创建的测试的任何变化（包括 status）都触发所属 ScheduledTest 调和
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("scheduledtest").
+		Watches(&infrav1alpha1.ScheduledTest{},
+			shared.DebounceUpdates(&handler.EnqueueRequestForObject{}, r.Debounce),
+			builder.WithPredicates(shared.IgnoreSelfUpdates())).
+		Owns(&infrav1alpha1.IntegrationTest{}).
+		Owns(&infrav1alpha1.LoadTest{}).
+		Complete(r)
+}
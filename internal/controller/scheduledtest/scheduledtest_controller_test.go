@@ -0,0 +1,176 @@
+package scheduledtest
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	infrav1alpha1 "github.com/lunz1207/testplane/api/v1alpha1"
+	"github.com/lunz1207/testplane/internal/controller/shared"
+)
+
+var _ = Describe("ScheduledTest Controller", func() {
+	ctx := context.Background()
+
+	newScheduledTest := func(schedule string, created time.Time) *infrav1alpha1.ScheduledTest {
+		return &infrav1alpha1.ScheduledTest{
+			ObjectMeta: metav1.ObjectMeta{Name: "nightly", Namespace: "default", UID: "st-uid", CreationTimestamp: metav1.NewTime(created)},
+			Spec: infrav1alpha1.ScheduledTestSpec{
+				Schedule: schedule,
+				Template: infrav1alpha1.ScheduledTestTemplate{
+					Kind: infrav1alpha1.TestSuiteItemIntegrationTest,
+					Spec: &runtime.RawExtension{Raw: []byte(`{"steps":[{"name":"check"}]}`)},
+				},
+			},
+		}
+	}
+
+	Context("When computing the due schedule", func() {
+		now := time.Date(2025, 6, 1, 10, 30, 0, 0, time.UTC)
+
+		due := func(schedule string, since time.Time) (time.Time, int) {
+			cron, err := shared.ParseCron(schedule)
+			Expect(err).NotTo(HaveOccurred())
+			return dueSchedule(cron, newScheduledTest(schedule, since), now)
+		}
+
+		It("should return the most recent trigger and the number of triggers since the last one", func() {
+			latest, count := due("*/10 * * * *", now.Add(-time.Hour))
+			Expect(latest).To(Equal(now))
+			Expect(count).To(Equal(6))
+
+			latest, count = due("0 * * * *", now.Add(-10*time.Minute))
+			Expect(latest.IsZero()).To(BeTrue())
+			Expect(count).To(BeZero())
+		})
+
+		It("should cap the number of missed triggers and still find the most recent one", func() {
+			latest, count := due("* * * * *", now.AddDate(0, -1, 0))
+			Expect(latest).To(Equal(now))
+			Expect(count).To(Equal(maxMissedSchedules + 1))
+
+			// 触发集中在过去的一段时间：跳跃查找落空后从已遍历的位置继续
+			latest, count = due("* * 1 1 *", time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+			Expect(latest).To(Equal(time.Date(2025, 1, 1, 23, 59, 0, 0, time.UTC)))
+			Expect(count).To(Equal(maxMissedSchedules + 1))
+		})
+	})
+
+	Context("When a schedule is due", func() {
+		var (
+			c        client.Client
+			r        *ScheduledTestReconciler
+			recorder *record.FakeRecorder
+		)
+
+		setup := func(objs ...client.Object) {
+			scheme := runtime.NewScheme()
+			Expect(infrav1alpha1.AddToScheme(scheme)).To(Succeed())
+			// status SSA 不被 fake client 支持：状态在内存中推进
+			c = fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).WithInterceptorFuncs(interceptor.Funcs{
+				SubResourcePatch: func(context.Context, client.Client, string, client.Object, client.Patch, ...client.SubResourcePatchOption) error {
+					return nil
+				},
+			}).Build()
+			recorder = record.NewFakeRecorder(100)
+			r = &ScheduledTestReconciler{Client: c, Scheme: scheme, Recorder: recorder}
+		}
+
+		activeRun := func(st *infrav1alpha1.ScheduledTest, name string) *infrav1alpha1.IntegrationTest {
+			it := &infrav1alpha1.IntegrationTest{ObjectMeta: metav1.ObjectMeta{
+				Name: name, Namespace: "default", Labels: map[string]string{infrav1alpha1.LabelScheduledTest: st.Name},
+			}}
+			it.Status.Phase = infrav1alpha1.IntegrationTestPhaseRunning
+			Expect(controllerutil.SetControllerReference(st, it, r.Scheme)).To(Succeed())
+			Expect(c.Create(ctx, it)).To(Succeed())
+			return it
+		}
+
+		testNames := func() []string {
+			var list infrav1alpha1.IntegrationTestList
+			Expect(c.List(ctx, &list)).To(Succeed())
+			names := []string{}
+			for _, it := range list.Items {
+				names = append(names, it.Name)
+			}
+			return names
+		}
+
+		events := func() string {
+			var out []string
+			for len(recorder.Events) > 0 {
+				out = append(out, <-recorder.Events)
+			}
+			return strings.Join(out, "\n")
+		}
+
+		It("should create a run owned by the scheduled test", func() {
+			st := newScheduledTest("* * * * *", time.Now().Add(-5*time.Minute))
+			setup(st)
+			result, err := r.reconcileNormal(ctx, st)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(BeNumerically(">", 0))
+
+			scheduled := st.Status.LastScheduleTime.Time
+			Expect(testNames()).To(ConsistOf(runName(st, scheduled)))
+			var it infrav1alpha1.IntegrationTest
+			Expect(c.Get(ctx, client.ObjectKey{Namespace: "default", Name: runName(st, scheduled)}, &it)).To(Succeed())
+			Expect(metav1.IsControlledBy(&it, st)).To(BeTrue())
+			Expect(it.Annotations).To(HaveKeyWithValue(infrav1alpha1.AnnotationScheduledTime, scheduled.Format(time.RFC3339)))
+			Expect(st.Status.NextScheduleTime.After(scheduled)).To(BeTrue())
+			Expect(events()).To(ContainSubstring(shared.EventReasonScheduledTestRunCreated))
+		})
+
+		It("should skip the trigger while a run is active with the Forbid policy", func() {
+			st := newScheduledTest("* * * * *", time.Now().Add(-5*time.Minute))
+			setup(st)
+			activeRun(st, "nightly-previous")
+
+			_, err := r.reconcileNormal(ctx, st)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(testNames()).To(ConsistOf("nightly-previous"))
+			Expect(st.Status.LastScheduleTime).To(BeNil())
+			Expect(st.Status.Active).To(Equal(int32(1)))
+			Expect(events()).To(ContainSubstring(shared.EventReasonScheduledTestRunSkipped))
+		})
+
+		It("should abort the active run and create a new one with the Replace policy", func() {
+			st := newScheduledTest("* * * * *", time.Now().Add(-5*time.Minute))
+			st.Spec.ConcurrencyPolicy = infrav1alpha1.ConcurrencyReplace
+			setup(st)
+			activeRun(st, "nightly-previous")
+
+			_, err := r.reconcileNormal(ctx, st)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(testNames()).To(ConsistOf("nightly-previous", runName(st, st.Status.LastScheduleTime.Time)))
+
+			var previous infrav1alpha1.IntegrationTest
+			Expect(c.Get(ctx, client.ObjectKey{Namespace: "default", Name: "nightly-previous"}, &previous)).To(Succeed())
+			Expect(previous.Annotations).To(HaveKeyWithValue(infrav1alpha1.AnnotationAbort, "true"))
+			Expect(events()).To(And(
+				ContainSubstring(shared.EventReasonScheduledTestRunReplaced),
+				ContainSubstring(shared.EventReasonScheduledTestRunCreated)))
+		})
+
+		It("should warn when too many triggers were missed", func() {
+			st := newScheduledTest("* * * * *", time.Now().AddDate(0, 0, -1))
+			setup(st)
+
+			_, err := r.reconcileNormal(ctx, st)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(testNames()).To(HaveLen(1))
+			Expect(time.Since(st.Status.LastScheduleTime.Time)).To(BeNumerically("<", 2*time.Minute))
+			Expect(events()).To(ContainSubstring(shared.EventReasonScheduledTestTooManyMissed))
+		})
+	})
+})
@@ -0,0 +1,79 @@
+package shared
+
+import (
+	"context"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// APIRemoval 确认资源类型是否已从集群中移除，并拆除该类型的缓存 informer。
+// CRD 卸载后 informer 的 list/watch 会持续失败重试，依赖该类型的测试也只会等待到超时。
+type APIRemoval struct {
+	// Discovery 查询 API Server 当前提供的资源类型。
+	Discovery discovery.DiscoveryInterface
+	// Informers 缓存，确认移除后从中拆除该 GVK 的 informer。
+	Informers cache.Informers
+	// Mapper RESTMapper，实现 meta.ResettableRESTMapper 时确认移除后重置，丢弃过期的映射。
+	Mapper meta.RESTMapper
+}
+
+// NewAPIRemoval 使用 manager 的缓存与 RESTMapper 创建 APIRemoval。
+func NewAPIRemoval(mgr ctrl.Manager) (*APIRemoval, error) {
+	dc, err := discovery.NewDiscoveryClientForConfig(mgr.GetConfig())
+	if err != nil {
+		return nil, fmt.Errorf("create discovery client: %w", err)
+	}
+	return &APIRemoval{Discovery: dc, Informers: mgr.GetCache(), Mapper: mgr.GetRESTMapper()}, nil
+}
+
+// Removed 在 err 表明资源类型可能不存在（NoKindMatch 或 NotFound）时通过 discovery 确认 gvk 是否仍被提供。
+// 确认已移除时拆除该 GVK 的 informer（完整对象与元数据各一个）、重置 RESTMapper 并返回 true。
+// 对象本身不存在时 discovery 中仍有该类型，返回 false；discovery 读取失败时保守返回 false。
+func (a *APIRemoval) Removed(ctx context.Context, gvk schema.GroupVersionKind, err error) bool {
+	if a == nil || err == nil || gvk.Kind == "" {
+		return false
+	}
+	if !meta.IsNoMatchError(err) && !apierrors.IsNotFound(err) {
+		return false
+	}
+
+	resources, derr := a.Discovery.ServerResourcesForGroupVersion(gvk.GroupVersion().String())
+	if derr != nil && !apierrors.IsNotFound(derr) {
+		return false
+	}
+	if derr == nil {
+		for _, r := range resources.APIResources {
+			if r.Kind == gvk.Kind {
+				return false
+			}
+		}
+	}
+
+	log := logf.FromContext(ctx)
+	if a.Informers != nil {
+		full := &unstructured.Unstructured{}
+		full.SetGroupVersionKind(gvk)
+		partial := &metav1.PartialObjectMetadata{}
+		partial.SetGroupVersionKind(gvk)
+		for _, obj := range []client.Object{full, partial} {
+			if rerr := a.Informers.RemoveInformer(ctx, obj); rerr != nil {
+				log.Info("remove informer failed", "gvk", gvk.String(), "error", rerr.Error())
+			}
+		}
+	}
+	if m, ok := a.Mapper.(meta.ResettableRESTMapper); ok {
+		m.Reset()
+	}
+	log.Info("API removed, informer torn down", "gvk", gvk.String())
+	return true
+}
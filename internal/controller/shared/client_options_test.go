@@ -0,0 +1,99 @@
+package shared
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	ctrl "sigs.k8s.io/controller-runtime"
+	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
+)
+
+var _ = Describe("Controller clients", func() {
+	base := &rest.Config{Host: "https://example.invalid", QPS: 20, Burst: 30, UserAgent: "manager"}
+
+	DescribeTable("should override QPS and Burst only when set",
+		func(opts ClientOptions, qps float32, burst int) {
+			cfg := ControllerRESTConfig(base, "loadtest", opts)
+			Expect(cfg.QPS).To(Equal(qps))
+			Expect(cfg.Burst).To(Equal(burst))
+			Expect(cfg.UserAgent).To(Equal("testplane-loadtest"))
+			// 不修改 manager 的配置
+			Expect(base.QPS).To(Equal(float32(20)))
+			Expect(base.Burst).To(Equal(30))
+			Expect(base.UserAgent).To(Equal("manager"))
+		},
+		Entry("unset", ClientOptions{}, float32(20), 30),
+		Entry("QPS only", ClientOptions{QPS: 100}, float32(100), 30),
+		Entry("Burst only", ClientOptions{Burst: 200}, float32(20), 200),
+		Entry("both", ClientOptions{QPS: 5, Burst: 10}, float32(5), 10),
+		Entry("non-positive values", ClientOptions{QPS: -1, Burst: 0}, float32(20), 30),
+	)
+
+	It("should send the controller and test in the User-Agent", func() {
+		var (
+			mu     sync.Mutex
+			agents = map[string][]string{}
+		)
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			if !strings.HasPrefix(r.URL.Path, "/api/v1/namespaces/") {
+				// 发现请求：只声明 v1 ConfigMap
+				switch r.URL.Path {
+				case "/api":
+					_ = json.NewEncoder(w).Encode(&metav1.APIVersions{Versions: []string{"v1"}})
+				case "/api/v1":
+					_ = json.NewEncoder(w).Encode(&metav1.APIResourceList{GroupVersion: "v1", APIResources: []metav1.APIResource{
+						{Name: "configmaps", Namespaced: true, Kind: "ConfigMap", Verbs: metav1.Verbs{"get", "create"}},
+					}})
+				default:
+					_ = json.NewEncoder(w).Encode(&metav1.APIGroupList{})
+				}
+				return
+			}
+			mu.Lock()
+			agents[r.Method] = append(agents[r.Method], r.Header.Get("User-Agent"))
+			mu.Unlock()
+			cm := &corev1.ConfigMap{
+				TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "ConfigMap"},
+				ObjectMeta: metav1.ObjectMeta{Namespace: "perf", Name: "settings"},
+			}
+			if r.Method == http.MethodPost {
+				w.WriteHeader(http.StatusCreated)
+			}
+			_ = json.NewEncoder(w).Encode(cm)
+		}))
+		defer server.Close()
+
+		mgr, err := ctrl.NewManager(&rest.Config{Host: server.URL}, ctrl.Options{
+			Scheme:                 scheme.Scheme,
+			Metrics:                metricsserver.Options{BindAddress: "0"},
+			HealthProbeBindAddress: "0",
+		})
+		Expect(err).NotTo(HaveOccurred())
+		c, reader, err := NewControllerClients(mgr, "loadtest", ClientOptions{QPS: 50, Burst: 100})
+		Expect(err).NotTo(HaveOccurred())
+
+		key := types.NamespacedName{Namespace: "perf", Name: "settings"}
+		ctx := WithRequestUserAgent(context.Background(), "LoadTest", types.NamespacedName{Namespace: "perf", Name: "soak"})
+		Expect(reader.Get(ctx, key, &corev1.ConfigMap{})).To(Succeed())
+		Expect(c.Create(ctx, &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "perf", Name: "settings"}})).To(Succeed())
+		// 没有测试对象的 ctx 只带控制器名称
+		Expect(reader.Get(context.Background(), key, &corev1.ConfigMap{})).To(Succeed())
+
+		mu.Lock()
+		defer mu.Unlock()
+		Expect(agents[http.MethodGet]).To(Equal([]string{"testplane-loadtest LoadTest/perf/soak", "testplane-loadtest"}))
+		Expect(agents[http.MethodPost]).To(Equal([]string{"testplane-loadtest LoadTest/perf/soak"}))
+	})
+})
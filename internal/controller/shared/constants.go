@@ -15,6 +15,7 @@ const (
 	StateFailed    = "Failed"
 	StatePassed    = "Passed"
 	StatePending   = "Pending"
+	StateAborted   = "Aborted"
 )
 
 // 原因常量
@@ -22,4 +23,16 @@ const (
 	ReasonSucceeded = "Succeeded"
 	ReasonFailed    = "Failed"
 	ReasonTimeout   = "Timeout"
+	// ReasonBudgetExceeded 资源预算超出（IntegrationTest 预算注解）。
+	ReasonBudgetExceeded = "BudgetExceeded"
+	// ReasonAPIRemoved 测试使用的资源类型已从集群中移除（如 CRD 被卸载）。
+	ReasonAPIRemoved = "APIRemoved"
+	// ReasonForbidden 资源写入被 RBAC 拒绝。
+	ReasonForbidden = "Forbidden"
+	// ReasonInvalid 资源清单不符合 schema 或字段校验。
+	ReasonInvalid = "Invalid"
+	// ReasonWebhookDenied 资源写入被准入 Webhook 拒绝。
+	ReasonWebhookDenied = "WebhookDenied"
+	// ReasonConflict 资源写入持续冲突直到步骤超时。
+	ReasonConflict = "Conflict"
 )
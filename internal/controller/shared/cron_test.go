@@ -0,0 +1,42 @@
+package shared
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Cron", func() {
+	// 2025-01-01 为周三
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	DescribeTable("should compute the next schedule time",
+		func(expr string, expected time.Time) {
+			cron, err := ParseCron(expr)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cron.Next(from)).To(Equal(expected))
+		},
+		Entry("every minute", "* * * * *", time.Date(2025, 1, 1, 0, 1, 0, 0, time.UTC)),
+		Entry("daily at a fixed time", "30 9 * * *", time.Date(2025, 1, 1, 9, 30, 0, 0, time.UTC)),
+		Entry("sunday as 0", "0 0 * * 0", time.Date(2025, 1, 5, 0, 0, 0, 0, time.UTC)),
+		Entry("sunday as 7", "0 0 * * 7", time.Date(2025, 1, 5, 0, 0, 0, 0, time.UTC)),
+		Entry("range ending on sunday", "0 0 * * 6-7", time.Date(2025, 1, 4, 0, 0, 0, 0, time.UTC)),
+		Entry("day of month or day of week", "0 0 1 * 1", time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC)),
+		Entry("stepped day of month with day of week", "0 0 */2 * 1", time.Date(2025, 1, 13, 0, 0, 0, 0, time.UTC)),
+		Entry("day of month with stepped day of week", "0 0 10 * */3", time.Date(2025, 5, 10, 0, 0, 0, 0, time.UTC)),
+		Entry("start with step", "0 0 20/5 1 *", time.Date(2025, 1, 20, 0, 0, 0, 0, time.UTC)),
+	)
+
+	DescribeTable("should reject malformed expressions",
+		func(expr string) {
+			_, err := ParseCron(expr)
+			Expect(err).To(HaveOccurred())
+		},
+		Entry("too few fields", "* * *"),
+		Entry("minute out of range", "60 * * * *"),
+		Entry("zero step", "*/0 * * * *"),
+		Entry("reversed range", "5-1 * * * *"),
+		Entry("day of week out of range", "0 0 * * 8"),
+	)
+})
@@ -0,0 +1,118 @@
+package shared
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	infrav1alpha1 "github.com/lunz1207/testplane/api/v1alpha1"
+)
+
+// environmentKey context 中 Environment 的键。
+type environmentKey struct{}
+
+// environmentRefPattern 匹配 ${env.<name>}、${env.baseURLs.<name>} 与 ${env.namespace}。
+var environmentRefPattern = regexp.MustCompile(`\$\{env\.([A-Za-z0-9_.\-]+)\}`)
+
+// baseURLsPrefix 服务地址引用前缀。
+const baseURLsPrefix = "baseURLs."
+
+// ResolveEnvironment 读取 environmentRef 引用的 Environment（与测试同命名空间），未引用时返回 nil。
+func ResolveEnvironment(ctx context.Context, reader client.Reader, namespace string, ref *infrav1alpha1.EnvironmentReference) (*infrav1alpha1.Environment, error) {
+	if ref == nil || ref.Name == "" {
+		return nil, nil
+	}
+	var env infrav1alpha1.Environment
+	if err := reader.Get(ctx, client.ObjectKey{Namespace: namespace, Name: ref.Name}, &env); err != nil {
+		return nil, fmt.Errorf("get environment %s: %w", ref.Name, err)
+	}
+	return &env, nil
+}
+
+// ExpandEnvironment 将 spec 中的 ${env.*} 引用替换为 Environment 的值，只修改内存中的对象。
+// env 为 nil 时不做任何处理；引用未定义的值时返回错误，spec 保持不变。
+func ExpandEnvironment[T any](env *infrav1alpha1.Environment, spec *T) error {
+	if env == nil {
+		return nil
+	}
+	raw, err := json.Marshal(spec)
+	if err != nil {
+		return fmt.Errorf("marshal spec: %w", err)
+	}
+
+	missing := map[string]bool{}
+	expanded := environmentRefPattern.ReplaceAllFunc(raw, func(match []byte) []byte {
+		name := string(environmentRefPattern.FindSubmatch(match)[1])
+		value, ok := environmentValue(env, name)
+		if !ok {
+			missing[name] = true
+			return match
+		}
+		// 引用位于 JSON 字符串内，按 JSON 转义后去掉引号
+		quoted, _ := json.Marshal(value)
+		return quoted[1 : len(quoted)-1]
+	})
+	if len(missing) > 0 {
+		names := make([]string, 0, len(missing))
+		for name := range missing {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return fmt.Errorf("undefined environment references in %s: %s", env.Name, strings.Join(names, ", "))
+	}
+
+	var out T
+	if err := json.Unmarshal(expanded, &out); err != nil {
+		return fmt.Errorf("unmarshal expanded spec: %w", err)
+	}
+	*spec = out
+	return nil
+}
+
+// environmentValue 返回引用名对应的值。
+func environmentValue(env *infrav1alpha1.Environment, name string) (string, bool) {
+	if name == "namespace" {
+		return EnvironmentNamespace(env, env.Namespace), true
+	}
+	if key, ok := strings.CutPrefix(name, baseURLsPrefix); ok {
+		value, found := env.Spec.BaseURLs[key]
+		return value, found
+	}
+	value, found := env.Spec.Variables[name]
+	return value, found
+}
+
+// EnvironmentNamespace 返回 Environment 的默认命名空间，未设置（或 env 为 nil）时返回 fallback。
+func EnvironmentNamespace(env *infrav1alpha1.Environment, fallback string) string {
+	if env != nil && env.Spec.DefaultNamespace != "" {
+		return env.Spec.DefaultNamespace
+	}
+	return fallback
+}
+
+// ContextWithEnvironment 将解析后的 Environment 放入 context（env 为 nil 时原样返回）。
+func ContextWithEnvironment(ctx context.Context, env *infrav1alpha1.Environment) context.Context {
+	if env == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, environmentKey{}, env)
+}
+
+// EnvironmentFromContext 返回 context 中的 Environment（可能为 nil）。
+func EnvironmentFromContext(ctx context.Context) *infrav1alpha1.Environment {
+	env, _ := ctx.Value(environmentKey{}).(*infrav1alpha1.Environment)
+	return env
+}
+
+// EnvironmentBaseURL 返回 context 中 Environment 的服务地址，未设置时返回空字符串。
+func EnvironmentBaseURL(ctx context.Context, name string) string {
+	if env := EnvironmentFromContext(ctx); env != nil {
+		return env.Spec.BaseURLs[name]
+	}
+	return ""
+}
@@ -0,0 +1,345 @@
+package shared
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"sync/atomic"
+	"time"
+	"unicode/utf8"
+
+	corev1 "k8s.io/api/core/v1"
+	eventsv1 "k8s.io/api/events/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/tools/reference"
+	"k8s.io/client-go/util/retry"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	infrav1alpha1 "github.com/lunz1207/testplane/api/v1alpha1"
+	"github.com/lunz1207/testplane/internal/controller/shared/logging"
+)
+
+// AnnotationEventIdempotencyKey Event 上的幂等键注解，下游系统可据此对通知去重。
+const AnnotationEventIdempotencyKey = "infra.testplane.io/idempotency-key"
+
+// AnnotationEventSeriesKey Event 上的系列键注解：系列键相同的事件合并为一个 Event，
+// 以 EventSeries 计数（kubectl get events 显示为 "x47 over 10m"）。
+const AnnotationEventSeriesKey = "infra.testplane.io/series-key"
+
+// AnnotationEventRecentKeys 系列 Event 上最近计入的幂等键（JSON 数组，最多 maxSeriesRecentKeys 个），
+// 交替出现的幂等键（如并发调和分别重试两次检查）也能识别为重复。
+const AnnotationEventRecentKeys = "infra.testplane.io/recent-keys"
+
+// maxSeriesRecentKeys 系列 Event 上保留的最近幂等键数量。
+const maxSeriesRecentKeys = 16
+
+// 步骤事件上的结果注解，供事件驱动的自动化（如 argo-events）直接读取测试进展，无需轮询 status。
+const (
+	// AnnotationEventRound 事件发生时的轮次。
+	AnnotationEventRound = "infra.testplane.io/round"
+	// AnnotationEventStep 步骤名称。
+	AnnotationEventStep = "infra.testplane.io/step"
+	// AnnotationEventOutputs 步骤输出（JSON 对象，如 {"kind":"Deployment","name":"web"}）。
+	AnnotationEventOutputs = "infra.testplane.io/outputs"
+	// AnnotationEventExpectationsPassed 通过的期望数。
+	AnnotationEventExpectationsPassed = "infra.testplane.io/expectations-passed"
+	// AnnotationEventExpectationsTotal 期望总数。
+	AnnotationEventExpectationsTotal = "infra.testplane.io/expectations-total"
+)
+
+// defaultEventWriteTimeout 写入一个事件（创建或计入系列）的默认时限，事件写入在调和中同步进行，不应长时间阻塞调和。
+const defaultEventWriteTimeout = 5 * time.Second
+
+// events.k8s.io/v1 Event 字段长度上限。
+const (
+	maxEventNoteBytes       = 1024
+	maxReportingInstanceLen = 128
+)
+
+// EventKey 生成事件幂等键：对象 UID + 轮次 + 步骤 + 状态转换。
+// 同一次状态转换无论 reconcile 多少次、控制器是否重启，生成的键都相同。
+// 不属于具体步骤的事件 step 传空。对象带重跑注解时 UID 后附加重跑令牌，重跑的事件不会被当作重复。
+func EventKey(obj client.Object, round int, step, transition string) string {
+	if step == "" {
+		step = "-"
+	}
+	return fmt.Sprintf("%s/%d/%s/%s", eventObjectID(obj), round, step, transition)
+}
+
+// eventObjectID 返回幂等键中的对象标识：UID[@重跑令牌][~断点恢复令牌]。
+// 断点恢复后重试的步骤重新产生同一轮次的事件，恢复令牌使其不被当作重复丢弃。
+func eventObjectID(obj client.Object) string {
+	id := string(obj.GetUID())
+	if token := obj.GetAnnotations()[infrav1alpha1.AnnotationRerun]; token != "" {
+		id += "@" + token
+	}
+	if token := obj.GetAnnotations()[infrav1alpha1.AnnotationResume]; token != "" {
+		id += "~" + token
+	}
+	return id
+}
+
+// EventSeriesKey 生成事件系列键：对象 UID + 步骤 + 状态转换，不含轮次。
+// 重复测试中每轮（或每次检查）相同的状态转换归入同一系列。
+func EventSeriesKey(obj client.Object, step, transition string) string {
+	if step == "" {
+		step = "-"
+	}
+	return fmt.Sprintf("%s/%s/%s", obj.GetUID(), step, transition)
+}
+
+// annotatedEventRecorder 支持附带注解的事件记录器（record.EventRecorder 满足该接口）。
+type annotatedEventRecorder interface {
+	AnnotatedEventf(object runtime.Object, annotations map[string]string, eventtype, reason, messageFmt string, args ...interface{})
+}
+
+// EmitKeyedEvent 发送带幂等键的 Kubernetes Event。
+// 记录器不支持注解时退化为普通事件。
+func EmitKeyedEvent(recorder EventRecorder, obj runtime.Object, key, eventType, reason, message string) {
+	EmitSeriesEvent(recorder, obj, key, "", eventType, reason, message)
+}
+
+// EmitSeriesEvent 发送带幂等键和系列键的 Kubernetes Event，seriesKey 为空时不合并。
+// 记录器不支持注解时退化为普通事件。
+func EmitSeriesEvent(recorder EventRecorder, obj runtime.Object, key, seriesKey, eventType, reason, message string) {
+	EmitAnnotatedSeriesEvent(recorder, obj, key, seriesKey, eventType, reason, message, nil)
+}
+
+// EmitAnnotatedSeriesEvent 同 EmitSeriesEvent，并在 Event 上附加 extra 注解（如步骤结果）。
+func EmitAnnotatedSeriesEvent(recorder EventRecorder, obj runtime.Object, key, seriesKey, eventType, reason, message string, extra map[string]string) {
+	if recorder == nil || obj == nil {
+		return
+	}
+	annotated, ok := recorder.(annotatedEventRecorder)
+	if !ok || key == "" {
+		recorder.Event(obj, eventType, reason, message)
+		return
+	}
+	annotations := map[string]string{AnnotationEventIdempotencyKey: key}
+	for k, v := range extra {
+		annotations[k] = v
+	}
+	if seriesKey != "" {
+		annotations[AnnotationEventSeriesKey] = seriesKey
+	}
+	annotated.AnnotatedEventf(obj, annotations, eventType, reason, "%s", message)
+}
+
+// EmitKeyedNormalEvent 发送带幂等键的 Normal 类型事件
+func EmitKeyedNormalEvent(recorder EventRecorder, obj runtime.Object, key, reason, message string) {
+	EmitKeyedEvent(recorder, obj, key, corev1.EventTypeNormal, reason, message)
+}
+
+// EmitKeyedWarningEvent 发送带幂等键的 Warning 类型事件
+func EmitKeyedWarningEvent(recorder EventRecorder, obj runtime.Object, key, reason, message string) {
+	EmitKeyedEvent(recorder, obj, key, corev1.EventTypeWarning, reason, message)
+}
+
+// IdempotentEventRecorder 以 events.k8s.io/v1 记录事件，按幂等键在服务端去重、按系列键合并。
+//
+// 带幂等键的事件以键的哈希作为 Event 名称直接创建，同一键的第二次创建被 API Server
+// 以 AlreadyExists 拒绝，因此控制器重启、patch 重试竞争都不会产生重复事件。
+// 带系列键的事件以系列键的哈希命名：首次创建，之后读取已有 Event，幂等键不在最近计入的键中时
+// 以 patch 累加 series.count（note、type 创建后不可修改，保留首次的内容），否则视为重复。
+// 不带幂等键的事件交给底层记录器，保持原有的聚合行为。
+type IdempotentEventRecorder struct {
+	record.EventRecorder
+	Client client.Client
+	// Reader 读取已有事件系列，应绕过缓存，避免为 Event 建立全集群 informer。
+	Reader    client.Reader
+	Scheme    *runtime.Scheme
+	Component string
+	// Instance 上报实例（ReportingInstance），默认 <component>-<hostname>。
+	Instance string
+	// Timeout 写入一个事件的时限，默认 5 秒；超时后退化为底层记录器（异步发送）。
+	Timeout time.Duration
+
+	// base 事件写入的父 context，由 manager 启动时设置，manager 停止时取消进行中的写入。
+	base atomic.Pointer[context.Context]
+}
+
+// NewIdempotentEventRecorder 包装底层事件记录器。
+func NewIdempotentEventRecorder(base record.EventRecorder, c client.Client, reader client.Reader, scheme *runtime.Scheme, component string) *IdempotentEventRecorder {
+	hostname, _ := os.Hostname()
+	instance := fmt.Sprintf("%s-%s", component, hostname)
+	if len(instance) > maxReportingInstanceLen {
+		instance = instance[:maxReportingInstanceLen]
+	}
+	return &IdempotentEventRecorder{
+		EventRecorder: base,
+		Client:        c,
+		Reader:        reader,
+		Scheme:        scheme,
+		Component:     component,
+		Instance:      instance,
+	}
+}
+
+// NewManagerEventRecorder 为控制器创建按幂等键去重的事件记录器，事件写入随 manager 停止取消。
+func NewManagerEventRecorder(mgr ctrl.Manager, name string) *IdempotentEventRecorder {
+	r := NewIdempotentEventRecorder(mgr.GetEventRecorderFor(name), mgr.GetClient(), mgr.GetAPIReader(), mgr.GetScheme(), name)
+	if err := mgr.Add(manager.RunnableFunc(func(ctx context.Context) error {
+		r.SetContext(ctx)
+		<-ctx.Done()
+		return nil
+	})); err != nil {
+		logf.Log.WithName("events").Info("register event recorder context failed", "component", name, "error", err.Error())
+	}
+	return r
+}
+
+// SetContext 设置事件写入的父 context，取消后进行中与之后的写入立即结束（退化为底层记录器）。
+func (r *IdempotentEventRecorder) SetContext(ctx context.Context) {
+	r.base.Store(&ctx)
+}
+
+// writeContext 返回一次事件写入使用的 context：父 context（未设置时为 Background）加写入时限。
+func (r *IdempotentEventRecorder) writeContext() (context.Context, context.CancelFunc) {
+	parent := context.Background()
+	if ctx := r.base.Load(); ctx != nil {
+		parent = *ctx
+	}
+	timeout := r.Timeout
+	if timeout <= 0 {
+		timeout = defaultEventWriteTimeout
+	}
+	return context.WithTimeout(parent, timeout)
+}
+
+// AnnotatedEventf 带幂等键时创建确定性名称的 Event，否则交给底层记录器。
+func (r *IdempotentEventRecorder) AnnotatedEventf(object runtime.Object, annotations map[string]string, eventtype, reason, messageFmt string, args ...interface{}) {
+	key := annotations[AnnotationEventIdempotencyKey]
+	if key == "" {
+		r.EventRecorder.AnnotatedEventf(object, annotations, eventtype, reason, messageFmt, args...)
+		return
+	}
+
+	log := logf.Log.WithName("events")
+	message := fmt.Sprintf(messageFmt, args...)
+	event, err := r.newEvent(object, annotations, eventtype, reason, message)
+	if err == nil {
+		ctx, cancel := r.writeContext()
+		defer cancel()
+		err = r.Client.Create(ctx, event)
+		if apierrors.IsAlreadyExists(err) && annotations[AnnotationEventSeriesKey] != "" {
+			err = r.appendToSeries(ctx, event)
+		}
+	}
+	switch {
+	case err == nil:
+	case apierrors.IsAlreadyExists(err):
+		log.V(logging.LevelVerbose).Info("suppress duplicate event", "reason", reason, "idempotencyKey", key)
+	default:
+		// 创建失败（如 RBAC 不足、集群不支持 events.k8s.io/v1）时退化为普通事件，保证事件不丢失
+		log.Info("create idempotent event failed, fallback to recorder", "error", err.Error())
+		r.EventRecorder.AnnotatedEventf(object, annotations, eventtype, reason, messageFmt, args...)
+	}
+}
+
+// appendToSeries 将一次新发生的事件计入已有 Event 的系列。
+// 本次幂等键已在最近计入的键中时返回 AlreadyExists（重复事件）。
+// 只 patch series 与注解：events.k8s.io/v1 Event 的 note、type 创建后不可修改。
+func (r *IdempotentEventRecorder) appendToSeries(ctx context.Context, event *eventsv1.Event) error {
+	key := event.Annotations[AnnotationEventIdempotencyKey]
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		var existing eventsv1.Event
+		if err := r.Reader.Get(ctx, client.ObjectKeyFromObject(event), &existing); err != nil {
+			return err
+		}
+		keys := seriesRecentKeys(&existing)
+		if slices.Contains(keys, key) {
+			return apierrors.NewAlreadyExists(eventsv1.Resource("events"), event.Name)
+		}
+
+		base := existing.DeepCopy()
+		count := int32(2)
+		if existing.Series != nil {
+			count = existing.Series.Count + 1
+		}
+		existing.Series = &eventsv1.EventSeries{Count: count, LastObservedTime: metav1.NowMicro()}
+		if existing.Annotations == nil {
+			existing.Annotations = map[string]string{}
+		}
+		// 注解（幂等键与结果注解）更新为最近一次
+		for k, v := range event.Annotations {
+			existing.Annotations[k] = v
+		}
+		existing.Annotations[AnnotationEventRecentKeys] = encodeRecentKeys(append(keys, key))
+		return r.Client.Patch(ctx, &existing, client.MergeFromWithOptions(base, client.MergeFromWithOptimisticLock{}))
+	})
+}
+
+// seriesRecentKeys 返回系列 Event 最近计入的幂等键；早于该注解创建的 Event 只有最近一次的幂等键。
+func seriesRecentKeys(event *eventsv1.Event) []string {
+	var keys []string
+	if raw := event.Annotations[AnnotationEventRecentKeys]; raw != "" && json.Unmarshal([]byte(raw), &keys) == nil {
+		return keys
+	}
+	if key := event.Annotations[AnnotationEventIdempotencyKey]; key != "" {
+		return []string{key}
+	}
+	return nil
+}
+
+// encodeRecentKeys 编码最近的幂等键，只保留最后 maxSeriesRecentKeys 个。
+func encodeRecentKeys(keys []string) string {
+	if len(keys) > maxSeriesRecentKeys {
+		keys = keys[len(keys)-maxSeriesRecentKeys:]
+	}
+	data, _ := json.Marshal(keys)
+	return string(data)
+}
+
+// newEvent 构造 events.k8s.io/v1 Event：带系列键时以系列键哈希命名，否则以幂等键哈希命名。
+func (r *IdempotentEventRecorder) newEvent(object runtime.Object, annotations map[string]string, eventtype, reason, message string) (*eventsv1.Event, error) {
+	ref, err := reference.GetReference(r.Scheme, object)
+	if err != nil {
+		return nil, err
+	}
+	nameKey := annotations[AnnotationEventSeriesKey]
+	if nameKey == "" {
+		nameKey = annotations[AnnotationEventIdempotencyKey]
+	} else {
+		annotations = maps.Clone(annotations)
+		annotations[AnnotationEventRecentKeys] = encodeRecentKeys([]string{annotations[AnnotationEventIdempotencyKey]})
+	}
+	sum := sha256.Sum256([]byte(nameKey))
+	return &eventsv1.Event{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        fmt.Sprintf("%s.%s", ref.Name, hex.EncodeToString(sum[:8])),
+			Namespace:   ref.Namespace,
+			Annotations: annotations,
+		},
+		EventTime:           metav1.NowMicro(),
+		ReportingController: r.Component,
+		ReportingInstance:   r.Instance,
+		Action:              reason,
+		Reason:              reason,
+		Regarding:           *ref,
+		Note:                truncateNote(message),
+		Type:                eventtype,
+	}, nil
+}
+
+// truncateNote 按字节截断 note 到 API Server 允许的长度，不截断多字节字符。
+func truncateNote(note string) string {
+	if len(note) <= maxEventNoteBytes {
+		return note
+	}
+	cut := maxEventNoteBytes
+	for cut > 0 && !utf8.RuneStart(note[cut]) {
+		cut--
+	}
+	return note[:cut]
+}
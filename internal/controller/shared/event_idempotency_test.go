@@ -0,0 +1,134 @@
+package shared
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	eventsv1 "k8s.io/api/events/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+var _ = Describe("Idempotent events", func() {
+	Context("When events share a series key", func() {
+		var (
+			c        client.Client
+			fallback *record.FakeRecorder
+			patches  []map[string]interface{}
+			r        *IdempotentEventRecorder
+			obj      *corev1.ConfigMap
+		)
+
+		BeforeEach(func() {
+			scheme := runtime.NewScheme()
+			Expect(corev1.AddToScheme(scheme)).To(Succeed())
+			Expect(eventsv1.AddToScheme(scheme)).To(Succeed())
+			patches = nil
+			c = fake.NewClientBuilder().WithScheme(scheme).WithInterceptorFuncs(interceptor.Funcs{
+				Patch: func(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+					data, err := patch.Data(obj)
+					Expect(err).NotTo(HaveOccurred())
+					var body map[string]interface{}
+					Expect(json.Unmarshal(data, &body)).To(Succeed())
+					patches = append(patches, body)
+					return c.Patch(ctx, obj, patch, opts...)
+				},
+			}).Build()
+			fallback = record.NewFakeRecorder(10)
+			r = NewIdempotentEventRecorder(fallback, c, c, scheme, "test-controller")
+			obj = &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "target", Namespace: "default", UID: "uid-1"}}
+		})
+
+		emit := func(key, note string) {
+			EmitSeriesEvent(r, obj, key, "uid-1/-/ExpectationFailed", corev1.EventTypeWarning, "ExpectationFailed", note)
+		}
+		series := func() eventsv1.Event {
+			var list eventsv1.EventList
+			Expect(c.List(context.Background(), &list)).To(Succeed())
+			Expect(list.Items).To(HaveLen(1))
+			return list.Items[0]
+		}
+
+		It("should count repeated keys without rewriting the immutable note", func() {
+			emit("uid-1/0/healthcheck-1/ExpectationFailed", "check 1 failed")
+			emit("uid-1/0/healthcheck-2/ExpectationFailed", "check 2 failed: different note")
+			emit("uid-1/0/healthcheck-3/ExpectationFailed", "check 3 failed")
+
+			event := series()
+			Expect(event.Series).NotTo(BeNil())
+			Expect(event.Series.Count).To(Equal(int32(3)))
+			Expect(event.Note).To(Equal("check 1 failed"))
+			Expect(event.Annotations[AnnotationEventIdempotencyKey]).To(Equal("uid-1/0/healthcheck-3/ExpectationFailed"))
+			Expect(fallback.Events).To(BeEmpty())
+
+			Expect(patches).To(HaveLen(2))
+			for _, patch := range patches {
+				Expect(patch).NotTo(HaveKey("note"))
+				Expect(patch).NotTo(HaveKey("type"))
+				Expect(patch).To(HaveKey("series"))
+			}
+		})
+
+		It("should treat alternating keys already counted as duplicates", func() {
+			emit("uid-1/0/healthcheck-1/ExpectationFailed", "check 1 failed")
+			emit("uid-1/0/healthcheck-2/ExpectationFailed", "check 2 failed")
+			emit("uid-1/0/healthcheck-1/ExpectationFailed", "check 1 failed")
+			emit("uid-1/0/healthcheck-2/ExpectationFailed", "check 2 failed")
+
+			Expect(series().Series.Count).To(Equal(int32(2)))
+			Expect(fallback.Events).To(BeEmpty())
+		})
+
+		It("should keep only the most recent keys", func() {
+			keys := []string{"a"}
+			for i := 0; i < maxSeriesRecentKeys+4; i++ {
+				keys = append(keys, string(rune('b'+i)))
+			}
+			var decoded []string
+			Expect(json.Unmarshal([]byte(encodeRecentKeys(keys)), &decoded)).To(Succeed())
+			Expect(decoded).To(HaveLen(maxSeriesRecentKeys))
+			Expect(decoded[len(decoded)-1]).To(Equal(keys[len(keys)-1]))
+		})
+	})
+
+	Context("When the API server does not answer", func() {
+		It("should give up after the write timeout and on cancellation", func() {
+			scheme := runtime.NewScheme()
+			Expect(corev1.AddToScheme(scheme)).To(Succeed())
+			Expect(eventsv1.AddToScheme(scheme)).To(Succeed())
+			c := fake.NewClientBuilder().WithScheme(scheme).WithInterceptorFuncs(interceptor.Funcs{
+				Create: func(ctx context.Context, _ client.WithWatch, _ client.Object, _ ...client.CreateOption) error {
+					<-ctx.Done()
+					return ctx.Err()
+				},
+			}).Build()
+			fallback := record.NewFakeRecorder(10)
+			r := NewIdempotentEventRecorder(fallback, c, c, scheme, "test-controller")
+			r.Timeout = 50 * time.Millisecond
+			obj := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "target", Namespace: "default", UID: "uid-1"}}
+
+			start := time.Now()
+			EmitKeyedNormalEvent(r, obj, "uid-1/0/-/Started", "Started", "started")
+			Expect(time.Since(start)).To(BeNumerically("<", time.Second))
+			Expect(<-fallback.Events).To(ContainSubstring("Started"))
+
+			// manager 停止后不再等待时限
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+			r.SetContext(ctx)
+			r.Timeout = time.Hour
+			start = time.Now()
+			EmitKeyedNormalEvent(r, obj, "uid-1/0/-/Finished", "Finished", "finished")
+			Expect(time.Since(start)).To(BeNumerically("<", time.Second))
+			Expect(<-fallback.Events).To(ContainSubstring("Finished"))
+		})
+	})
+})
@@ -9,18 +9,32 @@ import (
 const (
 	EventReasonExpectationPassed = "ExpectationPassed"
 	EventReasonExpectationFailed = "ExpectationFailed"
+	EventReasonExpectationError  = "ExpectationError"
+
+	EventReasonTestSummary = "TestSummary"
 )
 
 // IntegrationTest Event 原因常量
 const (
-	EventReasonIntegrationTestStarted   = "IntegrationTestStarted"
-	EventReasonIntegrationTestSucceeded = "IntegrationTestSucceeded"
-	EventReasonIntegrationTestFailed    = "IntegrationTestFailed"
-	EventReasonIntegrationTestTimeout   = "IntegrationTestTimeout"
+	EventReasonIntegrationTestStarted    = "IntegrationTestStarted"
+	EventReasonIntegrationTestSucceeded  = "IntegrationTestSucceeded"
+	EventReasonIntegrationTestFailed     = "IntegrationTestFailed"
+	EventReasonIntegrationTestTimeout    = "IntegrationTestTimeout"
+	EventReasonIntegrationTestAborted    = "IntegrationTestAborted"
+	EventReasonSpecChangeIgnored         = "SpecChangeIgnored"
+	EventReasonIntegrationTestSuspended  = "IntegrationTestSuspended"
+	EventReasonIntegrationTestResumed    = "IntegrationTestResumed"
+	EventReasonIntegrationTestBreakpoint = "IntegrationTestBreakpoint"
 
 	EventReasonStepStarted   = "StepStarted"
 	EventReasonStepSucceeded = "StepSucceeded"
 	EventReasonStepFailed    = "StepFailed"
+
+	EventReasonStepIterationSucceeded = "StepIterationSucceeded"
+	EventReasonStepIterationFailed    = "StepIterationFailed"
+
+	EventReasonStepHookFailed = "StepHookFailed"
+	EventReasonCleanupFailed  = "CleanupFailed"
 )
 
 // LoadTest Event 原因常量
@@ -30,13 +44,60 @@ const (
 	EventReasonLoadTestSucceeded = "LoadTestSucceeded"
 	EventReasonLoadTestFailed    = "LoadTestFailed"
 
+	EventReasonPostVerificationStarted = "PostVerificationStarted"
+
+	EventReasonHealthCheckWarmup = "HealthCheckWarmup"
+
 	EventReasonTargetApplied      = "TargetApplied"
 	EventReasonTargetReady        = "TargetReady"
 	EventReasonTargetApplyFailed  = "TargetApplyFailed"
 	EventReasonReadyConditionWait = "ReadyConditionWait"
+	EventReasonTargetDrifted      = "TargetDrifted"
+	EventReasonTargetReapplied    = "TargetReapplied"
 
 	EventReasonWorkloadApplied     = "WorkloadApplied"
 	EventReasonWorkloadApplyFailed = "WorkloadApplyFailed"
+
+	EventReasonMonitoringApplyFailed = "MonitoringApplyFailed"
+
+	EventReasonLoadAdjusted  = "LoadAdjusted"
+	EventReasonLoadConverged = "LoadConverged"
+)
+
+// 失败分诊 Event 原因常量（IntegrationTest 与 LoadTest 共用）
+const (
+	EventReasonTriageCompleted = "TriageCompleted"
+	EventReasonTriageFailed    = "TriageFailed"
+)
+
+// 重跑 Event 原因常量（IntegrationTest 与 LoadTest 共用）
+const (
+	EventReasonTestRerun = "TestRerun"
+)
+
+// Check Event 原因常量
+const (
+	EventReasonCheckPassed = "CheckPassed"
+	EventReasonCheckFailed = "CheckFailed"
+)
+
+// TestSuite Event 原因常量
+const (
+	EventReasonTestSuiteStarted     = "TestSuiteStarted"
+	EventReasonTestSuiteItemStarted = "TestSuiteItemStarted"
+	EventReasonTestSuiteItemFailed  = "TestSuiteItemFailed"
+	EventReasonTestSuiteSucceeded   = "TestSuiteSucceeded"
+	EventReasonTestSuiteFailed      = "TestSuiteFailed"
+)
+
+// ScheduledTest Event 原因常量
+const (
+	EventReasonScheduledTestRunCreated    = "ScheduledTestRunCreated"
+	EventReasonScheduledTestRunSkipped    = "ScheduledTestRunSkipped"
+	EventReasonScheduledTestRunMissed     = "ScheduledTestRunMissed"
+	EventReasonScheduledTestRunReplaced   = "ScheduledTestRunReplaced"
+	EventReasonScheduledTestTooManyMissed = "ScheduledTestTooManyMissed"
+	EventReasonScheduledTestInvalid       = "ScheduledTestInvalid"
 )
 
 // EventRecorder 定义事件记录器接口
@@ -2,15 +2,20 @@ package shared
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"io"
 	"net/http"
+	"sort"
+	"sync"
 	"time"
 
 	infrav1alpha1 "github.com/lunz1207/testplane/api/v1alpha1"
+	"github.com/lunz1207/testplane/internal/controller/shared/resource"
 	"github.com/lunz1207/testplane/internal/plugin"
 	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 // normalizeParams 确保 RawExtension 不为 null，为空时返回空对象 {}。
@@ -21,10 +26,50 @@ func normalizeParams(params runtime.RawExtension) runtime.RawExtension {
 	return params
 }
 
+// RelatedFetcher 按期望声明的 relatedResources 获取关联资源并合并到被断言资源中。
+type RelatedFetcher func(resource map[string]interface{}, refs []infrav1alpha1.RelatedResource) error
+
+// SubresourceFetcher 获取被断言资源的子资源视图（如 scale）。
+type SubresourceFetcher func(resource map[string]interface{}, subresource string) (map[string]interface{}, error)
+
+// ResourceResolver 按别名获取期望 resource 字段引用的资源。
+type ResourceResolver func(alias string) (map[string]interface{}, error)
+
+// TombstoneLookup 按状态键或别名获取资源删除前保存的快照。
+type TombstoneLookup func(name string) (map[string]interface{}, bool)
+
+// errTombstoneLost 墓碑快照不存在：快照只保存在内存中，控制器重启或切主后丢失。
+var errTombstoneLost = errors.New("no tombstone recorded")
+
 // ExpectationRunner 统一的期望执行器。
 type ExpectationRunner struct {
 	Registry   *plugin.Registry
 	HTTPClient *http.Client
+	// FetchRelated 关联资源获取器（可选，未设置时声明 relatedResources 的期望直接失败）。
+	FetchRelated RelatedFetcher
+	// FetchSubresource 子资源获取器（可选，未设置时声明 subresource 的期望直接失败）。
+	FetchSubresource SubresourceFetcher
+	// Injections 故障注入配置（可选，仅用于自测告警与失败路径）。
+	Injections FailureInjections
+	// injectionCtx 注入延迟等待时监听的 context，取消时提前结束等待。
+	injectionCtx context.Context
+	// Environment 测试引用的 Environment（可选），随 Webhook 请求传递。
+	Environment *infrav1alpha1.Environment
+	// ParamValues 期望参数中 ${vars.*}、${steps.*} 引用的取值（可选）。
+	ParamValues ParamValues
+	// UnresolvedParams 无法取值的引用及原因（如 valueFrom 引用的测试尚未产生该值），
+	// 原因为 TransientError 时引用它们的期望记为可重试错误。
+	UnresolvedParams map[string]error
+	// ResolveResource 别名解析器（可选，未设置时 resource 只能引用状态键）。
+	ResolveResource ResourceResolver
+	// LookupTombstone 墓碑快照获取器（可选，未设置时声明 tombstone 的期望直接失败）。
+	LookupTombstone TombstoneLookup
+	// HeldSince 上次检查中各期望（按 allOf、anyOf 顺序）开始连续成立的时间，
+	// 由 WithHeldSince 设置后才跟踪 holdSeconds，否则忽略。
+	HeldSince []HeldSince
+	trackHold bool
+
+	recording *recordingTarget
 }
 
 // NewExpectationRunner 创建期望执行器。
@@ -37,6 +82,66 @@ func NewExpectationRunner(registry *plugin.Registry) *ExpectationRunner {
 	}
 }
 
+// WithRelatedReader 设置基于 reader 的关联资源获取器。
+func (runner *ExpectationRunner) WithRelatedReader(ctx context.Context, reader client.Reader) *ExpectationRunner {
+	runner.FetchRelated = func(res map[string]interface{}, refs []infrav1alpha1.RelatedResource) error {
+		return resource.AttachDeclaredRelated(ctx, reader, res, refs)
+	}
+	return runner
+}
+
+// WithSubresourceClient 设置基于 client 的子资源获取器（经 API Server 读取，不经缓存）。
+func (runner *ExpectationRunner) WithSubresourceClient(ctx context.Context, c client.Client) *ExpectationRunner {
+	runner.FetchSubresource = func(res map[string]interface{}, subresource string) (map[string]interface{}, error) {
+		return resource.GetSubresource(ctx, c, res, subresource)
+	}
+	return runner
+}
+
+// WithFailureInjection 启用 context 中由注解声明的故障注入。
+func (runner *ExpectationRunner) WithFailureInjection(ctx context.Context) *ExpectationRunner {
+	runner.Injections = FailureInjectionFromContext(ctx)
+	runner.injectionCtx = ctx
+	return runner
+}
+
+// WithEnvironment 使用 context 中测试引用的 Environment。
+func (runner *ExpectationRunner) WithEnvironment(ctx context.Context) *ExpectationRunner {
+	runner.Environment = EnvironmentFromContext(ctx)
+	return runner
+}
+
+// WithParamValues 设置期望参数引用的取值。
+func (runner *ExpectationRunner) WithParamValues(values ParamValues) *ExpectationRunner {
+	runner.ParamValues = values
+	return runner
+}
+
+// WithUnresolvedParams 设置无法取值的参数引用。
+func (runner *ExpectationRunner) WithUnresolvedParams(unresolved map[string]error) *ExpectationRunner {
+	runner.UnresolvedParams = unresolved
+	return runner
+}
+
+// WithResourceResolver 设置期望 resource 别名的解析器。
+func (runner *ExpectationRunner) WithResourceResolver(resolve ResourceResolver) *ExpectationRunner {
+	runner.ResolveResource = resolve
+	return runner
+}
+
+// WithTombstones 设置墓碑快照获取器。
+func (runner *ExpectationRunner) WithTombstones(lookup TombstoneLookup) *ExpectationRunner {
+	runner.LookupTombstone = lookup
+	return runner
+}
+
+// WithHeldSince 启用 holdSeconds 跟踪，held 为上次检查的结果（见 HeldSinceFromResults）。
+func (runner *ExpectationRunner) WithHeldSince(held []HeldSince) *ExpectationRunner {
+	runner.HeldSince = held
+	runner.trackHold = true
+	return runner
+}
+
 // ExpectationResults 包含 allOf 和 anyOf 的检查结果。
 type ExpectationResults struct {
 	AllOf []infrav1alpha1.ExpectationResult
@@ -52,29 +157,50 @@ func (r ExpectationResults) All() []infrav1alpha1.ExpectationResult {
 }
 
 // Passed 检查期望是否满足：allOf 全部通过 && anyOf 任一通过（如果有）。
+// Warning 级别的结果不参与判定。
 func (r ExpectationResults) Passed() bool {
 	// allOf: 全部必须通过
 	for _, result := range r.AllOf {
-		if !result.Passed {
+		if !result.Passed && !IsWarning(result) {
 			return false
 		}
 	}
 
-	// anyOf: 任一通过即可（如果没有 anyOf 则视为通过）
-	if len(r.AnyOf) > 0 {
-		anyPassed := false
-		for _, result := range r.AnyOf {
-			if result.Passed {
-				anyPassed = true
-				break
-			}
+	// anyOf: 任一通过即可（如果没有 Blocker 级别的 anyOf 则视为通过）
+	blockers, anyPassed := 0, false
+	for _, result := range r.AnyOf {
+		if IsWarning(result) {
+			continue
 		}
-		if !anyPassed {
-			return false
+		blockers++
+		if result.Passed {
+			anyPassed = true
+			break
+		}
+	}
+	return blockers == 0 || anyPassed
+}
+
+// Warnings 返回未通过的 Warning 级别结果。
+func (r ExpectationResults) Warnings() []infrav1alpha1.ExpectationResult {
+	var warnings []infrav1alpha1.ExpectationResult
+	for _, result := range r.All() {
+		if !result.Passed && IsWarning(result) {
+			warnings = append(warnings, result)
 		}
 	}
+	return warnings
+}
 
-	return true
+// ErrorCount 返回执行出错（而非断言未通过）的结果数。
+func (r ExpectationResults) ErrorCount() int {
+	count := 0
+	for _, result := range r.All() {
+		if result.Error {
+			count++
+		}
+	}
+	return count
 }
 
 // RunStepCondition 执行 StepCondition 期望检查（用于 IntegrationTest）。
@@ -85,7 +211,7 @@ func (runner *ExpectationRunner) RunStepCondition(
 	if condition == nil {
 		return ExpectationResults{}, nil
 	}
-	return runner.runExpectations(condition.AllOf, condition.AnyOf, state)
+	return runner.runAndRecord(ConditionKindStep, condition.AllOf, condition.AnyOf, state)
 }
 
 // RunReadyCondition 执行 ReadyCondition 期望检查（用于 LoadTest Target）。
@@ -96,7 +222,7 @@ func (runner *ExpectationRunner) RunReadyCondition(
 	if condition == nil {
 		return ExpectationResults{}, nil
 	}
-	return runner.runExpectations(condition.AllOf, condition.AnyOf, state)
+	return runner.runAndRecord(ConditionKindReady, condition.AllOf, condition.AnyOf, state)
 }
 
 // RunHealthCheck 执行 HealthCheck 期望检查（用于 LoadTest 运行期）。
@@ -107,10 +233,25 @@ func (runner *ExpectationRunner) RunHealthCheck(
 	if healthCheck == nil {
 		return ExpectationResults{}, nil
 	}
-	return runner.runExpectations(healthCheck.AllOf, healthCheck.AnyOf, state)
+	return runner.runAndRecord(ConditionKindHealthCheck, healthCheck.AllOf, healthCheck.AnyOf, state)
+}
+
+// runAndRecord 执行期望检查，并在启用录制时保存状态快照。
+func (runner *ExpectationRunner) runAndRecord(
+	condition string,
+	allOf []infrav1alpha1.Expectation,
+	anyOf []infrav1alpha1.Expectation,
+	state map[string]interface{},
+) (ExpectationResults, error) {
+	results, err := runner.runExpectations(allOf, anyOf, state)
+	runner.record(condition, allOf, anyOf, state, results, err)
+	recordExpectationWarnings(condition, results.Warnings())
+	return results, err
 }
 
 // runExpectations 执行期望检查（allOf + anyOf）。
+// 可重试的错误记为错误结果（Error=true、Passed=false）并继续执行其余期望，
+// 由调用方在下一次检查时重试；永久错误或 onError=Fail 时立即中止并返回错误。
 func (runner *ExpectationRunner) runExpectations(
 	allOf []infrav1alpha1.Expectation,
 	anyOf []infrav1alpha1.Expectation,
@@ -120,36 +261,84 @@ func (runner *ExpectationRunner) runExpectations(
 
 	// 执行 allOf
 	results.AllOf = make([]infrav1alpha1.ExpectationResult, 0, len(allOf))
-	for _, exp := range allOf {
+	for i, exp := range allOf {
 		result, err := runner.runExpectation(exp, state)
 		if err != nil {
-			return results, err
+			if !retryableExpectationError(exp, err) {
+				return results, err
+			}
+			result.Error = true
 		}
+		result.Name, result.Description, result.Severity = exp.Name, exp.Description, exp.Severity
+		runner.applyHold(exp, i, &result)
 		results.AllOf = append(results.AllOf, result)
 	}
 
 	// 执行 anyOf
 	results.AnyOf = make([]infrav1alpha1.ExpectationResult, 0, len(anyOf))
-	for _, exp := range anyOf {
+	for i, exp := range anyOf {
 		result, err := runner.runExpectation(exp, state)
 		if err != nil {
-			return results, err
+			if !retryableExpectationError(exp, err) {
+				return results, err
+			}
+			result.Error = true
 		}
+		result.Name, result.Description, result.Severity = exp.Name, exp.Description, exp.Severity
+		runner.applyHold(exp, len(allOf)+i, &result)
 		results.AnyOf = append(results.AnyOf, result)
 	}
 
 	return results, nil
 }
 
+// unresolvedParamError 参数引用了无法取值的变量时返回取值错误（保留其是否可重试），否则原样返回 err。
+func (runner *ExpectationRunner) unresolvedParamError(params runtime.RawExtension, err error) error {
+	for _, match := range paramRefPattern.FindAllSubmatch(params.Raw, -1) {
+		if cause, ok := runner.UnresolvedParams[string(match[1])]; ok {
+			return fmt.Errorf("%s: %w", match[1], cause)
+		}
+	}
+	return err
+}
+
+// retryableExpectationError 判断期望执行错误是否可重试：暂时性错误且 onError 不为 Fail。
+func retryableExpectationError(exp infrav1alpha1.Expectation, err error) bool {
+	if exp.OnError == infrav1alpha1.ExpectationErrorFail {
+		return false
+	}
+	var transient *TransientError
+	return errors.As(err, &transient)
+}
+
 // runExpectation 执行单个期望检查。
 // 支持两种模式：
 // 1. 内置函数：Function + Params（可选）
 // 2. Webhook：Function + Webhook + Params（可选）
-// 断言的资源由调用方在 state 中提供。
+// 断言的资源由调用方在 state 中提供；参数中的引用先替换为取值，结果中记录替换后的参数。
 func (runner *ExpectationRunner) runExpectation(
 	exp infrav1alpha1.Expectation,
 	state map[string]interface{},
 ) (infrav1alpha1.ExpectationResult, error) {
+	params, err := ExpandParams(exp.Params, runner.ParamValues)
+	if err != nil {
+		err = runner.unresolvedParamError(exp.Params, err)
+		return infrav1alpha1.ExpectationResult{
+			Expect:  exp.Function,
+			Params:  normalizeParams(exp.Params),
+			Passed:  false,
+			Message: err.Error(),
+		}, err
+	}
+	exp.Params = params
+
+	// 故障注入：强制失败/通过，或延迟后继续正常执行
+	if inj, ok := runner.Injections[exp.Function]; ok {
+		if injected, err := inj.apply(runner.injectionCtx, exp); injected != nil || err != nil {
+			return *injected, err
+		}
+	}
+
 	// 有 Webhook → 调用外部服务
 	if exp.Webhook != "" {
 		return runner.runWebhook(exp)
@@ -157,8 +346,124 @@ func (runner *ExpectationRunner) runExpectation(
 
 	// 无 Webhook → 调用内置函数
 	payload := SelectStateForExpectation(state)
+	switch {
+	case exp.Tombstone:
+		target, err := runner.tombstone(exp.Resource, state)
+		if err != nil {
+			// 快照丢失时无法判定断言，记为错误结果而非未通过
+			return infrav1alpha1.ExpectationResult{
+				Expect:  exp.Function,
+				Params:  normalizeParams(exp.Params),
+				Passed:  false,
+				Error:   errors.Is(err, errTombstoneLost),
+				Message: err.Error(),
+			}, nil
+		}
+		payload = target
+	case exp.Resource != "":
+		target, err := runner.targetResource(exp.Resource, state)
+		if err != nil {
+			return infrav1alpha1.ExpectationResult{
+				Expect:  exp.Function,
+				Params:  normalizeParams(exp.Params),
+				Passed:  false,
+				Message: err.Error(),
+			}, err
+		}
+		payload = target
+	}
 
-	return runner.runFunction(exp, payload)
+	// 断言子资源视图（如 scale）代替资源本身
+	if exp.Subresource != "" {
+		target, err := runner.subresource(exp.Subresource, payload)
+		if err != nil {
+			return infrav1alpha1.ExpectationResult{
+				Expect:  exp.Function,
+				Params:  normalizeParams(exp.Params),
+				Passed:  false,
+				Message: err.Error(),
+			}, nil
+		}
+		payload = target
+	}
+
+	// 获取声明的关联资源
+	if len(exp.RelatedResources) > 0 {
+		if failed := runner.attachRelated(exp, payload); failed != nil {
+			return *failed, nil
+		}
+	}
+
+	// 跨资源函数 → 默认资源之外，按参数引用的状态键或别名获取资源
+	if runner.Registry.IsStateFunction(exp.Function) {
+		return runner.runStateFunction(exp, payload, state)
+	}
+	return runner.runFunction(exp, projectFields(payload, exp.Fields))
+}
+
+// targetResource 返回期望 resource 字段引用的资源：先按状态键查找，再按别名解析。
+func (runner *ExpectationRunner) targetResource(name string, state map[string]interface{}) (map[string]interface{}, error) {
+	if res, ok := state[name].(map[string]interface{}); ok {
+		return res, nil
+	}
+	if runner.ResolveResource == nil {
+		return nil, fmt.Errorf("resource %q not found in state", name)
+	}
+	return runner.ResolveResource(name)
+}
+
+// tombstone 返回期望引用资源的墓碑快照：name 为空时使用状态中唯一的资源。
+// 快照不存在（如控制器重启）时返回 errTombstoneLost。
+func (runner *ExpectationRunner) tombstone(name string, state map[string]interface{}) (map[string]interface{}, error) {
+	if runner.LookupTombstone == nil {
+		return nil, fmt.Errorf("tombstone is not supported in this context")
+	}
+	if name == "" {
+		if len(state) != 1 {
+			return nil, fmt.Errorf("tombstone requires resource when the state has %d resources", len(state))
+		}
+		for key := range state {
+			name = key
+		}
+	}
+	snapshot, ok := runner.LookupTombstone(name)
+	if !ok {
+		return nil, fmt.Errorf("%w for %q (lost after a controller restart?)", errTombstoneLost, name)
+	}
+	return snapshot, nil
+}
+
+// subresource 获取被断言资源的子资源视图。
+func (runner *ExpectationRunner) subresource(name string, payload map[string]interface{}) (map[string]interface{}, error) {
+	if runner.FetchSubresource == nil {
+		return nil, fmt.Errorf("subresource is not supported in this context")
+	}
+	if len(payload) == 0 {
+		return nil, fmt.Errorf("no resource to get subresource %s for", name)
+	}
+	return runner.FetchSubresource(payload, name)
+}
+
+// attachRelated 获取期望声明的关联资源，失败时返回未通过的结果。
+func (runner *ExpectationRunner) attachRelated(exp infrav1alpha1.Expectation, payload map[string]interface{}) *infrav1alpha1.ExpectationResult {
+	var err error
+	switch {
+	case runner.FetchRelated == nil:
+		err = fmt.Errorf("relatedResources are not supported in this context")
+	case len(payload) == 0:
+		err = fmt.Errorf("no resource to fetch relatedResources for")
+	default:
+		err = runner.FetchRelated(payload, exp.RelatedResources)
+	}
+	if err == nil {
+		return nil
+	}
+	return &infrav1alpha1.ExpectationResult{
+		Expect:  exp.Function,
+		Params:  normalizeParams(exp.Params),
+		Passed:  false,
+		Message: err.Error(),
+	}
 }
 
 // runFunction 执行内置函数断言。
@@ -176,23 +481,81 @@ func (runner *ExpectationRunner) runFunction(
 		}, err
 	}
 
+	return runFunctionResult(exp, result), nil
+}
+
+// runFunctionResult 将函数结果转换为期望结果，通过时不记录实际值与消息。
+func runFunctionResult(exp infrav1alpha1.Expectation, result plugin.Result) infrav1alpha1.ExpectationResult {
 	out := infrav1alpha1.ExpectationResult{
 		Expect: exp.Function,
 		Params: normalizeParams(exp.Params),
 		Passed: result.Passed,
+		Error:  result.Error,
 	}
 	if !result.Passed {
 		out.Actual = result.Actual
 		out.Message = result.Message
+		out.RetryAfterSeconds = int32(result.RetryAfter / time.Second)
 	}
+	return out
+}
 
-	return out, nil
+// runStateFunction 执行跨资源函数，函数通过 lookup 按状态键或别名获取资源，
+// 名称为空时返回默认资源 payload（已合并 relatedResources）。
+func (runner *ExpectationRunner) runStateFunction(
+	exp infrav1alpha1.Expectation,
+	payload map[string]interface{},
+	state map[string]interface{},
+) (infrav1alpha1.ExpectationResult, error) {
+	lookup := func(name string) (map[string]interface{}, error) {
+		if name == "" {
+			return payload, nil
+		}
+		return runner.targetResource(name, state)
+	}
+	result, err := runner.Registry.CallState(exp.Function, lookup, exp.Params.Raw)
+	if err != nil {
+		return infrav1alpha1.ExpectationResult{
+			Expect:  exp.Function,
+			Params:  normalizeParams(exp.Params),
+			Passed:  false,
+			Message: err.Error(),
+		}, err
+	}
+	return runFunctionResult(exp, result), nil
 }
 
 // WebhookRequest Webhook 请求结构。
 type WebhookRequest struct {
-	Function string                 `json:"function"`
-	Params   map[string]interface{} `json:"params,omitempty"`
+	Function    string                 `json:"function"`
+	Params      map[string]interface{} `json:"params,omitempty"`
+	Environment *WebhookEnvironment    `json:"environment,omitempty"`
+}
+
+// WebhookEnvironment 随 Webhook 请求传递的 Environment 信息。
+// kubeconfig 只传递 Secret 引用，由 Webhook 服务按自身权限读取。
+type WebhookEnvironment struct {
+	Name                string                            `json:"name"`
+	Namespace           string                            `json:"namespace"`
+	DefaultNamespace    string                            `json:"defaultNamespace,omitempty"`
+	KubeconfigSecretRef *infrav1alpha1.SecretKeyReference `json:"kubeconfigSecretRef,omitempty"`
+	BaseURLs            map[string]string                 `json:"baseURLs,omitempty"`
+	Variables           map[string]string                 `json:"variables,omitempty"`
+}
+
+// webhookEnvironment 构造 Webhook 请求中的 Environment 信息（env 为 nil 时返回 nil）。
+func webhookEnvironment(env *infrav1alpha1.Environment) *WebhookEnvironment {
+	if env == nil {
+		return nil
+	}
+	return &WebhookEnvironment{
+		Name:                env.Name,
+		Namespace:           env.Namespace,
+		DefaultNamespace:    env.Spec.DefaultNamespace,
+		KubeconfigSecretRef: env.Spec.KubeconfigSecretRef,
+		BaseURLs:            env.Spec.BaseURLs,
+		Variables:           env.Spec.Variables,
+	}
 }
 
 // WebhookResponse Webhook 响应结构。
@@ -200,11 +563,44 @@ type WebhookResponse struct {
 	Passed  bool   `json:"passed"`
 	Actual  string `json:"actual,omitempty"`
 	Message string `json:"message,omitempty"`
+	// RetryAfterSeconds 未通过时建议的下次检查间隔（秒）。
+	RetryAfterSeconds int32 `json:"retryAfterSeconds,omitempty"`
+}
+
+// webhookBufferPool 复用 Webhook 请求与响应的缓冲区（健康检查按固定间隔高频调用）。
+var webhookBufferPool = sync.Pool{
+	New: func() interface{} { return new(bytes.Buffer) },
+}
+
+// pooledBody 以池中缓冲区为内容的请求体。
+// Transport 在 Do 返回后仍可能读取请求体（如连接复用时的重试），因此在 Transport 关闭请求体时才归还缓冲区。
+type pooledBody struct {
+	*bytes.Reader
+	buf  *bytes.Buffer
+	once sync.Once
+}
+
+func (b *pooledBody) Close() error {
+	b.once.Do(func() { webhookBufferPool.Put(b.buf) })
+	return nil
+}
+
+// newPooledRequest 创建以 buf 为请求体的 JSON POST 请求，buf 在请求体关闭时归还（创建失败时立即归还）。
+func newPooledRequest(url string, buf *bytes.Buffer) (*http.Request, error) {
+	body := &pooledBody{Reader: bytes.NewReader(buf.Bytes()), buf: buf}
+	req, err := http.NewRequest(http.MethodPost, url, body)
+	if err != nil {
+		_ = body.Close()
+		return nil, err
+	}
+	req.ContentLength = int64(buf.Len())
+	req.Header.Set("Content-Type", "application/json")
+	return req, nil
 }
 
 // runWebhook 调用 Webhook 执行断言。
-// 请求格式：{ function, params }
-// 响应格式：{ passed, actual, message }
+// 请求格式：{ function, params, environment }
+// 响应格式：{ passed, actual, message, retryAfterSeconds }
 func (runner *ExpectationRunner) runWebhook(
 	exp infrav1alpha1.Expectation,
 ) (infrav1alpha1.ExpectationResult, error) {
@@ -225,11 +621,14 @@ func (runner *ExpectationRunner) runWebhook(
 
 	// 构建请求
 	reqBody := WebhookRequest{
-		Function: exp.Function,
-		Params:   params,
+		Function:    exp.Function,
+		Params:      params,
+		Environment: webhookEnvironment(runner.Environment),
 	}
-	reqData, err := json.Marshal(reqBody)
-	if err != nil {
+	reqBuf := webhookBufferPool.Get().(*bytes.Buffer)
+	reqBuf.Reset()
+	if err := json.NewEncoder(reqBuf).Encode(reqBody); err != nil {
+		webhookBufferPool.Put(reqBuf)
 		return infrav1alpha1.ExpectationResult{
 			Expect:  exp.Function,
 			Params:  normalizeParams(exp.Params),
@@ -239,35 +638,48 @@ func (runner *ExpectationRunner) runWebhook(
 	}
 
 	// 调用 Webhook
-	resp, err := runner.HTTPClient.Post(webhookURL, "application/json", bytes.NewReader(reqData))
+	req, err := newPooledRequest(webhookURL, reqBuf)
+	var resp *http.Response
+	if err == nil {
+		resp, err = runner.HTTPClient.Do(req)
+	}
 	if err != nil {
 		return infrav1alpha1.ExpectationResult{
 			Expect:  exp.Function,
 			Params:  normalizeParams(exp.Params),
 			Passed:  false,
 			Message: fmt.Sprintf("webhook call failed: %v", err),
-		}, err
+		}, NewTransientError(err, ShortRequeueAfter)
 	}
 	defer func() { _ = resp.Body.Close() }()
 
-	// 读取响应
-	respData, err := io.ReadAll(resp.Body)
+	// 读取响应（缓冲区在返回前归还，响应内容已复制到结果中）
+	respBuf := webhookBufferPool.Get().(*bytes.Buffer)
+	respBuf.Reset()
+	defer webhookBufferPool.Put(respBuf)
+	_, err = respBuf.ReadFrom(resp.Body)
+	respData := respBuf.Bytes()
 	if err != nil {
 		return infrav1alpha1.ExpectationResult{
 			Expect:  exp.Function,
 			Params:  normalizeParams(exp.Params),
 			Passed:  false,
 			Message: fmt.Sprintf("read response failed: %v", err),
-		}, err
+		}, NewTransientError(err, ShortRequeueAfter)
 	}
 
 	if resp.StatusCode != http.StatusOK {
+		err := fmt.Errorf("webhook returned status %d", resp.StatusCode)
+		// 5xx 与 429 视为服务端暂时不可用，其余状态码为永久错误
+		if resp.StatusCode >= http.StatusInternalServerError || resp.StatusCode == http.StatusTooManyRequests {
+			err = NewTransientError(err, ShortRequeueAfter)
+		}
 		return infrav1alpha1.ExpectationResult{
 			Expect:  exp.Function,
 			Params:  normalizeParams(exp.Params),
 			Passed:  false,
 			Message: fmt.Sprintf("webhook returned status %d: %s", resp.StatusCode, string(respData)),
-		}, fmt.Errorf("webhook returned status %d", resp.StatusCode)
+		}, err
 	}
 
 	// 解析响应
@@ -281,27 +693,43 @@ func (runner *ExpectationRunner) runWebhook(
 		}, err
 	}
 
-	return infrav1alpha1.ExpectationResult{
+	out := infrav1alpha1.ExpectationResult{
 		Expect:  exp.Function,
 		Params:  normalizeParams(exp.Params),
 		Passed:  webhookResp.Passed,
 		Actual:  webhookResp.Actual,
 		Message: webhookResp.Message,
-	}, nil
+	}
+	if !webhookResp.Passed {
+		out.RetryAfterSeconds = webhookResp.RetryAfterSeconds
+	}
+	return out, nil
 }
 
 // SelectStateForExpectation 选择最适合期望使用的对象。
+// 多个资源时按 key 排序后依次优先选择：带 status 的对象、带 spec 的对象、
+// 带 data/stringData/binaryData 的无 status 对象（ConfigMap、Secret 等）。
 func SelectStateForExpectation(state map[string]interface{}) map[string]interface{} {
 	if len(state) == 1 {
 		return unwrapSingleState(state)
 	}
-	for _, v := range state {
-		if m, ok := v.(map[string]interface{}); ok {
-			if _, hasStatus := m["status"]; hasStatus {
-				return m
+
+	keys := make([]string, 0, len(state))
+	for k := range state {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, fields := range [][]string{{"status"}, {"spec"}, {"data", "stringData", "binaryData"}} {
+		for _, k := range keys {
+			m, ok := state[k].(map[string]interface{})
+			if !ok {
+				continue
 			}
-			if _, hasSpec := m["spec"]; hasSpec {
-				return m
+			for _, f := range fields {
+				if _, has := m[f]; has {
+					return m
+				}
 			}
 		}
 	}
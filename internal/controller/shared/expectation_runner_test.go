@@ -0,0 +1,82 @@
+package shared
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	infrav1alpha1 "github.com/lunz1207/testplane/api/v1alpha1"
+	"github.com/lunz1207/testplane/internal/plugin"
+)
+
+var _ = Describe("Expectation runner", func() {
+	Context("When a webhook expectation errors", func() {
+		var server *httptest.Server
+
+		serve := func(status int) {
+			server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(status)
+			}))
+			DeferCleanup(server.Close)
+		}
+
+		run := func(onError infrav1alpha1.ExpectationErrorPolicy) (ExpectationResults, error) {
+			condition := &infrav1alpha1.StepCondition{AllOf: []infrav1alpha1.Expectation{
+				{Function: "remote", Webhook: server.URL, OnError: onError},
+			}}
+			return NewExpectationRunner(plugin.NewRegistry()).RunStepCondition(condition, map[string]interface{}{})
+		}
+
+		It("should record a transient error as a retryable result", func() {
+			serve(http.StatusServiceUnavailable)
+			results, err := run("")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(results.AllOf).To(HaveLen(1))
+			Expect(results.AllOf[0].Error).To(BeTrue())
+			Expect(results.AllOf[0].Passed).To(BeFalse())
+			Expect(results.ErrorCount()).To(Equal(1))
+		})
+
+		It("should abort on a permanent error", func() {
+			serve(http.StatusBadRequest)
+			_, err := run("")
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should abort on a transient error with onError Fail", func() {
+			serve(http.StatusTooManyRequests)
+			_, err := run(infrav1alpha1.ExpectationErrorFail)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Context("When a failure injection delays an expectation", func() {
+		run := func(ctx context.Context, injection string) (ExpectationResults, error) {
+			ctx, err := ContextWithFailureInjection(ctx, map[string]string{infrav1alpha1.AnnotationInjectExpectation: injection})
+			Expect(err).NotTo(HaveOccurred())
+			registry := plugin.NewRegistry()
+			registry.Register("Ready", func(_, _ map[string]interface{}) plugin.Result { return plugin.Pass() })
+			condition := &infrav1alpha1.StepCondition{AllOf: []infrav1alpha1.Expectation{{Function: "Ready"}}}
+			return NewExpectationRunner(registry).WithFailureInjection(ctx).RunStepCondition(condition, map[string]interface{}{})
+		}
+
+		It("should run the expectation after the delay", func() {
+			results, err := run(context.Background(), "Ready=delay:10ms")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(results.Passed()).To(BeTrue())
+		})
+
+		It("should stop waiting when the context is cancelled", func() {
+			ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+			defer cancel()
+			start := time.Now()
+			_, err := run(ctx, "Ready=delay:30s")
+			Expect(err).To(MatchError(context.DeadlineExceeded))
+			Expect(time.Since(start)).To(BeNumerically("<", 5*time.Second))
+		})
+	})
+})
@@ -0,0 +1,96 @@
+package shared
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	infrav1alpha1 "github.com/lunz1207/testplane/api/v1alpha1"
+	"github.com/lunz1207/testplane/pkg/export"
+)
+
+// exportDeadline 一次导出（含所有导出器）的总超时。
+const exportDeadline = 30 * time.Second
+
+// ResultExporter 把测试事件交给控制器级导出器与测试 spec 中配置的导出器。
+// 导出在后台进行，失败只记录日志，不影响调和。nil 表示不导出。
+type ResultExporter struct {
+	// Global 控制器级导出器（--exporters-config），可为空。
+	Global export.Exporter
+	// Reader 读取 spec 导出器凭证 Secret，建议使用 APIReader 避免缓存所有 Secret。
+	Reader client.Reader
+}
+
+// ExportPhaseChange 导出 PhaseChanged 事件。新阶段为空（如重跑清空状态）时跳过。
+func (e *ResultExporter) ExportPhaseChange(ctx context.Context, obj client.Object, kind string, specs []infrav1alpha1.ExporterSpec,
+	round int, previous, phase, reason, message string) {
+	if phase == "" || phase == previous {
+		return
+	}
+	e.Export(ctx, obj, kind, specs, export.Event{
+		Type:          infrav1alpha1.ExportEventPhaseChanged,
+		Key:           EventKey(obj, round, "", string(infrav1alpha1.ExportEventPhaseChanged)+"-"+phase),
+		Phase:         phase,
+		PreviousPhase: previous,
+		Reason:        reason,
+		Message:       message,
+		Round:         round,
+	})
+}
+
+// Export 补全事件来源后异步导出。
+func (e *ResultExporter) Export(ctx context.Context, obj client.Object, kind string, specs []infrav1alpha1.ExporterSpec, event export.Event) {
+	if e == nil {
+		return
+	}
+	log := logf.FromContext(ctx).WithValues("exportEvent", event.Type, "phase", event.Phase)
+	event.Time = time.Now()
+	event.Object = export.ObjectRef{Kind: kind, Namespace: obj.GetNamespace(), Name: obj.GetName(), UID: string(obj.GetUID())}
+	event.Labels = obj.GetLabels()
+
+	exporters := export.Multi{}
+	if e.Global != nil {
+		exporters = append(exporters, e.Global)
+	}
+	for i, spec := range specs {
+		exporter, err := e.specExporter(ctx, obj.GetNamespace(), spec)
+		if err != nil {
+			log.Info("skip exporter", "index", i, "error", err.Error())
+			continue
+		}
+		exporters = append(exporters, exporter)
+	}
+	if len(exporters) == 0 {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), exportDeadline)
+	go func() {
+		defer cancel()
+		if err := exporters.Export(ctx, event); err != nil {
+			log.Info("export failed", "error", err.Error())
+		}
+	}()
+}
+
+// specExporter 创建 spec 中的导出器，凭证从同命名空间的 Secret 读取。
+func (e *ResultExporter) specExporter(ctx context.Context, namespace string, spec infrav1alpha1.ExporterSpec) (export.Exporter, error) {
+	creds := export.Credentials{}
+	if ref := spec.CredentialsSecretRef; ref != nil {
+		if e.Reader == nil {
+			return nil, fmt.Errorf("no reader for credentials secret %s", ref.Name)
+		}
+		var secret corev1.Secret
+		if err := e.Reader.Get(ctx, client.ObjectKey{Namespace: namespace, Name: ref.Name}, &secret); err != nil {
+			return nil, fmt.Errorf("get credentials secret %s: %w", ref.Name, err)
+		}
+		for k, v := range secret.Data {
+			creds[k] = string(v)
+		}
+	}
+	return export.New(spec, creds)
+}
@@ -0,0 +1,115 @@
+package shared
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	infrav1alpha1 "github.com/lunz1207/testplane/api/v1alpha1"
+)
+
+// 故障注入动作。
+const (
+	InjectActionFail  = "fail"
+	InjectActionPass  = "pass"
+	InjectActionDelay = "delay"
+)
+
+// maxInjectedDelay 单次注入延迟上限，避免阻塞 worker 过久。
+const maxInjectedDelay = 30 * time.Second
+
+// injectedMarker 注入结果的 Message 前缀，便于在状态与事件中区分。
+const injectedMarker = "[injected]"
+
+// Injection 单个期望的故障注入配置。
+type Injection struct {
+	Action string
+	Delay  time.Duration
+}
+
+// FailureInjections 按期望函数名索引的故障注入配置。
+type FailureInjections map[string]Injection
+
+type failureInjectionKey struct{}
+
+// ParseFailureInjections 解析故障注入注解。
+// 格式：<function>=<action>[,<function>=<action>...]，action 为 fail、pass 或 delay:<duration>。
+func ParseFailureInjections(value string) (FailureInjections, error) {
+	injections := FailureInjections{}
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		name, action, ok := strings.Cut(item, "=")
+		name, action = strings.TrimSpace(name), strings.TrimSpace(action)
+		if !ok || name == "" {
+			return nil, fmt.Errorf("invalid injection %q, expected <function>=<action>", item)
+		}
+
+		switch {
+		case action == InjectActionFail, action == InjectActionPass:
+			injections[name] = Injection{Action: action}
+		case strings.HasPrefix(action, InjectActionDelay+":"):
+			d, err := time.ParseDuration(strings.TrimPrefix(action, InjectActionDelay+":"))
+			if err != nil || d < 0 {
+				return nil, fmt.Errorf("invalid delay for %s: %q", name, action)
+			}
+			injections[name] = Injection{Action: InjectActionDelay, Delay: min(d, maxInjectedDelay)}
+		default:
+			return nil, fmt.Errorf("unknown injection action %q for %s", action, name)
+		}
+	}
+	return injections, nil
+}
+
+// ContextWithFailureInjection 从对象注解读取故障注入配置并放入 context。
+// 注解不存在时原样返回；解析失败时返回错误，调用方可记录后忽略。
+func ContextWithFailureInjection(ctx context.Context, annotations map[string]string) (context.Context, error) {
+	value, ok := annotations[infrav1alpha1.AnnotationInjectExpectation]
+	if !ok {
+		return ctx, nil
+	}
+	injections, err := ParseFailureInjections(value)
+	if err != nil {
+		return ctx, err
+	}
+	return context.WithValue(ctx, failureInjectionKey{}, injections), nil
+}
+
+// FailureInjectionFromContext 返回 context 中的故障注入配置（可能为 nil）。
+func FailureInjectionFromContext(ctx context.Context) FailureInjections {
+	injections, _ := ctx.Value(failureInjectionKey{}).(FailureInjections)
+	return injections
+}
+
+// apply 对期望应用注入：fail/pass 直接返回注入结果，delay 等待后返回 nil 继续正常执行。
+// 等待期间 ctx 被取消（如控制器停止）时提前结束，返回注入结果与 ctx 的错误；ctx 为 nil 时不可取消。
+func (i Injection) apply(ctx context.Context, exp infrav1alpha1.Expectation) (*infrav1alpha1.ExpectationResult, error) {
+	result := &infrav1alpha1.ExpectationResult{
+		Expect: exp.Function,
+		Params: normalizeParams(exp.Params),
+	}
+	switch i.Action {
+	case InjectActionFail:
+		result.Message = injectedMarker + " forced failure"
+		return result, nil
+	case InjectActionPass:
+		result.Passed = true
+		return result, nil
+	case InjectActionDelay:
+		if ctx == nil {
+			ctx = context.Background()
+		}
+		timer := time.NewTimer(i.Delay)
+		defer timer.Stop()
+		select {
+		case <-ctx.Done():
+			result.Message = fmt.Sprintf("%s delay interrupted: %v", injectedMarker, ctx.Err())
+			return result, ctx.Err()
+		case <-timer.C:
+		}
+	}
+	return nil, nil
+}
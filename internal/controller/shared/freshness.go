@@ -0,0 +1,23 @@
+package shared
+
+import (
+	"context"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// 读取策略（所有控制器统一）：
+//   - 批量读取（列表、选择器匹配、期望检查的目标资源）走缓存；
+//   - 决策点（阶段转换、步骤开始/结束、终态判定，即发送 Event 之前）用 APIReader 读取 API Server 最新状态。
+//
+// status 写入后缓存可能尚未同步，下一次 reconcile 仍看到旧状态；
+// 决策点发现 API Server 上的状态已推进时跳过本次转换，避免重复事件和重复进入步骤。
+
+// ReadLatest 绕过缓存从 API Server 读取 obj 的最新版本到 latest。
+// reader 为空或读取失败时返回 false，调用方按缓存中的状态继续处理。
+func ReadLatest(ctx context.Context, reader client.Reader, obj, latest client.Object) bool {
+	if reader == nil {
+		return false
+	}
+	return reader.Get(ctx, client.ObjectKeyFromObject(obj), latest) == nil
+}
@@ -17,6 +17,8 @@ limitations under the License.
 package shared
 
 import (
+	"strings"
+
 	infrav1alpha1 "github.com/lunz1207/testplane/api/v1alpha1"
 )
 
@@ -27,11 +29,50 @@ func ToExpectationResultSummary(r *infrav1alpha1.ExpectationResult) infrav1alpha
 		msg = msg[:253] + "..."
 	}
 	return infrav1alpha1.ExpectationResultSummary{
-		Expect:  r.Expect,
-		Passed:  r.Passed,
-		Actual:  r.Actual,
-		Message: msg,
+		Name:      r.Name,
+		Expect:    r.Expect,
+		Passed:    r.Passed,
+		Actual:    r.Actual,
+		Message:   msg,
+		Error:     r.Error,
+		Severity:  r.Severity,
+		HeldSince: r.HeldSince,
+	}
+}
+
+// ExpectationLabel 返回期望结果的显示名：设置了 name 时使用 name，否则为函数名。
+func ExpectationLabel(name, expect string) string {
+	if name != "" {
+		return name
+	}
+	return expect
+}
+
+// FailedExpectationLabels 返回未通过期望的显示名（逗号分隔），全部通过时返回空字符串。
+func FailedExpectationLabels(results []infrav1alpha1.ExpectationResult) string {
+	var labels []string
+	for _, r := range results {
+		if !r.Passed && !IsWarning(r) {
+			labels = append(labels, ExpectationLabel(r.Name, r.Expect))
+		}
+	}
+	return strings.Join(labels, ", ")
+}
+
+// WarningExpectationLabels 返回未通过的 Warning 期望的显示名（逗号分隔），没有时返回空字符串。
+func WarningExpectationLabels(results []infrav1alpha1.ExpectationResult) string {
+	var labels []string
+	for _, r := range results {
+		if !r.Passed && IsWarning(r) {
+			labels = append(labels, ExpectationLabel(r.Name, r.Expect))
+		}
 	}
+	return strings.Join(labels, ", ")
+}
+
+// IsWarning 判断期望结果是否为仅提示的 Warning 级别。
+func IsWarning(r infrav1alpha1.ExpectationResult) bool {
+	return r.Severity == infrav1alpha1.ExpectationSeverityWarning
 }
 
 // ToExpectationResultSummaries 将 ExpectationResult 切片转换为摘要切片。
@@ -0,0 +1,79 @@
+package shared
+
+import (
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	infrav1alpha1 "github.com/lunz1207/testplane/api/v1alpha1"
+)
+
+// HeldSince 上次检查中某个期望开始连续成立的时间。
+type HeldSince struct {
+	// Expect 期望函数名，与本次检查的期望不一致（spec 已变化）时不沿用。
+	Expect string
+	Since  *metav1.Time
+}
+
+// HeldSinceFromResults 从上次检查的结果（按 allOf、anyOf 顺序）提取 holdSeconds 跟踪状态。
+func HeldSinceFromResults(results []infrav1alpha1.ExpectationResult) []HeldSince {
+	held := make([]HeldSince, len(results))
+	for i, result := range results {
+		held[i] = HeldSince{Expect: result.Expect, Since: result.HeldSince}
+	}
+	return held
+}
+
+// HeldSinceFromSummaries 从上次检查的结果摘要提取 holdSeconds 跟踪状态。
+func HeldSinceFromSummaries(summaries []infrav1alpha1.ExpectationResultSummary) []HeldSince {
+	held := make([]HeldSince, len(summaries))
+	for i, summary := range summaries {
+		held[i] = HeldSince{Expect: summary.Expect, Since: summary.HeldSince}
+	}
+	return held
+}
+
+// HoldChanged 判断本次检查是否开始或中断了某个期望的连续成立计时，需要持久化。
+func HoldChanged(held []HeldSince, results []infrav1alpha1.ExpectationResult) bool {
+	for i, result := range results {
+		var prev *metav1.Time
+		if i < len(held) && held[i].Expect == result.Expect {
+			prev = held[i].Since
+		}
+		if (prev == nil) != (result.HeldSince == nil) || (prev != nil && !prev.Equal(result.HeldSince)) {
+			return true
+		}
+	}
+	return false
+}
+
+// applyHold 处理期望的 holdSeconds：通过时记录（或沿用）开始连续成立的时间，
+// 未满 holdSeconds 前记为未通过；未通过时清除计时。
+// 不给出重试提示，保持正常的检查间隔以便发现计时期间的中断。
+func (runner *ExpectationRunner) applyHold(exp infrav1alpha1.Expectation, index int, result *infrav1alpha1.ExpectationResult) {
+	if !runner.trackHold || exp.HoldSeconds <= 0 {
+		return
+	}
+	if !result.Passed {
+		result.HeldSince = nil
+		return
+	}
+
+	now := time.Now()
+	since := metav1.NewTime(now)
+	if index < len(runner.HeldSince) {
+		if prev := runner.HeldSince[index]; prev.Expect == exp.Function && prev.Since != nil {
+			since = *prev.Since
+		}
+	}
+	result.HeldSince = &since
+
+	hold := time.Duration(exp.HoldSeconds) * time.Second
+	held := now.Sub(since.Time)
+	if held >= hold {
+		return
+	}
+	result.Passed = false
+	result.Message = fmt.Sprintf("condition held for %ds, requires %ds", int(held.Seconds()), exp.HoldSeconds)
+}
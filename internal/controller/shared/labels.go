@@ -0,0 +1,49 @@
+package shared
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// SyncPrefixedLabels 把对象上以 prefix 开头的标签同步为 labels：补齐缺少的、删除多余的，其余标签不变。
+// 使用只包含 metadata.labels 的 merge patch，不写入内存中已展开的 spec；成功后同步更新 obj 的标签。
+func SyncPrefixedLabels(ctx context.Context, c client.Client, obj client.Object, prefix string, labels map[string]string) error {
+	patch := map[string]interface{}{}
+	current := obj.GetLabels()
+	for k, v := range labels {
+		if current[k] != v {
+			patch[k] = v
+		}
+	}
+	for k := range current {
+		if _, ok := labels[k]; !ok && strings.HasPrefix(k, prefix) {
+			patch[k] = nil
+		}
+	}
+	if len(patch) == 0 {
+		return nil
+	}
+
+	data, err := json.Marshal(map[string]interface{}{"metadata": map[string]interface{}{"labels": patch}})
+	if err != nil {
+		return err
+	}
+	if err := c.Patch(ctx, obj.DeepCopyObject().(client.Object), client.RawPatch(types.MergePatchType, data)); err != nil {
+		return err
+	}
+	updated := make(map[string]string, len(current)+len(labels))
+	for k, v := range current {
+		if !strings.HasPrefix(k, prefix) {
+			updated[k] = v
+		}
+	}
+	for k, v := range labels {
+		updated[k] = v
+	}
+	obj.SetLabels(updated)
+	return nil
+}
@@ -0,0 +1,164 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package metrics 记录每次调和的耗时（按结果分类）、重新入队与错误次数，
+// 用于观察负载下控制器时间花在哪里，据此调整调和并发与 --<controller>-kube-api-qps/burst 客户端限流。
+//
+// 调和开始时 Start 在 context 中放入结果记录器，资源管理器、状态写入与期望检查在各自的位置调用 Mark，
+// 一次调和可能经过多个阶段，最终结果取优先级最高的一个（见 Outcome）。
+package metrics
+
+import (
+	"context"
+	stderrors "errors"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// Outcome 一次调和的结果，按优先级从低到高排列。
+type Outcome int
+
+const (
+	// OutcomeIdle 没有写入也没有等待（如终态对象、缓存未同步时的短暂 requeue）。
+	OutcomeIdle Outcome = iota
+	// OutcomePatchedStatus 只写入了 status（阶段或步骤状态推进）。
+	OutcomePatchedStatus
+	// OutcomeWaitedConvergence 等待资源收敛（创建、observedGeneration 同步或删除完成）。
+	OutcomeWaitedConvergence
+	// OutcomeWaitedExpectation 等待 readyCondition 或期望满足。
+	OutcomeWaitedExpectation
+	// OutcomeDeleted 删除了资源（测试删除或完成时的清理）。
+	OutcomeDeleted
+	// OutcomeApplied apply 了资源。
+	OutcomeApplied
+	// OutcomeError 调和返回错误。
+	OutcomeError
+)
+
+// String 返回指标标签值。
+func (o Outcome) String() string {
+	switch o {
+	case OutcomePatchedStatus:
+		return "patched_status"
+	case OutcomeWaitedConvergence:
+		return "waited_convergence"
+	case OutcomeWaitedExpectation:
+		return "waited_expectation"
+	case OutcomeDeleted:
+		return "deleted"
+	case OutcomeApplied:
+		return "applied"
+	case OutcomeError:
+		return "error"
+	default:
+		return "idle"
+	}
+}
+
+var (
+	reconcileDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "testplane_reconcile_duration_seconds",
+		Help:    "Duration of reconciles by controller and outcome (idle, patched_status, waited_convergence, waited_expectation, deleted, applied, error).",
+		Buckets: []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30},
+	}, []string{"controller", "outcome"})
+
+	reconcileRequeues = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "testplane_reconcile_requeues_total",
+		Help: "Number of reconciles that requested a requeue by controller and outcome.",
+	}, []string{"controller", "outcome"})
+
+	reconcileErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "testplane_reconcile_errors_total",
+		Help: "Number of reconcile errors by controller and type (timeout, conflict, not_found, forbidden, invalid, throttled, server, other).",
+	}, []string{"controller", "type"})
+)
+
+func init() {
+	ctrlmetrics.Registry.MustRegister(reconcileDuration, reconcileRequeues, reconcileErrors)
+}
+
+type outcomeKey struct{}
+
+// recorder 一次调和的结果记录器。
+type recorder struct {
+	mu      sync.Mutex
+	outcome Outcome
+}
+
+// Start 开始记录一次调和，返回带结果记录器的 context 与结束函数；结束函数记录耗时、重新入队与错误。
+func Start(ctx context.Context, controller string) (context.Context, func(ctrl.Result, error)) {
+	rec := &recorder{}
+	start := time.Now()
+	return context.WithValue(ctx, outcomeKey{}, rec), func(result ctrl.Result, err error) {
+		if err != nil {
+			rec.mark(OutcomeError)
+			reconcileErrors.WithLabelValues(controller, ErrorType(err)).Inc()
+		}
+		outcome := rec.get().String()
+		reconcileDuration.WithLabelValues(controller, outcome).Observe(time.Since(start).Seconds())
+		if result.Requeue || result.RequeueAfter > 0 {
+			reconcileRequeues.WithLabelValues(controller, outcome).Inc()
+		}
+	}
+}
+
+// Mark 记录调和经过的阶段，保留优先级最高的结果。context 中没有记录器时忽略。
+func Mark(ctx context.Context, outcome Outcome) {
+	if rec, ok := ctx.Value(outcomeKey{}).(*recorder); ok {
+		rec.mark(outcome)
+	}
+}
+
+func (r *recorder) mark(outcome Outcome) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if outcome > r.outcome {
+		r.outcome = outcome
+	}
+}
+
+func (r *recorder) get() Outcome {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.outcome
+}
+
+// ErrorType 返回错误的指标分类。
+func ErrorType(err error) string {
+	switch {
+	case stderrors.Is(err, context.DeadlineExceeded), apierrors.IsTimeout(err), apierrors.IsServerTimeout(err):
+		return "timeout"
+	case apierrors.IsConflict(err):
+		return "conflict"
+	case apierrors.IsNotFound(err):
+		return "not_found"
+	case apierrors.IsForbidden(err), apierrors.IsUnauthorized(err):
+		return "forbidden"
+	case apierrors.IsInvalid(err), apierrors.IsBadRequest(err):
+		return "invalid"
+	case apierrors.IsTooManyRequests(err):
+		return "throttled"
+	case apierrors.IsInternalError(err), apierrors.IsServiceUnavailable(err):
+		return "server"
+	default:
+		return "other"
+	}
+}
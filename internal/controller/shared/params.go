@@ -0,0 +1,134 @@
+package shared
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// paramRefPattern 匹配期望参数中的 ${vars.<name>} 与 ${steps.<step>.outputs.<key>} 引用。
+var paramRefPattern = regexp.MustCompile(`\$\{((?:vars|steps)\.[A-Za-z0-9_.\-]+)\}`)
+
+// stepOutputRefPattern 匹配清单中的 ${steps.<step>.outputs.<key>} 引用。
+var stepOutputRefPattern = regexp.MustCompile(`\$\{(steps\.[A-Za-z0-9_.\-]+)\}`)
+
+// ParamValues 期望参数引用的取值，键为引用名（如 vars.nodes、steps.create.outputs.name）。
+type ParamValues map[string]string
+
+// VarKey 返回测试变量的引用名。
+func VarKey(name string) string {
+	return "vars." + name
+}
+
+// StepOutputKey 返回步骤输出的引用名。
+func StepOutputKey(step, key string) string {
+	return "steps." + step + ".outputs." + key
+}
+
+// ExpandParams 替换参数中字符串值内的 ${...} 引用，不含引用时原样返回。
+// 字符串整体为单个引用且取值为数字或布尔时替换为对应的 JSON 类型（如期望的节点数）；
+// 引用未定义时返回错误。
+func ExpandParams(params runtime.RawExtension, values ParamValues) (runtime.RawExtension, error) {
+	return expandRefs(params, paramRefPattern, values, true)
+}
+
+// ExpandStepOutputRefs 替换清单中的 ${steps.<step>.outputs.<key>} 引用。与 ExpandParams 不同，替换结果总是字符串
+// （清单字段的类型由资源 schema 决定，如 ConfigMap 的 data 只接受字符串）；${env.*}、${WORKSPACE} 等其他引用保持原样。
+// 引用未定义时返回错误。
+func ExpandStepOutputRefs(manifest runtime.RawExtension, values ParamValues) (runtime.RawExtension, error) {
+	return expandRefs(manifest, stepOutputRefPattern, values, false)
+}
+
+// expandRefs 替换 JSON 字符串值中 pattern 匹配的引用，typed 时整体为单个引用的字符串按取值转换为数字或布尔。
+func expandRefs(params runtime.RawExtension, pattern *regexp.Regexp, values ParamValues, typed bool) (runtime.RawExtension, error) {
+	if !bytes.Contains(params.Raw, []byte("${")) {
+		return params, nil
+	}
+	decoded, err := decodeJSONNumber(params.Raw)
+	if err != nil {
+		return params, fmt.Errorf("invalid params: %w", err)
+	}
+
+	missing := map[string]bool{}
+	expanded := expandParamValue(decoded, pattern, values, typed, missing)
+	if len(missing) > 0 {
+		names := make([]string, 0, len(missing))
+		for name := range missing {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return params, fmt.Errorf("undefined param references: %s", strings.Join(names, ", "))
+	}
+
+	raw, err := json.Marshal(expanded)
+	if err != nil {
+		return params, fmt.Errorf("marshal expanded params: %w", err)
+	}
+	return runtime.RawExtension{Raw: raw}, nil
+}
+
+// expandParamValue 递归替换 JSON 值中的引用，未定义的引用记录到 missing。
+func expandParamValue(v interface{}, pattern *regexp.Regexp, values ParamValues, typed bool, missing map[string]bool) interface{} {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		for k, item := range val {
+			val[k] = expandParamValue(item, pattern, values, typed, missing)
+		}
+		return val
+	case []interface{}:
+		for i, item := range val {
+			val[i] = expandParamValue(item, pattern, values, typed, missing)
+		}
+		return val
+	case string:
+		return expandParamString(val, pattern, values, typed, missing)
+	default:
+		return v
+	}
+}
+
+// expandParamString 替换字符串中的引用。
+func expandParamString(s string, pattern *regexp.Regexp, values ParamValues, typed bool, missing map[string]bool) interface{} {
+	if m := pattern.FindStringSubmatch(s); typed && m != nil && m[0] == s {
+		value, ok := values[m[1]]
+		if !ok {
+			missing[m[1]] = true
+			return s
+		}
+		if scalar, err := decodeJSONNumber([]byte(value)); err == nil {
+			switch scalar.(type) {
+			case json.Number, bool:
+				return scalar
+			}
+		}
+		return value
+	}
+	return pattern.ReplaceAllStringFunc(s, func(match string) string {
+		name := pattern.FindStringSubmatch(match)[1]
+		value, ok := values[name]
+		if !ok {
+			missing[name] = true
+			return match
+		}
+		return value
+	})
+}
+
+// decodeJSONNumber 解码 JSON，数字保留为 json.Number 以免大整数丢失精度。
+func decodeJSONNumber(data []byte) (interface{}, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	if dec.More() {
+		return nil, fmt.Errorf("unexpected trailing data")
+	}
+	return v, nil
+}
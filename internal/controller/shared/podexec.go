@@ -0,0 +1,66 @@
+package shared
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/remotecommand"
+)
+
+// PodExecutor 在 Pod 容器中执行命令，标准输出写入 stdout。
+type PodExecutor interface {
+	Exec(ctx context.Context, namespace, pod, container string, command []string, stdout io.Writer) error
+}
+
+// restPodExecutor 经 API Server 的 pods/exec 子资源执行命令。
+type restPodExecutor struct {
+	config    *rest.Config
+	clientset kubernetes.Interface
+}
+
+// NewPodExecutor 创建基于 pods/exec 的执行器。
+func NewPodExecutor(config *rest.Config) (PodExecutor, error) {
+	clientset, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("create clientset: %w", err)
+	}
+	return &restPodExecutor{config: config, clientset: clientset}, nil
+}
+
+// Exec 实现 PodExecutor。命令失败时错误中附带标准错误输出（截断至 256 字符）。
+func (e *restPodExecutor) Exec(ctx context.Context, namespace, pod, container string, command []string, stdout io.Writer) error {
+	req := e.clientset.CoreV1().RESTClient().Post().
+		Resource("pods").
+		Namespace(namespace).
+		Name(pod).
+		SubResource("exec").
+		VersionedParams(&corev1.PodExecOptions{
+			Container: container,
+			Command:   command,
+			Stdout:    true,
+			Stderr:    true,
+		}, scheme.ParameterCodec)
+
+	exec, err := remotecommand.NewSPDYExecutor(e.config, "POST", req.URL())
+	if err != nil {
+		return fmt.Errorf("create executor: %w", err)
+	}
+	var stderr strings.Builder
+	if err := exec.StreamWithContext(ctx, remotecommand.StreamOptions{Stdout: stdout, Stderr: &stderr}); err != nil {
+		msg := strings.TrimSpace(stderr.String())
+		if len(msg) > 256 {
+			msg = msg[:253] + "..."
+		}
+		if msg != "" {
+			return fmt.Errorf("%w: %s", err, msg)
+		}
+		return err
+	}
+	return nil
+}
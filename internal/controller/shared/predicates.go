@@ -0,0 +1,88 @@
+package shared
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// IgnoreSelfUpdates 过滤控制器自身写入引起的更新：只改变 status（及 resourceVersion、finalizer 等元数据）
+// 或只改变 controllerAnnotations 中注解的更新。控制器是测试 status 与这些注解的唯一写入方，
+// 后续调和已由 Requeue/RequeueAfter 安排，无需再由 watch 触发。
+// spec（generation）、标签、其他注解（中止、重跑）变化与删除照常触发调和。
+func IgnoreSelfUpdates(controllerAnnotations ...string) predicate.Predicate {
+	return predicate.Or(
+		predicate.GenerationChangedPredicate{},
+		predicate.LabelChangedPredicate{},
+		predicate.Funcs{UpdateFunc: func(e event.UpdateEvent) bool {
+			return !annotationsEqualExcept(e.ObjectOld.GetAnnotations(), e.ObjectNew.GetAnnotations(), controllerAnnotations)
+		}},
+		predicate.Funcs{UpdateFunc: func(e event.UpdateEvent) bool {
+			return e.ObjectOld.GetDeletionTimestamp().IsZero() && !e.ObjectNew.GetDeletionTimestamp().IsZero()
+		}},
+	)
+}
+
+// annotationsEqualExcept 比较两组注解，忽略 ignored 中的键。
+func annotationsEqualExcept(a, b map[string]string, ignored []string) bool {
+	count := func(m map[string]string) int {
+		n := len(m)
+		for _, key := range ignored {
+			if _, ok := m[key]; ok {
+				n--
+			}
+		}
+		return n
+	}
+	if count(a) != count(b) {
+		return false
+	}
+	for key, value := range a {
+		if slices.Contains(ignored, key) {
+			continue
+		}
+		if other, ok := b[key]; !ok || other != value {
+			return false
+		}
+	}
+	return true
+}
+
+// DebounceUpdates 将 h 产生的更新事件延迟 window 后入队。
+// 工作队列对同一测试只保留最早的到期时间，窗口内的多次更新合并为一次调和；
+// 创建、删除事件立即入队。window <= 0 时原样返回 h。
+func DebounceUpdates(h handler.EventHandler, window time.Duration) handler.EventHandler {
+	if window <= 0 {
+		return h
+	}
+	return &debouncedHandler{EventHandler: h, window: window}
+}
+
+// debouncedHandler 更新事件延迟入队的 EventHandler。
+type debouncedHandler struct {
+	handler.EventHandler
+	window time.Duration
+}
+
+// Update 实现 handler.EventHandler。
+func (d *debouncedHandler) Update(ctx context.Context, e event.TypedUpdateEvent[client.Object], q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+	d.EventHandler.Update(ctx, e, delayedQueue{TypedRateLimitingInterface: q, delay: d.window})
+}
+
+// delayedQueue 把 Add 改为 AddAfter 的队列包装。
+type delayedQueue struct {
+	workqueue.TypedRateLimitingInterface[reconcile.Request]
+	delay time.Duration
+}
+
+// Add 延迟入队。
+func (q delayedQueue) Add(item reconcile.Request) {
+	q.AddAfter(item, q.delay)
+}
@@ -0,0 +1,59 @@
+package shared
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/lunz1207/testplane/internal/controller/shared/resource"
+)
+
+// identityFields 投影时始终保留的字段，保证函数仍能识别资源并读取已获取的关联资源与 apply 前快照。
+var identityFields = []string{"apiVersion", "kind", "metadata.name", "metadata.namespace", resource.RelatedKey, resource.BeforeKey}
+
+// projectFields 返回只包含指定字段路径的对象视图。
+// 子树与原对象共享（不做深拷贝），调用方不得修改返回值中的嵌套对象。
+func projectFields(obj map[string]interface{}, fields []string) map[string]interface{} {
+	if len(fields) == 0 || len(obj) == 0 {
+		return obj
+	}
+	out := make(map[string]interface{}, len(fields)+len(identityFields))
+	for _, group := range [][]string{identityFields, fields} {
+		for _, path := range group {
+			copyPath(out, obj, strings.Split(strings.TrimPrefix(path, "."), "."))
+		}
+	}
+	return out
+}
+
+// copyPath 将 src 中 path 指向的值复制到 dst 的同一路径（仅创建沿途的中间 map）。
+func copyPath(dst, src map[string]interface{}, path []string) {
+	for i, key := range path {
+		v, ok := src[key]
+		if !ok {
+			return
+		}
+		if i == len(path)-1 {
+			dst[key] = v
+			return
+		}
+		next, ok := v.(map[string]interface{})
+		if !ok {
+			return
+		}
+		// 已整体复制（与原对象共享）的子树无需再下钻，也不能写入；部分复制的子树继续补全
+		child, exists := dst[key].(map[string]interface{})
+		if exists && sameMap(child, next) {
+			return
+		}
+		if !exists {
+			child = make(map[string]interface{})
+			dst[key] = child
+		}
+		dst, src = child, next
+	}
+}
+
+// sameMap 判断两个 map 是否为同一个对象。
+func sameMap(a, b map[string]interface{}) bool {
+	return reflect.ValueOf(a).UnsafePointer() == reflect.ValueOf(b).UnsafePointer()
+}
@@ -0,0 +1,72 @@
+package shared
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Projection", func() {
+	object := func() map[string]interface{} {
+		return map[string]interface{}{
+			"apiVersion": "example.com/v1",
+			"kind":       "Cluster",
+			"metadata":   map[string]interface{}{"name": "demo", "namespace": "default", "labels": map[string]interface{}{"app": "demo"}},
+			"spec":       map[string]interface{}{"replicas": 3.0},
+			"status": map[string]interface{}{
+				"phase": "Running",
+				"nodes": map[string]interface{}{"ready": 3.0, "total": 3.0},
+				"vip":   "10.0.0.1",
+			},
+			"_related": map[string]interface{}{"nodes": []interface{}{}},
+		}
+	}
+	identity := map[string]interface{}{
+		"apiVersion": "example.com/v1",
+		"kind":       "Cluster",
+		"metadata":   map[string]interface{}{"name": "demo", "namespace": "default"},
+		"_related":   map[string]interface{}{"nodes": []interface{}{}},
+	}
+	with := func(extra map[string]interface{}) map[string]interface{} {
+		out := map[string]interface{}{}
+		for k, v := range identity {
+			out[k] = v
+		}
+		for k, v := range extra {
+			out[k] = v
+		}
+		return out
+	}
+
+	DescribeTable("should keep only the requested paths",
+		func(fields []string, expected map[string]interface{}) {
+			Expect(projectFields(object(), fields)).To(Equal(expected))
+		},
+		Entry("nested path", []string{"status.nodes.ready"},
+			with(map[string]interface{}{"status": map[string]interface{}{"nodes": map[string]interface{}{"ready": 3.0}}})),
+		Entry("leading dot", []string{".spec.replicas"},
+			with(map[string]interface{}{"spec": map[string]interface{}{"replicas": 3.0}})),
+		Entry("whole subtree then a path inside it", []string{"status", "status.nodes.ready"},
+			with(map[string]interface{}{"status": object()["status"]})),
+		Entry("path inside a subtree then the subtree", []string{"status.nodes.ready", "status"},
+			with(map[string]interface{}{"status": object()["status"]})),
+		Entry("sibling paths filling every key of a partial subtree", []string{"status.nodes.ready", "status.phase", "status.vip", "status.nodes"},
+			with(map[string]interface{}{"status": object()["status"]})),
+		Entry("metadata path next to the identity fields", []string{"metadata.labels"},
+			with(map[string]interface{}{"metadata": map[string]interface{}{"name": "demo", "namespace": "default", "labels": map[string]interface{}{"app": "demo"}}})),
+		Entry("missing path", []string{"status.conditions"}, with(map[string]interface{}{"status": map[string]interface{}{}})),
+		Entry("path through a scalar", []string{"status.phase.value"}, with(map[string]interface{}{"status": map[string]interface{}{}})),
+		Entry("missing top-level field", []string{"data"}, identity),
+	)
+
+	It("should return the object itself without fields", func() {
+		obj := object()
+		Expect(sameMap(projectFields(obj, nil), obj)).To(BeTrue())
+	})
+
+	It("should share subtrees without writing into the original object", func() {
+		obj := object()
+		out := projectFields(obj, []string{"status", "status.nodes.ready", "spec.replicas"})
+		Expect(sameMap(out["status"].(map[string]interface{}), obj["status"].(map[string]interface{}))).To(BeTrue())
+		Expect(obj).To(Equal(object()))
+	})
+})
@@ -0,0 +1,79 @@
+package shared
+
+import (
+	"context"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	infrav1alpha1 "github.com/lunz1207/testplane/api/v1alpha1"
+	"github.com/lunz1207/testplane/pkg/recording"
+)
+
+// 条件类型（写入快照的 Condition 字段）。
+const (
+	ConditionKindStep        = "StepCondition"
+	ConditionKindReady       = "ReadyCondition"
+	ConditionKindHealthCheck = "HealthCheck"
+)
+
+type recordingKey struct{}
+
+// recordingTarget context 中的录制配置。
+type recordingTarget struct {
+	ctx     context.Context
+	archive recording.Archive
+	ref     recording.ObjectRef
+}
+
+// ContextWithRecording 对象带有录制注解且控制器配置了归档时，在 context 中启用状态快照录制。
+func ContextWithRecording(ctx context.Context, archive recording.Archive, obj client.Object, kind string) context.Context {
+	if archive == nil || obj.GetAnnotations()[infrav1alpha1.AnnotationRecord] != "true" {
+		return ctx
+	}
+	target := &recordingTarget{
+		archive: archive,
+		ref:     recording.ObjectRef{Kind: kind, Namespace: obj.GetNamespace(), Name: obj.GetName()},
+	}
+	ctx = context.WithValue(ctx, recordingKey{}, target)
+	target.ctx = ctx
+	return ctx
+}
+
+// WithRecording 启用 context 中配置的状态快照录制。
+func (runner *ExpectationRunner) WithRecording(ctx context.Context) *ExpectationRunner {
+	runner.recording, _ = ctx.Value(recordingKey{}).(*recordingTarget)
+	return runner
+}
+
+// record 录制一次期望检查，录制失败只记录日志，不影响检查结果。
+func (runner *ExpectationRunner) record(
+	condition string,
+	allOf, anyOf []infrav1alpha1.Expectation,
+	state map[string]interface{},
+	results ExpectationResults,
+	err error,
+) {
+	target := runner.recording
+	if target == nil {
+		return
+	}
+
+	snapshot := recording.Snapshot{
+		Time:      time.Now(),
+		Object:    target.ref,
+		Condition: condition,
+		AllOf:     allOf,
+		AnyOf:     anyOf,
+		State:     state,
+		Results:   results.All(),
+		Passed:    err == nil && results.Passed(),
+	}
+	if err != nil {
+		snapshot.Error = err.Error()
+	}
+	if recErr := target.archive.Record(snapshot); recErr != nil {
+		logf.FromContext(target.ctx).Info("record snapshot failed", "error", recErr.Error())
+	}
+}
@@ -0,0 +1,42 @@
+package shared
+
+import (
+	"strings"
+
+	infrav1alpha1 "github.com/lunz1207/testplane/api/v1alpha1"
+)
+
+// AbortRequested 检查中止注解，返回中止原因和消息。
+func AbortRequested(annotations map[string]string) (reason, message string, ok bool) {
+	value, ok := annotations[infrav1alpha1.AnnotationAbort]
+	if !ok {
+		return "", "", false
+	}
+	value = strings.TrimSpace(value)
+	if strings.EqualFold(value, infrav1alpha1.AbortReasonPreempted) {
+		return infrav1alpha1.AbortReasonPreempted, "preempted by scheduler", true
+	}
+	return infrav1alpha1.AbortReasonManual, "aborted by user annotation", true
+}
+
+// IsTemplate 检查模板注解：模板测试只供 TestSuite 复制，控制器不执行。
+func IsTemplate(annotations map[string]string) bool {
+	return strings.EqualFold(strings.TrimSpace(annotations[infrav1alpha1.AnnotationTemplate]), "true")
+}
+
+// RerunRequested 检查重跑注解，令牌与已处理的令牌不同时返回新令牌。
+func RerunRequested(annotations map[string]string, handled string) (string, bool) {
+	token := strings.TrimSpace(annotations[infrav1alpha1.AnnotationRerun])
+	if token == "" || token == handled {
+		return "", false
+	}
+	return token, true
+}
+
+// RerunEventMessage 生成重跑事件消息。
+func RerunEventMessage(previousPhase string) string {
+	if previousPhase == "" {
+		previousPhase = "None"
+	}
+	return "Rerun requested (previous phase: " + previousPhase + ")"
+}
@@ -0,0 +1,59 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resource
+
+import "strings"
+
+// PropagateAnnotations 把测试对象上 keys 选中的注解复制到 Apply 清单，便于从资源追溯到创建它的测试与 CI 运行。
+// 以 * 结尾的键按前缀匹配；清单自身已声明的注解优先，不会被覆盖。Delete 清单不处理。
+func PropagateAnnotations(manifest *ExpandedManifest, source map[string]string, keys []string) {
+	if manifest == nil || manifest.IsDelete() || len(keys) == 0 || len(source) == 0 {
+		return
+	}
+	annotations := manifest.Object.GetAnnotations()
+	changed := false
+	for k, v := range source {
+		if !matchesAnnotationKey(k, keys) {
+			continue
+		}
+		if _, ok := annotations[k]; ok {
+			continue
+		}
+		if annotations == nil {
+			annotations = map[string]string{}
+		}
+		annotations[k] = v
+		changed = true
+	}
+	if changed {
+		manifest.Object.SetAnnotations(annotations)
+	}
+}
+
+// matchesAnnotationKey 判断注解键是否被 keys 选中。
+func matchesAnnotationKey(key string, keys []string) bool {
+	for _, k := range keys {
+		if prefix, ok := strings.CutSuffix(k, "*"); ok {
+			if strings.HasPrefix(key, prefix) {
+				return true
+			}
+		} else if key == k {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,103 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resource
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	jsonpatch "github.com/evanphx/json-patch/v5"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+
+	infrav1alpha1 "github.com/lunz1207/testplane/api/v1alpha1"
+)
+
+// BaseLoader 读取基础清单所在的 ConfigMap（与测试同命名空间）。
+type BaseLoader func(name string) (*corev1.ConfigMap, error)
+
+// ConfigMapBaseLoader 返回从集群读取 namespace 中 ConfigMap 的 BaseLoader。
+func ConfigMapBaseLoader(ctx context.Context, reader client.Reader, namespace string) BaseLoader {
+	return func(name string) (*corev1.ConfigMap, error) {
+		var cm corev1.ConfigMap
+		if err := reader.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, &cm); err != nil {
+			return nil, err
+		}
+		return &cm, nil
+	}
+}
+
+// ResolveManifest 把 base 引用的基础清单（未设置时为 Manifest）依次应用 patches，结果写回 Manifest 并清空 Base、Patches，
+// 只修改内存中的对象；之后的 ${env.*} 等替换与展开都作用于合成后的清单。未设置 base 与 patches 时不做处理。
+func ResolveManifest(ref *infrav1alpha1.ResourceRef, load BaseLoader) error {
+	if ref == nil || (ref.Base == nil && len(ref.Patches) == 0) {
+		return nil
+	}
+	raw := ref.Manifest.Raw
+	if ref.Base != nil {
+		if len(raw) > 0 || ref.Selector != nil {
+			return fmt.Errorf("base is mutually exclusive with manifest and selector")
+		}
+		data, err := loadBase(ref.Base, load)
+		if err != nil {
+			return err
+		}
+		raw = data
+	}
+	if len(raw) == 0 {
+		return fmt.Errorf("patches require a manifest or base")
+	}
+
+	if len(ref.Patches) > 0 {
+		ops, err := json.Marshal(ref.Patches)
+		if err != nil {
+			return fmt.Errorf("marshal patches: %w", err)
+		}
+		patch, err := jsonpatch.DecodePatch(ops)
+		if err != nil {
+			return fmt.Errorf("decode patches: %w", err)
+		}
+		if raw, err = patch.Apply(raw); err != nil {
+			return fmt.Errorf("apply patches: %w", err)
+		}
+	}
+	ref.Manifest = runtime.RawExtension{Raw: raw}
+	ref.Base = nil
+	ref.Patches = nil
+	return nil
+}
+
+// loadBase 读取基础清单并转换为 JSON。
+func loadBase(base *infrav1alpha1.ManifestBase, load BaseLoader) ([]byte, error) {
+	ref := base.ConfigMapRef
+	cm, err := load(ref.Name)
+	if err != nil {
+		return nil, fmt.Errorf("get base configmap %s: %w", ref.Name, err)
+	}
+	content, ok := cm.Data[ref.Key]
+	if !ok {
+		return nil, fmt.Errorf("base configmap %s has no key %q", ref.Name, ref.Key)
+	}
+	data, err := yaml.YAMLToJSON([]byte(content))
+	if err != nil {
+		return nil, fmt.Errorf("parse base %s/%s: %w", ref.Name, ref.Key, err)
+	}
+	return data, nil
+}
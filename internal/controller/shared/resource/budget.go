@@ -0,0 +1,142 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resource
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+
+	apiresource "k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	infrav1alpha1 "github.com/lunz1207/testplane/api/v1alpha1"
+)
+
+// ErrBudgetExceeded 表示 apply 该对象会超出资源预算，对象未被 apply。
+var ErrBudgetExceeded = stderrors.New("budget exceeded")
+
+// Budget 资源预算，各项为 0 表示不限制。
+type Budget struct {
+	MaxObjects   int64
+	MaxPods      int64
+	MaxCPUMillis int64
+}
+
+// IsZero 判断是否未设置任何限制。
+func (b Budget) IsZero() bool {
+	return b.MaxObjects == 0 && b.MaxPods == 0 && b.MaxCPUMillis == 0
+}
+
+// ObjectCost 估算 apply 对象的消耗：对象数固定为 1；
+// Pod 数按 Pod（1）、Deployment/StatefulSet/ReplicaSet 的 replicas（默认 1）、Job 的 completions（默认 1）、DaemonSet（1）计算；
+// CPU 为每个 Pod 的容器 CPU 请求之和（未设置 requests 时取 limits）乘以 Pod 数。其他类型不计 Pod 与 CPU。
+func ObjectCost(obj *unstructured.Unstructured) infrav1alpha1.BudgetUsage {
+	cost := infrav1alpha1.BudgetUsage{Objects: 1}
+	var podSpec map[string]interface{}
+	switch obj.GetKind() {
+	case "Pod":
+		podSpec, _, _ = unstructured.NestedMap(obj.Object, "spec")
+		cost.Pods = 1
+	case "Deployment", "StatefulSet", "ReplicaSet":
+		podSpec, _, _ = unstructured.NestedMap(obj.Object, "spec", "template", "spec")
+		cost.Pods = nestedCount(obj, "spec", "replicas")
+	case "Job":
+		podSpec, _, _ = unstructured.NestedMap(obj.Object, "spec", "template", "spec")
+		cost.Pods = nestedCount(obj, "spec", "completions")
+	case "DaemonSet":
+		podSpec, _, _ = unstructured.NestedMap(obj.Object, "spec", "template", "spec")
+		cost.Pods = 1
+	}
+	cost.CPUMillis = podCPUMillis(podSpec) * cost.Pods
+	return cost
+}
+
+// nestedCount 读取整数字段，未设置时返回 1。
+func nestedCount(obj *unstructured.Unstructured, fields ...string) int64 {
+	// 清单经 JSON 解码时数值可能为 float64
+	value, found, err := unstructured.NestedFieldNoCopy(obj.Object, fields...)
+	if err != nil || !found {
+		return 1
+	}
+	switch n := value.(type) {
+	case int64:
+		return n
+	case float64:
+		return int64(n)
+	default:
+		return 1
+	}
+}
+
+// podCPUMillis 计算 Pod 规格中各容器的 CPU 请求之和（毫核）。
+func podCPUMillis(podSpec map[string]interface{}) int64 {
+	containers, _, _ := unstructured.NestedSlice(podSpec, "containers")
+	var total int64
+	for _, c := range containers {
+		container, ok := c.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		value, found, _ := unstructured.NestedString(container, "resources", "requests", "cpu")
+		if !found {
+			value, found, _ = unstructured.NestedString(container, "resources", "limits", "cpu")
+		}
+		if !found {
+			continue
+		}
+		if q, err := apiresource.ParseQuantity(value); err == nil {
+			total += q.MilliValue()
+		}
+	}
+	return total
+}
+
+// Check 判断在已用量 used 上再加 cost 是否超出预算，超出时返回包装 ErrBudgetExceeded 的错误。
+func (b Budget) Check(used, cost infrav1alpha1.BudgetUsage) error {
+	if b.MaxObjects > 0 && used.Objects+cost.Objects > b.MaxObjects {
+		return fmt.Errorf("%w: objects %d/%d", ErrBudgetExceeded, used.Objects+cost.Objects, b.MaxObjects)
+	}
+	if b.MaxPods > 0 && used.Pods+cost.Pods > b.MaxPods {
+		return fmt.Errorf("%w: pods %d/%d", ErrBudgetExceeded, used.Pods+cost.Pods, b.MaxPods)
+	}
+	if b.MaxCPUMillis > 0 && used.CPUMillis+cost.CPUMillis > b.MaxCPUMillis {
+		return fmt.Errorf("%w: cpu %dm/%dm", ErrBudgetExceeded, used.CPUMillis+cost.CPUMillis, b.MaxCPUMillis)
+	}
+	return nil
+}
+
+// ExecuteManifestWithinBudget 在预算内执行资源清单：apply 前检查 used 加上对象消耗是否超出预算，
+// 超出时返回包装 ErrBudgetExceeded 的错误且不 apply；apply 成功后将消耗累加到 used（调用方负责持久化）。
+// 删除不计入预算。
+func (m *Manager) ExecuteManifestWithinBudget(ctx context.Context, owner client.Object, manifest *ExpandedManifest, budget Budget, used *infrav1alpha1.BudgetUsage) error {
+	if manifest == nil || manifest.IsDelete() || used == nil {
+		return m.ExecuteManifest(ctx, owner, manifest)
+	}
+	cost := ObjectCost(manifest.Object)
+	if err := budget.Check(*used, cost); err != nil {
+		return fmt.Errorf("apply %s/%s: %w", manifest.Object.GetKind(), manifest.Object.GetName(), err)
+	}
+	if err := m.ExecuteManifest(ctx, owner, manifest); err != nil {
+		return err
+	}
+	used.Objects += cost.Objects
+	used.Pods += cost.Pods
+	used.CPUMillis += cost.CPUMillis
+	return nil
+}
@@ -0,0 +1,162 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resource
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	infrav1alpha1 "github.com/lunz1207/testplane/api/v1alpha1"
+	"github.com/lunz1207/testplane/internal/controller/shared/logging"
+	"github.com/lunz1207/testplane/internal/controller/shared/metrics"
+)
+
+// DefaultCleanupGrace 未配置 cleanup 等待时长时，每个资源删除完成的最长等待时间。
+const DefaultCleanupGrace = 30 * time.Second
+
+// CleanupGrace 返回 Kind 的删除等待时长：graceSeconds 中的配置优先，其次 defaultGraceSeconds，最后 DefaultCleanupGrace。
+func CleanupGrace(spec *infrav1alpha1.CleanupSpec, kind string) time.Duration {
+	if spec == nil {
+		return DefaultCleanupGrace
+	}
+	if seconds, ok := spec.GraceSeconds[kind]; ok {
+		return time.Duration(seconds) * time.Second
+	}
+	if spec.DefaultGraceSeconds != nil {
+		return time.Duration(*spec.DefaultGraceSeconds) * time.Second
+	}
+	return DefaultCleanupGrace
+}
+
+// cleanupItem 待删除的资源及其当前元数据。
+type cleanupItem struct {
+	obj  *unstructured.Unstructured
+	meta *metav1.PartialObjectMetadata
+}
+
+// DeleteInOrder 按依赖顺序删除 owner 创建的资源，objs 按创建顺序给出。
+// 删除顺序为创建的相反顺序，并保证依赖方（ownerReferences 指向列表中另一个资源）先于其 owner 删除。
+// 每次只处理一个资源：发起删除后返回 false，下一次调和时该资源仍在删除中且未超过 Kind 的等待时长则继续等待，
+// 超过后不再等待（交给 GC）并处理下一个。全部资源已删除或已放弃等待时返回 true。
+// 不存在或不属于 owner 的资源跳过，不做删除。该方法无状态，进度完全由集群中的对象推导，控制器重启后可继续。
+func (m *Manager) DeleteInOrder(ctx context.Context, owner client.Object, objs []*unstructured.Unstructured, spec *infrav1alpha1.CleanupSpec) (bool, error) {
+	log := logf.FromContext(ctx)
+
+	items, err := m.ownedItems(ctx, owner, objs)
+	if err != nil {
+		return false, err
+	}
+	for _, item := range orderForDeletion(items) {
+		kind, name := item.obj.GetKind(), item.obj.GetName()
+		if deleting := item.meta.GetDeletionTimestamp(); deleting != nil {
+			grace := CleanupGrace(spec, kind)
+			if time.Since(deleting.Time) < grace {
+				logging.WaitingFor(log, "resource deletion", "targetKind", kind, "targetName", name)
+				metrics.Mark(ctx, metrics.OutcomeWaitedConvergence)
+				return false, nil
+			}
+			log.Info("resource deletion exceeded grace period, leaving it to GC",
+				"targetKind", kind, "targetName", name, "grace", grace.String())
+			continue
+		}
+
+		logging.ResourceDeleting(log, kind, name)
+		writer, err := m.writer(ctx)
+		if err != nil {
+			return false, err
+		}
+		if err := writer.Delete(ctx, item.meta, client.PropagationPolicy(metav1.DeletePropagationBackground)); err != nil {
+			if errors.IsNotFound(err) {
+				continue
+			}
+			return false, fmt.Errorf("delete resource %s/%s: %w", kind, name, classifyError(err))
+		}
+		metrics.Mark(ctx, metrics.OutcomeDeleted)
+		return false, nil
+	}
+	return true, nil
+}
+
+// ownedItems 读取资源的元数据，跳过不存在、不属于 owner 或重复的资源，保持给定顺序。
+func (m *Manager) ownedItems(ctx context.Context, owner client.Object, objs []*unstructured.Unstructured) ([]cleanupItem, error) {
+	seen := make(map[string]bool, len(objs))
+	var items []cleanupItem
+	for _, obj := range objs {
+		if obj.GetNamespace() == "" {
+			obj.SetNamespace(owner.GetNamespace())
+		}
+		key := objectRefKey(obj)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		meta, err := getMetadata(ctx, m.reader(), obj)
+		if errors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("get %s/%s for cleanup: %w", obj.GetKind(), obj.GetName(), err)
+		}
+		if !ownedByUID(meta.GetOwnerReferences(), owner.GetUID()) {
+			continue
+		}
+		items = append(items, cleanupItem{obj: obj, meta: meta})
+	}
+	return items, nil
+}
+
+// orderForDeletion 返回删除顺序：按创建的相反顺序，每个资源之前先删除列表中依赖它的资源（递归）；
+// 循环依赖时已访问的资源不再重复加入。
+func orderForDeletion(items []cleanupItem) []cleanupItem {
+	ordered := make([]cleanupItem, 0, len(items))
+	visited := make(map[types.UID]bool, len(items))
+	var visit func(item cleanupItem)
+	visit = func(item cleanupItem) {
+		if visited[item.meta.GetUID()] {
+			return
+		}
+		visited[item.meta.GetUID()] = true
+		for i := len(items) - 1; i >= 0; i-- {
+			if ownedByUID(items[i].meta.GetOwnerReferences(), item.meta.GetUID()) {
+				visit(items[i])
+			}
+		}
+		ordered = append(ordered, item)
+	}
+	for i := len(items) - 1; i >= 0; i-- {
+		visit(items[i])
+	}
+	return ordered
+}
+
+// ownedByUID 判断 ownerReferences 中是否包含指定 UID。
+func ownedByUID(refs []metav1.OwnerReference, uid types.UID) bool {
+	for _, ref := range refs {
+		if ref.UID == uid {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,108 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resource
+
+import (
+	stderrors "errors"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+)
+
+// ErrorClass 资源写入错误的分类，调用方据此决定步骤原因与是否重试。
+type ErrorClass string
+
+const (
+	// ErrorClassForbidden 身份无权执行该操作。
+	ErrorClassForbidden ErrorClass = "Forbidden"
+	// ErrorClassInvalid 对象不符合 schema 或字段校验（Invalid、BadRequest）。
+	ErrorClassInvalid ErrorClass = "Invalid"
+	// ErrorClassConflict 与其他写入者冲突（Conflict、AlreadyExists）。
+	ErrorClassConflict ErrorClass = "Conflict"
+	// ErrorClassWebhookDenied 准入 Webhook 拒绝了请求。
+	ErrorClassWebhookDenied ErrorClass = "WebhookDenied"
+	// ErrorClassTransient API Server 暂时不可用（超时、限流、5xx）。
+	ErrorClassTransient ErrorClass = "Transient"
+	// ErrorClassUnknown 其他错误。
+	ErrorClassUnknown ErrorClass = "Unknown"
+)
+
+// Retryable 判断该类错误重试后是否可能成功。
+func (c ErrorClass) Retryable() bool {
+	return c == ErrorClassConflict || c == ErrorClassTransient
+}
+
+// ClassifiedError Manager 写入（apply、删除、dry-run）返回的已分类错误，Unwrap 得到 API Server 的原始错误。
+type ClassifiedError struct {
+	Class ErrorClass
+	Err   error
+}
+
+// Error 实现 error。
+func (e *ClassifiedError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap 返回原始错误，apierrors.IsXxx 等判断仍然有效。
+func (e *ClassifiedError) Unwrap() error {
+	return e.Err
+}
+
+// classifyError 把 API Server 返回的错误包装为 ClassifiedError，nil 保持为 nil。
+func classifyError(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &ClassifiedError{Class: Classify(err), Err: err}
+}
+
+// Classify 返回错误的分类：错误链中有 ClassifiedError 时使用其分类，否则按 API 状态判断。
+// 准入 Webhook 的拒绝可能以 400/403/422 等状态码返回，优先按消息识别。
+func Classify(err error) ErrorClass {
+	if err == nil {
+		return ""
+	}
+	var classified *ClassifiedError
+	if stderrors.As(err, &classified) {
+		return classified.Class
+	}
+	switch {
+	case isWebhookDenial(err):
+		return ErrorClassWebhookDenied
+	case errors.IsForbidden(err):
+		return ErrorClassForbidden
+	case errors.IsInvalid(err), errors.IsBadRequest(err):
+		return ErrorClassInvalid
+	case errors.IsConflict(err), errors.IsAlreadyExists(err):
+		return ErrorClassConflict
+	case errors.IsTimeout(err), errors.IsServerTimeout(err), errors.IsTooManyRequests(err),
+		errors.IsServiceUnavailable(err), errors.IsInternalError(err), errors.IsUnexpectedServerError(err):
+		return ErrorClassTransient
+	default:
+		return ErrorClassUnknown
+	}
+}
+
+// isWebhookDenial 判断错误是否为准入 Webhook 的拒绝（消息形如 admission webhook "x" denied the request: ...）。
+func isWebhookDenial(err error) bool {
+	var status errors.APIStatus
+	if !stderrors.As(err, &status) {
+		return false
+	}
+	msg := status.Status().Message
+	return strings.Contains(msg, "admission webhook") && strings.Contains(msg, "denied the request")
+}
@@ -0,0 +1,37 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resource
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+
+	infrav1alpha1 "github.com/lunz1207/testplane/api/v1alpha1"
+)
+
+// ManifestHash 返回展开后清单的哈希，不含哈希注解本身。
+// 与 apply 台账及资源上的 infra.testplane.io/manifest-hash 注解一致，`testplane render` 输出同一哈希便于比对。
+func ManifestHash(manifest *ExpandedManifest) string {
+	obj := manifest.Object.DeepCopy()
+	annotations := obj.GetAnnotations()
+	delete(annotations, infrav1alpha1.AnnotationManifestHash)
+	obj.SetAnnotations(annotations)
+	data, _ := json.Marshal(obj.Object)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])[:16]
+}
@@ -0,0 +1,86 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resource
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/rest"
+	"k8s.io/utils/lru"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// identityKey context 中写入身份的键。
+type identityKey struct{}
+
+// WithIdentity 返回以 username 身份执行资源写入（apply、删除、dry-run）的 context，
+// Manager 设置了 Impersonator 时生效；读取仍使用控制器自身的身份。
+func WithIdentity(ctx context.Context, username string) context.Context {
+	if username == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, identityKey{}, username)
+}
+
+// identityFrom 返回 context 中的写入身份，未设置时返回空字符串。
+func identityFrom(ctx context.Context) string {
+	username, _ := ctx.Value(identityKey{}).(string)
+	return username
+}
+
+// Impersonator 返回以 username 身份访问 API Server 的客户端。
+type Impersonator func(username string) (client.Client, error)
+
+// maxImpersonatedClients Impersonator 缓存的客户端上限。每个测试使用独立的 ServiceAccount，
+// 按最近使用淘汰，已结束或删除的测试的客户端不会一直留在内存中。
+const maxImpersonatedClients = 128
+
+// NewImpersonator 基于控制器的 rest.Config 创建 Impersonator，客户端按身份缓存，
+// 最多保留 maxImpersonatedClients 个，超出时淘汰最久未使用的。
+// 控制器需要对 ServiceAccount 的 impersonate 权限。
+func NewImpersonator(cfg *rest.Config, scheme *runtime.Scheme, mapper meta.RESTMapper) Impersonator {
+	var mu sync.Mutex
+	clients := lru.New(maxImpersonatedClients)
+	return func(username string) (client.Client, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if c, ok := clients.Get(username); ok {
+			return c.(client.Client), nil
+		}
+		impersonated := rest.CopyConfig(cfg)
+		impersonated.Impersonate = rest.ImpersonationConfig{UserName: username}
+		c, err := client.New(impersonated, client.Options{Scheme: scheme, Mapper: mapper})
+		if err != nil {
+			return nil, fmt.Errorf("create client impersonating %s: %w", username, err)
+		}
+		clients.Add(username, c)
+		return c, nil
+	}
+}
+
+// writer 返回执行资源写入的客户端：context 带有身份且设置了 Impersonator 时以该身份写入。
+func (m *Manager) writer(ctx context.Context) (client.Client, error) {
+	username := identityFrom(ctx)
+	if username == "" || m.Impersonator == nil {
+		return m.Client, nil
+	}
+	return m.Impersonator(username)
+}
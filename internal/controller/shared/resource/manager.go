@@ -20,37 +20,113 @@ import (
 	"context"
 	stderrors "errors"
 	"fmt"
+	"strings"
+	"sync"
 
 	"k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 
+	infrav1alpha1 "github.com/lunz1207/testplane/api/v1alpha1"
 	"github.com/lunz1207/testplane/internal/controller/shared/logging"
+	"github.com/lunz1207/testplane/internal/controller/shared/metrics"
 )
 
 // ErrResourceNotReady 表示资源的 Controller 尚未处理完最新的 spec。
 // 调用方应该 requeue 等待，而不是将此视为失败。
 var ErrResourceNotReady = stderrors.New("resource not ready: observedGeneration < generation")
 
+// ErrWaitingBefore 表示批量执行在 waitBefore 清单处等待之前的清单收敛。
+// 调用方应该 requeue 后重新执行整批清单，而不是将此视为失败。
+var ErrWaitingBefore = stderrors.New("waiting for preceding manifests to converge")
+
+// ErrObjectTerminating 表示目标对象仍处于删除中（已设置 deletionTimestamp）。
+// 对正在删除的对象执行 SSA 会与 GC 竞争，调用方应等待删除完成后再重新 apply。
+var ErrObjectTerminating = stderrors.New("recreating a terminating object")
+
 // Manager 提供资源管理功能（应用、删除、等待、状态收集）。
 type Manager struct {
 	Client     client.Client
 	Scheme     *runtime.Scheme
 	FieldOwner string
 	APIReader  client.Reader // 用于 waitResourcesConverge 绕过缓存检查收敛状态
+	// Impersonator 按 context 中的身份（WithIdentity）创建写入客户端，nil 时总是使用 Client。
+	Impersonator Impersonator
+
+	// deletedUIDs 记录已发起删除的对象 UID（key 为 kind/namespace/name）。
+	// 用于判断同名对象是否已被重新创建（UID 变化即视为旧对象已删除）。
+	mu          sync.Mutex
+	deletedUIDs map[string]types.UID
+	// tombstones 删除前保存的对象快照（key 为 ownerUID/kind/namespace/name），只为声明 Tombstone 的清单保存。
+	tombstones map[string]map[string]interface{}
+	// snapshots apply 前保存的对象快照（key 同 tombstones），只为声明 Snapshot 的清单保存；
+	// 值为 nil 表示 apply 前对象不存在。
+	snapshots map[string]map[string]interface{}
 }
 
 // NewManager 创建一个新的资源管理器。
 func NewManager(c client.Client, scheme *runtime.Scheme, fieldOwner string, apiReader client.Reader) *Manager {
 	return &Manager{
-		Client:     c,
-		Scheme:     scheme,
-		FieldOwner: fieldOwner,
-		APIReader:  apiReader,
+		Client:      c,
+		Scheme:      scheme,
+		FieldOwner:  fieldOwner,
+		APIReader:   apiReader,
+		deletedUIDs: make(map[string]types.UID),
+	}
+}
+
+// reader 返回用于关键决策的读取器，优先使用 APIReader 绕过缓存。
+func (m *Manager) reader() client.Reader {
+	if m.APIReader != nil {
+		return m.APIReader
+	}
+	return m.Client
+}
+
+// objectRefKey 生成对象的唯一标识（kind/namespace/name）。
+func objectRefKey(obj *unstructured.Unstructured) string {
+	return fmt.Sprintf("%s/%s/%s", obj.GetKind(), obj.GetNamespace(), obj.GetName())
+}
+
+// recordDeletedUID 记录已发起删除的对象 UID。
+func (m *Manager) recordDeletedUID(obj *unstructured.Unstructured, uid types.UID) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.deletedUIDs == nil {
+		m.deletedUIDs = make(map[string]types.UID)
 	}
+	m.deletedUIDs[objectRefKey(obj)] = uid
+}
+
+// getMetadata 只读取对象的元数据（PartialObjectMetadata），不传输 spec/status，
+// 适用于只需判断存在性、UID、deletionTimestamp 的场景，避免读取大对象（如平台 Cluster CR）的完整内容。
+// 调用方应传入 m.reader()：经缓存 client 读取 PartialObjectMetadata 会为该 GVK 建立集群范围的 metadata informer，
+// 且缓存尚未同步刚 apply 的对象时会误判为不存在。
+func getMetadata(ctx context.Context, reader client.Reader, obj *unstructured.Unstructured) (*metav1.PartialObjectMetadata, error) {
+	meta := &metav1.PartialObjectMetadata{}
+	meta.SetGroupVersionKind(obj.GroupVersionKind())
+	key := client.ObjectKey{Namespace: obj.GetNamespace(), Name: obj.GetName()}
+	return meta, reader.Get(ctx, key, meta)
+}
+
+// deletedUID 返回对象最近一次被删除时的 UID（未记录时返回空）。
+func (m *Manager) deletedUID(obj *unstructured.Unstructured) types.UID {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.deletedUIDs[objectRefKey(obj)]
+}
+
+// forgetDeletedUID 清除对象的删除记录（旧对象已彻底删除或已被替换）。
+func (m *Manager) forgetDeletedUID(obj *unstructured.Unstructured) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.deletedUIDs, objectRefKey(obj))
 }
 
 // ExecuteManifest 执行单个资源清单（Apply 或 Delete）。
@@ -67,11 +143,21 @@ func (m *Manager) ExecuteManifest(ctx context.Context, owner client.Object, mani
 	}
 
 	if manifest.IsDelete() {
+		if manifest.Tombstone {
+			if err := m.saveTombstone(ctx, owner, manifest.Object); err != nil {
+				return err
+			}
+		}
 		if err := m.DeleteObject(ctx, manifest.Object); err != nil {
 			return fmt.Errorf("failed to delete %s/%s: %w",
 				manifest.Object.GetKind(), manifest.Object.GetName(), err)
 		}
 	} else {
+		if manifest.Snapshot {
+			if err := m.saveSnapshot(ctx, owner, manifest.Object); err != nil {
+				return err
+			}
+		}
 		if err := m.ApplyObject(ctx, owner, manifest.Object); err != nil {
 			return fmt.Errorf("failed to apply %s/%s: %w",
 				manifest.Object.GetKind(), manifest.Object.GetName(), err)
@@ -82,22 +168,18 @@ func (m *Manager) ExecuteManifest(ctx context.Context, owner client.Object, mani
 
 // ExecuteManifests 批量执行资源清单（Apply 或 Delete）。
 // 所有资源必须与 owner 在同一命名空间，通过 ownerRef 管理生命周期。
+// 设置 WaitBefore 的清单在之前的清单全部收敛后才执行，尚未收敛时返回 ErrWaitingBefore，
+// 调用方应稍后重试（已执行的清单重复执行是幂等的）。
 func (m *Manager) ExecuteManifests(ctx context.Context, owner client.Object, manifests []ExpandedManifest) error {
-	log := logf.FromContext(ctx)
-	for _, manifest := range manifests {
-		if manifest.IsDelete() {
-			logging.ResourceDeleting(log, manifest.Object.GetKind(), manifest.Object.GetName())
-			if err := m.DeleteObject(ctx, manifest.Object); err != nil {
-				return fmt.Errorf("failed to delete %s/%s: %w",
-					manifest.Object.GetKind(), manifest.Object.GetName(), err)
-			}
-		} else {
-			logging.ResourceApplying(log, manifest.Object.GetKind(), manifest.Object.GetName())
-			if err := m.ApplyObject(ctx, owner, manifest.Object); err != nil {
-				return fmt.Errorf("failed to apply %s/%s: %w",
-					manifest.Object.GetKind(), manifest.Object.GetName(), err)
+	for i, manifest := range manifests {
+		if manifest.WaitBefore && i > 0 {
+			if err := m.WaitForManifests(ctx, manifests[:i]); err != nil {
+				return fmt.Errorf("%w: %s/%s: %v", ErrWaitingBefore, manifest.Object.GetKind(), manifest.Object.GetName(), err)
 			}
 		}
+		if err := m.ExecuteManifest(ctx, owner, &manifests[i]); err != nil {
+			return err
+		}
 	}
 	return nil
 }
@@ -106,6 +188,12 @@ func (m *Manager) ExecuteManifests(ctx context.Context, owner client.Object, man
 // 使用 Server-Side Apply 统一处理，无需预先检查资源是否存在。
 // 资源通过 OwnerReference 关联到 owner，删除时 GC 自动清理。
 func (m *Manager) ApplyObject(ctx context.Context, owner client.Object, obj *unstructured.Unstructured) error {
+	return m.ApplyObjectAs(ctx, owner, obj, m.FieldOwner, false)
+}
+
+// ApplyObjectAs 以指定的字段管理者应用单个资源，force 为 true 时强制获取冲突字段的所有权。
+// 用于并发竞争步骤验证不同管理者之间的冲突处理。
+func (m *Manager) ApplyObjectAs(ctx context.Context, owner client.Object, obj *unstructured.Unstructured, fieldOwner string, force bool) error {
 	log := logf.FromContext(ctx)
 
 	namespace := obj.GetNamespace()
@@ -120,6 +208,11 @@ func (m *Manager) ApplyObject(ctx context.Context, owner client.Object, obj *uns
 			obj.GetKind(), obj.GetName(), namespace, owner.GetNamespace())
 	}
 
+	// 此前删除过的同名对象：等待其删除完成或已被新对象替换后再 apply，不对即将消失的旧对象写入
+	if err := m.ensureNotTerminating(ctx, obj); err != nil {
+		return err
+	}
+
 	// 设置 OwnerReference，owner 删除时 GC 自动清理资源
 	if err := controllerutil.SetOwnerReference(owner, obj, m.Scheme); err != nil {
 		return fmt.Errorf("set owner reference for %s/%s: %w", obj.GetKind(), obj.GetName(), err)
@@ -128,32 +221,98 @@ func (m *Manager) ApplyObject(ctx context.Context, owner client.Object, obj *uns
 	logging.ResourceApplying(log, obj.GetKind(), obj.GetName())
 
 	// 使用 Server-Side Apply
-	if err := m.Client.Patch(ctx, obj, client.Apply,
-		client.FieldOwner(m.FieldOwner)); err != nil {
-		return fmt.Errorf("apply resource %s/%s via SSA: %w", obj.GetKind(), obj.GetName(), err)
+	opts := []client.PatchOption{client.FieldOwner(fieldOwner)}
+	if force {
+		opts = append(opts, client.ForceOwnership)
+	}
+	writer, err := m.writer(ctx)
+	if err != nil {
+		return err
+	}
+	if err := writer.Patch(ctx, obj, client.Apply, opts...); err != nil {
+		return fmt.Errorf("apply resource %s/%s via SSA: %w", obj.GetKind(), obj.GetName(), classifyError(err))
+	}
+	if err := m.checkTerminating(ctx, obj); err != nil {
+		return err
 	}
 
 	logging.ResourceApplied(log, obj.GetKind(), obj.GetName())
+	metrics.Mark(ctx, metrics.OutcomeApplied)
+
+	return nil
+}
+
+// DryRunApply 以服务端 dry-run 的 Server-Side Apply 校验对象（fieldValidation=Strict），不持久化任何变更。
+// 清单含未知字段或重复字段时 API Server 返回 BadRequest，不符合 schema 时返回 Invalid。
+func (m *Manager) DryRunApply(ctx context.Context, owner client.Object, obj *unstructured.Unstructured) error {
+	obj = obj.DeepCopy()
+	if obj.GetNamespace() == "" {
+		obj.SetNamespace(owner.GetNamespace())
+	}
+	if err := controllerutil.SetOwnerReference(owner, obj, m.Scheme); err != nil {
+		return fmt.Errorf("set owner reference for %s/%s: %w", obj.GetKind(), obj.GetName(), err)
+	}
+	writer, err := m.writer(ctx)
+	if err != nil {
+		return err
+	}
+	return classifyError(writer.Patch(ctx, obj, client.Apply, client.FieldOwner(m.FieldOwner), client.DryRunAll, client.FieldValidation("Strict")))
+}
+
+// ensureNotTerminating 在 apply 前检查此前删除过的同名对象：只有记录了删除 UID 时才经 APIReader 读取元数据。
+// 对象已不存在或 UID 已变化（被新对象替换）时清除删除记录并允许 apply；
+// 仍是旧对象或处于删除中时返回包装 ErrObjectTerminating 的错误，调用方等待删除完成后重新 apply。
+func (m *Manager) ensureNotTerminating(ctx context.Context, obj *unstructured.Unstructured) error {
+	uid := m.deletedUID(obj)
+	if uid == "" {
+		return nil
+	}
+	existing, err := getMetadata(ctx, m.reader(), obj)
+	if errors.IsNotFound(err) {
+		m.forgetDeletedUID(obj)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("check %s/%s before re-apply: %w", obj.GetKind(), obj.GetName(), err)
+	}
+	if existing.GetUID() == uid || existing.GetDeletionTimestamp() != nil {
+		return terminatingError(ctx, obj, existing.GetUID())
+	}
+	m.forgetDeletedUID(obj)
+	return nil
+}
 
+// checkTerminating 根据 SSA 响应（apply 后对象的最新状态）兜底判断同名对象是否仍处于删除中：
+// apply 前未记录删除（如对象由其他控制器删除）时，响应带 deletionTimestamp 说明本次 apply 写入了即将消失的对象，
+// 返回包装 ErrObjectTerminating 的错误，调用方等待删除完成后重新 apply；
+// 否则若 UID 与记录的已删除对象不同，说明已是新对象，清除删除记录。
+func (m *Manager) checkTerminating(ctx context.Context, obj *unstructured.Unstructured) error {
+	if obj.GetDeletionTimestamp() != nil {
+		return terminatingError(ctx, obj, obj.GetUID())
+	}
+	if uid := m.deletedUID(obj); uid != "" && uid != obj.GetUID() {
+		m.forgetDeletedUID(obj)
+	}
 	return nil
 }
 
+// terminatingError 记录等待日志并返回包装 ErrObjectTerminating 的错误。
+func terminatingError(ctx context.Context, obj *unstructured.Unstructured, uid types.UID) error {
+	logging.WaitingFor(logf.FromContext(ctx), "deletion before re-apply",
+		"targetKind", obj.GetKind(),
+		"targetName", obj.GetName(),
+		"uid", uid)
+	return fmt.Errorf("%w: %s/%s (uid=%s) is still being deleted, waiting before re-apply",
+		ErrObjectTerminating, obj.GetKind(), obj.GetName(), uid)
+}
+
 // DeleteObject 删除单个资源。
 // 如果资源不存在，视为已删除成功。
 func (m *Manager) DeleteObject(ctx context.Context, obj *unstructured.Unstructured) error {
 	log := logf.FromContext(ctx)
 
-	// 先检查资源是否存在
-	existing := &unstructured.Unstructured{}
-	existing.SetAPIVersion(obj.GetAPIVersion())
-	existing.SetKind(obj.GetKind())
-
-	key := client.ObjectKey{
-		Namespace: obj.GetNamespace(),
-		Name:      obj.GetName(),
-	}
-
-	err := m.Client.Get(ctx, key, existing)
+	// 先经 APIReader 检查资源是否存在（只需元数据）：缓存可能尚未同步刚 apply 的对象
+	existing, err := getMetadata(ctx, m.reader(), obj)
 	if errors.IsNotFound(err) {
 		log.V(logging.LevelVerbose).Info("resource already deleted",
 			"targetKind", obj.GetKind(),
@@ -166,31 +325,156 @@ func (m *Manager) DeleteObject(ctx context.Context, obj *unstructured.Unstructur
 
 	logging.ResourceDeleting(log, obj.GetKind(), obj.GetName())
 
-	if err := m.Client.Delete(ctx, existing); err != nil {
-		return fmt.Errorf("delete resource %s/%s: %w", obj.GetKind(), obj.GetName(), err)
+	writer, err := m.writer(ctx)
+	if err != nil {
+		return err
+	}
+	if err := writer.Delete(ctx, existing); err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("delete resource %s/%s: %w", obj.GetKind(), obj.GetName(), classifyError(err))
+	}
+	m.recordDeletedUID(obj, existing.GetUID())
+	metrics.Mark(ctx, metrics.OutcomeDeleted)
+
+	return nil
+}
+
+// DeleteOwnedObject 删除 owner 创建的单个资源：ownerRef 不指向 owner 的同名资源保持不动，
+// 资源或其 CRD 不存在时视为已删除。用于关闭某项功能后清理此前生成的资源。
+func (m *Manager) DeleteOwnedObject(ctx context.Context, owner client.Object, obj *unstructured.Unstructured) error {
+	existing, err := getMetadata(ctx, m.reader(), obj)
+	if errors.IsNotFound(err) || apimeta.IsNoMatchError(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("get resource for deletion: %w", err)
+	}
+	if !ownedByUID(existing.GetOwnerReferences(), owner.GetUID()) {
+		return nil
+	}
+	return m.DeleteObject(ctx, obj)
+}
+
+// saveTombstone 删除前保存对象的完整状态（含关联资源）。
+// 经 APIReader 读取，不为该类型建立完整缓存；对象已不存在时保留之前的快照。
+func (m *Manager) saveTombstone(ctx context.Context, owner client.Object, obj *unstructured.Unstructured) error {
+	existing, err := m.readSnapshot(ctx, obj, "tombstone")
+	if err != nil || existing == nil {
+		return err
+	}
+	AttachRelated(ctx, m.Client, existing)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.tombstones == nil {
+		m.tombstones = make(map[string]map[string]interface{})
+	}
+	m.tombstones[ownedObjectKey(owner, obj)] = existing.Object
+	return nil
+}
+
+// Tombstone 返回 owner 删除对象前保存的快照。
+// 快照只保存在内存中，控制器重启或切主后返回 false，调用方应将其视为无法判定而非断言失败。
+func (m *Manager) Tombstone(owner client.Object, obj *unstructured.Unstructured) (map[string]interface{}, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	snapshot, ok := m.tombstones[ownedObjectKey(owner, obj)]
+	return snapshot, ok
+}
+
+// saveSnapshot apply 前保存对象的当前状态；对象尚不存在时记录 nil（创建没有"之前"）。
+func (m *Manager) saveSnapshot(ctx context.Context, owner client.Object, obj *unstructured.Unstructured) error {
+	existing, err := m.readSnapshot(ctx, obj, "snapshot")
+	if err != nil {
+		return err
 	}
 
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.snapshots == nil {
+		m.snapshots = make(map[string]map[string]interface{})
+	}
+	if existing == nil {
+		m.snapshots[ownedObjectKey(owner, obj)] = nil
+		return nil
+	}
+	m.snapshots[ownedObjectKey(owner, obj)] = existing.Object
 	return nil
 }
 
+// PreApplySnapshot 返回 owner 最近一次 apply 对象前保存的快照，apply 前对象不存在时快照为 nil。
+// 与 Tombstone 相同，控制器重启或切主后返回 false。
+func (m *Manager) PreApplySnapshot(owner client.Object, obj *unstructured.Unstructured) (map[string]interface{}, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	snapshot, ok := m.snapshots[ownedObjectKey(owner, obj)]
+	return snapshot, ok
+}
+
+// ForgetOwner 清除 owner 保存的墓碑与 apply 前快照，在测试删除或进入终态时调用。
+func (m *Manager) ForgetOwner(uid types.UID) {
+	prefix := string(uid) + "/"
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for key := range m.tombstones {
+		if strings.HasPrefix(key, prefix) {
+			delete(m.tombstones, key)
+		}
+	}
+	for key := range m.snapshots {
+		if strings.HasPrefix(key, prefix) {
+			delete(m.snapshots, key)
+		}
+	}
+}
+
+// ownedObjectKey 返回 tombstones、snapshots 的键：不同测试操作同名对象时互不覆盖。
+func ownedObjectKey(owner client.Object, obj *unstructured.Unstructured) string {
+	return string(owner.GetUID()) + "/" + objectRefKey(obj)
+}
+
+// readSnapshot 经 APIReader 读取对象用于保存快照，对象不存在时返回 nil。
+func (m *Manager) readSnapshot(ctx context.Context, obj *unstructured.Unstructured, purpose string) (*unstructured.Unstructured, error) {
+	existing := &unstructured.Unstructured{}
+	existing.SetAPIVersion(obj.GetAPIVersion())
+	existing.SetKind(obj.GetKind())
+	err := m.reader().Get(ctx, client.ObjectKey{Namespace: obj.GetNamespace(), Name: obj.GetName()}, existing)
+	if errors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get %s/%s for %s: %w", obj.GetKind(), obj.GetName(), purpose, err)
+	}
+	StripManagedFields(existing)
+	return existing, nil
+}
+
 // WaitForManifest 等待单个资源清单收敛。
 func (m *Manager) WaitForManifest(ctx context.Context, manifest *ExpandedManifest) error {
 	if manifest == nil {
 		return nil
 	}
 	log := logf.FromContext(ctx)
-	if err := m.WaitForObject(ctx, manifest.Object, manifest.IsDelete()); err != nil {
+	if err := m.waitForManifest(ctx, manifest); err != nil {
 		return err
 	}
 	logging.Converged(log, manifest.Object.GetKind(), manifest.Object.GetName())
 	return nil
 }
 
+// waitForManifest 按清单的收敛判定方式等待资源收敛。
+func (m *Manager) waitForManifest(ctx context.Context, manifest *ExpandedManifest) error {
+	checkGeneration := manifest.ConvergenceMode() != infrav1alpha1.ConvergenceNone
+	return m.waitForObject(ctx, manifest.Object, manifest.IsDelete(), checkGeneration)
+}
+
 // WaitForManifests 等待资源清单收敛。
 func (m *Manager) WaitForManifests(ctx context.Context, manifests []ExpandedManifest) error {
 	log := logf.FromContext(ctx)
-	for _, manifest := range manifests {
-		if err := m.WaitForObject(ctx, manifest.Object, manifest.IsDelete()); err != nil {
+	for i := range manifests {
+		if err := m.waitForManifest(ctx, &manifests[i]); err != nil {
 			return err
 		}
 	}
@@ -201,19 +485,32 @@ func (m *Manager) WaitForManifests(ctx context.Context, manifests []ExpandedMani
 // WaitForObject 等待单个资源收敛（删除或 spec 已被处理）。
 // 收敛的定义：控制器已经处理了最新的 spec（observedGeneration >= generation）。
 func (m *Manager) WaitForObject(ctx context.Context, obj *unstructured.Unstructured, isDelete bool) error {
-	log := logf.FromContext(ctx)
+	return m.waitForObject(ctx, obj, isDelete, true)
+}
 
-	existing := &unstructured.Unstructured{}
-	existing.SetAPIVersion(obj.GetAPIVersion())
-	existing.SetKind(obj.GetKind())
+// waitForObject 等待单个资源收敛。
+// checkGeneration=false 时仅要求资源存在（用于 ConfigMap/Secret 等无 status 的资源）。
+// observedGeneration 位于 status 中，只有需要比较 generation 时才读取完整对象，
+// 删除等待与 ConvergenceNone 只读取元数据。
+func (m *Manager) waitForObject(ctx context.Context, obj *unstructured.Unstructured, isDelete, checkGeneration bool) error {
+	log := logf.FromContext(ctx)
 
-	key := client.ObjectKey{
-		Namespace: obj.GetNamespace(),
-		Name:      obj.GetName(),
+	var (
+		existing client.Object
+		full     *unstructured.Unstructured
+		err      error
+	)
+	if isDelete || !checkGeneration {
+		existing, err = getMetadata(ctx, m.reader(), obj)
+	} else {
+		full = &unstructured.Unstructured{}
+		full.SetAPIVersion(obj.GetAPIVersion())
+		full.SetKind(obj.GetKind())
+		key := client.ObjectKey{Namespace: obj.GetNamespace(), Name: obj.GetName()}
+		err = m.Client.Get(ctx, key, full)
+		existing = full
 	}
 
-	err := m.Client.Get(ctx, key, existing)
-
 	if isDelete {
 		if errors.IsNotFound(err) {
 			return nil
@@ -221,28 +518,46 @@ func (m *Manager) WaitForObject(ctx context.Context, obj *unstructured.Unstructu
 		if err != nil {
 			return err
 		}
+		// 同名对象已被重新创建（UID 变化），说明被删除的旧对象已不存在
+		if uid := m.deletedUID(obj); uid != "" && existing.GetUID() != uid {
+			return nil
+		}
 		logging.WaitingFor(log, "deletion", "targetKind", obj.GetKind(), "targetName", obj.GetName())
+		metrics.Mark(ctx, metrics.OutcomeWaitedConvergence)
 		return fmt.Errorf("resource %s/%s still exists", obj.GetKind(), obj.GetName())
 	}
 
 	// 资源尚未创建，返回 ErrResourceNotReady 让调用方 requeue
 	if errors.IsNotFound(err) {
 		logging.WaitingFor(log, "creation", "targetKind", obj.GetKind(), "targetName", obj.GetName())
-		return fmt.Errorf("%w: %s/%s not found", ErrResourceNotReady, obj.GetKind(), obj.GetName())
+		metrics.Mark(ctx, metrics.OutcomeWaitedConvergence)
+		return fmt.Errorf("%w: %s/%s not found: %w", ErrResourceNotReady, obj.GetKind(), obj.GetName(), err)
 	}
 	if err != nil {
 		return err
 	}
 
+	// 缓存中仍是正在删除的旧对象，等待新对象出现
+	if existing.GetDeletionTimestamp() != nil {
+		logging.WaitingFor(log, "recreation", "targetKind", obj.GetKind(), "targetName", obj.GetName())
+		metrics.Mark(ctx, metrics.OutcomeWaitedConvergence)
+		return fmt.Errorf("%w: %s/%s is terminating", ErrResourceNotReady, obj.GetKind(), obj.GetName())
+	}
+
+	if !checkGeneration {
+		return nil
+	}
+
 	// 检查 observedGeneration：确保控制器已处理最新 spec
-	gen := existing.GetGeneration()
-	observed, found, _ := unstructured.NestedInt64(existing.Object, "status", "observedGeneration")
+	gen := full.GetGeneration()
+	observed, found, _ := unstructured.NestedInt64(full.Object, "status", "observedGeneration")
 	if found && observed < gen {
 		logging.WaitingFor(log, "generation sync",
 			"targetKind", obj.GetKind(),
 			"targetName", obj.GetName(),
 			"generation", gen,
 			"observedGeneration", observed)
+		metrics.Mark(ctx, metrics.OutcomeWaitedConvergence)
 		return fmt.Errorf("%w: %s/%s observedGeneration=%d < generation=%d",
 			ErrResourceNotReady, obj.GetKind(), obj.GetName(), observed, gen)
 	}
@@ -251,7 +566,7 @@ func (m *Manager) WaitForObject(ctx context.Context, obj *unstructured.Unstructu
 }
 
 // GatherManifestState 获取单个资源清单的当前状态，用于期望检查。
-func (m *Manager) GatherManifestState(ctx context.Context, manifest *ExpandedManifest) (map[string]interface{}, error) {
+func (m *Manager) GatherManifestState(ctx context.Context, owner client.Object, manifest *ExpandedManifest) (map[string]interface{}, error) {
 	if manifest == nil {
 		return make(map[string]interface{}), nil
 	}
@@ -269,6 +584,7 @@ func (m *Manager) GatherManifestState(ctx context.Context, manifest *ExpandedMan
 	}
 
 	err := m.Client.Get(ctx, key, existing)
+	StripManagedFields(existing)
 
 	if manifest.IsDelete() {
 		if errors.IsNotFound(err) {
@@ -288,6 +604,16 @@ func (m *Manager) GatherManifestState(ctx context.Context, manifest *ExpandedMan
 		return nil, err
 	}
 
+	AttachRelated(ctx, m.Client, existing)
+	if manifest.Snapshot {
+		// apply 前对象不存在时附加空快照（字段均视为不存在）；快照丢失（控制器重启）时不附加，由期望报告无法判定
+		if snapshot, ok := m.PreApplySnapshot(owner, obj); ok {
+			if snapshot == nil {
+				snapshot = map[string]interface{}{}
+			}
+			existing.Object[BeforeKey] = snapshot
+		}
+	}
 	return map[string]interface{}{keyStr: existing.Object}, nil
 }
 
@@ -310,6 +636,7 @@ func (m *Manager) GatherManifestStates(ctx context.Context, manifests []Expanded
 		}
 
 		err := m.Client.Get(ctx, key, existing)
+		StripManagedFields(existing)
 
 		if manifest.IsDelete() {
 			if errors.IsNotFound(err) {
@@ -331,6 +658,7 @@ func (m *Manager) GatherManifestStates(ctx context.Context, manifests []Expanded
 			return nil, err
 		}
 
+		AttachRelated(ctx, m.Client, existing)
 		state[keyStr] = existing.Object
 	}
 
@@ -354,7 +682,9 @@ func (m *Manager) GatherObjectState(ctx context.Context, obj *unstructured.Unstr
 		}
 		return nil, err
 	}
+	StripManagedFields(existing)
 
+	AttachRelated(ctx, m.Client, existing)
 	return existing.Object, nil
 }
 
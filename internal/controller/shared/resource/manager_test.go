@@ -0,0 +1,225 @@
+package resource
+
+import (
+	"context"
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	infrav1alpha1 "github.com/lunz1207/testplane/api/v1alpha1"
+)
+
+var _ = Describe("Manager", func() {
+	ctx := context.Background()
+	owner := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "owner", Namespace: "default", UID: "owner-uid"}}
+
+	var (
+		c       client.Client
+		m       *Manager
+		gets    int
+		applies int
+		// response SSA 响应中对象的 UID 与是否处于删除中（fake client 不支持 SSA）
+		responseUID         types.UID
+		responseTerminating bool
+	)
+
+	BeforeEach(func() {
+		gets, applies, responseUID, responseTerminating = 0, 0, "", false
+		scheme := runtime.NewScheme()
+		Expect(corev1.AddToScheme(scheme)).To(Succeed())
+		c = fake.NewClientBuilder().WithScheme(scheme).WithInterceptorFuncs(interceptor.Funcs{
+			Get: func(ctx context.Context, c client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+				gets++
+				return c.Get(ctx, key, obj, opts...)
+			},
+			Patch: func(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+				if patch.Type() != client.Apply.Type() {
+					return c.Patch(ctx, obj, patch, opts...)
+				}
+				applies++
+				obj.SetUID(responseUID)
+				if responseTerminating {
+					now := metav1.Now()
+					obj.SetDeletionTimestamp(&now)
+				}
+				return nil
+			},
+		}).Build()
+		m = NewManager(c, scheme, "test-owner", nil)
+	})
+
+	configMap := func() *unstructured.Unstructured {
+		obj := &unstructured.Unstructured{}
+		obj.SetAPIVersion("v1")
+		obj.SetKind("ConfigMap")
+		obj.SetName("app")
+		obj.SetNamespace("default")
+		return obj
+	}
+
+	Context("When applying over a terminating object", func() {
+		It("should fall back to the apply response when no deletion was recorded", func() {
+			responseUID, responseTerminating = "old", true
+			err := m.ApplyObject(ctx, owner, configMap())
+			Expect(errors.Is(err, ErrObjectTerminating)).To(BeTrue())
+			Expect(err.Error()).To(ContainSubstring("uid=old"))
+			Expect(gets).To(BeZero())
+
+			responseUID, responseTerminating = "new", false
+			Expect(m.ApplyObject(ctx, owner, configMap())).To(Succeed())
+			Expect(gets).To(BeZero())
+		})
+	})
+
+	Context("When an object is deleted and recreated", func() {
+		It("should treat a changed UID as the old object being gone", func() {
+			Expect(c.Create(ctx, &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{
+				Name: "app", Namespace: "default", UID: "old", Finalizers: []string{"example.com/hold"},
+			}})).To(Succeed())
+			Expect(m.DeleteObject(ctx, configMap())).To(Succeed())
+			Expect(m.deletedUID(configMap())).To(Equal(types.UID("old")))
+
+			// finalizer 未移除：旧对象仍在删除中，apply 前即等待，不写入旧对象
+			Expect(m.WaitForObject(ctx, configMap(), true)).To(MatchError(ContainSubstring("still exists")))
+			err := m.ApplyObject(ctx, owner, configMap())
+			Expect(errors.Is(err, ErrObjectTerminating)).To(BeTrue())
+			Expect(err.Error()).To(ContainSubstring("uid=old"))
+			Expect(applies).To(BeZero())
+
+			// 旧对象删除完成后同名对象被重新创建
+			var old corev1.ConfigMap
+			Expect(c.Get(ctx, client.ObjectKey{Namespace: "default", Name: "app"}, &old)).To(Succeed())
+			old.Finalizers = nil
+			Expect(c.Update(ctx, &old)).To(Succeed())
+			Expect(c.Create(ctx, &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default", UID: "new"}})).To(Succeed())
+			Expect(m.WaitForObject(ctx, configMap(), true)).To(Succeed())
+
+			// apply 响应为新对象：清除删除记录
+			responseUID = "new"
+			Expect(m.ApplyObject(ctx, owner, configMap())).To(Succeed())
+			Expect(applies).To(Equal(1))
+			Expect(m.deletedUID(configMap())).To(BeEmpty())
+		})
+
+		It("should apply once the old object is fully deleted", func() {
+			Expect(c.Create(ctx, &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default", UID: "old"}})).To(Succeed())
+			Expect(m.DeleteObject(ctx, configMap())).To(Succeed())
+			Expect(m.deletedUID(configMap())).To(Equal(types.UID("old")))
+
+			responseUID = "new"
+			Expect(m.ApplyObject(ctx, owner, configMap())).To(Succeed())
+			Expect(applies).To(Equal(1))
+			Expect(m.deletedUID(configMap())).To(BeEmpty())
+		})
+	})
+
+	Context("When the cache has not seen an object yet", func() {
+		It("should read existence through the APIReader and still delete it", func() {
+			scheme := runtime.NewScheme()
+			Expect(corev1.AddToScheme(scheme)).To(Succeed())
+			existing := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default", UID: "fresh"}}
+			var deleted []string
+			// 缓存 client：读取返回 NotFound（尚未同步），写入照常发往 API Server
+			cached := fake.NewClientBuilder().WithScheme(scheme).WithObjects(existing.DeepCopy()).WithInterceptorFuncs(interceptor.Funcs{
+				Get: func(_ context.Context, _ client.WithWatch, key client.ObjectKey, _ client.Object, _ ...client.GetOption) error {
+					return apierrors.NewNotFound(corev1.Resource("configmaps"), key.Name)
+				},
+				Delete: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.DeleteOption) error {
+					deleted = append(deleted, obj.GetName())
+					return c.Delete(ctx, obj, opts...)
+				},
+			}).Build()
+			apiReader := fake.NewClientBuilder().WithScheme(scheme).WithObjects(existing.DeepCopy()).Build()
+			m := NewManager(cached, scheme, "test-owner", apiReader)
+
+			Expect(m.DeleteObject(ctx, configMap())).To(Succeed())
+			Expect(deleted).To(Equal([]string{"app"}))
+			Expect(m.deletedUID(configMap())).To(Equal(types.UID("fresh")))
+			// 删除等待同样以 APIReader 为准
+			Expect(m.WaitForObject(ctx, configMap(), true)).To(MatchError(ContainSubstring("still exists")))
+		})
+	})
+
+	Context("When tests touch objects with the same name", func() {
+		other := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "default", UID: "other-uid"}}
+
+		It("should keep snapshots and tombstones of each test apart", func() {
+			Expect(c.Create(ctx, &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default"},
+				Data:       map[string]string{"version": "1"},
+			})).To(Succeed())
+			Expect(m.saveSnapshot(ctx, owner, configMap())).To(Succeed())
+
+			var current corev1.ConfigMap
+			Expect(c.Get(ctx, client.ObjectKey{Namespace: "default", Name: "app"}, &current)).To(Succeed())
+			current.Data["version"] = "2"
+			Expect(c.Update(ctx, &current)).To(Succeed())
+			Expect(m.saveSnapshot(ctx, other, configMap())).To(Succeed())
+
+			mine, ok := m.PreApplySnapshot(owner, configMap())
+			Expect(ok).To(BeTrue())
+			Expect(mine).To(HaveKeyWithValue("data", HaveKeyWithValue("version", "1")))
+			theirs, ok := m.PreApplySnapshot(other, configMap())
+			Expect(ok).To(BeTrue())
+			Expect(theirs).To(HaveKeyWithValue("data", HaveKeyWithValue("version", "2")))
+
+			// 批量执行同样保存墓碑
+			Expect(m.ExecuteManifests(ctx, owner, []ExpandedManifest{{
+				Object: configMap(), Action: infrav1alpha1.TemplateActionDelete, Tombstone: true,
+			}})).To(Succeed())
+			_, ok = m.Tombstone(owner, configMap())
+			Expect(ok).To(BeTrue())
+			_, ok = m.Tombstone(other, configMap())
+			Expect(ok).To(BeFalse())
+
+			// 测试删除或结束后只清除自己的记录
+			m.ForgetOwner(owner.UID)
+			_, ok = m.Tombstone(owner, configMap())
+			Expect(ok).To(BeFalse())
+			_, ok = m.PreApplySnapshot(owner, configMap())
+			Expect(ok).To(BeFalse())
+			_, ok = m.PreApplySnapshot(other, configMap())
+			Expect(ok).To(BeTrue())
+		})
+
+		It("should tell an object created by the step from a lost snapshot", func() {
+			manifest := &ExpandedManifest{Object: configMap(), Snapshot: true}
+			Expect(m.saveSnapshot(ctx, owner, configMap())).To(Succeed())
+			snapshot, ok := m.PreApplySnapshot(owner, configMap())
+			Expect(ok).To(BeTrue())
+			Expect(snapshot).To(BeNil())
+
+			Expect(c.Create(ctx, &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default"}})).To(Succeed())
+			state, err := m.GatherManifestState(ctx, owner, manifest)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(state[manifest.StateKey()]).To(HaveKeyWithValue(BeforeKey, BeEmpty()))
+
+			// 控制器重启后没有快照：不附加 _before
+			state, err = m.GatherManifestState(ctx, other, manifest)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(state[manifest.StateKey()]).NotTo(HaveKey(BeforeKey))
+		})
+	})
+	Context("When gathering state for expectations", func() {
+		It("should strip managedFields from the object", func() {
+			Expect(c.Create(ctx, &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{
+				Name: "app", Namespace: "default",
+				ManagedFields: []metav1.ManagedFieldsEntry{{Manager: "kubectl", Operation: metav1.ManagedFieldsOperationApply}},
+			}})).To(Succeed())
+			manifest := &ExpandedManifest{Object: configMap()}
+			state, err := m.GatherManifestState(ctx, owner, manifest)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(state[manifest.StateKey()]).To(HaveKeyWithValue("metadata", Not(HaveKey("managedFields"))))
+		})
+	})
+})
@@ -0,0 +1,105 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resource
+
+import "sort"
+
+// waitBeforeField 列表项的顶层标记字段，值为 true 时该项在之前的资源全部收敛后才 apply。
+// 该字段不是资源的一部分，展开时移除。
+const waitBeforeField = "waitBefore"
+
+// kindOrder 资源 apply 的类型优先级（与 kubectl/Helm 的安装顺序一致）：
+// 命名空间、CRD 与配置类资源在前，工作负载在后，准入 Webhook 与 APIService 最后，避免 Webhook 拦截同批资源。
+// 未列出的类型（如自定义资源）排在已知工作负载之后、Webhook 之前。
+var kindOrder = []string{
+	"Namespace",
+	"NetworkPolicy",
+	"ResourceQuota",
+	"LimitRange",
+	"PodDisruptionBudget",
+	"ServiceAccount",
+	"Secret",
+	"ConfigMap",
+	"StorageClass",
+	"PersistentVolume",
+	"PersistentVolumeClaim",
+	"CustomResourceDefinition",
+	"ClusterRole",
+	"ClusterRoleBinding",
+	"Role",
+	"RoleBinding",
+	"Service",
+	"DaemonSet",
+	"Pod",
+	"ReplicationController",
+	"ReplicaSet",
+	"Deployment",
+	"HorizontalPodAutoscaler",
+	"StatefulSet",
+	"Job",
+	"CronJob",
+	"IngressClass",
+	"Ingress",
+}
+
+// lastKinds 在所有其他类型之后 apply 的类型。
+var lastKinds = []string{
+	"APIService",
+	"ValidatingWebhookConfiguration",
+	"MutatingWebhookConfiguration",
+}
+
+// kindPriority 返回类型的 apply 优先级，数值越小越先 apply。
+func kindPriority(kind string) int {
+	for i, k := range kindOrder {
+		if k == kind {
+			return i
+		}
+	}
+	for i, k := range lastKinds {
+		if k == kind {
+			return len(kindOrder) + 1 + i
+		}
+	}
+	return len(kindOrder)
+}
+
+// SortByKind 按类型优先级稳定排序：Apply 清单按安装顺序，Delete 清单按相反顺序（先删 Webhook 与工作负载，最后删命名空间与 CRD）。
+// 同类型资源保持声明顺序。waitBefore 项与操作类型的变化把清单划分为依次执行的段，只在段内排序，
+// 因此 waitBefore 之前声明的资源总是先于它 apply。
+func SortByKind(manifests []ExpandedManifest) {
+	for start := 0; start < len(manifests); {
+		end := start + 1
+		for end < len(manifests) && !manifests[end].WaitBefore && manifests[end].IsDelete() == manifests[start].IsDelete() {
+			end++
+		}
+		segment := manifests[start:end]
+		reverse, wait := segment[0].IsDelete(), segment[0].WaitBefore
+		sort.SliceStable(segment, func(i, j int) bool {
+			pi, pj := kindPriority(segment[i].Object.GetKind()), kindPriority(segment[j].Object.GetKind())
+			if reverse {
+				return pi > pj
+			}
+			return pi < pj
+		})
+		// 等待标记属于整段：排序后由段内第一个资源等待
+		for i := range segment {
+			segment[i].WaitBefore = i == 0 && wait
+		}
+		start = end
+	}
+}
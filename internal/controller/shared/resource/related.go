@@ -0,0 +1,159 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resource
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	infrav1alpha1 "github.com/lunz1207/testplane/api/v1alpha1"
+	"github.com/lunz1207/testplane/internal/controller/shared/logging"
+)
+
+// RelatedKey 资源对象中存放关联资源的保留字段。
+// 状态收集时会将关联资源（内置的 Service EndpointSlice，以及期望声明的 relatedResources）
+// 合并到该字段下，供断言函数跨资源检查。
+const RelatedKey = "_related"
+
+// BeforeKey 资源对象中存放 apply 前快照的保留字段。
+// 清单声明 Snapshot 时，状态收集会把步骤 apply 前保存的对象合并到该字段下，供断言函数比较 apply 前后的字段。
+const BeforeKey = "_before"
+
+// RelatedEndpointSlices Service 关联的 EndpointSlice 列表在 RelatedKey 下的子键。
+const RelatedEndpointSlices = "endpointSlices"
+
+// serviceNameLabel EndpointSlice 指向所属 Service 的标签。
+const serviceNameLabel = "kubernetes.io/service-name"
+
+// AttachRelated 为资源对象附加内置的关联资源。
+// 目前支持：core/v1 Service → 同名 EndpointSlice（discovery.k8s.io/v1）。
+// 关联资源获取失败不影响主资源，只记录日志。
+func AttachRelated(ctx context.Context, reader client.Reader, obj *unstructured.Unstructured) {
+	if obj == nil || obj.GetAPIVersion() != "v1" || obj.GetKind() != "Service" {
+		return
+	}
+
+	list := &unstructured.UnstructuredList{}
+	list.SetAPIVersion("discovery.k8s.io/v1")
+	list.SetKind("EndpointSlice")
+	if err := reader.List(ctx, list,
+		client.InNamespace(obj.GetNamespace()),
+		client.MatchingLabels{serviceNameLabel: obj.GetName()},
+	); err != nil {
+		logf.FromContext(ctx).V(logging.LevelVerbose).Info("list endpointslices failed",
+			"service", obj.GetName(), "error", err.Error())
+		return
+	}
+
+	slices := make([]interface{}, 0, len(list.Items))
+	for i := range list.Items {
+		StripManagedFields(&list.Items[i])
+		slices = append(slices, list.Items[i].Object)
+	}
+	setRelated(obj.Object, RelatedEndpointSlices, slices)
+}
+
+// StripManagedFields 剔除状态对象的 metadata.managedFields。
+// 期望检查读取的 unstructured 对象不经缓存（缓存的 TransformStripManagedFields 对其不生效），
+// 而 managedFields 对断言无用却常占对象体积的一半以上，状态收集时统一剔除。
+func StripManagedFields(obj *unstructured.Unstructured) {
+	if obj != nil {
+		unstructured.RemoveNestedField(obj.Object, "metadata", "managedFields")
+	}
+}
+
+// setRelated 在对象的 RelatedKey 字段下设置关联资源。
+func setRelated(obj map[string]interface{}, name string, value interface{}) {
+	related, ok := obj[RelatedKey].(map[string]interface{})
+	if !ok {
+		related = make(map[string]interface{})
+		obj[RelatedKey] = related
+	}
+	related[name] = value
+}
+
+// AttachDeclaredRelated 按期望声明的 relatedResources 获取关联资源，
+// 以列表形式合并到主资源的 _related.<name> 下（同名子键会被覆盖）。
+func AttachDeclaredRelated(ctx context.Context, reader client.Reader, obj map[string]interface{}, refs []infrav1alpha1.RelatedResource) error {
+	primary := &unstructured.Unstructured{Object: obj}
+	for _, ref := range refs {
+		items, err := fetchRelated(ctx, reader, primary, ref)
+		if err != nil {
+			return fmt.Errorf("related resource %s: %w", ref.Name, err)
+		}
+		setRelated(obj, ref.Name, items)
+	}
+	return nil
+}
+
+// fetchRelated 获取单个关联资源声明对应的对象列表。
+func fetchRelated(ctx context.Context, reader client.Reader, primary *unstructured.Unstructured, ref infrav1alpha1.RelatedResource) ([]interface{}, error) {
+	if ref.Name == "" || ref.APIVersion == "" || ref.Kind == "" {
+		return nil, fmt.Errorf("name, apiVersion and kind are required")
+	}
+	namespace := primary.GetNamespace()
+
+	labels := ref.LabelSelector
+	if ref.LabelSelectorFrom != "" {
+		fromPath, found, err := unstructured.NestedStringMap(primary.Object, strings.Split(ref.LabelSelectorFrom, ".")...)
+		if err != nil || !found {
+			return nil, fmt.Errorf("labelSelectorFrom %s not found in %s/%s", ref.LabelSelectorFrom, primary.GetKind(), primary.GetName())
+		}
+		labels = fromPath
+	}
+
+	// 按名称获取（未指定选择器时使用主资源名称）
+	if len(labels) == 0 {
+		name := ref.ResourceName
+		if name == "" {
+			name = primary.GetName()
+		}
+		obj := &unstructured.Unstructured{}
+		obj.SetAPIVersion(ref.APIVersion)
+		obj.SetKind(ref.Kind)
+		if err := reader.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, obj); err != nil {
+			if errors.IsNotFound(err) {
+				return []interface{}{}, nil
+			}
+			return nil, err
+		}
+		StripManagedFields(obj)
+		return []interface{}{obj.Object}, nil
+	}
+
+	list := &unstructured.UnstructuredList{}
+	list.SetAPIVersion(ref.APIVersion)
+	list.SetKind(ref.Kind)
+	if err := reader.List(ctx, list, client.InNamespace(namespace), client.MatchingLabels(labels)); err != nil {
+		return nil, err
+	}
+	sort.Slice(list.Items, func(i, j int) bool { return list.Items[i].GetName() < list.Items[j].GetName() })
+
+	items := make([]interface{}, 0, len(list.Items))
+	for i := range list.Items {
+		StripManagedFields(&list.Items[i])
+		items = append(items, list.Items[i].Object)
+	}
+	return items, nil
+}
@@ -0,0 +1,75 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resource
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	infrav1alpha1 "github.com/lunz1207/testplane/api/v1alpha1"
+)
+
+// WorkspaceRef spec 中引用工作区名称的占位符。
+const WorkspaceRef = "${WORKSPACE}"
+
+// ExpandWorkspace 将 spec 中的 ${WORKSPACE} 替换为工作区名称，只修改内存中的对象，返回 spec 是否引用了工作区。
+// name 为空时只检查引用、不做替换（如工作区尚未创建）。控制器与 `testplane render` 共用此替换，保证预览与 apply 一致。
+func ExpandWorkspace[T any](spec *T, name string) (bool, error) {
+	raw, err := json.Marshal(spec)
+	if err != nil {
+		return false, fmt.Errorf("marshal spec: %w", err)
+	}
+	if !bytes.Contains(raw, []byte(WorkspaceRef)) {
+		return false, nil
+	}
+	if name == "" {
+		return true, nil
+	}
+	// 工作区名称为 DNS 名称，无需 JSON 转义
+	expanded := bytes.ReplaceAll(raw, []byte(WorkspaceRef), []byte(name))
+	var out T
+	if err := json.Unmarshal(expanded, &out); err != nil {
+		return true, fmt.Errorf("unmarshal expanded spec: %w", err)
+	}
+	*spec = out
+	return true, nil
+}
+
+// StepRef 步骤中的单个清单引用。
+type StepRef struct {
+	// Field 清单在步骤中的字段路径，如 preStep[0]、resource、postStep[1]。
+	Field string
+	// Ref 指向步骤中的 ResourceRef，ResolveManifest 等可就地修改。
+	Ref *infrav1alpha1.ResourceRef
+}
+
+// StepRefs 按执行顺序返回步骤的清单引用：preStep、步骤资源、postStep。未设置 resource 的步骤不含步骤资源。
+// Ref 与 step 共享底层数组，对 preStep/postStep 的修改对 spec 中的步骤可见。
+func StepRefs(step infrav1alpha1.TestStep) []StepRef {
+	refs := make([]StepRef, 0, len(step.PreStep)+len(step.PostStep)+1)
+	for i := range step.PreStep {
+		refs = append(refs, StepRef{Field: fmt.Sprintf("preStep[%d]", i), Ref: &step.PreStep[i]})
+	}
+	if step.Resource != nil {
+		refs = append(refs, StepRef{Field: "resource", Ref: step.Resource})
+	}
+	for i := range step.PostStep {
+		refs = append(refs, StepRef{Field: fmt.Sprintf("postStep[%d]", i), Ref: &step.PostStep[i]})
+	}
+	return refs
+}
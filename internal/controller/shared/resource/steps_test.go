@@ -0,0 +1,48 @@
+package resource
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	infrav1alpha1 "github.com/lunz1207/testplane/api/v1alpha1"
+)
+
+var _ = Describe("Step expansion", func() {
+	It("should list step manifests in execution order and share them with the step", func() {
+		step := infrav1alpha1.TestStep{
+			PreStep:  []infrav1alpha1.ResourceRef{{}, {}},
+			Resource: &infrav1alpha1.ResourceRef{},
+			PostStep: []infrav1alpha1.ResourceRef{{}},
+		}
+		refs := StepRefs(step)
+		fields := make([]string, 0, len(refs))
+		for _, ref := range refs {
+			fields = append(fields, ref.Field)
+		}
+		Expect(fields).To(Equal([]string{"preStep[0]", "preStep[1]", "resource", "postStep[0]"}))
+
+		refs[1].Ref.Action = infrav1alpha1.TemplateActionDelete
+		Expect(step.PreStep[1].Action).To(Equal(infrav1alpha1.TemplateActionDelete))
+		Expect(StepRefs(infrav1alpha1.TestStep{})).To(BeEmpty())
+	})
+
+	It("should replace ${WORKSPACE} only when a name is given", func() {
+		spec := &infrav1alpha1.TestStep{Resource: &infrav1alpha1.ResourceRef{
+			Manifest: runtime.RawExtension{Raw: []byte(`{"kind":"ConfigMap","data":{"path":"/${WORKSPACE}/out"}}`)},
+		}}
+		referenced, err := ExpandWorkspace(spec, "")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(referenced).To(BeTrue())
+		Expect(string(spec.Resource.Manifest.Raw)).To(ContainSubstring("${WORKSPACE}"))
+
+		referenced, err = ExpandWorkspace(spec, "smoke-ws-1a2b")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(referenced).To(BeTrue())
+		Expect(string(spec.Resource.Manifest.Raw)).To(ContainSubstring(`"/smoke-ws-1a2b/out"`))
+
+		referenced, err = ExpandWorkspace(spec, "other")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(referenced).To(BeFalse())
+	})
+})
@@ -0,0 +1,68 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resource
+
+import (
+	"context"
+	"fmt"
+
+	autoscalingv1 "k8s.io/api/autoscaling/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// SubresourceScale scale 子资源：任何可伸缩资源统一以 autoscaling/v1 Scale 呈现
+// （spec.replicas、status.replicas、status.selector），断言无需了解具体类型的 schema。
+const SubresourceScale = "scale"
+
+// GetSubresource 获取资源对象的子资源视图（目前只支持 scale），返回子资源对象。
+// res 只需包含 apiVersion、kind、metadata.name/namespace；scheme 已注册的类型以具体类型请求，其余以 unstructured 请求。
+func GetSubresource(ctx context.Context, c client.Client, res map[string]interface{}, subresource string) (map[string]interface{}, error) {
+	if subresource != SubresourceScale {
+		return nil, fmt.Errorf("unsupported subresource %q", subresource)
+	}
+	obj := &unstructured.Unstructured{Object: res}
+	if obj.GetKind() == "" || obj.GetName() == "" {
+		return nil, fmt.Errorf("no resource to get subresource %s for", subresource)
+	}
+	var owner client.Object
+	if typed, err := c.Scheme().New(obj.GroupVersionKind()); err == nil {
+		if o, ok := typed.(client.Object); ok {
+			owner = o
+		}
+	}
+	if owner == nil {
+		u := &unstructured.Unstructured{}
+		u.SetGroupVersionKind(obj.GroupVersionKind())
+		owner = u
+	}
+	owner.SetNamespace(obj.GetNamespace())
+	owner.SetName(obj.GetName())
+
+	scale := &autoscalingv1.Scale{}
+	if err := c.SubResource(subresource).Get(ctx, owner, scale); err != nil {
+		return nil, fmt.Errorf("get %s/%s %s: %w", obj.GetKind(), obj.GetName(), subresource, err)
+	}
+	out, err := runtime.DefaultUnstructuredConverter.ToUnstructured(scale)
+	if err != nil {
+		return nil, fmt.Errorf("convert %s/%s %s: %w", obj.GetKind(), obj.GetName(), subresource, err)
+	}
+	out["apiVersion"] = "autoscaling/v1"
+	out["kind"] = "Scale"
+	return out, nil
+}
@@ -37,7 +37,14 @@ func ExpandResourceRef(ref infrav1alpha1.ResourceRef, defaultNamespace string) (
 		action = infrav1alpha1.TemplateActionApply
 	}
 
-	return expandRaw(ref.Manifest.Raw, defaultNamespace, action)
+	manifests, err := expandRaw(ref.Manifest.Raw, defaultNamespace, action)
+	if err != nil {
+		return nil, err
+	}
+	for i := range manifests {
+		manifests[i].Convergence = ref.Convergence
+	}
+	return manifests, nil
 }
 
 // ExpandSingleResourceRef 展开单个 ResourceRef 为单个 ExpandedManifest。
@@ -66,10 +73,11 @@ func ExpandSingleResourceRef(ref infrav1alpha1.ResourceRef, defaultNamespace str
 	if err != nil {
 		return nil, err
 	}
+	manifest.Convergence = ref.Convergence
 	return &manifest, nil
 }
 
-// ExpandResourceRefs 展开多个 ResourceRef（支持 List/数组）。
+// ExpandResourceRefs 展开多个 ResourceRef（支持 List/数组），并按类型优先级排序（见 SortByKind）。
 func ExpandResourceRefs(refs []infrav1alpha1.ResourceRef, defaultNamespace string) ([]ExpandedManifest, error) {
 	if len(refs) == 0 {
 		return nil, nil
@@ -84,6 +92,7 @@ func ExpandResourceRefs(refs []infrav1alpha1.ResourceRef, defaultNamespace strin
 		result = append(result, expanded...)
 	}
 
+	SortByKind(result)
 	return result, nil
 }
 
@@ -135,6 +144,7 @@ func expandRaw(raw []byte, defaultNamespace string, action infrav1alpha1.Templat
 }
 
 // expandItemList 将 List/数组展开为 ExpandedManifest 列表。
+// 列表项可设置顶层字段 waitBefore: true，表示在之前的资源全部收敛后才执行该项；该字段不会写入资源。
 func expandItemList(items []interface{}, defaultNamespace string, action infrav1alpha1.TemplateAction) ([]ExpandedManifest, error) {
 	result := make([]ExpandedManifest, 0, len(items))
 	for i, item := range items {
@@ -142,15 +152,34 @@ func expandItemList(items []interface{}, defaultNamespace string, action infrav1
 		if !ok {
 			return nil, fmt.Errorf("list item %d is not an object", i)
 		}
+		waitBefore, err := popWaitBefore(obj)
+		if err != nil {
+			return nil, fmt.Errorf("list item %d: %w", i, err)
+		}
 		manifest, err := toExpandedManifest(obj, defaultNamespace, action)
 		if err != nil {
 			return nil, fmt.Errorf("list item %d: %w", i, err)
 		}
+		manifest.WaitBefore = waitBefore
 		result = append(result, manifest)
 	}
 	return result, nil
 }
 
+// popWaitBefore 读取并移除列表项的 waitBefore 标记。
+func popWaitBefore(obj map[string]interface{}) (bool, error) {
+	value, ok := obj[waitBeforeField]
+	if !ok {
+		return false, nil
+	}
+	delete(obj, waitBeforeField)
+	wait, ok := value.(bool)
+	if !ok {
+		return false, fmt.Errorf("%s must be a boolean", waitBeforeField)
+	}
+	return wait, nil
+}
+
 // toExpandedManifest 将模板对象转换为 ExpandedManifest。
 func toExpandedManifest(obj map[string]interface{}, defaultNamespace string, action infrav1alpha1.TemplateAction) (ExpandedManifest, error) {
 	apiVersion, _ := obj["apiVersion"].(string)
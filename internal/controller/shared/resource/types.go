@@ -19,7 +19,9 @@ package resource
 import (
 	"fmt"
 
+	rbacv1 "k8s.io/api/rbac/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 
 	infrav1alpha1 "github.com/lunz1207/testplane/api/v1alpha1"
 )
@@ -31,6 +33,43 @@ type ExpandedManifest struct {
 	Object *unstructured.Unstructured
 	// Action 操作类型（Apply 或 Delete）。
 	Action infrav1alpha1.TemplateAction
+	// Convergence 收敛判定方式（为空时按 Kind 自动选择）。
+	Convergence infrav1alpha1.ConvergenceMode
+	// Tombstone Delete 时是否先保存对象的墓碑快照，供期望断言删除前的状态。
+	Tombstone bool
+	// Snapshot Apply 时是否先保存对象当前状态的快照，收集状态时附加到 BeforeKey 下，供期望比较 apply 前后的字段。
+	Snapshot bool
+	// WaitBefore 执行前是否等待之前的清单全部收敛（列表项的 waitBefore 标记）。
+	WaitBefore bool
+}
+
+// statuslessKinds 没有 status 子资源、不会被控制器"处理"的内置资源类型。
+// 这些资源 apply 成功即可视为收敛。按 GroupKind 区分，其他 API 组中同名 Kind 的 CRD 不受影响。
+var statuslessKinds = map[schema.GroupKind]bool{
+	{Kind: "ConfigMap"}:                                   true,
+	{Kind: "Secret"}:                                      true,
+	{Kind: "ServiceAccount"}:                              true,
+	{Kind: "LimitRange"}:                                  true,
+	{Group: rbacv1.GroupName, Kind: "Role"}:               true,
+	{Group: rbacv1.GroupName, Kind: "RoleBinding"}:        true,
+	{Group: rbacv1.GroupName, Kind: "ClusterRole"}:        true,
+	{Group: rbacv1.GroupName, Kind: "ClusterRoleBinding"}: true,
+}
+
+// IsStatuslessKind 判断资源类型是否为无 status 的内置资源。
+func IsStatuslessKind(gk schema.GroupKind) bool {
+	return statuslessKinds[gk]
+}
+
+// ConvergenceMode 返回实际生效的收敛判定方式。
+func (e *ExpandedManifest) ConvergenceMode() infrav1alpha1.ConvergenceMode {
+	if e.Convergence != "" {
+		return e.Convergence
+	}
+	if IsStatuslessKind(e.Object.GroupVersionKind().GroupKind()) {
+		return infrav1alpha1.ConvergenceNone
+	}
+	return infrav1alpha1.ConvergenceGeneration
 }
 
 // StateKey 生成状态 map 的 key，格式为 "{apiVersion}/{kind}/{name}"。
@@ -0,0 +1,33 @@
+package resource
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	infrav1alpha1 "github.com/lunz1207/testplane/api/v1alpha1"
+)
+
+var _ = Describe("Expanded manifests", func() {
+	DescribeTable("should choose the convergence mode by group and kind",
+		func(apiVersion, kind string, expected infrav1alpha1.ConvergenceMode) {
+			obj := &unstructured.Unstructured{}
+			obj.SetAPIVersion(apiVersion)
+			obj.SetKind(kind)
+			Expect((&ExpandedManifest{Object: obj}).ConvergenceMode()).To(Equal(expected))
+		},
+		Entry("core ConfigMap", "v1", "ConfigMap", infrav1alpha1.ConvergenceNone),
+		Entry("RBAC Role", "rbac.authorization.k8s.io/v1", "Role", infrav1alpha1.ConvergenceNone),
+		Entry("Deployment", "apps/v1", "Deployment", infrav1alpha1.ConvergenceGeneration),
+		Entry("CRD sharing a core kind name", "example.com/v1", "ConfigMap", infrav1alpha1.ConvergenceGeneration),
+		Entry("CRD sharing an RBAC kind name", "example.com/v1", "Role", infrav1alpha1.ConvergenceGeneration),
+	)
+
+	It("should keep an explicit convergence mode", func() {
+		obj := &unstructured.Unstructured{}
+		obj.SetAPIVersion("v1")
+		obj.SetKind("ConfigMap")
+		manifest := &ExpandedManifest{Object: obj, Convergence: infrav1alpha1.ConvergenceGeneration}
+		Expect(manifest.ConvergenceMode()).To(Equal(infrav1alpha1.ConvergenceGeneration))
+	})
+})
@@ -0,0 +1,139 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package shared
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	infrav1alpha1 "github.com/lunz1207/testplane/api/v1alpha1"
+)
+
+// maxNearestMisses 诊断信息中最多记录的近似未命中资源数。
+const maxNearestMisses = 5
+
+// nearMiss 单个未命中候选资源。
+type nearMiss struct {
+	name    string
+	reasons []string
+}
+
+// SelectorNoMatchError 选择器未匹配到任何资源，携带诊断信息。
+type SelectorNoMatchError struct {
+	Diagnostics *infrav1alpha1.SelectorDiagnostics
+}
+
+func (e *SelectorNoMatchError) Error() string {
+	if e.Diagnostics == nil {
+		return "selector matched no resources"
+	}
+	return fmt.Sprintf("selector %s matched no resources: %s", e.Diagnostics.Selector, e.Diagnostics.Message)
+}
+
+// DiagnoseSelector 在选择器未匹配到资源时收集诊断信息。
+// 列出命名空间下同 Kind 的全部资源，按未满足条件的数量排序，给出最接近的未命中资源。
+// matchedCount 为满足名称/标签/注解条件的资源数量（可能因期望未通过而未被选中）。
+func DiagnoseSelector(ctx context.Context, reader client.Reader, sel infrav1alpha1.ResourceSelector, namespace string, matchedCount int) *infrav1alpha1.SelectorDiagnostics {
+	diag := &infrav1alpha1.SelectorDiagnostics{
+		Selector:     selectorKey(sel),
+		Namespaces:   []string{namespace},
+		MatchedCount: matchedCount,
+	}
+
+	list := &unstructured.UnstructuredList{}
+	list.SetAPIVersion(sel.APIVersion)
+	list.SetKind(sel.Kind)
+	if err := reader.List(ctx, list, client.InNamespace(namespace)); err != nil {
+		diag.Message = fmt.Sprintf("list %s in namespace %q failed: %v", sel.Kind, namespace, err)
+		return diag
+	}
+	diag.CandidateCount = len(list.Items)
+
+	misses := make([]nearMiss, 0, len(list.Items))
+	for i := range list.Items {
+		reasons := selectorMismatchReasons(sel, &list.Items[i])
+		if len(reasons) == 0 {
+			continue
+		}
+		misses = append(misses, nearMiss{name: list.Items[i].GetName(), reasons: reasons})
+	}
+	sort.Slice(misses, func(i, j int) bool {
+		if len(misses[i].reasons) != len(misses[j].reasons) {
+			return len(misses[i].reasons) < len(misses[j].reasons)
+		}
+		return misses[i].name < misses[j].name
+	})
+	for i := 0; i < len(misses) && i < maxNearestMisses; i++ {
+		diag.NearestMisses = append(diag.NearestMisses,
+			fmt.Sprintf("%s: %s", misses[i].name, strings.Join(misses[i].reasons, ", ")))
+	}
+
+	switch {
+	case diag.CandidateCount == 0:
+		diag.Message = fmt.Sprintf("no %s found in namespace %q", sel.Kind, namespace)
+	case matchedCount == 0:
+		diag.Message = fmt.Sprintf("%d %s found in namespace %q but none matched the selector", diag.CandidateCount, sel.Kind, namespace)
+	default:
+		diag.Message = fmt.Sprintf("%d %s matched the selector but none satisfied the expectations", matchedCount, sel.Kind)
+	}
+	return diag
+}
+
+// selectorMismatchReasons 返回资源不满足选择器的原因列表（为空表示满足）。
+func selectorMismatchReasons(sel infrav1alpha1.ResourceSelector, obj *unstructured.Unstructured) []string {
+	var reasons []string
+	if sel.Name != "" && obj.GetName() != sel.Name {
+		reasons = append(reasons, fmt.Sprintf("name %q != %q", obj.GetName(), sel.Name))
+	}
+	reasons = append(reasons, mapMismatchReasons("label", obj.GetLabels(), sel.LabelSelector)...)
+	reasons = append(reasons, mapMismatchReasons("annotation", obj.GetAnnotations(), sel.AnnotationSelector)...)
+	return reasons
+}
+
+// mapMismatchReasons 比较键值选择器，返回缺失或值不一致的键。
+func mapMismatchReasons(what string, actual, selector map[string]string) []string {
+	keys := make([]string, 0, len(selector))
+	for k := range selector {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var reasons []string
+	for _, k := range keys {
+		v, ok := actual[k]
+		switch {
+		case !ok:
+			reasons = append(reasons, fmt.Sprintf("missing %s %s", what, k))
+		case v != selector[k]:
+			reasons = append(reasons, fmt.Sprintf("%s %s=%q (want %q)", what, k, v, selector[k]))
+		}
+	}
+	return reasons
+}
+
+// selectorKey 生成 ResourceSelector 的诊断标识。
+func selectorKey(sel infrav1alpha1.ResourceSelector) string {
+	if sel.Name != "" {
+		return fmt.Sprintf("%s/%s/%s", sel.APIVersion, sel.Kind, sel.Name)
+	}
+	return fmt.Sprintf("%s/%s", sel.APIVersion, sel.Kind)
+}
@@ -23,27 +23,68 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	infrav1alpha1 "github.com/lunz1207/testplane/api/v1alpha1"
+	"github.com/lunz1207/testplane/internal/controller/shared/metrics"
 )
 
 // 默认 FieldOwner 常量
 const (
 	FieldOwnerIntegrationTest = "integrationtest-controller"
 	FieldOwnerLoadTest        = "loadtest-controller"
+	FieldOwnerCheck           = "check-controller"
+	FieldOwnerTestSuite       = "testsuite-controller"
+	FieldOwnerScheduledTest   = "scheduledtest-controller"
 )
 
-// PatchIntegrationTestStatus 使用纯正 SSA 更新 IntegrationTest 状态。
-// 构造干净的 Apply Configuration，只包含标识字段和 status，不依赖 GET 到的对象。
+// FieldOwnerIntegrationTestSteps IntegrationTest status.steps（当前轮次的步骤状态）的字段管理者，
+// 与测试级字段（FieldOwnerIntegrationTest）分开写入。
+const FieldOwnerIntegrationTestSteps = "integrationtest-controller-steps"
+
+// PatchIntegrationTestStatus 使用纯正 SSA 更新 IntegrationTest 状态：先以步骤级字段管理者写入 status.steps，
+// 再以测试级字段管理者写入其余字段（见 PatchIntegrationTestSteps、PatchIntegrationTestFields）。
 func PatchIntegrationTestStatus(ctx context.Context, c client.Client, name, namespace string, status infrav1alpha1.IntegrationTestStatus) error {
+	if err := PatchIntegrationTestSteps(ctx, c, name, namespace, status.Steps); err != nil {
+		return err
+	}
+	return PatchIntegrationTestFields(ctx, c, name, namespace, status)
+}
+
+// PatchIntegrationTestSteps 以步骤级字段管理者 SSA 更新 status.steps。
+// Apply Configuration 只包含标识字段和步骤状态；steps 为空时释放所有权，步骤状态随之清空（如进入下一轮）。
+func PatchIntegrationTestSteps(ctx context.Context, c client.Client, name, namespace string, steps []infrav1alpha1.StepStatus) error {
+	patch := integrationTestStatusPatch(name, namespace)
+	patch.Status.Steps = steps
+	if err := c.Status().Patch(ctx, patch, client.Apply,
+		client.FieldOwner(FieldOwnerIntegrationTestSteps),
+		client.ForceOwnership,
+	); err != nil {
+		return fmt.Errorf("apply status.steps as %s: %w", FieldOwnerIntegrationTestSteps, err)
+	}
+	return nil
+}
+
+// PatchIntegrationTestFields 以测试级字段管理者 SSA 更新 status 中步骤状态以外的字段（阶段、轮次、条件等）。
+// 不包含 status.steps：旧版本以该字段管理者写入的步骤状态在此释放所有权，由步骤级字段管理者接管。
+func PatchIntegrationTestFields(ctx context.Context, c client.Client, name, namespace string, status infrav1alpha1.IntegrationTestStatus) error {
+	patch := integrationTestStatusPatch(name, namespace)
+	patch.Status = status
+	patch.Status.Steps = nil
+	if err := c.Status().Patch(ctx, patch, client.Apply,
+		client.FieldOwner(FieldOwnerIntegrationTest),
+		client.ForceOwnership,
+	); err != nil {
+		return fmt.Errorf("apply status as %s: %w", FieldOwnerIntegrationTest, err)
+	}
+	metrics.Mark(ctx, metrics.OutcomePatchedStatus)
+	return nil
+}
+
+// integrationTestStatusPatch 构造干净的 Apply Configuration，只包含标识字段，不依赖 GET 到的对象。
+func integrationTestStatusPatch(name, namespace string) *infrav1alpha1.IntegrationTest {
 	patch := &infrav1alpha1.IntegrationTest{}
 	patch.SetName(name)
 	patch.SetNamespace(namespace)
 	patch.SetGroupVersionKind(infrav1alpha1.GroupVersion.WithKind("IntegrationTest"))
-	patch.Status = status
-
-	return c.Status().Patch(ctx, patch, client.Apply,
-		client.FieldOwner(FieldOwnerIntegrationTest),
-		client.ForceOwnership,
-	)
+	return patch
 }
 
 // PatchIntegrationTestStatusFromObject 便捷函数，直接从对象更新状态。
@@ -60,10 +101,10 @@ func PatchLoadTestStatus(ctx context.Context, c client.Client, name, namespace s
 	patch.SetGroupVersionKind(infrav1alpha1.GroupVersion.WithKind("LoadTest"))
 	patch.Status = status
 
-	return c.Status().Patch(ctx, patch, client.Apply,
+	return markPatched(ctx, c.Status().Patch(ctx, patch, client.Apply,
 		client.FieldOwner(FieldOwnerLoadTest),
 		client.ForceOwnership,
-	)
+	))
 }
 
 // PatchLoadTestStatusFromObject 便捷函数，直接从对象更新状态。
@@ -71,22 +112,52 @@ func PatchLoadTestStatusFromObject(ctx context.Context, c client.Client, lt *inf
 	return PatchLoadTestStatus(ctx, c, lt.Name, lt.Namespace, lt.Status)
 }
 
-// PatchStatusSSA 使用 Server-Side Apply 更新 status（通用版本，保留向后兼容）。
-// 推荐使用类型安全的 PatchIntegrationTestStatus 或 PatchLoadTestStatus。
-func PatchStatusSSA(ctx context.Context, c client.Client, obj client.Object, fieldOwner string) error {
-	// 构造干净的 Apply Configuration，只包含必要的标识字段和 status
-	switch o := obj.(type) {
-	case *infrav1alpha1.IntegrationTest:
-		return PatchIntegrationTestStatus(ctx, c, o.Name, o.Namespace, o.Status)
-	case *infrav1alpha1.LoadTest:
-		return PatchLoadTestStatus(ctx, c, o.Name, o.Namespace, o.Status)
-	default:
-		return fmt.Errorf("unsupported type for SSA status patch: %T", obj)
-	}
+// PatchCheckStatus 使用纯正 SSA 更新 Check 状态。
+func PatchCheckStatus(ctx context.Context, c client.Client, name, namespace string, status infrav1alpha1.CheckStatus) error {
+	patch := &infrav1alpha1.Check{}
+	patch.SetName(name)
+	patch.SetNamespace(namespace)
+	patch.SetGroupVersionKind(infrav1alpha1.GroupVersion.WithKind("Check"))
+	patch.Status = status
+
+	return markPatched(ctx, c.Status().Patch(ctx, patch, client.Apply,
+		client.FieldOwner(FieldOwnerCheck),
+		client.ForceOwnership,
+	))
+}
+
+// PatchTestSuiteStatus 使用纯正 SSA 更新 TestSuite 状态。
+func PatchTestSuiteStatus(ctx context.Context, c client.Client, name, namespace string, status infrav1alpha1.TestSuiteStatus) error {
+	patch := &infrav1alpha1.TestSuite{}
+	patch.SetName(name)
+	patch.SetNamespace(namespace)
+	patch.SetGroupVersionKind(infrav1alpha1.GroupVersion.WithKind("TestSuite"))
+	patch.Status = status
+
+	return markPatched(ctx, c.Status().Patch(ctx, patch, client.Apply,
+		client.FieldOwner(FieldOwnerTestSuite),
+		client.ForceOwnership,
+	))
+}
+
+// PatchScheduledTestStatus 使用纯正 SSA 更新 ScheduledTest 状态。
+func PatchScheduledTestStatus(ctx context.Context, c client.Client, name, namespace string, status infrav1alpha1.ScheduledTestStatus) error {
+	patch := &infrav1alpha1.ScheduledTest{}
+	patch.SetName(name)
+	patch.SetNamespace(namespace)
+	patch.SetGroupVersionKind(infrav1alpha1.GroupVersion.WithKind("ScheduledTest"))
+	patch.Status = status
+
+	return markPatched(ctx, c.Status().Patch(ctx, patch, client.Apply,
+		client.FieldOwner(FieldOwnerScheduledTest),
+		client.ForceOwnership,
+	))
 }
 
-// PatchStatusMerge 使用 SSA 更新 status 的便利函数（保留向后兼容）。
-// 推荐使用类型安全的 PatchIntegrationTestStatus 或 PatchLoadTestStatus。
-func PatchStatusMerge(ctx context.Context, c client.Client, obj client.Object) error {
-	return PatchStatusSSA(ctx, c, obj, "")
+// markPatched status 写入成功时记入调和结果指标。
+func markPatched(ctx context.Context, err error) error {
+	if err == nil {
+		metrics.Mark(ctx, metrics.OutcomePatchedStatus)
+	}
+	return err
 }
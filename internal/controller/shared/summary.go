@@ -0,0 +1,32 @@
+package shared
+
+import (
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ConditionTypeSummary 测试进入终态后的结果摘要：Reason 为最终阶段，Message 为关键数字（key=value，空格分隔），
+// 同时以 TestSummary 事件发送一次，`kubectl describe` 一行即可看出测试结果。
+const ConditionTypeSummary = "Summary"
+
+// SummaryDuration 返回开始到完成的时长（按秒取整），完成时间未设置时按 now 计算，开始时间未知时为 0。
+func SummaryDuration(start, completion *metav1.Time, now time.Time) time.Duration {
+	if start == nil {
+		return 0
+	}
+	end := now
+	if completion != nil {
+		end = completion.Time
+	}
+	return max(end.Sub(start.Time), 0).Round(time.Second)
+}
+
+// PassRate 返回通过率的显示形式（如 "98.3%"），没有得出结论的检查时为 "n/a"。
+func PassRate(passed, failed int32) string {
+	if passed+failed <= 0 {
+		return "n/a"
+	}
+	return fmt.Sprintf("%.1f%%", float64(passed)*100/float64(passed+failed))
+}
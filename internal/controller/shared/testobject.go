@@ -0,0 +1,125 @@
+package shared
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	infrav1alpha1 "github.com/lunz1207/testplane/api/v1alpha1"
+)
+
+// lastAppliedAnnotation kubectl apply 记录的配置，不复制到由模板创建的测试。
+const lastAppliedAnnotation = "kubectl.kubernetes.io/last-applied-configuration"
+
+// NewTestObject 返回 kind 对应的空测试对象（TestSuite 与 ScheduledTest 创建的测试）。
+func NewTestObject(kind infrav1alpha1.TestSuiteItemKind) (client.Object, error) {
+	switch kind {
+	case infrav1alpha1.TestSuiteItemIntegrationTest:
+		return &infrav1alpha1.IntegrationTest{}, nil
+	case infrav1alpha1.TestSuiteItemLoadTest:
+		return &infrav1alpha1.LoadTest{}, nil
+	default:
+		return nil, fmt.Errorf("unsupported kind %q", kind)
+	}
+}
+
+// DecodeInlineTest 按 kind 严格解析内联 spec，未知字段视为错误。
+func DecodeInlineTest(kind infrav1alpha1.TestSuiteItemKind, raw *runtime.RawExtension) (client.Object, error) {
+	obj, err := NewTestObject(kind)
+	if err != nil {
+		return nil, err
+	}
+	var spec interface{}
+	switch o := obj.(type) {
+	case *infrav1alpha1.IntegrationTest:
+		spec = &o.Spec
+	case *infrav1alpha1.LoadTest:
+		spec = &o.Spec
+	}
+	dec := json.NewDecoder(bytes.NewReader(raw.Raw))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(spec); err != nil {
+		return nil, fmt.Errorf("decode %s spec: %w", kind, err)
+	}
+	obj.SetLabels(map[string]string{})
+	return obj, nil
+}
+
+// TestFromTemplate 读取同命名空间的模板测试，返回复制了其 spec、标签与注解（不含模板注解）的新对象。
+// 模板不存在时返回 found=false。
+func TestFromTemplate(ctx context.Context, reader client.Reader, namespace string, kind infrav1alpha1.TestSuiteItemKind, name string) (client.Object, bool, error) {
+	template, err := NewTestObject(kind)
+	if err != nil {
+		return nil, true, err
+	}
+	if err := reader.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, template); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, false, nil
+		}
+		return nil, true, err
+	}
+	obj, _ := NewTestObject(kind)
+	switch o := obj.(type) {
+	case *infrav1alpha1.IntegrationTest:
+		o.Spec = *template.(*infrav1alpha1.IntegrationTest).Spec.DeepCopy()
+	case *infrav1alpha1.LoadTest:
+		o.Spec = *template.(*infrav1alpha1.LoadTest).Spec.DeepCopy()
+	}
+	labels := map[string]string{}
+	for k, v := range template.GetLabels() {
+		labels[k] = v
+	}
+	annotations := map[string]string{}
+	for k, v := range template.GetAnnotations() {
+		if k != infrav1alpha1.AnnotationTemplate && k != lastAppliedAnnotation {
+			annotations[k] = v
+		}
+	}
+	obj.SetLabels(labels)
+	obj.SetAnnotations(annotations)
+	return obj, true, nil
+}
+
+// TestOutcome 返回测试的阶段与结果：IntegrationTest 的 Succeeded/Failed/Aborted 与 LoadTest 的 Succeeded/Failed 视为结束，
+// 其中只有 Succeeded 视为通过。
+func TestOutcome(obj client.Object) (phase string, finished, passed bool, reason, message string) {
+	switch o := obj.(type) {
+	case *infrav1alpha1.IntegrationTest:
+		switch o.Status.Phase {
+		case infrav1alpha1.IntegrationTestPhaseSucceeded:
+			finished, passed = true, true
+		case infrav1alpha1.IntegrationTestPhaseFailed, infrav1alpha1.IntegrationTestPhaseAborted:
+			finished = true
+		}
+		return string(o.Status.Phase), finished, passed, o.Status.Reason, o.Status.Message
+	case *infrav1alpha1.LoadTest:
+		switch o.Status.Phase {
+		case infrav1alpha1.LoadTestSucceeded:
+			finished, passed = true, true
+		case infrav1alpha1.LoadTestFailed:
+			finished = true
+		}
+		return string(o.Status.Phase), finished, passed, o.Status.Reason, o.Status.Message
+	}
+	return "", false, false, "", ""
+}
+
+// RequestAbort 给测试加上中止注解，已设置或测试不存在时忽略。
+func RequestAbort(ctx context.Context, c client.Client, obj client.Object) error {
+	if _, _, ok := AbortRequested(obj.GetAnnotations()); ok {
+		return nil
+	}
+	patch := client.MergeFrom(obj.DeepCopyObject().(client.Object))
+	annotations := obj.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[infrav1alpha1.AnnotationAbort] = "true"
+	obj.SetAnnotations(annotations)
+	return client.IgnoreNotFound(c.Patch(ctx, obj, patch))
+}
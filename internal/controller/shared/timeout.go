@@ -16,7 +16,13 @@ limitations under the License.
 
 package shared
 
-import "time"
+import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	infrav1alpha1 "github.com/lunz1207/testplane/api/v1alpha1"
+)
 
 // 默认超时常量
 const (
@@ -28,6 +34,8 @@ const (
 	DefaultExpectationTimeout = 5 * time.Minute
 	// DefaultReadyConditionTimeout 默认就绪条件超时（5 分钟）
 	DefaultReadyConditionTimeout = 5 * time.Minute
+	// MaxRetryAfterHint 期望重试提示的上限（10 分钟），避免错误提示让检查长时间停摆
+	MaxRetryAfterHint = 10 * time.Minute
 )
 
 // GetTimeoutDuration 从 int32 秒获取 Duration，如果为 0 或负数返回默认值。
@@ -42,3 +50,35 @@ func GetTimeoutDuration(seconds int32, defaultDuration time.Duration) time.Durat
 func CalculateDeadline(start time.Time, timeout time.Duration) time.Time {
 	return start.Add(timeout)
 }
+
+// RetryAfterHint 返回未通过期望给出的最短重试提示。
+// 任一未通过的期望没有提示（或为执行错误）时返回 0，按默认间隔轮询。
+func RetryAfterHint(results []infrav1alpha1.ExpectationResult) time.Duration {
+	var hint time.Duration
+	for _, result := range results {
+		if result.Passed {
+			continue
+		}
+		if result.Error || result.RetryAfterSeconds <= 0 {
+			return 0
+		}
+		d := time.Duration(result.RetryAfterSeconds) * time.Second
+		if hint == 0 || d < hint {
+			hint = d
+		}
+	}
+	return hint
+}
+
+// HintedRequeue 根据重试提示计算下次检查间隔：不短于 fallback，
+// 不超过 MaxRetryAfterHint，也不越过 deadline（保证超时能被及时判定）。
+func HintedRequeue(hint, fallback time.Duration, deadline *metav1.Time) time.Duration {
+	if hint <= fallback {
+		return fallback
+	}
+	hint = min(hint, MaxRetryAfterHint)
+	if deadline != nil {
+		hint = min(hint, time.Until(deadline.Time))
+	}
+	return max(hint, fallback)
+}
@@ -0,0 +1,195 @@
+package shared
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	infrav1alpha1 "github.com/lunz1207/testplane/api/v1alpha1"
+)
+
+const (
+	// defaultTriageAttempts 分诊调用的默认最大尝试次数。
+	defaultTriageAttempts = 3
+	// TriageRetryInterval 分诊调用失败后的重试间隔。
+	TriageRetryInterval = 30 * time.Second
+	// triageTimeout 单次分诊调用超时。
+	triageTimeout = 10 * time.Second
+)
+
+// triageHTTPClient 分诊 Webhook 使用的 HTTP 客户端。
+var triageHTTPClient = &http.Client{Timeout: triageTimeout}
+
+// TriageRequest 分诊 Webhook 请求体：失败测试的结构化上下文。
+type TriageRequest struct {
+	Kind           string                                   `json:"kind"`
+	Namespace      string                                   `json:"namespace"`
+	Name           string                                   `json:"name"`
+	UID            string                                   `json:"uid"`
+	Reason         string                                   `json:"reason,omitempty"`
+	Message        string                                   `json:"message,omitempty"`
+	Round          int                                      `json:"round,omitempty"`
+	StartTime      *metav1.Time                             `json:"startTime,omitempty"`
+	CompletionTime *metav1.Time                             `json:"completionTime,omitempty"`
+	FailedStep     *TriageFailedStep                        `json:"failedStep,omitempty"`
+	Expectations   []infrav1alpha1.ExpectationResultSummary `json:"expectations,omitempty"`
+	EvidenceLinks  map[string]string                        `json:"evidenceLinks,omitempty"`
+	Artifacts      []infrav1alpha1.ArtifactStatus           `json:"artifacts,omitempty"`
+	Environment    *WebhookEnvironment                      `json:"environment,omitempty"`
+}
+
+// TriageFailedStep 失败步骤信息。
+type TriageFailedStep struct {
+	Name    string `json:"name"`
+	Index   int    `json:"index"`
+	Reason  string `json:"reason,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+// TriageResponse 分诊 Webhook 响应体。
+type TriageResponse struct {
+	KnownIssue bool   `json:"knownIssue,omitempty"`
+	Ticket     string `json:"ticket,omitempty"`
+	URL        string `json:"url,omitempty"`
+	Message    string `json:"message,omitempty"`
+}
+
+// NewTriageRequest 构造请求的公共部分，证据链接与 Environment 取自 spec 和 context。
+func NewTriageRequest(ctx context.Context, kind string, obj metav1.Object, spec *infrav1alpha1.TriageSpec) TriageRequest {
+	return TriageRequest{
+		Kind:          kind,
+		Namespace:     obj.GetNamespace(),
+		Name:          obj.GetName(),
+		UID:           string(obj.GetUID()),
+		EvidenceLinks: spec.EvidenceLinks,
+		Environment:   webhookEnvironment(EnvironmentFromContext(ctx)),
+	}
+}
+
+// FailedExpectations 返回未通过的期望结果。
+func FailedExpectations(results []infrav1alpha1.ExpectationResultSummary) []infrav1alpha1.ExpectationResultSummary {
+	var failed []infrav1alpha1.ExpectationResultSummary
+	for _, r := range results {
+		if !r.Passed {
+			failed = append(failed, r)
+		}
+	}
+	return failed
+}
+
+// TriageDue 判断是否需要（再次）调用分诊 Webhook。
+// 未到重试时间时返回 false 与剩余等待时间；已成功或达到最大尝试次数时返回 false, 0。
+func TriageDue(spec *infrav1alpha1.TriageSpec, status *infrav1alpha1.TriageStatus) (bool, time.Duration) {
+	if spec == nil || spec.Webhook == "" {
+		return false, 0
+	}
+	if status == nil {
+		return true, 0
+	}
+	if status.State == infrav1alpha1.TriageStateSucceeded || TriageExhausted(spec, status) {
+		return false, 0
+	}
+	if status.LastAttemptTime != nil {
+		if wait := time.Until(status.LastAttemptTime.Add(TriageRetryInterval)); wait > 0 {
+			return false, wait
+		}
+	}
+	return true, 0
+}
+
+// TriageExhausted 判断分诊调用是否已失败且达到最大尝试次数。
+func TriageExhausted(spec *infrav1alpha1.TriageSpec, status *infrav1alpha1.TriageStatus) bool {
+	maxAttempts := spec.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = defaultTriageAttempts
+	}
+	return status.State == infrav1alpha1.TriageStateFailed && status.Attempts >= maxAttempts
+}
+
+// TriageEventMessage 生成分诊完成事件消息。
+func TriageEventMessage(triage *infrav1alpha1.TriageStatus) string {
+	msg := "Failure triaged"
+	if triage.KnownIssue {
+		msg = "Known issue"
+	}
+	if triage.Ticket != "" {
+		msg += ": " + triage.Ticket
+	}
+	if triage.Message != "" {
+		msg += " (" + triage.Message + ")"
+	}
+	return msg
+}
+
+// RunTriage 调用分诊 Webhook，返回更新后的分诊状态（Attempts 加 1）。
+// 调用失败记录为 Failed 状态，由调用方按 TriageDue 决定是否重试。
+func RunTriage(ctx context.Context, spec *infrav1alpha1.TriageSpec, status *infrav1alpha1.TriageStatus, req TriageRequest) *infrav1alpha1.TriageStatus {
+	out := &infrav1alpha1.TriageStatus{}
+	if status != nil {
+		out.Attempts = status.Attempts
+	}
+	out.Attempts++
+	now := metav1.Now()
+	out.LastAttemptTime = &now
+
+	resp, err := callTriageWebhook(ctx, spec.Webhook, req)
+	if err != nil {
+		out.State = infrav1alpha1.TriageStateFailed
+		out.Message = truncateTriageMessage(err.Error())
+		return out
+	}
+	out.State = infrav1alpha1.TriageStateSucceeded
+	out.KnownIssue = resp.KnownIssue
+	out.Ticket = resp.Ticket
+	out.URL = resp.URL
+	out.Message = truncateTriageMessage(resp.Message)
+	return out
+}
+
+// callTriageWebhook 发送请求并解析响应，非 2xx 视为失败。
+func callTriageWebhook(ctx context.Context, url string, req TriageRequest) (*TriageResponse, error) {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("marshal triage request: %w", err)
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("build triage request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	httpResp, err := triageHTTPClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("call triage webhook: %w", err)
+	}
+	defer func() { _ = httpResp.Body.Close() }()
+
+	data, err := io.ReadAll(io.LimitReader(httpResp.Body, 1<<20))
+	if err != nil {
+		return nil, fmt.Errorf("read triage response: %w", err)
+	}
+	if httpResp.StatusCode < 200 || httpResp.StatusCode >= 300 {
+		return nil, fmt.Errorf("triage webhook returned status %d", httpResp.StatusCode)
+	}
+	var resp TriageResponse
+	if len(bytes.TrimSpace(data)) > 0 {
+		if err := json.Unmarshal(data, &resp); err != nil {
+			return nil, fmt.Errorf("decode triage response: %w", err)
+		}
+	}
+	return &resp, nil
+}
+
+// truncateTriageMessage 截断分诊消息至 256 字符。
+func truncateTriageMessage(msg string) string {
+	if len(msg) > 256 {
+		return msg[:253] + "..."
+	}
+	return msg
+}
@@ -1,6 +1,8 @@
 package shared
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 )
@@ -19,3 +21,53 @@ func DecodeSpecMap(raw []byte) (map[string]interface{}, error) {
 
 	return m, nil
 }
+
+// NormalizedHash 计算 v 规范化 JSON 的 SHA256：键按字典序排列，去除 null、空对象与空列表，
+// 数值统一按浮点表示。语义相同的写法（字段顺序不同、显式写出空值）得到相同的 hash，
+// 用于判断模板是否真正变化，避免无意义的重新 apply。
+func NormalizedHash(v interface{}) (string, error) {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return "", fmt.Errorf("marshal: %w", err)
+	}
+	var decoded interface{}
+	if err := json.Unmarshal(raw, &decoded); err != nil {
+		return "", fmt.Errorf("unmarshal: %w", err)
+	}
+	// encoding/json 按键排序输出 map，即规范化的键顺序
+	canonical, err := json.Marshal(pruneEmpty(decoded))
+	if err != nil {
+		return "", fmt.Errorf("marshal canonical: %w", err)
+	}
+	sum := sha256.Sum256(canonical)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// pruneEmpty 递归去除 null、空对象与空列表，返回 nil 表示整个值为空。
+func pruneEmpty(v interface{}) interface{} {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(val))
+		for k, item := range val {
+			if pruned := pruneEmpty(item); pruned != nil {
+				out[k] = pruned
+			}
+		}
+		if len(out) == 0 {
+			return nil
+		}
+		return out
+	case []interface{}:
+		if len(val) == 0 {
+			return nil
+		}
+		// 列表元素保持位置，空元素以 null 占位
+		out := make([]interface{}, len(val))
+		for i, item := range val {
+			out[i] = pruneEmpty(item)
+		}
+		return out
+	default:
+		return val
+	}
+}
@@ -0,0 +1,51 @@
+package shared
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/util/jsonpath"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	infrav1alpha1 "github.com/lunz1207/testplane/api/v1alpha1"
+)
+
+// ResolveTestField 读取 testRef 引用的测试（同命名空间）并按 JSONPath 取值。
+// 测试不存在或字段尚未产生时返回 TransientError，由调用方稍后重试。
+func ResolveTestField(ctx context.Context, reader client.Reader, namespace string, ref *infrav1alpha1.TestFieldReference) (string, error) {
+	kind := ref.Kind
+	var obj client.Object
+	switch kind {
+	case "", "IntegrationTest":
+		kind = "IntegrationTest"
+		obj = &infrav1alpha1.IntegrationTest{}
+	case "LoadTest":
+		obj = &infrav1alpha1.LoadTest{}
+	default:
+		return "", fmt.Errorf("unsupported testRef kind %q", ref.Kind)
+	}
+	if err := reader.Get(ctx, client.ObjectKey{Namespace: namespace, Name: ref.Name}, obj); err != nil {
+		return "", NewTransientError(fmt.Errorf("get %s %s: %w", kind, ref.Name, err), ShortRequeueAfter)
+	}
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return "", fmt.Errorf("convert %s: %w", ref.Name, err)
+	}
+
+	path := ref.JSONPath
+	if !strings.HasPrefix(path, "{") {
+		path = "{" + path + "}"
+	}
+	jp := jsonpath.New("testRef")
+	if err := jp.Parse(path); err != nil {
+		return "", fmt.Errorf("invalid jsonPath %q: %w", ref.JSONPath, err)
+	}
+	var buf bytes.Buffer
+	if err := jp.Execute(&buf, content); err != nil {
+		return "", NewTransientError(fmt.Errorf("%s %s: %w", ref.Name, ref.JSONPath, err), ShortRequeueAfter)
+	}
+	return buf.String(), nil
+}
@@ -0,0 +1,51 @@
+package shared
+
+import (
+	"fmt"
+
+	"github.com/prometheus/client_golang/prometheus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	infrav1alpha1 "github.com/lunz1207/testplane/api/v1alpha1"
+)
+
+// ConditionTypeExpectationWarnings 运行中出现过未通过的 Warning 级别期望，保持为 True 直到重跑。
+const ConditionTypeExpectationWarnings = "ExpectationWarnings"
+
+// expectationWarningsCounter Warning 级别期望未通过的次数，按条件类型（StepCondition、ReadyCondition、HealthCheck）与期望显示名统计。
+// 不带测试名称标签，测试删除时无需清理。
+var expectationWarningsCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "testplane_expectation_warnings_total",
+	Help: "Number of failed Warning-severity expectation evaluations by condition kind and expectation.",
+}, []string{"condition", "expectation"})
+
+func init() {
+	ctrlmetrics.Registry.MustRegister(expectationWarningsCounter)
+}
+
+// recordExpectationWarnings 将未通过的 Warning 期望计入指标。
+func recordExpectationWarnings(condition string, warnings []infrav1alpha1.ExpectationResult) {
+	for _, w := range warnings {
+		expectationWarningsCounter.WithLabelValues(condition, ExpectationLabel(w.Name, w.Expect)).Inc()
+	}
+}
+
+// SetExpectationWarnings 结果中有未通过的 Warning 期望时设置 ExpectationWarnings Condition，
+// scope 标明来源（如 "step create"）。返回 Condition 是否变化，调用方据此决定是否需要 patch。
+func SetExpectationWarnings(conditions *[]metav1.Condition, scope string, results []infrav1alpha1.ExpectationResult, generation int64) bool {
+	labels := WarningExpectationLabels(results)
+	if labels == "" {
+		return false
+	}
+	message := fmt.Sprintf("%s: %s", scope, labels)
+	if len(message) > 256 {
+		message = message[:253] + "..."
+	}
+	if existing := GetCondition(*conditions, ConditionTypeExpectationWarnings); existing != nil &&
+		existing.Status == metav1.ConditionTrue && existing.Message == message {
+		return false
+	}
+	SetCondition(conditions, ConditionTypeExpectationWarnings, metav1.ConditionTrue, "WarningExpectationsFailed", message, generation)
+	return true
+}
@@ -0,0 +1,294 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package statusboard
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	infrav1alpha1 "github.com/lunz1207/testplane/api/v1alpha1"
+	"github.com/lunz1207/testplane/internal/controller/shared"
+)
+
+const (
+	// DataKey 状态板 ConfigMap 中测试摘要的键。
+	DataKey = "tests.json"
+	// OmittedKey 超出大小上限、未写入状态板的测试数量的键，没有省略时不设置。
+	OmittedKey = "omitted"
+	// maxBoardBytes 测试摘要的大小上限，低于 ConfigMap 的 1 MiB 限制并为元数据留出余量。
+	maxBoardBytes = 900 * 1024
+	// maxMessageBytes 失败消息的最大字节数。
+	maxMessageBytes = 256
+	// fieldOwner 状态板 ConfigMap 的 SSA 字段所有者。
+	fieldOwner = "statusboard-controller"
+)
+
+// Entry 状态板中单个测试的摘要。
+type Entry struct {
+	Kind  string `json:"kind"`
+	Name  string `json:"name"`
+	Phase string `json:"phase,omitempty"`
+	Round int    `json:"round,omitempty"`
+	// LastFailure 最近一次失败，测试重跑或进入下一轮后保留。
+	LastFailure *Failure `json:"lastFailure,omitempty"`
+}
+
+// Failure 测试失败摘要。
+type Failure struct {
+	Reason  string       `json:"reason,omitempty"`
+	Message string       `json:"message,omitempty"`
+	Round   int          `json:"round,omitempty"`
+	Time    *metav1.Time `json:"time,omitempty"`
+}
+
+// StatusBoardReconciler 为每个命名空间维护一个汇总所有测试的 ConfigMap，
+// 受限环境中的看板只需挂载或读取该对象，无需 CRD 的 list 权限。
+// 调和请求以命名空间为单位：Namespace 为测试所在命名空间，Name 为 ConfigMapName。
+type StatusBoardReconciler struct {
+	client.Client
+	APIReader client.Reader // 读取已有状态板，避免为 ConfigMap 建立集群级缓存
+	// ConfigMapName 状态板 ConfigMap 名称。
+	ConfigMapName string
+	// Debounce 更新事件合并窗口（可选）。
+	Debounce time.Duration
+}
+
+// +kubebuilder:rbac:groups=infra.testplane.io,resources=integrationtests;loadtests,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=configmaps,verbs=get;create;patch
+
+func (r *StatusBoardReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := logf.FromContext(ctx).WithValues("namespace", req.Namespace, "configMap", req.Name)
+
+	var its infrav1alpha1.IntegrationTestList
+	if err := r.List(ctx, &its, client.InNamespace(req.Namespace)); err != nil {
+		return ctrl.Result{}, err
+	}
+	var lts infrav1alpha1.LoadTestList
+	if err := r.List(ctx, &lts, client.InNamespace(req.Namespace)); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	previous, err := r.previousEntries(ctx, req.NamespacedName)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if previous == nil && len(its.Items)+len(lts.Items) == 0 {
+		return ctrl.Result{}, nil
+	}
+
+	entries := make([]Entry, 0, len(its.Items)+len(lts.Items))
+	for i := range its.Items {
+		entries = append(entries, carryLastFailure(integrationTestEntry(&its.Items[i]), previous))
+	}
+	for i := range lts.Items {
+		entries = append(entries, carryLastFailure(loadTestEntry(&lts.Items[i]), previous))
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Kind != entries[j].Kind {
+			return entries[i].Kind < entries[j].Kind
+		}
+		return entries[i].Name < entries[j].Name
+	})
+
+	data, omitted, err := encodeEntries(entries, maxBoardBytes)
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("marshal status board: %w", err)
+	}
+	if omitted > 0 {
+		log.Info("status board exceeds the size limit, omitting tests", "omitted", omitted, "limit", maxBoardBytes)
+	}
+	// SSA 内容未变化时 API Server 不产生写入
+	cm := &corev1.ConfigMap{
+		TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "ConfigMap"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      req.Name,
+			Namespace: req.Namespace,
+			Labels:    map[string]string{"app.kubernetes.io/managed-by": "testplane"},
+		},
+		Data: map[string]string{DataKey: data},
+	}
+	if omitted > 0 {
+		cm.Data[OmittedKey] = strconv.Itoa(omitted)
+	}
+	if err := r.Patch(ctx, cm, client.Apply, client.FieldOwner(fieldOwner), client.ForceOwnership); err != nil {
+		return ctrl.Result{}, fmt.Errorf("apply status board: %w", err)
+	}
+	log.V(1).Info("status board updated", "tests", len(entries)-omitted)
+	return ctrl.Result{}, nil
+}
+
+// previousEntries 读取已有状态板中的摘要（按 kind/name 索引），不存在时返回 nil。
+// 内容无法解析时视为空，由本次调和覆盖。
+func (r *StatusBoardReconciler) previousEntries(ctx context.Context, key types.NamespacedName) (map[string]Entry, error) {
+	var cm corev1.ConfigMap
+	if err := r.APIReader.Get(ctx, key, &cm); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	entries := map[string]Entry{}
+	var list []Entry
+	if err := json.Unmarshal([]byte(cm.Data[DataKey]), &list); err != nil {
+		return entries, nil
+	}
+	for _, e := range list {
+		entries[e.Kind+"/"+e.Name] = e
+	}
+	return entries, nil
+}
+
+// carryLastFailure 当前未失败时沿用状态板中记录的最近一次失败。
+func carryLastFailure(e Entry, previous map[string]Entry) Entry {
+	if e.LastFailure == nil {
+		if prev, ok := previous[e.Kind+"/"+e.Name]; ok {
+			e.LastFailure = prev.LastFailure
+		}
+	}
+	return e
+}
+
+// integrationTestEntry 生成 IntegrationTest 的摘要。
+func integrationTestEntry(it *infrav1alpha1.IntegrationTest) Entry {
+	e := Entry{Kind: "IntegrationTest", Name: it.Name, Phase: string(it.Status.Phase), Round: it.Status.CurrentRound}
+	if it.Status.Phase == infrav1alpha1.IntegrationTestPhaseFailed {
+		e.LastFailure = &Failure{Reason: it.Status.Reason, Message: truncate(it.Status.Message), Round: it.Status.CurrentRound, Time: it.Status.CompletionTime}
+	}
+	return e
+}
+
+// loadTestEntry 生成 LoadTest 的摘要。
+func loadTestEntry(lt *infrav1alpha1.LoadTest) Entry {
+	e := Entry{Kind: "LoadTest", Name: lt.Name, Phase: string(lt.Status.Phase)}
+	if lt.Status.Phase == infrav1alpha1.LoadTestFailed {
+		e.LastFailure = &Failure{Reason: lt.Status.Reason, Message: truncate(lt.Status.Message), Time: lt.Status.CompletionTime}
+	}
+	return e
+}
+
+// truncate 截断失败消息至 maxMessageBytes 字节，避免 ConfigMap 超出大小限制；不截断多字节字符。
+func truncate(msg string) string {
+	if len(msg) <= maxMessageBytes {
+		return msg
+	}
+	cut := maxMessageBytes - len("...")
+	for cut > 0 && !utf8.RuneStart(msg[cut]) {
+		cut--
+	}
+	return msg[:cut] + "..."
+}
+
+// encodeEntries 把按 kind/name 排好序的摘要编码为 JSON 数组（格式同 json.MarshalIndent），总大小不超过 limit。
+// 超出时优先保留有失败记录的测试，其余按顺序保留到上限为止，返回省略的测试数量。
+func encodeEntries(entries []Entry, limit int) (string, int, error) {
+	encoded := make([]string, len(entries))
+	for i, e := range entries {
+		data, err := json.MarshalIndent(e, "  ", "  ")
+		if err != nil {
+			return "", 0, err
+		}
+		encoded[i] = string(data)
+	}
+
+	// "[\n  " 与 "\n]" 共 6 字节，条目之间以 ",\n  " 分隔
+	size := 6
+	keep := make([]bool, len(entries))
+	omitted := 0
+	for _, failed := range []bool{true, false} {
+		for i, e := range entries {
+			if (e.LastFailure != nil) != failed {
+				continue
+			}
+			if size+len(encoded[i])+4 > limit {
+				omitted++
+				continue
+			}
+			size += len(encoded[i]) + 4
+			keep[i] = true
+		}
+	}
+
+	kept := make([]string, 0, len(entries)-omitted)
+	for i := range entries {
+		if keep[i] {
+			kept = append(kept, encoded[i])
+		}
+	}
+	if len(kept) == 0 {
+		return "[]", omitted, nil
+	}
+	return "[\n  " + strings.Join(kept, ",\n  ") + "\n]", omitted, nil
+}
+
+// entryOf 返回测试对象的摘要，非测试对象返回零值。
+func entryOf(obj client.Object) Entry {
+	switch o := obj.(type) {
+	case *infrav1alpha1.IntegrationTest:
+		return integrationTestEntry(o)
+	case *infrav1alpha1.LoadTest:
+		return loadTestEntry(o)
+	}
+	return Entry{}
+}
+
+// summaryChanged 只在摘要字段（阶段、轮次、失败原因）变化时触发更新，忽略步骤进度等其他 status 写入。
+func summaryChanged() predicate.Predicate {
+	return predicate.Funcs{UpdateFunc: func(e event.UpdateEvent) bool {
+		before, _ := json.Marshal(entryOf(e.ObjectOld))
+		after, _ := json.Marshal(entryOf(e.ObjectNew))
+		return string(before) != string(after)
+	}}
+}
+
+// SetupWithManager wires the controller.
+func (r *StatusBoardReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if r.APIReader == nil {
+		r.APIReader = mgr.GetAPIReader()
+	}
+	// 测试事件映射为所在命名空间的状态板
+	toBoard := handler.EnqueueRequestsFromMapFunc(func(_ context.Context, obj client.Object) []reconcile.Request {
+		return []reconcile.Request{{NamespacedName: types.NamespacedName{Namespace: obj.GetNamespace(), Name: r.ConfigMapName}}}
+	})
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("statusboard").
+		Watches(&infrav1alpha1.IntegrationTest{},
+			shared.DebounceUpdates(toBoard, r.Debounce),
+			builder.WithPredicates(summaryChanged())).
+		Watches(&infrav1alpha1.LoadTest{},
+			shared.DebounceUpdates(toBoard, r.Debounce),
+			builder.WithPredicates(summaryChanged())).
+		Complete(r)
+}
@@ -0,0 +1,127 @@
+package statusboard
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	infrav1alpha1 "github.com/lunz1207/testplane/api/v1alpha1"
+)
+
+var _ = ginkgo.Describe("StatusBoard Controller", func() {
+	ctx := context.Background()
+	key := types.NamespacedName{Namespace: "qa", Name: "testplane-status"}
+
+	var applied *corev1.ConfigMap
+
+	newReconciler := func(objs ...client.Object) *StatusBoardReconciler {
+		applied = nil
+		scheme := runtime.NewScheme()
+		Expect(infrav1alpha1.AddToScheme(scheme)).To(Succeed())
+		Expect(corev1.AddToScheme(scheme)).To(Succeed())
+		// SSA 不被 fake client 支持：记录 apply 的 ConfigMap
+		c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).WithInterceptorFuncs(interceptor.Funcs{
+			Patch: func(_ context.Context, _ client.WithWatch, obj client.Object, _ client.Patch, _ ...client.PatchOption) error {
+				applied = obj.(*corev1.ConfigMap).DeepCopy()
+				return nil
+			},
+		}).Build()
+		return &StatusBoardReconciler{Client: c, APIReader: c, ConfigMapName: key.Name}
+	}
+
+	boardEntries := func() []Entry {
+		Expect(applied).NotTo(BeNil())
+		var entries []Entry
+		Expect(json.Unmarshal([]byte(applied.Data[DataKey]), &entries)).To(Succeed())
+		return entries
+	}
+
+	integrationTest := func(name string, phase infrav1alpha1.IntegrationTestPhase, message string) *infrav1alpha1.IntegrationTest {
+		it := &infrav1alpha1.IntegrationTest{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: key.Namespace}}
+		it.Status.Phase = phase
+		it.Status.CurrentRound = 2
+		if phase == infrav1alpha1.IntegrationTestPhaseFailed {
+			it.Status.Reason = "StepFailed"
+			it.Status.Message = message
+		}
+		return it
+	}
+
+	ginkgo.Context("When summarizing tests", func() {
+		ginkgo.It("should list tests sorted by kind and name and keep the last failure", func() {
+			previous, err := json.Marshal([]Entry{{Kind: "IntegrationTest", Name: "smoke",
+				LastFailure: &Failure{Reason: "StepFailed", Message: "timeout", Round: 1}}})
+			Expect(err).NotTo(HaveOccurred())
+			board := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: key.Namespace},
+				Data: map[string]string{DataKey: string(previous)}}
+			lt := &infrav1alpha1.LoadTest{ObjectMeta: metav1.ObjectMeta{Name: "api-load", Namespace: key.Namespace}}
+			lt.Status.Phase = infrav1alpha1.LoadTestSucceeded
+			r := newReconciler(board, lt,
+				integrationTest("smoke", infrav1alpha1.IntegrationTestPhaseRunning, ""),
+				integrationTest("e2e", infrav1alpha1.IntegrationTestPhaseFailed, "step create failed"))
+
+			_, err = r.Reconcile(ctx, ctrl.Request{NamespacedName: key})
+			Expect(err).NotTo(HaveOccurred())
+			entries := boardEntries()
+			Expect(entries).To(HaveLen(3))
+			Expect([]string{entries[0].Name, entries[1].Name, entries[2].Name}).To(Equal([]string{"e2e", "smoke", "api-load"}))
+			Expect(entries[0].LastFailure.Message).To(Equal("step create failed"))
+			Expect(entries[1].Phase).To(Equal("Running"))
+			Expect(entries[1].LastFailure.Message).To(Equal("timeout"))
+			Expect(applied.Data).NotTo(HaveKey(OmittedKey))
+			// 与 json.MarshalIndent 的格式一致
+			indented, err := json.MarshalIndent(entries, "", "  ")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(applied.Data[DataKey]).To(Equal(string(indented)))
+		})
+
+		ginkgo.It("should not create a board for a namespace without tests", func() {
+			r := newReconciler()
+			_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: key})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(applied).To(BeNil())
+		})
+
+		ginkgo.It("should stay under the size limit and keep failed tests first", func() {
+			var objs []client.Object
+			for i := range 10000 {
+				objs = append(objs, integrationTest(fmt.Sprintf("passing-%04d", i), infrav1alpha1.IntegrationTestPhaseSucceeded, ""))
+			}
+			objs = append(objs, integrationTest("zz-failing", infrav1alpha1.IntegrationTestPhaseFailed, strings.Repeat("x", 1000)))
+			r := newReconciler(objs...)
+
+			_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: key})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(len(applied.Data[DataKey])).To(BeNumerically("<=", maxBoardBytes))
+			entries := boardEntries()
+			Expect(applied.Data[OmittedKey]).To(Equal(fmt.Sprint(10001 - len(entries))))
+			Expect(entries[len(entries)-1].Name).To(Equal("zz-failing"))
+			Expect(entries[len(entries)-1].LastFailure.Message).To(HaveLen(maxMessageBytes))
+		})
+	})
+
+	ginkgo.Context("When truncating failure messages", func() {
+		ginkgo.It("should not split multi-byte characters", func() {
+			Expect(truncate("short")).To(Equal("short"))
+			msg := "x" + strings.Repeat("期望检查超时", 50)
+			out := truncate(msg)
+			Expect(utf8.ValidString(out)).To(BeTrue())
+			Expect(out).To(HaveSuffix("..."))
+			Expect(len(out)).To(BeNumerically("<=", maxMessageBytes))
+			Expect(msg).To(HavePrefix(strings.TrimSuffix(out, "...")))
+		})
+	})
+})
@@ -0,0 +1,229 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testsuite
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	infrav1alpha1 "github.com/lunz1207/testplane/api/v1alpha1"
+	"github.com/lunz1207/testplane/internal/controller/shared"
+)
+
+// testName 返回条目创建的测试名称：<suite>-<item>。
+func testName(suite *infrav1alpha1.TestSuite, item string) string {
+	return suite.Name + "-" + item
+}
+
+// specItem 按名称查找条目定义。
+func specItem(suite *infrav1alpha1.TestSuite, name string) infrav1alpha1.TestSuiteItem {
+	for _, item := range suite.Spec.Items {
+		if item.Name == name {
+			return item
+		}
+	}
+	return infrav1alpha1.TestSuiteItem{Name: name}
+}
+
+// validateItems 检查每个条目必须且只能设置 templateRef 与 spec 之一，内联 spec 可按 kind 解析。
+func validateItems(items []infrav1alpha1.TestSuiteItem) error {
+	for _, item := range items {
+		if (item.TemplateRef == nil) == (item.Spec == nil) {
+			return fmt.Errorf("item %q: exactly one of templateRef and spec must be set", item.Name)
+		}
+		if item.Spec != nil {
+			if _, err := shared.DecodeInlineTest(item.Kind, item.Spec); err != nil {
+				return fmt.Errorf("item %q: %w", item.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// skipItems 把处于 state 的条目标记为跳过。
+func skipItems(suite *infrav1alpha1.TestSuite, state infrav1alpha1.TestSuiteItemState, reason string, now metav1.Time) {
+	for i := range suite.Status.Items {
+		st := &suite.Status.Items[i]
+		if st.State != state {
+			continue
+		}
+		st.State = infrav1alpha1.TestSuiteItemSkipped
+		st.Reason = reason
+		st.Message = "skipped: " + reason
+		st.FinishedAt = &now
+	}
+}
+
+// buildTest 由模板或内联 spec 构造条目的测试对象。模板不存在时返回 found=false。
+func (r *TestSuiteReconciler) buildTest(ctx context.Context, suite *infrav1alpha1.TestSuite, item infrav1alpha1.TestSuiteItem) (client.Object, bool, error) {
+	var obj client.Object
+	if item.TemplateRef != nil {
+		template, found, err := shared.TestFromTemplate(ctx, r.Client, suite.Namespace, item.Kind, item.TemplateRef.Name)
+		if err != nil || !found {
+			return nil, found, err
+		}
+		obj = template
+	} else {
+		inline, err := shared.DecodeInlineTest(item.Kind, item.Spec)
+		if err != nil {
+			return nil, true, err
+		}
+		obj = inline
+	}
+
+	obj.SetName(testName(suite, item.Name))
+	obj.SetNamespace(suite.Namespace)
+	labels := obj.GetLabels()
+	labels[infrav1alpha1.LabelSuite] = suite.Name
+	labels[infrav1alpha1.LabelSuiteItem] = item.Name
+	obj.SetLabels(labels)
+	if err := controllerutil.SetControllerReference(suite, obj, r.Scheme); err != nil {
+		return nil, true, err
+	}
+	return obj, true, nil
+}
+
+// startItem 创建条目的测试并进入 Running。测试已存在且归属该套件时视为已创建（缓存滞后的重复调和）；
+// 模板不存在、同名测试不属于该套件或测试无法创建时条目失败。
+func (r *TestSuiteReconciler) startItem(ctx context.Context, suite *infrav1alpha1.TestSuite, item infrav1alpha1.TestSuiteItem, st *infrav1alpha1.TestSuiteItemStatus, now metav1.Time) (itemEvent, error) {
+	log := logf.FromContext(ctx)
+	fail := func(reason, message string) (itemEvent, error) {
+		st.State = infrav1alpha1.TestSuiteItemFailed
+		st.Reason = reason
+		st.Message = message
+		st.StartedAt = &now
+		st.FinishedAt = &now
+		return itemEvent{item: st.Name, eventType: corev1.EventTypeWarning, reason: shared.EventReasonTestSuiteItemFailed,
+			message: fmt.Sprintf("条目 %s 失败: %s", st.Name, message)}, nil
+	}
+
+	obj, found, err := r.buildTest(ctx, suite, item)
+	if err != nil {
+		return itemEvent{}, err
+	}
+	if !found {
+		return fail(ReasonTemplateNotFound, fmt.Sprintf("%s template %q not found", item.Kind, item.TemplateRef.Name))
+	}
+
+	if err := r.Create(ctx, obj); err != nil {
+		switch {
+		case apierrors.IsAlreadyExists(err):
+			existing, _ := shared.NewTestObject(item.Kind)
+			if err := r.Get(ctx, client.ObjectKeyFromObject(obj), existing); err != nil {
+				return itemEvent{}, err
+			}
+			if !metav1.IsControlledBy(existing, suite) {
+				return fail(ReasonNameConflict, fmt.Sprintf("%s %s already exists and is not owned by this suite", item.Kind, obj.GetName()))
+			}
+		case apierrors.IsInvalid(err), apierrors.IsBadRequest(err):
+			return fail(ReasonInvalidSpec, err.Error())
+		default:
+			return itemEvent{}, err
+		}
+	}
+
+	log.Info("suite item started", "item", st.Name, "kind", item.Kind, "test", obj.GetName())
+	st.State = infrav1alpha1.TestSuiteItemRunning
+	st.TestName = obj.GetName()
+	st.StartedAt = &now
+	return itemEvent{item: st.Name, eventType: corev1.EventTypeNormal, reason: shared.EventReasonTestSuiteItemStarted,
+		message: fmt.Sprintf("条目 %s 开始执行: %s %s", st.Name, item.Kind, obj.GetName())}, nil
+}
+
+// observeItem 读取运行中条目的测试，测试结束时记录结果。条目失败时返回对应事件。
+func (r *TestSuiteReconciler) observeItem(ctx context.Context, suite *infrav1alpha1.TestSuite, st *infrav1alpha1.TestSuiteItemStatus, now metav1.Time) (*itemEvent, error) {
+	obj, err := shared.NewTestObject(st.Kind)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.Get(ctx, client.ObjectKey{Namespace: suite.Namespace, Name: st.TestName}, obj); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return nil, err
+		}
+		st.State = infrav1alpha1.TestSuiteItemFailed
+		st.Reason = ReasonTestDeleted
+		st.Message = fmt.Sprintf("%s %s was deleted before it finished", st.Kind, st.TestName)
+		st.FinishedAt = &now
+	} else {
+		phase, state, reason, message := testOutcome(obj)
+		st.Phase = phase
+		if state == infrav1alpha1.TestSuiteItemRunning {
+			return nil, nil
+		}
+		st.State = state
+		st.Reason = reason
+		st.Message = message
+		st.FinishedAt = &now
+	}
+	if st.State != infrav1alpha1.TestSuiteItemFailed {
+		return nil, nil
+	}
+	return &itemEvent{item: st.Name, eventType: corev1.EventTypeWarning, reason: shared.EventReasonTestSuiteItemFailed,
+		message: fmt.Sprintf("条目 %s 失败: %s %s", st.Name, st.Reason, st.Message)}, nil
+}
+
+// testOutcome 把测试阶段映射为条目状态（见 shared.TestOutcome）。
+func testOutcome(obj client.Object) (phase string, state infrav1alpha1.TestSuiteItemState, reason, message string) {
+	phase, finished, passed, reason, message := shared.TestOutcome(obj)
+	switch {
+	case !finished:
+		state = infrav1alpha1.TestSuiteItemRunning
+	case passed:
+		state = infrav1alpha1.TestSuiteItemSucceeded
+	default:
+		state = infrav1alpha1.TestSuiteItemFailed
+	}
+	return phase, state, reason, message
+}
+
+// stopItems 以 infra.testplane.io/abort 注解中止运行中的条目（条目记为失败），并跳过未开始的条目。
+func (r *TestSuiteReconciler) stopItems(ctx context.Context, suite *infrav1alpha1.TestSuite, reason string, now metav1.Time) error {
+	for i := range suite.Status.Items {
+		st := &suite.Status.Items[i]
+		if st.State != infrav1alpha1.TestSuiteItemRunning {
+			continue
+		}
+		if err := r.abortTest(ctx, suite.Namespace, st); err != nil {
+			return err
+		}
+		st.State = infrav1alpha1.TestSuiteItemFailed
+		st.Reason = reason
+		st.Message = "aborted by test suite: " + reason
+		st.FinishedAt = &now
+	}
+	skipItems(suite, infrav1alpha1.TestSuiteItemPending, reason, now)
+	return nil
+}
+
+// abortTest 给条目的测试加上中止注解，已设置或测试不存在时忽略。
+func (r *TestSuiteReconciler) abortTest(ctx context.Context, namespace string, st *infrav1alpha1.TestSuiteItemStatus) error {
+	obj, err := shared.NewTestObject(st.Kind)
+	if err != nil {
+		return err
+	}
+	if err := r.Get(ctx, client.ObjectKey{Namespace: namespace, Name: st.TestName}, obj); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	return shared.RequestAbort(ctx, r.Client, obj)
+}
@@ -0,0 +1,354 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testsuite
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	infrav1alpha1 "github.com/lunz1207/testplane/api/v1alpha1"
+	"github.com/lunz1207/testplane/internal/controller/shared"
+	"github.com/lunz1207/testplane/internal/controller/shared/logging"
+	"github.com/lunz1207/testplane/internal/controller/shared/metrics"
+)
+
+// 套件与条目的结束原因。
+const (
+	// ReasonFailFast 条目失败且设置了 failFast。
+	ReasonFailFast = "FailFast"
+	// ReasonInvalidSpec 条目定义无效（未设置或同时设置 templateRef 与 spec、内联 spec 无法解析）。
+	ReasonInvalidSpec = "InvalidSpec"
+	// ReasonTemplateNotFound 引用的模板不存在。
+	ReasonTemplateNotFound = "TemplateNotFound"
+	// ReasonNameConflict 同名测试已存在且不属于该套件。
+	ReasonNameConflict = "NameConflict"
+	// ReasonTestDeleted 创建的测试在结束前被删除。
+	ReasonTestDeleted = "TestDeleted"
+)
+
+// TestSuiteReconciler reconciles a TestSuite object.
+// 创建的测试通过 ownerReference 归属套件，删除套件时由垃圾回收级联删除，因此无需 finalizer。
+type TestSuiteReconciler struct {
+	client.Client
+	Scheme    *runtime.Scheme
+	APIReader client.Reader // 用于终态判定前绕过缓存读取最新状态
+	Recorder  record.EventRecorder
+	// Debounce 更新事件合并窗口（可选）。
+	Debounce time.Duration
+}
+
+// +kubebuilder:rbac:groups=infra.testplane.io,resources=testsuites,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=infra.testplane.io,resources=testsuites/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=infra.testplane.io,resources=integrationtests;loadtests,verbs=get;list;watch;create;patch
+
+func (r *TestSuiteReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	// 按调和结果记录耗时、重新入队与错误指标
+	ctx, done := metrics.Start(ctx, "testsuite")
+	result, err := r.reconcile(ctx, req)
+	done(result, err)
+	return result, err
+}
+
+func (r *TestSuiteReconciler) reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	baseLog := logf.FromContext(ctx)
+	// API 请求的 User-Agent 附带套件对象，便于 API Server 端按测试归属负载
+	ctx = shared.WithRequestUserAgent(ctx, "TestSuite", req.NamespacedName)
+
+	var suite infrav1alpha1.TestSuite
+	if err := r.Get(ctx, req.NamespacedName, &suite); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	// 添加资源上下文到 logger
+	log := logging.WithKindName(baseLog, "TestSuite", suite.Namespace, suite.Name)
+	ctx = logf.IntoContext(ctx, log)
+
+	if !suite.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, nil
+	}
+
+	res, err := r.reconcileNormal(ctx, &suite)
+	if err != nil {
+		log.Error(err, "reconcile failed")
+	}
+	return res, err
+}
+
+func (r *TestSuiteReconciler) reconcileNormal(ctx context.Context, suite *infrav1alpha1.TestSuite) (ctrl.Result, error) {
+	log := logf.FromContext(ctx)
+
+	if suite.Status.Phase == "" {
+		return r.startSuite(ctx, suite)
+	}
+
+	logging.Reconciling(log, string(suite.Status.Phase))
+
+	if suite.Status.Phase != infrav1alpha1.TestSuitePhaseRunning {
+		return ctrl.Result{}, nil
+	}
+	return r.reconcileRunning(ctx, suite)
+}
+
+// startSuite 初始化条目状态并进入 Running；条目定义无效时直接失败。
+// 开始后的 spec 变更不生效（observedGeneration 记录开始时的 Generation）。
+func (r *TestSuiteReconciler) startSuite(ctx context.Context, suite *infrav1alpha1.TestSuite) (ctrl.Result, error) {
+	now := metav1.Now()
+	suite.Status.Phase = infrav1alpha1.TestSuitePhaseRunning
+	suite.Status.Reason = infrav1alpha1.ReasonRunning
+	suite.Status.Message = ""
+	suite.Status.StartedAt = &now
+	suite.Status.ObservedGeneration = suite.Generation
+	if suite.Spec.TimeoutSeconds > 0 {
+		deadline := metav1.NewTime(now.Add(time.Duration(suite.Spec.TimeoutSeconds) * time.Second))
+		suite.Status.Deadline = &deadline
+	}
+	suite.Status.Items = make([]infrav1alpha1.TestSuiteItemStatus, 0, len(suite.Spec.Items))
+	for _, item := range suite.Spec.Items {
+		suite.Status.Items = append(suite.Status.Items, infrav1alpha1.TestSuiteItemStatus{
+			Name:     item.Name,
+			Kind:     item.Kind,
+			TestName: testName(suite, item.Name),
+			State:    infrav1alpha1.TestSuiteItemPending,
+		})
+	}
+
+	if err := validateItems(suite.Spec.Items); err != nil {
+		skipItems(suite, infrav1alpha1.TestSuiteItemPending, ReasonInvalidSpec, now)
+		return r.finishSuite(ctx, suite, ReasonInvalidSpec, err.Error())
+	}
+
+	shared.SetCondition(&suite.Status.Conditions, infrav1alpha1.ConditionProgressing,
+		metav1.ConditionTrue, infrav1alpha1.ReasonRunning, "test suite started", suite.Generation)
+	if err := shared.PatchTestSuiteStatus(ctx, r.Client, suite.Name, suite.Namespace, suite.Status); err != nil {
+		return ctrl.Result{}, err
+	}
+	shared.EmitKeyedNormalEvent(r.Recorder, suite, shared.EventKey(suite, 0, "", shared.EventReasonTestSuiteStarted),
+		shared.EventReasonTestSuiteStarted, fmt.Sprintf("测试套件开始执行（%s，%d 个条目）", mode(suite), len(suite.Spec.Items)))
+	return ctrl.Result{Requeue: true}, nil
+}
+
+// reconcileRunning 同步运行中条目的结果，按 failFast 与超时停止套件，再按执行方式启动待执行的条目；
+// 全部条目结束后汇总结果。
+func (r *TestSuiteReconciler) reconcileRunning(ctx context.Context, suite *infrav1alpha1.TestSuite) (ctrl.Result, error) {
+	log := logf.FromContext(ctx)
+	original := suite.Status.DeepCopy()
+	now := metav1.Now()
+	var events []itemEvent
+
+	// 1. 同步运行中条目
+	for i := range suite.Status.Items {
+		st := &suite.Status.Items[i]
+		if st.State != infrav1alpha1.TestSuiteItemRunning {
+			continue
+		}
+		ev, err := r.observeItem(ctx, suite, st, now)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if ev != nil {
+			events = append(events, *ev)
+		}
+	}
+
+	// 2. failFast 与超时：中止运行中的条目，跳过未开始的条目
+	if suite.Spec.FailFast && countItems(suite, infrav1alpha1.TestSuiteItemFailed) > 0 {
+		if err := r.stopItems(ctx, suite, ReasonFailFast, now); err != nil {
+			return ctrl.Result{}, err
+		}
+		return r.finishSuite(ctx, suite, ReasonFailFast, "item failed, remaining items stopped by failFast", events...)
+	}
+	if suite.Status.Deadline != nil && now.After(suite.Status.Deadline.Time) {
+		if err := r.stopItems(ctx, suite, infrav1alpha1.ReasonTimeout, now); err != nil {
+			return ctrl.Result{}, err
+		}
+		return r.finishSuite(ctx, suite, infrav1alpha1.ReasonTimeout,
+			fmt.Sprintf("test suite did not finish within %ds", suite.Spec.TimeoutSeconds), events...)
+	}
+
+	// 3. 启动待执行的条目
+	for i := range suite.Status.Items {
+		st := &suite.Status.Items[i]
+		if st.State != infrav1alpha1.TestSuiteItemPending {
+			continue
+		}
+		if !canStart(suite) {
+			break
+		}
+		ev, err := r.startItem(ctx, suite, specItem(suite, st.Name), st, now)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		events = append(events, ev)
+	}
+	// 启动失败的条目同样触发 failFast
+	if suite.Spec.FailFast && countItems(suite, infrav1alpha1.TestSuiteItemFailed) > 0 {
+		if err := r.stopItems(ctx, suite, ReasonFailFast, now); err != nil {
+			return ctrl.Result{}, err
+		}
+		return r.finishSuite(ctx, suite, ReasonFailFast, "item failed, remaining items stopped by failFast", events...)
+	}
+
+	// 4. 全部条目结束：汇总结果
+	if countItems(suite, infrav1alpha1.TestSuiteItemPending)+countItems(suite, infrav1alpha1.TestSuiteItemRunning) == 0 {
+		if failed := countItems(suite, infrav1alpha1.TestSuiteItemFailed); failed > 0 {
+			return r.finishSuite(ctx, suite, infrav1alpha1.ReasonFailed,
+				fmt.Sprintf("%d of %d item(s) failed", failed, len(suite.Status.Items)), events...)
+		}
+		return r.finishSuite(ctx, suite, infrav1alpha1.ReasonSucceeded,
+			fmt.Sprintf("%d item(s) succeeded", len(suite.Status.Items)), events...)
+	}
+
+	updateCounts(suite)
+	if !equality.Semantic.DeepEqual(original, &suite.Status) {
+		// 先 patch，成功后再发 Event
+		if err := shared.PatchTestSuiteStatus(ctx, r.Client, suite.Name, suite.Namespace, suite.Status); err != nil {
+			return ctrl.Result{}, err
+		}
+		r.emitItemEvents(suite, events)
+	}
+	logging.WaitingFor(log, "suite items",
+		"running", countItems(suite, infrav1alpha1.TestSuiteItemRunning),
+		"pending", countItems(suite, infrav1alpha1.TestSuiteItemPending))
+
+	// 条目结果由 Owns 监听推进；设置超时时到期再调和一次
+	if suite.Status.Deadline != nil {
+		return ctrl.Result{RequeueAfter: time.Until(suite.Status.Deadline.Time)}, nil
+	}
+	return ctrl.Result{}, nil
+}
+
+// finishSuite 结束套件：先持久化状态，再发送事件。
+func (r *TestSuiteReconciler) finishSuite(ctx context.Context, suite *infrav1alpha1.TestSuite, reason, message string, events ...itemEvent) (ctrl.Result, error) {
+	// 检查 API Server 最新状态：套件已结束时不再重复发送事件
+	var latest infrav1alpha1.TestSuite
+	if shared.ReadLatest(ctx, r.APIReader, suite, &latest) &&
+		latest.Status.Phase != "" && latest.Status.Phase != infrav1alpha1.TestSuitePhaseRunning {
+		return ctrl.Result{Requeue: true}, nil
+	}
+
+	now := metav1.Now()
+	passed := reason == infrav1alpha1.ReasonSucceeded
+	suite.Status.Reason = reason
+	suite.Status.Message = message
+	suite.Status.CompletionTime = &now
+	updateCounts(suite)
+
+	conditionStatus := metav1.ConditionTrue
+	if passed {
+		suite.Status.Phase = infrav1alpha1.TestSuitePhaseSucceeded
+	} else {
+		suite.Status.Phase = infrav1alpha1.TestSuitePhaseFailed
+		conditionStatus = metav1.ConditionFalse
+	}
+	shared.SetCondition(&suite.Status.Conditions, infrav1alpha1.ConditionReady, conditionStatus, reason, message, suite.Generation)
+	shared.SetCondition(&suite.Status.Conditions, infrav1alpha1.ConditionProgressing,
+		metav1.ConditionFalse, reason, "test suite completed", suite.Generation)
+
+	if err := shared.PatchTestSuiteStatus(ctx, r.Client, suite.Name, suite.Namespace, suite.Status); err != nil {
+		return ctrl.Result{}, err
+	}
+	logf.FromContext(ctx).Info("test suite finished", "phase", suite.Status.Phase, "reason", reason,
+		"succeeded", suite.Status.Succeeded, "failed", suite.Status.Failed, "skipped", suite.Status.Skipped)
+
+	r.emitItemEvents(suite, events)
+	eventType, eventReason := corev1.EventTypeNormal, shared.EventReasonTestSuiteSucceeded
+	if !passed {
+		eventType, eventReason = corev1.EventTypeWarning, shared.EventReasonTestSuiteFailed
+	}
+	shared.EmitKeyedEvent(r.Recorder, suite, shared.EventKey(suite, 0, "", eventReason), eventType, eventReason,
+		fmt.Sprintf("%s（成功 %d，失败 %d，跳过 %d）", message, suite.Status.Succeeded, suite.Status.Failed, suite.Status.Skipped))
+	return ctrl.Result{}, nil
+}
+
+// itemEvent 条目状态变化的事件，在状态持久化后发送。
+type itemEvent struct {
+	item      string
+	eventType string
+	reason    string
+	message   string
+}
+
+// emitItemEvents 发送条目事件，幂等键按条目与状态转换区分。
+func (r *TestSuiteReconciler) emitItemEvents(suite *infrav1alpha1.TestSuite, events []itemEvent) {
+	for _, ev := range events {
+		shared.EmitKeyedEvent(r.Recorder, suite, shared.EventKey(suite, 0, ev.item, ev.reason), ev.eventType, ev.reason, ev.message)
+	}
+}
+
+// canStart 判断按执行方式是否还能启动条目：Sequential 一次一个，Parallel 受 maxConcurrency 限制。
+func canStart(suite *infrav1alpha1.TestSuite) bool {
+	running := countItems(suite, infrav1alpha1.TestSuiteItemRunning)
+	if mode(suite) == infrav1alpha1.TestSuiteSequential {
+		return running == 0
+	}
+	return suite.Spec.MaxConcurrency <= 0 || running < int(suite.Spec.MaxConcurrency)
+}
+
+// mode 返回执行方式，未设置时为 Sequential。
+func mode(suite *infrav1alpha1.TestSuite) infrav1alpha1.TestSuiteMode {
+	if suite.Spec.Mode == "" {
+		return infrav1alpha1.TestSuiteSequential
+	}
+	return suite.Spec.Mode
+}
+
+// countItems 统计处于 state 的条目数。
+func countItems(suite *infrav1alpha1.TestSuite, state infrav1alpha1.TestSuiteItemState) int {
+	n := 0
+	for _, st := range suite.Status.Items {
+		if st.State == state {
+			n++
+		}
+	}
+	return n
+}
+
+// updateCounts 刷新成功、失败与跳过的条目数。
+func updateCounts(suite *infrav1alpha1.TestSuite) {
+	suite.Status.Succeeded = int32(countItems(suite, infrav1alpha1.TestSuiteItemSucceeded))
+	suite.Status.Failed = int32(countItems(suite, infrav1alpha1.TestSuiteItemFailed))
+	suite.Status.Skipped = int32(countItems(suite, infrav1alpha1.TestSuiteItemSkipped))
+}
+
+// SetupWithManager wires the controller.
+func (r *TestSuiteReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if r.Recorder == nil {
+		r.Recorder = shared.NewManagerEventRecorder(mgr, "testsuite")
+	}
+	// 套件只有 spec、标签、注解变化与删除触发调和（更新事件按 Debounce 合并）；
+	// 创建的测试的任何变化（包括 status）都触发所属套件调和
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("testsuite").
+		Watches(&infrav1alpha1.TestSuite{},
+			shared.DebounceUpdates(&handler.EnqueueRequestForObject{}, r.Debounce),
+			builder.WithPredicates(shared.IgnoreSelfUpdates())).
+		Owns(&infrav1alpha1.IntegrationTest{}).
+		Owns(&infrav1alpha1.LoadTest{}).
+		Complete(r)
+}
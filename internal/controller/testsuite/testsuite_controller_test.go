@@ -0,0 +1,226 @@
+package testsuite
+
+import (
+	"context"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	infrav1alpha1 "github.com/lunz1207/testplane/api/v1alpha1"
+	"github.com/lunz1207/testplane/internal/controller/shared"
+)
+
+var _ = Describe("TestSuite Controller", func() {
+	ctx := context.Background()
+
+	var (
+		c        client.Client
+		r        *TestSuiteReconciler
+		recorder *record.FakeRecorder
+	)
+
+	newSuite := func(mode infrav1alpha1.TestSuiteMode, failFast bool, items ...string) *infrav1alpha1.TestSuite {
+		suite := &infrav1alpha1.TestSuite{
+			ObjectMeta: metav1.ObjectMeta{Name: "nightly", Namespace: "default", UID: "suite-uid"},
+			Spec:       infrav1alpha1.TestSuiteSpec{Mode: mode, FailFast: failFast},
+		}
+		for _, name := range items {
+			suite.Spec.Items = append(suite.Spec.Items, infrav1alpha1.TestSuiteItem{
+				Name: name,
+				Kind: infrav1alpha1.TestSuiteItemIntegrationTest,
+				Spec: &runtime.RawExtension{Raw: []byte(`{"steps":[{"name":"check"}]}`)},
+			})
+		}
+		return suite
+	}
+
+	setup := func(objs ...client.Object) {
+		scheme := runtime.NewScheme()
+		Expect(infrav1alpha1.AddToScheme(scheme)).To(Succeed())
+		// status SSA 不被 fake client 支持：套件状态在内存中推进
+		c = fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).WithInterceptorFuncs(interceptor.Funcs{
+			SubResourcePatch: func(context.Context, client.Client, string, client.Object, client.Patch, ...client.SubResourcePatchOption) error {
+				return nil
+			},
+		}).Build()
+		recorder = record.NewFakeRecorder(100)
+		r = &TestSuiteReconciler{Client: c, Scheme: scheme, Recorder: recorder}
+	}
+
+	reconcile := func(suite *infrav1alpha1.TestSuite) ctrl.Result {
+		result, err := r.reconcileNormal(ctx, suite)
+		Expect(err).NotTo(HaveOccurred())
+		return result
+	}
+
+	// finishTest 把条目创建的测试设为终态，模拟 IntegrationTest 控制器
+	finishTest := func(name string, phase infrav1alpha1.IntegrationTestPhase) {
+		var it infrav1alpha1.IntegrationTest
+		Expect(c.Get(ctx, client.ObjectKey{Namespace: "default", Name: name}, &it)).To(Succeed())
+		it.Status.Phase = phase
+		it.Status.Reason = string(phase)
+		Expect(c.Update(ctx, &it)).To(Succeed())
+	}
+
+	createdTests := func() []string {
+		var list infrav1alpha1.IntegrationTestList
+		Expect(c.List(ctx, &list)).To(Succeed())
+		names := []string{}
+		for _, it := range list.Items {
+			names = append(names, it.Name)
+		}
+		return names
+	}
+
+	itemStates := func(suite *infrav1alpha1.TestSuite) []infrav1alpha1.TestSuiteItemState {
+		states := []infrav1alpha1.TestSuiteItemState{}
+		for _, st := range suite.Status.Items {
+			states = append(states, st.State)
+		}
+		return states
+	}
+
+	Context("When items run sequentially", func() {
+		It("should start each item after the previous one finished", func() {
+			suite := newSuite(infrav1alpha1.TestSuiteSequential, false, "first", "second")
+			setup(suite)
+
+			Expect(reconcile(suite).Requeue).To(BeTrue())
+			Expect(suite.Status.Phase).To(Equal(infrav1alpha1.TestSuitePhaseRunning))
+			Expect(itemStates(suite)).To(Equal([]infrav1alpha1.TestSuiteItemState{infrav1alpha1.TestSuiteItemPending, infrav1alpha1.TestSuiteItemPending}))
+
+			reconcile(suite)
+			Expect(createdTests()).To(ConsistOf("nightly-first"))
+			Expect(itemStates(suite)).To(Equal([]infrav1alpha1.TestSuiteItemState{infrav1alpha1.TestSuiteItemRunning, infrav1alpha1.TestSuiteItemPending}))
+
+			// 运行中的条目未结束：不启动下一个
+			reconcile(suite)
+			Expect(createdTests()).To(ConsistOf("nightly-first"))
+
+			finishTest("nightly-first", infrav1alpha1.IntegrationTestPhaseSucceeded)
+			reconcile(suite)
+			Expect(createdTests()).To(ConsistOf("nightly-first", "nightly-second"))
+			Expect(itemStates(suite)).To(Equal([]infrav1alpha1.TestSuiteItemState{infrav1alpha1.TestSuiteItemSucceeded, infrav1alpha1.TestSuiteItemRunning}))
+
+			finishTest("nightly-second", infrav1alpha1.IntegrationTestPhaseSucceeded)
+			reconcile(suite)
+			Expect(suite.Status.Phase).To(Equal(infrav1alpha1.TestSuitePhaseSucceeded))
+			Expect(suite.Status.Reason).To(Equal(infrav1alpha1.ReasonSucceeded))
+			Expect(suite.Status.Succeeded).To(Equal(int32(2)))
+			Expect(suite.Status.CompletionTime).NotTo(BeNil())
+		})
+
+		It("should create tests owned and labeled by the suite", func() {
+			suite := newSuite(infrav1alpha1.TestSuiteSequential, false, "first")
+			setup(suite)
+			reconcile(suite)
+			reconcile(suite)
+
+			var it infrav1alpha1.IntegrationTest
+			Expect(c.Get(ctx, client.ObjectKey{Namespace: "default", Name: "nightly-first"}, &it)).To(Succeed())
+			Expect(metav1.IsControlledBy(&it, suite)).To(BeTrue())
+			Expect(it.Labels).To(HaveKeyWithValue(infrav1alpha1.LabelSuite, "nightly"))
+			Expect(it.Labels).To(HaveKeyWithValue(infrav1alpha1.LabelSuiteItem, "first"))
+			Expect(it.Spec.Steps).To(HaveLen(1))
+			Expect(suite.Status.Items[0].TestName).To(Equal("nightly-first"))
+		})
+
+		It("should fail an item whose test name is taken by an unowned test", func() {
+			suite := newSuite(infrav1alpha1.TestSuiteSequential, false, "first")
+			foreign := &infrav1alpha1.IntegrationTest{ObjectMeta: metav1.ObjectMeta{Name: "nightly-first", Namespace: "default"}}
+			setup(suite, foreign)
+			reconcile(suite)
+			reconcile(suite)
+
+			Expect(suite.Status.Phase).To(Equal(infrav1alpha1.TestSuitePhaseFailed))
+			Expect(suite.Status.Items[0].State).To(Equal(infrav1alpha1.TestSuiteItemFailed))
+			Expect(suite.Status.Items[0].Reason).To(Equal(ReasonNameConflict))
+		})
+	})
+
+	Context("When an item fails", func() {
+		It("should run the remaining items and fail the suite without failFast", func() {
+			suite := newSuite(infrav1alpha1.TestSuiteParallel, false, "first", "second")
+			setup(suite)
+			reconcile(suite)
+			reconcile(suite)
+			Expect(createdTests()).To(ConsistOf("nightly-first", "nightly-second"))
+
+			finishTest("nightly-first", infrav1alpha1.IntegrationTestPhaseFailed)
+			reconcile(suite)
+			Expect(suite.Status.Phase).To(Equal(infrav1alpha1.TestSuitePhaseRunning))
+
+			finishTest("nightly-second", infrav1alpha1.IntegrationTestPhaseSucceeded)
+			reconcile(suite)
+			Expect(suite.Status.Phase).To(Equal(infrav1alpha1.TestSuitePhaseFailed))
+			Expect(suite.Status.Reason).To(Equal(infrav1alpha1.ReasonFailed))
+			Expect(suite.Status.Message).To(Equal("1 of 2 item(s) failed"))
+			Expect(suite.Status.Succeeded).To(Equal(int32(1)))
+			Expect(suite.Status.Failed).To(Equal(int32(1)))
+		})
+
+		It("should abort running items and skip pending ones with failFast", func() {
+			suite := newSuite(infrav1alpha1.TestSuiteParallel, true, "first", "second", "third")
+			suite.Spec.MaxConcurrency = 2
+			setup(suite)
+			reconcile(suite)
+			reconcile(suite)
+			Expect(createdTests()).To(ConsistOf("nightly-first", "nightly-second"))
+
+			finishTest("nightly-first", infrav1alpha1.IntegrationTestPhaseFailed)
+			reconcile(suite)
+			Expect(suite.Status.Phase).To(Equal(infrav1alpha1.TestSuitePhaseFailed))
+			Expect(suite.Status.Reason).To(Equal(ReasonFailFast))
+			Expect(itemStates(suite)).To(Equal([]infrav1alpha1.TestSuiteItemState{
+				infrav1alpha1.TestSuiteItemFailed, infrav1alpha1.TestSuiteItemFailed, infrav1alpha1.TestSuiteItemSkipped,
+			}))
+			Expect(createdTests()).NotTo(ContainElement("nightly-third"))
+
+			var second infrav1alpha1.IntegrationTest
+			Expect(c.Get(ctx, client.ObjectKey{Namespace: "default", Name: "nightly-second"}, &second)).To(Succeed())
+			Expect(second.Annotations).To(HaveKeyWithValue(infrav1alpha1.AnnotationAbort, "true"))
+
+			var events []string
+			for len(recorder.Events) > 0 {
+				events = append(events, <-recorder.Events)
+			}
+			Expect(strings.Join(events, "\n")).To(And(
+				ContainSubstring(shared.EventReasonTestSuiteItemFailed),
+				ContainSubstring(shared.EventReasonTestSuiteFailed)))
+		})
+
+		It("should fail an item whose test was deleted before it finished", func() {
+			suite := newSuite(infrav1alpha1.TestSuiteSequential, false, "first")
+			setup(suite)
+			reconcile(suite)
+			reconcile(suite)
+
+			Expect(c.Delete(ctx, &infrav1alpha1.IntegrationTest{ObjectMeta: metav1.ObjectMeta{Name: "nightly-first", Namespace: "default"}})).To(Succeed())
+			reconcile(suite)
+			Expect(suite.Status.Items[0].Reason).To(Equal(ReasonTestDeleted))
+			Expect(suite.Status.Phase).To(Equal(infrav1alpha1.TestSuitePhaseFailed))
+		})
+	})
+
+	Context("When items are invalid", func() {
+		It("should fail the suite before creating any test", func() {
+			suite := newSuite(infrav1alpha1.TestSuiteSequential, false, "first")
+			suite.Spec.Items[0].TemplateRef = &infrav1alpha1.TestSuiteTemplateReference{Name: "template"}
+			setup(suite)
+			reconcile(suite)
+
+			Expect(suite.Status.Phase).To(Equal(infrav1alpha1.TestSuitePhaseFailed))
+			Expect(suite.Status.Reason).To(Equal(ReasonInvalidSpec))
+			Expect(suite.Status.Items[0].State).To(Equal(infrav1alpha1.TestSuiteItemSkipped))
+			Expect(createdTests()).To(BeEmpty())
+		})
+	})
+})
@@ -0,0 +1,146 @@
+package loadgen
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Loadgen", func() {
+	Context("When parsing flags", func() {
+		It("should parse repeated headers and defaults", func() {
+			cfg, err := ParseFlags([]string{"--url", "http://svc/api", "--header", "A=1", "--header", "B=x=y", "--rps", "5"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cfg.URL).To(Equal("http://svc/api"))
+			Expect(cfg.Method).To(Equal(http.MethodGet))
+			Expect(cfg.Headers).To(Equal(map[string]string{"A": "1", "B": "x=y"}))
+			Expect(cfg.RPS).To(Equal(5.0))
+			Expect(cfg.Concurrency).To(Equal(10))
+			Expect(cfg.Timeout).To(Equal(10 * time.Second))
+		})
+
+		DescribeTable("should reject invalid flags",
+			func(args ...string) {
+				_, err := ParseFlags(args)
+				Expect(err).To(HaveOccurred())
+			},
+			Entry("missing url"),
+			Entry("zero rps", "--url", "http://svc", "--rps", "0"),
+			Entry("negative concurrency", "--url", "http://svc", "--concurrency", "-1"),
+			Entry("malformed header", "--url", "http://svc", "--header", "novalue"),
+		)
+	})
+
+	Context("When aggregating stats", func() {
+		It("should bucket latencies and estimate quantiles", func() {
+			stats := NewStats()
+			Expect(stats.Snapshot().Quantile(0.5)).To(BeZero())
+			for range 9 {
+				stats.Observe(3*time.Millisecond, false)
+			}
+			stats.Observe(time.Minute, true)
+
+			snap := stats.Snapshot()
+			Expect(snap.Requests).To(Equal(int64(10)))
+			Expect(snap.Errors).To(Equal(int64(1)))
+			Expect(snap.Buckets).To(HaveLen(len(latencyBoundsMs) + 1))
+			Expect(snap.Buckets[2]).To(Equal(int64(9)))
+			Expect(snap.Buckets[len(latencyBoundsMs)]).To(Equal(int64(1)))
+			Expect(snap.Quantile(0.5)).To(Equal(int64(5)))
+			Expect(snap.Quantile(1)).To(Equal(latencyBoundsMs[len(latencyBoundsMs)-1]))
+
+			// 快照是副本，后续记录不影响已返回的快照
+			stats.Observe(time.Millisecond, false)
+			Expect(snap.Requests).To(Equal(int64(10)))
+		})
+
+		It("should keep dropped schedules out of the latency quantiles", func() {
+			stats := NewStats()
+			for range 10 {
+				stats.Observe(300*time.Millisecond, false)
+			}
+			// 目标过载：大部分调度因并发已满被丢弃
+			for range 90 {
+				stats.Drop()
+			}
+
+			snap := stats.Snapshot()
+			Expect(snap.Requests).To(Equal(int64(10)))
+			Expect(snap.Errors).To(BeZero())
+			Expect(snap.Dropped).To(Equal(int64(90)))
+			Expect(snap.Buckets[0]).To(BeZero())
+			Expect(snap.Quantile(0.5)).To(Equal(int64(500)))
+			Expect(snap.Quantile(0.99)).To(Equal(int64(500)))
+		})
+
+		It("should merge snapshots and finish only when all workers are done", func() {
+			a, b := NewStats(), NewStats()
+			a.Observe(time.Millisecond, false)
+			b.Observe(time.Millisecond, true)
+			b.Drop()
+			a.MarkDone()
+
+			merged := Merge([]Snapshot{a.Snapshot(), b.Snapshot()})
+			Expect(merged.Requests).To(Equal(int64(2)))
+			Expect(merged.Errors).To(Equal(int64(1)))
+			Expect(merged.Dropped).To(Equal(int64(1)))
+			Expect(merged.Buckets[0]).To(Equal(int64(2)))
+			Expect(merged.Done).To(BeFalse())
+
+			b.MarkDone()
+			Expect(Merge([]Snapshot{a.Snapshot(), b.Snapshot()}).Done).To(BeTrue())
+			Expect(Merge(nil).Done).To(BeFalse())
+		})
+	})
+
+	Context("When driving load", func() {
+		It("should send requests until the duration ends and count error responses", func() {
+			var received atomic.Int64
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				Expect(r.Method).To(Equal(http.MethodPost))
+				Expect(r.Header.Get("X-Test")).To(Equal("1"))
+				if received.Add(1)%2 == 0 {
+					w.WriteHeader(http.StatusInternalServerError)
+				}
+			}))
+			defer server.Close()
+
+			stats := NewStats()
+			Run(context.Background(), Config{
+				URL:         server.URL,
+				Method:      http.MethodPost,
+				Headers:     map[string]string{"X-Test": "1"},
+				Body:        "{}",
+				RPS:         100,
+				Concurrency: 4,
+				Duration:    200 * time.Millisecond,
+				Timeout:     time.Second,
+			}, stats)
+
+			snap := stats.Snapshot()
+			Expect(snap.Done).To(BeTrue())
+			Expect(snap.Requests).To(BeNumerically(">", 5))
+			Expect(snap.Requests).To(BeNumerically("<=", received.Load()))
+			Expect(snap.Errors).To(BeNumerically(">", 0))
+			Expect(snap.Errors).To(BeNumerically("<", snap.Requests))
+		})
+
+		It("should serve the stats snapshot", func() {
+			stats := NewStats()
+			stats.Observe(time.Millisecond, false)
+			rec := httptest.NewRecorder()
+			StatsHandler(stats).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, StatsPath, nil))
+
+			Expect(rec.Code).To(Equal(http.StatusOK))
+			var snap Snapshot
+			Expect(json.Unmarshal(rec.Body.Bytes(), &snap)).To(Succeed())
+			Expect(snap.Requests).To(Equal(int64(1)))
+		})
+	})
+})
@@ -0,0 +1,123 @@
+package loadgen
+
+import (
+	"sync"
+	"time"
+)
+
+// latencyBoundsMs 延迟直方图桶上界（毫秒），最后一个桶容纳超过最大上界的样本。
+// 所有 worker 使用相同的桶，控制器可直接按桶累加后估算分位数。
+var latencyBoundsMs = []int64{1, 2, 5, 10, 20, 50, 100, 200, 500, 1000, 2000, 5000, 10000}
+
+// Snapshot 单个 worker 的统计快照（/stats 响应）。
+type Snapshot struct {
+	// Requests 已完成的请求数。
+	Requests int64 `json:"requests"`
+	// Errors 失败的请求数。
+	Errors int64 `json:"errors"`
+	// Dropped 并发达到上限而未发出的调度次数，不计入 Requests 与延迟直方图。
+	Dropped int64 `json:"dropped"`
+	// Buckets 延迟直方图，长度为 len(latencyBoundsMs)+1。
+	Buckets []int64 `json:"buckets"`
+	// Done 是否已完成施压。
+	Done bool `json:"done"`
+}
+
+// Stats 并发安全的请求统计。
+type Stats struct {
+	mu      sync.Mutex
+	current Snapshot
+}
+
+// NewStats 创建空统计。
+func NewStats() *Stats {
+	return &Stats{current: Snapshot{Buckets: make([]int64, len(latencyBoundsMs)+1)}}
+}
+
+// Observe 记录一次请求结果。
+func (s *Stats) Observe(latency time.Duration, failed bool) {
+	ms := latency.Milliseconds()
+	idx := len(latencyBoundsMs)
+	for i, bound := range latencyBoundsMs {
+		if ms <= bound {
+			idx = i
+			break
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.current.Requests++
+	if failed {
+		s.current.Errors++
+	}
+	s.current.Buckets[idx]++
+}
+
+// Drop 记录一次因并发达到上限而丢弃的调度。
+// 丢弃的调度没有延迟样本，计入直方图会让目标过载时的分位数反而变好。
+func (s *Stats) Drop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.current.Dropped++
+}
+
+// MarkDone 标记施压结束。
+func (s *Stats) MarkDone() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.current.Done = true
+}
+
+// Snapshot 返回当前统计的副本。
+func (s *Stats) Snapshot() Snapshot {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := s.current
+	out.Buckets = append([]int64(nil), s.current.Buckets...)
+	return out
+}
+
+// Merge 汇总多个 worker 的快照，Done 仅在所有快照都完成时为 true。
+func Merge(snapshots []Snapshot) Snapshot {
+	merged := Snapshot{Buckets: make([]int64, len(latencyBoundsMs)+1), Done: len(snapshots) > 0}
+	for _, snap := range snapshots {
+		merged.Requests += snap.Requests
+		merged.Errors += snap.Errors
+		merged.Dropped += snap.Dropped
+		merged.Done = merged.Done && snap.Done
+		for i := range merged.Buckets {
+			if i < len(snap.Buckets) {
+				merged.Buckets[i] += snap.Buckets[i]
+			}
+		}
+	}
+	return merged
+}
+
+// Quantile 按直方图估算延迟分位数（毫秒），返回样本所在桶的上界。
+// 落入溢出桶时返回最大上界；无样本时返回 0。
+func (s Snapshot) Quantile(q float64) int64 {
+	var total int64
+	for _, n := range s.Buckets {
+		total += n
+	}
+	if total == 0 {
+		return 0
+	}
+	rank := int64(q * float64(total))
+	if rank < 1 {
+		rank = 1
+	}
+	var seen int64
+	for i, n := range s.Buckets {
+		seen += n
+		if seen >= rank {
+			if i < len(latencyBoundsMs) {
+				return latencyBoundsMs[i]
+			}
+			break
+		}
+	}
+	return latencyBoundsMs[len(latencyBoundsMs)-1]
+}
@@ -0,0 +1,189 @@
+// Package loadgen 实现 LoadTest 内置 HTTP 负载的 worker。
+//
+// worker 与控制器使用同一镜像（manager loadgen ...），按固定速率发起请求，
+// 并在 /stats 暴露统计快照，由 LoadTest 控制器定期汇总到 status.httpLoad。
+package loadgen
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// StatsPort worker 统计端口。
+const StatsPort = 8089
+
+// StatsPath worker 统计路径。
+const StatsPath = "/stats"
+
+// Config worker 配置。
+type Config struct {
+	URL         string
+	Method      string
+	Headers     map[string]string
+	Body        string
+	RPS         float64
+	Concurrency int
+	Duration    time.Duration
+	Timeout     time.Duration
+	StatsAddr   string
+}
+
+// headerFlag 可重复的 --header Name=Value 参数。
+type headerFlag map[string]string
+
+func (h headerFlag) String() string { return fmt.Sprint(map[string]string(h)) }
+
+func (h headerFlag) Set(v string) error {
+	name, value, ok := strings.Cut(v, "=")
+	if !ok || name == "" {
+		return fmt.Errorf("invalid header %q, expect Name=Value", v)
+	}
+	h[name] = value
+	return nil
+}
+
+// ParseFlags 解析 worker 命令行参数。
+func ParseFlags(args []string) (Config, error) {
+	cfg := Config{Headers: map[string]string{}}
+	fs := flag.NewFlagSet("loadgen", flag.ContinueOnError)
+	fs.StringVar(&cfg.URL, "url", "", "Target URL.")
+	fs.StringVar(&cfg.Method, "method", http.MethodGet, "HTTP method.")
+	fs.Var(headerFlag(cfg.Headers), "header", "Request header Name=Value, repeatable.")
+	fs.StringVar(&cfg.Body, "body", "", "Request body.")
+	fs.Float64Var(&cfg.RPS, "rps", 1, "Requests per second.")
+	fs.IntVar(&cfg.Concurrency, "concurrency", 10, "Max in-flight requests.")
+	fs.DurationVar(&cfg.Duration, "duration", 0, "Load duration, 0 runs until terminated.")
+	fs.DurationVar(&cfg.Timeout, "timeout", 10*time.Second, "Per-request timeout.")
+	fs.StringVar(&cfg.StatsAddr, "stats-address", fmt.Sprintf(":%d", StatsPort), "Address of the stats endpoint.")
+	if err := fs.Parse(args); err != nil {
+		return cfg, err
+	}
+	if cfg.URL == "" {
+		return cfg, errors.New("--url is required")
+	}
+	if cfg.RPS <= 0 || cfg.Concurrency <= 0 {
+		return cfg, errors.New("--rps and --concurrency must be positive")
+	}
+	return cfg, nil
+}
+
+// Main worker 入口，返回进程退出码。
+func Main(args []string) int {
+	cfg, err := ParseFlags(args)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	stats := NewStats()
+	server := &http.Server{Addr: cfg.StatsAddr, Handler: StatsHandler(stats), ReadHeaderTimeout: 5 * time.Second}
+	go func() {
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			fmt.Fprintln(os.Stderr, "stats server:", err)
+			cancel()
+		}
+	}()
+
+	Run(ctx, cfg, stats)
+
+	// 施压结束后继续提供统计，直到 Pod 被删除
+	<-ctx.Done()
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer shutdownCancel()
+	_ = server.Shutdown(shutdownCtx)
+	return 0
+}
+
+// StatsHandler 返回统计快照的 HTTP 处理器。
+func StatsHandler(stats *Stats) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc(StatsPath, func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(stats.Snapshot())
+	})
+	return mux
+}
+
+// Run 按 cfg.RPS 发起请求直到 Duration 到期或 ctx 取消。
+// 并发达到上限时丢弃本次调度（计入 Dropped），避免目标变慢时请求无限堆积。
+func Run(ctx context.Context, cfg Config, stats *Stats) {
+	defer stats.MarkDone()
+
+	if cfg.Duration > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, cfg.Duration)
+		defer cancel()
+	}
+
+	client := &http.Client{Timeout: cfg.Timeout}
+	slots := make(chan struct{}, cfg.Concurrency)
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	ticker := time.NewTicker(time.Duration(float64(time.Second) / cfg.RPS))
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		select {
+		case slots <- struct{}{}:
+		default:
+			stats.Drop()
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-slots }()
+			start := time.Now()
+			err := doRequest(ctx, client, cfg)
+			// 施压结束时被取消的请求不计入统计
+			if ctx.Err() != nil {
+				return
+			}
+			stats.Observe(time.Since(start), err != nil)
+		}()
+	}
+}
+
+// doRequest 发起单个请求，网络错误与 4xx/5xx 响应返回错误。
+func doRequest(ctx context.Context, client *http.Client, cfg Config) error {
+	var body io.Reader
+	if cfg.Body != "" {
+		body = strings.NewReader(cfg.Body)
+	}
+	req, err := http.NewRequestWithContext(ctx, cfg.Method, cfg.URL, body)
+	if err != nil {
+		return err
+	}
+	for name, value := range cfg.Headers {
+		req.Header.Set(name, value)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	_ = resp.Body.Close()
+	if resp.StatusCode >= http.StatusBadRequest {
+		return fmt.Errorf("status %d", resp.StatusCode)
+	}
+	return nil
+}
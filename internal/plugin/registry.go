@@ -13,15 +13,23 @@ import (
 //   - 提取模式：使用 Value 返回提取的值
 type Function func(resource, params map[string]interface{}) Result
 
+// Lookup 按状态键或别名获取当前状态中的资源，name 为空时返回默认被断言资源。
+type Lookup func(name string) (map[string]interface{}, error)
+
+// StateFunction 跨资源函数签名：通过 lookup 获取参数中引用的多个资源。
+type StateFunction func(lookup Lookup, params map[string]interface{}) Result
+
 // Registry 函数注册表。
 type Registry struct {
-	functions map[string]Function
+	functions      map[string]Function
+	stateFunctions map[string]StateFunction
 }
 
 // NewRegistry 创建注册表。
 func NewRegistry() *Registry {
 	return &Registry{
-		functions: make(map[string]Function),
+		functions:      make(map[string]Function),
+		stateFunctions: make(map[string]StateFunction),
 	}
 }
 
@@ -30,6 +38,32 @@ func (r *Registry) Register(name string, fn Function) {
 	r.functions[name] = fn
 }
 
+// RegisterState 注册跨资源函数。
+func (r *Registry) RegisterState(name string, fn StateFunction) {
+	r.stateFunctions[name] = fn
+}
+
+// IsStateFunction 检查 name 是否为跨资源函数。
+func (r *Registry) IsStateFunction(name string) bool {
+	_, ok := r.stateFunctions[name]
+	return ok
+}
+
+// CallState 调用跨资源函数。
+func (r *Registry) CallState(name string, lookup Lookup, paramsJSON []byte) (Result, error) {
+	fn, ok := r.stateFunctions[name]
+	if !ok {
+		return Fail(fmt.Sprintf("unknown function: %s", name)), fmt.Errorf("unknown function: %s", name)
+	}
+
+	params, err := parseParams(paramsJSON)
+	if err != nil {
+		return Fail(fmt.Sprintf("invalid params: %v", err)), err
+	}
+
+	return fn(lookup, params), nil
+}
+
 // Call 调用函数。
 func (r *Registry) Call(name string, resource map[string]interface{}, paramsJSON []byte) (Result, error) {
 	fn, ok := r.functions[name]
@@ -48,15 +82,18 @@ func (r *Registry) Call(name string, resource map[string]interface{}, paramsJSON
 // Has 检查函数是否存在。
 func (r *Registry) Has(name string) bool {
 	_, ok := r.functions[name]
-	return ok
+	return ok || r.IsStateFunction(name)
 }
 
 // Names 返回所有已注册的函数名称。
 func (r *Registry) Names() []string {
-	names := make([]string, 0, len(r.functions))
+	names := make([]string, 0, len(r.functions)+len(r.stateFunctions))
 	for name := range r.functions {
 		names = append(names, name)
 	}
+	for name := range r.stateFunctions {
+		names = append(names, name)
+	}
 	return names
 }
 
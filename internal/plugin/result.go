@@ -1,6 +1,9 @@
 package plugin
 
-import "fmt"
+import (
+	"fmt"
+	"time"
+)
 
 // Result 函数执行结果（统一断言和提取）。
 // 支持两种使用模式：
@@ -15,6 +18,10 @@ type Result struct {
 	Message string
 	// Value 提取的值（提取模式）。
 	Value string
+	// RetryAfter 未通过时建议的下次检查间隔（如平台给出的预计剩余时间），0 表示无提示。
+	RetryAfter time.Duration
+	// Error 断言无法判定（如所需的快照已丢失），记为错误结果而非未通过。
+	Error bool
 }
 
 // Pass 创建成功结果。
@@ -27,6 +34,11 @@ func Fail(msg string) Result {
 	return Result{Passed: false, Message: msg}
 }
 
+// Unknown 创建无法判定的结果。
+func Unknown(msg string) Result {
+	return Result{Passed: false, Error: true, Message: msg}
+}
+
 // Extract 创建提取结果。
 func Extract(value string) Result {
 	return Result{Passed: true, Value: value}
@@ -49,3 +61,9 @@ func (r Result) WithValue(value string) Result {
 	r.Value = value
 	return r
 }
+
+// WithRetryAfter 设置下次检查间隔提示。
+func (r Result) WithRetryAfter(d time.Duration) Result {
+	r.RetryAfter = d
+	return r
+}
@@ -0,0 +1,81 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package artifacts 定义测试工件（从 workload Pod 中复制出的结果文件）的存储。
+// 控制器在测试结束时经 exec + tar 读取 Pod 内的文件，写入 Store 并在 status.artifacts 中记录位置。
+package artifacts
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ObjectRef 工件来源测试（LoadTest）。
+type ObjectRef struct {
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+}
+
+// Store 工件存储。Put 写入 r 的全部内容，返回工件位置与字节数。
+type Store interface {
+	Put(ctx context.Context, ref ObjectRef, name string, r io.Reader) (string, int64, error)
+}
+
+// FileStore 写入本地目录的存储：<dir>/<namespace>/<kind>-<name>/<file>。
+// 目录通常挂载持久卷，或由 sidecar 同步到对象存储。
+type FileStore struct {
+	Dir string
+}
+
+// NewFileStore 创建文件存储。
+func NewFileStore(dir string) *FileStore {
+	return &FileStore{Dir: dir}
+}
+
+// Path 返回工件文件路径。
+func (s *FileStore) Path(ref ObjectRef, name string) string {
+	return filepath.Join(s.Dir, ref.Namespace, fmt.Sprintf("%s-%s", strings.ToLower(ref.Kind), ref.Name), name)
+}
+
+// Put 实现 Store。先写入临时文件，完整写入后再重命名，失败时不留下半截文件。
+func (s *FileStore) Put(_ context.Context, ref ObjectRef, name string, r io.Reader) (string, int64, error) {
+	path := s.Path(ref, name)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return "", 0, fmt.Errorf("create artifact dir: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+name+"-*")
+	if err != nil {
+		return "", 0, fmt.Errorf("create artifact: %w", err)
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	n, err := io.Copy(tmp, r)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return "", n, fmt.Errorf("write artifact: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return "", n, fmt.Errorf("write artifact: %w", err)
+	}
+	return path, n, nil
+}
@@ -0,0 +1,266 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package assertiontest 提供断言函数的离线测试工具。
+// 从 YAML/JSON 夹具加载资源，使用与控制器相同的期望执行引擎运行已注册函数（或 Webhook），
+// 无需连接集群即可验证内置函数与自定义函数的行为。
+//
+// 在 Go 测试中使用：
+//
+//	h := assertiontest.New(assertiontest.WithFunction("MyCheck", myCheck))
+//	res := assertiontest.MustLoadFixture(t, "testdata/pod.yaml")
+//	h.AssertPass(t, res, "PodReady", nil)
+package assertiontest
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+
+	infrav1alpha1 "github.com/lunz1207/testplane/api/v1alpha1"
+	"github.com/lunz1207/testplane/internal/builtins"
+	"github.com/lunz1207/testplane/internal/controller/shared"
+	"github.com/lunz1207/testplane/internal/plugin"
+	"github.com/lunz1207/testplane/pkg/recording"
+)
+
+// Function 自定义断言或提取函数，与控制器中注册的函数签名一致：
+// resource 为被断言资源，params 为期望参数。
+type Function func(resource, params map[string]interface{}) Result
+
+// Result 函数执行结果：断言模式使用 Passed、Actual、Message，提取模式使用 Value。
+type Result struct {
+	Passed  bool
+	Actual  string
+	Message string
+	Value   string
+}
+
+// Option 测试工具选项。
+type Option func(*options)
+
+type options struct {
+	withoutBuiltins bool
+	names           []string
+	functions       map[string]Function
+}
+
+// WithFunction 注册自定义函数，与内置函数同名时覆盖内置函数。
+func WithFunction(name string, fn Function) Option {
+	return func(o *options) {
+		if _, ok := o.functions[name]; !ok {
+			o.names = append(o.names, name)
+		}
+		o.functions[name] = fn
+	}
+}
+
+// WithoutBuiltins 不注册内置函数，只使用 WithFunction 注册的函数。
+func WithoutBuiltins() Option {
+	return func(o *options) {
+		o.withoutBuiltins = true
+	}
+}
+
+// Harness 断言函数测试工具。
+type Harness struct {
+	registry *plugin.Registry
+}
+
+// New 创建测试工具，默认注册全部内置函数。
+func New(opts ...Option) *Harness {
+	o := options{functions: map[string]Function{}}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	registry := plugin.NewRegistry()
+	if !o.withoutBuiltins {
+		builtins.RegisterAll(registry)
+	}
+	for _, name := range o.names {
+		fn := o.functions[name]
+		registry.Register(name, func(resource, params map[string]interface{}) plugin.Result {
+			result := fn(resource, params)
+			return plugin.Result{Passed: result.Passed, Actual: result.Actual, Message: result.Message, Value: result.Value}
+		})
+	}
+	return &Harness{registry: registry}
+}
+
+// Functions 返回已注册的函数名（按名称排序）。
+func (h *Harness) Functions() []string {
+	names := h.registry.Names()
+	sort.Strings(names)
+	return names
+}
+
+// ParseFixtures 解析 YAML/JSON 夹具（支持 --- 分隔的多文档），返回资源列表。
+func ParseFixtures(data []byte) ([]map[string]interface{}, error) {
+	decoder := utilyaml.NewYAMLOrJSONDecoder(bytes.NewReader(data), 4096)
+	var resources []map[string]interface{}
+	for {
+		var obj map[string]interface{}
+		if err := decoder.Decode(&obj); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, fmt.Errorf("decode fixture: %w", err)
+		}
+		if len(obj) == 0 {
+			continue
+		}
+		resources = append(resources, obj)
+	}
+	if len(resources) == 0 {
+		return nil, fmt.Errorf("fixture contains no resources")
+	}
+	return resources, nil
+}
+
+// LoadFixtures 从文件加载夹具中的全部资源。
+func LoadFixtures(path string) ([]map[string]interface{}, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read fixture: %w", err)
+	}
+	return ParseFixtures(data)
+}
+
+// LoadFixture 从文件加载夹具中的第一个资源。
+func LoadFixture(path string) (map[string]interface{}, error) {
+	resources, err := LoadFixtures(path)
+	if err != nil {
+		return nil, err
+	}
+	return resources[0], nil
+}
+
+// MustLoadFixture 加载夹具，失败时终止测试。
+func MustLoadFixture(t testing.TB, path string) map[string]interface{} {
+	t.Helper()
+	res, err := LoadFixture(path)
+	if err != nil {
+		t.Fatalf("load fixture %s: %v", path, err)
+	}
+	return res
+}
+
+// Eval 对资源执行单个函数。params 可为 nil。
+func (h *Harness) Eval(resource map[string]interface{}, function string, params map[string]interface{}) (infrav1alpha1.ExpectationResult, error) {
+	exp := infrav1alpha1.Expectation{Function: function}
+	if params != nil {
+		raw, err := json.Marshal(params)
+		if err != nil {
+			return infrav1alpha1.ExpectationResult{}, fmt.Errorf("marshal params: %w", err)
+		}
+		exp.Params = runtime.RawExtension{Raw: raw}
+	}
+	return h.EvalExpectation(resource, exp)
+}
+
+// EvalExpectation 使用期望执行引擎对资源执行一个期望（与控制器行为一致，支持 Webhook）。
+// relatedResources 需要集群访问，离线执行时返回未通过的结果；
+// 需要关联资源时请直接在夹具的 _related 字段中提供。
+func (h *Harness) EvalExpectation(resource map[string]interface{}, exp infrav1alpha1.Expectation) (infrav1alpha1.ExpectationResult, error) {
+	runner := shared.NewExpectationRunner(h.registry)
+	results, err := runner.RunStepCondition(&infrav1alpha1.StepCondition{
+		AllOf: []infrav1alpha1.Expectation{exp},
+	}, map[string]interface{}{stateKey(resource): resource})
+	if err != nil {
+		return infrav1alpha1.ExpectationResult{Expect: exp.Function, Message: err.Error()}, err
+	}
+	return results.AllOf[0], nil
+}
+
+// Extract 对资源执行提取函数，返回提取值。params 可为 nil。
+func (h *Harness) Extract(resource map[string]interface{}, function string, params map[string]interface{}) (string, error) {
+	var raw []byte
+	if params != nil {
+		var err error
+		if raw, err = json.Marshal(params); err != nil {
+			return "", fmt.Errorf("marshal params: %w", err)
+		}
+	}
+	result, err := h.registry.Call(function, resource, raw)
+	if err != nil {
+		return "", err
+	}
+	return result.Value, nil
+}
+
+// AssertPass 断言函数通过，否则标记测试失败。
+func (h *Harness) AssertPass(t testing.TB, resource map[string]interface{}, function string, params map[string]interface{}) {
+	t.Helper()
+	result, err := h.Eval(resource, function, params)
+	if err != nil {
+		t.Fatalf("%s: %v", function, err)
+	}
+	if !result.Passed {
+		t.Errorf("%s: expected pass, got fail: message=%q actual=%q", function, result.Message, result.Actual)
+	}
+}
+
+// AssertFail 断言函数未通过，否则标记测试失败。
+func (h *Harness) AssertFail(t testing.TB, resource map[string]interface{}, function string, params map[string]interface{}) {
+	t.Helper()
+	result, err := h.Eval(resource, function, params)
+	if err != nil {
+		t.Fatalf("%s: %v", function, err)
+	}
+	if result.Passed {
+		t.Errorf("%s: expected fail, got pass", function)
+	}
+}
+
+// stateKey 生成 apiVersion/kind/name 格式的 state key。
+func stateKey(resource map[string]interface{}) string {
+	apiVersion, _ := resource["apiVersion"].(string)
+	kind, _ := resource["kind"].(string)
+	name := ""
+	if meta, ok := resource["metadata"].(map[string]interface{}); ok {
+		name, _ = meta["name"].(string)
+	}
+	return fmt.Sprintf("%s/%s/%s", apiVersion, kind, name)
+}
+
+// Replay 对录制的快照重新执行期望，返回本地执行结果（allOf 在前）与是否通过。
+// 快照中的状态已包含录制时获取的 _related 关联资源，因此重放时不再获取 relatedResources。
+func (h *Harness) Replay(snapshot recording.Snapshot) ([]infrav1alpha1.ExpectationResult, bool, error) {
+	runner := shared.NewExpectationRunner(h.registry)
+	results, err := runner.RunStepCondition(&infrav1alpha1.StepCondition{
+		AllOf: withoutRelated(snapshot.AllOf),
+		AnyOf: withoutRelated(snapshot.AnyOf),
+	}, snapshot.State)
+	return results.All(), err == nil && results.Passed(), err
+}
+
+// withoutRelated 清除期望的 relatedResources 声明。
+func withoutRelated(exps []infrav1alpha1.Expectation) []infrav1alpha1.Expectation {
+	out := make([]infrav1alpha1.Expectation, len(exps))
+	for i, exp := range exps {
+		exp.RelatedResources = nil
+		out[i] = exp
+	}
+	return out
+}
@@ -0,0 +1,106 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package assertiontest
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	infrav1alpha1 "github.com/lunz1207/testplane/api/v1alpha1"
+	"github.com/lunz1207/testplane/pkg/recording"
+)
+
+var _ = Describe("Harness", func() {
+	resources, err := ParseFixtures([]byte(`
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: settings
+data:
+  mode: "on"
+`))
+
+	// modeIs 自定义断言：data.mode 等于参数 want
+	modeIs := func(resource, params map[string]interface{}) Result {
+		data, _ := resource["data"].(map[string]interface{})
+		if data["mode"] == params["want"] {
+			return Result{Passed: true, Value: data["mode"].(string)}
+		}
+		return Result{Message: "mode mismatch", Actual: data["mode"].(string)}
+	}
+
+	Context("When registering functions", func() {
+		It("should run custom functions alongside the builtins", func() {
+			Expect(err).NotTo(HaveOccurred())
+			h := New(WithFunction("ModeIs", modeIs))
+			Expect(h.Functions()).To(ContainElements("ModeIs", "ConfigMapValueEquals"))
+
+			result, err := h.Eval(resources[0], "ModeIs", map[string]interface{}{"want": "on"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.Passed).To(BeTrue())
+
+			result, err = h.Eval(resources[0], "ModeIs", map[string]interface{}{"want": "off"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.Passed).To(BeFalse())
+			Expect(result.Actual).To(Equal("on"))
+
+			result, err = h.Eval(resources[0], "ConfigMapValueEquals", map[string]interface{}{"key": "mode", "value": "on"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.Passed).To(BeTrue())
+
+			value, err := h.Extract(resources[0], "ModeIs", map[string]interface{}{"want": "on"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(value).To(Equal("on"))
+		})
+
+		It("should only register custom functions without the builtins", func() {
+			h := New(WithoutBuiltins(), WithFunction("ModeIs", modeIs))
+			Expect(h.Functions()).To(Equal([]string{"ModeIs"}))
+		})
+	})
+	Context("When replaying a recorded snapshot", func() {
+		It("should reproduce a recorded failure from the archive", func() {
+			Expect(err).NotTo(HaveOccurred())
+			archive := recording.NewFileArchive(GinkgoT().TempDir())
+			ref := recording.ObjectRef{Kind: "Check", Namespace: "default", Name: "settings"}
+			Expect(archive.Record(recording.Snapshot{
+				Object:    ref,
+				Condition: "StepCondition",
+				AllOf: []infrav1alpha1.Expectation{{
+					Function:         "ConfigMapValueEquals",
+					Params:           runtime.RawExtension{Raw: []byte(`{"key":"mode","value":"off"}`)},
+					RelatedResources: []infrav1alpha1.RelatedResource{{Kind: "Secret", Name: "settings"}},
+				}},
+				State:  map[string]interface{}{"v1/ConfigMap/settings": resources[0]},
+				Passed: false,
+			})).To(Succeed())
+
+			snapshots, err := recording.ReadArchive(archive.Path(ref))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(snapshots).To(HaveLen(1))
+
+			// 录制的状态已包含关联资源：重放不获取 relatedResources，失败与录制时一致
+			results, passed, err := New().Replay(snapshots[0])
+			Expect(err).NotTo(HaveOccurred())
+			Expect(passed).To(Equal(snapshots[0].Passed))
+			Expect(results).To(HaveLen(1))
+			Expect(results[0].Passed).To(BeFalse())
+			Expect(results[0].Actual).To(ContainSubstring(`"on"`))
+		})
+	})
+})
@@ -0,0 +1,182 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package export 定义测试结果导出器：控制器在测试阶段转换和 Check 每轮结束时
+// 生成 Event，交给导出器推送到外部系统。内置 HTTP、S3、Kafka（REST Proxy）三种实现，
+// 自定义导出器实现 Exporter 接口即可。
+package export
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"sigs.k8s.io/yaml"
+
+	infrav1alpha1 "github.com/lunz1207/testplane/api/v1alpha1"
+)
+
+// exportTimeout 单次导出的 HTTP 超时。
+const exportTimeout = 10 * time.Second
+
+// httpClient 内置导出器使用的 HTTP 客户端。
+var httpClient = &http.Client{Timeout: exportTimeout}
+
+// ObjectRef 事件来源（IntegrationTest / LoadTest / Check）。
+type ObjectRef struct {
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	UID       string `json:"uid"`
+}
+
+// Event 导出的事件。
+type Event struct {
+	// Type 事件类型。
+	Type infrav1alpha1.ExportEventType `json:"type"`
+	// Key 幂等键，同一事件重复导出时相同，接收端可据此去重。
+	Key string `json:"key"`
+	// Time 事件时间。
+	Time time.Time `json:"time"`
+	// Object 事件来源。
+	Object ObjectRef `json:"object"`
+	// Labels 来源对象的标签。
+	Labels map[string]string `json:"labels,omitempty"`
+	// Phase 当前阶段（CheckResult 为本轮结果 Passed/Failed）。
+	Phase string `json:"phase"`
+	// PreviousPhase 转换前的阶段（仅 PhaseChanged）。
+	PreviousPhase string `json:"previousPhase,omitempty"`
+	// Reason 阶段原因。
+	Reason string `json:"reason,omitempty"`
+	// Message 阶段消息。
+	Message string `json:"message,omitempty"`
+	// Round IntegrationTest 的轮次或 Check 的轮次（runCount）。
+	Round int `json:"round,omitempty"`
+	// Results 期望结果摘要（CheckResult 为本轮结果）。
+	Results []infrav1alpha1.ExpectationResultSummary `json:"results,omitempty"`
+}
+
+// Exporter 结果导出器。Export 返回错误时控制器只记录日志，不重试。
+type Exporter interface {
+	Export(ctx context.Context, event Event) error
+}
+
+// Multi 依次调用多个导出器，合并返回错误。
+type Multi []Exporter
+
+// Export 实现 Exporter。
+func (m Multi) Export(ctx context.Context, event Event) error {
+	var errs []error
+	for _, e := range m {
+		if err := e.Export(ctx, event); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Credentials 导出器凭证：S3 使用 accessKeyID/secretAccessKey，HTTP 与 Kafka 使用 token。
+type Credentials map[string]string
+
+// 凭证键名。
+const (
+	CredentialAccessKeyID     = "accessKeyID"
+	CredentialSecretAccessKey = "secretAccessKey"
+	CredentialToken           = "token"
+)
+
+// New 根据配置创建内置导出器，配置的事件类型过滤一并生效。
+func New(spec infrav1alpha1.ExporterSpec, creds Credentials) (Exporter, error) {
+	var exporter Exporter
+	switch spec.Type {
+	case infrav1alpha1.ExporterTypeHTTP:
+		if spec.URL == "" {
+			return nil, fmt.Errorf("http exporter requires url")
+		}
+		exporter = &HTTPExporter{URL: spec.URL, Headers: spec.Headers, Token: creds[CredentialToken]}
+	case infrav1alpha1.ExporterTypeS3:
+		if spec.Bucket == "" {
+			return nil, fmt.Errorf("s3 exporter requires bucket")
+		}
+		exporter = &S3Exporter{
+			Endpoint:        spec.URL,
+			Bucket:          spec.Bucket,
+			Region:          spec.Region,
+			Prefix:          spec.Prefix,
+			AccessKeyID:     creds[CredentialAccessKeyID],
+			SecretAccessKey: creds[CredentialSecretAccessKey],
+		}
+	case infrav1alpha1.ExporterTypeKafka:
+		if spec.URL == "" || spec.Topic == "" {
+			return nil, fmt.Errorf("kafka exporter requires url and topic")
+		}
+		exporter = &KafkaExporter{ProxyURL: spec.URL, Topic: spec.Topic, Headers: spec.Headers, Token: creds[CredentialToken]}
+	default:
+		return nil, fmt.Errorf("unknown exporter type %q", spec.Type)
+	}
+	if len(spec.Events) > 0 {
+		exporter = &filtered{events: spec.Events, next: exporter}
+	}
+	return exporter, nil
+}
+
+// filtered 只转发指定类型的事件。
+type filtered struct {
+	events []infrav1alpha1.ExportEventType
+	next   Exporter
+}
+
+// Export 实现 Exporter。
+func (f *filtered) Export(ctx context.Context, event Event) error {
+	for _, t := range f.events {
+		if t == event.Type {
+			return f.next.Export(ctx, event)
+		}
+	}
+	return nil
+}
+
+// Config 控制器级导出器配置（--exporters-config 文件中的一项）。
+// 文件本身可由 Secret 挂载，凭证直接写在 credentials 中。
+type Config struct {
+	infrav1alpha1.ExporterSpec `json:",inline"`
+	// Credentials 凭证，键名同 Secret。
+	Credentials Credentials `json:"credentials,omitempty"`
+}
+
+// LoadConfig 读取控制器级导出器配置文件（YAML 列表）并创建导出器。
+func LoadConfig(path string) (Multi, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read exporters config: %w", err)
+	}
+	var configs []Config
+	if err := yaml.UnmarshalStrict(data, &configs); err != nil {
+		return nil, fmt.Errorf("parse exporters config %s: %w", path, err)
+	}
+	exporters := make(Multi, 0, len(configs))
+	for i, cfg := range configs {
+		exporter, err := New(cfg.ExporterSpec, cfg.Credentials)
+		if err != nil {
+			return nil, fmt.Errorf("exporters config %s[%d]: %w", path, i, err)
+		}
+		exporters = append(exporters, exporter)
+	}
+	return exporters, nil
+}
@@ -0,0 +1,56 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package export
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// kafkaContentType Kafka REST Proxy v2 的 JSON 消息格式。
+const kafkaContentType = "application/vnd.kafka.json.v2+json"
+
+// KafkaExporter 通过 Kafka REST Proxy（v2 API）写入 Topic，不直接连接 Broker。
+// 消息键为 <namespace>/<name>，同一测试的事件落在同一分区，保持顺序。
+type KafkaExporter struct {
+	ProxyURL string
+	Topic    string
+	Headers  map[string]string
+	// Token 非空时作为 Bearer Token 发送。
+	Token string
+}
+
+// kafkaRecord REST Proxy 的单条消息。
+type kafkaRecord struct {
+	Key   string `json:"key"`
+	Value Event  `json:"value"`
+}
+
+// Export 实现 Exporter。
+func (e *KafkaExporter) Export(ctx context.Context, event Event) error {
+	body, err := json.Marshal(map[string][]kafkaRecord{
+		"records": {{Key: event.Object.Namespace + "/" + event.Object.Name, Value: event}},
+	})
+	if err != nil {
+		return fmt.Errorf("marshal event: %w", err)
+	}
+	endpoint := strings.TrimSuffix(e.ProxyURL, "/") + "/topics/" + url.PathEscape(e.Topic)
+	return post(ctx, endpoint, kafkaContentType, body, e.Headers, e.Token)
+}
@@ -0,0 +1,193 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package export
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+	"time"
+)
+
+// defaultS3Region 未设置区域时使用的默认值。
+const defaultS3Region = "us-east-1"
+
+// S3Exporter 每个事件写入一个对象：<prefix>/<namespace>/<kind>/<name>/<time>-<type>.json。
+// 使用 path-style 地址与 SigV4 签名，兼容 AWS S3 与 MinIO 等 S3 协议存储；未配置凭证时匿名写入。
+type S3Exporter struct {
+	// Endpoint 服务端点，默认 https://s3.<region>.amazonaws.com。
+	Endpoint        string
+	Bucket          string
+	Region          string
+	Prefix          string
+	AccessKeyID     string
+	SecretAccessKey string
+}
+
+// Export 实现 Exporter。
+func (e *S3Exporter) Export(ctx context.Context, event Event) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("marshal event: %w", err)
+	}
+
+	region := e.Region
+	if region == "" {
+		region = defaultS3Region
+	}
+	endpoint := e.Endpoint
+	if endpoint == "" {
+		endpoint = "https://s3." + region + ".amazonaws.com"
+	}
+	base, err := url.Parse(strings.TrimSuffix(endpoint, "/"))
+	if err != nil {
+		return fmt.Errorf("invalid s3 endpoint: %w", err)
+	}
+	key := e.objectKey(event)
+	base.Path = "/" + e.Bucket + "/" + key
+	base.RawPath = "/" + uriEncode(e.Bucket, false) + "/" + uriEncode(key, false)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, base.String(), bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("build request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if e.AccessKeyID != "" {
+		signV4(req, body, region, e.AccessKeyID, e.SecretAccessKey, time.Now().UTC())
+	}
+	return do(req)
+}
+
+// objectKey 生成事件的对象键。
+func (e *S3Exporter) objectKey(event Event) string {
+	name := fmt.Sprintf("%s-%s.json", event.Time.UTC().Format("20060102T150405.000000000Z"), event.Type)
+	return strings.TrimPrefix(path.Join(e.Prefix, event.Object.Namespace, strings.ToLower(event.Object.Kind), event.Object.Name, name), "/")
+}
+
+// signV4 按 AWS Signature Version 4 为 S3 请求签名：签名请求上已设置的全部头部与 host。
+func signV4(req *http.Request, body []byte, region, accessKeyID, secretAccessKey string, now time.Time) {
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+	payloadHash := sha256Hex(body)
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	canonical, signedHeaders := canonicalRequest(req, payloadHash)
+	scope := date + "/" + region + "/s3/aws4_request"
+	toSign := stringToSign(amzDate, scope, canonical)
+	signature := v4Signature(secretAccessKey, date, region, toSign)
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		accessKeyID, scope, signedHeaders, signature))
+}
+
+// canonicalRequest 返回 SigV4 的规范请求与签名头列表（小写、按字母排序、分号分隔），Authorization 除外。
+func canonicalRequest(req *http.Request, payloadHash string) (string, string) {
+	headers := map[string]string{"host": req.URL.Host}
+	for name, values := range req.Header {
+		// 签名本身不参与签名（重复签名时请求上已有 Authorization）
+		if strings.EqualFold(name, "Authorization") {
+			continue
+		}
+		trimmed := make([]string, 0, len(values))
+		for _, v := range values {
+			trimmed = append(trimmed, strings.Join(strings.Fields(v), " "))
+		}
+		headers[strings.ToLower(name)] = strings.Join(trimmed, ",")
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	return strings.Join([]string{
+		req.Method,
+		uriEncode(req.URL.Path, false),
+		canonicalQuery(req.URL.Query()),
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n"), signedHeaders
+}
+
+// canonicalQuery 按参数名（相同时按值）排序并编码查询参数。
+func canonicalQuery(query url.Values) string {
+	pairs := make([]string, 0, len(query))
+	for key, values := range query {
+		for _, v := range values {
+			pairs = append(pairs, uriEncode(key, true)+"="+uriEncode(v, true))
+		}
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, "&")
+}
+
+// stringToSign 返回 SigV4 的待签名字符串。
+func stringToSign(amzDate, scope, canonicalRequest string) string {
+	return "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + sha256Hex([]byte(canonicalRequest))
+}
+
+// v4Signature 以日期、区域与服务派生的签名密钥计算签名。
+func v4Signature(secretAccessKey, date, region, stringToSign string) string {
+	key := hmacSHA256([]byte("AWS4"+secretAccessKey), date)
+	key = hmacSHA256(key, region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	return hex.EncodeToString(hmacSHA256(key, stringToSign))
+}
+
+// uriEncode 按 SigV4 规则编码：保留非保留字符，encodeSlash 为 false 时（对象路径）同时保留 "/"。
+func uriEncode(s string, encodeSlash bool) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if ('A' <= c && c <= 'Z') || ('a' <= c && c <= 'z') || ('0' <= c && c <= '9') ||
+			c == '-' || c == '_' || c == '.' || c == '~' || (c == '/' && !encodeSlash) {
+			b.WriteByte(c)
+			continue
+		}
+		fmt.Fprintf(&b, "%%%02X", c)
+	}
+	return b.String()
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
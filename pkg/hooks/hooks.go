@@ -0,0 +1,195 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package hooks 定义控制器的阶段钩子：步骤开始/结束、测试结束与 LoadTest 健康检查。
+// 下游 fork 实现其中一个或多个接口，在 init 中调用 Register（或向自建的 Registry 注册并在 cmd/main.go 中传给控制器），
+// 即可添加自定义报告、配额记账等行为，无需修改调和代码。
+//
+// 钩子在状态写入成功后于调和协程中同步调用，只用于观察：不能修改对象，也不能影响测试结果。
+// 钩子应尽快返回，耗时操作请自行异步处理；panic 会被恢复并记录日志。
+package hooks
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	infrav1alpha1 "github.com/lunz1207/testplane/api/v1alpha1"
+)
+
+// ObjectRef 事件来源（IntegrationTest / LoadTest）。
+type ObjectRef struct {
+	Kind      string
+	Namespace string
+	Name      string
+	UID       string
+	Labels    map[string]string
+}
+
+// StepEvent 步骤开始或结束。
+type StepEvent struct {
+	// Object 步骤所属的测试。
+	Object ObjectRef
+	// Round 当前轮次。
+	Round int
+	// Index 步骤序号（从 0 开始）。
+	Index int
+	// Step 步骤状态（State、Reason、StartedAt/FinishedAt、期望结果摘要等）。
+	Step infrav1alpha1.StepStatus
+}
+
+// TestEvent 测试进入终态（Succeeded / Failed / Aborted）。
+type TestEvent struct {
+	Object  ObjectRef
+	Round   int
+	Phase   string
+	Reason  string
+	Message string
+	// Duration 测试开始到结束的时长，开始时间未知时为 0。
+	Duration time.Duration
+}
+
+// HealthCheckEvent LoadTest 的一次健康检查。
+type HealthCheckEvent struct {
+	Object ObjectRef
+	// CheckCount 已检查次数（含本次）。
+	CheckCount int32
+	// Outcome 检查结果：pass、fail、error（仅可重试错误）、warmup（预热期内，不计入统计）。
+	Outcome string
+	// Results 期望结果摘要。
+	Results []infrav1alpha1.ExpectationResultSummary
+}
+
+// StepStartHook 在步骤开始（资源已 apply）时调用。
+type StepStartHook interface {
+	OnStepStart(ctx context.Context, event StepEvent)
+}
+
+// StepFinishHook 在步骤结束（Succeeded / Failed / Aborted）时调用。
+type StepFinishHook interface {
+	OnStepFinish(ctx context.Context, event StepEvent)
+}
+
+// TestFinishHook 在 IntegrationTest 进入终态时调用。
+type TestFinishHook interface {
+	OnTestFinish(ctx context.Context, event TestEvent)
+}
+
+// HealthCheckHook 在 LoadTest 每次健康检查记录后调用。
+type HealthCheckHook interface {
+	OnHealthCheck(ctx context.Context, event HealthCheckEvent)
+}
+
+// Registry 钩子注册表，按注册顺序调用。nil 表示没有钩子。
+type Registry struct {
+	mu    sync.RWMutex
+	hooks []interface{}
+}
+
+// NewRegistry 创建空的钩子注册表。
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+// Default 默认注册表，cmd/main.go 将其传给各控制器。
+var Default = NewRegistry()
+
+// Register 向默认注册表注册钩子。
+func Register(hook interface{}) error {
+	return Default.Register(hook)
+}
+
+// Register 注册钩子，hook 至少需要实现一个钩子接口。
+func (r *Registry) Register(hook interface{}) error {
+	switch hook.(type) {
+	case StepStartHook, StepFinishHook, TestFinishHook, HealthCheckHook:
+	default:
+		return fmt.Errorf("%T implements no hook interface", hook)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.hooks = append(r.hooks, hook)
+	return nil
+}
+
+// Empty 判断是否没有注册任何钩子，调用方可据此跳过构建事件。
+func (r *Registry) Empty() bool {
+	if r == nil {
+		return true
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return len(r.hooks) == 0
+}
+
+// StepStarted 调用所有 StepStartHook。
+func (r *Registry) StepStarted(ctx context.Context, event StepEvent) {
+	r.each(ctx, "OnStepStart", func(hook interface{}) {
+		if h, ok := hook.(StepStartHook); ok {
+			h.OnStepStart(ctx, event)
+		}
+	})
+}
+
+// StepFinished 调用所有 StepFinishHook。
+func (r *Registry) StepFinished(ctx context.Context, event StepEvent) {
+	r.each(ctx, "OnStepFinish", func(hook interface{}) {
+		if h, ok := hook.(StepFinishHook); ok {
+			h.OnStepFinish(ctx, event)
+		}
+	})
+}
+
+// TestFinished 调用所有 TestFinishHook。
+func (r *Registry) TestFinished(ctx context.Context, event TestEvent) {
+	r.each(ctx, "OnTestFinish", func(hook interface{}) {
+		if h, ok := hook.(TestFinishHook); ok {
+			h.OnTestFinish(ctx, event)
+		}
+	})
+}
+
+// HealthChecked 调用所有 HealthCheckHook。
+func (r *Registry) HealthChecked(ctx context.Context, event HealthCheckEvent) {
+	r.each(ctx, "OnHealthCheck", func(hook interface{}) {
+		if h, ok := hook.(HealthCheckHook); ok {
+			h.OnHealthCheck(ctx, event)
+		}
+	})
+}
+
+// each 依次调用钩子，恢复单个钩子的 panic，不影响其他钩子与调和。
+func (r *Registry) each(ctx context.Context, name string, call func(hook interface{})) {
+	if r == nil {
+		return
+	}
+	r.mu.RLock()
+	hooks := append([]interface{}(nil), r.hooks...)
+	r.mu.RUnlock()
+	for _, hook := range hooks {
+		func() {
+			defer func() {
+				if p := recover(); p != nil {
+					logf.FromContext(ctx).Info("hook panicked", "hook", fmt.Sprintf("%T", hook), "method", name, "panic", fmt.Sprint(p))
+				}
+			}()
+			call(hook)
+		}()
+	}
+}
@@ -0,0 +1,108 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package impact 推导测试涉及的资源类型（GroupKind），用于测试影响分析：
+// 某个 Operator 变更时，CI 按资源类型只选择涉及其 CRD 的测试执行。
+// 资源类型来自测试中的清单与选择器（步骤资源、preStep/postStep、LoadTest 的 target 与 workload），
+// 格式与 schema.GroupKind.String() 一致，如 Cluster.infra.example.io、Deployment.apps，核心组只有 Kind（如 ConfigMap）。
+package impact
+
+import (
+	"encoding/json"
+	"sort"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	infrav1alpha1 "github.com/lunz1207/testplane/api/v1alpha1"
+)
+
+// IntegrationTest 返回 IntegrationTest 涉及的资源类型（去重并排序）。
+func IntegrationTest(spec *infrav1alpha1.IntegrationTestSpec) []string {
+	var refs []infrav1alpha1.ResourceRef
+	for _, step := range spec.Steps {
+		refs = append(refs, step.PreStep...)
+		if step.Resource != nil {
+			refs = append(refs, *step.Resource)
+		}
+		refs = append(refs, step.PostStep...)
+	}
+	return kinds(refs)
+}
+
+// LoadTest 返回 LoadTest 涉及的资源类型（去重并排序）。内置 HTTP 负载与监控资源由控制器生成，不计入。
+func LoadTest(spec *infrav1alpha1.LoadTestSpec) []string {
+	refs := append([]infrav1alpha1.ResourceRef{spec.Target.Resource}, spec.Workload.Resources...)
+	return kinds(refs)
+}
+
+// Labels 返回资源类型对应的标签（touches.infra.testplane.io/<Kind.group>: "true"）。
+// 不是合法标签键的资源类型（如组名过长）跳过，仍可从 status.touchedKinds 中查询。
+func Labels(kinds []string) map[string]string {
+	labels := map[string]string{}
+	for _, kind := range kinds {
+		key := infrav1alpha1.LabelTouchedKindPrefix + kind
+		if len(validation.IsQualifiedName(key)) == 0 {
+			labels[key] = "true"
+		}
+	}
+	return labels
+}
+
+// kinds 收集引用的资源类型。未合成 base 的引用与无法解析的清单跳过。
+func kinds(refs []infrav1alpha1.ResourceRef) []string {
+	seen := map[string]bool{}
+	for _, ref := range refs {
+		if gk, ok := refKind(ref); ok {
+			seen[gk.String()] = true
+		}
+	}
+	if len(seen) == 0 {
+		return nil
+	}
+	out := make([]string, 0, len(seen))
+	for kind := range seen {
+		out = append(out, kind)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// refKind 返回清单或选择器的资源类型。
+func refKind(ref infrav1alpha1.ResourceRef) (schema.GroupKind, bool) {
+	if ref.Selector != nil {
+		return groupKind(ref.Selector.APIVersion, ref.Selector.Kind)
+	}
+	if len(ref.Manifest.Raw) == 0 {
+		return schema.GroupKind{}, false
+	}
+	var typeMeta struct {
+		APIVersion string `json:"apiVersion"`
+		Kind       string `json:"kind"`
+	}
+	if err := json.Unmarshal(ref.Manifest.Raw, &typeMeta); err != nil {
+		return schema.GroupKind{}, false
+	}
+	return groupKind(typeMeta.APIVersion, typeMeta.Kind)
+}
+
+// groupKind 由 apiVersion 与 kind 构造 GroupKind，kind 为空时返回 false。
+func groupKind(apiVersion, kind string) (schema.GroupKind, bool) {
+	if kind == "" {
+		return schema.GroupKind{}, false
+	}
+	return schema.FromAPIVersionAndKind(apiVersion, kind).GroupKind(), true
+}
@@ -0,0 +1,181 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package lint 检查 IntegrationTest/LoadTest 中容易导致测试不可靠或无法结束的写法。
+// 规则只检查 spec 本身，不访问集群；`testplane lint` 使用本包在 CI 中拦截高风险的测试定义。
+package lint
+
+import (
+	"encoding/json"
+	"fmt"
+
+	infrav1alpha1 "github.com/lunz1207/testplane/api/v1alpha1"
+)
+
+// Severity 问题级别。
+type Severity string
+
+const (
+	// SeverityError 测试可能无法结束或结果不可信。
+	SeverityError Severity = "error"
+	// SeverityWarning 不推荐的写法。
+	SeverityWarning Severity = "warning"
+)
+
+// 规则名称。
+const (
+	RuleStepWithoutExpectations = "step-without-expectations"
+	RuleMissingTimeout          = "missing-timeout"
+	RuleUnboundedRepeat         = "unbounded-repeat"
+	RuleUnboundedDuration       = "unbounded-duration"
+	RuleWildcardSelector        = "wildcard-selector"
+	RuleMissingHealthCheck      = "missing-health-check"
+)
+
+// Finding 单条检查结果。
+type Finding struct {
+	// Kind 资源类型（IntegrationTest 或 LoadTest）。
+	Kind string `json:"kind"`
+	// Namespace 资源命名空间。
+	Namespace string `json:"namespace,omitempty"`
+	// Name 资源名称。
+	Name string `json:"name"`
+	// Rule 规则名称。
+	Rule string `json:"rule"`
+	// Severity 问题级别。
+	Severity Severity `json:"severity"`
+	// Path 问题所在的字段路径，如 spec.steps[1].timeoutSeconds。
+	Path string `json:"path"`
+	// Message 问题描述。
+	Message string `json:"message"`
+}
+
+// Object 对解析后的资源执行检查，不支持的 Kind 返回 nil。
+func Object(obj map[string]interface{}) ([]Finding, error) {
+	kind, _ := obj["kind"].(string)
+	switch kind {
+	case "IntegrationTest":
+		var it infrav1alpha1.IntegrationTest
+		if err := convert(obj, &it); err != nil {
+			return nil, err
+		}
+		return IntegrationTest(&it), nil
+	case "LoadTest":
+		var lt infrav1alpha1.LoadTest
+		if err := convert(obj, &lt); err != nil {
+			return nil, err
+		}
+		return LoadTest(&lt), nil
+	}
+	return nil, nil
+}
+
+// convert 将通用对象转换为类型化的资源。
+func convert(obj map[string]interface{}, out interface{}) error {
+	raw, err := json.Marshal(obj)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(raw, out); err != nil {
+		return fmt.Errorf("decode %v: %w", obj["kind"], err)
+	}
+	return nil
+}
+
+// IntegrationTest 检查 IntegrationTest。
+func IntegrationTest(it *infrav1alpha1.IntegrationTest) []Finding {
+	r := reporter{kind: "IntegrationTest", namespace: it.Namespace, name: it.Name}
+	r.steps("spec.steps", it.Spec.Steps)
+
+	if repeat := it.Spec.Repeat; repeat != nil && repeat.Count == 0 && repeat.MaxDurationSeconds == 0 {
+		r.add(RuleUnboundedRepeat, SeverityError, "spec.repeat",
+			"repeat has neither count nor maxDurationSeconds; the test runs until deleted")
+	}
+	return r.findings
+}
+
+// LoadTest 检查 LoadTest。
+func LoadTest(lt *infrav1alpha1.LoadTest) []Finding {
+	r := reporter{kind: "LoadTest", namespace: lt.Namespace, name: lt.Name}
+
+	if sel := lt.Spec.Target.Resource.Selector; sel != nil {
+		r.selector("spec.target.resource.selector", sel)
+	}
+	if lt.Spec.DurationSeconds == 0 {
+		r.add(RuleUnboundedDuration, SeverityWarning, "spec.durationSeconds",
+			"durationSeconds is not set; load runs until the LoadTest is deleted")
+	}
+	if lt.Spec.HealthCheck == nil || len(lt.Spec.HealthCheck.AllOf)+len(lt.Spec.HealthCheck.AnyOf) == 0 {
+		r.add(RuleMissingHealthCheck, SeverityWarning, "spec.healthCheck",
+			"no health check expectations; the LoadTest cannot fail while running")
+	}
+	if pv := lt.Spec.PostVerification; pv != nil {
+		r.steps("spec.postVerification.steps", pv.Steps)
+	}
+	return r.findings
+}
+
+// reporter 收集单个资源的检查结果。
+type reporter struct {
+	kind, namespace, name string
+	findings              []Finding
+}
+
+func (r *reporter) add(rule string, severity Severity, path, message string) {
+	r.findings = append(r.findings, Finding{
+		Kind:      r.kind,
+		Namespace: r.namespace,
+		Name:      r.name,
+		Rule:      rule,
+		Severity:  severity,
+		Path:      path,
+		Message:   message,
+	})
+}
+
+// steps 检查步骤列表。
+// Delete 步骤总会等待资源删除完成（受 timeoutSeconds 约束），因此只需检查超时。
+func (r *reporter) steps(path string, steps []infrav1alpha1.TestStep) {
+	for i, step := range steps {
+		stepPath := fmt.Sprintf("%s[%d]", path, i)
+		deleting := step.Resource != nil && step.Resource.Action == infrav1alpha1.TemplateActionDelete
+
+		if !deleting && !hasExpectations(step.Expectations) {
+			r.add(RuleStepWithoutExpectations, SeverityWarning, stepPath+".expectations",
+				fmt.Sprintf("step %q has no expectations; it succeeds as soon as the resource converges", step.Name))
+		}
+		if step.TimeoutSeconds == 0 {
+			r.add(RuleMissingTimeout, SeverityWarning, stepPath+".timeoutSeconds",
+				fmt.Sprintf("step %q has no timeoutSeconds; the default of 600s applies", step.Name))
+		}
+		if step.Resource != nil && step.Resource.Selector != nil {
+			r.selector(stepPath+".resource.selector", step.Resource.Selector)
+		}
+	}
+}
+
+// selector 检查选择器是否限定了名称、标签或注解。
+func (r *reporter) selector(path string, sel *infrav1alpha1.ResourceSelector) {
+	if sel.Name == "" && len(sel.LabelSelector) == 0 && len(sel.AnnotationSelector) == 0 {
+		r.add(RuleWildcardSelector, SeverityWarning, path,
+			fmt.Sprintf("selector matches every %s in the namespace; set name, labelSelector or annotationSelector", sel.Kind))
+	}
+}
+
+// hasExpectations 判断条件中是否声明了期望。
+func hasExpectations(condition *infrav1alpha1.StepCondition) bool {
+	return condition != nil && len(condition.AllOf)+len(condition.AnyOf) > 0
+}
@@ -0,0 +1,170 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lint
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"sigs.k8s.io/yaml"
+)
+
+var _ = Describe("Lint", func() {
+	// lint 解析 YAML 并返回 rule@path 形式的检查结果
+	lint := func(manifest string) []string {
+		var obj map[string]interface{}
+		Expect(yaml.Unmarshal([]byte(manifest), &obj)).To(Succeed())
+		findings, err := Object(obj)
+		Expect(err).NotTo(HaveOccurred())
+		out := []string{}
+		for _, f := range findings {
+			Expect(f.Name).To(Equal("sample"))
+			out = append(out, f.Rule+"@"+f.Path)
+		}
+		return out
+	}
+
+	// 无问题的 LoadTest，各用例在其上修改
+	const healthyLoadTest = `
+kind: LoadTest
+metadata: {name: sample}
+spec:
+  durationSeconds: 600
+  target:
+    resource:
+      selector: {apiVersion: apps/v1, kind: Deployment, name: web}
+  healthCheck:
+    allOf: [{function: DeploymentReady}]
+`
+
+	DescribeTable("should report IntegrationTest rules",
+		func(spec string, expected ...string) {
+			Expect(lint("kind: IntegrationTest\nmetadata: {name: sample}\nspec:\n" + spec)).To(ConsistOf(expected))
+		},
+		Entry("a well-formed test", `
+  steps:
+    - name: check
+      timeoutSeconds: 60
+      expectations: {allOf: [{function: PodReady}]}
+`),
+		Entry(RuleStepWithoutExpectations, `
+  steps:
+    - name: create
+      timeoutSeconds: 60
+`, RuleStepWithoutExpectations+"@spec.steps[0].expectations"),
+		Entry("delete steps without expectations", `
+  steps:
+    - name: cleanup
+      timeoutSeconds: 60
+      resource: {action: Delete, selector: {apiVersion: v1, kind: ConfigMap, name: cm}}
+`),
+		Entry(RuleMissingTimeout, `
+  steps:
+    - name: check
+      expectations: {anyOf: [{function: PodReady}]}
+`, RuleMissingTimeout+"@spec.steps[0].timeoutSeconds"),
+		Entry(RuleWildcardSelector, `
+  steps:
+    - name: check
+      timeoutSeconds: 60
+      resource: {selector: {apiVersion: v1, kind: Pod}}
+      expectations: {allOf: [{function: PodReady}]}
+`, RuleWildcardSelector+"@spec.steps[0].resource.selector"),
+		Entry("selectors narrowed by labels", `
+  steps:
+    - name: check
+      timeoutSeconds: 60
+      resource: {selector: {apiVersion: v1, kind: Pod, labelSelector: {app: web}}}
+      expectations: {allOf: [{function: PodReady}]}
+`),
+		Entry(RuleUnboundedRepeat, `
+  repeat: {untilFailure: true}
+  steps:
+    - name: check
+      timeoutSeconds: 60
+      expectations: {allOf: [{function: PodReady}]}
+`, RuleUnboundedRepeat+"@spec.repeat"),
+		Entry("repeats bounded by a duration", `
+  repeat: {maxDurationSeconds: 3600}
+  steps:
+    - name: check
+      timeoutSeconds: 60
+      expectations: {allOf: [{function: PodReady}]}
+`),
+	)
+
+	DescribeTable("should report LoadTest rules",
+		func(manifest string, expected ...string) {
+			Expect(lint(manifest)).To(ConsistOf(expected))
+		},
+		Entry("a well-formed test", healthyLoadTest),
+		Entry(RuleUnboundedDuration, `
+kind: LoadTest
+metadata: {name: sample}
+spec:
+  target:
+    resource:
+      selector: {apiVersion: apps/v1, kind: Deployment, name: web}
+  healthCheck:
+    allOf: [{function: DeploymentReady}]
+`, RuleUnboundedDuration+"@spec.durationSeconds"),
+		Entry(RuleMissingHealthCheck, `
+kind: LoadTest
+metadata: {name: sample}
+spec:
+  durationSeconds: 600
+  target:
+    resource:
+      selector: {apiVersion: apps/v1, kind: Deployment, name: web}
+  healthCheck: {}
+`, RuleMissingHealthCheck+"@spec.healthCheck"),
+		Entry(RuleWildcardSelector, `
+kind: LoadTest
+metadata: {name: sample}
+spec:
+  durationSeconds: 600
+  target:
+    resource:
+      selector: {apiVersion: apps/v1, kind: Deployment}
+  healthCheck:
+    allOf: [{function: DeploymentReady}]
+`, RuleWildcardSelector+"@spec.target.resource.selector"),
+		Entry("post verification steps", `
+kind: LoadTest
+metadata: {name: sample}
+spec:
+  durationSeconds: 600
+  target:
+    resource:
+      selector: {apiVersion: apps/v1, kind: Deployment, name: web}
+  healthCheck:
+    allOf: [{function: DeploymentReady}]
+  postVerification:
+    steps:
+      - name: verify
+`, RuleStepWithoutExpectations+"@spec.postVerification.steps[0].expectations",
+			RuleMissingTimeout+"@spec.postVerification.steps[0].timeoutSeconds"),
+	)
+
+	It("should skip unsupported kinds", func() {
+		Expect(lint("kind: ConfigMap\nmetadata: {name: sample}")).To(BeEmpty())
+	})
+
+	It("should reject objects that do not decode", func() {
+		_, err := Object(map[string]interface{}{"kind": "IntegrationTest", "spec": "invalid"})
+		Expect(err).To(HaveOccurred())
+	})
+})
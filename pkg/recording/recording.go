@@ -0,0 +1,170 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package recording 定义期望检查状态快照的录制格式与归档。
+// 每次期望检查的输入（期望定义 + 收集到的资源状态）与结果被追加到归档中，
+// 之后可使用 `testplane replay` 在本地对录制的状态重新执行期望，复现历史失败。
+//
+// 录制的状态是资源的完整内容（含 _related 关联资源），可能包含 Secret 数据等敏感信息：
+// 归档文件只对控制器进程可读，归档目录应按敏感数据对待。
+package recording
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	infrav1alpha1 "github.com/lunz1207/testplane/api/v1alpha1"
+)
+
+// ObjectRef 录制来源（IntegrationTest / LoadTest / Check）。
+type ObjectRef struct {
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+}
+
+// Snapshot 一次期望检查的录制快照。
+type Snapshot struct {
+	// Time 检查时间。
+	Time time.Time `json:"time"`
+	// Object 录制来源。
+	Object ObjectRef `json:"object"`
+	// Condition 条件类型：StepCondition、ReadyCondition、HealthCheck。
+	Condition string `json:"condition"`
+	// AllOf / AnyOf 执行的期望。
+	AllOf []infrav1alpha1.Expectation `json:"allOf,omitempty"`
+	AnyOf []infrav1alpha1.Expectation `json:"anyOf,omitempty"`
+	// State 收集到的资源状态（含已获取的 _related 关联资源）。
+	State map[string]interface{} `json:"state"`
+	// Results 期望结果。
+	Results []infrav1alpha1.ExpectationResult `json:"results,omitempty"`
+	// Passed 整体是否通过。
+	Passed bool `json:"passed"`
+	// Error 执行错误（如函数未注册）。
+	Error string `json:"error,omitempty"`
+}
+
+// Archive 快照归档。
+type Archive interface {
+	Record(snapshot Snapshot) error
+}
+
+// DefaultMaxBytes 单个归档文件的默认大小上限。
+const DefaultMaxBytes int64 = 16 << 20
+
+// FileArchive 按来源对象写入 JSON Lines 文件的归档：<dir>/<namespace>/<kind>-<name>.jsonl。
+// 文件超过 MaxBytes 时轮转为 <kind>-<name>.jsonl.1（覆盖上一次轮转的文件），
+// 每个来源对象最多占用约 2×MaxBytes；MaxBytes 不大于 0 时不限制。
+type FileArchive struct {
+	Dir      string
+	MaxBytes int64
+
+	mu sync.Mutex
+}
+
+// NewFileArchive 创建文件归档，单个文件上限为 DefaultMaxBytes。
+func NewFileArchive(dir string) *FileArchive {
+	return &FileArchive{Dir: dir, MaxBytes: DefaultMaxBytes}
+}
+
+// Path 返回来源对象对应的归档文件路径。
+func (a *FileArchive) Path(ref ObjectRef) string {
+	return filepath.Join(a.Dir, ref.Namespace, fmt.Sprintf("%s-%s.jsonl", strings.ToLower(ref.Kind), ref.Name))
+}
+
+// Record 追加一条快照，写入后超过大小上限时先轮转。
+func (a *FileArchive) Record(snapshot Snapshot) error {
+	line, err := json.Marshal(snapshot)
+	if err != nil {
+		return fmt.Errorf("marshal snapshot: %w", err)
+	}
+	line = append(line, '\n')
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	path := a.Path(snapshot.Object)
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("create archive dir: %w", err)
+	}
+	if err := a.rotate(path, int64(len(line))); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("open archive: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	if _, err := f.Write(line); err != nil {
+		return fmt.Errorf("write archive: %w", err)
+	}
+	return nil
+}
+
+// rotate 追加 size 字节后超过 MaxBytes 时把当前文件改名为 <path>.1；空文件不轮转，超大的单条快照照常写入。
+func (a *FileArchive) rotate(path string, size int64) error {
+	if a.MaxBytes <= 0 {
+		return nil
+	}
+	info, err := os.Stat(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("stat archive: %w", err)
+	}
+	if info.Size() == 0 || info.Size()+size <= a.MaxBytes {
+		return nil
+	}
+	if err := os.Rename(path, path+".1"); err != nil {
+		return fmt.Errorf("rotate archive: %w", err)
+	}
+	return nil
+}
+
+// ReadArchive 读取归档文件中的全部快照（按录制顺序）。
+func ReadArchive(path string) ([]Snapshot, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open archive: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	var snapshots []Snapshot
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 1024*1024), 64*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if len(strings.TrimSpace(scanner.Text())) == 0 {
+			continue
+		}
+		var s Snapshot
+		if err := json.Unmarshal(scanner.Bytes(), &s); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		snapshots = append(snapshots, s)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read archive: %w", err)
+	}
+	return snapshots, nil
+}
@@ -0,0 +1,105 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package recording
+
+import (
+	"os"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	infrav1alpha1 "github.com/lunz1207/testplane/api/v1alpha1"
+)
+
+var _ = Describe("FileArchive", func() {
+	ref := ObjectRef{Kind: "LoadTest", Namespace: "default", Name: "soak"}
+
+	snapshot := func(passed bool) Snapshot {
+		return Snapshot{
+			Time:      time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+			Object:    ref,
+			Condition: "HealthCheck",
+			AllOf:     []infrav1alpha1.Expectation{{Function: "ConfigMapValueEquals"}},
+			State: map[string]interface{}{
+				"v1/ConfigMap/settings": map[string]interface{}{"data": map[string]interface{}{"mode": "off"}},
+			},
+			Results: []infrav1alpha1.ExpectationResult{{Expect: "ConfigMapValueEquals", Passed: passed}},
+			Passed:  passed,
+		}
+	}
+
+	It("should read back snapshots in recording order", func() {
+		archive := NewFileArchive(GinkgoT().TempDir())
+		Expect(archive.Record(snapshot(true))).To(Succeed())
+		Expect(archive.Record(snapshot(false))).To(Succeed())
+
+		path := archive.Path(ref)
+		Expect(path).To(HaveSuffix("default/loadtest-soak.jsonl"))
+		info, err := os.Stat(path)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(info.Mode().Perm()).To(Equal(os.FileMode(0o600)))
+
+		snapshots, err := ReadArchive(path)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(snapshots).To(HaveLen(2))
+		Expect(snapshots[0].Passed).To(BeTrue())
+		Expect(snapshots[1]).To(Equal(snapshot(false)))
+	})
+
+	It("should rotate a file that would grow past the size cap", func() {
+		archive := NewFileArchive(GinkgoT().TempDir())
+		Expect(archive.Record(snapshot(true))).To(Succeed())
+		info, err := os.Stat(archive.Path(ref))
+		Expect(err).NotTo(HaveOccurred())
+		// 可容纳两条快照，第三条写入前轮转
+		archive.MaxBytes = 2*info.Size() + 1
+
+		Expect(archive.Record(snapshot(true))).To(Succeed())
+		Expect(archive.Path(ref) + ".1").NotTo(BeAnExistingFile())
+		for range 3 {
+			Expect(archive.Record(snapshot(true))).To(Succeed())
+		}
+
+		current, err := ReadArchive(archive.Path(ref))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(current).To(HaveLen(1))
+		rotated, err := ReadArchive(archive.Path(ref) + ".1")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(rotated).To(HaveLen(2))
+	})
+
+	It("should keep appending when the cap is disabled", func() {
+		archive := NewFileArchive(GinkgoT().TempDir())
+		archive.MaxBytes = 0
+		for range 3 {
+			Expect(archive.Record(snapshot(true))).To(Succeed())
+		}
+		snapshots, err := ReadArchive(archive.Path(ref))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(snapshots).To(HaveLen(3))
+		Expect(archive.Path(ref) + ".1").NotTo(BeAnExistingFile())
+	})
+
+	It("should report the line of a corrupt snapshot", func() {
+		dir := GinkgoT().TempDir()
+		path := dir + "/broken.jsonl"
+		Expect(os.WriteFile(path, []byte("{}\n\n{not json}\n"), 0o600)).To(Succeed())
+		_, err := ReadArchive(path)
+		Expect(err).To(MatchError(ContainSubstring("line 3")))
+	})
+})
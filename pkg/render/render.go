@@ -0,0 +1,214 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package render 离线展开 IntegrationTest/LoadTest 中的资源清单，结果与控制器 apply 前的展开一致：
+// 合成 base 与 patches（base 引用的 ConfigMap 由调用方提供）、替换 ${env.*}（提供 Environment 时）
+// 与 IntegrationTest 的 ${WORKSPACE}（提供工作区名称时）、补全命名空间、复制 propagateAnnotations。
+// 不访问集群；`testplane render` 使用本包让测试作者在控制器执行之前核对模板替换的结果。
+package render
+
+import (
+	"encoding/json"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+
+	infrav1alpha1 "github.com/lunz1207/testplane/api/v1alpha1"
+	"github.com/lunz1207/testplane/internal/controller/shared"
+	"github.com/lunz1207/testplane/internal/controller/shared/resource"
+)
+
+// Options 展开选项。
+type Options struct {
+	// Environment 替换 ${env.*} 使用的 Environment，为 nil 时引用保持原样。
+	Environment *infrav1alpha1.Environment
+	// Workspace 替换 IntegrationTest 中 ${WORKSPACE} 的工作区名称，为空时引用保持原样。
+	Workspace string
+	// Namespace 测试未设置命名空间时使用的命名空间，默认 default。
+	Namespace string
+	// ConfigMaps 资源 base 引用的 ConfigMap，按名称匹配（设置了命名空间时还需与测试一致）。
+	ConfigMaps []corev1.ConfigMap
+}
+
+// Resource 展开后的单个资源。
+type Resource struct {
+	// Source 资源在测试中的位置，如 spec.steps[1].resource、spec.steps[1].preStep[0]、spec.target、spec.workload.resources。
+	Source string `json:"source"`
+	// Step 步骤名称（仅 IntegrationTest）。
+	Step string `json:"step,omitempty"`
+	// Action Apply 或 Delete。
+	Action infrav1alpha1.TemplateAction `json:"action"`
+	// Kind 资源类型。
+	Kind string `json:"kind"`
+	// Namespace 资源命名空间。
+	Namespace string `json:"namespace,omitempty"`
+	// Name 资源名称。
+	Name string `json:"name"`
+	// Hash 清单哈希，与 apply 后资源上的 infra.testplane.io/manifest-hash 注解一致（仅 IntegrationTest 的 Apply 步骤写入该注解）。
+	Hash string `json:"hash"`
+	// Object 展开后的完整清单。
+	Object map[string]interface{} `json:"object"`
+}
+
+// Object 展开解析后的测试对象，不支持的 Kind 返回 nil。
+func Object(obj map[string]interface{}, opts Options) ([]Resource, error) {
+	kind, _ := obj["kind"].(string)
+	switch kind {
+	case "IntegrationTest":
+		var it infrav1alpha1.IntegrationTest
+		if err := convert(obj, &it); err != nil {
+			return nil, err
+		}
+		return IntegrationTest(&it, opts)
+	case "LoadTest":
+		var lt infrav1alpha1.LoadTest
+		if err := convert(obj, &lt); err != nil {
+			return nil, err
+		}
+		return LoadTest(&lt, opts)
+	}
+	return nil, nil
+}
+
+// convert 把通用对象解析为具体类型。
+func convert(obj map[string]interface{}, out interface{}) error {
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("decode %v: %w", obj["kind"], err)
+	}
+	return nil
+}
+
+// IntegrationTest 按步骤顺序展开步骤清单，步骤内依次为 preStep、步骤资源与 postStep（与控制器的执行顺序一致）。
+func IntegrationTest(it *infrav1alpha1.IntegrationTest, opts Options) ([]Resource, error) {
+	spec := it.Spec.DeepCopy()
+	namespace := namespaceOf(it.Namespace, opts)
+	load := opts.baseLoader(namespace)
+	for _, step := range spec.Steps {
+		for _, ref := range resource.StepRefs(step) {
+			if err := resource.ResolveManifest(ref.Ref, load); err != nil {
+				return nil, fmt.Errorf("step %s: %w", step.Name, err)
+			}
+		}
+	}
+	if err := shared.ExpandEnvironment(opts.Environment, spec); err != nil {
+		return nil, err
+	}
+	referenced, err := resource.ExpandWorkspace(spec, opts.Workspace)
+	if err != nil {
+		return nil, err
+	}
+	if referenced && spec.Workspace == nil {
+		return nil, fmt.Errorf("spec references %s but spec.workspace is not set", resource.WorkspaceRef)
+	}
+
+	var out []Resource
+	for i, step := range spec.Steps {
+		for _, ref := range resource.StepRefs(step) {
+			if len(ref.Ref.Manifest.Raw) == 0 {
+				continue
+			}
+			source := fmt.Sprintf("spec.steps[%d].%s", i, ref.Field)
+			manifest, err := resource.ExpandSingleResourceRef(*ref.Ref, namespace)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", source, err)
+			}
+			resource.PropagateAnnotations(manifest, it.Annotations, spec.PropagateAnnotations)
+			out = append(out, newResource(source, step.Name, manifest))
+		}
+	}
+	return out, nil
+}
+
+// LoadTest 依次展开 target 与 workload 清单。内置 HTTP 负载与监控资源由控制器生成，不在结果中；
+// 运行时提取的注入值（injection）在 apply 时才写入 workload。
+func LoadTest(lt *infrav1alpha1.LoadTest, opts Options) ([]Resource, error) {
+	spec := lt.Spec.DeepCopy()
+	namespace := namespaceOf(lt.Namespace, opts)
+	load := opts.baseLoader(namespace)
+	if err := resource.ResolveManifest(&spec.Target.Resource, load); err != nil {
+		return nil, fmt.Errorf("spec.target: %w", err)
+	}
+	for i := range spec.Workload.Resources {
+		if err := resource.ResolveManifest(&spec.Workload.Resources[i], load); err != nil {
+			return nil, fmt.Errorf("spec.workload.resources[%d]: %w", i, err)
+		}
+	}
+	// LoadTest 没有工作区，控制器只替换 ${env.*}
+	if err := shared.ExpandEnvironment(opts.Environment, spec); err != nil {
+		return nil, err
+	}
+
+	var out []Resource
+	if raw := spec.Target.Resource.Manifest; len(raw.Raw) > 0 {
+		manifest, err := resource.ExpandRawTemplate(&raw, namespace)
+		if err != nil {
+			return nil, fmt.Errorf("spec.target: %w", err)
+		}
+		out = append(out, newResource("spec.target", "", manifest))
+	}
+	manifests, err := resource.ExpandResourceRefs(spec.Workload.Resources, namespace)
+	if err != nil {
+		return nil, fmt.Errorf("spec.workload.resources: %w", err)
+	}
+	for i := range manifests {
+		resource.PropagateAnnotations(&manifests[i], lt.Annotations, spec.PropagateAnnotations)
+		out = append(out, newResource("spec.workload.resources", "", &manifests[i]))
+	}
+	return out, nil
+}
+
+// namespaceOf 返回测试的命名空间，未设置时使用选项中的命名空间（默认 default）。
+func namespaceOf(namespace string, opts Options) string {
+	switch {
+	case namespace != "":
+		return namespace
+	case opts.Namespace != "":
+		return opts.Namespace
+	default:
+		return "default"
+	}
+}
+
+// baseLoader 返回从 ConfigMaps 中查找 base 的 BaseLoader。
+func (o Options) baseLoader(namespace string) resource.BaseLoader {
+	return func(name string) (*corev1.ConfigMap, error) {
+		for i := range o.ConfigMaps {
+			cm := &o.ConfigMaps[i]
+			if cm.Name == name && (cm.Namespace == "" || cm.Namespace == namespace) {
+				return cm, nil
+			}
+		}
+		return nil, fmt.Errorf("configmap %s not found in input", name)
+	}
+}
+
+// newResource 由展开后的清单构造结果。
+func newResource(source, step string, manifest *resource.ExpandedManifest) Resource {
+	return Resource{
+		Source:    source,
+		Step:      step,
+		Action:    manifest.Action,
+		Kind:      manifest.Object.GetKind(),
+		Namespace: manifest.Object.GetNamespace(),
+		Name:      manifest.Object.GetName(),
+		Hash:      resource.ManifestHash(manifest),
+		Object:    manifest.Object.Object,
+	}
+}
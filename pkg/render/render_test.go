@@ -0,0 +1,88 @@
+package render
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/yaml"
+
+	infrav1alpha1 "github.com/lunz1207/testplane/api/v1alpha1"
+	"github.com/lunz1207/testplane/pkg/assertiontest"
+)
+
+// update 为 true 时用本次输出覆盖 golden 文件：go test ./pkg/render -args -update
+var update = flag.Bool("update", false, "update golden files")
+
+// renderFixture 展开 testdata 中的测试，文件中的 Environment 与 ConfigMap 作为展开选项。
+func renderFixture(name string, opts Options) ([]Resource, error) {
+	objects, err := assertiontest.LoadFixtures(filepath.Join("testdata", name+".yaml"))
+	Expect(err).NotTo(HaveOccurred())
+	var out []Resource
+	for _, obj := range objects {
+		switch obj["kind"] {
+		case "Environment":
+			env := &infrav1alpha1.Environment{}
+			Expect(runtime.DefaultUnstructuredConverter.FromUnstructured(obj, env)).To(Succeed())
+			opts.Environment = env
+		case "ConfigMap":
+			var cm corev1.ConfigMap
+			Expect(runtime.DefaultUnstructuredConverter.FromUnstructured(obj, &cm)).To(Succeed())
+			opts.ConfigMaps = append(opts.ConfigMaps, cm)
+		}
+	}
+	for _, obj := range objects {
+		resources, err := Object(obj, opts)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, resources...)
+	}
+	return out, nil
+}
+
+var _ = Describe("Render", func() {
+	DescribeTable("should match the golden output",
+		func(name string, opts Options) {
+			resources, err := renderFixture(name, opts)
+			Expect(err).NotTo(HaveOccurred())
+			actual, err := yaml.Marshal(resources)
+			Expect(err).NotTo(HaveOccurred())
+
+			golden := filepath.Join("testdata", name+".golden.yaml")
+			if *update {
+				Expect(os.WriteFile(golden, actual, 0o644)).To(Succeed())
+			}
+			expected, err := os.ReadFile(golden)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(actual)).To(Equal(string(expected)))
+		},
+		Entry("integration test steps in execution order", "integrationtest", Options{Workspace: "smoke-ws-1a2b3c"}),
+		Entry("load test target and workload", "loadtest", Options{Workspace: "ignored", Namespace: "perf"}),
+	)
+
+	It("should leave ${WORKSPACE} unchanged without a workspace name", func() {
+		resources, err := renderFixture("integrationtest", Options{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resources[0].Object).To(HaveKeyWithValue("data", HaveKeyWithValue("workspace", "${WORKSPACE}")))
+	})
+
+	It("should reject ${WORKSPACE} when spec.workspace is not set, like the controller", func() {
+		data, err := os.ReadFile(filepath.Join("testdata", "integrationtest.yaml"))
+		Expect(err).NotTo(HaveOccurred())
+		objects, err := assertiontest.ParseFixtures([]byte(strings.Replace(string(data), "  workspace:\n    kind: ConfigMap\n", "", 1)))
+		Expect(err).NotTo(HaveOccurred())
+		it := objects[len(objects)-1]
+		Expect(it).To(HaveKeyWithValue("spec", Not(HaveKey("workspace"))))
+		var cm corev1.ConfigMap
+		Expect(runtime.DefaultUnstructuredConverter.FromUnstructured(objects[1], &cm)).To(Succeed())
+
+		_, err = Object(it, Options{Workspace: "ws", ConfigMaps: []corev1.ConfigMap{cm}})
+		Expect(err).To(MatchError(ContainSubstring("spec.workspace is not set")))
+	})
+})
@@ -0,0 +1,225 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package results
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	infrav1alpha1 "github.com/lunz1207/testplane/api/v1alpha1"
+	"github.com/lunz1207/testplane/pkg/export"
+)
+
+var _ = Describe("Results", func() {
+	ctx := context.Background()
+	base := time.Date(2025, 6, 1, 10, 0, 0, 0, time.UTC)
+
+	phaseChanged := func(namespace, name, phase string, round int, at time.Time, labels map[string]string) export.Event {
+		return export.Event{
+			Type:   infrav1alpha1.ExportEventPhaseChanged,
+			Time:   at,
+			Object: export.ObjectRef{Kind: "IntegrationTest", Namespace: namespace, Name: name},
+			Labels: labels,
+			Phase:  phase,
+			Round:  round,
+		}
+	}
+
+	newStore := func() *Store {
+		store := NewStore(0)
+		for _, event := range []export.Event{
+			phaseChanged("qa", "smoke", "Succeeded", 1, base, map[string]string{"team": "storage"}),
+			phaseChanged("qa", "smoke", "Failed", 2, base.Add(time.Hour), map[string]string{"team": "storage"}),
+			phaseChanged("qa", "soak", "Aborted", 1, base.Add(2*time.Hour), map[string]string{"team": "network"}),
+			phaseChanged("dev", "smoke", "Succeeded", 1, base.Add(3*time.Hour), nil),
+			// 非终态转换与其他事件不记录
+			phaseChanged("qa", "smoke", "Running", 3, base.Add(4*time.Hour), nil),
+			{Type: infrav1alpha1.ExportEventType("StepFinished"), Time: base, Object: export.ObjectRef{Kind: "IntegrationTest", Namespace: "qa", Name: "other"}},
+		} {
+			Expect(store.Export(ctx, event)).To(Succeed())
+		}
+		return store
+	}
+
+	names := func(items []TestResult) []string {
+		out := make([]string, 0, len(items))
+		for _, item := range items {
+			out = append(out, item.Namespace+"/"+item.Name)
+		}
+		return out
+	}
+
+	Context("When filtering stored results", func() {
+		It("should filter by namespace, labels, outcome and time range", func() {
+			store := newStore()
+			Expect(names(store.List(Query{}))).To(Equal([]string{
+				"qa/integrationtest-smoke-1", "qa/integrationtest-smoke-2", "qa/integrationtest-soak-1", "dev/integrationtest-smoke-1",
+			}))
+			Expect(names(store.List(Query{Namespace: "dev"}))).To(Equal([]string{"dev/integrationtest-smoke-1"}))
+			Expect(names(store.List(Query{Selector: labels.SelectorFromSet(labels.Set{"team": "storage"})}))).To(Equal([]string{
+				"qa/integrationtest-smoke-1", "qa/integrationtest-smoke-2",
+			}))
+			Expect(names(store.List(Query{Outcome: "Succeeded"}))).To(Equal([]string{"qa/integrationtest-smoke-1", "dev/integrationtest-smoke-1"}))
+			// 时间范围为 [since, until)
+			Expect(names(store.List(Query{Since: base.Add(time.Hour), Until: base.Add(3 * time.Hour)}))).To(Equal([]string{
+				"qa/integrationtest-smoke-2", "qa/integrationtest-soak-1",
+			}))
+
+			result, ok := store.Get("qa", "integrationtest-soak-1")
+			Expect(ok).To(BeTrue())
+			Expect(result.Outcome).To(Equal("Aborted"))
+			_, ok = store.Get("qa", "integrationtest-other-0")
+			Expect(ok).To(BeFalse())
+		})
+
+		It("should keep the latest result of a round and evict the oldest beyond capacity", func() {
+			store := NewStore(2)
+			Expect(store.Export(ctx, phaseChanged("qa", "smoke", "Failed", 1, base, nil))).To(Succeed())
+			Expect(store.Export(ctx, phaseChanged("qa", "smoke", "Succeeded", 1, base.Add(time.Minute), nil))).To(Succeed())
+			result, ok := store.Get("qa", "integrationtest-smoke-1")
+			Expect(ok).To(BeTrue())
+			Expect(result.Outcome).To(Equal("Succeeded"))
+
+			Expect(store.Export(ctx, phaseChanged("qa", "smoke", "Succeeded", 2, base.Add(2*time.Minute), nil))).To(Succeed())
+			Expect(store.Export(ctx, phaseChanged("qa", "smoke", "Succeeded", 3, base.Add(3*time.Minute), nil))).To(Succeed())
+			Expect(names(store.List(Query{}))).To(Equal([]string{"qa/integrationtest-smoke-2", "qa/integrationtest-smoke-3"}))
+		})
+	})
+
+	Context("When serving requests", func() {
+		var reviews []authorizationv1.SubjectAccessReviewSpec
+
+		newServer := func() *Server {
+			reviews = nil
+			scheme := runtime.NewScheme()
+			Expect(authorizationv1.AddToScheme(scheme)).To(Succeed())
+			authorizer := fake.NewClientBuilder().WithScheme(scheme).WithInterceptorFuncs(interceptor.Funcs{
+				Create: func(_ context.Context, _ client.WithWatch, obj client.Object, _ ...client.CreateOption) error {
+					review := obj.(*authorizationv1.SubjectAccessReview)
+					reviews = append(reviews, review.Spec)
+					// 只允许 alice 读取 qa 命名空间
+					review.Status.Allowed = review.Spec.User == "alice" && review.Spec.ResourceAttributes.Namespace == "qa"
+					return nil
+				},
+			}).Build()
+			return &Server{Store: newStore(), Authorizer: authorizer}
+		}
+
+		serve := func(server *Server, path, user string) *httptest.ResponseRecorder {
+			req := httptest.NewRequest(http.MethodGet, path, nil)
+			if user != "" {
+				req.Header.Set(headerRemoteUser, user)
+				req.Header.Add(headerRemoteGroup, "testers")
+				req.Header.Set(headerRemoteExtraPrefix+"Scopes", "view")
+			}
+			rec := httptest.NewRecorder()
+			server.Handler().ServeHTTP(rec, req)
+			return rec
+		}
+
+		It("should authorize list and get with a SubjectAccessReview for the forwarded user", func() {
+			server := newServer()
+			rec := serve(server, "/apis/testresults.testplane.io/v1alpha1/namespaces/qa/testresults?outcome=Failed&labelSelector=team%3Dstorage", "alice")
+			Expect(rec.Code).To(Equal(http.StatusOK))
+			var list TestResultList
+			Expect(json.Unmarshal(rec.Body.Bytes(), &list)).To(Succeed())
+			Expect(names(list.Items)).To(Equal([]string{"qa/integrationtest-smoke-2"}))
+
+			Expect(reviews).To(HaveLen(1))
+			Expect(reviews[0].User).To(Equal("alice"))
+			Expect(reviews[0].Groups).To(Equal([]string{"testers"}))
+			Expect(reviews[0].Extra).To(HaveKeyWithValue("scopes", authorizationv1.ExtraValue{"view"}))
+			Expect(*reviews[0].ResourceAttributes).To(Equal(authorizationv1.ResourceAttributes{
+				Namespace: "qa", Verb: "list", Group: GroupVersion.Group, Version: GroupVersion.Version, Resource: Resource,
+			}))
+
+			rec = serve(server, "/apis/testresults.testplane.io/v1alpha1/namespaces/qa/testresults/integrationtest-soak-1", "alice")
+			Expect(rec.Code).To(Equal(http.StatusOK))
+			Expect(reviews[1].ResourceAttributes.Verb).To(Equal("get"))
+			Expect(reviews[1].ResourceAttributes.Name).To(Equal("integrationtest-soak-1"))
+
+			rec = serve(server, "/apis/testresults.testplane.io/v1alpha1/namespaces/qa/testresults/missing", "alice")
+			Expect(rec.Code).To(Equal(http.StatusNotFound))
+		})
+
+		It("should reject unauthorized users and requests without an identity", func() {
+			server := newServer()
+			rec := serve(server, "/apis/testresults.testplane.io/v1alpha1/namespaces/dev/testresults", "alice")
+			Expect(rec.Code).To(Equal(http.StatusForbidden))
+			var status metav1.Status
+			Expect(json.Unmarshal(rec.Body.Bytes(), &status)).To(Succeed())
+			Expect(status.Reason).To(Equal(metav1.StatusReasonForbidden))
+
+			Expect(serve(server, "/apis/testresults.testplane.io/v1alpha1/namespaces/qa/testresults", "bob").Code).To(Equal(http.StatusForbidden))
+			Expect(serve(server, "/apis/testresults.testplane.io/v1alpha1/testresults", "alice").Code).To(Equal(http.StatusForbidden))
+
+			reviews = nil
+			Expect(serve(server, "/apis/testresults.testplane.io/v1alpha1/namespaces/qa/testresults", "").Code).To(Equal(http.StatusUnauthorized))
+			Expect(reviews).To(BeEmpty())
+		})
+
+		It("should reject invalid list parameters", func() {
+			server := newServer()
+			Expect(serve(server, "/apis/testresults.testplane.io/v1alpha1/namespaces/qa/testresults?since=yesterday", "alice").Code).To(Equal(http.StatusBadRequest))
+			Expect(serve(server, "/apis/testresults.testplane.io/v1alpha1/namespaces/qa/testresults?labelSelector=%3D%3D", "alice").Code).To(Equal(http.StatusBadRequest))
+		})
+
+		It("should only serve on the leader", func() {
+			Expect((&Server{}).NeedLeaderElection()).To(BeTrue())
+		})
+
+		It("should route the Service to the leader pod through its label", func() {
+			key := client.ObjectKey{Namespace: "testplane-system", Name: "manager-0"}
+			pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: key.Namespace, Name: key.Name, Labels: map[string]string{"app": "testplane"}}}
+			c := fake.NewClientBuilder().WithObjects(pod).Build()
+			server := &Server{Pod: key, PodWriter: c}
+			labelsOf := func() map[string]string {
+				current := &corev1.Pod{}
+				Expect(c.Get(ctx, key, current)).To(Succeed())
+				return current.Labels
+			}
+
+			Expect(server.setLeaderLabel(ctx, true)).To(Succeed())
+			Expect(labelsOf()).To(Equal(map[string]string{"app": "testplane", LeaderLabel: "true"}))
+			Expect(server.setLeaderLabel(ctx, false)).To(Succeed())
+			Expect(labelsOf()).To(Equal(map[string]string{"app": "testplane"}))
+
+			// 容器重启前留下的标签在启动时清除，Pod 不存在时忽略
+			Expect(server.setLeaderLabel(ctx, true)).To(Succeed())
+			Expect(ClearLeaderLabel(ctx, c, key)).To(Succeed())
+			Expect(labelsOf()).NotTo(HaveKey(LeaderLabel))
+			Expect(ClearLeaderLabel(ctx, c, client.ObjectKey{Namespace: key.Namespace, Name: "gone"})).To(Succeed())
+
+			// 未设置 Pod 时不打标签
+			Expect((&Server{}).setLeaderLabel(ctx, true)).To(Succeed())
+		})
+	})
+})
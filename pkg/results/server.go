@@ -0,0 +1,363 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package results
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/certwatcher"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// 聚合层转发的用户身份请求头（--requestheader-*-headers 的默认值）。
+const (
+	headerRemoteUser        = "X-Remote-User"
+	headerRemoteGroup       = "X-Remote-Group"
+	headerRemoteExtraPrefix = "X-Remote-Extra-"
+)
+
+// extensionAuthConfigMap 聚合层客户端证书 CA 所在的 ConfigMap（kube-system 命名空间）。
+const (
+	extensionAuthNamespace = "kube-system"
+	extensionAuthConfigMap = "extension-apiserver-authentication"
+	extensionAuthCAKey     = "requestheader-client-ca-file"
+)
+
+// LeaderLabel 结果 API 在 leader 上开始服务后写入自身 Pod 的标签。
+// Service 只选择带该标签的 Pod，多副本部署时请求只落到持有结果的 leader 上；
+// 结束服务时移除，进程启动时由 ClearLeaderLabel 清除重启前残留的标签。
+const LeaderLabel = "testresults.testplane.io/leader"
+
+// Server 以聚合 API 提供结果视图，作为 manager.Runnable 运行。
+// 只接受经聚合层 requestheader CA 验证的客户端证书，
+// 用户身份取自聚合层转发的请求头，并经 SubjectAccessReview 授权。
+type Server struct {
+	// Store 结果存储。
+	Store *Store
+	// BindAddress 监听地址。
+	BindAddress string
+	// CertPath、CertName、KeyName 服务证书所在目录与文件名，证书轮换时自动重新加载。
+	CertPath string
+	CertName string
+	KeyName  string
+	// ClientCAFile 聚合层客户端证书的 CA 文件；为空时从 kube-system/extension-apiserver-authentication
+	// 的 requestheader-client-ca-file 读取（需要 Reader）。
+	ClientCAFile string
+	// Reader 读取 extension-apiserver-authentication，建议使用 APIReader。
+	Reader client.Reader
+	// Authorizer 创建 SubjectAccessReview 的客户端，nil 表示不做授权（仅用于测试）。
+	Authorizer client.Client
+	// Pod 当前 Pod（由 downward API 注入名称与命名空间），非空时开始服务后打上 LeaderLabel、结束时移除。
+	Pod client.ObjectKey
+	// PodWriter 更新 Pod 标签的客户端，Pod 非空时必须设置。
+	PodWriter client.Client
+}
+
+// NeedLeaderElection 实现 manager.LeaderElectionRunnable：只有 leader 运行控制器、接收结果，
+// 非 leader 的存储为空，提供查询只会返回不完整的结果，因此只在 leader 上提供服务，
+// 并通过 LeaderLabel 让 Service 只把请求转发给 leader。
+func (s *Server) NeedLeaderElection() bool {
+	return true
+}
+
+// Start 实现 manager.Runnable，ctx 结束时关闭服务。
+func (s *Server) Start(ctx context.Context) error {
+	log := logf.FromContext(ctx).WithName("results-api")
+	watcher, err := certwatcher.New(filepath.Join(s.CertPath, s.CertName), filepath.Join(s.CertPath, s.KeyName))
+	if err != nil {
+		return fmt.Errorf("load results api certificate: %w", err)
+	}
+	go func() {
+		if err := watcher.Start(ctx); err != nil {
+			log.Error(err, "certificate watcher stopped")
+		}
+	}()
+	caData, err := s.clientCA(ctx)
+	if err != nil {
+		return fmt.Errorf("load results api client CA: %w", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(caData) {
+		return fmt.Errorf("no certificates found in results api client CA")
+	}
+
+	server := &http.Server{
+		Addr:              s.BindAddress,
+		Handler:           s.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+		TLSConfig: &tls.Config{
+			MinVersion:     tls.VersionTLS12,
+			GetCertificate: watcher.GetCertificate,
+			ClientCAs:      pool,
+			ClientAuth:     tls.VerifyClientCertIfGiven,
+		},
+	}
+	listener, err := net.Listen("tcp", s.BindAddress)
+	if err != nil {
+		return fmt.Errorf("listen on %s: %w", s.BindAddress, err)
+	}
+	// 端口就绪后再打标签，Service 选中本 Pod 时请求不会被拒绝
+	if err := s.setLeaderLabel(ctx, true); err != nil {
+		_ = listener.Close()
+		return fmt.Errorf("label pod %s as results api leader: %w", s.Pod, err)
+	}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := s.setLeaderLabel(shutdownCtx, false); err != nil {
+			log.Error(err, "remove results api leader label", "pod", s.Pod)
+		}
+		_ = server.Shutdown(shutdownCtx)
+	}()
+	log.Info("serving test results", "address", s.BindAddress, "apiVersion", GroupVersion.String(), "pod", s.Pod)
+	if err := server.ServeTLS(listener, "", ""); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// setLeaderLabel 在当前 Pod 上设置或移除 LeaderLabel，未设置 Pod 时不做处理。
+func (s *Server) setLeaderLabel(ctx context.Context, leader bool) error {
+	if s.Pod.Name == "" {
+		return nil
+	}
+	return patchLeaderLabel(ctx, s.PodWriter, s.Pod, leader)
+}
+
+// ClearLeaderLabel 移除 Pod 上的 LeaderLabel，在 manager 启动前调用：
+// 失去 leader 身份的进程退出后容器在同一 Pod 中重启，标签会保留下来，不清除时 Service 仍会把请求转发给它。
+func ClearLeaderLabel(ctx context.Context, c client.Client, pod client.ObjectKey) error {
+	return client.IgnoreNotFound(patchLeaderLabel(ctx, c, pod, false))
+}
+
+// patchLeaderLabel 以 merge patch 设置或移除 Pod 的 LeaderLabel。
+func patchLeaderLabel(ctx context.Context, c client.Client, pod client.ObjectKey, leader bool) error {
+	var value interface{}
+	if leader {
+		value = "true"
+	}
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{"labels": map[string]interface{}{LeaderLabel: value}},
+	})
+	if err != nil {
+		return err
+	}
+	obj := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: pod.Namespace, Name: pod.Name}}
+	return c.Patch(ctx, obj, client.RawPatch(types.MergePatchType, patch))
+}
+
+// clientCA 读取聚合层客户端证书的 CA。
+func (s *Server) clientCA(ctx context.Context) ([]byte, error) {
+	if s.ClientCAFile != "" {
+		return os.ReadFile(s.ClientCAFile)
+	}
+	if s.Reader == nil {
+		return nil, fmt.Errorf("neither client CA file nor reader is set")
+	}
+	var cm corev1.ConfigMap
+	if err := s.Reader.Get(ctx, client.ObjectKey{Namespace: extensionAuthNamespace, Name: extensionAuthConfigMap}, &cm); err != nil {
+		return nil, err
+	}
+	data, ok := cm.Data[extensionAuthCAKey]
+	if !ok {
+		return nil, fmt.Errorf("%s/%s has no %s", extensionAuthNamespace, extensionAuthConfigMap, extensionAuthCAKey)
+	}
+	return []byte(data), nil
+}
+
+// Handler 返回结果视图的 HTTP 处理器（发现接口与 testresults 的 get/list）。
+func (s *Server) Handler() http.Handler {
+	prefix := "/apis/" + GroupVersion.Group
+	versionPrefix := "/apis/" + GroupVersion.String()
+	groupVersion := metav1.GroupVersionForDiscovery{GroupVersion: GroupVersion.String(), Version: GroupVersion.Version}
+	group := metav1.APIGroup{
+		TypeMeta:         metav1.TypeMeta{Kind: "APIGroup", APIVersion: "v1"},
+		Name:             GroupVersion.Group,
+		Versions:         []metav1.GroupVersionForDiscovery{groupVersion},
+		PreferredVersion: groupVersion,
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /healthz", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte("ok"))
+	})
+	mux.HandleFunc("GET /apis", s.authenticated(func(w http.ResponseWriter, _ *http.Request) {
+		writeJSON(w, http.StatusOK, &metav1.APIGroupList{
+			TypeMeta: metav1.TypeMeta{Kind: "APIGroupList", APIVersion: "v1"},
+			Groups:   []metav1.APIGroup{group},
+		})
+	}))
+	mux.HandleFunc("GET "+prefix, s.authenticated(func(w http.ResponseWriter, _ *http.Request) {
+		writeJSON(w, http.StatusOK, &group)
+	}))
+	mux.HandleFunc("GET "+versionPrefix, s.authenticated(func(w http.ResponseWriter, _ *http.Request) {
+		writeJSON(w, http.StatusOK, &metav1.APIResourceList{
+			TypeMeta:     metav1.TypeMeta{Kind: "APIResourceList", APIVersion: "v1"},
+			GroupVersion: GroupVersion.String(),
+			APIResources: []metav1.APIResource{{
+				Name:         Resource,
+				SingularName: "testresult",
+				Namespaced:   true,
+				Kind:         "TestResult",
+				Verbs:        metav1.Verbs{"get", "list"},
+			}},
+		})
+	}))
+	mux.HandleFunc("GET "+versionPrefix+"/"+Resource, s.authenticated(s.list))
+	mux.HandleFunc("GET "+versionPrefix+"/namespaces/{namespace}/"+Resource, s.authenticated(s.list))
+	mux.HandleFunc("GET "+versionPrefix+"/namespaces/{namespace}/"+Resource+"/{name}", s.authenticated(s.get))
+	return mux
+}
+
+// list 处理 list：labelSelector 为标准标签选择器，outcome 过滤结果，since/until（RFC3339）限定时间范围 [since, until)。
+func (s *Server) list(w http.ResponseWriter, r *http.Request) {
+	namespace := r.PathValue("namespace")
+	if !s.authorize(w, r, "list", namespace, "") {
+		return
+	}
+	params := r.URL.Query()
+	q := Query{Namespace: namespace, Outcome: params.Get("outcome")}
+	if raw := params.Get("labelSelector"); raw != "" {
+		selector, err := labels.Parse(raw)
+		if err != nil {
+			writeStatus(w, http.StatusBadRequest, metav1.StatusReasonBadRequest, fmt.Sprintf("invalid labelSelector: %v", err))
+			return
+		}
+		q.Selector = selector
+	}
+	for name, target := range map[string]*time.Time{"since": &q.Since, "until": &q.Until} {
+		raw := params.Get(name)
+		if raw == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			writeStatus(w, http.StatusBadRequest, metav1.StatusReasonBadRequest, fmt.Sprintf("invalid %s: %v", name, err))
+			return
+		}
+		*target = t
+	}
+	writeJSON(w, http.StatusOK, &TestResultList{
+		TypeMeta: metav1.TypeMeta{Kind: "TestResultList", APIVersion: GroupVersion.String()},
+		Items:    s.Store.List(q),
+	})
+}
+
+// get 处理 get。
+func (s *Server) get(w http.ResponseWriter, r *http.Request) {
+	namespace, name := r.PathValue("namespace"), r.PathValue("name")
+	if !s.authorize(w, r, "get", namespace, name) {
+		return
+	}
+	result, ok := s.Store.Get(namespace, name)
+	if !ok {
+		writeStatus(w, http.StatusNotFound, metav1.StatusReasonNotFound,
+			fmt.Sprintf("%s.%s %q not found", Resource, GroupVersion.Group, name))
+		return
+	}
+	writeJSON(w, http.StatusOK, &result)
+}
+
+// authenticated 只放行携带已验证客户端证书（来自聚合层）的 TLS 请求。
+func (s *Server) authenticated(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.TLS != nil && len(r.TLS.VerifiedChains) == 0 {
+			writeStatus(w, http.StatusUnauthorized, metav1.StatusReasonUnauthorized, "client certificate required")
+			return
+		}
+		next(w, r)
+	}
+}
+
+// authorize 以聚合层转发的用户身份创建 SubjectAccessReview，未授权时写入 403 并返回 false。
+func (s *Server) authorize(w http.ResponseWriter, r *http.Request, verb, namespace, name string) bool {
+	if s.Authorizer == nil {
+		return true
+	}
+	user := r.Header.Get(headerRemoteUser)
+	if user == "" {
+		writeStatus(w, http.StatusUnauthorized, metav1.StatusReasonUnauthorized, "missing user identity")
+		return false
+	}
+	extra := map[string]authorizationv1.ExtraValue{}
+	for key, values := range r.Header {
+		if strings.HasPrefix(key, headerRemoteExtraPrefix) {
+			extra[strings.ToLower(strings.TrimPrefix(key, headerRemoteExtraPrefix))] = values
+		}
+	}
+	review := &authorizationv1.SubjectAccessReview{
+		Spec: authorizationv1.SubjectAccessReviewSpec{
+			User:   user,
+			Groups: r.Header.Values(headerRemoteGroup),
+			Extra:  extra,
+			ResourceAttributes: &authorizationv1.ResourceAttributes{
+				Namespace: namespace,
+				Verb:      verb,
+				Group:     GroupVersion.Group,
+				Version:   GroupVersion.Version,
+				Resource:  Resource,
+				Name:      name,
+			},
+		},
+	}
+	if err := s.Authorizer.Create(r.Context(), review); err != nil {
+		writeStatus(w, http.StatusInternalServerError, metav1.StatusReasonInternalError, fmt.Sprintf("authorization failed: %v", err))
+		return false
+	}
+	if !review.Status.Allowed {
+		writeStatus(w, http.StatusForbidden, metav1.StatusReasonForbidden,
+			fmt.Sprintf("user %q cannot %s %s.%s in namespace %q", user, verb, Resource, GroupVersion.Group, namespace))
+		return false
+	}
+	return true
+}
+
+// writeJSON 写入 JSON 响应。
+func writeJSON(w http.ResponseWriter, code int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+// writeStatus 以 metav1.Status 写入错误响应。
+func writeStatus(w http.ResponseWriter, code int, reason metav1.StatusReason, message string) {
+	writeJSON(w, code, &metav1.Status{
+		TypeMeta: metav1.TypeMeta{Kind: "Status", APIVersion: "v1"},
+		Status:   metav1.StatusFailure,
+		Message:  message,
+		Reason:   reason,
+		Code:     int32(code),
+	})
+}
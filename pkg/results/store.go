@@ -0,0 +1,196 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package results 提供测试结果的查询视图：Store 作为导出器接收测试结果（与 CR status 双写），
+// Server 以聚合 API（APIService testresults.testplane.io）只读地提供按标签、时间范围、结果过滤的列表，
+// 报表类的大查询因此不必反复 list etcd 中的 CR。
+// 结果视图不是 CRD，跳过 CRD 生成。
+// +kubebuilder:skip
+package results
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	infrav1alpha1 "github.com/lunz1207/testplane/api/v1alpha1"
+	"github.com/lunz1207/testplane/pkg/export"
+)
+
+// GroupVersion 结果视图的 API 组版本。
+var GroupVersion = schema.GroupVersion{Group: "testresults.testplane.io", Version: "v1alpha1"}
+
+// Resource 结果视图的资源名。
+const Resource = "testresults"
+
+// DefaultMaxEntries Store 默认保留的结果条数。
+const DefaultMaxEntries = 10000
+
+// terminalPhases 记录为结果的测试阶段。
+var terminalPhases = map[string]bool{"Succeeded": true, "Failed": true, "Aborted": true}
+
+// TestResult 一次测试结果：IntegrationTest/LoadTest 进入终态或 Check 的一轮检查。
+type TestResult struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata"`
+	// Source 结果来源。
+	Source export.ObjectRef `json:"source"`
+	// Outcome 结果：Succeeded / Failed / Aborted，Check 为 Passed / Failed。
+	Outcome string `json:"outcome"`
+	// Reason 结果原因。
+	Reason string `json:"reason,omitempty"`
+	// Message 结果消息。
+	Message string `json:"message,omitempty"`
+	// Round 轮次。
+	Round int `json:"round,omitempty"`
+	// Time 结果产生时间。
+	Time metav1.Time `json:"time"`
+	// Results 期望结果摘要。
+	Results []infrav1alpha1.ExpectationResultSummary `json:"results,omitempty"`
+}
+
+// TestResultList 结果列表。
+type TestResultList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+	Items           []TestResult `json:"items"`
+}
+
+// Query 列表过滤条件，零值表示不过滤。
+type Query struct {
+	Namespace string
+	Selector  labels.Selector
+	Outcome   string
+	Since     time.Time
+	Until     time.Time
+}
+
+// Store 内存中的结果存储，超过容量时淘汰最早的结果。
+// 进程重启或 leader 切换后为空，不从 CR 回填；持久保存请配合其他导出器。
+type Store struct {
+	mu         sync.RWMutex
+	maxEntries int
+	items      map[string]*TestResult
+}
+
+// NewStore 创建结果存储，maxEntries <= 0 时使用 DefaultMaxEntries。
+func NewStore(maxEntries int) *Store {
+	if maxEntries <= 0 {
+		maxEntries = DefaultMaxEntries
+	}
+	return &Store{maxEntries: maxEntries, items: map[string]*TestResult{}}
+}
+
+// Export 实现 export.Exporter：记录终态阶段转换与 Check 结果，其他事件忽略。
+// 同一测试同一轮次的结果（如重跑后再次结束）以最新的为准。
+func (s *Store) Export(_ context.Context, event export.Event) error {
+	switch event.Type {
+	case infrav1alpha1.ExportEventPhaseChanged:
+		if !terminalPhases[event.Phase] {
+			return nil
+		}
+	case infrav1alpha1.ExportEventCheckResult:
+	default:
+		return nil
+	}
+
+	result := &TestResult{
+		TypeMeta: metav1.TypeMeta{APIVersion: GroupVersion.String(), Kind: "TestResult"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              fmt.Sprintf("%s-%s-%d", strings.ToLower(event.Object.Kind), event.Object.Name, event.Round),
+			Namespace:         event.Object.Namespace,
+			Labels:            event.Labels,
+			CreationTimestamp: metav1.NewTime(event.Time),
+		},
+		Source:  event.Object,
+		Outcome: event.Phase,
+		Reason:  event.Reason,
+		Message: event.Message,
+		Round:   event.Round,
+		Time:    metav1.NewTime(event.Time),
+		Results: event.Results,
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.items[result.Namespace+"/"+result.Name] = result
+	if len(s.items) > s.maxEntries {
+		s.evictLocked()
+	}
+	return nil
+}
+
+// evictLocked 淘汰最早的结果直到回到容量以内。
+func (s *Store) evictLocked() {
+	keys := make([]string, 0, len(s.items))
+	for key := range s.items {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool { return s.items[keys[i]].Time.Before(&s.items[keys[j]].Time) })
+	for _, key := range keys[:len(keys)-s.maxEntries] {
+		delete(s.items, key)
+	}
+}
+
+// Get 读取一条结果。
+func (s *Store) Get(namespace, name string) (TestResult, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	result, ok := s.items[namespace+"/"+name]
+	if !ok {
+		return TestResult{}, false
+	}
+	return *result, true
+}
+
+// List 按条件列出结果，按时间先后排序。
+func (s *Store) List(q Query) []TestResult {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	items := []TestResult{}
+	for _, result := range s.items {
+		if q.Namespace != "" && result.Namespace != q.Namespace {
+			continue
+		}
+		if q.Selector != nil && !q.Selector.Matches(labels.Set(result.Labels)) {
+			continue
+		}
+		if q.Outcome != "" && result.Outcome != q.Outcome {
+			continue
+		}
+		if !q.Since.IsZero() && result.Time.Time.Before(q.Since) {
+			continue
+		}
+		if !q.Until.IsZero() && !result.Time.Time.Before(q.Until) {
+			continue
+		}
+		items = append(items, *result)
+	}
+	sort.Slice(items, func(i, j int) bool {
+		if !items[i].Time.Equal(&items[j].Time) {
+			return items[i].Time.Before(&items[j].Time)
+		}
+		return items[i].Namespace+"/"+items[i].Name < items[j].Namespace+"/"+items[j].Name
+	})
+	return items
+}